@@ -20,7 +20,9 @@ import (
 	"xiaoheiplay/internal/adapter/robot"
 	"xiaoheiplay/internal/adapter/seed"
 	"xiaoheiplay/internal/adapter/sse"
+	"xiaoheiplay/internal/adapter/storage"
 	"xiaoheiplay/internal/adapter/system"
+	appabuse "xiaoheiplay/internal/app/abuse"
 	appadmin "xiaoheiplay/internal/app/admin"
 	appadminvps "xiaoheiplay/internal/app/adminvps"
 	appapikey "xiaoheiplay/internal/app/apikey"
@@ -29,11 +31,14 @@ import (
 	appcart "xiaoheiplay/internal/app/cart"
 	appcatalog "xiaoheiplay/internal/app/catalog"
 	appcms "xiaoheiplay/internal/app/cms"
+	appconsole "xiaoheiplay/internal/app/console"
 	appcoupon "xiaoheiplay/internal/app/coupon"
+	appfirewall "xiaoheiplay/internal/app/firewall"
 	appgoodstype "xiaoheiplay/internal/app/goodstype"
 	appintegration "xiaoheiplay/internal/app/integration"
 	applogcleanup "xiaoheiplay/internal/app/logcleanup"
 	appmessage "xiaoheiplay/internal/app/message"
+	appmetrics "xiaoheiplay/internal/app/metrics"
 	appnotification "xiaoheiplay/internal/app/notification"
 	appopenapi "xiaoheiplay/internal/app/openapi"
 	apporder "xiaoheiplay/internal/app/order"
@@ -42,19 +47,25 @@ import (
 	apppayment "xiaoheiplay/internal/app/payment"
 	apppermission "xiaoheiplay/internal/app/permission"
 	apppluginadmin "xiaoheiplay/internal/app/pluginadmin"
+	apppluginhook "xiaoheiplay/internal/app/pluginhook"
+	appports "xiaoheiplay/internal/app/ports"
 	appprobe "xiaoheiplay/internal/app/probe"
 	apppush "xiaoheiplay/internal/app/push"
+	apprdns "xiaoheiplay/internal/app/rdns"
 	apprealname "xiaoheiplay/internal/app/realname"
 	appreport "xiaoheiplay/internal/app/report"
+	apprescue "xiaoheiplay/internal/app/rescue"
 	appscheduledtask "xiaoheiplay/internal/app/scheduledtask"
 	appsecurityticket "xiaoheiplay/internal/app/securityticket"
 	appsettings "xiaoheiplay/internal/app/settings"
+	appsshkey "xiaoheiplay/internal/app/sshkey"
 	appsystemstatus "xiaoheiplay/internal/app/systemstatus"
 	appticket "xiaoheiplay/internal/app/ticket"
 	appupload "xiaoheiplay/internal/app/upload"
 	appuserapikey "xiaoheiplay/internal/app/userapikey"
 	appusertier "xiaoheiplay/internal/app/usertier"
 	appvps "xiaoheiplay/internal/app/vps"
+	appvpsoperation "xiaoheiplay/internal/app/vpsoperation"
 	appwallet "xiaoheiplay/internal/app/wallet"
 	appwalletorder "xiaoheiplay/internal/app/walletorder"
 	"xiaoheiplay/internal/pkg/config"
@@ -123,9 +134,24 @@ func main() {
 	if strings.TrimSpace(cfg.PluginsDir) == "" {
 		log.Fatalf("plugins_dir is empty in config")
 	}
+	messageSvc := appmessage.NewService(repoSQLite, repoSQLite)
 	pluginMgr := plugins.NewManager(cfg.PluginsDir, repoSQLite, pluginCipher, plugins.ParseEd25519PublicKeys(cfg.PluginOfficialKeys))
+	pluginMgr.SetHostServices(plugins.HostServices{
+		KV:       repoSQLite,
+		Logs:     repoSQLite,
+		Orders:   repoSQLite,
+		Users:    repoSQLite,
+		Notifier: messageSvc,
+	})
+	pluginMgr.SetVersionRepository(repoSQLite)
+	pluginMgr.SetRepositories(cfg.PluginRepositories)
+	robotNotifier := robot.NewWebhookNotifier(repoSQLite)
+	pluginMgr.SetRuntimeEventSinks(robotNotifier)
 	pluginSMSSender := plugins.NewSMSSender(pluginMgr)
+	robotNotifier.SetNotifySender(plugins.NewNotifySender(pluginMgr))
 	pluginAdminSvc := apppluginadmin.NewService(plugins.NewAdminManager(pluginMgr), repoSQLite, repoSQLite)
+	pluginAdminSvc.SetPluginLogs(repoSQLite)
+	pluginHookSvc := apppluginhook.NewService(repoSQLite, pluginMgr)
 	_ = pluginMgr.BootstrapFromDisk(context.Background(), repoSQLite)
 	pluginMgr.StartEnabled(context.Background())
 
@@ -135,27 +161,55 @@ func main() {
 	broker := sse.NewBroker(repoSQLite)
 	automationResolver := automation.NewResolver(repoSQLite, pluginMgr, repoSQLite, repoSQLite)
 	emailSender := email.NewSender(repoSQLite)
-	robotNotifier := robot.NewWebhookNotifier(repoSQLite)
 	pushSender := push.NewFCMSender()
 	pushSvc := apppush.NewService(repoSQLite, repoSQLite, repoSQLite, pushSender)
 	pushNotifier := push.NewOrderPushNotifier(repoSQLite, pushSvc)
-	eventBus := event.NewFanoutPublisher(broker, robotNotifier, pushNotifier)
+	eventBus := event.NewFanoutPublisher(broker, robotNotifier, pushNotifier, pluginHookSvc)
 	realnameRegistry := realname.NewRegistry(repoSQLite)
 	realnameRegistry.SetPluginManager(pluginMgr)
 	realnameSvc := apprealname.NewService(repoSQLite, realnameRegistry, repoSQLite)
-	messageSvc := appmessage.NewService(repoSQLite, repoSQLite)
 	orderSvc := apporder.NewService(repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite, eventBus, automationResolver, nil, repoSQLite, repoSQLite, emailSender, repoSQLite, repoSQLite, repoSQLite, repoSQLite, messageSvc, realnameSvc)
 	vpsSvc := appvps.NewService(repoSQLite, automationResolver, repoSQLite)
+	vpsSvc.SetActionUsage(repoSQLite)
+	vpsSvc.SetActivityLog(repoSQLite)
+	vpsSvc.SetPluginHooks(pluginHookSvc)
+	vpsOperationFeed := sse.NewVPSOperationBroker()
+	vpsOperationSvc := appvpsoperation.NewService(repoSQLite, repoSQLite, repoSQLite, vpsSvc, vpsOperationFeed)
+	orderSvc.SetVPSOperationTracker(vpsOperationSvc)
+	goodsTypeSvc.SetBackendPool(repoSQLite, automationResolver)
+	orderSvc.SetBackendPlacer(goodsTypeSvc)
+	orderSvc.SetGuestInit(repoSQLite, pluginAdminSvc)
+	orderSvc.SetExtraIPs(repoSQLite)
 	adminSvc := appadmin.NewService(repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite)
 	adminVPSSvc := appadminvps.NewService(repoSQLite, automationResolver, repoSQLite, repoSQLite, repoSQLite, messageSvc)
+	adminVPSSvc.SetActivityLog(repoSQLite)
 	apiKeySvc := appapikey.NewService(repoSQLite)
 	userAPIKeySvc := appuserapikey.NewService(repoSQLite)
+	sshKeySvc := appsshkey.NewService(repoSQLite)
+	reverseDNSSvc := apprdns.NewService(repoSQLite, automationResolver, repoSQLite, repoSQLite)
+	rescueSvc := apprescue.NewService(repoSQLite, automationResolver, repoSQLite, repoSQLite, repoSQLite)
+	metricsSvc := appmetrics.NewService(repoSQLite, automationResolver, repoSQLite, repoSQLite)
+	abuseSvc := appabuse.NewService(repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite)
+	abuseSvc.SetEnforcer(adminVPSSvc)
+	abuseSvc.SetMessageCenter(messageSvc)
+	consoleSvc := appconsole.NewService(repoSQLite, automationResolver, repoSQLite, repoSQLite, repoSQLite, cfg.JWTSecret)
+	if closed, err := consoleSvc.CloseOrphans(context.Background()); err != nil {
+		log.Printf("console session cleanup failed: %v", err)
+	} else if closed > 0 {
+		log.Printf("console session cleanup: closed=%d", closed)
+	}
+	firewallSvc := appfirewall.NewService(repoSQLite, repoSQLite, automationResolver, repoSQLite)
+	firewallSvc.SetQuotaPolicy(vpsSvc)
+	orderSvc.SetFirewallTemplates(firewallSvc)
+	orderSvc.SetProvisionAttempts(repoSQLite)
+	vpsOperationSvc.SetFirewallTemplates(firewallSvc)
 	authSvc := appauth.NewService(repoSQLite, repoSQLite, repoSQLite)
 	notifySvc := appnotification.NewService(repoSQLite, repoSQLite, repoSQLite, emailSender, messageSvc)
 	integrationSvc := appintegration.NewService(repoSQLite, repoSQLite, repoSQLite, repoSQLite, automationResolver, repoSQLite)
 	reportSvc := appreport.NewService(repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite)
 	cmsSvc := appcms.NewService(repoSQLite, repoSQLite, repoSQLite, messageSvc)
 	ticketSvc := appticket.NewService(repoSQLite, repoSQLite, repoSQLite, messageSvc)
+	ticketSvc.SetPluginHooks(pluginHookSvc)
 	permissionSvc := apppermission.NewService(repoSQLite, repoSQLite, repoSQLite)
 	passwordResetSvc := apppasswordreset.NewService(repoSQLite, repoSQLite, emailSender, repoSQLite)
 	walletSvc := appwallet.NewService(repoSQLite, repoSQLite)
@@ -164,6 +218,7 @@ func main() {
 	userTierSvc := appusertier.NewService(repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite)
 	_, _ = userTierSvc.EnsureDefaultGroup(context.Background())
 	authSvc.SetUserTierAssigner(userTierSvc)
+	authSvc.SetPluginHooks(pluginHookSvc)
 	adminSvc.SetUserTierAssigner(userTierSvc)
 	go func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
@@ -183,6 +238,10 @@ func main() {
 	orderSvc.SetCouponService(couponSvc)
 	walletOrderSvc.SetUserTierAutoApprover(userTierSvc)
 	uploadSvc := appupload.NewService(repoSQLite)
+	uploadSvc.SetSettings(repoSQLite)
+	uploadSvc.SetStorage(storage.NewResolver(storage.NewLocal("uploads"), func(pluginID, instanceID string) appports.ObjectStorage {
+		return plugins.NewStorageClient(pluginMgr, pluginID, instanceID)
+	}))
 	autoLogSvc := appautomationlog.NewService(repoSQLite)
 	orderEventSvc := apporderevent.NewService(repoSQLite)
 	securityTicketSvc := appsecurityticket.NewService(repoSQLite)
@@ -195,13 +254,21 @@ func main() {
 	openAPISvc := appopenapi.NewService(orderSvc, paymentSvc, repoSQLite)
 	statusSvc := appsystemstatus.NewService(system.NewProvider())
 	taskSvc := appscheduledtask.NewService(repoSQLite, vpsSvc, orderSvc, notifySvc, repoSQLite, realnameSvc)
-	logCleanupSvc := applogcleanup.NewService(repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite)
+	logCleanupSvc := applogcleanup.NewService(repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite)
 	taskSvc.SetUserTierService(userTierSvc)
 	taskSvc.SetIntegrationService(integrationSvc)
 	taskSvc.SetLogRetentionCleaner(logCleanupSvc)
+	taskSvc.SetBackendHealthChecker(goodsTypeSvc)
+	taskSvc.SetRescueExpirer(rescueSvc)
+	taskSvc.SetMetricsCollector(metricsSvc)
+	taskSvc.SetAbuseResponseChecker(abuseSvc)
+	taskSvc.SetFirewallReconciler(firewallSvc)
+	taskSvc.SetPluginJobHost(pluginMgr)
 	probeHub := appprobe.NewHub()
 	probeSvc := appprobe.NewService(repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite)
 	go taskSvc.Start(context.Background())
+	go vpsOperationSvc.Start(context.Background())
+	go pluginHookSvc.Start(context.Background())
 	go probeSvc.StartOfflineWatcher(context.Background())
 
 	pluginDir := pluginAdminSvc.ResolveUploadDir(context.Background(), "")
@@ -233,16 +300,27 @@ func main() {
 		SettingsSvc:       settingsSvc,
 		UploadSvc:         uploadSvc,
 		Broker:            broker,
+		VPSOperationSvc:   vpsOperationSvc,
+		VPSOperationFeed:  vpsOperationFeed,
 		JWTSecret:         cfg.JWTSecret,
 		PasswordReset:     passwordResetSvc,
 		SecurityTicketSvc: securityTicketSvc,
 		PermissionSvc:     permissionSvc,
 		PluginAdmin:       pluginAdminSvc,
+		PluginHookSvc:     pluginHookSvc,
 		UserTierSvc:       userTierSvc,
 		CouponSvc:         couponSvc,
 		SMSSender:         pluginSMSSender,
+		CaptchaProvider:   plugins.NewCaptchaProvider(pluginMgr),
 		TaskSvc:           taskSvc,
 		UserAPIKeySvc:     userAPIKeySvc,
+		SSHKeySvc:         sshKeySvc,
+		ReverseDNSSvc:     reverseDNSSvc,
+		RescueSvc:         rescueSvc,
+		MetricsSvc:        metricsSvc,
+		AbuseSvc:          abuseSvc,
+		ConsoleSvc:        consoleSvc,
+		FirewallSvc:       firewallSvc,
 		OpenAPISvc:        openAPISvc,
 		ProbeSvc:          probeSvc,
 		ProbeHub:          probeHub,
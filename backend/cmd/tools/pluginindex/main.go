@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	plugins "xiaoheiplay/internal/adapter/plugins/core"
+)
+
+func main() {
+	repoDir := flag.String("repo", "", "repository directory (index.json is created or updated in it)")
+	pkgPath := flag.String("package", "", "plugin package to publish (.zip / .tar.gz)")
+	priv := flag.String("ed25519-priv", "", "base64 ed25519 private key (64 bytes) of an official key")
+	flag.Parse()
+
+	if strings.TrimSpace(*repoDir) == "" || strings.TrimSpace(*pkgPath) == "" || strings.TrimSpace(*priv) == "" {
+		fmt.Fprintln(os.Stderr, "missing -repo, -package or -ed25519-priv")
+		os.Exit(2)
+	}
+	keyBytes, err := base64.StdEncoding.DecodeString(strings.TrimSpace(*priv))
+	if err != nil || len(keyBytes) != ed25519.PrivateKeySize {
+		fmt.Fprintln(os.Stderr, "invalid -ed25519-priv")
+		os.Exit(2)
+	}
+	privKey := ed25519.PrivateKey(keyBytes)
+
+	raw, err := os.ReadFile(*pkgPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "read package:", err)
+		os.Exit(1)
+	}
+	filename := filepath.Base(*pkgPath)
+	pkg, err := plugins.InspectPackage(filename, bytes.NewReader(raw), nil)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "invalid package:", err)
+		os.Exit(1)
+	}
+
+	rel := pkg.Category + "/" + pkg.PluginID + "/" + filename
+	dst := filepath.Join(*repoDir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		fmt.Fprintln(os.Stderr, "mkdir:", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(dst, raw, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "copy package:", err)
+		os.Exit(1)
+	}
+
+	sum := sha256.Sum256(raw)
+	entry := plugins.RepositoryEntry{
+		Category:    pkg.Category,
+		PluginID:    pkg.PluginID,
+		Name:        pkg.Manifest.Name,
+		Version:     pkg.Manifest.Version,
+		Description: pkg.Manifest.Description,
+		Package:     rel,
+		SHA256:      hex.EncodeToString(sum[:]),
+		SignerKey:   base64.StdEncoding.EncodeToString(privKey.Public().(ed25519.PublicKey)),
+	}
+	entry.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(privKey, plugins.RepositorySigningPayload(entry)))
+
+	indexPath := filepath.Join(*repoDir, plugins.RepositoryIndexFile)
+	var index plugins.RepositoryIndex
+	if b, err := os.ReadFile(indexPath); err == nil {
+		if err := json.Unmarshal(b, &index); err != nil {
+			fmt.Fprintln(os.Stderr, "invalid index.json:", err)
+			os.Exit(1)
+		}
+	}
+	kept := index.Plugins[:0]
+	for _, e := range index.Plugins {
+		if e.Category == entry.Category && e.PluginID == entry.PluginID && e.Version == entry.Version {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	index.Plugins = append(kept, entry)
+	sort.SliceStable(index.Plugins, func(i, j int) bool {
+		a, b := index.Plugins[i], index.Plugins[j]
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		return a.PluginID < b.PluginID
+	})
+	out, _ := json.MarshalIndent(index, "", "  ")
+	if err := os.WriteFile(indexPath, out, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "write index.json:", err)
+		os.Exit(1)
+	}
+	fmt.Println("OK: published", entry.Category+"/"+entry.PluginID+"@"+entry.Version)
+}
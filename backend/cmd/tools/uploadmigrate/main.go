@@ -0,0 +1,70 @@
+// Command uploadmigrate moves uploaded files between storage backends, e.g.
+// from the local disk to a storage plugin before running several backend
+// instances. Run it from the server working directory so it picks up the
+// same config, uploads directory and plugins:
+//
+//	go run ./cmd/tools/uploadmigrate -from local -to s3_compat/default
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	plugins "xiaoheiplay/internal/adapter/plugins/core"
+	repo "xiaoheiplay/internal/adapter/repo/core"
+	"xiaoheiplay/internal/adapter/storage"
+	appports "xiaoheiplay/internal/app/ports"
+	appupload "xiaoheiplay/internal/app/upload"
+	"xiaoheiplay/internal/pkg/config"
+	"xiaoheiplay/internal/pkg/cryptox"
+	"xiaoheiplay/internal/pkg/db"
+)
+
+func main() {
+	from := flag.String("from", "local", "source backend: local or <plugin_id>/<instance_id> of a storage plugin")
+	to := flag.String("to", "", "target backend: local or <plugin_id>/<instance_id> of a storage plugin")
+	uploadsDir := flag.String("uploads", "uploads", "local uploads directory")
+	flag.Parse()
+
+	if strings.TrimSpace(*to) == "" {
+		fmt.Fprintln(os.Stderr, "missing -to")
+		os.Exit(2)
+	}
+	cfg := config.Load()
+	if strings.TrimSpace(cfg.DBType) == "" {
+		fmt.Fprintln(os.Stderr, "db config missing")
+		os.Exit(1)
+	}
+	conn, err := db.Open(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "db open:", err)
+		os.Exit(1)
+	}
+	if err := repo.Migrate(conn.Gorm); err != nil {
+		fmt.Fprintln(os.Stderr, "migrate:", err)
+		os.Exit(1)
+	}
+	gormRepo := repo.NewGormRepo(conn.Gorm)
+	cipher, err := cryptox.NewAESGCM(cfg.PluginMasterKey)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "plugin cipher:", err)
+		os.Exit(1)
+	}
+	pluginMgr := plugins.NewManager(cfg.PluginsDir, gormRepo, cipher, plugins.ParseEd25519PublicKeys(cfg.PluginOfficialKeys))
+	pluginMgr.SetHostServices(plugins.HostServices{KV: gormRepo, Logs: gormRepo})
+
+	svc := appupload.NewService(gormRepo)
+	svc.SetStorage(storage.NewResolver(storage.NewLocal(*uploadsDir), func(pluginID, instanceID string) appports.ObjectStorage {
+		return plugins.NewStorageClient(pluginMgr, pluginID, instanceID)
+	}))
+	res, err := svc.Migrate(context.Background(), *from, *to)
+	pluginMgr.StopAll()
+	fmt.Printf("moved %d uploads from %s to %s, %d missing in source\n", res.Moved, appupload.NormalizeBackend(*from), appupload.NormalizeBackend(*to), res.Missing)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "migrate uploads:", err)
+		os.Exit(1)
+	}
+}
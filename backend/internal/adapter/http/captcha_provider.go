@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	appshared "xiaoheiplay/internal/app/shared"
 	"xiaoheiplay/internal/domain"
 )
 
@@ -21,20 +22,87 @@ type geeTestValidatePayload struct {
 	GenTime       string
 }
 
+// captchaAnswer holds every captcha field a protected request may carry;
+// which ones are read depends on the provider configured for the scene.
+type captchaAnswer struct {
+	CaptchaID     string            `json:"captcha_id"`
+	CaptchaCode   string            `json:"captcha_code"`
+	LotNumber     string            `json:"lot_number"`
+	CaptchaOutput string            `json:"captcha_output"`
+	PassToken     string            `json:"pass_token"`
+	GenTime       string            `json:"gen_time"`
+	CaptchaToken  string            `json:"captcha_token"`
+	CaptchaParams map[string]string `json:"captcha_params"`
+}
+
+// captchaSceneSettings is the provider selected for one captcha scene.
+type captchaSceneSettings struct {
+	Provider   string
+	PluginID   string
+	InstanceID string
+}
+
+var captchaScenes = []string{
+	appshared.CaptchaSceneRegister,
+	appshared.CaptchaSceneLogin,
+	appshared.CaptchaScenePasswordReset,
+	appshared.CaptchaSceneOrderSubmit,
+}
+
 func normalizeCaptchaProvider(provider string) string {
 	switch strings.ToLower(strings.TrimSpace(provider)) {
 	case "geetest":
 		return "geetest"
+	case "plugin":
+		return "plugin"
 	default:
 		return "image"
 	}
 }
 
-func (h *Handler) verifyHumanCaptcha(c *gin.Context, settings authSettings, captchaID, captchaCode string, gt geeTestValidatePayload) error {
-	if normalizeCaptchaProvider(settings.CaptchaProvider) == "geetest" {
-		return h.verifyGeeTestCaptcha(settings, gt)
+// captchaFor returns the provider of scene; unknown scenes use the global
+// provider.
+func (s authSettings) captchaFor(scene string) captchaSceneSettings {
+	if cfg, ok := s.CaptchaScenes[strings.TrimSpace(scene)]; ok {
+		return cfg
+	}
+	return captchaSceneSettings{Provider: s.CaptchaProvider, PluginID: s.CaptchaPluginID, InstanceID: s.CaptchaInstanceID}
+}
+
+// captchaSceneProviders tells the front end which widget each scene renders.
+func captchaSceneProviders(settings authSettings) map[string]string {
+	out := make(map[string]string, len(captchaScenes))
+	for _, scene := range captchaScenes {
+		out[scene] = settings.captchaFor(scene).Provider
+	}
+	return out
+}
+
+func (h *Handler) verifyHumanCaptcha(c *gin.Context, settings authSettings, scene string, answer captchaAnswer) error {
+	cfg := settings.captchaFor(scene)
+	switch cfg.Provider {
+	case "geetest":
+		return h.verifyGeeTestCaptcha(settings, geeTestValidatePayload{
+			LotNumber:     answer.LotNumber,
+			CaptchaOutput: answer.CaptchaOutput,
+			PassToken:     answer.PassToken,
+			GenTime:       answer.GenTime,
+		})
+	case "plugin":
+		if h.captchaProvider == nil || strings.TrimSpace(cfg.PluginID) == "" {
+			return domain.ErrCaptchaFailed
+		}
+		if strings.TrimSpace(answer.CaptchaToken) == "" && len(answer.CaptchaParams) == 0 {
+			return domain.ErrCaptchaFailed
+		}
+		return h.captchaProvider.Verify(c, cfg.PluginID, cfg.InstanceID, appshared.CaptchaAnswer{
+			Scene:    scene,
+			Token:    answer.CaptchaToken,
+			Params:   answer.CaptchaParams,
+			RemoteIP: c.ClientIP(),
+		})
 	}
-	return h.authSvc.VerifyCaptcha(c, captchaID, captchaCode)
+	return h.authSvc.VerifyCaptcha(c, answer.CaptchaID, answer.CaptchaCode)
 }
 
 func (h *Handler) verifyGeeTestCaptcha(settings authSettings, payload geeTestValidatePayload) error {
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	appconsole "xiaoheiplay/internal/app/console"
+)
+
+type consoleRelayResult struct {
+	Reason        string
+	ClientBytes   int64
+	UpstreamBytes int64
+}
+
+// relayConsole copies frames between the browser and the upstream console
+// until either side closes, ctx is cancelled or a limit is hit. Idle time is
+// measured from the last browser input; the framebuffer update requests noVNC
+// sends on its own do not keep a session alive.
+func relayConsole(ctx context.Context, client, upstream *websocket.Conn, limits appconsole.Limits) consoleRelayResult {
+	var clientBytes, upstreamBytes, lastInput atomic.Int64
+	lastInput.Store(time.Now().UnixNano())
+
+	done := make(chan string, 2)
+	go func() {
+		done <- pumpConsole(upstream, client, &clientBytes, func(msg []byte) {
+			if consoleInput(msg) {
+				lastInput.Store(time.Now().UnixNano())
+			}
+		}, appconsole.ReasonClosed)
+	}()
+	go func() {
+		done <- pumpConsole(client, upstream, &upstreamBytes, nil, appconsole.ReasonUpstreamClosed)
+	}()
+
+	tick := time.Second
+	if limits.IdleTimeout > 0 && limits.IdleTimeout/2 < tick {
+		tick = limits.IdleTimeout / 2
+	}
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	var deadline <-chan time.Time
+	if limits.MaxDuration > 0 {
+		timer := time.NewTimer(limits.MaxDuration)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	pending := 2
+	reason := ""
+	for reason == "" {
+		select {
+		case reason = <-done:
+			pending--
+		case <-ctx.Done():
+			reason = appconsole.ReasonRevoked
+		case <-deadline:
+			reason = appconsole.ReasonMaxDuration
+		case <-ticker.C:
+			if limits.IdleTimeout > 0 && time.Since(time.Unix(0, lastInput.Load())) >= limits.IdleTimeout {
+				reason = appconsole.ReasonIdle
+			}
+		}
+	}
+
+	msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, reason)
+	_ = client.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+	_ = upstream.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+	_ = client.Close()
+	_ = upstream.Close()
+	for ; pending > 0; pending-- {
+		<-done
+	}
+	return consoleRelayResult{Reason: reason, ClientBytes: clientBytes.Load(), UpstreamBytes: upstreamBytes.Load()}
+}
+
+// pumpConsole forwards messages from src to dst and returns reason once src
+// stops delivering.
+func pumpConsole(dst, src *websocket.Conn, counter *atomic.Int64, observe func([]byte), reason string) string {
+	for {
+		kind, msg, err := src.ReadMessage()
+		if err != nil {
+			return reason
+		}
+		counter.Add(int64(len(msg)))
+		if observe != nil {
+			observe(msg)
+		}
+		_ = dst.SetWriteDeadline(time.Now().Add(15 * time.Second))
+		if err := dst.WriteMessage(kind, msg); err != nil {
+			return reason
+		}
+	}
+}
+
+// consoleInput reports whether a browser frame is user activity. A frame made
+// up only of RFB FramebufferUpdateRequest messages (type 3, 10 bytes each) is
+// not.
+func consoleInput(msg []byte) bool {
+	if len(msg) == 0 || len(msg)%10 != 0 {
+		return true
+	}
+	for i := 0; i < len(msg); i += 10 {
+		if msg[i] != 3 {
+			return true
+		}
+	}
+	return false
+}
@@ -69,6 +69,10 @@ type PlanGroupDTO struct {
 	AddBWMin          int     `json:"add_bw_min"`
 	AddBWMax          int     `json:"add_bw_max"`
 	AddBWStep         int     `json:"add_bw_step"`
+	UnitIPv4          float64 `json:"unit_ipv4"`
+	UnitIPv6          float64 `json:"unit_ipv6"`
+	AddIPv4Max        int     `json:"add_ipv4_max"`
+	AddIPv6Max        int     `json:"add_ipv6_max"`
 	Active            bool    `json:"active"`
 	Visible           bool    `json:"visible"`
 	CapacityRemaining int     `json:"capacity_remaining"`
@@ -317,11 +321,22 @@ type VPSInstanceDTO struct {
 	PanelURLCache        string              `json:"panel_url_cache"`
 	AccessInfo           map[string]any      `json:"access_info"`
 	Capabilities         *VPSCapabilitiesDTO `json:"capabilities,omitempty"`
+	ExtraIPs             []VPSExtraIPDTO     `json:"extra_ips,omitempty"`
+	Quotas               []VPSActionQuotaDTO `json:"quotas,omitempty"`
 	LastEmergencyRenewAt *time.Time          `json:"last_emergency_renew_at"`
 	CreatedAt            time.Time           `json:"created_at"`
 	UpdatedAt            time.Time           `json:"updated_at"`
 }
 
+type VPSActionQuotaDTO struct {
+	Action        string     `json:"action"`
+	Window        string     `json:"window"`
+	Limit         int        `json:"limit"`
+	Used          *int       `json:"used,omitempty"`
+	Remaining     *int       `json:"remaining,omitempty"`
+	CooldownUntil *time.Time `json:"cooldown_until,omitempty"`
+}
+
 type VPSCapabilitiesDTO struct {
 	Automation *VPSAutomationCapabilityDTO `json:"automation,omitempty"`
 }
@@ -331,6 +346,217 @@ type VPSAutomationCapabilityDTO struct {
 	NotSupportedReasons map[string]string `json:"not_supported_reasons,omitempty"`
 }
 
+type VPSOperationDTO struct {
+	ID       int64  `json:"id"`
+	VPSID    int64  `json:"vps_id"`
+	Action   string `json:"action"`
+	Status   string `json:"status"`
+	Progress int    `json:"progress"`
+	// ProgressEstimated is true when the plugin reports no task progress and
+	// Progress is the host's own estimate.
+	ProgressEstimated bool            `json:"progress_estimated"`
+	Message           string          `json:"message"`
+	Params            json.RawMessage `json:"params"`
+	Error             string          `json:"error,omitempty"`
+	StartedAt         *time.Time      `json:"started_at"`
+	FinishedAt        *time.Time      `json:"finished_at"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+}
+
+type VPSActivityDTO struct {
+	ID        int64     `json:"id"`
+	VPSID     int64     `json:"vps_id"`
+	ActorType string    `json:"actor_type"`
+	ActorID   int64     `json:"actor_id"`
+	Action    string    `json:"action"`
+	Detail    string    `json:"detail"`
+	IP        string    `json:"ip"`
+	Result    string    `json:"result"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PluginLogDTO struct {
+	ID        int64             `json:"id"`
+	Level     string            `json:"level"`
+	Message   string            `json:"message"`
+	Fields    map[string]string `json:"fields,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+type PluginHookDeliveryDTO struct {
+	ID            int64      `json:"id"`
+	EventID       string     `json:"event_id"`
+	Event         string     `json:"event"`
+	Payload       any        `json:"payload,omitempty"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	OccurredAt    time.Time  `json:"occurred_at"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type PluginVersionDTO struct {
+	Version         string     `json:"version"`
+	SignatureStatus string     `json:"signature_status"`
+	Status          string     `json:"status"`
+	LastError       string     `json:"last_error,omitempty"`
+	ActivatedAt     *time.Time `json:"activated_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+type ProvisionAttemptDTO struct {
+	ID          int64     `json:"id"`
+	OrderID     int64     `json:"order_id"`
+	OrderItemID int64     `json:"order_item_id"`
+	Attempt     int       `json:"attempt"`
+	Strategy    string    `json:"strategy"`
+	LineID      int64     `json:"line_id"`
+	PackageID   int64     `json:"package_id"`
+	Success     bool      `json:"success"`
+	Error       string    `json:"error,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type AutomationBackendDTO struct {
+	ID                  int64      `json:"id"`
+	GoodsTypeID         int64      `json:"goods_type_id"`
+	PluginID            string     `json:"plugin_id"`
+	InstanceID          string     `json:"instance_id"`
+	Weight              int        `json:"weight"`
+	Capacity            int        `json:"capacity"`
+	Enabled             bool       `json:"enabled"`
+	Health              string     `json:"health"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastError           string     `json:"last_error,omitempty"`
+	LastCheckedAt       *time.Time `json:"last_checked_at"`
+	ActiveHosts         int        `json:"active_hosts"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+type SSHKeyDTO struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	PublicKey   string    `json:"public_key"`
+	Fingerprint string    `json:"fingerprint"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type FirewallTemplateRuleDTO struct {
+	Direction string `json:"direction"`
+	Protocol  string `json:"protocol"`
+	Method    string `json:"method"`
+	Port      string `json:"port"`
+	IP        string `json:"ip"`
+	Priority  int    `json:"priority"`
+}
+
+type FirewallTemplateDTO struct {
+	ID          int64                     `json:"id"`
+	GoodsTypeID int64                     `json:"goods_type_id,omitempty"`
+	Name        string                    `json:"name"`
+	Description string                    `json:"description"`
+	Admin       bool                      `json:"admin"`
+	Rules       []FirewallTemplateRuleDTO `json:"rules"`
+	CreatedAt   time.Time                 `json:"created_at"`
+	UpdatedAt   time.Time                 `json:"updated_at"`
+}
+
+type VPSFirewallSyncDTO struct {
+	Status       string                    `json:"status"`
+	LastError    string                    `json:"last_error,omitempty"`
+	Attempts     int                       `json:"attempts"`
+	ManagedRules []FirewallTemplateRuleDTO `json:"managed_rules"`
+	SyncedAt     *time.Time                `json:"synced_at,omitempty"`
+}
+
+type ReverseDNSChangeDTO struct {
+	ID        int64     `json:"id"`
+	VPSID     int64     `json:"vps_id"`
+	UserID    int64     `json:"user_id"`
+	AdminID   int64     `json:"admin_id"`
+	IP        string    `json:"ip"`
+	OldPTR    string    `json:"old_ptr"`
+	NewPTR    string    `json:"new_ptr"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type VPSExtraIPDTO struct {
+	ID          int64     `json:"id"`
+	VPSID       int64     `json:"vps_id"`
+	OrderItemID int64     `json:"order_item_id"`
+	Family      string    `json:"family"`
+	Address     string    `json:"address"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type VPSRescueDTO struct {
+	Active    bool             `json:"active"`
+	ExpiresAt *time.Time       `json:"expires_at,omitempty"`
+	ISO       *VPSISOMountDTO  `json:"iso,omitempty"`
+	ISOs      []VPSISOImageDTO `json:"isos"`
+}
+
+type VPSISOMountDTO struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	MountedAt time.Time `json:"mounted_at"`
+}
+
+type VPSISOImageDTO struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type VPSRescueCredentialsDTO struct {
+	Username  string    `json:"username"`
+	Password  string    `json:"password"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type VPSConsoleTicketDTO struct {
+	Ticket    string    `json:"ticket"`
+	ExpiresAt time.Time `json:"expires_at"`
+	WSPath    string    `json:"ws_path"`
+	PageURL   string    `json:"page_url"`
+}
+
+type ConsoleSessionDTO struct {
+	ID            int64      `json:"id"`
+	VPSID         int64      `json:"vps_id"`
+	UserID        int64      `json:"user_id"`
+	Status        string     `json:"status"`
+	ClientIP      string     `json:"client_ip"`
+	UpstreamHost  string     `json:"upstream_host"`
+	StartedAt     *time.Time `json:"started_at,omitempty"`
+	EndedAt       *time.Time `json:"ended_at,omitempty"`
+	EndReason     string     `json:"end_reason"`
+	ClientBytes   int64      `json:"client_bytes"`
+	UpstreamBytes int64      `json:"upstream_bytes"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+type VPSMetricPointDTO struct {
+	At             time.Time `json:"at"`
+	CPUPercent     float64   `json:"cpu"`
+	MemoryPercent  float64   `json:"memory"`
+	StoragePercent float64   `json:"storage"`
+	BytesIn        int64     `json:"bytes_in"`
+	BytesOut       int64     `json:"bytes_out"`
+	Samples        int       `json:"samples"`
+}
+
+type VPSMetricSeriesDTO struct {
+	Resolution string              `json:"resolution"`
+	From       time.Time           `json:"from"`
+	To         time.Time           `json:"to"`
+	Points     []VPSMetricPointDTO `json:"points"`
+}
+
 type OrderEventDTO struct {
 	ID        int64           `json:"id"`
 	OrderID   int64           `json:"order_id"`
@@ -692,6 +918,10 @@ func toPlanGroupDTO(plan domain.PlanGroup) PlanGroupDTO {
 		AddBWMin:          plan.AddBWMin,
 		AddBWMax:          plan.AddBWMax,
 		AddBWStep:         plan.AddBWStep,
+		UnitIPv4:          centsToFloat(plan.UnitIPv4),
+		UnitIPv6:          centsToFloat(plan.UnitIPv6),
+		AddIPv4Max:        plan.AddIPv4Max,
+		AddIPv6Max:        plan.AddIPv6Max,
 		Active:            plan.Active,
 		Visible:           plan.Visible,
 		CapacityRemaining: plan.CapacityRemaining,
@@ -1031,6 +1261,165 @@ func toVPSInstanceDTO(inst domain.VPSInstance) VPSInstanceDTO {
 	}
 }
 
+func toAutomationBackendDTO(backend domain.AutomationBackend) AutomationBackendDTO {
+	return AutomationBackendDTO{
+		ID:                  backend.ID,
+		GoodsTypeID:         backend.GoodsTypeID,
+		PluginID:            backend.PluginID,
+		InstanceID:          backend.InstanceID,
+		Weight:              backend.Weight,
+		Capacity:            backend.Capacity,
+		Enabled:             backend.Enabled,
+		Health:              string(backend.Health),
+		ConsecutiveFailures: backend.ConsecutiveFailures,
+		LastError:           backend.LastError,
+		LastCheckedAt:       backend.LastCheckedAt,
+		ActiveHosts:         backend.ActiveHosts,
+		CreatedAt:           backend.CreatedAt,
+		UpdatedAt:           backend.UpdatedAt,
+	}
+}
+
+func toAutomationBackendDTOs(items []domain.AutomationBackend) []AutomationBackendDTO {
+	out := make([]AutomationBackendDTO, 0, len(items))
+	for _, item := range items {
+		out = append(out, toAutomationBackendDTO(item))
+	}
+	return out
+}
+
+func toSSHKeyDTO(key domain.SSHKey) SSHKeyDTO {
+	return SSHKeyDTO{
+		ID:          key.ID,
+		Name:        key.Name,
+		PublicKey:   key.PublicKey,
+		Fingerprint: key.Fingerprint,
+		CreatedAt:   key.CreatedAt,
+	}
+}
+
+func toSSHKeyDTOs(items []domain.SSHKey) []SSHKeyDTO {
+	out := make([]SSHKeyDTO, 0, len(items))
+	for _, item := range items {
+		out = append(out, toSSHKeyDTO(item))
+	}
+	return out
+}
+
+func toFirewallTemplateRuleDTOs(items []domain.FirewallTemplateRule) []FirewallTemplateRuleDTO {
+	out := make([]FirewallTemplateRuleDTO, 0, len(items))
+	for _, item := range items {
+		out = append(out, FirewallTemplateRuleDTO{
+			Direction: item.Direction,
+			Protocol:  item.Protocol,
+			Method:    item.Method,
+			Port:      item.Port,
+			IP:        item.IP,
+			Priority:  item.Priority,
+		})
+	}
+	return out
+}
+
+func toFirewallTemplateDTO(tpl domain.FirewallTemplate) FirewallTemplateDTO {
+	return FirewallTemplateDTO{
+		ID:          tpl.ID,
+		GoodsTypeID: tpl.GoodsTypeID,
+		Name:        tpl.Name,
+		Description: tpl.Description,
+		Admin:       tpl.UserID == 0,
+		Rules:       toFirewallTemplateRuleDTOs(tpl.Rules),
+		CreatedAt:   tpl.CreatedAt,
+		UpdatedAt:   tpl.UpdatedAt,
+	}
+}
+
+func toFirewallTemplateDTOs(items []domain.FirewallTemplate) []FirewallTemplateDTO {
+	out := make([]FirewallTemplateDTO, 0, len(items))
+	for _, item := range items {
+		out = append(out, toFirewallTemplateDTO(item))
+	}
+	return out
+}
+
+func toVPSFirewallSyncDTO(sync domain.VPSFirewallSync) VPSFirewallSyncDTO {
+	return VPSFirewallSyncDTO{
+		Status:       sync.Status,
+		LastError:    sync.LastError,
+		Attempts:     sync.Attempts,
+		ManagedRules: toFirewallTemplateRuleDTOs(sync.ManagedRules),
+		SyncedAt:     sync.SyncedAt,
+	}
+}
+
+func toVPSActionQuotaDTOs(items []appshared.VPSQuotaStatus) []VPSActionQuotaDTO {
+	if len(items) == 0 {
+		return nil
+	}
+	out := make([]VPSActionQuotaDTO, 0, len(items))
+	for _, item := range items {
+		out = append(out, VPSActionQuotaDTO{
+			Action:        item.Action,
+			Window:        item.Window,
+			Limit:         item.Limit,
+			Used:          item.Used,
+			Remaining:     item.Remaining,
+			CooldownUntil: item.CooldownUntil,
+		})
+	}
+	return out
+}
+
+func toReverseDNSChangeDTOs(items []domain.ReverseDNSChange) []ReverseDNSChangeDTO {
+	out := make([]ReverseDNSChangeDTO, 0, len(items))
+	for _, item := range items {
+		out = append(out, ReverseDNSChangeDTO{
+			ID:        item.ID,
+			VPSID:     item.VPSID,
+			UserID:    item.UserID,
+			AdminID:   item.AdminID,
+			IP:        item.IP,
+			OldPTR:    item.OldPTR,
+			NewPTR:    item.NewPTR,
+			CreatedAt: item.CreatedAt,
+		})
+	}
+	return out
+}
+
+func toVPSExtraIPDTOs(items []domain.VPSExtraIP) []VPSExtraIPDTO {
+	out := make([]VPSExtraIPDTO, 0, len(items))
+	for _, item := range items {
+		out = append(out, VPSExtraIPDTO{
+			ID:          item.ID,
+			VPSID:       item.VPSID,
+			OrderItemID: item.OrderItemID,
+			Family:      item.Family,
+			Address:     item.Address,
+			CreatedAt:   item.CreatedAt,
+		})
+	}
+	return out
+}
+
+func toVPSOperationDTO(op domain.VPSOperation) VPSOperationDTO {
+	return VPSOperationDTO{
+		ID:                op.ID,
+		VPSID:             op.VPSID,
+		Action:            string(op.Action),
+		Status:            string(op.Status),
+		Progress:          op.Progress,
+		ProgressEstimated: op.ProgressEstimated,
+		Message:           op.Message,
+		Params:            parseRawJSON(op.ParamsJSON),
+		Error:             op.ErrorMessage,
+		StartedAt:         op.StartedAt,
+		FinishedAt:        op.FinishedAt,
+		CreatedAt:         op.CreatedAt,
+		UpdatedAt:         op.UpdatedAt,
+	}
+}
+
 func toOrderEventDTO(event domain.OrderEvent) OrderEventDTO {
 	return OrderEventDTO{
 		ID:        event.ID,
@@ -1193,6 +1582,114 @@ func toVPSInstanceDTOs(items []domain.VPSInstance) []VPSInstanceDTO {
 	return out
 }
 
+func toVPSOperationDTOs(items []domain.VPSOperation) []VPSOperationDTO {
+	out := make([]VPSOperationDTO, 0, len(items))
+	for _, item := range items {
+		out = append(out, toVPSOperationDTO(item))
+	}
+	return out
+}
+
+func toPluginLogDTOs(items []domain.PluginLog) []PluginLogDTO {
+	out := make([]PluginLogDTO, 0, len(items))
+	for _, item := range items {
+		dto := PluginLogDTO{
+			ID:        item.ID,
+			Level:     item.Level,
+			Message:   item.Message,
+			CreatedAt: item.CreatedAt,
+		}
+		_ = json.Unmarshal([]byte(item.FieldsJSON), &dto.Fields)
+		out = append(out, dto)
+	}
+	return out
+}
+
+func toPluginHookDeliveryDTO(item domain.PluginHookDelivery) PluginHookDeliveryDTO {
+	dto := PluginHookDeliveryDTO{
+		ID:          item.ID,
+		EventID:     item.EventID,
+		Event:       item.Event,
+		Status:      string(item.Status),
+		Attempts:    item.Attempts,
+		LastError:   item.LastError,
+		OccurredAt:  item.OccurredAt,
+		DeliveredAt: item.DeliveredAt,
+		CreatedAt:   item.CreatedAt,
+	}
+	if item.Status == domain.PluginHookDeliveryPending {
+		next := item.NextAttemptAt
+		dto.NextAttemptAt = &next
+	}
+	_ = json.Unmarshal([]byte(item.PayloadJSON), &dto.Payload)
+	return dto
+}
+
+func toPluginHookDeliveryDTOs(items []domain.PluginHookDelivery) []PluginHookDeliveryDTO {
+	out := make([]PluginHookDeliveryDTO, 0, len(items))
+	for _, item := range items {
+		out = append(out, toPluginHookDeliveryDTO(item))
+	}
+	return out
+}
+
+func toPluginVersionDTO(item domain.PluginVersion) PluginVersionDTO {
+	return PluginVersionDTO{
+		Version:         item.Version,
+		SignatureStatus: string(item.SignatureStatus),
+		Status:          string(item.Status),
+		LastError:       item.LastError,
+		ActivatedAt:     item.ActivatedAt,
+		CreatedAt:       item.CreatedAt,
+	}
+}
+
+func toPluginVersionDTOs(items []domain.PluginVersion) []PluginVersionDTO {
+	out := make([]PluginVersionDTO, 0, len(items))
+	for _, item := range items {
+		out = append(out, toPluginVersionDTO(item))
+	}
+	return out
+}
+
+func toVPSActivityDTOs(items []domain.VPSActivity) []VPSActivityDTO {
+	out := make([]VPSActivityDTO, 0, len(items))
+	for _, item := range items {
+		out = append(out, VPSActivityDTO{
+			ID:        item.ID,
+			VPSID:     item.VPSID,
+			ActorType: string(item.ActorType),
+			ActorID:   item.ActorID,
+			Action:    item.Action,
+			Detail:    item.Detail,
+			IP:        item.IP,
+			Result:    string(item.Result),
+			Error:     item.ErrorMessage,
+			CreatedAt: item.CreatedAt,
+		})
+	}
+	return out
+}
+
+func toProvisionAttemptDTOs(items []domain.ProvisionAttempt) []ProvisionAttemptDTO {
+	out := make([]ProvisionAttemptDTO, 0, len(items))
+	for _, item := range items {
+		out = append(out, ProvisionAttemptDTO{
+			ID:          item.ID,
+			OrderID:     item.OrderID,
+			OrderItemID: item.OrderItemID,
+			Attempt:     item.Attempt,
+			Strategy:    string(item.Strategy),
+			LineID:      item.LineID,
+			PackageID:   item.PackageID,
+			Success:     item.Success,
+			Error:       item.ErrorMessage,
+			CreatedAt:   item.CreatedAt,
+		})
+	}
+	return out
+}
+
 func toOrderEventDTOs(items []domain.OrderEvent) []OrderEventDTO {
 	out := make([]OrderEventDTO, 0, len(items))
 	for _, item := range items {
@@ -1289,7 +1786,7 @@ func normalizeOrderItemSpec(action, specJSON string) json.RawMessage {
 		return raw
 	}
 	action = strings.ToLower(strings.TrimSpace(action))
-	if action != "resize" && action != "refund" {
+	if action != "resize" && action != "refund" && action != "add_ip" {
 		return raw
 	}
 
@@ -1299,7 +1796,7 @@ func normalizeOrderItemSpec(action, specJSON string) json.RawMessage {
 	}
 
 	changed := false
-	for _, key := range []string{"current_monthly", "target_monthly", "charge_amount", "refund_amount"} {
+	for _, key := range []string{"current_monthly", "target_monthly", "charge_amount", "refund_amount", "monthly_delta"} {
 		value, ok := payload[key]
 		if !ok {
 			continue
@@ -1433,6 +1930,10 @@ func planGroupDTOToDomain(dto PlanGroupDTO) domain.PlanGroup {
 		AddBWMin:          dto.AddBWMin,
 		AddBWMax:          dto.AddBWMax,
 		AddBWStep:         dto.AddBWStep,
+		UnitIPv4:          floatToCents(dto.UnitIPv4),
+		UnitIPv6:          floatToCents(dto.UnitIPv6),
+		AddIPv4Max:        dto.AddIPv4Max,
+		AddIPv6Max:        dto.AddIPv6Max,
 		Active:            dto.Active,
 		Visible:           dto.Visible,
 		CapacityRemaining: dto.CapacityRemaining,
@@ -1581,11 +2082,68 @@ type TicketResourceDTO struct {
 	ResourceName string    `json:"resource_name"`
 	CreatedAt    time.Time `json:"created_at"`
 }
+
+type AbuseCaseDTO struct {
+	ID              int64      `json:"id"`
+	Source          string     `json:"source"`
+	Category        string     `json:"category"`
+	Reporter        string     `json:"reporter"`
+	Subject         string     `json:"subject"`
+	Description     string     `json:"description"`
+	Status          string     `json:"status"`
+	AssigneeID      int64      `json:"assignee_id"`
+	Resolution      string     `json:"resolution"`
+	AckDueAt        time.Time  `json:"ack_due_at"`
+	ResolveDueAt    time.Time  `json:"resolve_due_at"`
+	ResponseDueAt   *time.Time `json:"response_due_at,omitempty"`
+	AcknowledgedAt  *time.Time `json:"acknowledged_at,omitempty"`
+	WarnedAt        *time.Time `json:"warned_at,omitempty"`
+	ClosedAt        *time.Time `json:"closed_at,omitempty"`
+	AckOverdue      bool       `json:"ack_overdue"`
+	ResolveOverdue  bool       `json:"resolve_overdue"`
+	ResponseExpired bool       `json:"response_expired"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+type AbuseCaseLinkDTO struct {
+	ID         int64     `json:"id"`
+	TargetType string    `json:"target_type"`
+	TargetID   int64     `json:"target_id"`
+	TicketID   int64     `json:"ticket_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type AbuseEvidenceDTO struct {
+	ID        int64     `json:"id"`
+	Kind      string    `json:"kind"`
+	Content   string    `json:"content"`
+	UploadID  int64     `json:"upload_id,omitempty"`
+	AddedBy   int64     `json:"added_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AbuseCaseEventDTO struct {
+	ID        int64           `json:"id"`
+	AdminID   int64           `json:"admin_id"`
+	Type      string          `json:"type"`
+	Detail    json.RawMessage `json:"detail"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type AbuseCaseDetailDTO struct {
+	AbuseCaseDTO
+	Links    []AbuseCaseLinkDTO  `json:"links"`
+	Evidence []AbuseEvidenceDTO  `json:"evidence"`
+	Events   []AbuseCaseEventDTO `json:"events"`
+}
 type UploadDTO struct {
 	ID         int64     `json:"id"`
 	Name       string    `json:"name"`
 	Path       string    `json:"path"`
 	URL        string    `json:"url"`
+	Backend    string    `json:"backend"`
+	ObjectKey  string    `json:"object_key"`
 	Mime       string    `json:"mime"`
 	Size       int64     `json:"size"`
 	UploaderID int64     `json:"uploader_id"`
@@ -1686,6 +2244,8 @@ func toUploadDTO(item domain.Upload) UploadDTO {
 		Name:       item.Name,
 		Path:       item.Path,
 		URL:        item.URL,
+		Backend:    item.Backend,
+		ObjectKey:  item.ObjectKey,
 		Mime:       item.Mime,
 		Size:       item.Size,
 		UploaderID: item.UploaderID,
@@ -3,22 +3,30 @@ package http
 import (
 	"regexp"
 	"time"
+	appabuse "xiaoheiplay/internal/app/abuse"
 	appadmin "xiaoheiplay/internal/app/admin"
 	appadminvps "xiaoheiplay/internal/app/adminvps"
 	appcart "xiaoheiplay/internal/app/cart"
 	appcatalog "xiaoheiplay/internal/app/catalog"
 	appcms "xiaoheiplay/internal/app/cms"
+	appconsole "xiaoheiplay/internal/app/console"
+	appfirewall "xiaoheiplay/internal/app/firewall"
 	appgoodstype "xiaoheiplay/internal/app/goodstype"
 	appmessage "xiaoheiplay/internal/app/message"
+	appmetrics "xiaoheiplay/internal/app/metrics"
 	appopenapi "xiaoheiplay/internal/app/openapi"
 	apppasswordreset "xiaoheiplay/internal/app/passwordreset"
 	apppayment "xiaoheiplay/internal/app/payment"
 	apppermission "xiaoheiplay/internal/app/permission"
+	apppluginhook "xiaoheiplay/internal/app/pluginhook"
 	appports "xiaoheiplay/internal/app/ports"
 	appprobe "xiaoheiplay/internal/app/probe"
 	apppush "xiaoheiplay/internal/app/push"
+	apprdns "xiaoheiplay/internal/app/rdns"
 	apprealname "xiaoheiplay/internal/app/realname"
+	apprescue "xiaoheiplay/internal/app/rescue"
 	appscheduledtask "xiaoheiplay/internal/app/scheduledtask"
+	appsshkey "xiaoheiplay/internal/app/sshkey"
 	appticket "xiaoheiplay/internal/app/ticket"
 	appuserapikey "xiaoheiplay/internal/app/userapikey"
 	appwallet "xiaoheiplay/internal/app/wallet"
@@ -38,6 +46,10 @@ var (
 	resetVerifyLimiter   = newRateLimiter()
 	contactCodeLimiter   = newRateLimiter()
 	contactVerifyLimiter = newRateLimiter()
+	reverseDNSLimiter    = newRateLimiter()
+	rescueLimiter        = newRateLimiter()
+	abuseReportLimiter   = newRateLimiter()
+	consoleTicketLimiter = newRateLimiter()
 	simpleTemplateVarRE  = regexp.MustCompile(`\{\{\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*}}`)
 )
 
@@ -80,21 +92,32 @@ type HandlerDeps struct {
 	SettingsSvc       SettingsService
 	UploadSvc         UploadService
 	Broker            EventBroker
+	VPSOperationSvc   VPSOperationService
+	VPSOperationFeed  VPSOperationStreamer
 	JWTSecret         string
 	PasswordReset     *apppasswordreset.Service
 	SecurityTicketSvc SecurityTicketService
 	PermissionSvc     *apppermission.Service
 	PluginAdmin       PluginAdminService
+	PluginHookSvc     *apppluginhook.Service
 	UserTierSvc       UserTierService
 	CouponSvc         CouponService
 	TaskSvc           *appscheduledtask.Service
 	UserAPIKeySvc     *appuserapikey.Service
+	SSHKeySvc         *appsshkey.Service
+	ReverseDNSSvc     *apprdns.Service
+	RescueSvc         *apprescue.Service
+	MetricsSvc        *appmetrics.Service
+	AbuseSvc          *appabuse.Service
+	ConsoleSvc        *appconsole.Service
+	FirewallSvc       *appfirewall.Service
 	OpenAPISvc        *appopenapi.Service
 	ProbeSvc          *appprobe.Service
 	ProbeHub          *appprobe.Hub
 	GeoResolver       GeoResolver
 	EmailSender       appports.EmailSender
 	SMSSender         appports.SMSSender
+	CaptchaProvider   appports.CaptchaProvider
 	RobotNotifier     RobotEventNotifier
 }
 
@@ -123,21 +146,32 @@ type Handler struct {
 	settingsSvc       SettingsService
 	uploadSvc         UploadService
 	broker            EventBroker
+	vpsOperationSvc   VPSOperationService
+	vpsOperationFeed  VPSOperationStreamer
 	jwtSecret         []byte
 	passwordReset     *apppasswordreset.Service
 	securityTicketSvc SecurityTicketService
 	permissionSvc     *apppermission.Service
 	pluginAdmin       PluginAdminService
+	pluginHookSvc     *apppluginhook.Service
 	userTierSvc       UserTierService
 	couponSvc         CouponService
 	taskSvc           *appscheduledtask.Service
 	userAPIKeySvc     *appuserapikey.Service
+	sshKeySvc         *appsshkey.Service
+	reverseDNSSvc     *apprdns.Service
+	rescueSvc         *apprescue.Service
+	metricsSvc        *appmetrics.Service
+	abuseSvc          *appabuse.Service
+	consoleSvc        *appconsole.Service
+	firewallSvc       *appfirewall.Service
 	openAPISvc        *appopenapi.Service
 	probeSvc          *appprobe.Service
 	probeHub          *appprobe.Hub
 	geoResolver       GeoResolver
 	emailSender       appports.EmailSender
 	smsSender         appports.SMSSender
+	captchaProvider   appports.CaptchaProvider
 	robotNotifier     RobotEventNotifier
 }
 
@@ -155,6 +189,9 @@ type authSettings struct {
 	RegisterVerifyTTL      time.Duration
 	RegisterCaptchaEnabled bool
 	CaptchaProvider        string
+	CaptchaPluginID        string
+	CaptchaInstanceID      string
+	CaptchaScenes          map[string]captchaSceneSettings
 	GeeTestCaptchaID       string
 	GeeTestCaptchaKey      string
 	GeeTestAPIServer       string
@@ -172,9 +209,11 @@ type authSettings struct {
 	LoginNotifyOnIPChange  bool
 	LoginNotifyChannels    []string
 
-	PasswordResetEnabled   bool
-	PasswordResetChannels  []string
-	PasswordResetVerifyTTL time.Duration
+	PasswordResetEnabled        bool
+	PasswordResetChannels       []string
+	PasswordResetVerifyTTL      time.Duration
+	PasswordResetCaptchaEnabled bool
+	OrderSubmitCaptchaEnabled   bool
 
 	SMSCodeLength       int
 	SMSCodeComplexity   string
@@ -224,21 +263,32 @@ func NewHandler(deps HandlerDeps) *Handler {
 		settingsSvc:       deps.SettingsSvc,
 		uploadSvc:         deps.UploadSvc,
 		broker:            deps.Broker,
+		vpsOperationSvc:   deps.VPSOperationSvc,
+		vpsOperationFeed:  deps.VPSOperationFeed,
 		jwtSecret:         []byte(deps.JWTSecret),
 		passwordReset:     deps.PasswordReset,
 		securityTicketSvc: deps.SecurityTicketSvc,
 		permissionSvc:     deps.PermissionSvc,
 		pluginAdmin:       deps.PluginAdmin,
+		pluginHookSvc:     deps.PluginHookSvc,
 		userTierSvc:       deps.UserTierSvc,
 		couponSvc:         deps.CouponSvc,
 		taskSvc:           deps.TaskSvc,
 		userAPIKeySvc:     deps.UserAPIKeySvc,
+		sshKeySvc:         deps.SSHKeySvc,
+		reverseDNSSvc:     deps.ReverseDNSSvc,
+		rescueSvc:         deps.RescueSvc,
+		metricsSvc:        deps.MetricsSvc,
+		abuseSvc:          deps.AbuseSvc,
+		consoleSvc:        deps.ConsoleSvc,
+		firewallSvc:       deps.FirewallSvc,
 		openAPISvc:        deps.OpenAPISvc,
 		probeSvc:          deps.ProbeSvc,
 		probeHub:          deps.ProbeHub,
 		geoResolver:       deps.GeoResolver,
 		emailSender:       deps.EmailSender,
 		smsSender:         deps.SMSSender,
+		captchaProvider:   deps.CaptchaProvider,
 		robotNotifier:     deps.RobotNotifier,
 	}
 }
@@ -0,0 +1,344 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	appabuse "xiaoheiplay/internal/app/abuse"
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+type abuseEvidencePayload struct {
+	Kind     string `json:"kind" binding:"required,oneof=text url upload"`
+	Content  string `json:"content" binding:"required,max=10000"`
+	UploadID int64  `json:"upload_id" binding:"omitempty,gt=0"`
+}
+
+type abuseCasePayload struct {
+	Category    string                 `json:"category" binding:"max=32"`
+	Reporter    string                 `json:"reporter" binding:"max=255"`
+	Subject     string                 `json:"subject" binding:"required,max=240"`
+	Description string                 `json:"description" binding:"max=10000"`
+	IP          string                 `json:"ip" binding:"omitempty,ip"`
+	VPSIDs      []int64                `json:"vps_ids" binding:"max=50,dive,gt=0"`
+	UserIDs     []int64                `json:"user_ids" binding:"max=50,dive,gt=0"`
+	Evidence    []abuseEvidencePayload `json:"evidence" binding:"max=20,dive"`
+}
+
+type abuseLinkPayload struct {
+	TargetType string `json:"target_type" binding:"required,oneof=vps user"`
+	TargetID   int64  `json:"target_id" binding:"required,gt=0"`
+}
+
+type abuseTextPayload struct {
+	Text string `json:"text" binding:"required,max=10000"`
+}
+
+type abuseTerminatePayload struct {
+	VPSID int64 `json:"vps_id" binding:"required,gt=0"`
+}
+
+func (h *Handler) AdminAbuseCases(c *gin.Context) {
+	if h.abuseSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var query struct {
+		Status     string `form:"status"`
+		Queue      bool   `form:"queue"`
+		TargetType string `form:"target_type" binding:"omitempty,oneof=vps user"`
+		TargetID   int64  `form:"target_id" binding:"omitempty,gt=0"`
+	}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidInput.Error()})
+		return
+	}
+	limit, offset := paging(c)
+	items, total, err := h.abuseSvc.List(c, appshared.AbuseCaseFilter{
+		Status:     strings.TrimSpace(query.Status),
+		Active:     query.Queue,
+		TargetType: query.TargetType,
+		TargetID:   query.TargetID,
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	resp := make([]AbuseCaseDTO, 0, len(items))
+	for _, item := range items {
+		resp = append(resp, toAbuseCaseDTO(item))
+	}
+	c.JSON(http.StatusOK, gin.H{"items": resp, "total": total})
+}
+
+func (h *Handler) AdminAbuseCaseCreate(c *gin.Context) {
+	if h.abuseSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var payload abuseCasePayload
+	if err := bindJSON(c, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
+		return
+	}
+	item, err := h.abuseSvc.Create(c, getUserID(c), payload.toInput())
+	if err != nil {
+		writeAbuseError(c, err)
+		return
+	}
+	detail, err := h.abuseSvc.Get(c, item.ID)
+	if err != nil {
+		writeAbuseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toAbuseCaseDetailDTO(detail))
+}
+
+func (h *Handler) AdminAbuseCaseDetail(c *gin.Context) {
+	id, ok := h.abuseCaseID(c)
+	if !ok {
+		return
+	}
+	detail, err := h.abuseSvc.Get(c, id)
+	if err != nil {
+		writeAbuseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toAbuseCaseDetailDTO(detail))
+}
+
+func (h *Handler) AdminAbuseCaseAck(c *gin.Context) {
+	id, ok := h.abuseCaseID(c)
+	if !ok {
+		return
+	}
+	h.writeAbuseResult(c, id, h.abuseSvc.Acknowledge(c, getUserID(c), id))
+}
+
+func (h *Handler) AdminAbuseCaseEvidence(c *gin.Context) {
+	id, ok := h.abuseCaseID(c)
+	if !ok {
+		return
+	}
+	var payload abuseEvidencePayload
+	if err := bindJSON(c, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
+		return
+	}
+	_, err := h.abuseSvc.AddEvidence(c, getUserID(c), id, appabuse.EvidenceInput{Kind: payload.Kind, Content: payload.Content, UploadID: payload.UploadID})
+	h.writeAbuseResult(c, id, err)
+}
+
+func (h *Handler) AdminAbuseCaseLink(c *gin.Context) {
+	id, ok := h.abuseCaseID(c)
+	if !ok {
+		return
+	}
+	var payload abuseLinkPayload
+	if err := bindJSON(c, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
+		return
+	}
+	h.writeAbuseResult(c, id, h.abuseSvc.Link(c, getUserID(c), id, payload.TargetType, payload.TargetID))
+}
+
+func (h *Handler) AdminAbuseCaseNote(c *gin.Context) {
+	h.abuseTextAction(c, h.abuseSvc.AddNote)
+}
+
+func (h *Handler) AdminAbuseCaseWarn(c *gin.Context) {
+	h.abuseTextAction(c, h.abuseSvc.Warn)
+}
+
+func (h *Handler) AdminAbuseCaseResolve(c *gin.Context) {
+	h.abuseTextAction(c, h.abuseSvc.Resolve)
+}
+
+func (h *Handler) AdminAbuseCaseDismiss(c *gin.Context) {
+	h.abuseTextAction(c, h.abuseSvc.Dismiss)
+}
+
+func (h *Handler) AdminAbuseCaseLock(c *gin.Context) {
+	id, ok := h.abuseCaseID(c)
+	if !ok {
+		return
+	}
+	var payload struct {
+		Reason string `json:"reason" binding:"max=500"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := bindJSON(c, &payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
+			return
+		}
+	}
+	h.writeAbuseResult(c, id, h.abuseSvc.Lock(c, getUserID(c), id, payload.Reason))
+}
+
+func (h *Handler) AdminAbuseCaseTerminate(c *gin.Context) {
+	id, ok := h.abuseCaseID(c)
+	if !ok {
+		return
+	}
+	var payload abuseTerminatePayload
+	if err := bindJSON(c, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
+		return
+	}
+	h.writeAbuseResult(c, id, h.abuseSvc.Terminate(c, getUserID(c), id, payload.VPSID))
+}
+
+// AbuseReport is the inbound report webhook. Callers authenticate with the
+// shared token in the X-Abuse-Token header.
+func (h *Handler) AbuseReport(c *gin.Context) {
+	if h.abuseSvc == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrNotFound.Error()})
+		return
+	}
+	if !abuseReportLimiter.Allow(fmt.Sprintf("abuse_report:%s", c.ClientIP()), 60, time.Minute) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": domain.ErrTooManyRequests.Error()})
+		return
+	}
+	var payload abuseCasePayload
+	if err := bindJSON(c, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
+		return
+	}
+	item, err := h.abuseSvc.Report(c, c.GetHeader("X-Abuse-Token"), payload.toInput())
+	if err != nil {
+		writeAbuseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"id": item.ID, "status": item.Status})
+}
+
+func (h *Handler) abuseTextAction(c *gin.Context, action func(ctx context.Context, adminID, id int64, text string) error) {
+	id, ok := h.abuseCaseID(c)
+	if !ok {
+		return
+	}
+	var payload abuseTextPayload
+	if err := bindJSON(c, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
+		return
+	}
+	h.writeAbuseResult(c, id, action(c, getUserID(c), id, payload.Text))
+}
+
+func (h *Handler) abuseCaseID(c *gin.Context) (int64, bool) {
+	if h.abuseSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return 0, false
+	}
+	var uri adminIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return 0, false
+	}
+	return uri.ID, true
+}
+
+// writeAbuseResult answers an action with the refreshed case so the desk UI
+// can redraw the timeline without a second request.
+func (h *Handler) writeAbuseResult(c *gin.Context, id int64, err error) {
+	if err != nil {
+		writeAbuseError(c, err)
+		return
+	}
+	detail, err := h.abuseSvc.Get(c, id)
+	if err != nil {
+		writeAbuseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toAbuseCaseDetailDTO(detail))
+}
+
+func (p abuseCasePayload) toInput() appabuse.CreateInput {
+	input := appabuse.CreateInput{
+		Category:    p.Category,
+		Reporter:    p.Reporter,
+		Subject:     p.Subject,
+		Description: p.Description,
+		IP:          p.IP,
+		VPSIDs:      p.VPSIDs,
+		UserIDs:     p.UserIDs,
+	}
+	for _, item := range p.Evidence {
+		input.Evidence = append(input.Evidence, appabuse.EvidenceInput{Kind: item.Kind, Content: item.Content, UploadID: item.UploadID})
+	}
+	return input
+}
+
+func toAbuseCaseDTO(item appabuse.QueueItem) AbuseCaseDTO {
+	c := item.Case
+	return AbuseCaseDTO{
+		ID:              c.ID,
+		Source:          c.Source,
+		Category:        c.Category,
+		Reporter:        c.Reporter,
+		Subject:         c.Subject,
+		Description:     c.Description,
+		Status:          string(c.Status),
+		AssigneeID:      c.AssigneeID,
+		Resolution:      c.Resolution,
+		AckDueAt:        c.AckDueAt,
+		ResolveDueAt:    c.ResolveDueAt,
+		ResponseDueAt:   c.ResponseDueAt,
+		AcknowledgedAt:  c.AcknowledgedAt,
+		WarnedAt:        c.WarnedAt,
+		ClosedAt:        c.ClosedAt,
+		AckOverdue:      item.AckOverdue,
+		ResolveOverdue:  item.ResolveOverdue,
+		ResponseExpired: item.ResponseExpired,
+		CreatedAt:       c.CreatedAt,
+		UpdatedAt:       c.UpdatedAt,
+	}
+}
+
+func toAbuseCaseDetailDTO(detail appabuse.Detail) AbuseCaseDetailDTO {
+	out := AbuseCaseDetailDTO{
+		AbuseCaseDTO: toAbuseCaseDTO(detail.QueueItem),
+		Links:        make([]AbuseCaseLinkDTO, 0, len(detail.Links)),
+		Evidence:     make([]AbuseEvidenceDTO, 0, len(detail.Evidence)),
+		Events:       make([]AbuseCaseEventDTO, 0, len(detail.Events)),
+	}
+	for _, link := range detail.Links {
+		out.Links = append(out.Links, AbuseCaseLinkDTO{ID: link.ID, TargetType: link.TargetType, TargetID: link.TargetID, TicketID: link.TicketID, CreatedAt: link.CreatedAt})
+	}
+	for _, item := range detail.Evidence {
+		out.Evidence = append(out.Evidence, AbuseEvidenceDTO{ID: item.ID, Kind: item.Kind, Content: item.Content, UploadID: item.UploadID, AddedBy: item.AddedBy, CreatedAt: item.CreatedAt})
+	}
+	for _, event := range detail.Events {
+		raw := json.RawMessage(event.DetailJSON)
+		if !json.Valid(raw) {
+			raw = json.RawMessage("{}")
+		}
+		out.Events = append(out.Events, AbuseCaseEventDTO{ID: event.ID, AdminID: event.AdminID, Type: event.Type, Detail: raw, CreatedAt: event.CreatedAt})
+	}
+	return out
+}
+
+func writeAbuseError(c *gin.Context, err error) {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, appshared.ErrNotSupported):
+		status = http.StatusNotImplemented
+	case errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrAbuseWebhookDisabled):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, domain.ErrAbuseCaseClosed):
+		status = http.StatusConflict
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
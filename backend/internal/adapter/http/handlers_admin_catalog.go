@@ -242,6 +242,10 @@ func (h *Handler) AdminPlanGroupUpdate(c *gin.Context) {
 		AddBWMin          *int     `json:"add_bw_min"`
 		AddBWMax          *int     `json:"add_bw_max"`
 		AddBWStep         *int     `json:"add_bw_step"`
+		UnitIPv4          *float64 `json:"unit_ipv4"`
+		UnitIPv6          *float64 `json:"unit_ipv6"`
+		AddIPv4Max        *int     `json:"add_ipv4_max"`
+		AddIPv6Max        *int     `json:"add_ipv6_max"`
 		Active            *bool    `json:"active"`
 		Visible           *bool    `json:"visible"`
 		CapacityRemaining *int     `json:"capacity_remaining"`
@@ -316,6 +320,18 @@ func (h *Handler) AdminPlanGroupUpdate(c *gin.Context) {
 	if payload.AddBWStep != nil {
 		plan.AddBWStep = *payload.AddBWStep
 	}
+	if payload.UnitIPv4 != nil {
+		plan.UnitIPv4 = floatToCents(*payload.UnitIPv4)
+	}
+	if payload.UnitIPv6 != nil {
+		plan.UnitIPv6 = floatToCents(*payload.UnitIPv6)
+	}
+	if payload.AddIPv4Max != nil {
+		plan.AddIPv4Max = *payload.AddIPv4Max
+	}
+	if payload.AddIPv6Max != nil {
+		plan.AddIPv6Max = *payload.AddIPv6Max
+	}
 	if payload.Active != nil {
 		plan.Active = *payload.Active
 	}
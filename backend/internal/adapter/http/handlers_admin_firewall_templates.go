@@ -0,0 +1,121 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"xiaoheiplay/internal/domain"
+)
+
+func (h *Handler) AdminFirewallTemplates(c *gin.Context) {
+	if h.firewallSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	items, err := h.firewallSvc.AdminList(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": domain.ErrListError.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": toFirewallTemplateDTOs(items)})
+}
+
+func (h *Handler) AdminFirewallTemplateCreate(c *gin.Context) {
+	if h.firewallSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var payload firewallTemplatePayload
+	if err := bindJSON(c, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
+		return
+	}
+	tpl, err := h.firewallSvc.AdminCreate(c, getUserID(c), payload.toDomain())
+	if err != nil {
+		writeFirewallTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toFirewallTemplateDTO(tpl))
+}
+
+func (h *Handler) AdminFirewallTemplateUpdate(c *gin.Context) {
+	if h.firewallSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var uri adminIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	var payload firewallTemplatePayload
+	if err := bindJSON(c, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
+		return
+	}
+	tpl, err := h.firewallSvc.AdminUpdate(c, getUserID(c), uri.ID, payload.toDomain())
+	if err != nil {
+		writeFirewallTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toFirewallTemplateDTO(tpl))
+}
+
+func (h *Handler) AdminFirewallTemplateDelete(c *gin.Context) {
+	if h.firewallSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var uri adminIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	if err := h.firewallSvc.AdminDelete(c, getUserID(c), uri.ID); err != nil {
+		writeFirewallTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+func (h *Handler) AdminVPSFirewallTemplates(c *gin.Context) {
+	if h.firewallSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var uri adminIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	inst, err := h.adminVPS.Get(c, uri.ID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrNotFound.Error()})
+		return
+	}
+	attached, err := h.firewallSvc.Attached(c, inst)
+	if err != nil {
+		writeFirewallTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": toFirewallTemplateDTOs(attached.Templates), "sync": toVPSFirewallSyncDTO(attached.Sync)})
+}
+
+func (h *Handler) AdminVPSFirewallReconcile(c *gin.Context) {
+	if h.firewallSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var uri adminIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	sync, err := h.firewallSvc.AdminReconcile(c, getUserID(c), uri.ID)
+	if err != nil && sync.Status != domain.FirewallSyncFailed {
+		writeFirewallTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"sync": toVPSFirewallSyncDTO(sync)})
+}
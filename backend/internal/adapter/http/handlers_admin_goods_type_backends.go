@@ -0,0 +1,134 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"xiaoheiplay/internal/domain"
+)
+
+type adminGoodsTypeBackendURI struct {
+	ID        int64 `uri:"id" binding:"required,gt=0"`
+	BackendID int64 `uri:"backend_id" binding:"required,gt=0"`
+}
+
+func (h *Handler) AdminGoodsTypeBackends(c *gin.Context) {
+	if h.goodsTypes == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var uri adminIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	items, err := h.goodsTypes.ListBackends(c, uri.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": toAutomationBackendDTOs(items)})
+}
+
+func (h *Handler) AdminGoodsTypeBackendCreate(c *gin.Context) {
+	if h.goodsTypes == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var uri adminIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	var payload struct {
+		PluginID   string `json:"plugin_id"`
+		InstanceID string `json:"instance_id"`
+		Weight     int    `json:"weight"`
+		Capacity   int    `json:"capacity"`
+		Enabled    *bool  `json:"enabled"`
+	}
+	if err := bindJSON(c, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
+		return
+	}
+	backend := &domain.AutomationBackend{
+		GoodsTypeID: uri.ID,
+		PluginID:    strings.TrimSpace(payload.PluginID),
+		InstanceID:  strings.TrimSpace(payload.InstanceID),
+		Weight:      payload.Weight,
+		Capacity:    payload.Capacity,
+		Enabled:     payload.Enabled == nil || *payload.Enabled,
+	}
+	if err := h.goodsTypes.AddBackend(c, backend); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, domain.ErrNotFound) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, domain.ErrAutomationBackendExists) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, toAutomationBackendDTO(*backend))
+}
+
+func (h *Handler) AdminGoodsTypeBackendUpdate(c *gin.Context) {
+	if h.goodsTypes == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var uri adminGoodsTypeBackendURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	var payload struct {
+		Weight   int  `json:"weight"`
+		Capacity int  `json:"capacity"`
+		Enabled  bool `json:"enabled"`
+	}
+	if err := bindJSON(c, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
+		return
+	}
+	err := h.goodsTypes.UpdateBackend(c, domain.AutomationBackend{
+		ID:          uri.BackendID,
+		GoodsTypeID: uri.ID,
+		Weight:      payload.Weight,
+		Capacity:    payload.Capacity,
+		Enabled:     payload.Enabled,
+	})
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, domain.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+func (h *Handler) AdminGoodsTypeBackendDelete(c *gin.Context) {
+	if h.goodsTypes == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var uri adminGoodsTypeBackendURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	if err := h.goodsTypes.DeleteBackend(c, uri.ID, uri.BackendID); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, domain.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
@@ -6,6 +6,7 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/image/font"
@@ -16,8 +17,6 @@ import (
 	"image/draw"
 	"io"
 	"net/http"
-	"os"
-	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
@@ -260,10 +259,17 @@ func (h *Handler) AdminUploadCreate(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrFileOpenFailed.Error()})
 		return
 	}
-	head := make([]byte, 512)
-	n, _ := io.ReadFull(opened, head)
+	body, err := io.ReadAll(io.LimitReader(opened, maxUploadSize+1))
 	_ = opened.Close()
-	detected := http.DetectContentType(head[:n])
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrFileOpenFailed.Error()})
+		return
+	}
+	if len(body) > maxUploadSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrFileTooLarge.Error()})
+		return
+	}
+	detected := http.DetectContentType(body)
 	allowed := map[string]bool{
 		"image/png":  true,
 		"image/jpeg": true,
@@ -274,26 +280,38 @@ func (h *Handler) AdminUploadCreate(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrUnsupportedFileType.Error()})
 		return
 	}
-	dateDir := time.Now().Format("20060102")
-	if err := os.MkdirAll(filepath.Join("uploads", dateDir), 0o755); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": domain.ErrUploadDirError.Error()})
-		return
-	}
-	name := buildUploadName(detected)
-	localPath := filepath.Join("uploads", dateDir, name)
-	if err := c.SaveUploadedFile(file, localPath); err != nil {
+	key := time.Now().Format("20060102") + "/" + buildUploadName(detected)
+	item := domain.Upload{Name: file.Filename, ObjectKey: key, Mime: detected, Size: int64(len(body)), UploaderID: getUserID(c)}
+	if err := h.uploadSvc.Store(c, &item, body); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": domain.ErrSaveFailed.Error()})
 		return
 	}
-	url := "/uploads/" + dateDir + "/" + name
-	item := domain.Upload{Name: file.Filename, Path: localPath, URL: url, Mime: detected, Size: file.Size, UploaderID: getUserID(c)}
-	if err := h.uploadSvc.Create(c, &item); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
 	c.JSON(http.StatusOK, toUploadDTO(item))
 }
 
+// ServeUpload serves /uploads/<key> from the backend holding the upload,
+// redirecting to a presigned URL when the storage plugin offers one.
+func (h *Handler) ServeUpload(c *gin.Context) {
+	key := c.Param("filepath")
+	if h.uploadSvc != nil {
+		obj, err := h.uploadSvc.Open(c, key)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			c.JSON(http.StatusBadGateway, gin.H{"error": domain.ErrUploadUnavailable.Error()})
+			return
+		}
+		if err == nil && !obj.Local {
+			c.Header("Cache-Control", "private, max-age=300")
+			if obj.RedirectURL != "" {
+				c.Redirect(http.StatusFound, obj.RedirectURL)
+				return
+			}
+			c.Data(http.StatusOK, obj.ContentType, obj.Content)
+			return
+		}
+	}
+	c.FileFromFS(key, gin.Dir("uploads", false))
+}
+
 func (h *Handler) AdminUploads(c *gin.Context) {
 	if h.uploadSvc == nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
@@ -65,11 +65,16 @@ func (h *Handler) AdminOrderDetail(c *gin.Context) {
 	if h.orderEventSvc != nil {
 		events, _ = h.orderEventSvc.ListAfter(c, uri.ID, 0, 200)
 	}
+	var attempts []domain.ProvisionAttempt
+	if h.orderSvc != nil {
+		attempts, _, _ = h.orderSvc.ProvisionAttempts(c, appshared.ProvisionAttemptFilter{OrderID: uri.ID, Limit: 200})
+	}
 	c.JSON(http.StatusOK, gin.H{
-		"order":    toOrderDTO(order),
-		"items":    toOrderItemDTOs(items),
-		"payments": toOrderPaymentDTOs(payments),
-		"events":   toOrderEventDTOs(events),
+		"order":              toOrderDTO(order),
+		"items":              toOrderItemDTOs(items),
+		"payments":           toOrderPaymentDTOs(payments),
+		"events":             toOrderEventDTOs(events),
+		"provision_attempts": toProvisionAttemptDTOs(attempts),
 	})
 }
 
@@ -0,0 +1,78 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+type pluginHookDeliveryURI struct {
+	pluginCategoryPluginInstanceURI
+	ID int64 `uri:"id" binding:"required,gt=0"`
+}
+
+// AdminPluginHookDeliveries lists the hook events queued for an instance,
+// newest first.
+func (h *Handler) AdminPluginHookDeliveries(c *gin.Context) {
+	if h.pluginHookSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrPluginsDisabled.Error()})
+		return
+	}
+	var uri pluginCategoryPluginInstanceURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidInput.Error()})
+		return
+	}
+	var query struct {
+		Status string `form:"status" binding:"omitempty,oneof=pending delivered failed"`
+		Event  string `form:"event" binding:"omitempty,max=64"`
+	}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidInput.Error()})
+		return
+	}
+	limit, offset := paging(c)
+	items, total, err := h.pluginHookSvc.ListDeliveries(c, appshared.PluginHookDeliveryFilter{
+		Category:   uri.Category,
+		PluginID:   uri.PluginID,
+		InstanceID: uri.InstanceID,
+		Status:     query.Status,
+		Event:      query.Event,
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": toPluginHookDeliveryDTOs(items), "total": total})
+}
+
+// AdminPluginHookDeliveryRetry queues one more attempt of a failed delivery.
+func (h *Handler) AdminPluginHookDeliveryRetry(c *gin.Context) {
+	if h.pluginHookSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrPluginsDisabled.Error()})
+		return
+	}
+	var uri pluginHookDeliveryURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidInput.Error()})
+		return
+	}
+	item, err := h.pluginHookSvc.Retry(c, uri.Category, uri.PluginID, uri.InstanceID, uri.ID)
+	if err != nil {
+		status := http.StatusBadRequest
+		switch err {
+		case appshared.ErrNotFound:
+			status = http.StatusNotFound
+		case appshared.ErrConflict:
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, toPluginHookDeliveryDTO(item))
+}
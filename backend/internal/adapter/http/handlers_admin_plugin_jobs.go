@@ -0,0 +1,80 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+func (h *Handler) AdminPluginJobs(c *gin.Context) {
+	if h.taskSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrScheduledTasksDisabled.Error()})
+		return
+	}
+	items, err := h.taskSvc.ListPluginJobs(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+func (h *Handler) AdminPluginJobUpdate(c *gin.Context) {
+	if h.taskSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrScheduledTasksDisabled.Error()})
+		return
+	}
+	var uri adminTaskKeyURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidInput.Error()})
+		return
+	}
+	var payload struct {
+		Enabled *bool `json:"enabled"`
+	}
+	if err := bindJSON(c, &payload); err != nil || payload.Enabled == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
+		return
+	}
+	item, err := h.taskSvc.UpdatePluginJob(c, uri.Key, *payload.Enabled)
+	if err != nil {
+		status := http.StatusBadRequest
+		if err == appshared.ErrNotFound {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+// AdminPluginJobRun starts a manual run; the result shows up in the job's run
+// history.
+func (h *Handler) AdminPluginJobRun(c *gin.Context) {
+	if h.taskSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrScheduledTasksDisabled.Error()})
+		return
+	}
+	var uri adminTaskKeyURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidInput.Error()})
+		return
+	}
+	// The run outlives the request and gin recycles its context afterwards.
+	if err := h.taskSvc.TriggerPluginJob(context.Background(), uri.Key); err != nil {
+		status := http.StatusBadRequest
+		switch err {
+		case appshared.ErrNotFound:
+			status = http.StatusNotFound
+		case appshared.ErrConflict:
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"ok": true})
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"xiaoheiplay/internal/domain"
+)
+
+// AdminPluginRepository lists the plugins offered by the configured
+// repository indexes with their install and update state.
+func (h *Handler) AdminPluginRepository(c *gin.Context) {
+	if h.pluginAdmin == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrPluginsDisabled.Error()})
+		return
+	}
+	items, err := h.pluginAdmin.RepositoryPlugins(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	updates := 0
+	for _, it := range items {
+		if it.UpdateAvailable {
+			updates++
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "updates": updates})
+}
+
+// AdminPluginRepositoryInstall installs a plugin from the repository indexes,
+// or upgrades it when it is already installed.
+func (h *Handler) AdminPluginRepositoryInstall(c *gin.Context) {
+	if h.pluginAdmin == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrPluginsDisabled.Error()})
+		return
+	}
+	var payload struct {
+		Category string `json:"category" binding:"required,max=64"`
+		PluginID string `json:"plugin_id" binding:"required,max=128"`
+		Version  string `json:"version" binding:"omitempty,max=64"`
+	}
+	if err := bindJSON(c, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
+		return
+	}
+	version, err := h.pluginAdmin.InstallFromRepository(c, payload.Category, payload.PluginID, payload.Version)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		writePluginHandlerError(c, err)
+		return
+	}
+	if h.adminSvc != nil {
+		h.adminSvc.Audit(c, getUserID(c), "plugin.repository_install", "plugin", payload.Category+"/"+payload.PluginID, map[string]any{
+			"version":          version.Version,
+			"signature_status": version.SignatureStatus,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "version": toPluginVersionDTO(version)})
+}
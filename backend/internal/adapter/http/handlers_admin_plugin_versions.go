@@ -0,0 +1,121 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"xiaoheiplay/internal/domain"
+)
+
+type pluginVersionURI struct {
+	pluginCategoryPluginURI
+	Version string `uri:"version" binding:"required,max=64"`
+}
+
+// AdminPluginVersions lists the versions kept for a plugin, newest first.
+func (h *Handler) AdminPluginVersions(c *gin.Context) {
+	if h.pluginAdmin == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrPluginsDisabled.Error()})
+		return
+	}
+	var uri pluginCategoryPluginURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidInput.Error()})
+		return
+	}
+	items, err := h.pluginAdmin.ListVersions(c, uri.Category, uri.PluginID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": toPluginVersionDTOs(items)})
+}
+
+// AdminPluginUpgrade switches an installed plugin to an uploaded package.
+// Packages without an official signature need the admin password, as on
+// install.
+func (h *Handler) AdminPluginUpgrade(c *gin.Context) {
+	if !pluginUploadAllowed() {
+		c.JSON(http.StatusForbidden, gin.H{"error": domain.ErrPluginUploadDebugOnly.Error()})
+		return
+	}
+	if h.pluginAdmin == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrPluginsDisabled.Error()})
+		return
+	}
+	var uri pluginCategoryPluginURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidInput.Error()})
+		return
+	}
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrMissingFile.Error()})
+		return
+	}
+	trustUnofficial := false
+	if adminPassword := strings.TrimSpace(c.PostForm("admin_password")); adminPassword != "" {
+		if h.authSvc == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrAuthDisabled.Error()})
+			return
+		}
+		if err := h.authSvc.VerifyPassword(c, getUserID(c), adminPassword); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": domain.ErrInvalidAdminPassword.Error()})
+			return
+		}
+		trustUnofficial = true
+	}
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrOpenFileFailed.Error()})
+		return
+	}
+	defer f.Close()
+
+	version, err := h.pluginAdmin.Upgrade(c, uri.Category, uri.PluginID, file.Filename, f, trustUnofficial)
+	if err != nil {
+		if err == domain.ErrAdminPasswordRequiredForUntrustedPlugin {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		writePluginHandlerError(c, err)
+		return
+	}
+	if h.adminSvc != nil {
+		h.adminSvc.Audit(c, getUserID(c), "plugin.upgrade", "plugin", uri.Category+"/"+uri.PluginID, map[string]any{
+			"version":          version.Version,
+			"signature_status": version.SignatureStatus,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "version": toPluginVersionDTO(version)})
+}
+
+// AdminPluginRollback switches a plugin back to a version kept on disk.
+func (h *Handler) AdminPluginRollback(c *gin.Context) {
+	if h.pluginAdmin == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrPluginsDisabled.Error()})
+		return
+	}
+	var uri pluginVersionURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidInput.Error()})
+		return
+	}
+	version, err := h.pluginAdmin.Rollback(c, uri.Category, uri.PluginID, uri.Version)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		writePluginHandlerError(c, err)
+		return
+	}
+	if h.adminSvc != nil {
+		h.adminSvc.Audit(c, getUserID(c), "plugin.rollback", "plugin", uri.Category+"/"+uri.PluginID, map[string]any{
+			"version": version.Version,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true, "version": toPluginVersionDTO(version)})
+}
@@ -156,6 +156,10 @@ func (h *Handler) AdminPaymentProviderUpdate(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"ok": true})
 }
 
+// AdminPaymentPluginUpload stores a raw payment plugin binary.
+//
+// Deprecated: install plugins from a repository index through
+// AdminPluginRepositoryInstall.
 func (h *Handler) AdminPaymentPluginUpload(c *gin.Context) {
 	if !pluginUploadAllowed() {
 		c.JSON(http.StatusForbidden, gin.H{"error": domain.ErrPluginUploadDebugOnly.Error()})
@@ -692,6 +696,41 @@ func (h *Handler) AdminPluginInstanceConfigGet(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"config_json": cfg})
 }
 
+// AdminPluginInstanceLogs lists what the instance logged through
+// HostService.Log, newest first.
+func (h *Handler) AdminPluginInstanceLogs(c *gin.Context) {
+	if h.pluginAdmin == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrPluginsDisabled.Error()})
+		return
+	}
+	var uri pluginCategoryPluginInstanceURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidInput.Error()})
+		return
+	}
+	var query struct {
+		Level string `form:"level" binding:"omitempty,oneof=debug info warn error"`
+	}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidInput.Error()})
+		return
+	}
+	limit, offset := paging(c)
+	items, total, err := h.pluginAdmin.ListLogs(c, appshared.PluginLogFilter{
+		Category:   uri.Category,
+		PluginID:   uri.PluginID,
+		InstanceID: uri.InstanceID,
+		Level:      query.Level,
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": toPluginLogDTOs(items), "total": total})
+}
+
 func (h *Handler) AdminPluginInstanceConfigUpdate(c *gin.Context) {
 	if h.pluginAdmin == nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrPluginsDisabled.Error()})
@@ -0,0 +1,123 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+func (h *Handler) loadAllProvisionFallbackPolicies(ctx context.Context) map[string]appshared.ProvisionFallbackPolicy {
+	setting, err := h.getSettingByContext(ctx, appshared.PlanGroupProvisionFallbackSettingKey)
+	if err != nil {
+		return map[string]appshared.ProvisionFallbackPolicy{}
+	}
+	raw := strings.TrimSpace(setting.ValueJSON)
+	if raw == "" || raw == "{}" {
+		return map[string]appshared.ProvisionFallbackPolicy{}
+	}
+	var out map[string]appshared.ProvisionFallbackPolicy
+	if err := json.Unmarshal([]byte(raw), &out); err != nil || out == nil {
+		return map[string]appshared.ProvisionFallbackPolicy{}
+	}
+	return out
+}
+
+func (h *Handler) AdminPlanGroupProvisionFallbackGet(c *gin.Context) {
+	var uri adminIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	if _, err := h.catalogSvc.GetPlanGroup(c, uri.ID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrNotFound.Error()})
+		return
+	}
+	policy := h.loadAllProvisionFallbackPolicies(c)[strconv.FormatInt(uri.ID, 10)]
+	c.JSON(http.StatusOK, gin.H{"plan_group_id": uri.ID, "fallback": policy})
+}
+
+// AdminPlanGroupProvisionFallbackUpdate replaces the fallback policy of the
+// plan group; an empty policy removes it.
+func (h *Handler) AdminPlanGroupProvisionFallbackUpdate(c *gin.Context) {
+	if h.adminSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var uri adminIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	if _, err := h.catalogSvc.GetPlanGroup(c, uri.ID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrNotFound.Error()})
+		return
+	}
+	var payload appshared.ProvisionFallbackPolicy
+	if err := bindJSON(c, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
+		return
+	}
+	if err := payload.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for _, id := range payload.FallbackPackageIDs {
+		if _, err := h.catalogSvc.GetPackage(c, id); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidInput.Error()})
+			return
+		}
+	}
+	all := h.loadAllProvisionFallbackPolicies(c)
+	key := strconv.FormatInt(uri.ID, 10)
+	if payload.Empty() {
+		delete(all, key)
+	} else {
+		all[key] = payload
+	}
+	raw, err := json.Marshal(all)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.adminSvc.UpdateSetting(c, getUserID(c), appshared.PlanGroupProvisionFallbackSettingKey, string(raw)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+// AdminProvisionAttempts lists provision attempts across orders for the
+// provision watchdog; failed=true keeps only failed attempts.
+func (h *Handler) AdminProvisionAttempts(c *gin.Context) {
+	if h.orderSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var query struct {
+		OrderID int64 `form:"order_id" binding:"omitempty,gte=0"`
+		Failed  bool  `form:"failed"`
+	}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidInput.Error()})
+		return
+	}
+	limit, offset := paging(c)
+	items, total, err := h.orderSvc.ProvisionAttempts(c, appshared.ProvisionAttemptFilter{
+		OrderID: query.OrderID,
+		Failed:  query.Failed,
+		Limit:   limit,
+		Offset:  offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": domain.ErrListError.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": toProvisionAttemptDTOs(items), "total": total})
+}
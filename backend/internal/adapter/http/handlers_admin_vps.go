@@ -165,7 +165,9 @@ func (h *Handler) AdminVPSDetail(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrNotFound.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, h.toVPSInstanceDTOWithLifecycle(c, inst))
+	dto := h.toVPSInstanceDTOWithLifecycle(c, inst)
+	dto.ExtraIPs = h.vpsExtraIPDTOs(c, inst.ID)
+	c.JSON(http.StatusOK, dto)
 }
 
 func (h *Handler) AdminVPSUpdate(c *gin.Context) {
@@ -278,7 +280,7 @@ func (h *Handler) AdminVPSUpdateExpire(c *gin.Context) {
 			return
 		}
 	}
-	inst, err := h.adminVPS.UpdateExpireAt(c, getUserID(c), uri.ID, t)
+	inst, err := h.adminVPS.UpdateExpireAt(activityContext(c), getUserID(c), uri.ID, t)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -292,7 +294,7 @@ func (h *Handler) AdminVPSLock(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
 		return
 	}
-	if err := h.adminVPS.SetAdminStatus(c, getUserID(c), uri.ID, domain.VPSAdminStatusLocked, "lock"); err != nil {
+	if err := h.adminVPS.SetAdminStatus(activityContext(c), getUserID(c), uri.ID, domain.VPSAdminStatusLocked, "lock"); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -305,7 +307,7 @@ func (h *Handler) AdminVPSUnlock(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
 		return
 	}
-	if err := h.adminVPS.SetAdminStatus(c, getUserID(c), uri.ID, domain.VPSAdminStatusNormal, "unlock"); err != nil {
+	if err := h.adminVPS.SetAdminStatus(activityContext(c), getUserID(c), uri.ID, domain.VPSAdminStatusNormal, "unlock"); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -364,7 +366,7 @@ func (h *Handler) AdminVPSResize(c *gin.Context) {
 	if payload.Bandwidth > 0 {
 		req.Bandwidth = &payload.Bandwidth
 	}
-	if err := h.adminVPS.Resize(c, getUserID(c), uri.ID, req, mustJSON(payload)); err != nil {
+	if err := h.adminVPS.Resize(activityContext(c), getUserID(c), uri.ID, req, mustJSON(payload)); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -386,7 +388,7 @@ func (h *Handler) AdminVPSStatus(c *gin.Context) {
 		return
 	}
 	status := domain.VPSAdminStatus(payload.Status)
-	if err := h.adminVPS.SetAdminStatus(c, getUserID(c), uri.ID, status, payload.Reason); err != nil {
+	if err := h.adminVPS.SetAdminStatus(activityContext(c), getUserID(c), uri.ID, status, payload.Reason); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
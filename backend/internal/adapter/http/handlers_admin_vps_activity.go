@@ -0,0 +1,39 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"xiaoheiplay/internal/domain"
+)
+
+func (h *Handler) AdminVPSActivities(c *gin.Context) {
+	inst, ok := h.adminActivityInstance(c)
+	if !ok {
+		return
+	}
+	h.listVPSActivities(c, inst.ID)
+}
+
+func (h *Handler) AdminVPSActivitiesExport(c *gin.Context) {
+	inst, ok := h.adminActivityInstance(c)
+	if !ok {
+		return
+	}
+	h.exportVPSActivities(c, inst.ID)
+}
+
+func (h *Handler) adminActivityInstance(c *gin.Context) (domain.VPSInstance, bool) {
+	var uri adminIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return domain.VPSInstance{}, false
+	}
+	inst, err := h.adminVPS.Get(c, uri.ID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrNotFound.Error()})
+		return domain.VPSInstance{}, false
+	}
+	return inst, true
+}
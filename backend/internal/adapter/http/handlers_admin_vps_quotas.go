@@ -0,0 +1,140 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+func (h *Handler) loadAllQuotaPolicies(ctx context.Context, key string) map[string]appshared.VPSQuotaPolicy {
+	setting, err := h.getSettingByContext(ctx, key)
+	if err != nil {
+		return map[string]appshared.VPSQuotaPolicy{}
+	}
+	raw := strings.TrimSpace(setting.ValueJSON)
+	if raw == "" || raw == "{}" {
+		return map[string]appshared.VPSQuotaPolicy{}
+	}
+	var out map[string]appshared.VPSQuotaPolicy
+	if err := json.Unmarshal([]byte(raw), &out); err != nil || out == nil {
+		return map[string]appshared.VPSQuotaPolicy{}
+	}
+	return out
+}
+
+func (h *Handler) saveQuotaPolicy(c *gin.Context, settingKey string, itemID int64, policy appshared.VPSQuotaPolicy) error {
+	if h.adminSvc == nil || itemID <= 0 {
+		return domain.ErrNotSupported
+	}
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	all := h.loadAllQuotaPolicies(c, settingKey)
+	key := strconv.FormatInt(itemID, 10)
+	if policy.Empty() {
+		delete(all, key)
+	} else {
+		all[key] = policy
+	}
+	raw, err := json.Marshal(all)
+	if err != nil {
+		return err
+	}
+	return h.adminSvc.UpdateSetting(c, getUserID(c), settingKey, string(raw))
+}
+
+func (h *Handler) AdminGoodsTypeQuotasGet(c *gin.Context) {
+	if h.goodsTypes == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var uri adminIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	if _, err := h.goodsTypes.Get(c, uri.ID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrNotFound.Error()})
+		return
+	}
+	policy := h.loadAllQuotaPolicies(c, appshared.GoodsTypeQuotaSettingKey)[strconv.FormatInt(uri.ID, 10)]
+	c.JSON(http.StatusOK, gin.H{"goods_type_id": uri.ID, "quotas": policy})
+}
+
+func (h *Handler) AdminGoodsTypeQuotasUpdate(c *gin.Context) {
+	if h.goodsTypes == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var uri adminIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	if _, err := h.goodsTypes.Get(c, uri.ID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrNotFound.Error()})
+		return
+	}
+	var payload appshared.VPSQuotaPolicy
+	if err := bindJSON(c, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
+		return
+	}
+	if err := h.saveQuotaPolicy(c, appshared.GoodsTypeQuotaSettingKey, uri.ID, payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+// AdminPackageQuotasGet returns the package policy together with the goods
+// type policy it inherits from.
+func (h *Handler) AdminPackageQuotasGet(c *gin.Context) {
+	var uri adminIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	pkg, err := h.catalogSvc.GetPackage(c, uri.ID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrNotFound.Error()})
+		return
+	}
+	own := h.loadAllQuotaPolicies(c, appshared.PackageQuotaSettingKey)[strconv.FormatInt(uri.ID, 10)]
+	inherited := h.loadAllQuotaPolicies(c, appshared.GoodsTypeQuotaSettingKey)[strconv.FormatInt(pkg.GoodsTypeID, 10)]
+	c.JSON(http.StatusOK, gin.H{
+		"package_id":        uri.ID,
+		"quotas":            own,
+		"goods_type_quotas": inherited,
+		"effective":         inherited.Merge(own),
+	})
+}
+
+func (h *Handler) AdminPackageQuotasUpdate(c *gin.Context) {
+	var uri adminIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	if _, err := h.catalogSvc.GetPackage(c, uri.ID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrNotFound.Error()})
+		return
+	}
+	var payload appshared.VPSQuotaPolicy
+	if err := bindJSON(c, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
+		return
+	}
+	if err := h.saveQuotaPolicy(c, appshared.PackageQuotaSettingKey, uri.ID, payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"xiaoheiplay/internal/domain"
+)
+
+func (h *Handler) AdminVPSReverseDNS(c *gin.Context) {
+	if h.reverseDNSSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var uri adminIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	inst, err := h.adminVPS.Get(c, uri.ID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrNotFound.Error()})
+		return
+	}
+	items, err := h.reverseDNSSvc.List(c, inst)
+	if err != nil {
+		writeReverseDNSError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+func (h *Handler) AdminVPSReverseDNSSet(c *gin.Context) {
+	if h.reverseDNSSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var uri adminIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	var payload vpsReverseDNSPayload
+	if err := bindJSON(c, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
+		return
+	}
+	if err := h.reverseDNSSvc.AdminSet(c, getUserID(c), uri.ID, payload.IP, payload.PTR); err != nil {
+		writeReverseDNSError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+func (h *Handler) AdminVPSReverseDNSChanges(c *gin.Context) {
+	if h.reverseDNSSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var uri adminIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	limit, offset := paging(c)
+	items, total, err := h.reverseDNSSvc.ListChanges(c, uri.ID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": domain.ErrListError.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": toReverseDNSChangeDTOs(items), "total": total})
+}
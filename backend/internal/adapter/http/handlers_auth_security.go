@@ -53,6 +53,7 @@ func (h *Handler) PasswordResetSendCode(c *gin.Context) {
 		Account   string `json:"account"`
 		Channel   string `json:"channel"`
 		PhoneFull string `json:"phone_full"`
+		captchaAnswer
 	}
 	if err := bindJSON(c, &payload); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
@@ -63,6 +64,12 @@ func (h *Handler) PasswordResetSendCode(c *gin.Context) {
 		c.JSON(http.StatusForbidden, gin.H{"error": domain.ErrPasswordResetDisabled.Error()})
 		return
 	}
+	if settings.PasswordResetCaptchaEnabled {
+		if err := h.verifyHumanCaptcha(c, settings, appshared.CaptchaScenePasswordReset, payload.captchaAnswer); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrCaptchaFailed.Error()})
+			return
+		}
+	}
 	user, err := h.findUserByAccount(c, payload.Account)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrAccountNotFound.Error()})
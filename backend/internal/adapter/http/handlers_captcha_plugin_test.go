@@ -0,0 +1,112 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"xiaoheiplay/internal/domain"
+	"xiaoheiplay/internal/testutil"
+	"xiaoheiplay/internal/testutilhttp"
+)
+
+func setAuthSettings(t *testing.T, env *testutilhttp.Env, kv map[string]string) {
+	t.Helper()
+	for k, v := range kv {
+		if err := env.AdminSvc.UpdateSetting(context.Background(), 1, k, v); err != nil {
+			t.Fatalf("set %s: %v", k, err)
+		}
+	}
+}
+
+func TestHandlers_CaptchaPlugin_PerScene(t *testing.T) {
+	env := testutilhttp.NewTestEnv(t, false)
+	setAuthSettings(t, env, map[string]string{
+		"auth_login_captcha_enabled":     "true",
+		"auth_login_captcha_provider":    "plugin",
+		"auth_login_captcha_plugin_id":   "mock_captcha",
+		"auth_login_captcha_instance_id": "default",
+	})
+	testutil.CreateUser(t, env.Repo, "carol", "carol@example.com", "pass123")
+
+	rec := testutil.DoJSON(t, env.Router, http.MethodGet, "/api/v1/captcha?scene=login", nil, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("captcha init: %d %s", rec.Code, rec.Body.String())
+	}
+	body := parseJSONBody(t, rec)
+	if body["captcha_provider"] != "plugin" || body["widget"] != "mock" {
+		t.Fatalf("unexpected plugin challenge: %v", body)
+	}
+	rec = testutil.DoJSON(t, env.Router, http.MethodGet, "/api/v1/captcha?scene=register", nil, "")
+	if body := parseJSONBody(t, rec); body["captcha_provider"] != "image" {
+		t.Fatalf("register scene must keep the global provider, got %v", body)
+	}
+	rec = testutil.DoJSON(t, env.Router, http.MethodGet, "/api/v1/auth/settings", nil, "")
+	scenes, _ := parseJSONBody(t, rec)["captcha_scenes"].(map[string]any)
+	if scenes["login"] != "plugin" || scenes["register"] != "image" || scenes["order_submit"] != "image" {
+		t.Fatalf("unexpected captcha scenes: %v", scenes)
+	}
+
+	rec = testutil.DoJSON(t, env.Router, http.MethodPost, "/api/v1/auth/login", map[string]any{
+		"username":      "carol",
+		"password":      "pass123",
+		"captcha_token": "wrong",
+	}, "")
+	if rec.Code != http.StatusBadRequest || parseJSONBody(t, rec)["error"] != domain.ErrCaptchaFailed.Error() {
+		t.Fatalf("expected captcha failure, got %d %s", rec.Code, rec.Body.String())
+	}
+	rec = testutil.DoJSON(t, env.Router, http.MethodPost, "/api/v1/auth/login", map[string]any{
+		"username":       "carol",
+		"password":       "pass123",
+		"captcha_token":  "mock-pass",
+		"captcha_params": map[string]string{"challenge": "mock-1"},
+	}, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("login with plugin captcha: %d %s", rec.Code, rec.Body.String())
+	}
+	last := env.Captcha.Answers[len(env.Captcha.Answers)-1]
+	if last.Scene != "login" || last.Params["challenge"] != "mock-1" {
+		t.Fatalf("unexpected answer passed to plugin: %+v", last)
+	}
+}
+
+func TestHandlers_CaptchaPlugin_PasswordResetAndOrderSubmit(t *testing.T) {
+	env := testutilhttp.NewTestEnv(t, false)
+	setAuthSettings(t, env, map[string]string{
+		"auth_captcha_provider":               "plugin",
+		"auth_captcha_plugin_id":              "mock_captcha",
+		"auth_password_reset_captcha_enabled": "true",
+		"auth_order_submit_captcha_enabled":   "true",
+	})
+	user := testutil.CreateUser(t, env.Repo, "dave", "dave@example.com", "pass123")
+	token := testutil.IssueJWT(t, env.JWTSecret, user.ID, "user", time.Hour)
+
+	rec := testutil.DoJSON(t, env.Router, http.MethodPost, "/api/v1/auth/password-reset/send-code", map[string]any{
+		"account": "dave",
+		"channel": "email",
+	}, "")
+	if rec.Code != http.StatusBadRequest || parseJSONBody(t, rec)["error"] != domain.ErrCaptchaFailed.Error() {
+		t.Fatalf("expected password reset captcha failure, got %d %s", rec.Code, rec.Body.String())
+	}
+	rec = testutil.DoJSON(t, env.Router, http.MethodPost, "/api/v1/auth/password-reset/send-code", map[string]any{
+		"account":       "dave",
+		"channel":       "email",
+		"captcha_token": "mock-pass",
+	}, "")
+	if parseJSONBody(t, rec)["error"] == domain.ErrCaptchaFailed.Error() {
+		t.Fatalf("password reset with a valid captcha must pass the captcha check: %s", rec.Body.String())
+	}
+
+	rec = testutil.DoJSON(t, env.Router, http.MethodPost, "/api/v1/orders", nil, token)
+	if rec.Code != http.StatusBadRequest || parseJSONBody(t, rec)["error"] != domain.ErrCaptchaFailed.Error() {
+		t.Fatalf("expected order captcha failure, got %d %s", rec.Code, rec.Body.String())
+	}
+	rec = testutil.DoJSON(t, env.Router, http.MethodPost, "/api/v1/orders", map[string]any{"captcha_token": "mock-pass"}, token)
+	if parseJSONBody(t, rec)["error"] == domain.ErrCaptchaFailed.Error() {
+		t.Fatalf("order with a valid captcha must pass the captcha check: %s", rec.Body.String())
+	}
+	if n := len(env.Captcha.Answers); n == 0 || env.Captcha.Answers[n-1].Scene != "order_submit" {
+		t.Fatalf("expected order_submit answer, got %+v", env.Captcha.Answers)
+	}
+}
@@ -2,6 +2,7 @@ package http_test
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"testing"
 	"time"
@@ -108,9 +109,21 @@ func TestHandlers_UserOpsMore(t *testing.T) {
 		"template_id": altImage.ID,
 		"password":    "Pass123!",
 	}, token)
-	if rec.Code != http.StatusOK {
+	if rec.Code != http.StatusAccepted {
 		t.Fatalf("vps reset-os: %d", rec.Code)
 	}
+	var resetResp struct {
+		Operation struct {
+			ID int64 `json:"id"`
+		} `json:"operation"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resetResp); err != nil || resetResp.Operation.ID == 0 {
+		t.Fatalf("vps reset-os operation: %v %s", err, rec.Body.String())
+	}
+	op := waitVPSOperation(t, env, resetResp.Operation.ID)
+	if op.Status != domain.VPSOperationStatusSucceeded {
+		t.Fatalf("expected reset-os operation succeeded, got %s (%s)", op.Status, op.ErrorMessage)
+	}
 	updatedInst, err := env.Repo.GetInstance(context.Background(), inst.ID)
 	if err != nil {
 		t.Fatalf("get updated vps: %v", err)
@@ -143,3 +156,21 @@ func TestHandlers_UserOpsMore(t *testing.T) {
 func ptrTime(t time.Time) *time.Time {
 	return &t
 }
+
+func waitVPSOperation(t *testing.T, env *testutilhttp.Env, id int64) domain.VPSOperation {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		op, err := env.Repo.GetVPSOperation(context.Background(), id)
+		if err != nil {
+			t.Fatalf("get vps operation: %v", err)
+		}
+		if op.Status == domain.VPSOperationStatusSucceeded || op.Status == domain.VPSOperationStatusFailed {
+			return op
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("vps operation %d still %s", id, op.Status)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
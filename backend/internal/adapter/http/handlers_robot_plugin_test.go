@@ -43,6 +43,14 @@ func TestHandlers_AdminPaymentPluginUpload(t *testing.T) {
 	gin.SetMode(gin.DebugMode)
 	defer gin.SetMode(previousMode)
 
+	pluginDir := t.TempDir()
+	if err := env.Repo.UpsertSetting(context.Background(), domain.Setting{
+		Key:       "payment_plugin_dir",
+		ValueJSON: pluginDir,
+	}); err != nil {
+		t.Fatalf("set payment plugin dir: %v", err)
+	}
+
 	groupID := ensureAdminGroup(t, env)
 	admin := testutil.CreateAdmin(t, env.Repo, "adminplugin", "adminplugin@example.com", "pass", groupID)
 	token := testutil.IssueJWT(t, env.JWTSecret, admin.ID, "admin", time.Hour)
@@ -70,6 +78,9 @@ func TestHandlers_AdminPaymentPluginUpload(t *testing.T) {
 	if rec.Code != http.StatusOK {
 		t.Fatalf("payment plugin upload: %d", rec.Code)
 	}
+	if _, err := os.Stat(filepath.Join(pluginDir, "demo.exe")); err != nil {
+		t.Fatalf("uploaded plugin not written to payment_plugin_dir: %v", err)
+	}
 }
 
 func TestHandlers_AdminPaymentPluginUpload_DisabledOutsideDebug(t *testing.T) {
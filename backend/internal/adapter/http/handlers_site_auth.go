@@ -84,6 +84,20 @@ func (h *Handler) loadAuthSettings(ctx context.Context) authSettings {
 		return def
 	}
 
+	captchaProvider := normalizeCaptchaProvider(getString("auth_captcha_provider", "image"))
+	captchaPluginID := strings.TrimSpace(getString("auth_captcha_plugin_id", ""))
+	captchaInstanceID := strings.TrimSpace(getString("auth_captcha_instance_id", "default"))
+	sceneCaptcha := make(map[string]captchaSceneSettings, len(captchaScenes))
+	for _, scene := range captchaScenes {
+		cfg := captchaSceneSettings{Provider: captchaProvider, PluginID: captchaPluginID, InstanceID: captchaInstanceID}
+		if provider := strings.TrimSpace(getString("auth_"+scene+"_captcha_provider", "")); provider != "" {
+			cfg.Provider = normalizeCaptchaProvider(provider)
+			cfg.PluginID = strings.TrimSpace(getString("auth_"+scene+"_captcha_plugin_id", captchaPluginID))
+			cfg.InstanceID = strings.TrimSpace(getString("auth_"+scene+"_captcha_instance_id", captchaInstanceID))
+		}
+		sceneCaptcha[scene] = cfg
+	}
+
 	verifyType := strings.ToLower(getString("auth_register_verify_type", "none"))
 	if verifyType != "email" && verifyType != "sms" {
 		verifyType = "none"
@@ -113,7 +127,10 @@ func (h *Handler) loadAuthSettings(ctx context.Context) authSettings {
 		RegisterVerifyChannels:         verifyChannels,
 		RegisterVerifyTTL:              time.Duration(getInt("auth_register_verify_ttl_sec", 600)) * time.Second,
 		RegisterCaptchaEnabled:         getBool("auth_register_captcha_enabled", true),
-		CaptchaProvider:                normalizeCaptchaProvider(getString("auth_captcha_provider", "image")),
+		CaptchaProvider:                captchaProvider,
+		CaptchaPluginID:                captchaPluginID,
+		CaptchaInstanceID:              captchaInstanceID,
+		CaptchaScenes:                  sceneCaptcha,
 		GeeTestCaptchaID:               getString("auth_geetest_captcha_id", ""),
 		GeeTestCaptchaKey:              getString("auth_geetest_captcha_key", ""),
 		GeeTestAPIServer:               strings.TrimRight(getString("auth_geetest_api_server", "https://gcaptcha4.geetest.com"), "/"),
@@ -133,6 +150,8 @@ func (h *Handler) loadAuthSettings(ctx context.Context) authSettings {
 		PasswordResetEnabled:           getBool("auth_password_reset_enabled", true),
 		PasswordResetChannels:          normalizeChannels(getStringSlice("auth_password_reset_channels", []string{"email"})),
 		PasswordResetVerifyTTL:         time.Duration(getInt("auth_password_reset_verify_ttl_sec", 600)) * time.Second,
+		PasswordResetCaptchaEnabled:    getBool("auth_password_reset_captcha_enabled", false),
+		OrderSubmitCaptchaEnabled:      getBool("auth_order_submit_captcha_enabled", false),
 		SMSCodeLength:                  getCodeLength("auth_sms_code_len", 6),
 		SMSCodeComplexity:              getCodeComplexity("auth_sms_code_complexity", appshared.CodeComplexityDigits),
 		EmailCodeLength:                getCodeLength("auth_email_code_len", 6),
@@ -189,13 +208,33 @@ func validatePasswordBySettings(password string, s authSettings) error {
 
 func (h *Handler) Captcha(c *gin.Context) {
 	settings := h.loadAuthSettings(c)
-	if settings.CaptchaProvider == "geetest" {
+	scene := strings.TrimSpace(c.Query("scene"))
+	cfg := settings.captchaFor(scene)
+	switch cfg.Provider {
+	case "geetest":
 		c.JSON(http.StatusOK, gin.H{
 			"captcha_provider": "geetest",
 			"captcha_id":       settings.GeeTestCaptchaID,
 			"api_server":       settings.GeeTestAPIServer,
 		})
 		return
+	case "plugin":
+		if h.captchaProvider == nil || cfg.PluginID == "" {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": domain.ErrCaptchaError.Error()})
+			return
+		}
+		challenge, err := h.captchaProvider.Init(c, cfg.PluginID, cfg.InstanceID, scene, c.ClientIP())
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": domain.ErrCaptchaError.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"captcha_provider": "plugin",
+			"scene":            scene,
+			"widget":           challenge.Widget,
+			"params":           challenge.Params,
+		})
+		return
 	}
 	captcha, code, err := h.authSvc.CreateCaptchaWithPolicy(c, 5*time.Minute, settings.CaptchaLength, settings.CaptchaComplexity)
 	if err != nil {
@@ -222,17 +261,12 @@ func (h *Handler) Captcha(c *gin.Context) {
 
 func (h *Handler) Register(c *gin.Context) {
 	var payload struct {
-		Username      string `json:"username"`
-		Email         string `json:"email"`
-		QQ            string `json:"qq"`
-		Phone         string `json:"phone"`
-		Password      string `json:"password"`
-		CaptchaID     string `json:"captcha_id"`
-		CaptchaCode   string `json:"captcha_code"`
-		LotNumber     string `json:"lot_number"`
-		CaptchaOutput string `json:"captcha_output"`
-		PassToken     string `json:"pass_token"`
-		GenTime       string `json:"gen_time"`
+		Username string `json:"username"`
+		Email    string `json:"email"`
+		QQ       string `json:"qq"`
+		Phone    string `json:"phone"`
+		Password string `json:"password"`
+		captchaAnswer
 		VerifyCode    string `json:"verify_code"`
 		VerifyChannel string `json:"verify_channel"`
 	}
@@ -287,12 +321,7 @@ func (h *Handler) Register(c *gin.Context) {
 		return
 	}
 	if len(settings.RegisterVerifyChannels) > 0 && settings.RegisterCaptchaEnabled {
-		if err := h.verifyHumanCaptcha(c, settings, payload.CaptchaID, payload.CaptchaCode, geeTestValidatePayload{
-			LotNumber:     payload.LotNumber,
-			CaptchaOutput: payload.CaptchaOutput,
-			PassToken:     payload.PassToken,
-			GenTime:       payload.GenTime,
-		}); err != nil {
+		if err := h.verifyHumanCaptcha(c, settings, appshared.CaptchaSceneRegister, payload.captchaAnswer); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrCaptchaFailed.Error()})
 			return
 		}
@@ -371,14 +400,9 @@ func (h *Handler) Register(c *gin.Context) {
 
 func (h *Handler) Login(c *gin.Context) {
 	var payload struct {
-		Username      string `json:"username"`
-		Password      string `json:"password"`
-		CaptchaID     string `json:"captcha_id"`
-		CaptchaCode   string `json:"captcha_code"`
-		LotNumber     string `json:"lot_number"`
-		CaptchaOutput string `json:"captcha_output"`
-		PassToken     string `json:"pass_token"`
-		GenTime       string `json:"gen_time"`
+		Username string `json:"username"`
+		Password string `json:"password"`
+		captchaAnswer
 	}
 	if err := bindJSON(c, &payload); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
@@ -393,12 +417,7 @@ func (h *Handler) Login(c *gin.Context) {
 		}
 	}
 	if settings.LoginCaptchaEnabled {
-		if err := h.verifyHumanCaptcha(c, settings, payload.CaptchaID, payload.CaptchaCode, geeTestValidatePayload{
-			LotNumber:     payload.LotNumber,
-			CaptchaOutput: payload.CaptchaOutput,
-			PassToken:     payload.PassToken,
-			GenTime:       payload.GenTime,
-		}); err != nil {
+		if err := h.verifyHumanCaptcha(c, settings, appshared.CaptchaSceneLogin, payload.captchaAnswer); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrCaptchaFailed.Error()})
 			return
 		}
@@ -457,6 +476,9 @@ func (h *Handler) AuthSettings(c *gin.Context) {
 		"register_captcha_enabled":                 settings.RegisterCaptchaEnabled,
 		"login_captcha_enabled":                    settings.LoginCaptchaEnabled,
 		"captcha_provider":                         settings.CaptchaProvider,
+		"captcha_scenes":                           captchaSceneProviders(settings),
+		"password_reset_captcha_enabled":           settings.PasswordResetCaptchaEnabled,
+		"order_submit_captcha_enabled":             settings.OrderSubmitCaptchaEnabled,
 		"auth_geetest_captcha_id":                  settings.GeeTestCaptchaID,
 		"auth_geetest_api_server":                  settings.GeeTestAPIServer,
 		"auth_login_notify_enabled":                settings.LoginNotifyEnabled,
@@ -486,15 +508,10 @@ func (h *Handler) AuthSettings(c *gin.Context) {
 
 func (h *Handler) RegisterCode(c *gin.Context) {
 	var payload struct {
-		Channel       string `json:"channel"`
-		Email         string `json:"email"`
-		Phone         string `json:"phone"`
-		CaptchaID     string `json:"captcha_id"`
-		CaptchaCode   string `json:"captcha_code"`
-		LotNumber     string `json:"lot_number"`
-		CaptchaOutput string `json:"captcha_output"`
-		PassToken     string `json:"pass_token"`
-		GenTime       string `json:"gen_time"`
+		Channel string `json:"channel"`
+		Email   string `json:"email"`
+		Phone   string `json:"phone"`
+		captchaAnswer
 	}
 	if err := bindJSON(c, &payload); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
@@ -506,12 +523,7 @@ func (h *Handler) RegisterCode(c *gin.Context) {
 		return
 	}
 	if settings.RegisterCaptchaEnabled {
-		if err := h.verifyHumanCaptcha(c, settings, payload.CaptchaID, payload.CaptchaCode, geeTestValidatePayload{
-			LotNumber:     payload.LotNumber,
-			CaptchaOutput: payload.CaptchaOutput,
-			PassToken:     payload.PassToken,
-			GenTime:       payload.GenTime,
-		}); err != nil {
+		if err := h.verifyHumanCaptcha(c, settings, appshared.CaptchaSceneRegister, payload.captchaAnswer); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrCaptchaFailed.Error()})
 			return
 		}
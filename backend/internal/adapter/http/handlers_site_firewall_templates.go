@@ -0,0 +1,201 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+type firewallTemplatePayload struct {
+	Name        string                    `json:"name" binding:"required,max=64"`
+	Description string                    `json:"description" binding:"max=255"`
+	GoodsTypeID int64                     `json:"goods_type_id"`
+	Rules       []FirewallTemplateRuleDTO `json:"rules" binding:"max=50"`
+}
+
+func (p firewallTemplatePayload) toDomain() domain.FirewallTemplate {
+	rules := make([]domain.FirewallTemplateRule, 0, len(p.Rules))
+	for _, rule := range p.Rules {
+		rules = append(rules, domain.FirewallTemplateRule{
+			Direction: rule.Direction,
+			Protocol:  rule.Protocol,
+			Method:    rule.Method,
+			Port:      rule.Port,
+			IP:        rule.IP,
+			Priority:  rule.Priority,
+		})
+	}
+	return domain.FirewallTemplate{
+		Name:        p.Name,
+		Description: p.Description,
+		GoodsTypeID: p.GoodsTypeID,
+		Rules:       rules,
+	}
+}
+
+type vpsFirewallTemplateURI struct {
+	ID         int64 `uri:"id" binding:"required,gt=0"`
+	TemplateID int64 `uri:"templateId" binding:"required,gt=0"`
+}
+
+func (h *Handler) FirewallTemplates(c *gin.Context) {
+	if h.firewallSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	items, err := h.firewallSvc.List(c, getUserID(c))
+	if err != nil {
+		writeFirewallTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": toFirewallTemplateDTOs(items)})
+}
+
+func (h *Handler) FirewallTemplateCreate(c *gin.Context) {
+	if h.firewallSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var payload firewallTemplatePayload
+	if err := bindJSON(c, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
+		return
+	}
+	tpl, err := h.firewallSvc.Create(c, getUserID(c), payload.toDomain())
+	if err != nil {
+		writeFirewallTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toFirewallTemplateDTO(tpl))
+}
+
+func (h *Handler) FirewallTemplateUpdate(c *gin.Context) {
+	if h.firewallSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var uri siteIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	var payload firewallTemplatePayload
+	if err := bindJSON(c, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
+		return
+	}
+	tpl, err := h.firewallSvc.Update(c, getUserID(c), uri.ID, payload.toDomain())
+	if err != nil {
+		writeFirewallTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toFirewallTemplateDTO(tpl))
+}
+
+func (h *Handler) FirewallTemplateDelete(c *gin.Context) {
+	if h.firewallSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var uri siteIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	if err := h.firewallSvc.Delete(c, getUserID(c), uri.ID); err != nil {
+		writeFirewallTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+func (h *Handler) VPSFirewallTemplates(c *gin.Context) {
+	inst, ok := h.firewallTemplateInstance(c)
+	if !ok {
+		return
+	}
+	attached, err := h.firewallSvc.Attached(c, inst)
+	if err != nil {
+		writeFirewallTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": toFirewallTemplateDTOs(attached.Templates), "sync": toVPSFirewallSyncDTO(attached.Sync)})
+}
+
+func (h *Handler) VPSFirewallTemplateAttach(c *gin.Context) {
+	inst, ok := h.firewallTemplateInstance(c)
+	if !ok {
+		return
+	}
+	var payload struct {
+		TemplateID int64 `json:"template_id" binding:"required,gt=0"`
+	}
+	if err := bindJSON(c, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
+		return
+	}
+	sync, err := h.firewallSvc.Attach(c, inst, payload.TemplateID)
+	if err != nil && sync.Status != domain.FirewallSyncFailed {
+		writeFirewallTemplateError(c, err)
+		return
+	}
+	// A provider failure leaves the attachment in place for the reconcile
+	// task, so report the sync state rather than an error.
+	c.JSON(http.StatusOK, gin.H{"sync": toVPSFirewallSyncDTO(sync)})
+}
+
+func (h *Handler) VPSFirewallTemplateDetach(c *gin.Context) {
+	var uri vpsFirewallTemplateURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidInput.Error()})
+		return
+	}
+	inst, ok := h.firewallTemplateInstance(c)
+	if !ok {
+		return
+	}
+	sync, err := h.firewallSvc.Detach(c, inst, uri.TemplateID)
+	if err != nil && sync.Status != domain.FirewallSyncFailed {
+		writeFirewallTemplateError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"sync": toVPSFirewallSyncDTO(sync)})
+}
+
+func (h *Handler) firewallTemplateInstance(c *gin.Context) (domain.VPSInstance, bool) {
+	if h.firewallSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return domain.VPSInstance{}, false
+	}
+	var uri vpsIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return domain.VPSInstance{}, false
+	}
+	inst, err := h.vpsSvc.Get(c, uri.ID, getUserID(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrNotFound.Error()})
+		return domain.VPSInstance{}, false
+	}
+	if h.denyIfFeatureDisabled(c, inst, "firewall", "防火墙") {
+		return domain.VPSInstance{}, false
+	}
+	return inst, true
+}
+
+func writeFirewallTemplateError(c *gin.Context, err error) {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, appshared.ErrNotSupported):
+		status = http.StatusNotImplemented
+	case errors.Is(err, domain.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrFirewallTemplateLimitReached):
+		status = http.StatusConflict
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
@@ -420,6 +420,7 @@ func (h *Handler) OrderCreate(c *gin.Context) {
 	var payload struct {
 		Items      []appshared.OrderItemInput `json:"items"`
 		CouponCode string                     `json:"coupon_code"`
+		captchaAnswer
 	}
 	if c.Request.ContentLength > 0 {
 		if err := bindJSON(c, &payload); err != nil {
@@ -427,6 +428,9 @@ func (h *Handler) OrderCreate(c *gin.Context) {
 			return
 		}
 	}
+	if !h.verifyOrderSubmitCaptcha(c, payload.captchaAnswer) {
+		return
+	}
 	idem := c.GetHeader("Idempotency-Key")
 	var order domain.Order
 	var items []domain.OrderItem
@@ -447,11 +451,15 @@ func (h *Handler) OrderCreateItems(c *gin.Context) {
 	var payload struct {
 		Items      []appshared.OrderItemInput `json:"items" binding:"required,min=1,dive"`
 		CouponCode string                     `json:"coupon_code" binding:"omitempty,max=64"`
+		captchaAnswer
 	}
 	if err := bindJSON(c, &payload); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
 		return
 	}
+	if !h.verifyOrderSubmitCaptcha(c, payload.captchaAnswer) {
+		return
+	}
 	idem := c.GetHeader("Idempotency-Key")
 	order, items, err := h.orderSvc.CreateOrderFromItems(c, getUserID(c), "CNY", payload.Items, idem, payload.CouponCode)
 	if err != nil {
@@ -461,6 +469,20 @@ func (h *Handler) OrderCreateItems(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"order": toOrderDTO(order), "items": toOrderItemDTOs(items)})
 }
 
+// verifyOrderSubmitCaptcha writes the error response and returns false when
+// order submission requires a captcha and answer does not pass it.
+func (h *Handler) verifyOrderSubmitCaptcha(c *gin.Context, answer captchaAnswer) bool {
+	settings := h.loadAuthSettings(c)
+	if !settings.OrderSubmitCaptchaEnabled {
+		return true
+	}
+	if err := h.verifyHumanCaptcha(c, settings, appshared.CaptchaSceneOrderSubmit, answer); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrCaptchaFailed.Error()})
+		return false
+	}
+	return true
+}
+
 func (h *Handler) CouponPreview(c *gin.Context) {
 	var payload struct {
 		CouponCode string                     `json:"coupon_code" binding:"required,max=64"`
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"xiaoheiplay/internal/domain"
+)
+
+func (h *Handler) SSHKeys(c *gin.Context) {
+	if h.sshKeySvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	items, err := h.sshKeySvc.List(c, getUserID(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": toSSHKeyDTOs(items)})
+}
+
+func (h *Handler) SSHKeyCreate(c *gin.Context) {
+	if h.sshKeySvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var payload struct {
+		Name      string `json:"name" binding:"omitempty,max=64"`
+		PublicKey string `json:"public_key" binding:"required,max=16384"`
+	}
+	if err := bindJSON(c, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
+		return
+	}
+	key, err := h.sshKeySvc.Create(c, getUserID(c), payload.Name, payload.PublicKey)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, domain.ErrSSHKeyExists) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, toSSHKeyDTO(key))
+}
+
+func (h *Handler) SSHKeyDelete(c *gin.Context) {
+	if h.sshKeySvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var uri siteIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	if err := h.sshKeySvc.Delete(c, getUserID(c), uri.ID); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, domain.ErrNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
@@ -0,0 +1,166 @@
+package http
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+// activityContext tags the request context with the caller so instance
+// actions show up in the activity feed under the right actor.
+func activityContext(c *gin.Context) context.Context {
+	actor := appshared.VPSActivityActor{Type: domain.VPSActivityActorUser, ID: getUserID(c), IP: strings.TrimSpace(c.ClientIP())}
+	if val, ok := c.Get("request_actor"); ok {
+		if ra, ok := val.(domain.RequestActor); ok {
+			switch ra.Mode {
+			case domain.RequestActorModeUserAPIKey:
+				actor.Type = domain.VPSActivityActorAPIKey
+				actor.ID = ra.UserAPIKeyID
+			case domain.RequestActorModeAdminJWT:
+				actor.Type = domain.VPSActivityActorAdmin
+				actor.ID = ra.UserID
+			case domain.RequestActorModeAdminAPIKey:
+				actor.Type = domain.VPSActivityActorAdmin
+				actor.ID = ra.AdminAPIKeyID
+			}
+		}
+	}
+	return appshared.WithVPSActivityActor(c, actor)
+}
+
+func parseVPSActivityFilter(c *gin.Context, vpsID int64) (appshared.VPSActivityFilter, error) {
+	filter := appshared.VPSActivityFilter{
+		VPSID:     vpsID,
+		Action:    strings.TrimSpace(c.Query("action")),
+		ActorType: domain.VPSActivityActorType(strings.TrimSpace(c.Query("actor_type"))),
+		Result:    domain.VPSActivityResult(strings.TrimSpace(c.Query("result"))),
+	}
+	switch filter.ActorType {
+	case "", domain.VPSActivityActorUser, domain.VPSActivityActorAPIKey, domain.VPSActivityActorAdmin, domain.VPSActivityActorScheduler:
+	default:
+		return filter, domain.ErrInvalidInput
+	}
+	switch filter.Result {
+	case "", domain.VPSActivityResultSuccess, domain.VPSActivityResultFailed:
+	default:
+		return filter, domain.ErrInvalidInput
+	}
+	if raw := c.Query("from"); raw != "" {
+		t, err := parseQueryTime(raw)
+		if err != nil {
+			return filter, err
+		}
+		filter.From = &t
+	}
+	if raw := c.Query("to"); raw != "" {
+		t, err := parseQueryTime(raw)
+		if err != nil {
+			return filter, err
+		}
+		filter.To = &t
+	}
+	return filter, nil
+}
+
+func (h *Handler) VPSActivities(c *gin.Context) {
+	inst, ok := h.activityInstance(c)
+	if !ok {
+		return
+	}
+	h.listVPSActivities(c, inst.ID)
+}
+
+func (h *Handler) VPSActivitiesExport(c *gin.Context) {
+	inst, ok := h.activityInstance(c)
+	if !ok {
+		return
+	}
+	h.exportVPSActivities(c, inst.ID)
+}
+
+func (h *Handler) activityInstance(c *gin.Context) (domain.VPSInstance, bool) {
+	var uri vpsIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return domain.VPSInstance{}, false
+	}
+	inst, err := h.vpsSvc.Get(c, uri.ID, getUserID(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrNotFound.Error()})
+		return domain.VPSInstance{}, false
+	}
+	return inst, true
+}
+
+func (h *Handler) listVPSActivities(c *gin.Context, vpsID int64) {
+	filter, err := parseVPSActivityFilter(c, vpsID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidInput.Error()})
+		return
+	}
+	filter.Limit, filter.Offset = paging(c)
+	items, total, err := h.vpsSvc.Activities(c, filter)
+	if err != nil {
+		if errors.Is(err, appshared.ErrNotSupported) {
+			c.JSON(http.StatusOK, gin.H{"items": []VPSActivityDTO{}, "total": 0})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": domain.ErrListError.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": toVPSActivityDTOs(items), "total": total})
+}
+
+// exportVPSActivities writes the filtered feed as CSV, newest first, capped
+// at VPSActivityExportLimit rows.
+func (h *Handler) exportVPSActivities(c *gin.Context, vpsID int64) {
+	filter, err := parseVPSActivityFilter(c, vpsID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidInput.Error()})
+		return
+	}
+	filter.Limit = appshared.VPSActivityExportLimit
+	items, _, err := h.vpsSvc.Activities(c, filter)
+	if err != nil && !errors.Is(err, appshared.ErrNotSupported) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": domain.ErrListError.Error()})
+		return
+	}
+
+	fileName := fmt.Sprintf("vps_%d_activity_%s.csv", vpsID, time.Now().Format("20060102_150405"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", fileName))
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+	if _, err := c.Writer.Write([]byte{0xEF, 0xBB, 0xBF}); err != nil {
+		return
+	}
+	w := csv.NewWriter(c.Writer)
+	if err := w.Write([]string{"time", "action", "detail", "actor_type", "actor_id", "ip", "result", "error"}); err != nil {
+		return
+	}
+	for _, item := range items {
+		if err := w.Write([]string{
+			item.CreatedAt.UTC().Format(time.RFC3339),
+			item.Action,
+			item.Detail,
+			string(item.ActorType),
+			strconv.FormatInt(item.ActorID, 10),
+			item.IP,
+			string(item.Result),
+			item.ErrorMessage,
+		}); err != nil {
+			return
+		}
+	}
+	w.Flush()
+}
@@ -0,0 +1,213 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	appconsole "xiaoheiplay/internal/app/console"
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+// The ticket authenticates the relay, not cookies, so any origin may connect.
+var consoleUpgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+	CheckOrigin: func(r *http.Request) bool {
+		return true
+	},
+}
+
+func (h *Handler) VPSConsoleTicket(c *gin.Context) {
+	if h.consoleSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var uri vpsIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	inst, err := h.vpsSvc.Get(c, uri.ID, getUserID(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrNotFound.Error()})
+		return
+	}
+	if h.denyIfFeatureDisabled(c, inst, "vnc", "VNC") {
+		return
+	}
+	ticket, ok := h.issueConsoleTicket(c, inst)
+	if !ok {
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, VPSConsoleTicketDTO{
+		Ticket:    ticket.Token,
+		ExpiresAt: ticket.ExpiresAt,
+		WSPath:    appconsole.WSPath + "?ticket=" + url.QueryEscape(ticket.Token),
+		PageURL:   h.consoleSvc.PageURL(c, ticket.Token),
+	})
+}
+
+// ConsoleWS redeems a console ticket and relays the websocket to the
+// upstream console until either side hangs up or a limit is reached.
+func (h *Handler) ConsoleWS(c *gin.Context) {
+	if h.consoleSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	target, err := h.consoleSvc.Redeem(c, c.Query("ticket"))
+	if err != nil {
+		writeConsoleError(c, err)
+		return
+	}
+	id := target.Session.ID
+	upstream, err := dialConsoleUpstream(c, target, websocket.Subprotocols(c.Request))
+	if err != nil {
+		_ = h.consoleSvc.Finish(context.Background(), id, appconsole.ReasonUpstreamError, 0, 0)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "console upstream unavailable"})
+		return
+	}
+	var respHeader http.Header
+	if proto := upstream.Subprotocol(); proto != "" {
+		respHeader = http.Header{"Sec-Websocket-Protocol": {proto}}
+	}
+	client, err := consoleUpgrader.Upgrade(c.Writer, c.Request, respHeader)
+	if err != nil {
+		_ = upstream.Close()
+		_ = h.consoleSvc.Finish(context.Background(), id, appconsole.ReasonClosed, 0, 0)
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	release := h.consoleSvc.Track(id, cancel)
+	defer release()
+	result := relayConsole(ctx, client, upstream, target.Limits)
+	_ = h.consoleSvc.Finish(context.Background(), id, result.Reason, result.ClientBytes, result.UpstreamBytes)
+}
+
+func (h *Handler) AdminConsoleSessions(c *gin.Context) {
+	if h.consoleSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var query struct {
+		VPSID  int64 `form:"vps_id" binding:"omitempty,gt=0"`
+		UserID int64 `form:"user_id" binding:"omitempty,gt=0"`
+		Open   bool  `form:"open"`
+	}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidInput.Error()})
+		return
+	}
+	limit, offset := paging(c)
+	items, total, err := h.consoleSvc.List(c, appshared.ConsoleSessionFilter{VPSID: query.VPSID, UserID: query.UserID, Open: query.Open, Limit: limit, Offset: offset})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	resp := make([]ConsoleSessionDTO, 0, len(items))
+	for _, item := range items {
+		resp = append(resp, toConsoleSessionDTO(item))
+	}
+	c.JSON(http.StatusOK, gin.H{"items": resp, "total": total})
+}
+
+func (h *Handler) AdminConsoleSessionTerminate(c *gin.Context) {
+	if h.consoleSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var uri adminIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	if err := h.consoleSvc.Terminate(c, getUserID(c), uri.ID); err != nil {
+		writeConsoleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+func (h *Handler) issueConsoleTicket(c *gin.Context, inst domain.VPSInstance) (appconsole.Ticket, bool) {
+	if !consoleTicketLimiter.Allow(fmt.Sprintf("console_ticket:user:%d", inst.UserID), 30, time.Minute) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": domain.ErrTooManyRequests.Error()})
+		return appconsole.Ticket{}, false
+	}
+	ticket, err := h.consoleSvc.Issue(c, inst, c.ClientIP())
+	if err != nil {
+		writeConsoleError(c, err)
+		return appconsole.Ticket{}, false
+	}
+	return ticket, true
+}
+
+func dialConsoleUpstream(ctx context.Context, target appconsole.Target, subprotocols []string) (*websocket.Conn, error) {
+	upstreamURL, err := url.Parse(target.URL)
+	if err != nil {
+		return nil, err
+	}
+	dialer := websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: 10 * time.Second,
+		Subprotocols:     subprotocols,
+		ReadBufferSize:   4096,
+		WriteBufferSize:  4096,
+		TLSClientConfig:  &tls.Config{InsecureSkipVerify: target.Limits.InsecureTLS},
+	}
+	// websockify and most panels check Origin against their own host.
+	origin := "http://" + upstreamURL.Host
+	if upstreamURL.Scheme == "wss" {
+		origin = "https://" + upstreamURL.Host
+	}
+	conn, resp, err := dialer.DialContext(ctx, target.URL, http.Header{"Origin": {origin}})
+	if resp != nil && resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+	return conn, err
+}
+
+func writeConsoleError(c *gin.Context, err error) {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, domain.ErrConsoleTicketInvalid):
+		status = http.StatusUnauthorized
+	case errors.Is(err, domain.ErrConsoleSessionLimit):
+		status = http.StatusTooManyRequests
+	case errors.Is(err, appshared.ErrNotSupported):
+		status = http.StatusNotImplemented
+	case errors.Is(err, domain.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, domain.ErrConflict):
+		status = http.StatusConflict
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+func toConsoleSessionDTO(item domain.ConsoleSession) ConsoleSessionDTO {
+	return ConsoleSessionDTO{
+		ID:            item.ID,
+		VPSID:         item.VPSID,
+		UserID:        item.UserID,
+		Status:        item.Status,
+		ClientIP:      item.ClientIP,
+		UpstreamHost:  item.UpstreamHost,
+		StartedAt:     item.StartedAt,
+		EndedAt:       item.EndedAt,
+		EndReason:     item.EndReason,
+		ClientBytes:   item.ClientBytes,
+		UpstreamBytes: item.UpstreamBytes,
+		CreatedAt:     item.CreatedAt,
+	}
+}
@@ -0,0 +1,204 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+	"xiaoheiplay/internal/testutil"
+	"xiaoheiplay/internal/testutilhttp"
+)
+
+// newFakeVNCServer is a stand-in console: it sends the RFB banner and echoes
+// every frame back.
+func newFakeVNCServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	upgrader := websocket.Upgrader{Subprotocols: []string{"binary"}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if err := conn.WriteMessage(websocket.BinaryMessage, []byte("RFB 003.008\n")); err != nil {
+			return
+		}
+		for {
+			kind, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(kind, msg); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestHandlers_VPSConsoleProxy(t *testing.T) {
+	env := testutilhttp.NewTestEnv(t, false)
+	ctx := context.Background()
+	vnc, _ := url.Parse(newFakeVNCServer(t).URL)
+	// Plugins usually hand out a noVNC page on the panel host.
+	env.Automation.VNCURL = "https://panel.example/novnc/vnc.html?host=" + vnc.Hostname() + "&port=" + vnc.Port() + "&path=websockify&encrypt=0"
+	api := httptest.NewServer(env.Router)
+	t.Cleanup(api.Close)
+
+	user := testutil.CreateUser(t, env.Repo, "console", "console@example.com", "pass")
+	token := testutil.IssueJWT(t, env.JWTSecret, user.ID, "user", time.Hour)
+	inst := domain.VPSInstance{UserID: user.ID, AutomationInstanceID: "321", Name: "vm-console", Status: domain.VPSStatusRunning, SpecJSON: "{}"}
+	if err := env.Repo.CreateInstance(ctx, &inst); err != nil {
+		t.Fatalf("create instance: %v", err)
+	}
+
+	issue := func() (string, string) {
+		t.Helper()
+		rec := testutil.DoJSON(t, env.Router, http.MethodPost, "/api/v1/vps/"+testutil.Itoa(inst.ID)+"/console", nil, token)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("console ticket: %d %s", rec.Code, rec.Body.String())
+		}
+		var resp struct {
+			Ticket  string `json:"ticket"`
+			WSPath  string `json:"ws_path"`
+			PageURL string `json:"page_url"`
+		}
+		_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+		if resp.Ticket == "" || !strings.HasPrefix(resp.PageURL, "/vnc/console.html?") || !strings.Contains(resp.PageURL, "path=api%2Fv1%2Fconsole%2Fws%3Fticket%3D") {
+			t.Fatalf("unexpected ticket response %s", rec.Body.String())
+		}
+		return resp.Ticket, "ws" + strings.TrimPrefix(api.URL, "http") + resp.WSPath
+	}
+	dial := func(wsURL string) (*websocket.Conn, int) {
+		t.Helper()
+		conn, resp, err := (&websocket.Dialer{Subprotocols: []string{"binary"}}).Dial(wsURL, nil)
+		if err != nil {
+			if resp == nil {
+				t.Fatalf("dial: %v", err)
+			}
+			return nil, resp.StatusCode
+		}
+		return conn, http.StatusSwitchingProtocols
+	}
+
+	ticket, wsURL := issue()
+	conn, code := dial(wsURL)
+	if conn == nil {
+		t.Fatalf("expected relay upgrade, got %d", code)
+	}
+	if conn.Subprotocol() != "binary" {
+		t.Fatalf("expected upstream subprotocol passed through, got %q", conn.Subprotocol())
+	}
+	_, banner, err := conn.ReadMessage()
+	if err != nil || string(banner) != "RFB 003.008\n" {
+		t.Fatalf("expected rfb banner, got %q %v", banner, err)
+	}
+	if err := conn.WriteMessage(websocket.BinaryMessage, []byte("RFB 003.008\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, echo, err := conn.ReadMessage(); err != nil || string(echo) != "RFB 003.008\n" {
+		t.Fatalf("expected echo, got %q %v", echo, err)
+	}
+	if _, code := dial(wsURL); code != http.StatusUnauthorized {
+		t.Fatalf("expected ticket to be single use, got %d", code)
+	}
+	if _, code := dial(strings.Replace(wsURL, ticket, ticket+"x", 1)); code != http.StatusUnauthorized {
+		t.Fatalf("expected tampered ticket rejected, got %d", code)
+	}
+
+	// One live session plus one pending ticket reach the default limit of two.
+	issue()
+	rec := testutil.DoJSON(t, env.Router, http.MethodPost, "/api/v1/vps/"+testutil.Itoa(inst.ID)+"/console", nil, token)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected session limit, got %d", rec.Code)
+	}
+
+	_ = conn.Close()
+	var first domain.ConsoleSession
+	waitFor(t, func() bool {
+		items, _, _ := env.Repo.ListConsoleSessions(ctx, shared.ConsoleSessionFilter{VPSID: inst.ID})
+		first = items[len(items)-1]
+		return first.Status == "ended"
+	})
+	if first.EndReason != "closed" || first.ClientBytes != 12 || first.UpstreamBytes != 24 || first.UpstreamHost == "" {
+		t.Fatalf("unexpected session log %+v", first)
+	}
+
+	if err := env.Repo.UpsertSetting(ctx, domain.Setting{Key: "vps_console_idle_timeout_seconds", ValueJSON: "1"}); err != nil {
+		t.Fatalf("set idle timeout: %v", err)
+	}
+	_, wsURL = issue()
+	conn, code = dial(wsURL)
+	if conn == nil {
+		t.Fatalf("expected relay upgrade, got %d", code)
+	}
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure) || !strings.Contains(err.Error(), "idle") {
+				t.Fatalf("expected idle close, got %v", err)
+			}
+			break
+		}
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met in time")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestHandlers_VPSVNCLegacyRedirectOptIn(t *testing.T) {
+	env := testutilhttp.NewTestEnv(t, false)
+	ctx := context.Background()
+	env.Automation.VNCURL = "https://panel.example/novnc/vnc.html?host=10.0.0.5"
+	user := testutil.CreateUser(t, env.Repo, "vncuser", "vncuser@example.com", "pass")
+	token := testutil.IssueJWT(t, env.JWTSecret, user.ID, "user", time.Hour)
+	inst := domain.VPSInstance{UserID: user.ID, AutomationInstanceID: "322", Name: "vm-vnc", Status: domain.VPSStatusRunning, SpecJSON: "{}"}
+	if err := env.Repo.CreateInstance(ctx, &inst); err != nil {
+		t.Fatalf("create instance: %v", err)
+	}
+	location := func() string {
+		t.Helper()
+		rec := testutil.DoJSON(t, env.Router, http.MethodGet, "/api/v1/vps/"+testutil.Itoa(inst.ID)+"/vnc", nil, token)
+		if rec.Code != http.StatusFound {
+			t.Fatalf("vps vnc: %d %s", rec.Code, rec.Body.String())
+		}
+		return rec.Header().Get("Location")
+	}
+	set := func(key, value string) {
+		t.Helper()
+		if err := env.Repo.UpsertSetting(ctx, domain.Setting{Key: key, ValueJSON: value}); err != nil {
+			t.Fatalf("set %s: %v", key, err)
+		}
+	}
+
+	if got := location(); !strings.HasPrefix(got, "/vnc/console.html?") || !strings.Contains(got, "ticket%3D") || strings.Contains(got, "panel.example") {
+		t.Fatalf("expected the bundled console page by default, got %q", got)
+	}
+	set("vps_console_page_url", "/novnc/vnc.html")
+	if got := location(); !strings.HasPrefix(got, "/novnc/vnc.html?") {
+		t.Fatalf("expected the configured console page, got %q", got)
+	}
+	set("vps_console_proxy_enabled", "false")
+	if got := location(); got != env.Automation.VNCURL {
+		t.Fatalf("expected provider redirect only when the proxy is turned off, got %q", got)
+	}
+}
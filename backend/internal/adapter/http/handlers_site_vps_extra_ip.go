@@ -0,0 +1,136 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+type vpsExtraIPPayload struct {
+	AddIPv4 int `json:"add_ipv4" binding:"min=0,max=64"`
+	AddIPv6 int `json:"add_ipv6" binding:"min=0,max=64"`
+}
+
+func (h *Handler) VPSExtraIPs(c *gin.Context) {
+	inst, ok := h.extraIPInstance(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": h.vpsExtraIPDTOs(c, inst.ID)})
+}
+
+func (h *Handler) VPSExtraIPQuote(c *gin.Context) {
+	inst, ok := h.extraIPInstance(c)
+	if !ok {
+		return
+	}
+	var payload vpsExtraIPPayload
+	if err := bindJSON(c, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
+		return
+	}
+	quote, err := h.orderSvc.QuoteExtraIPs(c, getUserID(c), inst.ID, payload.AddIPv4, payload.AddIPv6)
+	if err != nil {
+		writeExtraIPError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"quote": gin.H{
+		"vps_id":        quote.VPSID,
+		"add_ipv4":      quote.AddIPv4,
+		"add_ipv6":      quote.AddIPv6,
+		"target_ipv4":   quote.TargetIPv4,
+		"target_ipv6":   quote.TargetIPv6,
+		"monthly_delta": centsToFloat(quote.MonthlyDelta),
+		"charge_amount": centsToFloat(quote.ChargeAmount),
+	}})
+}
+
+func (h *Handler) VPSExtraIPOrder(c *gin.Context) {
+	inst, ok := h.extraIPInstance(c)
+	if !ok {
+		return
+	}
+	var payload vpsExtraIPPayload
+	if err := bindJSON(c, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
+		return
+	}
+	order, _, err := h.orderSvc.CreateExtraIPOrder(c, getUserID(c), inst.ID, payload.AddIPv4, payload.AddIPv6)
+	if err != nil {
+		writeExtraIPError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"order": toOrderDTO(order)})
+}
+
+func (h *Handler) AdminVPSExtraIPSync(c *gin.Context) {
+	if h.orderSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrOrdersDisabled.Error()})
+		return
+	}
+	var uri adminIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	items, err := h.orderSvc.SyncExtraIPs(c, getUserID(c), uri.ID)
+	if err != nil {
+		writeExtraIPError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": toVPSExtraIPDTOs(items)})
+}
+
+// vpsExtraIPDTOs is best effort so a repository hiccup does not break the
+// VPS detail page.
+func (h *Handler) vpsExtraIPDTOs(c *gin.Context, vpsID int64) []VPSExtraIPDTO {
+	if h.orderSvc == nil {
+		return []VPSExtraIPDTO{}
+	}
+	items, err := h.orderSvc.ListExtraIPs(c, vpsID)
+	if err != nil {
+		return []VPSExtraIPDTO{}
+	}
+	return toVPSExtraIPDTOs(items)
+}
+
+func (h *Handler) extraIPInstance(c *gin.Context) (domain.VPSInstance, bool) {
+	if h.orderSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrOrdersDisabled.Error()})
+		return domain.VPSInstance{}, false
+	}
+	var uri vpsIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return domain.VPSInstance{}, false
+	}
+	inst, err := h.vpsSvc.Get(c, uri.ID, getUserID(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrNotFound.Error()})
+		return domain.VPSInstance{}, false
+	}
+	if !featureAllowedByCapability(h.resolveVPSAutomationCapability(c, inst), "extra_ip", false) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "附加IP功能未启用"})
+		return domain.VPSInstance{}, false
+	}
+	return inst, true
+}
+
+func writeExtraIPError(c *gin.Context, err error) {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, appshared.ErrNotSupported), errors.Is(err, domain.ErrExtraIPNotSupported):
+		status = http.StatusNotImplemented
+	case errors.Is(err, appshared.ErrRealNameRequired), errors.Is(err, appshared.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, appshared.ErrResizeInProgress), errors.Is(err, appshared.ErrConflict), errors.Is(err, appshared.ErrVPSOperationInProgress):
+		status = http.StatusConflict
+	case errors.Is(err, domain.ErrNotFound):
+		status = http.StatusNotFound
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	appmetrics "xiaoheiplay/internal/app/metrics"
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+type vpsMetricsQuery struct {
+	From       string `form:"from"`
+	To         string `form:"to"`
+	Resolution string `form:"resolution"`
+}
+
+func (h *Handler) VPSMetrics(c *gin.Context) {
+	if h.metricsSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var uri vpsIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	inst, err := h.vpsSvc.Get(c, uri.ID, getUserID(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrNotFound.Error()})
+		return
+	}
+	h.writeVPSMetrics(c, inst.ID)
+}
+
+func (h *Handler) AdminVPSMetrics(c *gin.Context) {
+	if h.metricsSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var uri adminIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	inst, err := h.adminVPS.Get(c, uri.ID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrNotFound.Error()})
+		return
+	}
+	h.writeVPSMetrics(c, inst.ID)
+}
+
+func (h *Handler) writeVPSMetrics(c *gin.Context, vpsID int64) {
+	var query vpsMetricsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidInput.Error()})
+		return
+	}
+	var from, to time.Time
+	var err error
+	if strings.TrimSpace(query.From) != "" {
+		if from, err = parseQueryTime(query.From); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidInput.Error()})
+			return
+		}
+	}
+	if strings.TrimSpace(query.To) != "" {
+		if to, err = parseQueryTime(query.To); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidInput.Error()})
+			return
+		}
+	}
+	series, err := h.metricsSvc.Query(c, vpsID, from, to, domain.MetricResolution(strings.TrimSpace(query.Resolution)))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, appshared.ErrInvalidInput) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, toVPSMetricSeriesDTO(series))
+}
+
+func toVPSMetricSeriesDTO(series appmetrics.Series) VPSMetricSeriesDTO {
+	out := VPSMetricSeriesDTO{Resolution: string(series.Resolution), From: series.From, To: series.To, Points: make([]VPSMetricPointDTO, 0, len(series.Points))}
+	for _, p := range series.Points {
+		out.Points = append(out.Points, VPSMetricPointDTO{
+			At:             p.BucketAt,
+			CPUPercent:     p.CPUPercent,
+			MemoryPercent:  p.MemoryPercent,
+			StoragePercent: p.StoragePercent,
+			BytesIn:        p.BytesIn,
+			BytesOut:       p.BytesOut,
+			Samples:        p.Samples,
+		})
+	}
+	return out
+}
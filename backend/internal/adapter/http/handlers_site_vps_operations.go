@@ -0,0 +1,96 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+type vpsOperationURI struct {
+	ID          int64 `uri:"id" binding:"required,gt=0"`
+	OperationID int64 `uri:"operationId" binding:"required,gt=0"`
+}
+
+func (h *Handler) VPSOperations(c *gin.Context) {
+	var uri vpsIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	inst, err := h.vpsSvc.Get(c, uri.ID, getUserID(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrNotFound.Error()})
+		return
+	}
+	if h.vpsOperationSvc == nil {
+		c.JSON(http.StatusOK, gin.H{"items": []VPSOperationDTO{}, "total": 0})
+		return
+	}
+	limit, offset := paging(c)
+	items, total, err := h.vpsOperationSvc.List(c, inst.ID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": domain.ErrListError.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": toVPSOperationDTOs(items), "total": total})
+}
+
+func (h *Handler) VPSOperationDetail(c *gin.Context) {
+	var uri vpsOperationURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	inst, err := h.vpsSvc.Get(c, uri.ID, getUserID(c))
+	if err != nil || h.vpsOperationSvc == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrNotFound.Error()})
+		return
+	}
+	op, err := h.vpsOperationSvc.Get(c, inst.ID, uri.OperationID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrNotFound.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, toVPSOperationDTO(op))
+}
+
+// VPSOperationEvents streams operation updates for one instance. The stream
+// opens with the currently pending/running operations so reconnecting
+// clients do not need to replay history.
+func (h *Handler) VPSOperationEvents(c *gin.Context) {
+	var uri vpsIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	inst, err := h.vpsSvc.Get(c, uri.ID, getUserID(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrNotFound.Error()})
+		return
+	}
+	if h.vpsOperationSvc == nil || h.vpsOperationFeed == nil {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	active, err := h.vpsOperationSvc.ListActive(c, inst.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": domain.ErrListError.Error()})
+		return
+	}
+	_ = h.vpsOperationFeed.StreamVPSOperations(c, c.Writer, inst.ID, active)
+}
+
+func writeVPSOperationSubmitError(c *gin.Context, err error) {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, appshared.ErrVPSOperationInProgress):
+		status = http.StatusConflict
+	case errors.Is(err, appshared.ErrNotSupported):
+		status = http.StatusNotImplemented
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
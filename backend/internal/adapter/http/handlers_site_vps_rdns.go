@@ -0,0 +1,91 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+type vpsReverseDNSPayload struct {
+	IP  string `json:"ip" binding:"required,max=64"`
+	PTR string `json:"ptr" binding:"max=255"`
+}
+
+func (h *Handler) VPSReverseDNS(c *gin.Context) {
+	inst, ok := h.reverseDNSInstance(c)
+	if !ok {
+		return
+	}
+	items, err := h.reverseDNSSvc.List(c, inst)
+	if err != nil {
+		writeReverseDNSError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+func (h *Handler) VPSReverseDNSSet(c *gin.Context) {
+	inst, ok := h.reverseDNSInstance(c)
+	if !ok {
+		return
+	}
+	var payload vpsReverseDNSPayload
+	if err := bindJSON(c, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
+		return
+	}
+	// Each change hits the provider's DNS API and a forward lookup, so keep
+	// both the account and a single address well below provider quotas.
+	if !reverseDNSLimiter.Allow(fmt.Sprintf("rdns_set:user:%d", inst.UserID), 20, time.Hour) ||
+		!reverseDNSLimiter.Allow(fmt.Sprintf("rdns_set:vps:%d:%s", inst.ID, strings.TrimSpace(payload.IP)), 5, 10*time.Minute) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": domain.ErrTooManyRequests.Error()})
+		return
+	}
+	if err := h.reverseDNSSvc.Set(c, inst, payload.IP, payload.PTR); err != nil {
+		writeReverseDNSError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+func (h *Handler) reverseDNSInstance(c *gin.Context) (domain.VPSInstance, bool) {
+	if h.reverseDNSSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return domain.VPSInstance{}, false
+	}
+	var uri vpsIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return domain.VPSInstance{}, false
+	}
+	inst, err := h.vpsSvc.Get(c, uri.ID, getUserID(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrNotFound.Error()})
+		return domain.VPSInstance{}, false
+	}
+	if !featureAllowedByCapability(h.resolveVPSAutomationCapability(c, inst), "reverse_dns", false) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "反向解析功能未启用"})
+		return domain.VPSInstance{}, false
+	}
+	return inst, true
+}
+
+func writeReverseDNSError(c *gin.Context, err error) {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, appshared.ErrNotSupported):
+		status = http.StatusNotImplemented
+	case errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrReverseDNSUnknownIP):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrReverseDNSNotConfirmed):
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,156 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	apprescue "xiaoheiplay/internal/app/rescue"
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+type vpsMountISOPayload struct {
+	ISOID string `json:"iso_id" binding:"required,max=64"`
+}
+
+func (h *Handler) VPSRescue(c *gin.Context) {
+	inst, ok := h.rescueInstance(c)
+	if !ok {
+		return
+	}
+	status, err := h.rescueSvc.Status(c, inst)
+	if err != nil {
+		writeRescueError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toVPSRescueDTO(status, h.rescueSvc.ListISOs(c)))
+}
+
+func (h *Handler) VPSRescueEnter(c *gin.Context) {
+	inst, ok := h.rescueInstance(c)
+	if !ok {
+		return
+	}
+	if !rescueLimiter.Allow(fmt.Sprintf("rescue_enter:vps:%d", inst.ID), 5, time.Hour) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": domain.ErrTooManyRequests.Error()})
+		return
+	}
+	creds, err := h.rescueSvc.Enter(c, inst)
+	if err != nil {
+		writeRescueError(c, err)
+		return
+	}
+	// The rescue password is only ever shown in this response.
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, VPSRescueCredentialsDTO{Username: creds.Username, Password: creds.Password, ExpiresAt: creds.ExpiresAt})
+}
+
+func (h *Handler) VPSRescueExit(c *gin.Context) {
+	inst, ok := h.rescueInstance(c)
+	if !ok {
+		return
+	}
+	if err := h.rescueSvc.Exit(c, inst); err != nil {
+		writeRescueError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+func (h *Handler) VPSMountISO(c *gin.Context) {
+	inst, ok := h.rescueInstance(c)
+	if !ok {
+		return
+	}
+	var payload vpsMountISOPayload
+	if err := bindJSON(c, &payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
+		return
+	}
+	if err := h.rescueSvc.MountISO(c, inst, payload.ISOID); err != nil {
+		writeRescueError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+func (h *Handler) VPSUnmountISO(c *gin.Context) {
+	inst, ok := h.rescueInstance(c)
+	if !ok {
+		return
+	}
+	if err := h.rescueSvc.UnmountISO(c, inst); err != nil {
+		writeRescueError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+func (h *Handler) AdminVPSRescueExit(c *gin.Context) {
+	if h.rescueSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return
+	}
+	var uri adminIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return
+	}
+	if err := h.rescueSvc.AdminExit(c, getUserID(c), uri.ID); err != nil {
+		writeRescueError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+func (h *Handler) rescueInstance(c *gin.Context) (domain.VPSInstance, bool) {
+	if h.rescueSvc == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+		return domain.VPSInstance{}, false
+	}
+	var uri vpsIDURI
+	if err := c.ShouldBindUri(&uri); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidId.Error()})
+		return domain.VPSInstance{}, false
+	}
+	inst, err := h.vpsSvc.Get(c, uri.ID, getUserID(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrNotFound.Error()})
+		return domain.VPSInstance{}, false
+	}
+	if !featureAllowedByCapability(h.resolveVPSAutomationCapability(c, inst), "rescue", false) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "救援模式功能未启用"})
+		return domain.VPSInstance{}, false
+	}
+	return inst, true
+}
+
+func toVPSRescueDTO(status apprescue.Status, isos []apprescue.ISOImage) VPSRescueDTO {
+	out := VPSRescueDTO{Active: status.Active, ExpiresAt: status.ExpiresAt, ISOs: make([]VPSISOImageDTO, 0, len(isos))}
+	if status.ISO != nil {
+		out.ISO = &VPSISOMountDTO{ID: status.ISO.ISOID, Name: status.ISO.ISOName, MountedAt: status.ISO.MountedAt}
+	}
+	for _, item := range isos {
+		out.ISOs = append(out.ISOs, VPSISOImageDTO{ID: item.ID, Name: item.Name})
+	}
+	return out
+}
+
+func writeRescueError(c *gin.Context, err error) {
+	status := http.StatusBadRequest
+	switch {
+	case errors.Is(err, appshared.ErrNotSupported):
+		status = http.StatusNotImplemented
+	case errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrISONotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, domain.ErrForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, domain.ErrRescueActive), errors.Is(err, domain.ErrRescueNotActive), errors.Is(err, domain.ErrConflict):
+		status = http.StatusConflict
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
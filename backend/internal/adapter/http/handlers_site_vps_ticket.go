@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 	appshared "xiaoheiplay/internal/app/shared"
+	appvpsoperation "xiaoheiplay/internal/app/vpsoperation"
 	"xiaoheiplay/internal/domain"
 )
 
@@ -73,7 +74,10 @@ func (h *Handler) VPSDetail(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrNotFound.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, h.toVPSInstanceDTOWithLifecycle(c, inst))
+	dto := h.toVPSInstanceDTOWithLifecycle(c, inst)
+	dto.ExtraIPs = h.vpsExtraIPDTOs(c, inst.ID)
+	dto.Quotas = toVPSActionQuotaDTOs(h.vpsSvc.Quotas(c, inst))
+	c.JSON(http.StatusOK, dto)
 }
 
 func (h *Handler) VPSRefresh(c *gin.Context) {
@@ -170,6 +174,14 @@ func (h *Handler) VPSVNC(c *gin.Context) {
 	if h.denyIfFeatureDisabled(c, inst, "vnc", "VNC") {
 		return
 	}
+	if h.consoleSvc != nil && h.consoleSvc.Enabled(c) {
+		ticket, ok := h.issueConsoleTicket(c, inst)
+		if !ok {
+			return
+		}
+		c.Redirect(http.StatusFound, h.consoleSvc.PageURL(c, ticket.Token))
+		return
+	}
 	url, err := h.vpsSvc.VNCURL(c, inst)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -189,7 +201,7 @@ func (h *Handler) VPSStart(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrNotFound.Error()})
 		return
 	}
-	if err := h.vpsSvc.Start(c, inst); err != nil {
+	if err := h.vpsSvc.Start(activityContext(c), inst); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -207,7 +219,7 @@ func (h *Handler) VPSShutdown(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrNotFound.Error()})
 		return
 	}
-	if err := h.vpsSvc.Shutdown(c, inst); err != nil {
+	if err := h.vpsSvc.Shutdown(activityContext(c), inst); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -225,7 +237,7 @@ func (h *Handler) VPSReboot(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrNotFound.Error()})
 		return
 	}
-	if err := h.vpsSvc.Reboot(c, inst); err != nil {
+	if err := h.vpsSvc.Reboot(activityContext(c), inst); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -310,11 +322,39 @@ func (h *Handler) VPSResetOS(c *gin.Context) {
 			matchedSystemID = img.ID
 		}
 	}
-	if err := h.vpsSvc.ResetOS(c, inst, templateID, strings.TrimSpace(password)); err != nil {
+	guest, ok := h.resetOSGuestInit(c, inst, payload)
+	if !ok {
+		return
+	}
+	// The queued reinstall is checked again when it runs; checking here
+	// rejects it before the operation is recorded.
+	if err := h.vpsSvc.CheckResetOSQuota(c, inst); err != nil {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return
+	}
+	if h.vpsOperationSvc != nil {
+		op, err := h.vpsOperationSvc.SubmitResetOS(activityContext(c), inst, appvpsoperation.ResetOSInput{
+			TemplateID: templateID,
+			SystemID:   matchedSystemID,
+			Password:   strings.TrimSpace(password),
+			Init:       guest,
+		})
+		if err != nil {
+			writeVPSOperationSubmitError(c, err)
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{"ok": true, "operation": toVPSOperationDTO(op)})
+		return
+	}
+	if err := h.vpsSvc.ResetOS(activityContext(c), inst, templateID, strings.TrimSpace(password), guest); err != nil {
 		if err == appshared.ErrInvalidInput {
 			c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidInput.Error()})
 			return
 		}
+		if errors.Is(err, domain.ErrVPSActionQuotaExceeded) || errors.Is(err, domain.ErrVPSActionCooldown) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -324,6 +364,51 @@ func (h *Handler) VPSResetOS(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"ok": true})
 }
 
+// resetOSGuestInit reads the optional ssh_key_ids, user_data, startup_script
+// and key_only fields of a reinstall request. It writes the error response and
+// returns false when the request cannot be honored.
+func (h *Handler) resetOSGuestInit(c *gin.Context, inst domain.VPSInstance, payload map[string]any) (appshared.AutomationGuestInit, bool) {
+	var guest appshared.AutomationGuestInit
+	guest.UserData, _ = payload["user_data"].(string)
+	guest.StartupScript, _ = payload["startup_script"].(string)
+	guest.DisablePassword, _ = payload["key_only"].(bool)
+	var keyIDs []int64
+	if raw, ok := payload["ssh_key_ids"].([]any); ok {
+		for _, item := range raw {
+			id, ok := item.(float64)
+			if !ok || id <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidInput.Error()})
+				return guest, false
+			}
+			keyIDs = append(keyIDs, int64(id))
+		}
+	}
+	if len(keyIDs) == 0 && guest.Empty() {
+		return guest, true
+	}
+	if !featureAllowedByCapability(h.resolveVPSAutomationCapability(c, inst), "cloud_init", false) {
+		c.JSON(http.StatusForbidden, gin.H{"error": domain.ErrGuestInitNotSupported.Error()})
+		return guest, false
+	}
+	if len(keyIDs) > 0 {
+		if h.sshKeySvc == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrNotSupported.Error()})
+			return guest, false
+		}
+		keys, err := h.sshKeySvc.PublicKeys(c, inst.UserID, keyIDs)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return guest, false
+		}
+		guest.SSHKeys = keys
+	}
+	if err := guest.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return guest, false
+	}
+	return guest, true
+}
+
 func (h *Handler) VPSResetOSPassword(c *gin.Context) {
 	var uri vpsIDURI
 	if err := c.ShouldBindUri(&uri); err != nil {
@@ -345,11 +430,15 @@ func (h *Handler) VPSResetOSPassword(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
 		return
 	}
-	if err := h.vpsSvc.ResetOSPassword(c, inst, strings.TrimSpace(payload.Password)); err != nil {
+	if err := h.vpsSvc.ResetOSPassword(activityContext(c), inst, strings.TrimSpace(payload.Password)); err != nil {
 		if err == appshared.ErrInvalidInput {
 			c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidInput.Error()})
 			return
 		}
+		if errors.Is(err, domain.ErrVPSActionQuotaExceeded) || errors.Is(err, domain.ErrVPSActionCooldown) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -383,10 +472,12 @@ func (h *Handler) VPSSnapshots(c *gin.Context) {
 		}
 		c.JSON(http.StatusOK, gin.H{"data": items})
 	case http.MethodPost:
-		if err := h.vpsSvc.CreateSnapshot(c, inst); err != nil {
+		if err := h.vpsSvc.CreateSnapshot(activityContext(c), inst); err != nil {
 			status := http.StatusBadRequest
 			if errors.Is(err, appshared.ErrNotSupported) {
 				status = http.StatusNotImplemented
+			} else if errors.Is(err, domain.ErrVPSActionQuotaExceeded) {
+				status = http.StatusTooManyRequests
 			}
 			c.JSON(status, gin.H{"error": err.Error()})
 			return
@@ -411,7 +502,7 @@ func (h *Handler) VPSSnapshotDelete(c *gin.Context) {
 	if h.denyIfFeatureDisabled(c, inst, "snapshot", "快照") {
 		return
 	}
-	if err := h.vpsSvc.DeleteSnapshot(c, inst, uri.SnapshotID); err != nil {
+	if err := h.vpsSvc.DeleteSnapshot(activityContext(c), inst, uri.SnapshotID); err != nil {
 		status := http.StatusBadRequest
 		if errors.Is(err, appshared.ErrNotSupported) {
 			status = http.StatusNotImplemented
@@ -436,7 +527,16 @@ func (h *Handler) VPSSnapshotRestore(c *gin.Context) {
 	if h.denyIfFeatureDisabled(c, inst, "snapshot", "快照") {
 		return
 	}
-	if err := h.vpsSvc.RestoreSnapshot(c, inst, uri.SnapshotID); err != nil {
+	if h.vpsOperationSvc != nil {
+		op, err := h.vpsOperationSvc.SubmitRestoreSnapshot(activityContext(c), inst, uri.SnapshotID)
+		if err != nil {
+			writeVPSOperationSubmitError(c, err)
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{"ok": true, "operation": toVPSOperationDTO(op)})
+		return
+	}
+	if err := h.vpsSvc.RestoreSnapshot(activityContext(c), inst, uri.SnapshotID); err != nil {
 		status := http.StatusBadRequest
 		if errors.Is(err, appshared.ErrNotSupported) {
 			status = http.StatusNotImplemented
@@ -474,10 +574,12 @@ func (h *Handler) VPSBackups(c *gin.Context) {
 		}
 		c.JSON(http.StatusOK, gin.H{"data": items})
 	case http.MethodPost:
-		if err := h.vpsSvc.CreateBackup(c, inst); err != nil {
+		if err := h.vpsSvc.CreateBackup(activityContext(c), inst); err != nil {
 			status := http.StatusBadRequest
 			if errors.Is(err, appshared.ErrNotSupported) {
 				status = http.StatusNotImplemented
+			} else if errors.Is(err, domain.ErrVPSActionQuotaExceeded) {
+				status = http.StatusTooManyRequests
 			}
 			c.JSON(status, gin.H{"error": err.Error()})
 			return
@@ -502,7 +604,7 @@ func (h *Handler) VPSBackupDelete(c *gin.Context) {
 	if h.denyIfFeatureDisabled(c, inst, "backup", "备份") {
 		return
 	}
-	if err := h.vpsSvc.DeleteBackup(c, inst, uri.BackupID); err != nil {
+	if err := h.vpsSvc.DeleteBackup(activityContext(c), inst, uri.BackupID); err != nil {
 		status := http.StatusBadRequest
 		if errors.Is(err, appshared.ErrNotSupported) {
 			status = http.StatusNotImplemented
@@ -527,7 +629,16 @@ func (h *Handler) VPSBackupRestore(c *gin.Context) {
 	if h.denyIfFeatureDisabled(c, inst, "backup", "备份") {
 		return
 	}
-	if err := h.vpsSvc.RestoreBackup(c, inst, uri.BackupID); err != nil {
+	if h.vpsOperationSvc != nil {
+		op, err := h.vpsOperationSvc.SubmitRestoreBackup(activityContext(c), inst, uri.BackupID)
+		if err != nil {
+			writeVPSOperationSubmitError(c, err)
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{"ok": true, "operation": toVPSOperationDTO(op)})
+		return
+	}
+	if err := h.vpsSvc.RestoreBackup(activityContext(c), inst, uri.BackupID); err != nil {
 		status := http.StatusBadRequest
 		if errors.Is(err, appshared.ErrNotSupported) {
 			status = http.StatusNotImplemented
@@ -584,10 +695,12 @@ func (h *Handler) VPSFirewallRules(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidBody.Error()})
 			return
 		}
-		if err := h.vpsSvc.AddFirewallRule(c, inst, req); err != nil {
+		if err := h.vpsSvc.AddFirewallRule(activityContext(c), inst, req); err != nil {
 			status := http.StatusBadRequest
 			if errors.Is(err, appshared.ErrNotSupported) {
 				status = http.StatusNotImplemented
+			} else if errors.Is(err, domain.ErrVPSActionQuotaExceeded) {
+				status = http.StatusTooManyRequests
 			}
 			c.JSON(status, gin.H{"error": err.Error()})
 			return
@@ -612,7 +725,7 @@ func (h *Handler) VPSFirewallDelete(c *gin.Context) {
 	if h.denyIfFeatureDisabled(c, inst, "firewall", "防火墙") {
 		return
 	}
-	if err := h.vpsSvc.DeleteFirewallRule(c, inst, uri.RuleID); err != nil {
+	if err := h.vpsSvc.DeleteFirewallRule(activityContext(c), inst, uri.RuleID); err != nil {
 		status := http.StatusBadRequest
 		if errors.Is(err, appshared.ErrNotSupported) {
 			status = http.StatusNotImplemented
@@ -670,10 +783,12 @@ func (h *Handler) VPSPortMappings(c *gin.Context) {
 			Sport: sport,
 			Dport: dport,
 		}
-		if err := h.vpsSvc.AddPortMapping(c, inst, req); err != nil {
+		if err := h.vpsSvc.AddPortMapping(activityContext(c), inst, req); err != nil {
 			status := http.StatusBadRequest
 			if errors.Is(err, appshared.ErrNotSupported) {
 				status = http.StatusNotImplemented
+			} else if errors.Is(err, domain.ErrVPSActionQuotaExceeded) {
+				status = http.StatusTooManyRequests
 			}
 			c.JSON(status, gin.H{"error": err.Error()})
 			return
@@ -752,7 +867,7 @@ func (h *Handler) VPSPortMappingDelete(c *gin.Context) {
 	if h.denyIfFeatureDisabled(c, inst, "port_mapping", "端口映射") {
 		return
 	}
-	if err := h.vpsSvc.DeletePortMapping(c, inst, uri.MappingID); err != nil {
+	if err := h.vpsSvc.DeletePortMapping(activityContext(c), inst, uri.MappingID); err != nil {
 		status := http.StatusBadRequest
 		if errors.Is(err, appshared.ErrNotSupported) {
 			status = http.StatusNotImplemented
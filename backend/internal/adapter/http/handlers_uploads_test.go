@@ -2,6 +2,7 @@ package http_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"image"
 	"image/color"
 	"image/png"
@@ -10,6 +11,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 	"xiaoheiplay/internal/testutil"
@@ -61,6 +63,22 @@ func TestHandlers_AdminUploads(t *testing.T) {
 	if rec.Code != http.StatusOK {
 		t.Fatalf("upload create: %d", rec.Code)
 	}
+	var created struct {
+		URL string `json:"url"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil || !strings.HasPrefix(created.URL, "/uploads/") {
+		t.Fatalf("unexpected upload response: %s", rec.Body.String())
+	}
+	rec = httptest.NewRecorder()
+	env.Router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, created.URL, nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/png" {
+		t.Fatalf("serve upload: %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	rec = httptest.NewRecorder()
+	env.Router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/uploads/missing.png", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("missing upload: %d", rec.Code)
+	}
 
 	rec = testutil.DoJSON(t, env.Router, http.MethodGet, "/admin/api/v1/uploads", nil, token)
 	if rec.Code != http.StatusOK {
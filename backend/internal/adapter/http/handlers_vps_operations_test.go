@@ -0,0 +1,97 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"xiaoheiplay/internal/domain"
+	"xiaoheiplay/internal/testutil"
+	"xiaoheiplay/internal/testutilhttp"
+)
+
+func TestHandlers_VPSOperations(t *testing.T) {
+	env := testutilhttp.NewTestEnv(t, false)
+	user := testutil.CreateUser(t, env.Repo, "opsuser", "opsuser@example.com", "pass")
+	other := testutil.CreateUser(t, env.Repo, "opsother", "opsother@example.com", "pass")
+	token := testutil.IssueJWT(t, env.JWTSecret, user.ID, "user", time.Hour)
+	otherToken := testutil.IssueJWT(t, env.JWTSecret, other.ID, "user", time.Hour)
+
+	inst := domain.VPSInstance{
+		UserID:               user.ID,
+		AutomationInstanceID: "321",
+		Name:                 "vm-ops",
+		Status:               domain.VPSStatusRunning,
+		SpecJSON:             "{}",
+		ExpireAt:             ptrTime(time.Now().Add(24 * time.Hour)),
+	}
+	if err := env.Repo.CreateInstance(context.Background(), &inst); err != nil {
+		t.Fatalf("create instance: %v", err)
+	}
+	base := "/api/v1/vps/" + testutil.Itoa(inst.ID)
+	busy := domain.VPSOperation{VPSID: inst.ID, UserID: user.ID, Action: domain.VPSOperationElasticUpdate, Status: domain.VPSOperationStatusRunning, ParamsJSON: "{}"}
+	if err := env.Repo.CreateVPSOperation(context.Background(), &busy); err != nil {
+		t.Fatalf("create running operation: %v", err)
+	}
+	rec := testutil.DoJSON(t, env.Router, http.MethodPost, base+"/backups/6/restore", nil, token)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected conflict while operation active, got %d", rec.Code)
+	}
+	busy.Status = domain.VPSOperationStatusSucceeded
+	busy.Progress = 100
+	if err := env.Repo.UpdateVPSOperation(context.Background(), busy); err != nil {
+		t.Fatalf("finish running operation: %v", err)
+	}
+
+	rec = testutil.DoJSON(t, env.Router, http.MethodPost, base+"/snapshots/5/restore", nil, token)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("snapshot restore: %d %s", rec.Code, rec.Body.String())
+	}
+	var submitted struct {
+		Operation struct {
+			ID     int64  `json:"id"`
+			Action string `json:"action"`
+		} `json:"operation"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &submitted); err != nil {
+		t.Fatalf("decode submit: %v", err)
+	}
+	if submitted.Operation.ID == 0 || submitted.Operation.Action != string(domain.VPSOperationRestoreSnapshot) {
+		t.Fatalf("unexpected operation: %s", rec.Body.String())
+	}
+
+	op := waitVPSOperation(t, env, submitted.Operation.ID)
+	if op.Status != domain.VPSOperationStatusSucceeded {
+		t.Fatalf("expected snapshot restore succeeded, got %s (%s)", op.Status, op.ErrorMessage)
+	}
+
+	rec = testutil.DoJSON(t, env.Router, http.MethodGet, base+"/operations", nil, token)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("list operations: %d", rec.Code)
+	}
+	var list struct {
+		Items []struct {
+			ID       int64  `json:"id"`
+			Status   string `json:"status"`
+			Progress int    `json:"progress"`
+		} `json:"items"`
+		Total int `json:"total"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if list.Total != 2 || len(list.Items) != 2 || list.Items[0].ID != op.ID || list.Items[0].Progress != 100 {
+		t.Fatalf("unexpected operations list: %s", rec.Body.String())
+	}
+
+	rec = testutil.DoJSON(t, env.Router, http.MethodGet, base+"/operations/"+testutil.Itoa(op.ID), nil, token)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("operation detail: %d", rec.Code)
+	}
+	rec = testutil.DoJSON(t, env.Router, http.MethodGet, base+"/operations/"+testutil.Itoa(op.ID), nil, otherToken)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected not found for other user, got %d", rec.Code)
+	}
+}
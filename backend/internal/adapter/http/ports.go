@@ -9,6 +9,7 @@ import (
 	apporder "xiaoheiplay/internal/app/order"
 	appreport "xiaoheiplay/internal/app/report"
 	appshared "xiaoheiplay/internal/app/shared"
+	appvpsoperation "xiaoheiplay/internal/app/vpsoperation"
 	"xiaoheiplay/internal/domain"
 )
 
@@ -21,6 +22,19 @@ type EventBroker interface {
 	Stream(ctx context.Context, w http.ResponseWriter, orderID int64, lastSeq int64) error
 }
 
+type VPSOperationService interface {
+	Get(ctx context.Context, vpsID, operationID int64) (domain.VPSOperation, error)
+	List(ctx context.Context, vpsID int64, limit, offset int) ([]domain.VPSOperation, int, error)
+	ListActive(ctx context.Context, vpsID int64) ([]domain.VPSOperation, error)
+	SubmitResetOS(ctx context.Context, inst domain.VPSInstance, input appvpsoperation.ResetOSInput) (domain.VPSOperation, error)
+	SubmitRestoreSnapshot(ctx context.Context, inst domain.VPSInstance, snapshotID int64) (domain.VPSOperation, error)
+	SubmitRestoreBackup(ctx context.Context, inst domain.VPSInstance, backupID int64) (domain.VPSOperation, error)
+}
+
+type VPSOperationStreamer interface {
+	StreamVPSOperations(ctx context.Context, w http.ResponseWriter, vpsID int64, initial []domain.VPSOperation) error
+}
+
 type SettingsService interface {
 	Get(ctx context.Context, key string) (domain.Setting, error)
 	List(ctx context.Context) ([]domain.Setting, error)
@@ -47,7 +61,8 @@ type StatusService interface {
 }
 
 type UploadService interface {
-	Create(ctx context.Context, item *domain.Upload) error
+	Store(ctx context.Context, item *domain.Upload, body []byte) error
+	Open(ctx context.Context, key string) (appshared.UploadObject, error)
 	List(ctx context.Context, limit, offset int) ([]domain.Upload, int, error)
 }
 
@@ -77,6 +92,7 @@ type OrderService interface {
 	RejectOrder(ctx context.Context, adminID int64, orderID int64, reason string) error
 	MarkPaid(ctx context.Context, adminID int64, orderID int64, input appshared.PaymentInput) (domain.OrderPayment, error)
 	RetryProvision(orderID int64) error
+	ProvisionAttempts(ctx context.Context, filter appshared.ProvisionAttemptFilter) ([]domain.ProvisionAttempt, int, error)
 	CreateEmergencyRenewOrder(ctx context.Context, userID int64, vpsID int64) (domain.Order, error)
 	CancelOrder(ctx context.Context, userID int64, orderID int64) error
 	ListOrders(ctx context.Context, filter appshared.OrderFilter, limit, offset int) ([]domain.Order, int, error)
@@ -90,6 +106,10 @@ type OrderService interface {
 	SubmitPayment(ctx context.Context, userID int64, orderID int64, input appshared.PaymentInput, idemKey string) (domain.OrderPayment, error)
 	CreateRenewOrder(ctx context.Context, userID int64, vpsID int64, renewDays int, durationMonths int) (domain.Order, error)
 	CreateResizeOrder(ctx context.Context, userID int64, vpsID int64, spec *appshared.CartSpec, targetPackageID int64, resetAddons bool, scheduledAt *time.Time) (domain.Order, appshared.ResizeQuote, error)
+	ListExtraIPs(ctx context.Context, vpsID int64) ([]domain.VPSExtraIP, error)
+	QuoteExtraIPs(ctx context.Context, userID, vpsID int64, ipv4, ipv6 int) (appshared.ExtraIPQuote, error)
+	CreateExtraIPOrder(ctx context.Context, userID, vpsID int64, ipv4, ipv6 int) (domain.Order, appshared.ExtraIPQuote, error)
+	SyncExtraIPs(ctx context.Context, adminID, vpsID int64) ([]domain.VPSExtraIP, error)
 	QuoteResizeOrder(ctx context.Context, userID int64, vpsID int64, spec *appshared.CartSpec, targetPackageID int64, resetAddons bool) (appshared.ResizeQuote, appshared.CartSpec, error)
 	CreateRefundOrder(ctx context.Context, userID int64, vpsID int64, reason string) (domain.Order, int64, error)
 }
@@ -105,7 +125,7 @@ type VPSService interface {
 	Start(ctx context.Context, inst domain.VPSInstance) error
 	Shutdown(ctx context.Context, inst domain.VPSInstance) error
 	Reboot(ctx context.Context, inst domain.VPSInstance) error
-	ResetOS(ctx context.Context, inst domain.VPSInstance, templateID int64, password string) error
+	ResetOS(ctx context.Context, inst domain.VPSInstance, templateID int64, password string, guest appshared.AutomationGuestInit) error
 	UpdateLocalSystemID(ctx context.Context, inst domain.VPSInstance, systemID int64) error
 	ResetOSPassword(ctx context.Context, inst domain.VPSInstance, password string) error
 	ListSnapshots(ctx context.Context, inst domain.VPSInstance) ([]appshared.AutomationSnapshot, error)
@@ -123,6 +143,9 @@ type VPSService interface {
 	AddPortMapping(ctx context.Context, inst domain.VPSInstance, req appshared.AutomationPortMappingCreate) error
 	FindPortCandidates(ctx context.Context, inst domain.VPSInstance, keywords string) ([]int64, error)
 	DeletePortMapping(ctx context.Context, inst domain.VPSInstance, mappingID int64) error
+	Quotas(ctx context.Context, inst domain.VPSInstance) []appshared.VPSQuotaStatus
+	CheckResetOSQuota(ctx context.Context, inst domain.VPSInstance) error
+	Activities(ctx context.Context, filter appshared.VPSActivityFilter) ([]domain.VPSActivity, int, error)
 }
 
 type ReportService interface {
@@ -163,6 +186,12 @@ type PluginAdminService interface {
 	UpdateConfigInstance(ctx context.Context, category, pluginID, instanceID, configJSON string) error
 	CreateInstance(ctx context.Context, category, pluginID, instanceID string) (domain.PluginInstallation, error)
 	DeletePluginFiles(ctx context.Context, category, pluginID string) error
+	ListLogs(ctx context.Context, filter appshared.PluginLogFilter) ([]domain.PluginLog, int, error)
+	ListVersions(ctx context.Context, category, pluginID string) ([]domain.PluginVersion, error)
+	Upgrade(ctx context.Context, category, pluginID, filename string, r io.Reader, trustUnofficial bool) (domain.PluginVersion, error)
+	Rollback(ctx context.Context, category, pluginID, version string) (domain.PluginVersion, error)
+	RepositoryPlugins(ctx context.Context) ([]appshared.PluginRepositoryItem, error)
+	InstallFromRepository(ctx context.Context, category, pluginID, version string) (domain.PluginVersion, error)
 }
 
 type UserTierService interface {
@@ -32,7 +32,8 @@ func NewServer(handler *Handler, middleware *Middleware) *Server {
 	r := gin.Default()
 	r.Use(securityHeadersMiddleware())
 	r.Use(corsMiddleware())
-	r.Static("/uploads", "./uploads")
+	r.GET("/uploads/*filepath", handler.ServeUpload)
+	r.HEAD("/uploads/*filepath", handler.ServeUpload)
 
 	// Installer gate: before installation completes, redirect site traffic to /install and
 	// block non-install API calls to avoid confusing errors.
@@ -27,6 +27,10 @@ func (adminRoutesRegistrar) Register(r *gin.Engine, handler *Handler, middleware
 		admin.GET("/scheduled-tasks", handler.AdminScheduledTasks)
 		admin.PATCH("/scheduled-tasks/:key", handler.AdminScheduledTaskUpdate)
 		admin.GET("/scheduled-tasks/:key/runs", handler.AdminScheduledTaskRuns)
+		admin.GET("/plugin-jobs", handler.AdminPluginJobs)
+		admin.PATCH("/plugin-jobs/:key", handler.AdminPluginJobUpdate)
+		admin.POST("/plugin-jobs/:key/run", handler.AdminPluginJobRun)
+		admin.GET("/plugin-jobs/:key/runs", handler.AdminScheduledTaskRuns)
 		admin.GET("/payments/providers", handler.AdminPaymentProviders)
 		admin.PATCH("/payments/providers/:key", handler.AdminPaymentProviderUpdate)
 		admin.POST("/plugins/payment/upload", handler.AdminPaymentPluginUpload)
@@ -35,6 +39,8 @@ func (adminRoutesRegistrar) Register(r *gin.Engine, handler *Handler, middleware
 		admin.GET("/plugins", handler.AdminPluginsList)
 		admin.GET("/plugins/discover", handler.AdminPluginsDiscover)
 		admin.POST("/plugins/install", handler.AdminPluginInstall)
+		admin.GET("/plugins/repository", handler.AdminPluginRepository)
+		admin.POST("/plugins/repository/install", handler.AdminPluginRepositoryInstall)
 		admin.POST("/plugins/:category/:plugin_id/import", handler.AdminPluginImportFromDisk)
 		admin.POST("/plugins/:category/:plugin_id/instances", handler.AdminPluginInstanceCreate)
 		admin.POST("/plugins/:category/:plugin_id/:instance_id/enable", handler.AdminPluginInstanceEnable)
@@ -43,7 +49,13 @@ func (adminRoutesRegistrar) Register(r *gin.Engine, handler *Handler, middleware
 		admin.GET("/plugins/:category/:plugin_id/:instance_id/config/schema", handler.AdminPluginInstanceConfigSchema)
 		admin.GET("/plugins/:category/:plugin_id/:instance_id/config", handler.AdminPluginInstanceConfigGet)
 		admin.PUT("/plugins/:category/:plugin_id/:instance_id/config", handler.AdminPluginInstanceConfigUpdate)
+		admin.GET("/plugins/:category/:plugin_id/:instance_id/logs", handler.AdminPluginInstanceLogs)
+		admin.GET("/plugins/:category/:plugin_id/:instance_id/hook-deliveries", handler.AdminPluginHookDeliveries)
+		admin.POST("/plugins/:category/:plugin_id/:instance_id/hook-deliveries/:id/retry", handler.AdminPluginHookDeliveryRetry)
 		admin.DELETE("/plugins/:category/:plugin_id/files", handler.AdminPluginDeleteFiles)
+		admin.GET("/plugins/:category/:plugin_id/versions", handler.AdminPluginVersions)
+		admin.POST("/plugins/:category/:plugin_id/upgrade", handler.AdminPluginUpgrade)
+		admin.POST("/plugins/:category/:plugin_id/versions/:version/rollback", handler.AdminPluginRollback)
 		admin.POST("/plugins/:category/:plugin_id/enable", handler.AdminPluginEnable)
 		admin.POST("/plugins/:category/:plugin_id/disable", handler.AdminPluginDisable)
 		admin.DELETE("/plugins/:category/:plugin_id", handler.AdminPluginUninstall)
@@ -60,11 +72,30 @@ func (adminRoutesRegistrar) Register(r *gin.Engine, handler *Handler, middleware
 		admin.DELETE("/orders/:id", handler.AdminOrderDelete)
 		admin.POST("/orders/:id/mark-paid", handler.AdminOrderMarkPaid)
 		admin.POST("/orders/:id/retry", handler.AdminOrderRetry)
+		admin.GET("/provision-attempts", handler.AdminProvisionAttempts)
 		admin.GET("/tickets", handler.AdminTickets)
 		admin.GET("/tickets/:id", handler.AdminTicketDetail)
 		admin.PATCH("/tickets/:id", handler.AdminTicketUpdate)
 		admin.POST("/tickets/:id/messages", handler.AdminTicketMessageCreate)
 		admin.DELETE("/tickets/:id", handler.AdminTicketDelete)
+		admin.GET("/abuse-cases", handler.AdminAbuseCases)
+		admin.POST("/abuse-cases", handler.AdminAbuseCaseCreate)
+		admin.GET("/abuse-cases/:id", handler.AdminAbuseCaseDetail)
+		admin.POST("/abuse-cases/:id/ack", handler.AdminAbuseCaseAck)
+		admin.POST("/abuse-cases/:id/evidence", handler.AdminAbuseCaseEvidence)
+		admin.POST("/abuse-cases/:id/links", handler.AdminAbuseCaseLink)
+		admin.POST("/abuse-cases/:id/notes", handler.AdminAbuseCaseNote)
+		admin.POST("/abuse-cases/:id/warn", handler.AdminAbuseCaseWarn)
+		admin.POST("/abuse-cases/:id/lock", handler.AdminAbuseCaseLock)
+		admin.POST("/abuse-cases/:id/terminate", handler.AdminAbuseCaseTerminate)
+		admin.POST("/abuse-cases/:id/resolve", handler.AdminAbuseCaseResolve)
+		admin.POST("/abuse-cases/:id/dismiss", handler.AdminAbuseCaseDismiss)
+		admin.GET("/console-sessions", handler.AdminConsoleSessions)
+		admin.POST("/console-sessions/:id/terminate", handler.AdminConsoleSessionTerminate)
+		admin.GET("/firewall-templates", handler.AdminFirewallTemplates)
+		admin.POST("/firewall-templates", handler.AdminFirewallTemplateCreate)
+		admin.PUT("/firewall-templates/:id", handler.AdminFirewallTemplateUpdate)
+		admin.DELETE("/firewall-templates/:id", handler.AdminFirewallTemplateDelete)
 		admin.GET("/vps", handler.AdminVPSList)
 		admin.POST("/vps", handler.AdminVPSCreate)
 		admin.GET("/vps/:id", handler.AdminVPSDetail)
@@ -77,6 +108,16 @@ func (adminRoutesRegistrar) Register(r *gin.Engine, handler *Handler, middleware
 		admin.POST("/vps/:id/emergency-renew", handler.AdminVPSEmergencyRenew)
 		admin.POST("/vps/:id/refresh", handler.AdminVPSRefresh)
 		admin.PATCH("/vps/:id/expire-at", handler.AdminVPSUpdateExpire)
+		admin.GET("/vps/:id/rdns", handler.AdminVPSReverseDNS)
+		admin.PUT("/vps/:id/rdns", handler.AdminVPSReverseDNSSet)
+		admin.GET("/vps/:id/rdns/changes", handler.AdminVPSReverseDNSChanges)
+		admin.POST("/vps/:id/ips/sync", handler.AdminVPSExtraIPSync)
+		admin.POST("/vps/:id/rescue/exit", handler.AdminVPSRescueExit)
+		admin.GET("/vps/:id/metrics", handler.AdminVPSMetrics)
+		admin.GET("/vps/:id/activities", handler.AdminVPSActivities)
+		admin.GET("/vps/:id/activities/export", handler.AdminVPSActivitiesExport)
+		admin.GET("/vps/:id/firewall-templates", handler.AdminVPSFirewallTemplates)
+		admin.POST("/vps/:id/firewall-templates/reconcile", handler.AdminVPSFirewallReconcile)
 		admin.GET("/audit-logs", handler.AdminAuditLogs)
 		admin.GET("/regions", handler.AdminRegions)
 		admin.POST("/regions", handler.AdminRegionCreate)
@@ -91,6 +132,8 @@ func (adminRoutesRegistrar) Register(r *gin.Engine, handler *Handler, middleware
 		admin.PATCH("/lines/:id", handler.AdminLineUpdate)
 		admin.POST("/lines/:id/system-images", handler.AdminLineSystemImages)
 		admin.DELETE("/plan-groups/:id", handler.AdminPlanGroupDelete)
+		admin.GET("/plan-groups/:id/provision-fallback", handler.AdminPlanGroupProvisionFallbackGet)
+		admin.PATCH("/plan-groups/:id/provision-fallback", handler.AdminPlanGroupProvisionFallbackUpdate)
 		admin.DELETE("/lines/:id", handler.AdminLineDelete)
 		admin.POST("/plan-groups/bulk-delete", handler.AdminPlanGroupBulkDelete)
 		admin.POST("/lines/bulk-delete", handler.AdminPlanGroupBulkDelete)
@@ -98,6 +141,8 @@ func (adminRoutesRegistrar) Register(r *gin.Engine, handler *Handler, middleware
 		admin.POST("/packages", handler.AdminPackageCreate)
 		admin.PATCH("/packages/:id", handler.AdminPackageUpdate)
 		admin.DELETE("/packages/:id", handler.AdminPackageDelete)
+		admin.GET("/packages/:id/quotas", handler.AdminPackageQuotasGet)
+		admin.PATCH("/packages/:id/quotas", handler.AdminPackageQuotasUpdate)
 		admin.POST("/packages/bulk-delete", handler.AdminPackageBulkDelete)
 		admin.GET("/billing-cycles", handler.AdminBillingCycles)
 		admin.POST("/billing-cycles", handler.AdminBillingCycleCreate)
@@ -120,6 +165,12 @@ func (adminRoutesRegistrar) Register(r *gin.Engine, handler *Handler, middleware
 		admin.GET("/goods-types/:id/automation-options", handler.AdminGoodsTypeAutomationOptions)
 		admin.GET("/goods-types/:id/capabilities", handler.AdminGoodsTypeCapabilitiesGet)
 		admin.PATCH("/goods-types/:id/capabilities", handler.AdminGoodsTypeCapabilitiesUpdate)
+		admin.GET("/goods-types/:id/quotas", handler.AdminGoodsTypeQuotasGet)
+		admin.PATCH("/goods-types/:id/quotas", handler.AdminGoodsTypeQuotasUpdate)
+		admin.GET("/goods-types/:id/backends", handler.AdminGoodsTypeBackends)
+		admin.POST("/goods-types/:id/backends", handler.AdminGoodsTypeBackendCreate)
+		admin.PATCH("/goods-types/:id/backends/:backend_id", handler.AdminGoodsTypeBackendUpdate)
+		admin.DELETE("/goods-types/:id/backends/:backend_id", handler.AdminGoodsTypeBackendDelete)
 		admin.PUT("/goods-types/:id", handler.AdminGoodsTypeUpdate)
 		admin.DELETE("/goods-types/:id", handler.AdminGoodsTypeDelete)
 		admin.GET("/integrations/robot", handler.AdminRobotConfig)
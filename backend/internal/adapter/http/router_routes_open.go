@@ -27,7 +27,10 @@ func (openRoutesRegistrar) Register(r *gin.Engine, handler *Handler, middleware
 		openSigned.POST("/vps/:id/refresh", handler.VPSRefresh)
 		openSigned.GET("/vps/:id/panel", handler.VPSPanel)
 		openSigned.GET("/vps/:id/monitor", handler.VPSMonitor)
+		openSigned.GET("/vps/:id/activities", handler.VPSActivities)
+		openSigned.GET("/vps/:id/activities/export", handler.VPSActivitiesExport)
 		openSigned.GET("/vps/:id/vnc", handler.VPSVNC)
+		openSigned.POST("/vps/:id/console", handler.VPSConsoleTicket)
 		openSigned.POST("/vps/:id/start", handler.VPSStart)
 		openSigned.POST("/vps/:id/shutdown", handler.VPSShutdown)
 		openSigned.POST("/vps/:id/reboot", handler.VPSReboot)
@@ -26,6 +26,7 @@ func (publicRoutesRegistrar) Register(r *gin.Engine, handler *Handler, _ *Middle
 		public.POST("/auth/refresh", handler.Refresh)
 		public.Any("/payments/notify/:provider", handler.PaymentNotify)
 		public.Any("/wallet/payments/notify/:provider", handler.WalletPaymentNotify)
+		public.POST("/abuse/reports", handler.AbuseReport)
 		public.GET("/site/settings", handler.SiteSettings)
 		public.GET("/cms/blocks", handler.CMSBlocksPublic)
 		public.GET("/cms/posts", handler.CMSPostsPublic)
@@ -33,5 +34,6 @@ func (publicRoutesRegistrar) Register(r *gin.Engine, handler *Handler, _ *Middle
 		public.POST("/probe/enroll", handler.ProbeEnroll)
 		public.POST("/probe/auth/token", handler.ProbeAuthToken)
 		public.GET("/probe/ws", handler.ProbeWS)
+		public.GET("/console/ws", handler.ConsoleWS)
 	}
 }
@@ -22,6 +22,13 @@ func (userRoutesRegistrar) Register(r *gin.Engine, handler *Handler, middleware
 		user.GET("/me/security/2fa/status", handler.MeTwoFAStatus)
 		user.POST("/me/security/2fa/setup", handler.MeTwoFASetup)
 		user.POST("/me/security/2fa/confirm", handler.MeTwoFAConfirm)
+		user.GET("/me/ssh-keys", handler.SSHKeys)
+		user.POST("/me/ssh-keys", handler.SSHKeyCreate)
+		user.DELETE("/me/ssh-keys/:id", handler.SSHKeyDelete)
+		user.GET("/me/firewall-templates", handler.FirewallTemplates)
+		user.POST("/me/firewall-templates", handler.FirewallTemplateCreate)
+		user.PUT("/me/firewall-templates/:id", handler.FirewallTemplateUpdate)
+		user.DELETE("/me/firewall-templates/:id", handler.FirewallTemplateDelete)
 		user.GET("/realname/status", handler.RealNameStatus)
 		user.POST("/realname/verify", handler.RealNameVerify)
 		user.GET("/dashboard", handler.Dashboard)
@@ -69,7 +76,9 @@ func (userRoutesRegistrar) Register(r *gin.Engine, handler *Handler, middleware
 		user.POST("/vps/:id/refresh", handler.VPSRefresh)
 		user.GET("/vps/:id/panel", handler.VPSPanel)
 		user.GET("/vps/:id/monitor", handler.VPSMonitor)
+		user.GET("/vps/:id/metrics", handler.VPSMetrics)
 		user.GET("/vps/:id/vnc", handler.VPSVNC)
+		user.POST("/vps/:id/console", handler.VPSConsoleTicket)
 		user.POST("/vps/:id/start", handler.VPSStart)
 		user.POST("/vps/:id/shutdown", handler.VPSShutdown)
 		user.POST("/vps/:id/reboot", handler.VPSReboot)
@@ -86,6 +95,19 @@ func (userRoutesRegistrar) Register(r *gin.Engine, handler *Handler, middleware
 		user.GET("/vps/:id/firewall", handler.VPSFirewallRules)
 		user.POST("/vps/:id/firewall", handler.VPSFirewallRules)
 		user.DELETE("/vps/:id/firewall/:ruleId", handler.VPSFirewallDelete)
+		user.GET("/vps/:id/firewall-templates", handler.VPSFirewallTemplates)
+		user.POST("/vps/:id/firewall-templates", handler.VPSFirewallTemplateAttach)
+		user.DELETE("/vps/:id/firewall-templates/:templateId", handler.VPSFirewallTemplateDetach)
+		user.GET("/vps/:id/rdns", handler.VPSReverseDNS)
+		user.PUT("/vps/:id/rdns", handler.VPSReverseDNSSet)
+		user.GET("/vps/:id/ips", handler.VPSExtraIPs)
+		user.POST("/vps/:id/ips/quote", handler.VPSExtraIPQuote)
+		user.POST("/vps/:id/ips", handler.VPSExtraIPOrder)
+		user.GET("/vps/:id/rescue", handler.VPSRescue)
+		user.POST("/vps/:id/rescue", handler.VPSRescueEnter)
+		user.POST("/vps/:id/rescue/exit", handler.VPSRescueExit)
+		user.POST("/vps/:id/iso", handler.VPSMountISO)
+		user.DELETE("/vps/:id/iso", handler.VPSUnmountISO)
 		user.GET("/vps/:id/ports", handler.VPSPortMappings)
 		user.POST("/vps/:id/ports", handler.VPSPortMappings)
 		user.GET("/vps/:id/ports/candidates", handler.VPSPortCandidates)
@@ -95,5 +117,10 @@ func (userRoutesRegistrar) Register(r *gin.Engine, handler *Handler, middleware
 		user.POST("/vps/:id/resize", handler.VPSResizeOrder)
 		user.POST("/vps/:id/emergency-renew", handler.VPSEmergencyRenew)
 		user.POST("/vps/:id/refund", handler.VPSRefund)
+		user.GET("/vps/:id/operations", handler.VPSOperations)
+		user.GET("/vps/:id/activities", handler.VPSActivities)
+		user.GET("/vps/:id/activities/export", handler.VPSActivitiesExport)
+		user.GET("/vps/:id/operations/stream", handler.VPSOperationEvents)
+		user.GET("/vps/:id/operations/:operationId", handler.VPSOperationDetail)
 	}
 }
@@ -11,6 +11,7 @@ import (
 	"xiaoheiplay/internal/adapter/plugins/core"
 	appports "xiaoheiplay/internal/app/ports"
 	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
 	pluginv1 "xiaoheiplay/plugin/v1"
 )
 
@@ -49,6 +50,24 @@ func (c *PluginInstanceClient) client(ctx context.Context) (pluginv1.AutomationS
 	return cli, err
 }
 
+// requireGuestInit refuses SSH key and user data requests unless the plugin
+// declares the cloud_init feature, so an older plugin never silently drops them.
+func (c *PluginInstanceClient) requireGuestInit(ctx context.Context) error {
+	if c.mgr == nil {
+		return fmt.Errorf("plugin manager missing")
+	}
+	_, manifest, err := c.mgr.GetAutomationClient(ctx, c.pluginID, c.instanceID)
+	if err != nil {
+		return err
+	}
+	for _, f := range manifest.GetAutomation().GetFeatures() {
+		if f == pluginv1.AutomationFeature_AUTOMATION_FEATURE_CLOUD_INIT {
+			return nil
+		}
+	}
+	return domain.ErrGuestInitNotSupported
+}
+
 func (c *PluginInstanceClient) call(ctx context.Context, action string, req proto.Message, fn func(context.Context, pluginv1.AutomationServiceClient) (proto.Message, error)) (proto.Message, error) {
 	cli, err := c.client(ctx)
 	if err != nil {
@@ -117,6 +136,15 @@ func (c *PluginInstanceClient) CreateHost(ctx context.Context, req appshared.Aut
 		DiskGb:        int32(req.DiskGB),
 		BandwidthMbps: int32(req.Bandwidth),
 	}
+	if !req.Init.Empty() {
+		if err := c.requireGuestInit(ctx); err != nil {
+			return appshared.AutomationCreateHostResult{}, err
+		}
+		pb.SshKeys = req.Init.SSHKeys
+		pb.UserData = req.Init.UserData
+		pb.StartupScript = req.Init.StartupScript
+		pb.DisablePasswordLogin = req.Init.DisablePassword
+	}
 	respAny, err := c.call(ctx, "automation.CreateInstance", pb, func(cctx context.Context, cli pluginv1.AutomationServiceClient) (proto.Message, error) {
 		return cli.CreateInstance(cctx, pb)
 	})
@@ -142,6 +170,16 @@ func (c *PluginInstanceClient) GetHostInfo(ctx context.Context, hostID int64) (a
 		t := time.Unix(inst.GetExpireAtUnix(), 0)
 		expire = &t
 	}
+	var task *appshared.AutomationTaskProgress
+	if inst.TaskProgress != nil {
+		percent := int(inst.GetTaskProgress())
+		if percent < 0 {
+			percent = 0
+		} else if percent > 100 {
+			percent = 100
+		}
+		task = &appshared.AutomationTaskProgress{Percent: percent, Message: inst.GetTaskMessage()}
+	}
 	return appshared.AutomationHostInfo{
 		HostID:        inst.GetId(),
 		HostName:      inst.GetName(),
@@ -155,6 +193,7 @@ func (c *PluginInstanceClient) GetHostInfo(ctx context.Context, hostID int64) (a
 		OSPassword:    inst.GetOsPassword(),
 		RemoteIP:      inst.GetRemoteIp(),
 		ExpireAt:      expire,
+		Task:          task,
 	}, nil
 }
 
@@ -87,8 +87,17 @@ func (c *PluginInstanceClient) RebootHost(ctx context.Context, hostID int64) err
 	return ensureOpOK(respAny)
 }
 
-func (c *PluginInstanceClient) ResetOS(ctx context.Context, hostID int64, templateID int64, password string) error {
+func (c *PluginInstanceClient) ResetOS(ctx context.Context, hostID int64, templateID int64, password string, guest appshared.AutomationGuestInit) error {
 	pb := &pluginv1.RebuildRequest{InstanceId: hostID, ImageId: templateID, Password: password}
+	if !guest.Empty() {
+		if err := c.requireGuestInit(ctx); err != nil {
+			return err
+		}
+		pb.SshKeys = guest.SSHKeys
+		pb.UserData = guest.UserData
+		pb.StartupScript = guest.StartupScript
+		pb.DisablePasswordLogin = guest.DisablePassword
+	}
 	respAny, err := c.call(ctx, "automation.Rebuild", pb, func(cctx context.Context, cli pluginv1.AutomationServiceClient) (proto.Message, error) {
 		return cli.Rebuild(cctx, pb)
 	})
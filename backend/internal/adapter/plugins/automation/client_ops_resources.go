@@ -177,6 +177,101 @@ func (c *PluginInstanceClient) DeleteFirewallRule(ctx context.Context, hostID in
 	return ensureOpOK(respAny)
 }
 
+func (c *PluginInstanceClient) ListReverseDNS(ctx context.Context, hostID int64) ([]appshared.AutomationReverseDNSRecord, error) {
+	pb := &pluginv1.ListReverseDNSRequest{InstanceId: hostID}
+	respAny, err := c.call(ctx, "automation.ListReverseDNS", pb, func(cctx context.Context, cli pluginv1.AutomationServiceClient) (proto.Message, error) {
+		return cli.ListReverseDNS(cctx, pb)
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := respAny.(*pluginv1.ListReverseDNSResponse)
+	out := make([]appshared.AutomationReverseDNSRecord, 0, len(resp.GetItems()))
+	for _, it := range resp.GetItems() {
+		out = append(out, appshared.AutomationReverseDNSRecord{IP: it.GetIp(), PTR: it.GetPtr()})
+	}
+	return out, nil
+}
+
+func (c *PluginInstanceClient) SetReverseDNS(ctx context.Context, hostID int64, ip, ptr string) error {
+	pb := &pluginv1.SetReverseDNSRequest{InstanceId: hostID, Ip: ip, Ptr: ptr}
+	respAny, err := c.call(ctx, "automation.SetReverseDNS", pb, func(cctx context.Context, cli pluginv1.AutomationServiceClient) (proto.Message, error) {
+		return cli.SetReverseDNS(cctx, pb)
+	})
+	if err != nil {
+		return err
+	}
+	return ensureOpOK(respAny)
+}
+
+func (c *PluginInstanceClient) AssignIP(ctx context.Context, hostID int64, family string) (string, error) {
+	pb := &pluginv1.AssignIPRequest{InstanceId: hostID, Family: family}
+	respAny, err := c.call(ctx, "automation.AssignIP", pb, func(cctx context.Context, cli pluginv1.AutomationServiceClient) (proto.Message, error) {
+		return cli.AssignIP(cctx, pb)
+	})
+	if err != nil {
+		return "", err
+	}
+	resp := respAny.(*pluginv1.AssignIPResponse)
+	return resp.GetIp(), nil
+}
+
+func (c *PluginInstanceClient) ReleaseIP(ctx context.Context, hostID int64, ip string) error {
+	pb := &pluginv1.ReleaseIPRequest{InstanceId: hostID, Ip: ip}
+	respAny, err := c.call(ctx, "automation.ReleaseIP", pb, func(cctx context.Context, cli pluginv1.AutomationServiceClient) (proto.Message, error) {
+		return cli.ReleaseIP(cctx, pb)
+	})
+	if err != nil {
+		return err
+	}
+	return ensureOpOK(respAny)
+}
+
+func (c *PluginInstanceClient) EnterRescue(ctx context.Context, hostID int64, password string) (appshared.AutomationRescueCredentials, error) {
+	pb := &pluginv1.EnterRescueRequest{InstanceId: hostID, Password: password}
+	respAny, err := c.call(ctx, "automation.EnterRescue", pb, func(cctx context.Context, cli pluginv1.AutomationServiceClient) (proto.Message, error) {
+		return cli.EnterRescue(cctx, pb)
+	})
+	if err != nil {
+		return appshared.AutomationRescueCredentials{}, err
+	}
+	resp := respAny.(*pluginv1.EnterRescueResponse)
+	return appshared.AutomationRescueCredentials{Username: resp.GetUsername(), Password: resp.GetPassword()}, nil
+}
+
+func (c *PluginInstanceClient) ExitRescue(ctx context.Context, hostID int64) error {
+	pb := &pluginv1.ExitRescueRequest{InstanceId: hostID}
+	respAny, err := c.call(ctx, "automation.ExitRescue", pb, func(cctx context.Context, cli pluginv1.AutomationServiceClient) (proto.Message, error) {
+		return cli.ExitRescue(cctx, pb)
+	})
+	if err != nil {
+		return err
+	}
+	return ensureOpOK(respAny)
+}
+
+func (c *PluginInstanceClient) MountISO(ctx context.Context, hostID int64, iso string) error {
+	pb := &pluginv1.MountISORequest{InstanceId: hostID, Iso: iso}
+	respAny, err := c.call(ctx, "automation.MountISO", pb, func(cctx context.Context, cli pluginv1.AutomationServiceClient) (proto.Message, error) {
+		return cli.MountISO(cctx, pb)
+	})
+	if err != nil {
+		return err
+	}
+	return ensureOpOK(respAny)
+}
+
+func (c *PluginInstanceClient) UnmountISO(ctx context.Context, hostID int64) error {
+	pb := &pluginv1.UnmountISORequest{InstanceId: hostID}
+	respAny, err := c.call(ctx, "automation.UnmountISO", pb, func(cctx context.Context, cli pluginv1.AutomationServiceClient) (proto.Message, error) {
+		return cli.UnmountISO(cctx, pb)
+	})
+	if err != nil {
+		return err
+	}
+	return ensureOpOK(respAny)
+}
+
 func (c *PluginInstanceClient) ListPortMappings(ctx context.Context, hostID int64) ([]appshared.AutomationPortMapping, error) {
 	pb := &pluginv1.ListPortMappingsRequest{InstanceId: hostID}
 	respAny, err := c.call(ctx, "automation.ListPortMappings", pb, func(cctx context.Context, cli pluginv1.AutomationServiceClient) (proto.Message, error) {
@@ -48,10 +48,31 @@ func (r *Resolver) ClientForGoodsType(ctx context.Context, goodsTypeID int64) (a
 	if cat != "automation" || strings.TrimSpace(gt.AutomationPluginID) == "" || strings.TrimSpace(gt.AutomationInstanceID) == "" {
 		return nil, fmt.Errorf("invalid automation binding")
 	}
+	return r.ClientForBackend(ctx, gt.AutomationPluginID, gt.AutomationInstanceID)
+}
+
+// ClientForBackend returns a client bound to one automation plugin instance,
+// independent of any goods type.
+func (r *Resolver) ClientForBackend(ctx context.Context, pluginID, instanceID string) (appshared.AutomationClient, error) {
+	_ = ctx
+	pluginID = strings.TrimSpace(pluginID)
+	instanceID = strings.TrimSpace(instanceID)
+	if pluginID == "" || instanceID == "" {
+		return nil, fmt.Errorf("invalid automation binding")
+	}
 	if r.pluginMgr == nil {
 		return nil, fmt.Errorf("plugin manager missing")
 	}
-	return NewPluginInstanceClient(r.pluginMgr, gt.AutomationPluginID, gt.AutomationInstanceID, r.settings, r.autoLogs), nil
+	return NewPluginInstanceClient(r.pluginMgr, pluginID, instanceID, r.settings, r.autoLogs), nil
+}
+
+// ClientForInstance routes to the backend the instance was placed on. Hosts
+// created before backend pools existed fall back to their goods type binding.
+func (r *Resolver) ClientForInstance(ctx context.Context, inst domain.VPSInstance) (appshared.AutomationClient, error) {
+	if strings.TrimSpace(inst.BackendPluginID) != "" && strings.TrimSpace(inst.BackendInstanceID) != "" {
+		return r.ClientForBackend(ctx, inst.BackendPluginID, inst.BackendInstanceID)
+	}
+	return r.ClientForGoodsType(ctx, inst.GoodsTypeID)
 }
 
 func DefaultGoodsType(items []domain.GoodsType) *domain.GoodsType {
@@ -157,6 +157,76 @@ func (m *AdminManager) DeletePluginFiles(ctx context.Context, category, pluginID
 	return m.inner.DeletePluginFiles(ctx, category, pluginID)
 }
 
+func (m *AdminManager) ListVersions(ctx context.Context, category, pluginID string) ([]domain.PluginVersion, error) {
+	if m == nil || m.inner == nil {
+		return nil, fmt.Errorf("plugins disabled")
+	}
+	return m.inner.ListVersions(ctx, category, pluginID)
+}
+
+func (m *AdminManager) Upgrade(ctx context.Context, category, pluginID, filename string, r io.Reader, trustUnofficial bool) (domain.PluginVersion, error) {
+	if m == nil || m.inner == nil {
+		return domain.PluginVersion{}, fmt.Errorf("plugins disabled")
+	}
+	v, err := m.inner.Upgrade(ctx, category, pluginID, filename, r, trustUnofficial)
+	return v, mapConfigValidationError(err)
+}
+
+func (m *AdminManager) Rollback(ctx context.Context, category, pluginID, version string) (domain.PluginVersion, error) {
+	if m == nil || m.inner == nil {
+		return domain.PluginVersion{}, fmt.Errorf("plugins disabled")
+	}
+	v, err := m.inner.Rollback(ctx, category, pluginID, version)
+	return v, mapConfigValidationError(err)
+}
+
+func (m *AdminManager) RepositoryPlugins(ctx context.Context) ([]appshared.PluginRepositoryItem, error) {
+	if m == nil || m.inner == nil {
+		return nil, fmt.Errorf("plugins disabled")
+	}
+	items, err := m.inner.RepositoryPlugins(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]appshared.PluginRepositoryItem, 0, len(items))
+	for _, it := range items {
+		out = append(out, appshared.PluginRepositoryItem{
+			Category:         it.Category,
+			PluginID:         it.PluginID,
+			Name:             it.Name,
+			Description:      it.Description,
+			Version:          it.Version,
+			Source:           it.Source,
+			Installed:        it.Installed,
+			InstalledVersion: it.InstalledVersion,
+			UpdateAvailable:  it.UpdateAvailable,
+			Verified:         it.Verified,
+			VerifyError:      it.VerifyError,
+		})
+	}
+	return out, nil
+}
+
+func (m *AdminManager) InstallFromRepository(ctx context.Context, category, pluginID, version string) (domain.PluginVersion, error) {
+	if m == nil || m.inner == nil {
+		return domain.PluginVersion{}, fmt.Errorf("plugins disabled")
+	}
+	v, err := m.inner.InstallFromRepository(ctx, category, pluginID, version)
+	return v, mapConfigValidationError(err)
+}
+
+func mapConfigValidationError(err error) error {
+	if cfgErr, ok := AsConfigValidationError(err); ok && cfgErr != nil {
+		return &appshared.ConfigValidationError{
+			Code:          cfgErr.Code,
+			Message:       cfgErr.Message,
+			MissingFields: cfgErr.MissingFields,
+			RedirectPath:  cfgErr.RedirectPath,
+		}
+	}
+	return err
+}
+
 func mapListItem(it ListItem) appshared.PluginListItem {
 	out := appshared.PluginListItem{
 		Category:        it.Category,
@@ -172,12 +242,16 @@ func mapListItem(it ListItem) appshared.PluginListItem {
 		LastHealthAt:    it.LastHealthAt,
 		HealthStatus:    it.HealthStatus,
 		HealthMessage:   it.HealthMessage,
+		Runtime:         it.Runtime,
 		Capabilities: appshared.PluginManifest{
 			PluginID:    it.Capabilities.PluginID,
 			Name:        it.Capabilities.Name,
 			Version:     it.Capabilities.Version,
 			Description: it.Capabilities.Description,
 			Binaries:    it.Capabilities.Binaries,
+			Jobs:        it.Capabilities.Jobs,
+			Permissions: it.Capabilities.Permissions,
+			Hooks:       it.Capabilities.Hooks,
 			Capabilities: appshared.PluginCapabilities{
 				SMS: mapSMSCapability(it.Capabilities.Capabilities.SMS),
 				Payment: &appshared.PluginPaymentCapability{
@@ -185,6 +259,9 @@ func mapListItem(it ListItem) appshared.PluginListItem {
 				},
 				KYC:        mapKYCCapability(it.Capabilities.Capabilities.KYC),
 				Automation: mapAutomationCapability(it.Capabilities.Capabilities.Automation),
+				Notify:     mapNotifyCapability(it.Capabilities.Capabilities.Notify),
+				Storage:    mapStorageCapability(it.Capabilities.Capabilities.Storage),
+				Captcha:    mapCaptchaCapability(it.Capabilities.Capabilities.Captcha),
 			},
 		},
 		Entry: appshared.PluginEntryInfo{
@@ -221,6 +298,34 @@ func mapKYCCapability(in *struct {
 	return &appshared.PluginKYCCapability{Start: in.Start, QueryResult: in.QueryResult}
 }
 
+func mapNotifyCapability(in *struct {
+	Send     bool "json:\"send\""
+	Markdown bool "json:\"markdown,omitempty\""
+}) *appshared.PluginNotifyCapability {
+	if in == nil {
+		return nil
+	}
+	return &appshared.PluginNotifyCapability{Send: in.Send, Markdown: in.Markdown}
+}
+
+func mapStorageCapability(in *struct {
+	Presign bool "json:\"presign,omitempty\""
+}) *appshared.PluginStorageCapability {
+	if in == nil {
+		return nil
+	}
+	return &appshared.PluginStorageCapability{Presign: in.Presign}
+}
+
+func mapCaptchaCapability(in *struct {
+	Widget string "json:\"widget\""
+}) *appshared.PluginCaptchaCapability {
+	if in == nil {
+		return nil
+	}
+	return &appshared.PluginCaptchaCapability{Widget: in.Widget}
+}
+
 func mapAutomationCapability(in *struct {
 	Features           []string          "json:\"features\""
 	NotSupportedReason map[string]string "json:\"not_supported_reasons,omitempty\""
@@ -0,0 +1,103 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+const captchaCategory = "captcha"
+
+// CaptchaProvider serves human verification through plugins of the captcha
+// category.
+type CaptchaProvider struct {
+	manager *Manager
+}
+
+func NewCaptchaProvider(manager *Manager) *CaptchaProvider {
+	return &CaptchaProvider{manager: manager}
+}
+
+func (p *CaptchaProvider) Init(ctx context.Context, pluginID, instanceID, scene, remoteIP string) (appshared.CaptchaChallenge, error) {
+	client, rp, err := p.client(ctx, pluginID, instanceID)
+	if err != nil {
+		return appshared.CaptchaChallenge{}, err
+	}
+	resp, err := client.Init(ctx, &pluginv1.CaptchaInitRequest{
+		Scene:    strings.TrimSpace(scene),
+		RemoteIp: strings.TrimSpace(remoteIP),
+	})
+	if err != nil {
+		return appshared.CaptchaChallenge{}, MapRPCError(err, "captcha plugin")
+	}
+	if resp == nil || !resp.Ok {
+		errMsg := "captcha init failed"
+		if resp != nil && strings.TrimSpace(resp.Error) != "" {
+			errMsg = strings.TrimSpace(resp.Error)
+		}
+		return appshared.CaptchaChallenge{}, fmt.Errorf("%s", errMsg)
+	}
+	widget := strings.TrimSpace(resp.Widget)
+	if widget == "" && rp != nil {
+		widget = strings.TrimSpace(rp.manifest.GetCaptcha().GetWidget())
+	}
+	params := resp.Params
+	if params == nil {
+		params = map[string]string{}
+	}
+	return appshared.CaptchaChallenge{Widget: widget, Params: params}, nil
+}
+
+func (p *CaptchaProvider) Verify(ctx context.Context, pluginID, instanceID string, answer appshared.CaptchaAnswer) error {
+	client, _, err := p.client(ctx, pluginID, instanceID)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Verify(ctx, &pluginv1.CaptchaVerifyRequest{
+		Scene:    strings.TrimSpace(answer.Scene),
+		Token:    strings.TrimSpace(answer.Token),
+		Params:   answer.Params,
+		RemoteIp: strings.TrimSpace(answer.RemoteIP),
+	})
+	if err != nil {
+		return MapRPCError(err, "captcha plugin")
+	}
+	if resp == nil || !resp.Ok {
+		errMsg := "rejected"
+		if resp != nil && strings.TrimSpace(resp.Error) != "" {
+			errMsg = strings.TrimSpace(resp.Error)
+		}
+		if resp != nil && strings.TrimSpace(resp.ErrorCode) != "" {
+			errMsg += " (" + strings.TrimSpace(resp.ErrorCode) + ")"
+		}
+		return fmt.Errorf("%w: %s", domain.ErrCaptchaFailed, errMsg)
+	}
+	return nil
+}
+
+func (p *CaptchaProvider) client(ctx context.Context, pluginID, instanceID string) (pluginv1.CaptchaServiceClient, *runningPlugin, error) {
+	if p == nil || p.manager == nil {
+		return nil, nil, fmt.Errorf("plugin manager unavailable")
+	}
+	pluginID = strings.TrimSpace(pluginID)
+	instanceID = strings.TrimSpace(instanceID)
+	if instanceID == "" {
+		instanceID = DefaultInstanceID
+	}
+	if pluginID == "" {
+		return nil, nil, fmt.Errorf("captcha plugin not configured")
+	}
+	if _, err := p.manager.EnsureRunning(ctx, captchaCategory, pluginID, instanceID); err != nil {
+		return nil, nil, err
+	}
+	client, ok := p.manager.GetCaptchaClient(captchaCategory, pluginID, instanceID)
+	if !ok || client == nil {
+		return nil, nil, fmt.Errorf("captcha plugin not running")
+	}
+	rp, _ := p.manager.runtime.GetRunning(captchaCategory, pluginID, instanceID)
+	return client, rp, nil
+}
@@ -0,0 +1,81 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+const hookDeliveryTimeout = 10 * time.Second
+
+// ListHookSubscribers returns the enabled instances whose manifest.json
+// subscribes to event.
+func (m *Manager) ListHookSubscribers(ctx context.Context, event string) ([]appshared.PluginHookSubscriber, error) {
+	if m.repo == nil {
+		return nil, fmt.Errorf("plugin repo missing")
+	}
+	installations, err := m.repo.ListPluginInstallations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []appshared.PluginHookSubscriber
+	for _, inst := range installations {
+		if !inst.Enabled {
+			continue
+		}
+		manifest, err := ReadManifest(m.PluginDir(inst.Category, inst.PluginID))
+		if err != nil || !slices.Contains(manifest.Hooks, event) {
+			continue
+		}
+		out = append(out, appshared.PluginHookSubscriber{
+			Category:   inst.Category,
+			PluginID:   inst.PluginID,
+			InstanceID: inst.InstanceID,
+		})
+	}
+	return out, nil
+}
+
+// DeliverHook starts the instance if needed and calls HookService.OnEvent.
+func (m *Manager) DeliverHook(ctx context.Context, delivery domain.PluginHookDelivery) error {
+	if _, err := m.EnsureRunning(ctx, delivery.Category, delivery.PluginID, delivery.InstanceID); err != nil {
+		return err
+	}
+	rp, ok := m.runtime.GetRunning(delivery.Category, delivery.PluginID, delivery.InstanceID)
+	if !ok || rp == nil || rp.hook == nil {
+		return appshared.ErrNotSupported
+	}
+	cctx, cancel := context.WithTimeout(ctx, hookDeliveryTimeout)
+	defer cancel()
+	resp, err := rp.hook.OnEvent(cctx, &pluginv1.HookEvent{
+		Id:             delivery.EventID,
+		InstanceId:     delivery.InstanceID,
+		Type:           delivery.Event,
+		OccurredAtUnix: delivery.OccurredAt.Unix(),
+		PayloadJson:    delivery.PayloadJSON,
+		Attempt:        int32(delivery.Attempts),
+	})
+	if status.Code(err) == codes.Unimplemented {
+		return appshared.ErrNotSupported
+	}
+	if err != nil {
+		return MapRPCError(err, "plugin hook")
+	}
+	if resp == nil || !resp.Ok {
+		msg := "plugin hook failed"
+		if resp != nil && strings.TrimSpace(resp.Error) != "" {
+			msg = strings.TrimSpace(resp.Error)
+		}
+		return fmt.Errorf("%s", msg)
+	}
+	return nil
+}
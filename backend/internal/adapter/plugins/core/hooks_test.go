@@ -0,0 +1,37 @@
+package plugins
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeHooksManifest(t *testing.T, hooks string) string {
+	t.Helper()
+	dir := t.TempDir()
+	raw := `{"plugin_id":"crm","name":"CRM","version":"1.0.0","hooks":` + hooks + `}`
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json"), []byte(raw), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	return dir
+}
+
+func TestReadManifestHooks(t *testing.T) {
+	m, err := ReadManifest(writeHooksManifest(t, `[" order.paid ","ticket.created"]`))
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	if len(m.Hooks) != 2 || m.Hooks[0] != "order.paid" {
+		t.Fatalf("unexpected hooks: %+v", m.Hooks)
+	}
+	for name, hooks := range map[string]string{
+		"unknown":   `["order.deleted"]`,
+		"duplicate": `["order.paid","order.paid"]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ReadManifest(writeHooksManifest(t, hooks)); err == nil {
+				t.Fatalf("expected invalid hooks to be rejected")
+			}
+		})
+	}
+}
@@ -0,0 +1,333 @@
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	appports "xiaoheiplay/internal/app/ports"
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+const (
+	pluginLogMaxMessageBytes = 4000
+	pluginLogMaxFields       = 32
+	pluginKVListMax          = 500
+)
+
+type HostOrderReader interface {
+	GetOrder(ctx context.Context, id int64) (domain.Order, error)
+	GetOrderByNo(ctx context.Context, orderNo string) (domain.Order, error)
+}
+
+type HostUserReader interface {
+	GetUserByID(ctx context.Context, id int64) (domain.User, error)
+}
+
+type HostNotifier interface {
+	NotifyUser(ctx context.Context, userID int64, typ, title, content string) error
+}
+
+// HostServices backs the HostService served to every plugin instance. A nil
+// field makes the matching RPCs return Unavailable.
+type HostServices struct {
+	KV       appports.PluginKVRepository
+	Logs     appports.PluginLogRepository
+	Orders   HostOrderReader
+	Users    HostUserReader
+	Notifier HostNotifier
+}
+
+// SetHostServices enables HostService for instances started afterwards.
+func (m *Manager) SetHostServices(services HostServices) {
+	m.host = &services
+	m.runtime.SetHostFactory(func(category, pluginID, instanceID string, manifest Manifest) pluginv1.HostServiceServer {
+		return newHostServer(services, category, pluginID, instanceID, manifest.Permissions)
+	})
+}
+
+type hostServer struct {
+	pluginv1.UnimplementedHostServiceServer
+
+	services   HostServices
+	category   string
+	pluginID   string
+	instanceID string
+	scopes     map[string]bool
+}
+
+func newHostServer(services HostServices, category, pluginID, instanceID string, permissions []string) *hostServer {
+	scopes := map[string]bool{}
+	for _, scope := range permissions {
+		scopes[strings.TrimSpace(scope)] = true
+	}
+	return &hostServer{
+		services:   services,
+		category:   category,
+		pluginID:   pluginID,
+		instanceID: instanceID,
+		scopes:     scopes,
+	}
+}
+
+func (h *hostServer) Log(ctx context.Context, req *pluginv1.LogRequest) (*pluginv1.Empty, error) {
+	level := hostLogLevel(req.GetLevel())
+	msg := truncateUTF8(strings.TrimSpace(req.GetMessage()), pluginLogMaxMessageBytes)
+	if msg == "" {
+		return nil, status.Error(codes.InvalidArgument, "message required")
+	}
+	fields := req.GetFields()
+	if len(fields) > pluginLogMaxFields {
+		return nil, status.Error(codes.InvalidArgument, "too many log fields")
+	}
+	fieldsJSON := "{}"
+	if len(fields) > 0 {
+		raw, err := json.Marshal(fields)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid log fields")
+		}
+		fieldsJSON = string(raw)
+	}
+	log.Printf("plugin %s/%s/%s [%s] %s %s", h.category, h.pluginID, h.instanceID, level, msg, fieldsJSON)
+	if h.services.Logs != nil {
+		entry := domain.PluginLog{
+			Category:   h.category,
+			PluginID:   h.pluginID,
+			InstanceID: h.instanceID,
+			Level:      level,
+			Message:    msg,
+			FieldsJSON: fieldsJSON,
+			CreatedAt:  time.Now(),
+		}
+		if err := h.services.Logs.CreatePluginLog(ctx, &entry); err != nil {
+			return nil, status.Error(codes.Internal, "log write failed")
+		}
+	}
+	return &pluginv1.Empty{}, nil
+}
+
+func (h *hostServer) KVGet(ctx context.Context, req *pluginv1.KVGetRequest) (*pluginv1.KVGetResponse, error) {
+	if h.services.KV == nil {
+		return nil, status.Error(codes.Unavailable, "kv store unavailable")
+	}
+	key, err := hostKVKey(req.GetKey())
+	if err != nil {
+		return nil, err
+	}
+	kv, err := h.services.KV.GetPluginKV(ctx, h.category, h.pluginID, h.instanceID, key)
+	if errors.Is(err, appshared.ErrNotFound) {
+		return &pluginv1.KVGetResponse{Found: false}, nil
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "kv read failed")
+	}
+	return &pluginv1.KVGetResponse{Found: true, Value: kv.Value, ExpiresAtUnix: unixOrZero(kv.ExpiresAt)}, nil
+}
+
+func (h *hostServer) KVSet(ctx context.Context, req *pluginv1.KVSetRequest) (*pluginv1.Empty, error) {
+	if h.services.KV == nil {
+		return nil, status.Error(codes.Unavailable, "kv store unavailable")
+	}
+	key, err := hostKVKey(req.GetKey())
+	if err != nil {
+		return nil, err
+	}
+	if len(req.GetValue()) > appshared.PluginKVMaxValueBytes {
+		return nil, status.Error(codes.InvalidArgument, "value too large")
+	}
+	if req.GetTtlSec() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid ttl")
+	}
+	if _, err := h.services.KV.GetPluginKV(ctx, h.category, h.pluginID, h.instanceID, key); errors.Is(err, appshared.ErrNotFound) {
+		count, err := h.services.KV.CountPluginKV(ctx, h.category, h.pluginID, h.instanceID)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "kv write failed")
+		}
+		if count >= appshared.PluginKVMaxKeys {
+			return nil, status.Error(codes.ResourceExhausted, "kv key limit reached")
+		}
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, "kv write failed")
+	}
+	kv := domain.PluginKV{
+		Category:   h.category,
+		PluginID:   h.pluginID,
+		InstanceID: h.instanceID,
+		Key:        key,
+		Value:      req.GetValue(),
+	}
+	if ttl := req.GetTtlSec(); ttl > 0 {
+		expires := time.Now().Add(time.Duration(ttl) * time.Second)
+		kv.ExpiresAt = &expires
+	}
+	if err := h.services.KV.PutPluginKV(ctx, &kv); err != nil {
+		return nil, status.Error(codes.Internal, "kv write failed")
+	}
+	return &pluginv1.Empty{}, nil
+}
+
+func (h *hostServer) KVDelete(ctx context.Context, req *pluginv1.KVDeleteRequest) (*pluginv1.Empty, error) {
+	if h.services.KV == nil {
+		return nil, status.Error(codes.Unavailable, "kv store unavailable")
+	}
+	key, err := hostKVKey(req.GetKey())
+	if err != nil {
+		return nil, err
+	}
+	if err := h.services.KV.DeletePluginKV(ctx, h.category, h.pluginID, h.instanceID, key); err != nil {
+		return nil, status.Error(codes.Internal, "kv delete failed")
+	}
+	return &pluginv1.Empty{}, nil
+}
+
+func (h *hostServer) KVList(ctx context.Context, req *pluginv1.KVListRequest) (*pluginv1.KVListResponse, error) {
+	if h.services.KV == nil {
+		return nil, status.Error(codes.Unavailable, "kv store unavailable")
+	}
+	limit := int(req.GetLimit())
+	if limit <= 0 || limit > pluginKVListMax {
+		limit = pluginKVListMax
+	}
+	items, err := h.services.KV.ListPluginKV(ctx, h.category, h.pluginID, h.instanceID, req.GetPrefix(), limit)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "kv list failed")
+	}
+	out := &pluginv1.KVListResponse{Items: make([]*pluginv1.KVEntry, 0, len(items))}
+	for _, kv := range items {
+		out.Items = append(out.Items, &pluginv1.KVEntry{Key: kv.Key, Value: kv.Value, ExpiresAtUnix: unixOrZero(kv.ExpiresAt)})
+	}
+	return out, nil
+}
+
+func (h *hostServer) GetOrder(ctx context.Context, req *pluginv1.GetOrderRequest) (*pluginv1.HostOrder, error) {
+	if err := h.require(appshared.PluginScopeOrderRead); err != nil {
+		return nil, err
+	}
+	if h.services.Orders == nil {
+		return nil, status.Error(codes.Unavailable, "orders unavailable")
+	}
+	var (
+		order domain.Order
+		err   error
+	)
+	switch {
+	case req.GetId() > 0:
+		order, err = h.services.Orders.GetOrder(ctx, req.GetId())
+	case strings.TrimSpace(req.GetOrderNo()) != "":
+		order, err = h.services.Orders.GetOrderByNo(ctx, strings.TrimSpace(req.GetOrderNo()))
+	default:
+		return nil, status.Error(codes.InvalidArgument, "id or order_no required")
+	}
+	if errors.Is(err, appshared.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "order not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "order read failed")
+	}
+	return &pluginv1.HostOrder{
+		Id:            order.ID,
+		OrderNo:       order.OrderNo,
+		UserId:        order.UserID,
+		Status:        string(order.Status),
+		TotalAmount:   order.TotalAmount,
+		Currency:      order.Currency,
+		CreatedAtUnix: order.CreatedAt.Unix(),
+	}, nil
+}
+
+func (h *hostServer) GetUser(ctx context.Context, req *pluginv1.GetUserRequest) (*pluginv1.HostUser, error) {
+	if err := h.require(appshared.PluginScopeUserRead); err != nil {
+		return nil, err
+	}
+	if h.services.Users == nil {
+		return nil, status.Error(codes.Unavailable, "users unavailable")
+	}
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "id required")
+	}
+	user, err := h.services.Users.GetUserByID(ctx, req.GetId())
+	if errors.Is(err, appshared.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "user read failed")
+	}
+	return &pluginv1.HostUser{
+		Id:       user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Status:   string(user.Status),
+	}, nil
+}
+
+func (h *hostServer) Notify(ctx context.Context, req *pluginv1.NotifyRequest) (*pluginv1.Empty, error) {
+	if err := h.require(appshared.PluginScopeNotifyUser); err != nil {
+		return nil, err
+	}
+	if h.services.Notifier == nil {
+		return nil, status.Error(codes.Unavailable, "notifications unavailable")
+	}
+	title := strings.TrimSpace(req.GetTitle())
+	if req.GetUserId() <= 0 || title == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id and title required")
+	}
+	if err := h.services.Notifier.NotifyUser(ctx, req.GetUserId(), "plugin:"+h.pluginID, title, strings.TrimSpace(req.GetContent())); err != nil {
+		return nil, status.Error(codes.Internal, "notify failed")
+	}
+	return &pluginv1.Empty{}, nil
+}
+
+func (h *hostServer) require(scope string) error {
+	if !h.scopes[scope] {
+		return status.Error(codes.PermissionDenied, "manifest permission "+scope+" required")
+	}
+	return nil
+}
+
+func hostKVKey(raw string) (string, error) {
+	key := strings.TrimSpace(raw)
+	if key == "" || len(key) > appshared.PluginKVMaxKeyLen || !utf8.ValidString(key) {
+		return "", status.Error(codes.InvalidArgument, "invalid key")
+	}
+	return key, nil
+}
+
+func hostLogLevel(level pluginv1.LogLevel) string {
+	switch level {
+	case pluginv1.LogLevel_LOG_LEVEL_DEBUG:
+		return "debug"
+	case pluginv1.LogLevel_LOG_LEVEL_WARN:
+		return "warn"
+	case pluginv1.LogLevel_LOG_LEVEL_ERROR:
+		return "error"
+	default:
+		return "info"
+	}
+}
+
+func truncateUTF8(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	s = s[:max]
+	for len(s) > 0 && !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+func unixOrZero(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.Unix()
+}
@@ -0,0 +1,122 @@
+package plugins
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+type memPluginKV struct {
+	items map[string]domain.PluginKV
+}
+
+func (m *memPluginKV) id(category, pluginID, instanceID, key string) string {
+	return category + "/" + pluginID + "/" + instanceID + "/" + key
+}
+
+func (m *memPluginKV) GetPluginKV(_ context.Context, category, pluginID, instanceID, key string) (domain.PluginKV, error) {
+	kv, ok := m.items[m.id(category, pluginID, instanceID, key)]
+	if !ok {
+		return domain.PluginKV{}, appshared.ErrNotFound
+	}
+	return kv, nil
+}
+
+func (m *memPluginKV) PutPluginKV(_ context.Context, kv *domain.PluginKV) error {
+	m.items[m.id(kv.Category, kv.PluginID, kv.InstanceID, kv.Key)] = *kv
+	return nil
+}
+
+func (m *memPluginKV) DeletePluginKV(_ context.Context, category, pluginID, instanceID, key string) error {
+	delete(m.items, m.id(category, pluginID, instanceID, key))
+	return nil
+}
+
+func (m *memPluginKV) ListPluginKV(context.Context, string, string, string, string, int) ([]domain.PluginKV, error) {
+	return nil, nil
+}
+
+func (m *memPluginKV) CountPluginKV(_ context.Context, category, pluginID, instanceID string) (int, error) {
+	n := 0
+	for _, kv := range m.items {
+		if kv.Category == category && kv.PluginID == pluginID && kv.InstanceID == instanceID {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (m *memPluginKV) DeletePluginKVByInstance(context.Context, string, string, string) error {
+	return nil
+}
+
+type stubHostOrders struct{}
+
+func (stubHostOrders) GetOrder(context.Context, int64) (domain.Order, error) {
+	return domain.Order{ID: 7, OrderNo: "ORD-7", UserID: 3, Status: domain.OrderStatusPendingPayment}, nil
+}
+
+func (stubHostOrders) GetOrderByNo(context.Context, string) (domain.Order, error) {
+	return domain.Order{}, appshared.ErrNotFound
+}
+
+func TestHostServerScopes(t *testing.T) {
+	ctx := context.Background()
+	services := HostServices{Orders: stubHostOrders{}}
+
+	denied := newHostServer(services, "payment", "ezpay", "default", nil)
+	if _, err := denied.GetOrder(ctx, &pluginv1.GetOrderRequest{Id: 7}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected permission denied, got %v", err)
+	}
+
+	allowed := newHostServer(services, "payment", "ezpay", "default", []string{appshared.PluginScopeOrderRead})
+	order, err := allowed.GetOrder(ctx, &pluginv1.GetOrderRequest{Id: 7})
+	if err != nil || order.GetOrderNo() != "ORD-7" || order.GetUserId() != 3 {
+		t.Fatalf("get order: %+v %v", order, err)
+	}
+	if _, err := allowed.GetOrder(ctx, &pluginv1.GetOrderRequest{OrderNo: "missing"}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected not found, got %v", err)
+	}
+	if _, err := allowed.GetUser(ctx, &pluginv1.GetUserRequest{Id: 3}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected user:read to be required, got %v", err)
+	}
+}
+
+func TestHostServerKVNamespaceAndLimits(t *testing.T) {
+	ctx := context.Background()
+	store := &memPluginKV{items: map[string]domain.PluginKV{}}
+	a := newHostServer(HostServices{KV: store}, "payment", "ezpay", "a", nil)
+	b := newHostServer(HostServices{KV: store}, "payment", "ezpay", "b", nil)
+
+	if _, err := a.KVSet(ctx, &pluginv1.KVSetRequest{Key: "token", Value: []byte("x"), TtlSec: 60}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	got, err := a.KVGet(ctx, &pluginv1.KVGetRequest{Key: "token"})
+	if err != nil || !got.GetFound() || string(got.GetValue()) != "x" || got.GetExpiresAtUnix() == 0 {
+		t.Fatalf("get: %+v %v", got, err)
+	}
+	if got, err := b.KVGet(ctx, &pluginv1.KVGetRequest{Key: "token"}); err != nil || got.GetFound() {
+		t.Fatalf("expected other instance not to see the key: %+v %v", got, err)
+	}
+	big := make([]byte, appshared.PluginKVMaxValueBytes+1)
+	if _, err := a.KVSet(ctx, &pluginv1.KVSetRequest{Key: "big", Value: big}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected oversized value to be rejected, got %v", err)
+	}
+
+	for i := 1; i < appshared.PluginKVMaxKeys; i++ {
+		_ = store.PutPluginKV(ctx, &domain.PluginKV{Category: "payment", PluginID: "ezpay", InstanceID: "a", Key: "k" + strconv.Itoa(i)})
+	}
+	if _, err := a.KVSet(ctx, &pluginv1.KVSetRequest{Key: "one-more"}); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected key limit, got %v", err)
+	}
+	if _, err := a.KVSet(ctx, &pluginv1.KVSetRequest{Key: "token", Value: []byte("y")}); err != nil {
+		t.Fatalf("overwriting an existing key should not hit the limit: %v", err)
+	}
+}
@@ -30,12 +30,57 @@ func InstallPackage(baseDir string, filename string, r io.Reader, officialKeys [
 		return InstallResult{}, fmt.Errorf("missing base dir")
 	}
 
+	pkg, cleanup, err := unpackPackage(filename, r, officialKeys)
+	if err != nil {
+		return InstallResult{}, err
+	}
+	defer cleanup()
+
+	finalDir := filepath.Join(baseDir, pkg.Category, pkg.PluginID)
+	if fileExists(finalDir) {
+		return InstallResult{}, fmt.Errorf("plugin already installed")
+	}
+	if err := os.MkdirAll(filepath.Dir(finalDir), 0o755); err != nil {
+		return InstallResult{}, err
+	}
+	if err := copyDir(pkg.PluginDir, finalDir); err != nil {
+		_ = os.RemoveAll(finalDir)
+		return InstallResult{}, err
+	}
+	pkg.PluginDir = finalDir
+	return pkg, nil
+}
+
+// unpackPackage extracts a package into a temporary directory and checks its
+// manifest, entry and signature. PluginDir of the result points into the
+// temporary directory, which cleanup removes.
+func unpackPackage(filename string, r io.Reader, officialKeys []ed25519.PublicKey) (InstallResult, func(), error) {
 	tmpRoot, err := os.MkdirTemp("", "xiaoheiplay-plugin-install-*")
+	if err != nil {
+		return InstallResult{}, nil, err
+	}
+	cleanup := func() { _ = os.RemoveAll(tmpRoot) }
+	res, err := inspectPackage(tmpRoot, filename, r, officialKeys)
+	if err != nil {
+		cleanup()
+		return InstallResult{}, nil, err
+	}
+	return res, cleanup, nil
+}
+
+// InspectPackage checks a package the way InstallPackage does and returns
+// what it contains without installing it.
+func InspectPackage(filename string, r io.Reader, officialKeys []ed25519.PublicKey) (InstallResult, error) {
+	res, cleanup, err := unpackPackage(filename, r, officialKeys)
 	if err != nil {
 		return InstallResult{}, err
 	}
-	defer os.RemoveAll(tmpRoot)
+	cleanup()
+	res.PluginDir = ""
+	return res, nil
+}
 
+func inspectPackage(tmpRoot string, filename string, r io.Reader, officialKeys []ed25519.PublicKey) (InstallResult, error) {
 	ext := strings.ToLower(filename)
 	switch {
 	case strings.HasSuffix(ext, ".zip"):
@@ -81,22 +126,10 @@ func InstallPackage(baseDir string, filename string, r io.Reader, officialKeys [
 	if err != nil {
 		return InstallResult{}, err
 	}
-
-	finalDir := filepath.Join(baseDir, category, pluginID)
-	if fileExists(finalDir) {
-		return InstallResult{}, fmt.Errorf("plugin already installed")
-	}
-	if err := os.MkdirAll(filepath.Dir(finalDir), 0o755); err != nil {
-		return InstallResult{}, err
-	}
-	if err := copyDir(pluginDir, finalDir); err != nil {
-		_ = os.RemoveAll(finalDir)
-		return InstallResult{}, err
-	}
 	return InstallResult{
 		Category:        category,
 		PluginID:        pluginID,
-		PluginDir:       finalDir,
+		PluginDir:       pluginDir,
 		SignatureStatus: sigStatus,
 		Manifest:        m,
 	}, nil
@@ -0,0 +1,112 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	appshared "xiaoheiplay/internal/app/shared"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+// ListJobs returns the jobs declared by enabled plugin instances. Jobs from
+// manifest.json are merged with the ones a running instance reports through
+// GetManifest; the gRPC definition wins on a name clash.
+func (m *Manager) ListJobs(ctx context.Context) ([]appshared.PluginJob, error) {
+	if m.repo == nil {
+		return nil, fmt.Errorf("plugin repo missing")
+	}
+	installations, err := m.repo.ListPluginInstallations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []appshared.PluginJob
+	for _, inst := range installations {
+		if !inst.Enabled {
+			continue
+		}
+		manifest, err := ReadManifest(m.PluginDir(inst.Category, inst.PluginID))
+		if err != nil {
+			continue
+		}
+		defs := manifest.Jobs
+		if rp, ok := m.runtime.GetRunning(inst.Category, inst.PluginID, inst.InstanceID); ok && rp.manifest != nil {
+			defs = mergeJobDefinitions(defs, rp.manifest.GetJobs())
+		}
+		for _, def := range defs {
+			out = append(out, appshared.PluginJob{
+				Category:   inst.Category,
+				PluginID:   inst.PluginID,
+				InstanceID: inst.InstanceID,
+				Definition: def,
+			})
+		}
+	}
+	return out, nil
+}
+
+// RunJob starts the instance if needed and calls CoreService.RunJob within
+// the job timeout. The returned message is the plugin's run summary.
+func (m *Manager) RunJob(ctx context.Context, job appshared.PluginJob, scheduledAt time.Time, manual bool) (string, error) {
+	if _, err := m.EnsureRunning(ctx, job.Category, job.PluginID, job.InstanceID); err != nil {
+		return "", err
+	}
+	rp, ok := m.runtime.GetRunning(job.Category, job.PluginID, job.InstanceID)
+	if !ok || rp == nil || rp.core == nil {
+		return "", fmt.Errorf("plugin instance not running")
+	}
+	req := &pluginv1.RunJobRequest{
+		InstanceId: job.InstanceID,
+		Name:       job.Definition.Name,
+		Manual:     manual,
+	}
+	if !scheduledAt.IsZero() {
+		req.ScheduledAtUnix = scheduledAt.Unix()
+	}
+	cctx, cancel := context.WithTimeout(ctx, job.Definition.Timeout())
+	defer cancel()
+	resp, err := rp.core.RunJob(cctx, req)
+	if err != nil {
+		return "", MapRPCError(err, "plugin job")
+	}
+	if resp == nil || !resp.Ok {
+		msg := "plugin job failed"
+		if resp != nil && strings.TrimSpace(resp.Error) != "" {
+			msg = strings.TrimSpace(resp.Error)
+		}
+		return "", fmt.Errorf("%s", msg)
+	}
+	return strings.TrimSpace(resp.GetMessage()), nil
+}
+
+func mergeJobDefinitions(fromJSON []appshared.PluginJobDefinition, fromGRPC []*pluginv1.JobDefinition) []appshared.PluginJobDefinition {
+	if len(fromGRPC) == 0 {
+		return fromJSON
+	}
+	out := make([]appshared.PluginJobDefinition, 0, len(fromJSON)+len(fromGRPC))
+	index := map[string]int{}
+	for _, def := range fromJSON {
+		index[def.Name] = len(out)
+		out = append(out, def)
+	}
+	for _, job := range fromGRPC {
+		def := appshared.PluginJobDefinition{
+			Name:        strings.TrimSpace(job.GetName()),
+			Description: strings.TrimSpace(job.GetDescription()),
+			IntervalSec: int(job.GetIntervalSec()),
+			Cron:        strings.TrimSpace(job.GetCron()),
+			TimeoutSec:  int(job.GetTimeoutSec()),
+		}
+		if def.Validate() != nil {
+			continue
+		}
+		if i, ok := index[def.Name]; ok {
+			out[i] = def
+			continue
+		}
+		index[def.Name] = len(out)
+		out = append(out, def)
+	}
+	return out
+}
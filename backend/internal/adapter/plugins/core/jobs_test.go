@@ -0,0 +1,67 @@
+package plugins
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	appshared "xiaoheiplay/internal/app/shared"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+func TestMergeJobDefinitionsGRPCOverridesJSON(t *testing.T) {
+	fromJSON := []appshared.PluginJobDefinition{
+		{Name: "sync", IntervalSec: 300},
+		{Name: "report", Cron: "0 3 * * *"},
+	}
+	fromGRPC := []*pluginv1.JobDefinition{
+		{Name: "sync", IntervalSec: 600, TimeoutSec: 120},
+		{Name: "cleanup", Cron: "*/15 * * * *"},
+		{Name: "broken", IntervalSec: 5},
+	}
+	got := mergeJobDefinitions(fromJSON, fromGRPC)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 jobs, got %+v", got)
+	}
+	if got[0].Name != "sync" || got[0].IntervalSec != 600 || got[0].TimeoutSec != 120 {
+		t.Fatalf("expected grpc sync definition, got %+v", got[0])
+	}
+	if got[1].Name != "report" || got[2].Name != "cleanup" {
+		t.Fatalf("unexpected order: %+v", got)
+	}
+}
+
+func writeJobsManifest(t *testing.T, jobs string) string {
+	t.Helper()
+	dir := t.TempDir()
+	raw := `{"plugin_id":"demo","name":"Demo","version":"1.0.0","binaries":{"linux_amd64":"bin/linux_amd64/plugin"},"jobs":` + jobs + `}`
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json"), []byte(raw), 0o644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+	return dir
+}
+
+func TestReadManifestJobs(t *testing.T) {
+	m, err := ReadManifest(writeJobsManifest(t, `[{"name":"sync","interval_sec":60},{"name":"report","cron":"@daily"}]`))
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	if len(m.Jobs) != 2 || m.Jobs[1].Cron != "@daily" {
+		t.Fatalf("unexpected jobs: %+v", m.Jobs)
+	}
+}
+
+func TestReadManifestRejectsInvalidJobs(t *testing.T) {
+	cases := map[string]string{
+		"duplicate": `[{"name":"sync","interval_sec":60},{"name":"sync","interval_sec":120}]`,
+		"both":      `[{"name":"sync","interval_sec":60,"cron":"* * * * *"}]`,
+		"bad_cron":  `[{"name":"sync","cron":"61 * * * *"}]`,
+	}
+	for name, jobs := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ReadManifest(writeJobsManifest(t, jobs)); err == nil {
+				t.Fatalf("expected invalid jobs to be rejected")
+			}
+		})
+	}
+}
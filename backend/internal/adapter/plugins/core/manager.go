@@ -14,9 +14,11 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	appports "xiaoheiplay/internal/app/ports"
+	appshared "xiaoheiplay/internal/app/shared"
 	"xiaoheiplay/internal/domain"
 	"xiaoheiplay/internal/pkg/cryptox"
 
@@ -33,16 +35,26 @@ type Manager struct {
 	cipher       *cryptox.AESGCM
 	repo         appports.PluginInstallationRepository
 	runtime      *Runtime
+	host         *HostServices
+	versions     appports.PluginVersionRepository
+	repositories []string
+	runtimeSinks []RuntimeEventSink
+
+	locksMu sync.Mutex
+	locks   map[string]*sync.RWMutex
 }
 
 func NewManager(baseDir string, repo appports.PluginInstallationRepository, cipher *cryptox.AESGCM, officialKeys []ed25519.PublicKey) *Manager {
-	return &Manager{
+	m := &Manager{
 		baseDir:      strings.TrimSpace(baseDir),
 		officialKeys: officialKeys,
 		cipher:       cipher,
 		repo:         repo,
 		runtime:      NewRuntime(strings.TrimSpace(baseDir)),
 	}
+	m.runtime.SetRestarter(m.restartInstance)
+	m.runtime.SetEventHandler(m.handleRuntimeEvent)
+	return m
 }
 
 const DefaultInstanceID = "default"
@@ -85,9 +97,17 @@ func (m *Manager) EnsureRunning(ctx context.Context, category, pluginID, instanc
 	if strings.TrimSpace(cfg) == "" {
 		cfg = "{}"
 	}
+	lock := m.pluginLock(category, pluginID)
+	lock.RLock()
+	defer lock.RUnlock()
 	return m.runtime.Start(ctx, category, pluginID, instanceID, cfg)
 }
 
+// StopAll stops every running plugin instance.
+func (m *Manager) StopAll() {
+	m.runtime.StopAll()
+}
+
 func (m *Manager) GetAutomationClient(ctx context.Context, pluginID, instanceID string) (pluginv1.AutomationServiceClient, *pluginv1.Manifest, error) {
 	manifest, err := m.EnsureRunning(ctx, "automation", pluginID, instanceID)
 	if err != nil {
@@ -104,21 +124,22 @@ func (m *Manager) GetAutomationClient(ctx context.Context, pluginID, instanceID
 }
 
 type ListItem struct {
-	Category        string                       `json:"category"`
-	PluginID        string                       `json:"plugin_id"`
-	InstanceID      string                       `json:"instance_id"`
-	Name            string                       `json:"name"`
-	Version         string                       `json:"version"`
-	SignatureStatus domain.PluginSignatureStatus `json:"signature_status"`
-	Enabled         bool                         `json:"enabled"`
-	Loaded          bool                         `json:"loaded"`
-	InstalledAt     time.Time                    `json:"installed_at"`
-	UpdatedAt       time.Time                    `json:"updated_at"`
-	LastHealthAt    *time.Time                   `json:"last_health_at"`
-	HealthStatus    string                       `json:"health_status"`
-	HealthMessage   string                       `json:"health_message"`
-	Capabilities    Manifest                     `json:"manifest"`
-	Entry           EntryInfo                    `json:"entry"`
+	Category        string                         `json:"category"`
+	PluginID        string                         `json:"plugin_id"`
+	InstanceID      string                         `json:"instance_id"`
+	Name            string                         `json:"name"`
+	Version         string                         `json:"version"`
+	SignatureStatus domain.PluginSignatureStatus   `json:"signature_status"`
+	Enabled         bool                           `json:"enabled"`
+	Loaded          bool                           `json:"loaded"`
+	InstalledAt     time.Time                      `json:"installed_at"`
+	UpdatedAt       time.Time                      `json:"updated_at"`
+	LastHealthAt    *time.Time                     `json:"last_health_at"`
+	HealthStatus    string                         `json:"health_status"`
+	HealthMessage   string                         `json:"health_message"`
+	Runtime         *appshared.PluginRuntimeStatus `json:"runtime,omitempty"`
+	Capabilities    Manifest                       `json:"manifest"`
+	Entry           EntryInfo                      `json:"entry"`
 }
 
 type ConfigValidationError struct {
@@ -183,6 +204,10 @@ func (m *Manager) List(ctx context.Context) ([]ListItem, error) {
 			}
 			rp.mu.Unlock()
 		}
+		var runtimeStatus *appshared.PluginRuntimeStatus
+		if st, ok := m.runtime.Status(inst.Category, inst.PluginID, inst.InstanceID); ok {
+			runtimeStatus = &st
+		}
 		out = append(out, ListItem{
 			Category:        inst.Category,
 			PluginID:        inst.PluginID,
@@ -197,6 +222,7 @@ func (m *Manager) List(ctx context.Context) ([]ListItem, error) {
 			LastHealthAt:    lastHealthAt,
 			HealthStatus:    healthStatus,
 			HealthMessage:   healthMessage,
+			Runtime:         runtimeStatus,
 			Capabilities:    manifest,
 			Entry:           entry,
 		})
@@ -224,6 +250,7 @@ func (m *Manager) Install(ctx context.Context, filename string, r io.Reader) (do
 		_ = os.RemoveAll(res.PluginDir)
 		return domain.PluginInstallation{}, err
 	}
+	m.recordInstalledVersion(ctx, res)
 	return m.repo.GetPluginInstallation(ctx, res.Category, res.PluginID, DefaultInstanceID)
 }
 
@@ -334,6 +361,9 @@ func (m *Manager) EnableInstance(ctx context.Context, category, pluginID, instan
 	if m.repo == nil {
 		return fmt.Errorf("plugin repo missing")
 	}
+	lock := m.pluginLock(category, pluginID)
+	lock.RLock()
+	defer lock.RUnlock()
 	inst, err := m.repo.GetPluginInstallation(ctx, category, pluginID, instanceID)
 	if err != nil {
 		return err
@@ -562,6 +592,9 @@ func (m *Manager) DeleteInstance(ctx context.Context, category, pluginID, instan
 	if err := m.repo.DeletePluginInstallation(ctx, category, pluginID, instanceID); err != nil {
 		return err
 	}
+	if m.host != nil && m.host.KV != nil {
+		_ = m.host.KV.DeletePluginKVByInstance(ctx, category, pluginID, instanceID)
+	}
 	// Delete physical files only when no instances remain.
 	remain, err := m.repo.ListPluginInstallations(ctx)
 	if err != nil {
@@ -572,7 +605,7 @@ func (m *Manager) DeleteInstance(ctx context.Context, category, pluginID, instan
 			return nil
 		}
 	}
-	_ = os.RemoveAll(filepath.Join(m.baseDir, category, pluginID))
+	m.removePluginFiles(ctx, category, pluginID)
 	return nil
 }
 
@@ -594,7 +627,7 @@ func (m *Manager) DeletePluginFiles(ctx context.Context, category, pluginID stri
 			return fmt.Errorf("cannot delete plugin files: instances still exist")
 		}
 	}
-	_ = os.RemoveAll(filepath.Join(m.baseDir, category, pluginID))
+	m.removePluginFiles(ctx, category, pluginID)
 	return nil
 }
 
@@ -754,6 +787,36 @@ func (m *Manager) GetKYCClient(category, pluginID, instanceID string) (pluginv1.
 	return nil, false
 }
 
+func (m *Manager) GetNotifyClient(category, pluginID, instanceID string) (pluginv1.NotifyServiceClient, bool) {
+	if strings.TrimSpace(instanceID) == "" {
+		instanceID = DefaultInstanceID
+	}
+	if rp, ok := m.runtime.GetRunning(category, pluginID, instanceID); ok && rp.notify != nil {
+		return rp.notify, true
+	}
+	return nil, false
+}
+
+func (m *Manager) GetStorageClient(category, pluginID, instanceID string) (pluginv1.StorageServiceClient, bool) {
+	if strings.TrimSpace(instanceID) == "" {
+		instanceID = DefaultInstanceID
+	}
+	if rp, ok := m.runtime.GetRunning(category, pluginID, instanceID); ok && rp.storage != nil {
+		return rp.storage, true
+	}
+	return nil, false
+}
+
+func (m *Manager) GetCaptchaClient(category, pluginID, instanceID string) (pluginv1.CaptchaServiceClient, bool) {
+	if strings.TrimSpace(instanceID) == "" {
+		instanceID = DefaultInstanceID
+	}
+	if rp, ok := m.runtime.GetRunning(category, pluginID, instanceID); ok && rp.captcha != nil {
+		return rp.captcha, true
+	}
+	return nil, false
+}
+
 func (m *Manager) decryptConfig(cipherText string) (string, error) {
 	if strings.TrimSpace(cipherText) == "" {
 		return "", nil
@@ -799,7 +862,11 @@ func newInstanceID(category, pluginID string) string {
 }
 
 func (m *Manager) dialCore(ctx context.Context, category, pluginID string) (*plugin.Client, pluginv1.CoreServiceClient, *pluginv1.Manifest, error) {
-	pluginDir := filepath.Join(m.baseDir, category, pluginID)
+	return m.dialCoreAt(ctx, filepath.Join(m.baseDir, category, pluginID))
+}
+
+// dialCoreAt starts a short-lived process from pluginDir for one-off core calls.
+func (m *Manager) dialCoreAt(ctx context.Context, pluginDir string) (*plugin.Client, pluginv1.CoreServiceClient, *pluginv1.Manifest, error) {
 	manifestJSON, err := ReadManifest(pluginDir)
 	if err != nil {
 		return nil, nil, nil, err
@@ -853,7 +920,11 @@ func (m *Manager) dialCore(ctx context.Context, category, pluginID string) (*plu
 }
 
 func (m *Manager) validateConfig(ctx context.Context, category, pluginID string, configJSON string) error {
-	client, core, _, err := m.dialCore(ctx, category, pluginID)
+	return m.validateConfigAt(ctx, filepath.Join(m.baseDir, category, pluginID), category, configJSON)
+}
+
+func (m *Manager) validateConfigAt(ctx context.Context, pluginDir, category string, configJSON string) error {
+	client, core, _, err := m.dialCoreAt(ctx, pluginDir)
 	if err != nil {
 		return err
 	}
@@ -862,7 +933,7 @@ func (m *Manager) validateConfig(ctx context.Context, category, pluginID string,
 	schemaResp, schemaErr := core.GetConfigSchema(sctx, &pluginv1.Empty{})
 	scancel()
 	if schemaErr == nil && schemaResp != nil {
-		missing := missingRequiredConfigFields(schemaResp.GetJsonSchema(), configJSON)
+		missing := MissingRequiredConfigFields(schemaResp.GetJsonSchema(), configJSON)
 		if len(missing) > 0 {
 			return &ConfigValidationError{
 				Code:          "missing_required_config",
@@ -898,7 +969,9 @@ func (m *Manager) validateConfig(ctx context.Context, category, pluginID string,
 	return nil
 }
 
-func missingRequiredConfigFields(schemaJSON, configJSON string) []string {
+// MissingRequiredConfigFields lists the required schema fields configJSON
+// leaves empty, as dotted paths. Config saves are rejected when it is non-empty.
+func MissingRequiredConfigFields(schemaJSON, configJSON string) []string {
 	var schemaAny any
 	if err := json.Unmarshal([]byte(strings.TrimSpace(schemaJSON)), &schemaAny); err != nil {
 		return nil
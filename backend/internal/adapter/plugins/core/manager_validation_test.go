@@ -22,13 +22,13 @@ func TestMissingRequiredConfigFields(t *testing.T) {
 		"required":["base_url","api_key"]
 	}`
 
-	got := missingRequiredConfigFields(schema, `{}`)
+	got := MissingRequiredConfigFields(schema, `{}`)
 	want := []string{"api_key", "base_url"}
 	if !reflect.DeepEqual(got, want) {
 		t.Fatalf("missing fields mismatch: got=%v want=%v", got, want)
 	}
 
-	got = missingRequiredConfigFields(schema, `{"base_url":"https://example.com","api_key":""}`)
+	got = MissingRequiredConfigFields(schema, `{"base_url":"https://example.com","api_key":""}`)
 	want = []string{"api_key"}
 	if !reflect.DeepEqual(got, want) {
 		t.Fatalf("missing fields mismatch after partial config: got=%v want=%v", got, want)
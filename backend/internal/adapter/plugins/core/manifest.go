@@ -6,14 +6,22 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	appshared "xiaoheiplay/internal/app/shared"
 )
 
 type Manifest struct {
-	PluginID     string            `json:"plugin_id"`
-	Name         string            `json:"name"`
-	Version      string            `json:"version"`
-	Description  string            `json:"description,omitempty"`
-	Binaries     map[string]string `json:"binaries,omitempty"`
+	PluginID    string            `json:"plugin_id"`
+	Name        string            `json:"name"`
+	Version     string            `json:"version"`
+	Description string            `json:"description,omitempty"`
+	Binaries    map[string]string `json:"binaries,omitempty"`
+	// Jobs are run by the host on schedule through CoreService.RunJob.
+	Jobs []appshared.PluginJobDefinition `json:"jobs,omitempty"`
+	// Permissions are the HostService scopes the plugin may use.
+	Permissions []string `json:"permissions,omitempty"`
+	// Hooks are the host events delivered through HookService.OnEvent.
+	Hooks        []string `json:"hooks,omitempty"`
 	Capabilities struct {
 		SMS *struct {
 			Send bool `json:"send"`
@@ -30,6 +38,16 @@ type Manifest struct {
 			NotSupportedReason map[string]string `json:"not_supported_reasons,omitempty"`
 			CatalogReadonly    bool              `json:"catalog_readonly,omitempty"`
 		} `json:"automation,omitempty"`
+		Notify *struct {
+			Send     bool `json:"send"`
+			Markdown bool `json:"markdown,omitempty"`
+		} `json:"notify,omitempty"`
+		Storage *struct {
+			Presign bool `json:"presign,omitempty"`
+		} `json:"storage,omitempty"`
+		Captcha *struct {
+			Widget string `json:"widget"`
+		} `json:"captcha,omitempty"`
 	} `json:"capabilities"`
 }
 
@@ -71,5 +89,41 @@ func ReadManifest(dir string) (Manifest, error) {
 		}
 		m.Binaries = clean
 	}
+	if err := validateManifestJobs(m.Jobs); err != nil {
+		return Manifest{}, err
+	}
+	for i, scope := range m.Permissions {
+		scope = strings.TrimSpace(scope)
+		if !appshared.IsPluginScope(scope) {
+			return Manifest{}, fmt.Errorf("%s", "invalid manifest permission "+scope)
+		}
+		m.Permissions[i] = scope
+	}
+	seenHooks := map[string]bool{}
+	for i, event := range m.Hooks {
+		event = strings.TrimSpace(event)
+		if !appshared.IsPluginHookEvent(event) {
+			return Manifest{}, fmt.Errorf("%s", "invalid manifest hook "+event)
+		}
+		if seenHooks[event] {
+			return Manifest{}, fmt.Errorf("%s", "duplicate manifest hook "+event)
+		}
+		seenHooks[event] = true
+		m.Hooks[i] = event
+	}
 	return m, nil
 }
+
+func validateManifestJobs(jobs []appshared.PluginJobDefinition) error {
+	seen := map[string]bool{}
+	for _, job := range jobs {
+		if err := job.Validate(); err != nil {
+			return fmt.Errorf("%s", "invalid manifest job "+job.Name)
+		}
+		if seen[job.Name] {
+			return fmt.Errorf("%s", "duplicate manifest job "+job.Name)
+		}
+		seen[job.Name] = true
+	}
+	return nil
+}
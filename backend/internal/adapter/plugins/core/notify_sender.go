@@ -0,0 +1,67 @@
+package plugins
+
+import (
+	"context"
+	"strings"
+
+	"fmt"
+	appshared "xiaoheiplay/internal/app/shared"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+const notifyCategory = "notify"
+
+// NotifySender delivers notifications through plugins of the notify category.
+type NotifySender struct {
+	manager *Manager
+}
+
+func NewNotifySender(manager *Manager) *NotifySender {
+	return &NotifySender{manager: manager}
+}
+
+func (s *NotifySender) Send(ctx context.Context, pluginID, instanceID string, msg appshared.NotifyMessage) (appshared.NotifyDelivery, error) {
+	if s == nil || s.manager == nil {
+		return appshared.NotifyDelivery{}, fmt.Errorf("plugin manager unavailable")
+	}
+	pluginID = strings.TrimSpace(pluginID)
+	instanceID = strings.TrimSpace(instanceID)
+	if instanceID == "" {
+		instanceID = DefaultInstanceID
+	}
+	if pluginID == "" {
+		return appshared.NotifyDelivery{}, fmt.Errorf("notify plugin not configured")
+	}
+	if _, err := s.manager.EnsureRunning(ctx, notifyCategory, pluginID, instanceID); err != nil {
+		return appshared.NotifyDelivery{}, err
+	}
+	client, ok := s.manager.GetNotifyClient(notifyCategory, pluginID, instanceID)
+	if !ok || client == nil {
+		return appshared.NotifyDelivery{}, fmt.Errorf("notify plugin not running")
+	}
+	resp, err := client.Send(ctx, &pluginv1.SendNotifyRequest{
+		Title:    strings.TrimSpace(msg.Title),
+		Body:     msg.Body,
+		Markdown: msg.Markdown,
+		Target:   strings.TrimSpace(msg.Target),
+		Event:    strings.TrimSpace(msg.Event),
+	})
+	if err != nil {
+		return appshared.NotifyDelivery{}, MapRPCError(err, "notify plugin")
+	}
+	if resp == nil || !resp.Ok {
+		errMsg := "notify send failed"
+		errCode := ""
+		if resp != nil && strings.TrimSpace(resp.Error) != "" {
+			errMsg = strings.TrimSpace(resp.Error)
+		}
+		if resp != nil {
+			errCode = strings.TrimSpace(resp.ErrorCode)
+		}
+		if errCode != "" {
+			return appshared.NotifyDelivery{}, fmt.Errorf("%s (%s)", errMsg, errCode)
+		}
+		return appshared.NotifyDelivery{}, fmt.Errorf("%s", errMsg)
+	}
+	return appshared.NotifyDelivery{MessageID: strings.TrimSpace(resp.MessageId)}, nil
+}
@@ -0,0 +1,379 @@
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"xiaoheiplay/internal/domain"
+)
+
+// RepositoryIndexFile is read from repository sources that are local directories.
+const RepositoryIndexFile = "index.json"
+
+const (
+	repositoryFetchTimeout   = 30 * time.Second
+	repositoryIndexMaxBytes  = 4 << 20
+	repositoryPackageMaxSize = 256 << 20
+)
+
+// RepositoryIndex is the index.json a plugin repository publishes.
+type RepositoryIndex struct {
+	Plugins []RepositoryEntry `json:"plugins"`
+}
+
+// RepositoryEntry is one downloadable package version. Package is resolved
+// relative to the index unless it is an absolute http(s) URL. Signature is the
+// base64 ed25519 signature of RepositorySigningPayload made with SignerKey.
+type RepositoryEntry struct {
+	Category    string `json:"category"`
+	PluginID    string `json:"plugin_id"`
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+	Package     string `json:"package"`
+	SHA256      string `json:"sha256"`
+	SignerKey   string `json:"signer_key"`
+	Signature   string `json:"signature"`
+}
+
+// RepositorySigningPayload binds the package checksum to the plugin and
+// version it is published as, so a signed package cannot be re-listed under
+// another name or version.
+func RepositorySigningPayload(e RepositoryEntry) []byte {
+	return []byte(e.Category + "/" + e.PluginID + "@" + e.Version + ":" + strings.ToLower(e.SHA256))
+}
+
+// RepositoryItem is the newest version of a plugin across all repositories,
+// compared with what is installed.
+type RepositoryItem struct {
+	RepositoryEntry
+	Source           string
+	Installed        bool
+	InstalledVersion string
+	UpdateAvailable  bool
+	Verified         bool
+	VerifyError      string
+}
+
+// SetRepositories sets the index sources: local directories holding
+// index.json, or http(s) URLs of an index file.
+func (m *Manager) SetRepositories(sources []string) {
+	out := make([]string, 0, len(sources))
+	for _, s := range sources {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	m.repositories = out
+}
+
+// RepositoryPlugins lists the newest version of every plugin the configured
+// repositories offer. Indexes that cannot be loaded are skipped.
+func (m *Manager) RepositoryPlugins(ctx context.Context) ([]RepositoryItem, error) {
+	if len(m.repositories) == 0 {
+		return nil, fmt.Errorf("no plugin repositories configured")
+	}
+	latest := map[string]RepositoryItem{}
+	for _, source := range m.repositories {
+		index, err := m.loadRepositoryIndex(ctx, source)
+		if err != nil {
+			continue
+		}
+		for _, e := range index.Plugins {
+			e = normalizeRepositoryEntry(e)
+			if e.Category == "" || e.PluginID == "" || e.Version == "" || e.Package == "" {
+				continue
+			}
+			k := e.Category + ":" + e.PluginID
+			if cur, ok := latest[k]; ok && compareVersions(cur.Version, e.Version) >= 0 {
+				continue
+			}
+			latest[k] = RepositoryItem{RepositoryEntry: e, Source: source}
+		}
+	}
+
+	out := make([]RepositoryItem, 0, len(latest))
+	for _, it := range latest {
+		if err := m.verifyRepositoryEntry(it.RepositoryEntry); err != nil {
+			it.VerifyError = err.Error()
+		} else {
+			it.Verified = true
+		}
+		if installed, err := ReadManifest(m.PluginDir(it.Category, it.PluginID)); err == nil {
+			it.Installed = true
+			it.InstalledVersion = installed.Version
+			it.UpdateAvailable = compareVersions(it.Version, installed.Version) > 0
+		}
+		out = append(out, it)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Category != out[j].Category {
+			return out[i].Category < out[j].Category
+		}
+		return out[i].PluginID < out[j].PluginID
+	})
+	return out, nil
+}
+
+// InstallFromRepository downloads a plugin from the repositories, verifies it
+// against the official keys and installs it, or upgrades the installed copy.
+// An empty version picks the newest one.
+func (m *Manager) InstallFromRepository(ctx context.Context, category, pluginID, version string) (domain.PluginVersion, error) {
+	category = strings.TrimSpace(category)
+	pluginID = strings.TrimSpace(pluginID)
+	version = strings.TrimSpace(version)
+	entry, source, err := m.findRepositoryEntry(ctx, category, pluginID, version)
+	if err != nil {
+		return domain.PluginVersion{}, err
+	}
+	if err := m.verifyRepositoryEntry(entry); err != nil {
+		return domain.PluginVersion{}, err
+	}
+	pkgRef, err := resolveRepositoryRef(source, entry.Package)
+	if err != nil {
+		return domain.PluginVersion{}, err
+	}
+	raw, err := m.readRepositoryFile(ctx, pkgRef, repositoryPackageMaxSize)
+	if err != nil {
+		return domain.PluginVersion{}, err
+	}
+	sum := sha256.Sum256(raw)
+	if hex.EncodeToString(sum[:]) != entry.SHA256 {
+		return domain.PluginVersion{}, fmt.Errorf("package checksum mismatch")
+	}
+	filename := path.Base(filepath.ToSlash(entry.Package))
+	pkg, err := InspectPackage(filename, bytes.NewReader(raw), m.officialKeys)
+	if err != nil {
+		return domain.PluginVersion{}, err
+	}
+	if pkg.Category != category || pkg.PluginID != pluginID || pkg.Manifest.Version != entry.Version {
+		return domain.PluginVersion{}, fmt.Errorf("package does not match repository index")
+	}
+
+	if _, err := ReadManifest(m.PluginDir(category, pluginID)); err == nil {
+		// The index signature already proves the package is official.
+		return m.Upgrade(ctx, category, pluginID, filename, bytes.NewReader(raw), true)
+	}
+	inst, err := m.Install(ctx, filename, bytes.NewReader(raw))
+	if err != nil {
+		return domain.PluginVersion{}, err
+	}
+	if m.versions != nil {
+		if v, err := m.versions.GetPluginVersion(ctx, category, pluginID, entry.Version); err == nil {
+			return v, nil
+		}
+	}
+	return domain.PluginVersion{
+		Category:        category,
+		PluginID:        pluginID,
+		Version:         entry.Version,
+		SignatureStatus: inst.SignatureStatus,
+		Status:          domain.PluginVersionActive,
+	}, nil
+}
+
+func (m *Manager) findRepositoryEntry(ctx context.Context, category, pluginID, version string) (RepositoryEntry, string, error) {
+	if len(m.repositories) == 0 {
+		return RepositoryEntry{}, "", fmt.Errorf("no plugin repositories configured")
+	}
+	var found RepositoryEntry
+	var foundSource string
+	for _, source := range m.repositories {
+		index, err := m.loadRepositoryIndex(ctx, source)
+		if err != nil {
+			continue
+		}
+		for _, e := range index.Plugins {
+			e = normalizeRepositoryEntry(e)
+			if e.Category != category || e.PluginID != pluginID || e.Package == "" {
+				continue
+			}
+			if version != "" && e.Version != version {
+				continue
+			}
+			if foundSource != "" && compareVersions(found.Version, e.Version) >= 0 {
+				continue
+			}
+			found, foundSource = e, source
+		}
+	}
+	if foundSource == "" {
+		return RepositoryEntry{}, "", domain.ErrNotFound
+	}
+	return found, foundSource, nil
+}
+
+// verifyRepositoryEntry requires the entry to be signed by an official key.
+func (m *Manager) verifyRepositoryEntry(e RepositoryEntry) error {
+	if _, err := hex.DecodeString(e.SHA256); err != nil || len(e.SHA256) != 64 {
+		return fmt.Errorf("invalid package checksum")
+	}
+	signer, err := base64.StdEncoding.DecodeString(e.SignerKey)
+	if err != nil || len(signer) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid signer key")
+	}
+	official := false
+	for _, k := range m.officialKeys {
+		if bytes.Equal(k, signer) {
+			official = true
+			break
+		}
+	}
+	if !official {
+		return fmt.Errorf("signer key is not an official key")
+	}
+	sig, err := base64.StdEncoding.DecodeString(e.Signature)
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return fmt.Errorf("invalid signature")
+	}
+	if !ed25519.Verify(ed25519.PublicKey(signer), RepositorySigningPayload(e), sig) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+func (m *Manager) loadRepositoryIndex(ctx context.Context, source string) (RepositoryIndex, error) {
+	ref := source
+	if !isHTTPSource(source) {
+		ref = filepath.Join(source, RepositoryIndexFile)
+	}
+	raw, err := m.readRepositoryFile(ctx, ref, repositoryIndexMaxBytes)
+	if err != nil {
+		return RepositoryIndex{}, err
+	}
+	var index RepositoryIndex
+	if err := json.Unmarshal(raw, &index); err != nil {
+		return RepositoryIndex{}, fmt.Errorf("invalid repository index")
+	}
+	return index, nil
+}
+
+func (m *Manager) readRepositoryFile(ctx context.Context, ref string, maxBytes int64) ([]byte, error) {
+	var r io.Reader
+	if isHTTPSource(ref) {
+		cctx, cancel := context.WithTimeout(ctx, repositoryFetchTimeout)
+		defer cancel()
+		req, err := http.NewRequestWithContext(cctx, http.MethodGet, ref, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("%s", "repository fetch failed: "+resp.Status)
+		}
+		r = resp.Body
+	} else {
+		f, err := os.Open(ref)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	raw, err := io.ReadAll(io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(raw)) > maxBytes {
+		return nil, fmt.Errorf("repository file too large")
+	}
+	return raw, nil
+}
+
+// resolveRepositoryRef resolves a package path against the index it was
+// listed in. Local packages must stay inside the repository directory.
+func resolveRepositoryRef(source, pkg string) (string, error) {
+	if isHTTPSource(pkg) {
+		return pkg, nil
+	}
+	if isHTTPSource(source) {
+		base, err := url.Parse(source)
+		if err != nil {
+			return "", err
+		}
+		ref, err := url.Parse(pkg)
+		if err != nil {
+			return "", err
+		}
+		return base.ResolveReference(ref).String(), nil
+	}
+	rel := filepath.ToSlash(pkg)
+	if strings.HasPrefix(rel, "/") || strings.Contains(rel, "..") || strings.Contains(rel, ":") {
+		return "", fmt.Errorf("invalid package path")
+	}
+	return filepath.Join(source, filepath.FromSlash(rel)), nil
+}
+
+func isHTTPSource(s string) bool {
+	lower := strings.ToLower(s)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+func normalizeRepositoryEntry(e RepositoryEntry) RepositoryEntry {
+	e.Category = strings.TrimSpace(e.Category)
+	e.PluginID = strings.TrimSpace(e.PluginID)
+	e.Name = strings.TrimSpace(e.Name)
+	e.Version = strings.TrimSpace(e.Version)
+	e.Package = strings.TrimSpace(e.Package)
+	e.SHA256 = strings.ToLower(strings.TrimSpace(e.SHA256))
+	e.SignerKey = strings.TrimSpace(e.SignerKey)
+	e.Signature = strings.TrimSpace(e.Signature)
+	return e
+}
+
+// compareVersions orders dotted versions numerically ("1.10.0" > "1.9.2"),
+// ignoring a leading "v". Non-numeric parts compare as strings.
+func compareVersions(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(strings.TrimSpace(a), "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(strings.TrimSpace(b), "v"), ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y string
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		xn, xerr := strconv.Atoi(x)
+		yn, yerr := strconv.Atoi(y)
+		if x == "" {
+			xn, xerr = 0, nil
+		}
+		if y == "" {
+			yn, yerr = 0, nil
+		}
+		if xerr == nil && yerr == nil {
+			if xn != yn {
+				if xn < yn {
+					return -1
+				}
+				return 1
+			}
+			continue
+		}
+		if c := strings.Compare(x, y); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
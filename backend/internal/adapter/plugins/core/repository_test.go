@@ -0,0 +1,188 @@
+package plugins
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"xiaoheiplay/internal/domain"
+)
+
+type testRepository struct {
+	t     *testing.T
+	dir   string
+	priv  ed25519.PrivateKey
+	index RepositoryIndex
+}
+
+func newTestRepository(t *testing.T) *testRepository {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return &testRepository{t: t, dir: t.TempDir(), priv: priv}
+}
+
+func (r *testRepository) publicKey() ed25519.PublicKey {
+	return r.priv.Public().(ed25519.PublicKey)
+}
+
+// publish adds a signed entry for the demo sms plugin and rewrites index.json.
+func (r *testRepository) publish(version string, mutate func(*RepositoryEntry)) {
+	r.t.Helper()
+	raw, _ := io.ReadAll(pluginPackage(r.t, "demo", version))
+	rel := "sms/demo/demo-" + version + ".zip"
+	if err := os.MkdirAll(filepath.Join(r.dir, "sms", "demo"), 0o755); err != nil {
+		r.t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(r.dir, filepath.FromSlash(rel)), raw, 0o644); err != nil {
+		r.t.Fatalf("write package: %v", err)
+	}
+	sum := sha256.Sum256(raw)
+	e := RepositoryEntry{
+		Category:  "sms",
+		PluginID:  "demo",
+		Name:      "Demo",
+		Version:   version,
+		Package:   rel,
+		SHA256:    hex.EncodeToString(sum[:]),
+		SignerKey: base64.StdEncoding.EncodeToString(r.publicKey()),
+	}
+	e.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(r.priv, RepositorySigningPayload(e)))
+	if mutate != nil {
+		mutate(&e)
+	}
+	r.index.Plugins = append(r.index.Plugins, e)
+	b, _ := json.Marshal(r.index)
+	if err := os.WriteFile(filepath.Join(r.dir, RepositoryIndexFile), b, 0o644); err != nil {
+		r.t.Fatalf("write index: %v", err)
+	}
+}
+
+func newRepositoryManager(t *testing.T, officialKeys []ed25519.PublicKey, sources ...string) *Manager {
+	t.Helper()
+	m := NewManager(t.TempDir(), &memPluginInstallations{}, nil, officialKeys)
+	m.SetVersionRepository(&memPluginVersions{items: map[string]domain.PluginVersion{}})
+	m.SetRepositories(sources)
+	return m
+}
+
+func repositoryItem(t *testing.T, m *Manager) RepositoryItem {
+	t.Helper()
+	items, err := m.RepositoryPlugins(context.Background())
+	if err != nil || len(items) != 1 {
+		t.Fatalf("expected one repository item, got %+v (%v)", items, err)
+	}
+	return items[0]
+}
+
+func TestInstallFromLocalRepositoryAndUpgrade(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	repo.publish("1.0.0", nil)
+	m := newRepositoryManager(t, []ed25519.PublicKey{repo.publicKey()}, repo.dir)
+
+	it := repositoryItem(t, m)
+	if !it.Verified || it.Installed || it.Version != "1.0.0" {
+		t.Fatalf("unexpected item before install: %+v", it)
+	}
+	if _, err := m.InstallFromRepository(ctx, "sms", "demo", ""); err != nil {
+		t.Fatalf("install from repository: %v", err)
+	}
+	if it := repositoryItem(t, m); !it.Installed || it.InstalledVersion != "1.0.0" || it.UpdateAvailable {
+		t.Fatalf("unexpected item after install: %+v", it)
+	}
+
+	repo.publish("1.10.0", nil)
+	repo.publish("1.9.0", nil)
+	if it := repositoryItem(t, m); it.Version != "1.10.0" || !it.UpdateAvailable {
+		t.Fatalf("expected 1.10.0 to be offered as an update: %+v", it)
+	}
+	v, err := m.InstallFromRepository(ctx, "sms", "demo", "")
+	if err != nil {
+		t.Fatalf("upgrade from repository: %v", err)
+	}
+	if v.Version != "1.10.0" || v.Status != domain.PluginVersionActive || activeVersion(t, m) != "1.10.0" {
+		t.Fatalf("unexpected version after upgrade: %+v", v)
+	}
+	if _, err := m.InstallFromRepository(ctx, "sms", "demo", "2.0.0"); err != domain.ErrNotFound {
+		t.Fatalf("expected unknown version to be not found, got %v", err)
+	}
+}
+
+func TestInstallFromRepositoryRejectsUnverifiedPackages(t *testing.T) {
+	ctx := context.Background()
+	for name, tc := range map[string]struct {
+		mutate  func(*RepositoryEntry)
+		trusted bool
+	}{
+		"unofficial signer": {trusted: false},
+		"bad checksum": {trusted: true, mutate: func(e *RepositoryEntry) {
+			e.SHA256 = hex.EncodeToString(make([]byte, 32))
+		}},
+		"relabelled version": {trusted: true, mutate: func(e *RepositoryEntry) {
+			e.Version = "9.9.9"
+		}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			repo := newTestRepository(t)
+			repo.publish("1.0.0", tc.mutate)
+			var keys []ed25519.PublicKey
+			if tc.trusted {
+				keys = append(keys, repo.publicKey())
+			}
+			m := newRepositoryManager(t, keys, repo.dir)
+			if _, err := m.InstallFromRepository(ctx, "sms", "demo", ""); err == nil {
+				t.Fatalf("expected install to be rejected")
+			}
+			if _, err := os.Stat(m.PluginDir("sms", "demo")); !os.IsNotExist(err) {
+				t.Fatalf("rejected package must not be installed, stat err %v", err)
+			}
+		})
+	}
+}
+
+func TestInstallFromHTTPRepository(t *testing.T) {
+	repo := newTestRepository(t)
+	repo.publish("1.0.0", nil)
+	srv := httptest.NewServer(http.FileServer(http.Dir(repo.dir)))
+	defer srv.Close()
+	m := newRepositoryManager(t, []ed25519.PublicKey{repo.publicKey()}, srv.URL+"/"+RepositoryIndexFile)
+
+	if it := repositoryItem(t, m); !it.Verified {
+		t.Fatalf("expected verified item: %+v", it)
+	}
+	if _, err := m.InstallFromRepository(context.Background(), "sms", "demo", "1.0.0"); err != nil {
+		t.Fatalf("install from http repository: %v", err)
+	}
+	if got := activeVersion(t, m); got != "1.0.0" {
+		t.Fatalf("expected 1.0.0 installed, got %s", got)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want int
+	}{
+		{"1.10.0", "1.9.2", 1},
+		{"v1.2.0", "1.2", 0},
+		{"1.2.0", "1.2.1", -1},
+		{"2.0.0-beta", "2.0.0-alpha", 1},
+	} {
+		if got := compareVersions(tc.a, tc.b); got != tc.want {
+			t.Fatalf("compareVersions(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
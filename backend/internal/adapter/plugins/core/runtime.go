@@ -13,7 +13,10 @@ import (
 	"time"
 
 	"github.com/hashicorp/go-plugin"
+	"github.com/shirou/gopsutil/v3/process"
+	"google.golang.org/grpc"
 
+	appshared "xiaoheiplay/internal/app/shared"
 	"xiaoheiplay/pkg/pluginsdk"
 	pluginv1 "xiaoheiplay/plugin/v1"
 )
@@ -21,10 +24,17 @@ import (
 type Runtime struct {
 	baseDir string
 
-	mu      sync.Mutex
-	running map[string]*runningPlugin
+	mu         sync.Mutex
+	running    map[string]*runningPlugin
+	supervised map[string]*supervision
+	host       hostFactory
+	restart    restartFunc
+	onEvent    func(appshared.PluginRuntimeEvent)
 }
 
+// hostFactory builds the HostService a started instance can dial back.
+type hostFactory func(category, pluginID, instanceID string, manifest Manifest) pluginv1.HostServiceServer
+
 type runningPlugin struct {
 	mu sync.Mutex
 
@@ -38,15 +48,26 @@ type runningPlugin struct {
 	payment    pluginv1.PaymentServiceClient
 	kyc        pluginv1.KycServiceClient
 	automation pluginv1.AutomationServiceClient
+	hook       pluginv1.HookServiceClient
+	notify     pluginv1.NotifyServiceClient
+	storage    pluginv1.StorageServiceClient
+	captcha    pluginv1.CaptchaServiceClient
 	manifest   *pluginv1.Manifest
 
 	lastHealth time.Time
 	health     *pluginv1.HealthCheckResponse
 	cancelHB   context.CancelFunc
+
+	sup        *supervision
+	pid        int
+	proc       *process.Process
+	memoryRSS  uint64
+	cpuPercent float64
+	sampledAt  time.Time
 }
 
 func NewRuntime(baseDir string) *Runtime {
-	return &Runtime{baseDir: baseDir, running: map[string]*runningPlugin{}}
+	return &Runtime{baseDir: baseDir, running: map[string]*runningPlugin{}, supervised: map[string]*supervision{}}
 }
 
 func automationFeatureFromString(s string) (pluginv1.AutomationFeature, bool) {
@@ -63,6 +84,14 @@ func automationFeatureFromString(s string) (pluginv1.AutomationFeature, bool) {
 		return pluginv1.AutomationFeature_AUTOMATION_FEATURE_SNAPSHOT, true
 	case "firewall":
 		return pluginv1.AutomationFeature_AUTOMATION_FEATURE_FIREWALL, true
+	case "cloud_init":
+		return pluginv1.AutomationFeature_AUTOMATION_FEATURE_CLOUD_INIT, true
+	case "reverse_dns":
+		return pluginv1.AutomationFeature_AUTOMATION_FEATURE_REVERSE_DNS, true
+	case "extra_ip":
+		return pluginv1.AutomationFeature_AUTOMATION_FEATURE_EXTRA_IP, true
+	case "rescue":
+		return pluginv1.AutomationFeature_AUTOMATION_FEATURE_RESCUE, true
 	default:
 		return pluginv1.AutomationFeature_AUTOMATION_FEATURE_UNSPECIFIED, false
 	}
@@ -93,7 +122,10 @@ func normalizeAutomationNotSupportedReasons(m map[string]string) map[int32]strin
 	return out
 }
 
-func validateManifestConsistency(jsonM Manifest, grpcM *pluginv1.Manifest) error {
+// ValidateManifestConsistency checks the manifest a plugin serves over gRPC
+// against its manifest.json. Start refuses plugins that fail it; plugin tests
+// run it through pkg/pluginsdk/testing.
+func ValidateManifestConsistency(jsonM Manifest, grpcM *pluginv1.Manifest) error {
 	if grpcM == nil {
 		return fmt.Errorf("invalid manifest")
 	}
@@ -149,6 +181,36 @@ func validateManifestConsistency(jsonM Manifest, grpcM *pluginv1.Manifest) error
 		}
 	}
 
+	// notify
+	if (jsonM.Capabilities.Notify != nil) != (grpcM.Notify != nil) {
+		return fmt.Errorf("manifest mismatch: notify capability presence")
+	}
+	if jsonM.Capabilities.Notify != nil && grpcM.Notify != nil {
+		if grpcM.Notify.GetSend() != jsonM.Capabilities.Notify.Send || grpcM.Notify.GetMarkdown() != jsonM.Capabilities.Notify.Markdown {
+			return fmt.Errorf("manifest mismatch: notify flags")
+		}
+	}
+
+	// storage
+	if (jsonM.Capabilities.Storage != nil) != (grpcM.Storage != nil) {
+		return fmt.Errorf("manifest mismatch: storage capability presence")
+	}
+	if jsonM.Capabilities.Storage != nil && grpcM.Storage != nil {
+		if grpcM.Storage.GetPresign() != jsonM.Capabilities.Storage.Presign {
+			return fmt.Errorf("manifest mismatch: storage flags")
+		}
+	}
+
+	// captcha
+	if (jsonM.Capabilities.Captcha != nil) != (grpcM.Captcha != nil) {
+		return fmt.Errorf("manifest mismatch: captcha capability presence")
+	}
+	if jsonM.Capabilities.Captcha != nil && grpcM.Captcha != nil {
+		if strings.TrimSpace(grpcM.Captcha.GetWidget()) != strings.TrimSpace(jsonM.Capabilities.Captcha.Widget) {
+			return fmt.Errorf("manifest mismatch: captcha widget")
+		}
+	}
+
 	// automation
 	if (jsonM.Capabilities.Automation != nil) != (grpcM.Automation != nil) {
 		return fmt.Errorf("manifest mismatch: automation capability presence")
@@ -191,6 +253,12 @@ func validateManifestConsistency(jsonM Manifest, grpcM *pluginv1.Manifest) error
 	return nil
 }
 
+func (r *Runtime) SetHostFactory(f hostFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.host = f
+}
+
 func (r *Runtime) key(category, pluginID, instanceID string) string {
 	return category + ":" + pluginID + ":" + instanceID
 }
@@ -206,7 +274,12 @@ func (r *Runtime) Start(ctx context.Context, category, pluginID, instanceID, con
 		r.mu.Unlock()
 		return existing.manifest, nil
 	}
+	newHost := r.host
+	sup := r.supervised[k]
 	r.mu.Unlock()
+	if sup == nil {
+		sup = newSupervision(category, pluginID, instanceID)
+	}
 
 	pluginDir := filepath.Join(r.baseDir, category, pluginID)
 	manifestJSON, err := ReadManifest(pluginDir)
@@ -224,19 +297,32 @@ func (r *Runtime) Start(ctx context.Context, category, pluginID, instanceID, con
 	cmd := exec.Command(entry.EntryPath)
 	cmd.Dir = pluginDir
 
+	corePlugin := &pluginsdk.CoreGRPCPlugin{}
+	if newHost != nil {
+		corePlugin.Host = newHost(category, pluginID, instanceID, manifestJSON)
+	}
+
 	client := plugin.NewClient(&plugin.ClientConfig{
 		HandshakeConfig: pluginsdk.Handshake,
 		AllowedProtocols: []plugin.Protocol{
 			plugin.ProtocolGRPC,
 		},
 		Plugins: map[string]plugin.Plugin{
-			pluginsdk.PluginKeyCore:       &pluginsdk.CoreGRPCPlugin{},
+			pluginsdk.PluginKeyCore:       corePlugin,
 			pluginsdk.PluginKeySMS:        &pluginsdk.SmsGRPCPlugin{},
 			pluginsdk.PluginKeyPayment:    &pluginsdk.PaymentGRPCPlugin{},
 			pluginsdk.PluginKeyKYC:        &pluginsdk.KycGRPCPlugin{},
 			pluginsdk.PluginKeyAutomation: &pluginsdk.AutomationGRPCPlugin{},
+			pluginsdk.PluginKeyHook:       &pluginsdk.HookGRPCPlugin{},
+			pluginsdk.PluginKeyNotify:     &pluginsdk.NotifyGRPCPlugin{},
+			pluginsdk.PluginKeyStorage:    &pluginsdk.StorageGRPCPlugin{},
+			pluginsdk.PluginKeyCaptcha:    &pluginsdk.CaptchaGRPCPlugin{},
 		},
 		Cmd: cmd,
+		GRPCDialOptions: []grpc.DialOption{
+			grpc.WithChainUnaryInterceptor(sup.rpc.unaryInterceptor),
+			grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(pluginsdk.MaxMessageSize), grpc.MaxCallSendMsgSize(pluginsdk.MaxMessageSize)),
+		},
 	})
 
 	rpcClient, err := client.Client()
@@ -254,6 +340,10 @@ func (r *Runtime) Start(ctx context.Context, category, pluginID, instanceID, con
 		client.Kill()
 		return nil, fmt.Errorf("invalid core client")
 	}
+	var hostBrokerID uint32
+	if cc, ok := rawCore.(*pluginsdk.CoreClient); ok {
+		hostBrokerID = cc.HostBrokerID
+	}
 
 	ctxm, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
@@ -266,7 +356,7 @@ func (r *Runtime) Start(ctx context.Context, category, pluginID, instanceID, con
 		client.Kill()
 		return nil, fmt.Errorf("invalid manifest")
 	}
-	if err := validateManifestConsistency(manifestJSON, manifest); err != nil {
+	if err := ValidateManifestConsistency(manifestJSON, manifest); err != nil {
 		client.Kill()
 		return nil, err
 	}
@@ -275,6 +365,10 @@ func (r *Runtime) Start(ctx context.Context, category, pluginID, instanceID, con
 	var payment pluginv1.PaymentServiceClient
 	var kyc pluginv1.KycServiceClient
 	var automation pluginv1.AutomationServiceClient
+	var hook pluginv1.HookServiceClient
+	var notify pluginv1.NotifyServiceClient
+	var storage pluginv1.StorageServiceClient
+	var captcha pluginv1.CaptchaServiceClient
 
 	if manifest.Sms != nil {
 		raw, err := rpcClient.Dispense(pluginsdk.PluginKeySMS)
@@ -328,10 +422,62 @@ func (r *Runtime) Start(ctx context.Context, category, pluginID, instanceID, con
 		}
 		automation = c
 	}
+	if manifest.Notify != nil {
+		raw, err := rpcClient.Dispense(pluginsdk.PluginKeyNotify)
+		if err != nil {
+			client.Kill()
+			return nil, err
+		}
+		c, ok := raw.(pluginv1.NotifyServiceClient)
+		if !ok {
+			client.Kill()
+			return nil, fmt.Errorf("invalid notify client")
+		}
+		notify = c
+	}
+	if manifest.Storage != nil {
+		raw, err := rpcClient.Dispense(pluginsdk.PluginKeyStorage)
+		if err != nil {
+			client.Kill()
+			return nil, err
+		}
+		c, ok := raw.(pluginv1.StorageServiceClient)
+		if !ok {
+			client.Kill()
+			return nil, fmt.Errorf("invalid storage client")
+		}
+		storage = c
+	}
+	if manifest.Captcha != nil {
+		raw, err := rpcClient.Dispense(pluginsdk.PluginKeyCaptcha)
+		if err != nil {
+			client.Kill()
+			return nil, err
+		}
+		c, ok := raw.(pluginv1.CaptchaServiceClient)
+		if !ok {
+			client.Kill()
+			return nil, fmt.Errorf("invalid captcha client")
+		}
+		captcha = c
+	}
+	if len(manifestJSON.Hooks) > 0 {
+		raw, err := rpcClient.Dispense(pluginsdk.PluginKeyHook)
+		if err != nil {
+			client.Kill()
+			return nil, err
+		}
+		c, ok := raw.(pluginv1.HookServiceClient)
+		if !ok {
+			client.Kill()
+			return nil, fmt.Errorf("invalid hook client")
+		}
+		hook = c
+	}
 
 	ctxi, cancelInit := context.WithTimeout(ctx, 10*time.Second)
 	defer cancelInit()
-	initResp, err := core.Init(ctxi, &pluginv1.InitRequest{InstanceId: instanceID, ConfigJson: configJSON})
+	initResp, err := core.Init(ctxi, &pluginv1.InitRequest{InstanceId: instanceID, ConfigJson: configJSON, HostBrokerId: hostBrokerID})
 	if err != nil {
 		client.Kill()
 		return nil, err
@@ -355,16 +501,26 @@ func (r *Runtime) Start(ctx context.Context, category, pluginID, instanceID, con
 		payment:    payment,
 		kyc:        kyc,
 		automation: automation,
+		hook:       hook,
+		notify:     notify,
+		storage:    storage,
+		captcha:    captcha,
 		manifest:   manifest,
 		cancelHB:   hbCancel,
 		health:     nil,
 		lastHealth: time.Time{},
+		sup:        sup,
+	}
+	if rc := client.ReattachConfig(); rc != nil {
+		rp.pid = rc.Pid
 	}
-	go rp.heartbeatLoop(hbCtx)
+	sup.started(time.Now(), configJSON)
 
 	r.mu.Lock()
 	r.running[k] = rp
+	r.supervised[k] = sup
 	r.mu.Unlock()
+	go r.supervise(hbCtx, k, rp)
 
 	return manifest, nil
 }
@@ -373,8 +529,13 @@ func (r *Runtime) Stop(category, pluginID, instanceID string) {
 	k := r.key(category, pluginID, instanceID)
 	r.mu.Lock()
 	rp := r.running[k]
+	sup := r.supervised[k]
 	delete(r.running, k)
+	delete(r.supervised, k)
 	r.mu.Unlock()
+	if sup != nil {
+		sup.stop()
+	}
 	if rp == nil {
 		return
 	}
@@ -386,6 +547,20 @@ func (r *Runtime) Stop(category, pluginID, instanceID string) {
 	}
 }
 
+// StopAll stops every running instance, for short lived host processes
+// such as command line tools.
+func (r *Runtime) StopAll() {
+	r.mu.Lock()
+	running := make([]*runningPlugin, 0, len(r.running))
+	for _, rp := range r.running {
+		running = append(running, rp)
+	}
+	r.mu.Unlock()
+	for _, rp := range running {
+		r.Stop(rp.category, rp.pluginID, rp.instanceID)
+	}
+}
+
 func (r *Runtime) GetRunning(category, pluginID, instanceID string) (*runningPlugin, bool) {
 	k := r.key(category, pluginID, instanceID)
 	r.mu.Lock()
@@ -394,27 +569,11 @@ func (r *Runtime) GetRunning(category, pluginID, instanceID string) (*runningPlu
 	return rp, rp != nil
 }
 
-func (p *runningPlugin) heartbeatLoop(ctx context.Context) {
-	ticker := time.NewTicker(10 * time.Second)
-	defer ticker.Stop()
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			if p.core == nil || p.manifest == nil {
-				continue
-			}
-			cctx, cancel := context.WithTimeout(ctx, 2*time.Second)
-			resp, err := p.core.Health(cctx, &pluginv1.HealthCheckRequest{InstanceId: p.instanceID})
-			cancel()
-			if err != nil {
-				continue
-			}
-			p.mu.Lock()
-			p.lastHealth = time.Now()
-			p.health = resp
-			p.mu.Unlock()
-		}
+// AutomationClient returns the automation service of a running instance.
+func (r *Runtime) AutomationClient(pluginID, instanceID string) (pluginv1.AutomationServiceClient, bool) {
+	rp, ok := r.GetRunning("automation", pluginID, instanceID)
+	if !ok || rp.automation == nil {
+		return nil, false
 	}
+	return rp.automation, true
 }
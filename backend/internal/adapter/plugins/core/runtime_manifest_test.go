@@ -32,7 +32,7 @@ func TestValidateManifestConsistencyCatalogReadonlyMatch(t *testing.T) {
 		},
 	}
 
-	if err := validateManifestConsistency(jsonM, grpcM); err != nil {
+	if err := ValidateManifestConsistency(jsonM, grpcM); err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
 }
@@ -62,7 +62,7 @@ func TestValidateManifestConsistencyCatalogReadonlyMismatch(t *testing.T) {
 		},
 	}
 
-	err := validateManifestConsistency(jsonM, grpcM)
+	err := ValidateManifestConsistency(jsonM, grpcM)
 	if err == nil {
 		t.Fatalf("expected mismatch error")
 	}
@@ -70,3 +70,100 @@ func TestValidateManifestConsistencyCatalogReadonlyMismatch(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestValidateManifestConsistencyNotifyMismatch(t *testing.T) {
+	jsonM := Manifest{
+		PluginID: "demo",
+		Name:     "Demo Plugin",
+		Version:  "1.0.0",
+	}
+	jsonM.Capabilities.Notify = &struct {
+		Send     bool `json:"send"`
+		Markdown bool `json:"markdown,omitempty"`
+	}{Send: true, Markdown: true}
+
+	grpcM := &pluginv1.Manifest{
+		PluginId: "demo",
+		Name:     "Demo Plugin",
+		Version:  "1.0.0",
+		Notify:   &pluginv1.NotifyCapability{Send: true, Markdown: true},
+	}
+	if err := ValidateManifestConsistency(jsonM, grpcM); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	grpcM.Notify.Markdown = false
+	err := ValidateManifestConsistency(jsonM, grpcM)
+	if err == nil || !strings.Contains(err.Error(), "notify") {
+		t.Fatalf("expected notify mismatch error, got: %v", err)
+	}
+
+	grpcM.Notify = nil
+	if err := ValidateManifestConsistency(jsonM, grpcM); err == nil {
+		t.Fatalf("expected missing notify capability to be rejected")
+	}
+}
+
+func TestValidateManifestConsistencyStorageMismatch(t *testing.T) {
+	jsonM := Manifest{
+		PluginID: "demo",
+		Name:     "Demo Plugin",
+		Version:  "1.0.0",
+	}
+	jsonM.Capabilities.Storage = &struct {
+		Presign bool `json:"presign,omitempty"`
+	}{Presign: true}
+
+	grpcM := &pluginv1.Manifest{
+		PluginId: "demo",
+		Name:     "Demo Plugin",
+		Version:  "1.0.0",
+		Storage:  &pluginv1.StorageCapability{Presign: true},
+	}
+	if err := ValidateManifestConsistency(jsonM, grpcM); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	grpcM.Storage.Presign = false
+	err := ValidateManifestConsistency(jsonM, grpcM)
+	if err == nil || !strings.Contains(err.Error(), "storage") {
+		t.Fatalf("expected storage mismatch error, got: %v", err)
+	}
+
+	grpcM.Storage = nil
+	if err := ValidateManifestConsistency(jsonM, grpcM); err == nil {
+		t.Fatalf("expected missing storage capability to be rejected")
+	}
+}
+
+func TestValidateManifestConsistencyCaptchaMismatch(t *testing.T) {
+	jsonM := Manifest{
+		PluginID: "demo",
+		Name:     "Demo Plugin",
+		Version:  "1.0.0",
+	}
+	jsonM.Capabilities.Captcha = &struct {
+		Widget string `json:"widget"`
+	}{Widget: "geetest_v4"}
+
+	grpcM := &pluginv1.Manifest{
+		PluginId: "demo",
+		Name:     "Demo Plugin",
+		Version:  "1.0.0",
+		Captcha:  &pluginv1.CaptchaCapability{Widget: "geetest_v4"},
+	}
+	if err := ValidateManifestConsistency(jsonM, grpcM); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	grpcM.Captcha.Widget = "hcaptcha"
+	err := ValidateManifestConsistency(jsonM, grpcM)
+	if err == nil || !strings.Contains(err.Error(), "captcha") {
+		t.Fatalf("expected captcha mismatch error, got: %v", err)
+	}
+
+	grpcM.Captcha = nil
+	if err := ValidateManifestConsistency(jsonM, grpcM); err == nil {
+		t.Fatalf("expected missing captcha capability to be rejected")
+	}
+}
@@ -0,0 +1,124 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"xiaoheiplay/internal/domain"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+const storageCategory = "storage"
+
+// StorageClient keeps upload contents in one instance of a storage plugin.
+type StorageClient struct {
+	manager    *Manager
+	pluginID   string
+	instanceID string
+}
+
+func NewStorageClient(manager *Manager, pluginID, instanceID string) *StorageClient {
+	pluginID = strings.TrimSpace(pluginID)
+	instanceID = strings.TrimSpace(instanceID)
+	if instanceID == "" {
+		instanceID = DefaultInstanceID
+	}
+	return &StorageClient{manager: manager, pluginID: pluginID, instanceID: instanceID}
+}
+
+func (s *StorageClient) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	client, err := s.client(ctx)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Put(ctx, &pluginv1.PutObjectRequest{Key: key, Content: body, ContentType: contentType})
+	if err != nil {
+		return MapRPCError(err, "storage plugin")
+	}
+	if resp == nil || !resp.Ok {
+		return storageError(resp.GetError(), "storage put failed")
+	}
+	return nil
+}
+
+func (s *StorageClient) Get(ctx context.Context, key string) ([]byte, string, error) {
+	client, err := s.client(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := client.Get(ctx, &pluginv1.GetObjectRequest{Key: key})
+	if status.Code(err) == codes.NotFound {
+		return nil, "", domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, "", MapRPCError(err, "storage plugin")
+	}
+	if resp == nil || !resp.Ok {
+		return nil, "", storageError(resp.GetError(), "storage get failed")
+	}
+	return resp.Content, strings.TrimSpace(resp.ContentType), nil
+}
+
+func (s *StorageClient) Delete(ctx context.Context, key string) error {
+	client, err := s.client(ctx)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Delete(ctx, &pluginv1.DeleteObjectRequest{Key: key})
+	if err != nil {
+		return MapRPCError(err, "storage plugin")
+	}
+	if resp == nil || !resp.Ok {
+		return storageError(resp.GetError(), "storage delete failed")
+	}
+	return nil
+}
+
+// PresignURL returns "" when the plugin does not declare the presign
+// capability.
+func (s *StorageClient) PresignURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	client, err := s.client(ctx)
+	if err != nil {
+		return "", err
+	}
+	if rp, ok := s.manager.runtime.GetRunning(storageCategory, s.pluginID, s.instanceID); !ok || !rp.manifest.GetStorage().GetPresign() {
+		return "", nil
+	}
+	resp, err := client.PresignURL(ctx, &pluginv1.PresignURLRequest{Key: key, ExpiresInSec: int32(ttl / time.Second)})
+	if err != nil {
+		return "", MapRPCError(err, "storage plugin")
+	}
+	if resp == nil || !resp.Ok {
+		return "", storageError(resp.GetError(), "storage presign failed")
+	}
+	return strings.TrimSpace(resp.Url), nil
+}
+
+func (s *StorageClient) client(ctx context.Context) (pluginv1.StorageServiceClient, error) {
+	if s == nil || s.manager == nil {
+		return nil, fmt.Errorf("plugin manager unavailable")
+	}
+	if s.pluginID == "" {
+		return nil, fmt.Errorf("storage plugin not configured")
+	}
+	if _, err := s.manager.EnsureRunning(ctx, storageCategory, s.pluginID, s.instanceID); err != nil {
+		return nil, err
+	}
+	client, ok := s.manager.GetStorageClient(storageCategory, s.pluginID, s.instanceID)
+	if !ok || client == nil {
+		return nil, fmt.Errorf("storage plugin not running")
+	}
+	return client, nil
+}
+
+func storageError(msg, fallback string) error {
+	if msg = strings.TrimSpace(msg); msg != "" {
+		return fmt.Errorf("%s", msg)
+	}
+	return fmt.Errorf("%s", fallback)
+}
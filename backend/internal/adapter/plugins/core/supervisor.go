@@ -0,0 +1,527 @@
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+	"google.golang.org/grpc"
+
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+const (
+	exitPollInterval    = time.Second
+	healthCheckInterval = 10 * time.Second
+	healthCheckTimeout  = 2 * time.Second
+	// healthFailureLimit missed health checks in a row count as a hung process.
+	healthFailureLimit = 3
+
+	restartBackoffMin = time.Second
+	restartBackoffMax = 5 * time.Minute
+	// breakerThreshold failures in a row mark the instance degraded; restarts
+	// are then only tried once per breakerCooldown.
+	breakerThreshold = 5
+	breakerCooldown  = 10 * time.Minute
+	// stableAfter is how long an instance must stay up before its failure
+	// count is cleared.
+	stableAfter = 2 * time.Minute
+)
+
+// RuntimeEventSink receives crash, degraded and recovered notices of
+// supervised instances.
+type RuntimeEventSink interface {
+	NotifyPluginRuntimeEvent(ctx context.Context, ev appshared.PluginRuntimeEvent) error
+}
+
+// restartFunc starts a crashed instance again.
+type restartFunc func(ctx context.Context, category, pluginID, instanceID string) error
+
+// supervision is kept per instance from its first successful start until it
+// is stopped, so failures and RPC counters survive restarts.
+type supervision struct {
+	category   string
+	pluginID   string
+	instanceID string
+	configJSON string
+
+	rpc *rpcMetrics
+
+	mu            sync.Mutex
+	state         string
+	startedAt     time.Time
+	restarts      int
+	failures      int
+	lastError     string
+	lastExitAt    time.Time
+	nextRestartAt time.Time
+	cancelRestart context.CancelFunc
+}
+
+func newSupervision(category, pluginID, instanceID string) *supervision {
+	return &supervision{
+		category:   category,
+		pluginID:   pluginID,
+		instanceID: instanceID,
+		rpc:        newRPCMetrics(),
+	}
+}
+
+// restartBackoff is the wait before the n-th restart attempt in a row.
+func restartBackoff(n int) time.Duration {
+	d := restartBackoffMin
+	for i := 1; i < n && d < restartBackoffMax; i++ {
+		d *= 2
+	}
+	if d > restartBackoffMax {
+		d = restartBackoffMax
+	}
+	return d
+}
+
+// started records a successful (re)start.
+func (s *supervision) started(now time.Time, configJSON string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != "" {
+		s.restarts++
+	}
+	if s.state != appshared.PluginRuntimeDegraded {
+		s.state = appshared.PluginRuntimeRunning
+	}
+	s.configJSON = configJSON
+	s.startedAt = now
+	s.nextRestartAt = time.Time{}
+	// A caller may have started the instance before the pending restart.
+	if s.cancelRestart != nil {
+		s.cancelRestart()
+		s.cancelRestart = nil
+	}
+}
+
+// failed records a crash or a failed restart and returns the wait before the
+// next attempt. degraded is true when this failure tripped the breaker.
+func (s *supervision) failed(now time.Time, reason string) (delay time.Duration, degraded bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures++
+	s.lastError = reason
+	s.lastExitAt = now
+	if s.failures >= breakerThreshold {
+		degraded = s.state != appshared.PluginRuntimeDegraded
+		s.state = appshared.PluginRuntimeDegraded
+		delay = breakerCooldown
+	} else {
+		s.state = appshared.PluginRuntimeRestarting
+		delay = restartBackoff(s.failures)
+	}
+	s.nextRestartAt = now.Add(delay)
+	return delay, degraded
+}
+
+// markStable clears the failure count once the instance has stayed up for
+// stableAfter. It reports whether the instance recovered from failures.
+func (s *supervision) markStable(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures == 0 || s.startedAt.IsZero() || now.Sub(s.startedAt) < stableAfter {
+		return false
+	}
+	s.failures = 0
+	s.state = appshared.PluginRuntimeRunning
+	return true
+}
+
+func (s *supervision) setPendingRestart(cancel context.CancelFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cancelRestart = cancel
+}
+
+func (s *supervision) stop() {
+	s.mu.Lock()
+	cancel := s.cancelRestart
+	s.cancelRestart = nil
+	s.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+func (s *supervision) event(eventType string, now time.Time) appshared.PluginRuntimeEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ev := appshared.PluginRuntimeEvent{
+		Type:       eventType,
+		Category:   s.category,
+		PluginID:   s.pluginID,
+		InstanceID: s.instanceID,
+		Failures:   s.failures,
+		Restarts:   s.restarts,
+		OccurredAt: now,
+	}
+	if eventType != appshared.PluginRuntimeEventRecovered {
+		ev.Error = s.lastError
+		if !s.nextRestartAt.IsZero() {
+			t := s.nextRestartAt
+			ev.NextRestartAt = &t
+		}
+	}
+	return ev
+}
+
+func (s *supervision) status() appshared.PluginRuntimeStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := appshared.PluginRuntimeStatus{
+		State:     s.state,
+		Restarts:  s.restarts,
+		Failures:  s.failures,
+		LastError: s.lastError,
+		RPC:       s.rpc.snapshot(),
+	}
+	if !s.startedAt.IsZero() {
+		t := s.startedAt
+		out.StartedAt = &t
+	}
+	if !s.lastExitAt.IsZero() {
+		t := s.lastExitAt
+		out.LastExitAt = &t
+	}
+	if !s.nextRestartAt.IsZero() {
+		t := s.nextRestartAt
+		out.NextRestartAt = &t
+	}
+	return out
+}
+
+// SetRestarter replaces how crashed instances are started again. By default
+// the config of the last start is reused.
+func (r *Runtime) SetRestarter(f restartFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.restart = f
+}
+
+// SetEventHandler sets the callback for runtime events.
+func (r *Runtime) SetEventHandler(f func(appshared.PluginRuntimeEvent)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onEvent = f
+}
+
+// Status returns the supervision state of an instance that has been started
+// and not stopped since.
+func (r *Runtime) Status(category, pluginID, instanceID string) (appshared.PluginRuntimeStatus, bool) {
+	k := r.key(category, pluginID, instanceID)
+	r.mu.Lock()
+	sup := r.supervised[k]
+	rp := r.running[k]
+	r.mu.Unlock()
+	if sup == nil {
+		return appshared.PluginRuntimeStatus{}, false
+	}
+	out := sup.status()
+	if rp != nil {
+		rp.mu.Lock()
+		out.PID = rp.pid
+		out.MemoryRSSBytes = rp.memoryRSS
+		out.CPUPercent = rp.cpuPercent
+		if !rp.sampledAt.IsZero() {
+			t := rp.sampledAt
+			out.SampledAt = &t
+		}
+		rp.mu.Unlock()
+	}
+	return out, true
+}
+
+func (r *Runtime) emit(ev appshared.PluginRuntimeEvent) {
+	r.mu.Lock()
+	f := r.onEvent
+	r.mu.Unlock()
+	if f != nil {
+		f(ev)
+	}
+}
+
+// supervise watches a started instance: it polls for process exit, checks
+// health, samples process usage and hands crashes to handleFailure.
+func (r *Runtime) supervise(ctx context.Context, k string, rp *runningPlugin) {
+	exitTicker := time.NewTicker(exitPollInterval)
+	defer exitTicker.Stop()
+	healthTicker := time.NewTicker(healthCheckInterval)
+	defer healthTicker.Stop()
+	missed := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-exitTicker.C:
+			if rp.client != nil && rp.client.Exited() {
+				r.handleFailure(k, rp, "plugin process exited")
+				return
+			}
+		case <-healthTicker.C:
+			rp.sampleProcess()
+			if rp.sup.markStable(time.Now()) {
+				r.emit(rp.sup.event(appshared.PluginRuntimeEventRecovered, time.Now()))
+			}
+			if err := rp.checkHealth(ctx); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				missed++
+				if missed >= healthFailureLimit {
+					r.handleFailure(k, rp, "health check failed: "+err.Error())
+					return
+				}
+				continue
+			}
+			missed = 0
+		}
+	}
+}
+
+// handleFailure drops a crashed or hung instance and schedules its restart.
+// Instances stopped or replaced in the meantime are left alone.
+func (r *Runtime) handleFailure(k string, rp *runningPlugin, reason string) {
+	r.mu.Lock()
+	if r.running[k] != rp {
+		r.mu.Unlock()
+		return
+	}
+	delete(r.running, k)
+	r.mu.Unlock()
+	if rp.cancelHB != nil {
+		rp.cancelHB()
+	}
+	if rp.client != nil {
+		rp.client.Kill()
+	}
+	r.recordFailure(k, rp.sup, reason, true)
+}
+
+func (r *Runtime) recordFailure(k string, sup *supervision, reason string, crashed bool) {
+	now := time.Now()
+	delay, degraded := sup.failed(now, reason)
+	if crashed {
+		r.emit(sup.event(appshared.PluginRuntimeEventCrashed, now))
+	}
+	if degraded {
+		r.emit(sup.event(appshared.PluginRuntimeEventDegraded, now))
+	}
+	r.scheduleRestart(k, sup, delay)
+}
+
+func (r *Runtime) scheduleRestart(k string, sup *supervision, delay time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.mu.Lock()
+	if r.supervised[k] != sup {
+		r.mu.Unlock()
+		cancel()
+		return
+	}
+	sup.setPendingRestart(cancel)
+	restart := r.restart
+	r.mu.Unlock()
+
+	go func() {
+		defer cancel()
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		var err error
+		if restart != nil {
+			err = restart(ctx, sup.category, sup.pluginID, sup.instanceID)
+		} else {
+			sup.mu.Lock()
+			cfg := sup.configJSON
+			sup.mu.Unlock()
+			_, err = r.Start(ctx, sup.category, sup.pluginID, sup.instanceID, cfg)
+		}
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+		r.recordFailure(k, sup, "restart failed: "+err.Error(), false)
+	}()
+}
+
+func (p *runningPlugin) checkHealth(ctx context.Context) error {
+	if p.core == nil || p.manifest == nil {
+		return nil
+	}
+	cctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	resp, err := p.core.Health(cctx, &pluginv1.HealthCheckRequest{InstanceId: p.instanceID})
+	cancel()
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.lastHealth = time.Now()
+	p.health = resp
+	p.mu.Unlock()
+	return nil
+}
+
+// sampleProcess refreshes memory and CPU usage of the plugin process. CPU is
+// the share of one core used since the previous sample.
+func (p *runningPlugin) sampleProcess() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.pid <= 0 {
+		return
+	}
+	if p.proc == nil {
+		proc, err := process.NewProcess(int32(p.pid))
+		if err != nil {
+			return
+		}
+		p.proc = proc
+	}
+	if mem, err := p.proc.MemoryInfo(); err == nil && mem != nil {
+		p.memoryRSS = mem.RSS
+	}
+	if cpu, err := p.proc.Percent(0); err == nil {
+		p.cpuPercent = cpu
+	}
+	p.sampledAt = time.Now()
+}
+
+// rpcMetrics counts calls per gRPC method on the host side of a plugin
+// connection.
+type rpcMetrics struct {
+	mu      sync.Mutex
+	methods map[string]*rpcMethodStats
+}
+
+type rpcMethodStats struct {
+	calls      int64
+	errors     int64
+	total      time.Duration
+	max        time.Duration
+	lastError  string
+	lastCallAt time.Time
+}
+
+func newRPCMetrics() *rpcMetrics {
+	return &rpcMetrics{methods: map[string]*rpcMethodStats{}}
+}
+
+func (m *rpcMetrics) unaryInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	m.observe(method, start, time.Since(start), err)
+	return err
+}
+
+func (m *rpcMetrics) observe(method string, at time.Time, latency time.Duration, err error) {
+	method = strings.TrimPrefix(method, "/")
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	st := m.methods[method]
+	if st == nil {
+		st = &rpcMethodStats{}
+		m.methods[method] = st
+	}
+	st.calls++
+	st.total += latency
+	if latency > st.max {
+		st.max = latency
+	}
+	st.lastCallAt = at
+	if err != nil {
+		st.errors++
+		st.lastError = err.Error()
+	}
+}
+
+func (m *rpcMetrics) snapshot() []appshared.PluginRPCMethodStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.methods) == 0 {
+		return nil
+	}
+	out := make([]appshared.PluginRPCMethodStats, 0, len(m.methods))
+	for method, st := range m.methods {
+		lastCallAt := st.lastCallAt
+		out = append(out, appshared.PluginRPCMethodStats{
+			Method:       method,
+			Calls:        st.calls,
+			Errors:       st.errors,
+			AvgLatencyMs: float64(st.total) / float64(st.calls) / float64(time.Millisecond),
+			MaxLatencyMs: float64(st.max) / float64(time.Millisecond),
+			LastError:    st.lastError,
+			LastCallAt:   &lastCallAt,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Method < out[j].Method })
+	return out
+}
+
+// SetRuntimeEventSinks registers receivers for crash, degraded and recovered
+// events, e.g. the robot webhooks.
+func (m *Manager) SetRuntimeEventSinks(sinks ...RuntimeEventSink) {
+	m.runtimeSinks = sinks
+}
+
+// restartInstance goes through EnsureRunning so a restart picks up the
+// current config and waits for a running upgrade.
+func (m *Manager) restartInstance(ctx context.Context, category, pluginID, instanceID string) error {
+	_, err := m.EnsureRunning(ctx, category, pluginID, instanceID)
+	return err
+}
+
+// handleRuntimeEvent records ev in the plugin log and hands it to the sinks
+// without blocking the supervisor.
+func (m *Manager) handleRuntimeEvent(ev appshared.PluginRuntimeEvent) {
+	level := "error"
+	msg := ev.Type
+	switch ev.Type {
+	case appshared.PluginRuntimeEventCrashed:
+		msg = "plugin process crashed: " + ev.Error
+	case appshared.PluginRuntimeEventDegraded:
+		msg = fmt.Sprintf("plugin degraded after %d failures: %s", ev.Failures, ev.Error)
+	case appshared.PluginRuntimeEventRecovered:
+		level = "info"
+		msg = "plugin recovered"
+	}
+	log.Printf("plugin %s/%s/%s [%s] %s", ev.Category, ev.PluginID, ev.InstanceID, level, msg)
+	if m.host != nil && m.host.Logs != nil {
+		fieldsJSON := "{}"
+		if raw, err := json.Marshal(ev); err == nil {
+			fieldsJSON = string(raw)
+		}
+		_ = m.host.Logs.CreatePluginLog(context.Background(), &domain.PluginLog{
+			Category:   ev.Category,
+			PluginID:   ev.PluginID,
+			InstanceID: ev.InstanceID,
+			Level:      level,
+			Message:    msg,
+			FieldsJSON: fieldsJSON,
+			CreatedAt:  ev.OccurredAt,
+		})
+	}
+	for _, sink := range m.runtimeSinks {
+		if sink == nil {
+			continue
+		}
+		go func(s RuntimeEventSink) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			_ = s.NotifyPluginRuntimeEvent(ctx, ev)
+		}(sink)
+	}
+}
@@ -0,0 +1,146 @@
+package plugins
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	appshared "xiaoheiplay/internal/app/shared"
+)
+
+func TestRestartBackoff(t *testing.T) {
+	for n, want := range map[int]time.Duration{
+		1:  time.Second,
+		2:  2 * time.Second,
+		4:  8 * time.Second,
+		20: restartBackoffMax,
+	} {
+		if got := restartBackoff(n); got != want {
+			t.Fatalf("restartBackoff(%d) = %s, want %s", n, got, want)
+		}
+	}
+}
+
+func TestSupervisionOpensBreakerAndRecovers(t *testing.T) {
+	now := time.Now()
+	s := newSupervision("payment", "demo", "default")
+	s.started(now, "{}")
+
+	for i := 1; i < breakerThreshold; i++ {
+		delay, degraded := s.failed(now, "exit")
+		if degraded || delay != restartBackoff(i) || s.status().State != appshared.PluginRuntimeRestarting {
+			t.Fatalf("failure %d: delay %s degraded %v state %s", i, delay, degraded, s.status().State)
+		}
+		s.started(now, "{}")
+	}
+	delay, degraded := s.failed(now, "exit")
+	if !degraded || delay != breakerCooldown || s.status().State != appshared.PluginRuntimeDegraded {
+		t.Fatalf("expected breaker to open, delay %s degraded %v", delay, degraded)
+	}
+	if _, degraded := s.failed(now, "exit"); degraded {
+		t.Fatalf("an open breaker must only be reported once")
+	}
+
+	s.started(now, "{}")
+	if s.status().State != appshared.PluginRuntimeDegraded {
+		t.Fatalf("a restart alone must not clear the degraded state")
+	}
+	if s.markStable(now.Add(time.Second)) {
+		t.Fatalf("instance is not stable yet")
+	}
+	if !s.markStable(now.Add(stableAfter)) {
+		t.Fatalf("expected instance to recover after running stably")
+	}
+	if st := s.status(); st.State != appshared.PluginRuntimeRunning || st.Failures != 0 || st.Restarts != breakerThreshold {
+		t.Fatalf("unexpected status after recovery: %+v", st)
+	}
+}
+
+func TestRPCMetricsInterceptor(t *testing.T) {
+	m := newRPCMetrics()
+	ok := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error { return nil }
+	fail := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		return errors.New("boom")
+	}
+	const method = "/plugin.v1.PaymentService/CreatePayment"
+	_ = m.unaryInterceptor(context.Background(), method, nil, nil, nil, ok)
+	if err := m.unaryInterceptor(context.Background(), method, nil, nil, nil, fail); err == nil {
+		t.Fatalf("interceptor must pass the error through")
+	}
+	stats := m.snapshot()
+	if len(stats) != 1 || stats[0].Method != "plugin.v1.PaymentService/CreatePayment" || stats[0].Calls != 2 || stats[0].Errors != 1 || stats[0].LastError != "boom" {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+type runtimeRecorder struct {
+	mu       sync.Mutex
+	events   []string
+	restarts chan string
+}
+
+func newSupervisedRuntime(t *testing.T) (*Runtime, *runtimeRecorder, string, *runningPlugin) {
+	t.Helper()
+	rec := &runtimeRecorder{restarts: make(chan string, 4)}
+	r := NewRuntime(t.TempDir())
+	r.SetEventHandler(func(ev appshared.PluginRuntimeEvent) {
+		rec.mu.Lock()
+		rec.events = append(rec.events, ev.Type)
+		rec.mu.Unlock()
+	})
+	r.SetRestarter(func(_ context.Context, category, pluginID, instanceID string) error {
+		rec.restarts <- r.key(category, pluginID, instanceID)
+		return nil
+	})
+	k := r.key("payment", "demo", "default")
+	rp := &runningPlugin{category: "payment", pluginID: "demo", instanceID: "default", sup: newSupervision("payment", "demo", "default")}
+	rp.sup.started(time.Now(), "{}")
+	r.running[k] = rp
+	r.supervised[k] = rp.sup
+	return r, rec, k, rp
+}
+
+func TestRuntimeRestartsCrashedInstance(t *testing.T) {
+	r, rec, k, rp := newSupervisedRuntime(t)
+	r.handleFailure(k, rp, "plugin process exited")
+
+	if _, ok := r.GetRunning("payment", "demo", "default"); ok {
+		t.Fatalf("crashed instance must not be served")
+	}
+	st, ok := r.Status("payment", "demo", "default")
+	if !ok || st.State != appshared.PluginRuntimeRestarting || st.NextRestartAt == nil || st.LastError != "plugin process exited" {
+		t.Fatalf("unexpected status after crash: %+v", st)
+	}
+	select {
+	case got := <-rec.restarts:
+		if got != k {
+			t.Fatalf("restarted %s, want %s", got, k)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("expected crashed instance to be restarted")
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if len(rec.events) != 1 || rec.events[0] != appshared.PluginRuntimeEventCrashed {
+		t.Fatalf("unexpected events: %v", rec.events)
+	}
+}
+
+func TestRuntimeStopCancelsPendingRestart(t *testing.T) {
+	r, rec, k, rp := newSupervisedRuntime(t)
+	r.handleFailure(k, rp, "plugin process exited")
+	r.Stop("payment", "demo", "default")
+
+	if _, ok := r.Status("payment", "demo", "default"); ok {
+		t.Fatalf("stopped instance must not be supervised")
+	}
+	select {
+	case <-rec.restarts:
+		t.Fatalf("stopped instance must not be restarted")
+	case <-time.After(restartBackoffMin + 500*time.Millisecond):
+	}
+}
@@ -0,0 +1,391 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	appports "xiaoheiplay/internal/app/ports"
+	"xiaoheiplay/internal/domain"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+// versionsDirName holds a copy of every version a plugin has run, as
+// <base>/.versions/<category>/<plugin_id>/<version>. The dot prefix keeps it
+// out of disk discovery; the active version stays at <base>/<category>/<plugin_id>.
+const versionsDirName = ".versions"
+
+const upgradeHealthTimeout = 5 * time.Second
+
+type pluginInstanceConfig struct {
+	instanceID string
+	configJSON string
+}
+
+// SetVersionRepository enables version history, upgrades and rollbacks.
+func (m *Manager) SetVersionRepository(repo appports.PluginVersionRepository) {
+	m.versions = repo
+}
+
+// pluginLock serializes version switches of a plugin with instance starts.
+func (m *Manager) pluginLock(category, pluginID string) *sync.RWMutex {
+	m.locksMu.Lock()
+	defer m.locksMu.Unlock()
+	if m.locks == nil {
+		m.locks = map[string]*sync.RWMutex{}
+	}
+	k := category + ":" + pluginID
+	if m.locks[k] == nil {
+		m.locks[k] = &sync.RWMutex{}
+	}
+	return m.locks[k]
+}
+
+func (m *Manager) versionsDir(category, pluginID string) string {
+	return filepath.Join(m.baseDir, versionsDirName, category, pluginID)
+}
+
+func (m *Manager) versionDir(category, pluginID, version string) string {
+	return filepath.Join(m.versionsDir(category, pluginID), version)
+}
+
+func validVersionDirName(version string) bool {
+	if version == "" || version == "." || strings.Contains(version, "..") {
+		return false
+	}
+	return !strings.ContainsAny(version, `/\:`)
+}
+
+// ListVersions returns the versions kept for a plugin, newest first.
+func (m *Manager) ListVersions(ctx context.Context, category, pluginID string) ([]domain.PluginVersion, error) {
+	if m.versions == nil {
+		return nil, fmt.Errorf("plugin version repo missing")
+	}
+	category = strings.TrimSpace(category)
+	pluginID = strings.TrimSpace(pluginID)
+	current, err := ReadManifest(m.PluginDir(category, pluginID))
+	if err != nil {
+		return nil, fmt.Errorf("plugin not installed")
+	}
+	items, err := m.versions.ListPluginVersions(ctx, category, pluginID)
+	if err != nil {
+		return nil, err
+	}
+	for _, it := range items {
+		if it.Version == current.Version {
+			return items, nil
+		}
+	}
+	// Plugins installed before versions were tracked have no record yet.
+	sigStatus, _ := VerifySignature(m.PluginDir(category, pluginID), m.officialKeys)
+	active := domain.PluginVersion{
+		Category:        category,
+		PluginID:        pluginID,
+		Version:         current.Version,
+		SignatureStatus: sigStatus,
+		Status:          domain.PluginVersionActive,
+	}
+	return append([]domain.PluginVersion{active}, items...), nil
+}
+
+// Upgrade stores a new package of an installed plugin next to the current
+// version and switches every instance over to it. Packages without an
+// official signature are refused unless trustUnofficial is set.
+func (m *Manager) Upgrade(ctx context.Context, category, pluginID, filename string, r io.Reader, trustUnofficial bool) (domain.PluginVersion, error) {
+	if m.repo == nil || m.versions == nil {
+		return domain.PluginVersion{}, fmt.Errorf("plugin repo missing")
+	}
+	category = strings.TrimSpace(category)
+	pluginID = strings.TrimSpace(pluginID)
+	pkg, cleanup, err := unpackPackage(filename, r, m.officialKeys)
+	if err != nil {
+		return domain.PluginVersion{}, err
+	}
+	defer cleanup()
+	if pkg.Category != category || pkg.PluginID != pluginID {
+		return domain.PluginVersion{}, fmt.Errorf("package is for a different plugin")
+	}
+	if pkg.SignatureStatus != domain.PluginSignatureOfficial && !trustUnofficial {
+		return domain.PluginVersion{}, domain.ErrAdminPasswordRequiredForUntrustedPlugin
+	}
+	version := pkg.Manifest.Version
+	if !validVersionDirName(version) {
+		return domain.PluginVersion{}, fmt.Errorf("invalid manifest version")
+	}
+
+	lock := m.pluginLock(category, pluginID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	current, err := ReadManifest(m.PluginDir(category, pluginID))
+	if err != nil {
+		return domain.PluginVersion{}, fmt.Errorf("plugin not installed")
+	}
+	if current.Version == version {
+		return domain.PluginVersion{}, fmt.Errorf("plugin version already active")
+	}
+	if err := m.archiveActive(ctx, category, pluginID, current.Version); err != nil {
+		return domain.PluginVersion{}, err
+	}
+	dir := m.versionDir(category, pluginID, version)
+	_ = os.RemoveAll(dir)
+	if err := copyDir(pkg.PluginDir, dir); err != nil {
+		_ = os.RemoveAll(dir)
+		return domain.PluginVersion{}, err
+	}
+	target := domain.PluginVersion{
+		Category:        category,
+		PluginID:        pluginID,
+		Version:         version,
+		SignatureStatus: pkg.SignatureStatus,
+		Status:          domain.PluginVersionInactive,
+	}
+	if err := m.versions.UpsertPluginVersion(ctx, &target); err != nil {
+		return domain.PluginVersion{}, err
+	}
+	return m.activateLocked(ctx, target, current.Version)
+}
+
+// Rollback switches a plugin back to a version kept on disk.
+func (m *Manager) Rollback(ctx context.Context, category, pluginID, version string) (domain.PluginVersion, error) {
+	if m.repo == nil || m.versions == nil {
+		return domain.PluginVersion{}, fmt.Errorf("plugin repo missing")
+	}
+	category = strings.TrimSpace(category)
+	pluginID = strings.TrimSpace(pluginID)
+	version = strings.TrimSpace(version)
+	if !validVersionDirName(version) {
+		return domain.PluginVersion{}, fmt.Errorf("invalid version")
+	}
+
+	lock := m.pluginLock(category, pluginID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	current, err := ReadManifest(m.PluginDir(category, pluginID))
+	if err != nil {
+		return domain.PluginVersion{}, fmt.Errorf("plugin not installed")
+	}
+	if current.Version == version {
+		return domain.PluginVersion{}, fmt.Errorf("plugin version already active")
+	}
+	dir := m.versionDir(category, pluginID, version)
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		return domain.PluginVersion{}, domain.ErrNotFound
+	}
+	if manifest.PluginID != pluginID || manifest.Version != version {
+		return domain.PluginVersion{}, fmt.Errorf("manifest version mismatch")
+	}
+	// Files kept on disk are verified again in case they changed since.
+	sigStatus, err := VerifySignature(dir, m.officialKeys)
+	if err != nil {
+		return domain.PluginVersion{}, err
+	}
+	if err := m.archiveActive(ctx, category, pluginID, current.Version); err != nil {
+		return domain.PluginVersion{}, err
+	}
+	target, err := m.versions.GetPluginVersion(ctx, category, pluginID, version)
+	if err != nil {
+		target = domain.PluginVersion{Category: category, PluginID: pluginID, Version: version}
+	}
+	target.SignatureStatus = sigStatus
+	return m.activateLocked(ctx, target, current.Version)
+}
+
+// archiveActive keeps a copy of the active version so it can be rolled back to.
+func (m *Manager) archiveActive(ctx context.Context, category, pluginID, version string) error {
+	if !validVersionDirName(version) {
+		return fmt.Errorf("invalid manifest version")
+	}
+	dir := m.versionDir(category, pluginID, version)
+	if !fileExists(dir) {
+		if err := copyDir(m.PluginDir(category, pluginID), dir); err != nil {
+			_ = os.RemoveAll(dir)
+			return err
+		}
+	}
+	if _, err := m.versions.GetPluginVersion(ctx, category, pluginID, version); err == nil {
+		return nil
+	}
+	sigStatus, _ := VerifySignature(dir, m.officialKeys)
+	return m.versions.UpsertPluginVersion(ctx, &domain.PluginVersion{
+		Category:        category,
+		PluginID:        pluginID,
+		Version:         version,
+		SignatureStatus: sigStatus,
+		Status:          domain.PluginVersionActive,
+	})
+}
+
+// activateLocked makes target the active version. Enabled instances are
+// validated against the new binary, stopped, restarted on it and health
+// checked; on any failure the previous directory is restored and the
+// instances are restarted on it. The caller holds the plugin lock.
+func (m *Manager) activateLocked(ctx context.Context, target domain.PluginVersion, previousVersion string) (domain.PluginVersion, error) {
+	category, pluginID := target.Category, target.PluginID
+	installations, err := m.repo.ListPluginInstallations(ctx)
+	if err != nil {
+		return domain.PluginVersion{}, err
+	}
+	var all []domain.PluginInstallation
+	var enabled []pluginInstanceConfig
+	for _, inst := range installations {
+		if inst.Category != category || inst.PluginID != pluginID {
+			continue
+		}
+		all = append(all, inst)
+		if !inst.Enabled {
+			continue
+		}
+		cfg, err := m.decryptConfig(inst.ConfigCipher)
+		if err != nil {
+			return domain.PluginVersion{}, err
+		}
+		if strings.TrimSpace(cfg) == "" {
+			cfg = "{}"
+		}
+		enabled = append(enabled, pluginInstanceConfig{instanceID: inst.InstanceID, configJSON: cfg})
+	}
+
+	src := m.versionDir(category, pluginID, target.Version)
+	if !isAutomationCategory(category) {
+		for _, inst := range enabled {
+			if err := m.validateConfigAt(ctx, src, category, inst.configJSON); err != nil {
+				return m.failActivation(ctx, target, inst.instanceID, err)
+			}
+		}
+	}
+
+	activeDir := m.PluginDir(category, pluginID)
+	stagingDir := filepath.Join(m.baseDir, category, "."+pluginID+".staging")
+	previousDir := filepath.Join(m.baseDir, category, "."+pluginID+".previous")
+	_ = os.RemoveAll(stagingDir)
+	_ = os.RemoveAll(previousDir)
+	if err := copyDir(src, stagingDir); err != nil {
+		_ = os.RemoveAll(stagingDir)
+		return m.failActivation(ctx, target, "", err)
+	}
+
+	// Drain the old processes before their files move.
+	for _, inst := range all {
+		m.runtime.Stop(category, pluginID, inst.InstanceID)
+	}
+	if err := os.Rename(activeDir, previousDir); err != nil {
+		_ = os.RemoveAll(stagingDir)
+		_ = m.startAndCheck(ctx, category, pluginID, enabled)
+		return m.failActivation(ctx, target, "", err)
+	}
+	if err := os.Rename(stagingDir, activeDir); err != nil {
+		_ = os.Rename(previousDir, activeDir)
+		_ = os.RemoveAll(stagingDir)
+		_ = m.startAndCheck(ctx, category, pluginID, enabled)
+		return m.failActivation(ctx, target, "", err)
+	}
+	if err := m.startAndCheck(ctx, category, pluginID, enabled); err != nil {
+		for _, inst := range all {
+			m.runtime.Stop(category, pluginID, inst.InstanceID)
+		}
+		_ = os.RemoveAll(activeDir)
+		_ = os.Rename(previousDir, activeDir)
+		_ = m.startAndCheck(ctx, category, pluginID, enabled)
+		return m.failActivation(ctx, target, "", err)
+	}
+	_ = os.RemoveAll(previousDir)
+
+	if prev, err := m.versions.GetPluginVersion(ctx, category, pluginID, previousVersion); err == nil {
+		prev.Status = domain.PluginVersionInactive
+		_ = m.versions.UpsertPluginVersion(ctx, &prev)
+	}
+	now := time.Now()
+	target.Status = domain.PluginVersionActive
+	target.LastError = ""
+	target.ActivatedAt = &now
+	if err := m.versions.UpsertPluginVersion(ctx, &target); err != nil {
+		return domain.PluginVersion{}, err
+	}
+	for _, inst := range all {
+		inst.SignatureStatus = target.SignatureStatus
+		_ = m.repo.UpsertPluginInstallation(ctx, &inst)
+	}
+	return m.versions.GetPluginVersion(ctx, category, pluginID, target.Version)
+}
+
+func (m *Manager) failActivation(ctx context.Context, target domain.PluginVersion, instanceID string, err error) (domain.PluginVersion, error) {
+	target.Status = domain.PluginVersionFailed
+	target.LastError = err.Error()
+	if instanceID != "" {
+		target.LastError = "instance " + instanceID + ": " + target.LastError
+	}
+	_ = m.versions.UpsertPluginVersion(ctx, &target)
+	return domain.PluginVersion{}, err
+}
+
+// startAndCheck starts each instance and requires a passing Health call.
+func (m *Manager) startAndCheck(ctx context.Context, category, pluginID string, instances []pluginInstanceConfig) error {
+	for _, inst := range instances {
+		if _, err := m.runtime.Start(ctx, category, pluginID, inst.instanceID, inst.configJSON); err != nil {
+			return fmt.Errorf("%s", "instance "+inst.instanceID+": "+err.Error())
+		}
+		if err := m.checkHealth(ctx, category, pluginID, inst.instanceID); err != nil {
+			return fmt.Errorf("%s", "instance "+inst.instanceID+": "+err.Error())
+		}
+	}
+	return nil
+}
+
+func (m *Manager) checkHealth(ctx context.Context, category, pluginID, instanceID string) error {
+	rp, ok := m.runtime.GetRunning(category, pluginID, instanceID)
+	if !ok || rp.core == nil {
+		return fmt.Errorf("plugin instance not running")
+	}
+	cctx, cancel := context.WithTimeout(ctx, upgradeHealthTimeout)
+	defer cancel()
+	resp, err := rp.core.Health(cctx, &pluginv1.HealthCheckRequest{InstanceId: instanceID})
+	if err != nil {
+		return err
+	}
+	rp.mu.Lock()
+	rp.lastHealth = time.Now()
+	rp.health = resp
+	rp.mu.Unlock()
+	switch resp.GetStatus() {
+	case pluginv1.HealthStatus_HEALTH_STATUS_OK, pluginv1.HealthStatus_HEALTH_STATUS_DEGRADED:
+		return nil
+	}
+	msg := strings.TrimSpace(resp.GetMessage())
+	if msg == "" {
+		msg = resp.GetStatus().String()
+	}
+	return fmt.Errorf("%s", "health check failed: "+msg)
+}
+
+// removePluginFiles deletes the active directory and every kept version.
+func (m *Manager) removePluginFiles(ctx context.Context, category, pluginID string) {
+	_ = os.RemoveAll(m.PluginDir(category, pluginID))
+	_ = os.RemoveAll(m.versionsDir(category, pluginID))
+	if m.versions != nil {
+		_ = m.versions.DeletePluginVersions(ctx, category, pluginID)
+	}
+}
+
+// recordInstalledVersion starts the version history of a fresh install.
+func (m *Manager) recordInstalledVersion(ctx context.Context, res InstallResult) {
+	if m.versions == nil {
+		return
+	}
+	now := time.Now()
+	_ = m.versions.UpsertPluginVersion(ctx, &domain.PluginVersion{
+		Category:        res.Category,
+		PluginID:        res.PluginID,
+		Version:         res.Manifest.Version,
+		SignatureStatus: res.SignatureStatus,
+		Status:          domain.PluginVersionActive,
+		ActivatedAt:     &now,
+	})
+}
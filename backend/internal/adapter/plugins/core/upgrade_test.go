@@ -0,0 +1,195 @@
+package plugins
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+type memPluginInstallations struct {
+	items []domain.PluginInstallation
+}
+
+func (m *memPluginInstallations) UpsertPluginInstallation(_ context.Context, inst *domain.PluginInstallation) error {
+	for i, it := range m.items {
+		if it.Category == inst.Category && it.PluginID == inst.PluginID && it.InstanceID == inst.InstanceID {
+			m.items[i] = *inst
+			return nil
+		}
+	}
+	m.items = append(m.items, *inst)
+	return nil
+}
+
+func (m *memPluginInstallations) GetPluginInstallation(_ context.Context, category, pluginID, instanceID string) (domain.PluginInstallation, error) {
+	for _, it := range m.items {
+		if it.Category == category && it.PluginID == pluginID && it.InstanceID == instanceID {
+			return it, nil
+		}
+	}
+	return domain.PluginInstallation{}, appshared.ErrNotFound
+}
+
+func (m *memPluginInstallations) ListPluginInstallations(context.Context) ([]domain.PluginInstallation, error) {
+	return append([]domain.PluginInstallation(nil), m.items...), nil
+}
+
+func (m *memPluginInstallations) DeletePluginInstallation(_ context.Context, category, pluginID, instanceID string) error {
+	out := m.items[:0]
+	for _, it := range m.items {
+		if it.Category != category || it.PluginID != pluginID || it.InstanceID != instanceID {
+			out = append(out, it)
+		}
+	}
+	m.items = out
+	return nil
+}
+
+type memPluginVersions struct {
+	items map[string]domain.PluginVersion
+}
+
+func (m *memPluginVersions) UpsertPluginVersion(_ context.Context, v *domain.PluginVersion) error {
+	m.items[v.Category+"/"+v.PluginID+"/"+v.Version] = *v
+	return nil
+}
+
+func (m *memPluginVersions) GetPluginVersion(_ context.Context, category, pluginID, version string) (domain.PluginVersion, error) {
+	v, ok := m.items[category+"/"+pluginID+"/"+version]
+	if !ok {
+		return domain.PluginVersion{}, appshared.ErrNotFound
+	}
+	return v, nil
+}
+
+func (m *memPluginVersions) ListPluginVersions(_ context.Context, category, pluginID string) ([]domain.PluginVersion, error) {
+	var out []domain.PluginVersion
+	for _, v := range m.items {
+		if v.Category == category && v.PluginID == pluginID {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+func (m *memPluginVersions) DeletePluginVersions(_ context.Context, category, pluginID string) error {
+	for k, v := range m.items {
+		if v.Category == category && v.PluginID == pluginID {
+			delete(m.items, k)
+		}
+	}
+	return nil
+}
+
+func pluginPackage(t *testing.T, pluginID, version string) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	files := map[string]string{
+		"sms/" + pluginID + "/manifest.json": `{"plugin_id":"` + pluginID + `","name":"Demo","version":"` + version + `"}`,
+		"sms/" + pluginID + "/plugin":        "#!/bin/sh\n",
+	}
+	for name, body := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("zip entry: %v", err)
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Fatalf("zip write: %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("zip close: %v", err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func versionStatuses(t *testing.T, m *Manager) map[string]domain.PluginVersionStatus {
+	t.Helper()
+	items, err := m.ListVersions(context.Background(), "sms", "demo")
+	if err != nil {
+		t.Fatalf("list versions: %v", err)
+	}
+	out := map[string]domain.PluginVersionStatus{}
+	for _, it := range items {
+		out[it.Version] = it.Status
+	}
+	return out
+}
+
+func activeVersion(t *testing.T, m *Manager) string {
+	t.Helper()
+	manifest, err := ReadManifest(m.PluginDir("sms", "demo"))
+	if err != nil {
+		t.Fatalf("read active manifest: %v", err)
+	}
+	return manifest.Version
+}
+
+func TestUpgradeKeepsVersionsSideBySideAndRollsBack(t *testing.T) {
+	ctx := context.Background()
+	base := t.TempDir()
+	installs := &memPluginInstallations{}
+	m := NewManager(base, installs, nil, nil)
+	m.SetVersionRepository(&memPluginVersions{items: map[string]domain.PluginVersion{}})
+
+	if _, err := m.Install(ctx, "demo.zip", pluginPackage(t, "demo", "1.0.0")); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	if _, err := m.Upgrade(ctx, "sms", "demo", "demo.zip", pluginPackage(t, "demo", "1.1.0"), false); err != domain.ErrAdminPasswordRequiredForUntrustedPlugin {
+		t.Fatalf("expected unsigned upgrade to need confirmation, got %v", err)
+	}
+	if _, err := m.Upgrade(ctx, "sms", "demo", "other.zip", pluginPackage(t, "other", "1.1.0"), true); err == nil {
+		t.Fatalf("expected a package of another plugin to be rejected")
+	}
+	if got := activeVersion(t, m); got != "1.0.0" {
+		t.Fatalf("rejected upgrades must not switch versions, active %s", got)
+	}
+
+	v, err := m.Upgrade(ctx, "sms", "demo", "demo.zip", pluginPackage(t, "demo", "1.1.0"), true)
+	if err != nil {
+		t.Fatalf("upgrade: %v", err)
+	}
+	if v.Version != "1.1.0" || v.Status != domain.PluginVersionActive || v.ActivatedAt == nil || v.SignatureStatus != domain.PluginSignatureUnsigned {
+		t.Fatalf("unexpected upgraded version: %+v", v)
+	}
+	if got := activeVersion(t, m); got != "1.1.0" {
+		t.Fatalf("expected 1.1.0 to be active, got %s", got)
+	}
+	if got := versionStatuses(t, m); got["1.1.0"] != domain.PluginVersionActive || got["1.0.0"] != domain.PluginVersionInactive {
+		t.Fatalf("unexpected version history: %+v", got)
+	}
+	found, err := scanDiskPlugins(base)
+	if err != nil || len(found) != 1 {
+		t.Fatalf("kept versions must stay out of discovery, found %+v (%v)", found, err)
+	}
+	if _, err := m.Upgrade(ctx, "sms", "demo", "demo.zip", pluginPackage(t, "demo", "1.1.0"), true); err == nil {
+		t.Fatalf("expected upgrading to the active version to fail")
+	}
+
+	if _, err := m.Rollback(ctx, "sms", "demo", "0.9.0"); err != domain.ErrNotFound {
+		t.Fatalf("expected unknown version to be not found, got %v", err)
+	}
+	if _, err := m.Rollback(ctx, "sms", "demo", "1.0.0"); err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+	if got := activeVersion(t, m); got != "1.0.0" {
+		t.Fatalf("expected 1.0.0 after rollback, got %s", got)
+	}
+	if got := versionStatuses(t, m); got["1.0.0"] != domain.PluginVersionActive || got["1.1.0"] != domain.PluginVersionInactive {
+		t.Fatalf("unexpected version history after rollback: %+v", got)
+	}
+
+	if err := m.Uninstall(ctx, "sms", "demo"); err != nil {
+		t.Fatalf("uninstall: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(base, versionsDirName, "sms", "demo")); !os.IsNotExist(err) {
+		t.Fatalf("expected kept versions to be removed on uninstall, stat err %v", err)
+	}
+}
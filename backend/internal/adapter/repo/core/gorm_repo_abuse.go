@@ -0,0 +1,241 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+var activeAbuseCaseStatuses = []string{
+	string(domain.AbuseCaseStatusOpen),
+	string(domain.AbuseCaseStatusInvestigating),
+	string(domain.AbuseCaseStatusAwaitingUser),
+}
+
+func (r *GormRepo) CreateAbuseCase(ctx context.Context, c *domain.AbuseCase) error {
+	row := toAbuseCaseRow(*c)
+	if err := r.gdb.WithContext(ctx).Create(&row).Error; err != nil {
+		return err
+	}
+	c.ID = row.ID
+	c.CreatedAt = row.CreatedAt
+	c.UpdatedAt = row.UpdatedAt
+	return nil
+}
+
+func (r *GormRepo) GetAbuseCase(ctx context.Context, id int64) (domain.AbuseCase, error) {
+	var row abuseCaseRow
+	if err := r.gdb.WithContext(ctx).First(&row, id).Error; err != nil {
+		return domain.AbuseCase{}, r.ensure(err)
+	}
+	return fromAbuseCaseRow(row), nil
+}
+
+func (r *GormRepo) ListAbuseCases(ctx context.Context, filter appshared.AbuseCaseFilter) ([]domain.AbuseCase, int, error) {
+	q := r.gdb.WithContext(ctx).Model(&abuseCaseRow{})
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	} else if filter.Active {
+		q = q.Where("status IN ?", activeAbuseCaseStatuses)
+	}
+	if filter.TargetType != "" && filter.TargetID > 0 {
+		sub := r.gdb.Model(&abuseCaseLinkRow{}).Select("case_id").Where("target_type = ? AND target_id = ?", filter.TargetType, filter.TargetID)
+		q = q.Where("id IN (?)", sub)
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	order := "id DESC"
+	if filter.Active {
+		order = "resolve_due_at ASC, id ASC"
+	}
+	var rows []abuseCaseRow
+	if err := q.Order(order).Limit(limit).Offset(filter.Offset).Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	out := make([]domain.AbuseCase, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, fromAbuseCaseRow(row))
+	}
+	return out, int(total), nil
+}
+
+func (r *GormRepo) UpdateAbuseCase(ctx context.Context, c domain.AbuseCase) error {
+	return r.gdb.WithContext(ctx).Model(&abuseCaseRow{}).Where("id = ?", c.ID).Updates(map[string]any{
+		"category":        c.Category,
+		"subject":         c.Subject,
+		"description":     c.Description,
+		"status":          string(c.Status),
+		"assignee_id":     c.AssigneeID,
+		"resolution":      c.Resolution,
+		"response_due_at": c.ResponseDueAt,
+		"acknowledged_at": c.AcknowledgedAt,
+		"warned_at":       c.WarnedAt,
+		"closed_at":       c.ClosedAt,
+	}).Error
+}
+
+func (r *GormRepo) AddAbuseCaseLink(ctx context.Context, link *domain.AbuseCaseLink) error {
+	var existing abuseCaseLinkRow
+	err := r.gdb.WithContext(ctx).Where("case_id = ? AND target_type = ? AND target_id = ?", link.CaseID, link.TargetType, link.TargetID).First(&existing).Error
+	if err == nil {
+		*link = fromAbuseCaseLinkRow(existing)
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	row := abuseCaseLinkRow{CaseID: link.CaseID, TargetType: link.TargetType, TargetID: link.TargetID, TicketID: link.TicketID}
+	if err := r.gdb.WithContext(ctx).Create(&row).Error; err != nil {
+		return err
+	}
+	link.ID = row.ID
+	link.CreatedAt = row.CreatedAt
+	return nil
+}
+
+func (r *GormRepo) UpdateAbuseCaseLinkTicket(ctx context.Context, linkID, ticketID int64) error {
+	return r.gdb.WithContext(ctx).Model(&abuseCaseLinkRow{}).Where("id = ?", linkID).Update("ticket_id", ticketID).Error
+}
+
+func (r *GormRepo) ListAbuseCaseLinks(ctx context.Context, caseID int64) ([]domain.AbuseCaseLink, error) {
+	var rows []abuseCaseLinkRow
+	if err := r.gdb.WithContext(ctx).Where("case_id = ?", caseID).Order("id ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]domain.AbuseCaseLink, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, fromAbuseCaseLinkRow(row))
+	}
+	return out, nil
+}
+
+func (r *GormRepo) AddAbuseEvidence(ctx context.Context, evidence *domain.AbuseEvidence) error {
+	row := abuseEvidenceRow{
+		CaseID:   evidence.CaseID,
+		Kind:     evidence.Kind,
+		Content:  evidence.Content,
+		UploadID: evidence.UploadID,
+		AddedBy:  evidence.AddedBy,
+	}
+	if err := r.gdb.WithContext(ctx).Create(&row).Error; err != nil {
+		return err
+	}
+	evidence.ID = row.ID
+	evidence.CreatedAt = row.CreatedAt
+	return nil
+}
+
+func (r *GormRepo) ListAbuseEvidence(ctx context.Context, caseID int64) ([]domain.AbuseEvidence, error) {
+	var rows []abuseEvidenceRow
+	if err := r.gdb.WithContext(ctx).Where("case_id = ?", caseID).Order("id ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]domain.AbuseEvidence, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, domain.AbuseEvidence{
+			ID:        row.ID,
+			CaseID:    row.CaseID,
+			Kind:      row.Kind,
+			Content:   row.Content,
+			UploadID:  row.UploadID,
+			AddedBy:   row.AddedBy,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	return out, nil
+}
+
+func (r *GormRepo) AddAbuseCaseEvent(ctx context.Context, event *domain.AbuseCaseEvent) error {
+	row := abuseCaseEventRow{CaseID: event.CaseID, AdminID: event.AdminID, Type: event.Type, DetailJSON: event.DetailJSON}
+	if row.DetailJSON == "" {
+		row.DetailJSON = "{}"
+	}
+	if err := r.gdb.WithContext(ctx).Create(&row).Error; err != nil {
+		return err
+	}
+	event.ID = row.ID
+	event.CreatedAt = row.CreatedAt
+	return nil
+}
+
+func (r *GormRepo) ListAbuseCaseEvents(ctx context.Context, caseID int64) ([]domain.AbuseCaseEvent, error) {
+	var rows []abuseCaseEventRow
+	if err := r.gdb.WithContext(ctx).Where("case_id = ?", caseID).Order("id ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]domain.AbuseCaseEvent, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, domain.AbuseCaseEvent{
+			ID:         row.ID,
+			CaseID:     row.CaseID,
+			AdminID:    row.AdminID,
+			Type:       row.Type,
+			DetailJSON: row.DetailJSON,
+			CreatedAt:  row.CreatedAt,
+		})
+	}
+	return out, nil
+}
+
+func toAbuseCaseRow(c domain.AbuseCase) abuseCaseRow {
+	return abuseCaseRow{
+		ID:             c.ID,
+		Source:         c.Source,
+		Category:       c.Category,
+		Reporter:       c.Reporter,
+		Subject:        c.Subject,
+		Description:    c.Description,
+		Status:         string(c.Status),
+		AssigneeID:     c.AssigneeID,
+		Resolution:     c.Resolution,
+		AckDueAt:       c.AckDueAt,
+		ResolveDueAt:   c.ResolveDueAt,
+		ResponseDueAt:  c.ResponseDueAt,
+		AcknowledgedAt: c.AcknowledgedAt,
+		WarnedAt:       c.WarnedAt,
+		ClosedAt:       c.ClosedAt,
+	}
+}
+
+func fromAbuseCaseRow(row abuseCaseRow) domain.AbuseCase {
+	return domain.AbuseCase{
+		ID:             row.ID,
+		Source:         row.Source,
+		Category:       row.Category,
+		Reporter:       row.Reporter,
+		Subject:        row.Subject,
+		Description:    row.Description,
+		Status:         domain.AbuseCaseStatus(row.Status),
+		AssigneeID:     row.AssigneeID,
+		Resolution:     row.Resolution,
+		AckDueAt:       row.AckDueAt,
+		ResolveDueAt:   row.ResolveDueAt,
+		ResponseDueAt:  row.ResponseDueAt,
+		AcknowledgedAt: row.AcknowledgedAt,
+		WarnedAt:       row.WarnedAt,
+		ClosedAt:       row.ClosedAt,
+		CreatedAt:      row.CreatedAt,
+		UpdatedAt:      row.UpdatedAt,
+	}
+}
+
+func fromAbuseCaseLinkRow(row abuseCaseLinkRow) domain.AbuseCaseLink {
+	return domain.AbuseCaseLink{
+		ID:         row.ID,
+		CaseID:     row.CaseID,
+		TargetType: row.TargetType,
+		TargetID:   row.TargetID,
+		TicketID:   row.TicketID,
+		CreatedAt:  row.CreatedAt,
+	}
+}
@@ -0,0 +1,123 @@
+package repo
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"xiaoheiplay/internal/domain"
+)
+
+func (r *GormRepo) ListAutomationBackends(ctx context.Context, goodsTypeID int64) ([]domain.AutomationBackend, error) {
+	var rows []automationBackendRow
+	q := r.gdb.WithContext(ctx).Order("goods_type_id, id")
+	if goodsTypeID > 0 {
+		q = q.Where("goods_type_id = ?", goodsTypeID)
+	}
+	if err := q.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]domain.AutomationBackend, 0, len(rows))
+	for _, row := range rows {
+		backend := fromAutomationBackendRow(row)
+		active, err := r.countBackendHosts(ctx, row.PluginID, row.InstanceID)
+		if err != nil {
+			return nil, err
+		}
+		backend.ActiveHosts = active
+		out = append(out, backend)
+	}
+	return out, nil
+}
+
+func (r *GormRepo) GetAutomationBackend(ctx context.Context, id int64) (domain.AutomationBackend, error) {
+	var row automationBackendRow
+	if err := r.gdb.WithContext(ctx).Where("id = ?", id).First(&row).Error; err != nil {
+		return domain.AutomationBackend{}, r.ensure(err)
+	}
+	backend := fromAutomationBackendRow(row)
+	active, err := r.countBackendHosts(ctx, row.PluginID, row.InstanceID)
+	if err != nil {
+		return domain.AutomationBackend{}, err
+	}
+	backend.ActiveHosts = active
+	return backend, nil
+}
+
+func (r *GormRepo) CreateAutomationBackend(ctx context.Context, backend *domain.AutomationBackend) error {
+	health := strings.TrimSpace(string(backend.Health))
+	if health == "" {
+		health = string(domain.AutomationBackendHealthy)
+	}
+	row := automationBackendRow{
+		GoodsTypeID:         backend.GoodsTypeID,
+		PluginID:            backend.PluginID,
+		InstanceID:          backend.InstanceID,
+		Weight:              backend.Weight,
+		Capacity:            backend.Capacity,
+		Enabled:             boolToInt(backend.Enabled),
+		Health:              health,
+		ConsecutiveFailures: backend.ConsecutiveFailures,
+		LastError:           backend.LastError,
+		LastCheckedAt:       backend.LastCheckedAt,
+	}
+	if err := r.gdb.WithContext(ctx).Create(&row).Error; err != nil {
+		return err
+	}
+	backend.ID = row.ID
+	backend.Health = domain.AutomationBackendHealth(row.Health)
+	backend.CreatedAt = row.CreatedAt
+	backend.UpdatedAt = row.UpdatedAt
+	return nil
+}
+
+func (r *GormRepo) UpdateAutomationBackend(ctx context.Context, backend domain.AutomationBackend) error {
+	return r.gdb.WithContext(ctx).Model(&automationBackendRow{}).Where("id = ?", backend.ID).Updates(map[string]any{
+		"weight":     backend.Weight,
+		"capacity":   backend.Capacity,
+		"enabled":    boolToInt(backend.Enabled),
+		"updated_at": time.Now(),
+	}).Error
+}
+
+func (r *GormRepo) UpdateAutomationBackendHealth(ctx context.Context, backend domain.AutomationBackend) error {
+	return r.gdb.WithContext(ctx).Model(&automationBackendRow{}).Where("id = ?", backend.ID).Updates(map[string]any{
+		"health":               string(backend.Health),
+		"consecutive_failures": backend.ConsecutiveFailures,
+		"last_error":           backend.LastError,
+		"last_checked_at":      backend.LastCheckedAt,
+		"updated_at":           time.Now(),
+	}).Error
+}
+
+func (r *GormRepo) DeleteAutomationBackend(ctx context.Context, id int64) error {
+	return r.gdb.WithContext(ctx).Delete(&automationBackendRow{}, id).Error
+}
+
+func (r *GormRepo) countBackendHosts(ctx context.Context, pluginID, instanceID string) (int, error) {
+	var total int64
+	if err := r.gdb.WithContext(ctx).Model(&vpsInstanceRow{}).
+		Where("backend_plugin_id = ? AND backend_instance_id = ?", pluginID, instanceID).
+		Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return int(total), nil
+}
+
+func fromAutomationBackendRow(row automationBackendRow) domain.AutomationBackend {
+	return domain.AutomationBackend{
+		ID:                  row.ID,
+		GoodsTypeID:         row.GoodsTypeID,
+		PluginID:            row.PluginID,
+		InstanceID:          row.InstanceID,
+		Weight:              row.Weight,
+		Capacity:            row.Capacity,
+		Enabled:             row.Enabled == 1,
+		Health:              domain.AutomationBackendHealth(row.Health),
+		ConsecutiveFailures: row.ConsecutiveFailures,
+		LastError:           row.LastError,
+		LastCheckedAt:       row.LastCheckedAt,
+		CreatedAt:           row.CreatedAt,
+		UpdatedAt:           row.UpdatedAt,
+	}
+}
@@ -180,6 +180,10 @@ func (r *GormRepo) ListPlanGroups(ctx context.Context) ([]domain.PlanGroup, erro
 			AddBWMin:          row.AddBWMin,
 			AddBWMax:          row.AddBWMax,
 			AddBWStep:         row.AddBWStep,
+			UnitIPv4:          row.UnitIPv4,
+			UnitIPv6:          row.UnitIPv6,
+			AddIPv4Max:        row.AddIPv4Max,
+			AddIPv6Max:        row.AddIPv6Max,
 			Active:            row.Active == 1,
 			Visible:           row.Visible == 1,
 			CapacityRemaining: row.CapacityRemaining,
@@ -213,6 +217,10 @@ func (r *GormRepo) CreatePlanGroup(ctx context.Context, plan *domain.PlanGroup)
 		AddBWMin:          plan.AddBWMin,
 		AddBWMax:          plan.AddBWMax,
 		AddBWStep:         plan.AddBWStep,
+		UnitIPv4:          plan.UnitIPv4,
+		UnitIPv6:          plan.UnitIPv6,
+		AddIPv4Max:        plan.AddIPv4Max,
+		AddIPv6Max:        plan.AddIPv6Max,
 		Active:            boolToInt(plan.Active),
 		Visible:           boolToInt(plan.Visible),
 		CapacityRemaining: plan.CapacityRemaining,
@@ -249,6 +257,10 @@ func (r *GormRepo) UpdatePlanGroup(ctx context.Context, plan domain.PlanGroup) e
 		"add_bw_min":         plan.AddBWMin,
 		"add_bw_max":         plan.AddBWMax,
 		"add_bw_step":        plan.AddBWStep,
+		"unit_ipv4":          plan.UnitIPv4,
+		"unit_ipv6":          plan.UnitIPv6,
+		"add_ipv4_max":       plan.AddIPv4Max,
+		"add_ipv6_max":       plan.AddIPv6Max,
 		"active":             boolToInt(plan.Active),
 		"visible":            boolToInt(plan.Visible),
 		"capacity_remaining": plan.CapacityRemaining,
@@ -410,6 +422,10 @@ func (r *GormRepo) GetPlanGroup(ctx context.Context, id int64) (domain.PlanGroup
 		AddBWMin:          row.AddBWMin,
 		AddBWMax:          row.AddBWMax,
 		AddBWStep:         row.AddBWStep,
+		UnitIPv4:          row.UnitIPv4,
+		UnitIPv6:          row.UnitIPv6,
+		AddIPv4Max:        row.AddIPv4Max,
+		AddIPv6Max:        row.AddIPv6Max,
 		Active:            row.Active == 1,
 		Visible:           row.Visible == 1,
 		CapacityRemaining: row.CapacityRemaining,
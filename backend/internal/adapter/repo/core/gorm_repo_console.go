@@ -0,0 +1,129 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+func (r *GormRepo) CreateConsoleSession(ctx context.Context, session *domain.ConsoleSession) error {
+	row := consoleSessionRow{
+		VPSID:     session.VPSID,
+		UserID:    session.UserID,
+		Status:    session.Status,
+		ClientIP:  session.ClientIP,
+		ExpiresAt: session.ExpiresAt,
+	}
+	if err := r.gdb.WithContext(ctx).Create(&row).Error; err != nil {
+		return err
+	}
+	session.ID = row.ID
+	session.CreatedAt = row.CreatedAt
+	return nil
+}
+
+func (r *GormRepo) GetConsoleSession(ctx context.Context, id int64) (domain.ConsoleSession, error) {
+	var row consoleSessionRow
+	if err := r.gdb.WithContext(ctx).Where("id = ?", id).First(&row).Error; err != nil {
+		return domain.ConsoleSession{}, r.ensure(err)
+	}
+	return fromConsoleSessionRow(row), nil
+}
+
+// ActivateConsoleSession moves a pending session to active. It reports false
+// when the session was already redeemed, ended or its ticket has expired, so
+// a ticket can be used only once.
+func (r *GormRepo) ActivateConsoleSession(ctx context.Context, id int64, upstreamHost string, startedAt time.Time) (bool, error) {
+	res := r.gdb.WithContext(ctx).Model(&consoleSessionRow{}).
+		Where("id = ? AND status = ? AND expires_at > ?", id, "pending", startedAt).
+		Updates(map[string]any{
+			"status":        "active",
+			"upstream_host": upstreamHost,
+			"started_at":    startedAt,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *GormRepo) EndConsoleSession(ctx context.Context, id int64, reason string, clientBytes, upstreamBytes int64, endedAt time.Time) error {
+	return r.gdb.WithContext(ctx).Model(&consoleSessionRow{}).Where("id = ? AND status <> ?", id, "ended").Updates(map[string]any{
+		"status":         "ended",
+		"end_reason":     reason,
+		"client_bytes":   clientBytes,
+		"upstream_bytes": upstreamBytes,
+		"ended_at":       endedAt,
+	}).Error
+}
+
+// CountOpenConsoleSessions counts active sessions plus pending tickets that
+// can still be redeemed.
+func (r *GormRepo) CountOpenConsoleSessions(ctx context.Context, userID int64, now time.Time) (int, error) {
+	var total int64
+	err := r.gdb.WithContext(ctx).Model(&consoleSessionRow{}).
+		Where("user_id = ? AND (status = ? OR (status = ? AND expires_at > ?))", userID, "active", "pending", now).
+		Count(&total).Error
+	return int(total), err
+}
+
+func (r *GormRepo) ListConsoleSessions(ctx context.Context, filter appshared.ConsoleSessionFilter) ([]domain.ConsoleSession, int, error) {
+	q := r.gdb.WithContext(ctx).Model(&consoleSessionRow{})
+	if filter.VPSID > 0 {
+		q = q.Where("vps_id = ?", filter.VPSID)
+	}
+	if filter.UserID > 0 {
+		q = q.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Open {
+		q = q.Where("status IN ?", []string{"pending", "active"})
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	var rows []consoleSessionRow
+	if err := q.Order("id DESC").Limit(limit).Offset(filter.Offset).Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	out := make([]domain.ConsoleSession, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, fromConsoleSessionRow(row))
+	}
+	return out, int(total), nil
+}
+
+// EndActiveConsoleSessions closes every session still marked active. It is
+// used at startup, when no relay can be running.
+func (r *GormRepo) EndActiveConsoleSessions(ctx context.Context, reason string, endedAt time.Time) (int64, error) {
+	res := r.gdb.WithContext(ctx).Model(&consoleSessionRow{}).Where("status = ?", "active").Updates(map[string]any{
+		"status":     "ended",
+		"end_reason": reason,
+		"ended_at":   endedAt,
+	})
+	return res.RowsAffected, res.Error
+}
+
+func fromConsoleSessionRow(row consoleSessionRow) domain.ConsoleSession {
+	return domain.ConsoleSession{
+		ID:            row.ID,
+		VPSID:         row.VPSID,
+		UserID:        row.UserID,
+		Status:        row.Status,
+		ClientIP:      row.ClientIP,
+		UpstreamHost:  row.UpstreamHost,
+		ExpiresAt:     row.ExpiresAt,
+		StartedAt:     row.StartedAt,
+		EndedAt:       row.EndedAt,
+		EndReason:     row.EndReason,
+		ClientBytes:   row.ClientBytes,
+		UpstreamBytes: row.UpstreamBytes,
+		CreatedAt:     row.CreatedAt,
+	}
+}
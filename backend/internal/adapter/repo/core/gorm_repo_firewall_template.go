@@ -0,0 +1,196 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"xiaoheiplay/internal/domain"
+)
+
+func (r *GormRepo) CreateFirewallTemplate(ctx context.Context, tpl *domain.FirewallTemplate) error {
+	row := toFirewallTemplateRow(*tpl)
+	row.ID = 0
+	if err := r.gdb.WithContext(ctx).Create(&row).Error; err != nil {
+		return err
+	}
+	tpl.ID = row.ID
+	tpl.CreatedAt = row.CreatedAt
+	tpl.UpdatedAt = row.UpdatedAt
+	return nil
+}
+
+func (r *GormRepo) GetFirewallTemplate(ctx context.Context, id int64) (domain.FirewallTemplate, error) {
+	var row firewallTemplateRow
+	if err := r.gdb.WithContext(ctx).Where("id = ?", id).First(&row).Error; err != nil {
+		return domain.FirewallTemplate{}, r.ensure(err)
+	}
+	return fromFirewallTemplateRow(row), nil
+}
+
+func (r *GormRepo) UpdateFirewallTemplate(ctx context.Context, tpl domain.FirewallTemplate) error {
+	row := toFirewallTemplateRow(tpl)
+	res := r.gdb.WithContext(ctx).Model(&firewallTemplateRow{}).Where("id = ?", tpl.ID).Updates(map[string]any{
+		"goods_type_id": row.GoodsTypeID,
+		"name":          row.Name,
+		"description":   row.Description,
+		"rules_json":    row.RulesJSON,
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return r.ensure(gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// DeleteFirewallTemplate removes the template and its attachments. Sync rows
+// are kept so the reconciler can still remove the rules it created.
+func (r *GormRepo) DeleteFirewallTemplate(ctx context.Context, id int64) error {
+	return r.gdb.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("template_id = ?", id).Delete(&firewallTemplateAttachmentRow{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&firewallTemplateRow{}).Error
+	})
+}
+
+func (r *GormRepo) ListFirewallTemplates(ctx context.Context, userID int64) ([]domain.FirewallTemplate, error) {
+	var rows []firewallTemplateRow
+	if err := r.gdb.WithContext(ctx).Where("user_id = ?", userID).Order("id ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return fromFirewallTemplateRows(rows), nil
+}
+
+func (r *GormRepo) ListDefaultFirewallTemplates(ctx context.Context, goodsTypeID int64) ([]domain.FirewallTemplate, error) {
+	var rows []firewallTemplateRow
+	if err := r.gdb.WithContext(ctx).Where("user_id = 0 AND goods_type_id = ?", goodsTypeID).Order("id ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return fromFirewallTemplateRows(rows), nil
+}
+
+func (r *GormRepo) AttachFirewallTemplate(ctx context.Context, templateID, vpsID int64) error {
+	row := firewallTemplateAttachmentRow{TemplateID: templateID, VPSID: vpsID}
+	return r.gdb.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&row).Error
+}
+
+func (r *GormRepo) DetachFirewallTemplate(ctx context.Context, templateID, vpsID int64) error {
+	return r.gdb.WithContext(ctx).Where("template_id = ? AND vps_id = ?", templateID, vpsID).Delete(&firewallTemplateAttachmentRow{}).Error
+}
+
+func (r *GormRepo) ListFirewallTemplateIDsForVPS(ctx context.Context, vpsID int64) ([]int64, error) {
+	var ids []int64
+	if err := r.gdb.WithContext(ctx).Model(&firewallTemplateAttachmentRow{}).Where("vps_id = ?", vpsID).Order("template_id ASC").Pluck("template_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *GormRepo) ListVPSIDsForFirewallTemplate(ctx context.Context, templateID int64) ([]int64, error) {
+	var ids []int64
+	if err := r.gdb.WithContext(ctx).Model(&firewallTemplateAttachmentRow{}).Where("template_id = ?", templateID).Order("vps_id ASC").Pluck("vps_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *GormRepo) GetVPSFirewallSync(ctx context.Context, vpsID int64) (domain.VPSFirewallSync, error) {
+	var row vpsFirewallSyncRow
+	if err := r.gdb.WithContext(ctx).Where("vps_id = ?", vpsID).First(&row).Error; err != nil {
+		return domain.VPSFirewallSync{}, r.ensure(err)
+	}
+	return fromVPSFirewallSyncRow(row), nil
+}
+
+func (r *GormRepo) SaveVPSFirewallSync(ctx context.Context, sync domain.VPSFirewallSync) error {
+	raw, _ := json.Marshal(nonNilRules(sync.ManagedRules))
+	row := vpsFirewallSyncRow{
+		VPSID:            sync.VPSID,
+		ManagedRulesJSON: string(raw),
+		Status:           sync.Status,
+		LastError:        sync.LastError,
+		Attempts:         sync.Attempts,
+		SyncedAt:         sync.SyncedAt,
+	}
+	return r.gdb.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "vps_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"managed_rules_json", "status", "last_error", "attempts", "synced_at", "updated_at"}),
+	}).Create(&row).Error
+}
+
+// ListPendingVPSFirewallSyncs returns pending and failed syncs that have not
+// used up their attempts, oldest first.
+func (r *GormRepo) ListPendingVPSFirewallSyncs(ctx context.Context, maxAttempts, limit int) ([]domain.VPSFirewallSync, error) {
+	var rows []vpsFirewallSyncRow
+	if err := r.gdb.WithContext(ctx).
+		Where("status IN ? AND attempts < ?", []string{domain.FirewallSyncPending, domain.FirewallSyncFailed}, maxAttempts).
+		Order("updated_at ASC").Limit(limit).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]domain.VPSFirewallSync, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, fromVPSFirewallSyncRow(row))
+	}
+	return out, nil
+}
+
+func toFirewallTemplateRow(tpl domain.FirewallTemplate) firewallTemplateRow {
+	raw, _ := json.Marshal(nonNilRules(tpl.Rules))
+	return firewallTemplateRow{
+		ID:          tpl.ID,
+		UserID:      tpl.UserID,
+		GoodsTypeID: tpl.GoodsTypeID,
+		Name:        tpl.Name,
+		Description: tpl.Description,
+		RulesJSON:   string(raw),
+	}
+}
+
+func fromFirewallTemplateRow(row firewallTemplateRow) domain.FirewallTemplate {
+	var rules []domain.FirewallTemplateRule
+	_ = json.Unmarshal([]byte(row.RulesJSON), &rules)
+	return domain.FirewallTemplate{
+		ID:          row.ID,
+		UserID:      row.UserID,
+		GoodsTypeID: row.GoodsTypeID,
+		Name:        row.Name,
+		Description: row.Description,
+		Rules:       rules,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+}
+
+func fromFirewallTemplateRows(rows []firewallTemplateRow) []domain.FirewallTemplate {
+	out := make([]domain.FirewallTemplate, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, fromFirewallTemplateRow(row))
+	}
+	return out
+}
+
+func fromVPSFirewallSyncRow(row vpsFirewallSyncRow) domain.VPSFirewallSync {
+	var rules []domain.FirewallTemplateRule
+	_ = json.Unmarshal([]byte(row.ManagedRulesJSON), &rules)
+	return domain.VPSFirewallSync{
+		VPSID:        row.VPSID,
+		ManagedRules: rules,
+		Status:       row.Status,
+		LastError:    row.LastError,
+		Attempts:     row.Attempts,
+		SyncedAt:     row.SyncedAt,
+		UpdatedAt:    row.UpdatedAt,
+	}
+}
+
+func nonNilRules(rules []domain.FirewallTemplateRule) []domain.FirewallTemplateRule {
+	if rules == nil {
+		return []domain.FirewallTemplateRule{}
+	}
+	return rules
+}
@@ -325,7 +325,7 @@ func (r *GormRepo) hasExclusiveVPSOrderInProgress(ctx context.Context, userID, v
 		string(domain.OrderStatusApproved),
 		string(domain.OrderStatusProvisioning),
 	}
-	actions := []string{"renew", "emergency_renew", "resize", "refund"}
+	actions := []string{"renew", "emergency_renew", "resize", "refund", "add_ip"}
 	var rows []orderItemRow
 	if err := r.gdb.WithContext(ctx).
 		Joins("JOIN orders o ON o.id = order_items.order_id").
@@ -357,6 +357,32 @@ func (r *GormRepo) UpdateOrderItemStatus(ctx context.Context, id int64, status d
 
 }
 
+func (r *GormRepo) UpdateOrderItemSpec(ctx context.Context, id int64, specJSON string) error {
+
+	return r.gdb.WithContext(ctx).Model(&orderItemRow{}).Where("id = ?", id).Updates(map[string]any{
+		"spec_json":  specJSON,
+		"updated_at": time.Now(),
+	}).Error
+
+}
+
+func (r *GormRepo) SwitchOrderItemPackage(ctx context.Context, id, packageID, amount int64) error {
+	return r.gdb.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Model(&orderItemRow{}).Where("id = ?", id).Updates(map[string]any{
+			"package_id": packageID,
+			"amount":     amount,
+			"updated_at": now,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&packageRow{}).Where("id = ? AND capacity_remaining > 0", packageID).Updates(map[string]any{
+			"capacity_remaining": gorm.Expr("capacity_remaining - 1"),
+			"updated_at":         now,
+		}).Error
+	})
+}
+
 func (r *GormRepo) UpdateOrderItemAutomation(ctx context.Context, id int64, automationID string) error {
 
 	return r.gdb.WithContext(ctx).Model(&orderItemRow{}).Where("id = ?", id).Updates(map[string]any{
@@ -0,0 +1,134 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+func (r *GormRepo) CreatePluginHookDeliveries(ctx context.Context, items []domain.PluginHookDelivery) error {
+	if len(items) == 0 {
+		return nil
+	}
+	rows := make([]pluginHookDeliveryRow, 0, len(items))
+	for _, item := range items {
+		rows = append(rows, toPluginHookDeliveryRow(item))
+	}
+	return r.gdb.WithContext(ctx).Create(&rows).Error
+}
+
+func (r *GormRepo) GetPluginHookDelivery(ctx context.Context, id int64) (domain.PluginHookDelivery, error) {
+	var row pluginHookDeliveryRow
+	if err := r.gdb.WithContext(ctx).Where("id = ?", id).First(&row).Error; err != nil {
+		return domain.PluginHookDelivery{}, r.ensure(err)
+	}
+	return fromPluginHookDeliveryRow(row), nil
+}
+
+func (r *GormRepo) UpdatePluginHookDelivery(ctx context.Context, item domain.PluginHookDelivery) error {
+	return r.gdb.WithContext(ctx).Model(&pluginHookDeliveryRow{}).
+		Where("id = ?", item.ID).
+		Updates(map[string]any{
+			"status":          string(item.Status),
+			"attempts":        item.Attempts,
+			"last_error":      item.LastError,
+			"next_attempt_at": item.NextAttemptAt,
+			"delivered_at":    item.DeliveredAt,
+			"updated_at":      time.Now(),
+		}).Error
+}
+
+func (r *GormRepo) ListDuePluginHookDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.PluginHookDelivery, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	var rows []pluginHookDeliveryRow
+	if err := r.gdb.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", string(domain.PluginHookDeliveryPending), now).
+		Order("next_attempt_at ASC, id ASC").
+		Limit(limit).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]domain.PluginHookDelivery, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, fromPluginHookDeliveryRow(row))
+	}
+	return out, nil
+}
+
+func (r *GormRepo) ListPluginHookDeliveries(ctx context.Context, filter appshared.PluginHookDeliveryFilter) ([]domain.PluginHookDelivery, int, error) {
+	q := r.gdb.WithContext(ctx).Model(&pluginHookDeliveryRow{}).
+		Where("category = ? AND plugin_id = ? AND instance_id = ?", filter.Category, filter.PluginID, filter.InstanceID)
+	if filter.Status != "" {
+		q = q.Where("status = ?", filter.Status)
+	}
+	if filter.Event != "" {
+		q = q.Where("event = ?", filter.Event)
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	var rows []pluginHookDeliveryRow
+	if err := q.Order("id DESC").Limit(limit).Offset(filter.Offset).Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	out := make([]domain.PluginHookDelivery, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, fromPluginHookDeliveryRow(row))
+	}
+	return out, int(total), nil
+}
+
+// PurgePluginHookDeliveries drops finished deliveries; pending ones are kept
+// whatever their age.
+func (r *GormRepo) PurgePluginHookDeliveries(ctx context.Context, before time.Time) error {
+	return r.gdb.WithContext(ctx).
+		Where("status <> ? AND created_at < ?", string(domain.PluginHookDeliveryPending), before).
+		Delete(&pluginHookDeliveryRow{}).Error
+}
+
+func toPluginHookDeliveryRow(item domain.PluginHookDelivery) pluginHookDeliveryRow {
+	return pluginHookDeliveryRow{
+		ID:            item.ID,
+		EventID:       item.EventID,
+		Category:      item.Category,
+		PluginID:      item.PluginID,
+		InstanceID:    item.InstanceID,
+		Event:         item.Event,
+		PayloadJSON:   item.PayloadJSON,
+		Status:        string(item.Status),
+		Attempts:      item.Attempts,
+		LastError:     item.LastError,
+		NextAttemptAt: item.NextAttemptAt,
+		OccurredAt:    item.OccurredAt,
+		DeliveredAt:   item.DeliveredAt,
+	}
+}
+
+func fromPluginHookDeliveryRow(row pluginHookDeliveryRow) domain.PluginHookDelivery {
+	return domain.PluginHookDelivery{
+		ID:            row.ID,
+		EventID:       row.EventID,
+		Category:      row.Category,
+		PluginID:      row.PluginID,
+		InstanceID:    row.InstanceID,
+		Event:         row.Event,
+		PayloadJSON:   row.PayloadJSON,
+		Status:        domain.PluginHookDeliveryStatus(row.Status),
+		Attempts:      row.Attempts,
+		LastError:     row.LastError,
+		NextAttemptAt: row.NextAttemptAt,
+		OccurredAt:    row.OccurredAt,
+		DeliveredAt:   row.DeliveredAt,
+		CreatedAt:     row.CreatedAt,
+		UpdatedAt:     row.UpdatedAt,
+	}
+}
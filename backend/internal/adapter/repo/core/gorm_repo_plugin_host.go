@@ -0,0 +1,174 @@
+package repo
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+// pluginKVLive keeps KV rows that have not expired. Expired rows are dropped
+// lazily by PutPluginKV.
+const pluginKVLive = "expires_at IS NULL OR expires_at > ?"
+
+func (r *GormRepo) GetPluginKV(ctx context.Context, category, pluginID, instanceID, key string) (domain.PluginKV, error) {
+	var row pluginKVRow
+	if err := r.gdb.WithContext(ctx).
+		Where("category = ? AND plugin_id = ? AND instance_id = ? AND kv_key = ?", category, pluginID, instanceID, key).
+		Where(pluginKVLive, time.Now()).
+		First(&row).Error; err != nil {
+		return domain.PluginKV{}, r.ensure(err)
+	}
+	return toPluginKV(row), nil
+}
+
+func (r *GormRepo) PutPluginKV(ctx context.Context, kv *domain.PluginKV) error {
+	if kv == nil || strings.TrimSpace(kv.Category) == "" || strings.TrimSpace(kv.PluginID) == "" || strings.TrimSpace(kv.InstanceID) == "" || kv.Key == "" {
+		return appshared.ErrInvalidInput
+	}
+	db := r.gdb.WithContext(ctx)
+	if err := db.Where("category = ? AND plugin_id = ? AND instance_id = ? AND expires_at IS NOT NULL AND expires_at <= ?", kv.Category, kv.PluginID, kv.InstanceID, time.Now()).
+		Delete(&pluginKVRow{}).Error; err != nil {
+		return err
+	}
+	value := kv.Value
+	if value == nil {
+		value = []byte{}
+	}
+	row := pluginKVRow{
+		Category:   kv.Category,
+		PluginID:   kv.PluginID,
+		InstanceID: kv.InstanceID,
+		Key:        kv.Key,
+		Value:      value,
+		ExpiresAt:  kv.ExpiresAt,
+	}
+	if err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "category"}, {Name: "plugin_id"}, {Name: "instance_id"}, {Name: "kv_key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "expires_at", "updated_at"}),
+	}).Create(&row).Error; err != nil {
+		return err
+	}
+	kv.UpdatedAt = row.UpdatedAt
+	return nil
+}
+
+func (r *GormRepo) DeletePluginKV(ctx context.Context, category, pluginID, instanceID, key string) error {
+	return r.gdb.WithContext(ctx).
+		Where("category = ? AND plugin_id = ? AND instance_id = ? AND kv_key = ?", category, pluginID, instanceID, key).
+		Delete(&pluginKVRow{}).Error
+}
+
+func (r *GormRepo) ListPluginKV(ctx context.Context, category, pluginID, instanceID, prefix string, limit int) ([]domain.PluginKV, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	q := r.gdb.WithContext(ctx).
+		Where("category = ? AND plugin_id = ? AND instance_id = ?", category, pluginID, instanceID).
+		Where(pluginKVLive, time.Now())
+	if prefix != "" {
+		q = q.Where("kv_key LIKE ? ESCAPE '!'", escapeLikePrefix(prefix)+"%")
+	}
+	var rows []pluginKVRow
+	if err := q.Order("kv_key ASC").Limit(limit).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]domain.PluginKV, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, toPluginKV(row))
+	}
+	return out, nil
+}
+
+func (r *GormRepo) CountPluginKV(ctx context.Context, category, pluginID, instanceID string) (int, error) {
+	var total int64
+	if err := r.gdb.WithContext(ctx).Model(&pluginKVRow{}).
+		Where("category = ? AND plugin_id = ? AND instance_id = ?", category, pluginID, instanceID).
+		Where(pluginKVLive, time.Now()).
+		Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return int(total), nil
+}
+
+func (r *GormRepo) DeletePluginKVByInstance(ctx context.Context, category, pluginID, instanceID string) error {
+	return r.gdb.WithContext(ctx).
+		Where("category = ? AND plugin_id = ? AND instance_id = ?", category, pluginID, instanceID).
+		Delete(&pluginKVRow{}).Error
+}
+
+func (r *GormRepo) CreatePluginLog(ctx context.Context, log *domain.PluginLog) error {
+	row := pluginLogRow{
+		Category:   log.Category,
+		PluginID:   log.PluginID,
+		InstanceID: log.InstanceID,
+		Level:      log.Level,
+		Message:    log.Message,
+		FieldsJSON: log.FieldsJSON,
+		CreatedAt:  log.CreatedAt,
+	}
+	if err := r.gdb.WithContext(ctx).Create(&row).Error; err != nil {
+		return err
+	}
+	log.ID = row.ID
+	log.CreatedAt = row.CreatedAt
+	return nil
+}
+
+func (r *GormRepo) ListPluginLogs(ctx context.Context, filter appshared.PluginLogFilter) ([]domain.PluginLog, int, error) {
+	q := r.gdb.WithContext(ctx).Model(&pluginLogRow{}).
+		Where("category = ? AND plugin_id = ? AND instance_id = ?", filter.Category, filter.PluginID, filter.InstanceID)
+	if filter.Level != "" {
+		q = q.Where("level = ?", filter.Level)
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	var rows []pluginLogRow
+	if err := q.Order("id DESC").Limit(limit).Offset(filter.Offset).Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	out := make([]domain.PluginLog, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, domain.PluginLog{
+			ID:         row.ID,
+			Category:   row.Category,
+			PluginID:   row.PluginID,
+			InstanceID: row.InstanceID,
+			Level:      row.Level,
+			Message:    row.Message,
+			FieldsJSON: row.FieldsJSON,
+			CreatedAt:  row.CreatedAt,
+		})
+	}
+	return out, int(total), nil
+}
+
+func (r *GormRepo) PurgePluginLogs(ctx context.Context, before time.Time) error {
+	return r.gdb.WithContext(ctx).Where("created_at < ?", before).Delete(&pluginLogRow{}).Error
+}
+
+func toPluginKV(row pluginKVRow) domain.PluginKV {
+	return domain.PluginKV{
+		Category:   row.Category,
+		PluginID:   row.PluginID,
+		InstanceID: row.InstanceID,
+		Key:        row.Key,
+		Value:      row.Value,
+		ExpiresAt:  row.ExpiresAt,
+		UpdatedAt:  row.UpdatedAt,
+	}
+}
+
+func escapeLikePrefix(s string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(s)
+}
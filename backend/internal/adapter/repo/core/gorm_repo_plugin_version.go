@@ -0,0 +1,84 @@
+package repo
+
+import (
+	"context"
+	"strings"
+
+	"gorm.io/gorm/clause"
+
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+func (r *GormRepo) UpsertPluginVersion(ctx context.Context, v *domain.PluginVersion) error {
+	if v == nil || strings.TrimSpace(v.Category) == "" || strings.TrimSpace(v.PluginID) == "" || strings.TrimSpace(v.Version) == "" {
+		return appshared.ErrInvalidInput
+	}
+	row := pluginVersionRow{
+		Category:        v.Category,
+		PluginID:        v.PluginID,
+		Version:         v.Version,
+		SignatureStatus: string(v.SignatureStatus),
+		Status:          string(v.Status),
+		LastError:       v.LastError,
+		ActivatedAt:     v.ActivatedAt,
+	}
+	return r.gdb.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "category"}, {Name: "plugin_id"}, {Name: "version"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"signature_status",
+				"status",
+				"last_error",
+				"activated_at",
+				"updated_at",
+			}),
+		}).
+		Create(&row).Error
+}
+
+func (r *GormRepo) GetPluginVersion(ctx context.Context, category, pluginID, version string) (domain.PluginVersion, error) {
+	var row pluginVersionRow
+	if err := r.gdb.WithContext(ctx).
+		Where("category = ? AND plugin_id = ? AND version = ?", category, pluginID, version).
+		First(&row).Error; err != nil {
+		return domain.PluginVersion{}, r.ensure(err)
+	}
+	return fromPluginVersionRow(row), nil
+}
+
+func (r *GormRepo) ListPluginVersions(ctx context.Context, category, pluginID string) ([]domain.PluginVersion, error) {
+	var rows []pluginVersionRow
+	if err := r.gdb.WithContext(ctx).
+		Where("category = ? AND plugin_id = ?", category, pluginID).
+		Order("created_at DESC, id DESC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]domain.PluginVersion, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, fromPluginVersionRow(row))
+	}
+	return out, nil
+}
+
+func (r *GormRepo) DeletePluginVersions(ctx context.Context, category, pluginID string) error {
+	return r.gdb.WithContext(ctx).
+		Where("category = ? AND plugin_id = ?", category, pluginID).
+		Delete(&pluginVersionRow{}).Error
+}
+
+func fromPluginVersionRow(row pluginVersionRow) domain.PluginVersion {
+	return domain.PluginVersion{
+		ID:              row.ID,
+		Category:        row.Category,
+		PluginID:        row.PluginID,
+		Version:         row.Version,
+		SignatureStatus: domain.PluginSignatureStatus(row.SignatureStatus),
+		Status:          domain.PluginVersionStatus(row.Status),
+		LastError:       row.LastError,
+		ActivatedAt:     row.ActivatedAt,
+		CreatedAt:       row.CreatedAt,
+		UpdatedAt:       row.UpdatedAt,
+	}
+}
@@ -0,0 +1,65 @@
+package repo
+
+import (
+	"context"
+
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+func (r *GormRepo) CreateProvisionAttempt(ctx context.Context, attempt *domain.ProvisionAttempt) error {
+	row := provisionAttemptRow{
+		OrderID:      attempt.OrderID,
+		OrderItemID:  attempt.OrderItemID,
+		Attempt:      attempt.Attempt,
+		Strategy:     string(attempt.Strategy),
+		LineID:       attempt.LineID,
+		PackageID:    attempt.PackageID,
+		Success:      boolToInt(attempt.Success),
+		ErrorMessage: attempt.ErrorMessage,
+	}
+	if err := r.gdb.WithContext(ctx).Create(&row).Error; err != nil {
+		return err
+	}
+	attempt.ID = row.ID
+	attempt.CreatedAt = row.CreatedAt
+	return nil
+}
+
+func (r *GormRepo) ListProvisionAttempts(ctx context.Context, filter appshared.ProvisionAttemptFilter) ([]domain.ProvisionAttempt, int, error) {
+	q := r.gdb.WithContext(ctx).Model(&provisionAttemptRow{})
+	if filter.OrderID > 0 {
+		q = q.Where("order_id = ?", filter.OrderID)
+	}
+	if filter.Failed {
+		q = q.Where("success = ?", 0)
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	var rows []provisionAttemptRow
+	if err := q.Order("id DESC").Limit(limit).Offset(filter.Offset).Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	out := make([]domain.ProvisionAttempt, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, domain.ProvisionAttempt{
+			ID:           row.ID,
+			OrderID:      row.OrderID,
+			OrderItemID:  row.OrderItemID,
+			Attempt:      row.Attempt,
+			Strategy:     domain.ProvisionAttemptStrategy(row.Strategy),
+			LineID:       row.LineID,
+			PackageID:    row.PackageID,
+			Success:      row.Success == 1,
+			ErrorMessage: row.ErrorMessage,
+			CreatedAt:    row.CreatedAt,
+		})
+	}
+	return out, int(total), nil
+}
@@ -0,0 +1,50 @@
+package repo
+
+import (
+	"context"
+
+	"xiaoheiplay/internal/domain"
+)
+
+func (r *GormRepo) AddReverseDNSChange(ctx context.Context, change *domain.ReverseDNSChange) error {
+	row := reverseDNSChangeRow{
+		VPSID:   change.VPSID,
+		UserID:  change.UserID,
+		AdminID: change.AdminID,
+		IP:      change.IP,
+		OldPTR:  change.OldPTR,
+		NewPTR:  change.NewPTR,
+	}
+	if err := r.gdb.WithContext(ctx).Create(&row).Error; err != nil {
+		return err
+	}
+	change.ID = row.ID
+	change.CreatedAt = row.CreatedAt
+	return nil
+}
+
+func (r *GormRepo) ListReverseDNSChanges(ctx context.Context, vpsID int64, limit, offset int) ([]domain.ReverseDNSChange, int, error) {
+	q := r.gdb.WithContext(ctx).Model(&reverseDNSChangeRow{}).Where("vps_id = ?", vpsID)
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var rows []reverseDNSChangeRow
+	if err := q.Order("id DESC").Limit(limit).Offset(offset).Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	out := make([]domain.ReverseDNSChange, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, domain.ReverseDNSChange{
+			ID:        row.ID,
+			VPSID:     row.VPSID,
+			UserID:    row.UserID,
+			AdminID:   row.AdminID,
+			IP:        row.IP,
+			OldPTR:    row.OldPTR,
+			NewPTR:    row.NewPTR,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	return out, int(total), nil
+}
@@ -202,6 +202,8 @@ func toVPSInstanceRow(inst domain.VPSInstance) vpsInstanceRow {
 		OrderItemID:          inst.OrderItemID,
 		AutomationInstanceID: inst.AutomationInstanceID,
 		GoodsTypeID:          inst.GoodsTypeID,
+		BackendPluginID:      inst.BackendPluginID,
+		BackendInstanceID:    inst.BackendInstanceID,
 		Name:                 inst.Name,
 		Region:               inst.Region,
 		RegionID:             inst.RegionID,
@@ -235,6 +237,8 @@ func fromVPSInstanceRow(r vpsInstanceRow) domain.VPSInstance {
 		OrderItemID:          r.OrderItemID,
 		AutomationInstanceID: r.AutomationInstanceID,
 		GoodsTypeID:          r.GoodsTypeID,
+		BackendPluginID:      r.BackendPluginID,
+		BackendInstanceID:    r.BackendInstanceID,
 		Name:                 r.Name,
 		Region:               r.Region,
 		RegionID:             r.RegionID,
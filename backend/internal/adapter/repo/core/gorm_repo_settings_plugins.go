@@ -171,22 +171,26 @@ func (r *GormRepo) getNormalizedSettingValue(ctx context.Context, key string) (s
 			return "", false, nil
 		}
 		type webhookPayload struct {
-			Name    string   `json:"name"`
-			URL     string   `json:"url"`
-			Secret  string   `json:"secret"`
-			Enabled bool     `json:"enabled"`
-			Events  []string `json:"events"`
+			Name       string   `json:"name"`
+			URL        string   `json:"url"`
+			Secret     string   `json:"secret"`
+			Enabled    bool     `json:"enabled"`
+			Events     []string `json:"events"`
+			PluginID   string   `json:"plugin_id,omitempty"`
+			InstanceID string   `json:"instance_id,omitempty"`
 		}
 		payload := make([]webhookPayload, 0, len(rows))
 		for _, row := range rows {
 			var events []string
 			_ = json.Unmarshal([]byte(row.EventsJSON), &events)
 			payload = append(payload, webhookPayload{
-				Name:    row.Name,
-				URL:     row.URL,
-				Secret:  row.Secret,
-				Enabled: row.Enabled == 1,
-				Events:  events,
+				Name:       row.Name,
+				URL:        row.URL,
+				Secret:     row.Secret,
+				Enabled:    row.Enabled == 1,
+				Events:     events,
+				PluginID:   row.PluginID,
+				InstanceID: row.InstanceID,
 			})
 		}
 		raw, _ := json.Marshal(payload)
@@ -332,11 +336,13 @@ func (r *GormRepo) upsertSMSTemplatesSetting(ctx context.Context, raw string) er
 
 func (r *GormRepo) upsertRobotWebhooksSetting(ctx context.Context, raw string) error {
 	type webhookPayload struct {
-		Name    string   `json:"name"`
-		URL     string   `json:"url"`
-		Secret  string   `json:"secret"`
-		Enabled bool     `json:"enabled"`
-		Events  []string `json:"events"`
+		Name       string   `json:"name"`
+		URL        string   `json:"url"`
+		Secret     string   `json:"secret"`
+		Enabled    bool     `json:"enabled"`
+		Events     []string `json:"events"`
+		PluginID   string   `json:"plugin_id,omitempty"`
+		InstanceID string   `json:"instance_id,omitempty"`
 	}
 	var items []webhookPayload
 	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &items); err != nil {
@@ -354,9 +360,12 @@ func (r *GormRepo) upsertRobotWebhooksSetting(ctx context.Context, raw string) e
 				Secret:     strings.TrimSpace(item.Secret),
 				Enabled:    boolToInt(item.Enabled),
 				EventsJSON: string(eventsRaw),
+				PluginID:   strings.TrimSpace(item.PluginID),
+				InstanceID: strings.TrimSpace(item.InstanceID),
 				SortOrder:  i,
 			}
-			if row.URL == "" {
+			// Notify plugin entries may leave the target to the plugin config.
+			if row.URL == "" && row.PluginID == "" {
 				continue
 			}
 			if err := tx.Create(&row).Error; err != nil {
@@ -0,0 +1,57 @@
+package repo
+
+import (
+	"context"
+
+	"xiaoheiplay/internal/domain"
+)
+
+func (r *GormRepo) ListSSHKeys(ctx context.Context, userID int64) ([]domain.SSHKey, error) {
+	var rows []sshKeyRow
+	if err := r.gdb.WithContext(ctx).Where("user_id = ?", userID).Order("id DESC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]domain.SSHKey, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, fromSSHKeyRow(row))
+	}
+	return out, nil
+}
+
+func (r *GormRepo) GetSSHKey(ctx context.Context, id int64) (domain.SSHKey, error) {
+	var row sshKeyRow
+	if err := r.gdb.WithContext(ctx).Where("id = ?", id).First(&row).Error; err != nil {
+		return domain.SSHKey{}, r.ensure(err)
+	}
+	return fromSSHKeyRow(row), nil
+}
+
+func (r *GormRepo) CreateSSHKey(ctx context.Context, key *domain.SSHKey) error {
+	row := sshKeyRow{
+		UserID:      key.UserID,
+		Name:        key.Name,
+		PublicKey:   key.PublicKey,
+		Fingerprint: key.Fingerprint,
+	}
+	if err := r.gdb.WithContext(ctx).Create(&row).Error; err != nil {
+		return err
+	}
+	key.ID = row.ID
+	key.CreatedAt = row.CreatedAt
+	return nil
+}
+
+func (r *GormRepo) DeleteSSHKey(ctx context.Context, userID, id int64) error {
+	return r.gdb.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&sshKeyRow{}).Error
+}
+
+func fromSSHKeyRow(row sshKeyRow) domain.SSHKey {
+	return domain.SSHKey{
+		ID:          row.ID,
+		UserID:      row.UserID,
+		Name:        row.Name,
+		PublicKey:   row.PublicKey,
+		Fingerprint: row.Fingerprint,
+		CreatedAt:   row.CreatedAt,
+	}
+}
@@ -2,23 +2,31 @@ package repo
 
 import (
 	"context"
+	"strings"
 
 	"xiaoheiplay/internal/domain"
 )
 
 func (r *GormRepo) CreateUpload(ctx context.Context, upload *domain.Upload) error {
+	backend := strings.TrimSpace(upload.Backend)
+	if backend == "" {
+		backend = domain.UploadBackendLocal
+	}
 	row := uploadRow{
 		Name:       upload.Name,
 		Path:       upload.Path,
 		URL:        upload.URL,
 		Mime:       upload.Mime,
 		Size:       upload.Size,
+		Backend:    backend,
+		ObjectKey:  upload.ObjectKey,
 		UploaderID: upload.UploaderID,
 	}
 	if err := r.gdb.WithContext(ctx).Create(&row).Error; err != nil {
 		return err
 	}
 	upload.ID = row.ID
+	upload.Backend = row.Backend
 	upload.CreatedAt = row.CreatedAt
 	return nil
 }
@@ -38,16 +46,57 @@ func (r *GormRepo) ListUploads(ctx context.Context, limit, offset int) ([]domain
 	}
 	out := make([]domain.Upload, 0, len(rows))
 	for _, row := range rows {
-		out = append(out, domain.Upload{
-			ID:         row.ID,
-			Name:       row.Name,
-			Path:       row.Path,
-			URL:        row.URL,
-			Mime:       row.Mime,
-			Size:       row.Size,
-			UploaderID: row.UploaderID,
-			CreatedAt:  row.CreatedAt,
-		})
+		out = append(out, toUpload(row))
 	}
 	return out, int(total), nil
 }
+
+func (r *GormRepo) GetUploadByObjectKey(ctx context.Context, key string) (domain.Upload, error) {
+	var row uploadRow
+	if err := r.gdb.WithContext(ctx).Where("object_key = ?", key).Order("id DESC").First(&row).Error; err != nil {
+		return domain.Upload{}, r.ensure(err)
+	}
+	return toUpload(row), nil
+}
+
+func (r *GormRepo) ListUploadsByBackend(ctx context.Context, backend string, afterID int64, limit int) ([]domain.Upload, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	var rows []uploadRow
+	if err := r.gdb.WithContext(ctx).
+		Where("backend = ? AND id > ?", backend, afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]domain.Upload, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, toUpload(row))
+	}
+	return out, nil
+}
+
+func (r *GormRepo) UpdateUploadStorage(ctx context.Context, id int64, backend, key, path string) error {
+	return r.gdb.WithContext(ctx).Model(&uploadRow{}).Where("id = ?", id).Updates(map[string]any{
+		"backend":    backend,
+		"object_key": key,
+		"path":       path,
+	}).Error
+}
+
+func toUpload(row uploadRow) domain.Upload {
+	return domain.Upload{
+		ID:         row.ID,
+		Name:       row.Name,
+		Path:       row.Path,
+		URL:        row.URL,
+		Mime:       row.Mime,
+		Size:       row.Size,
+		Backend:    row.Backend,
+		ObjectKey:  row.ObjectKey,
+		UploaderID: row.UploaderID,
+		CreatedAt:  row.CreatedAt,
+	}
+}
@@ -86,7 +86,21 @@ func (r *GormRepo) ListInstancesExpiring(ctx context.Context, before time.Time)
 
 func (r *GormRepo) DeleteInstance(ctx context.Context, id int64) error {
 
-	return r.gdb.WithContext(ctx).Delete(&vpsInstanceRow{}, id).Error
+	return r.gdb.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("vps_id = ?", id).Delete(&vpsExtraIPRow{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("vps_id = ?", id).Delete(&vpsISOMountRow{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("vps_id = ?", id).Delete(&vpsRescueSessionRow{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("vps_id = ?", id).Delete(&vpsMetricRow{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&vpsInstanceRow{}, id).Error
+	})
 
 }
 
@@ -189,6 +203,8 @@ func (r *GormRepo) UpdateInstanceLocal(ctx context.Context, inst domain.VPSInsta
 	return r.gdb.WithContext(ctx).Model(&vpsInstanceRow{}).Where("id = ?", inst.ID).Updates(map[string]any{
 		"automation_instance_id": inst.AutomationInstanceID,
 		"goods_type_id":          inst.GoodsTypeID,
+		"backend_plugin_id":      inst.BackendPluginID,
+		"backend_instance_id":    inst.BackendInstanceID,
 		"name":                   inst.Name,
 		"region":                 inst.Region,
 		"region_id":              inst.RegionID,
@@ -0,0 +1,39 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// vpsActionUsageRetention covers the longest quota window and cooldown.
+const vpsActionUsageRetention = 7 * 24 * time.Hour
+
+// RecordVPSActionUsage stores one action and drops rows of the same instance
+// and action that no quota window can reach any more.
+func (r *GormRepo) RecordVPSActionUsage(ctx context.Context, vpsID int64, action string, at time.Time) error {
+	return r.gdb.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&vpsActionUsageRow{VPSID: vpsID, Action: action, CreatedAt: at}).Error; err != nil {
+			return err
+		}
+		return tx.Where("vps_id = ? AND action = ? AND created_at < ?", vpsID, action, at.Add(-vpsActionUsageRetention)).
+			Delete(&vpsActionUsageRow{}).Error
+	})
+}
+
+func (r *GormRepo) CountVPSActionUsage(ctx context.Context, vpsID int64, action string, since time.Time) (int, error) {
+	var count int64
+	err := r.gdb.WithContext(ctx).Model(&vpsActionUsageRow{}).
+		Where("vps_id = ? AND action = ? AND created_at >= ?", vpsID, action, since).
+		Count(&count).Error
+	return int(count), err
+}
+
+func (r *GormRepo) LastVPSActionUsage(ctx context.Context, vpsID int64, action string) (time.Time, error) {
+	var row vpsActionUsageRow
+	if err := r.gdb.WithContext(ctx).Where("vps_id = ? AND action = ?", vpsID, action).Order("created_at DESC").First(&row).Error; err != nil {
+		return time.Time{}, r.ensure(err)
+	}
+	return row.CreatedAt, nil
+}
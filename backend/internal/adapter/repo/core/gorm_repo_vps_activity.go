@@ -0,0 +1,82 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+func (r *GormRepo) CreateVPSActivity(ctx context.Context, activity *domain.VPSActivity) error {
+	row := vpsActivityRow{
+		VPSID:        activity.VPSID,
+		UserID:       activity.UserID,
+		ActorType:    string(activity.ActorType),
+		ActorID:      activity.ActorID,
+		Action:       activity.Action,
+		Detail:       activity.Detail,
+		IP:           activity.IP,
+		Result:       string(activity.Result),
+		ErrorMessage: activity.ErrorMessage,
+		CreatedAt:    activity.CreatedAt,
+	}
+	if err := r.gdb.WithContext(ctx).Create(&row).Error; err != nil {
+		return err
+	}
+	activity.ID = row.ID
+	activity.CreatedAt = row.CreatedAt
+	return nil
+}
+
+func (r *GormRepo) ListVPSActivities(ctx context.Context, filter appshared.VPSActivityFilter) ([]domain.VPSActivity, int, error) {
+	q := r.gdb.WithContext(ctx).Model(&vpsActivityRow{}).Where("vps_id = ?", filter.VPSID)
+	if filter.Action != "" {
+		q = q.Where("action = ?", filter.Action)
+	}
+	if filter.ActorType != "" {
+		q = q.Where("actor_type = ?", string(filter.ActorType))
+	}
+	if filter.Result != "" {
+		q = q.Where("result = ?", string(filter.Result))
+	}
+	if filter.From != nil {
+		q = q.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		q = q.Where("created_at < ?", *filter.To)
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	var rows []vpsActivityRow
+	if err := q.Order("id DESC").Limit(limit).Offset(filter.Offset).Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	out := make([]domain.VPSActivity, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, domain.VPSActivity{
+			ID:           row.ID,
+			VPSID:        row.VPSID,
+			UserID:       row.UserID,
+			ActorType:    domain.VPSActivityActorType(row.ActorType),
+			ActorID:      row.ActorID,
+			Action:       row.Action,
+			Detail:       row.Detail,
+			IP:           row.IP,
+			Result:       domain.VPSActivityResult(row.Result),
+			ErrorMessage: row.ErrorMessage,
+			CreatedAt:    row.CreatedAt,
+		})
+	}
+	return out, int(total), nil
+}
+
+func (r *GormRepo) PurgeVPSActivities(ctx context.Context, before time.Time) error {
+	return r.gdb.WithContext(ctx).Where("created_at < ?", before).Delete(&vpsActivityRow{}).Error
+}
@@ -0,0 +1,60 @@
+package repo
+
+import (
+	"context"
+
+	"xiaoheiplay/internal/domain"
+)
+
+func (r *GormRepo) CreateVPSExtraIP(ctx context.Context, ip *domain.VPSExtraIP) error {
+	row := vpsExtraIPRow{
+		VPSID:       ip.VPSID,
+		OrderItemID: ip.OrderItemID,
+		Family:      ip.Family,
+		Address:     ip.Address,
+	}
+	if err := r.gdb.WithContext(ctx).Create(&row).Error; err != nil {
+		return err
+	}
+	ip.ID = row.ID
+	ip.CreatedAt = row.CreatedAt
+	return nil
+}
+
+func (r *GormRepo) ListVPSExtraIPs(ctx context.Context, vpsID int64) ([]domain.VPSExtraIP, error) {
+	var rows []vpsExtraIPRow
+	if err := r.gdb.WithContext(ctx).Where("vps_id = ?", vpsID).Order("id ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]domain.VPSExtraIP, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, domain.VPSExtraIP{
+			ID:          row.ID,
+			VPSID:       row.VPSID,
+			OrderItemID: row.OrderItemID,
+			Family:      row.Family,
+			Address:     row.Address,
+			CreatedAt:   row.CreatedAt,
+		})
+	}
+	return out, nil
+}
+
+func (r *GormRepo) DeleteVPSExtraIP(ctx context.Context, id int64) error {
+	return r.gdb.WithContext(ctx).Delete(&vpsExtraIPRow{}, id).Error
+}
+
+func (r *GormRepo) FindVPSExtraIPByAddress(ctx context.Context, address string) (domain.VPSExtraIP, error) {
+	var row vpsExtraIPRow
+	if err := r.gdb.WithContext(ctx).Where("address = ?", address).Order("id DESC").First(&row).Error; err != nil {
+		return domain.VPSExtraIP{}, r.ensure(err)
+	}
+	return domain.VPSExtraIP{
+		ID:          row.ID,
+		VPSID:       row.VPSID,
+		OrderItemID: row.OrderItemID,
+		Family:      row.Family,
+		Address:     row.Address,
+		CreatedAt:   row.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,73 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"xiaoheiplay/internal/domain"
+)
+
+// AccumulateVPSMetric adds one reading to its bucket, creating the bucket on
+// first use. Buckets keep sums so averages stay exact as readings arrive.
+func (r *GormRepo) AccumulateVPSMetric(ctx context.Context, point domain.VPSMetricPoint) error {
+	db := r.gdb.WithContext(ctx)
+	where := db.Model(&vpsMetricRow{}).Where("vps_id = ? AND resolution = ? AND bucket_at = ?", point.VPSID, string(point.Resolution), point.BucketAt)
+	res := where.Updates(map[string]any{
+		"cpu_sum":       gorm.Expr("cpu_sum + ?", point.CPUPercent),
+		"memory_sum":    gorm.Expr("memory_sum + ?", point.MemoryPercent),
+		"storage_sum":   gorm.Expr("storage_sum + ?", point.StoragePercent),
+		"bytes_in_sum":  gorm.Expr("bytes_in_sum + ?", point.BytesIn),
+		"bytes_out_sum": gorm.Expr("bytes_out_sum + ?", point.BytesOut),
+		"samples":       gorm.Expr("samples + 1"),
+	})
+	if res.Error != nil || res.RowsAffected > 0 {
+		return res.Error
+	}
+	return db.Create(&vpsMetricRow{
+		VPSID:       point.VPSID,
+		Resolution:  string(point.Resolution),
+		BucketAt:    point.BucketAt,
+		CPUSum:      point.CPUPercent,
+		MemorySum:   point.MemoryPercent,
+		StorageSum:  point.StoragePercent,
+		BytesInSum:  point.BytesIn,
+		BytesOutSum: point.BytesOut,
+		Samples:     1,
+	}).Error
+}
+
+func (r *GormRepo) ListVPSMetrics(ctx context.Context, vpsID int64, resolution domain.MetricResolution, from, to time.Time) ([]domain.VPSMetricPoint, error) {
+	var rows []vpsMetricRow
+	if err := r.gdb.WithContext(ctx).
+		Where("vps_id = ? AND resolution = ? AND bucket_at >= ? AND bucket_at <= ?", vpsID, string(resolution), from, to).
+		Order("bucket_at ASC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]domain.VPSMetricPoint, 0, len(rows))
+	for _, row := range rows {
+		n := row.Samples
+		if n <= 0 {
+			continue
+		}
+		out = append(out, domain.VPSMetricPoint{
+			VPSID:          row.VPSID,
+			Resolution:     domain.MetricResolution(row.Resolution),
+			BucketAt:       row.BucketAt,
+			CPUPercent:     row.CPUSum / float64(n),
+			MemoryPercent:  row.MemorySum / float64(n),
+			StoragePercent: row.StorageSum / float64(n),
+			BytesIn:        row.BytesInSum / int64(n),
+			BytesOut:       row.BytesOutSum / int64(n),
+			Samples:        n,
+		})
+	}
+	return out, nil
+}
+
+func (r *GormRepo) PurgeVPSMetrics(ctx context.Context, resolution domain.MetricResolution, before time.Time) (int64, error) {
+	res := r.gdb.WithContext(ctx).Where("resolution = ? AND bucket_at < ?", string(resolution), before).Delete(&vpsMetricRow{})
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,133 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"xiaoheiplay/internal/domain"
+)
+
+func (r *GormRepo) CreateVPSOperation(ctx context.Context, op *domain.VPSOperation) error {
+	row := vpsOperationRow{
+		VPSID:        op.VPSID,
+		UserID:       op.UserID,
+		Action:       string(op.Action),
+		Status:       string(op.Status),
+		Progress:     op.Progress,
+		Estimated:    op.ProgressEstimated,
+		Message:      op.Message,
+		ParamsJSON:   op.ParamsJSON,
+		ErrorMessage: op.ErrorMessage,
+		StartedAt:    op.StartedAt,
+		FinishedAt:   op.FinishedAt,
+	}
+	if err := r.gdb.WithContext(ctx).Create(&row).Error; err != nil {
+		return err
+	}
+	op.ID = row.ID
+	op.CreatedAt = row.CreatedAt
+	op.UpdatedAt = row.UpdatedAt
+	return nil
+}
+
+func (r *GormRepo) GetVPSOperation(ctx context.Context, id int64) (domain.VPSOperation, error) {
+	var row vpsOperationRow
+	if err := r.gdb.WithContext(ctx).Where("id = ?", id).First(&row).Error; err != nil {
+		return domain.VPSOperation{}, r.ensure(err)
+	}
+	return fromVPSOperationRow(row), nil
+}
+
+func (r *GormRepo) UpdateVPSOperation(ctx context.Context, op domain.VPSOperation) error {
+	return r.gdb.WithContext(ctx).Model(&vpsOperationRow{}).Where("id = ?", op.ID).Updates(map[string]any{
+		"status":             string(op.Status),
+		"progress":           op.Progress,
+		"progress_estimated": op.ProgressEstimated,
+		"message":            op.Message,
+		"error_message":      op.ErrorMessage,
+		"started_at":         op.StartedAt,
+		"finished_at":        op.FinishedAt,
+		"updated_at":         time.Now(),
+	}).Error
+}
+
+func (r *GormRepo) ListVPSOperations(ctx context.Context, vpsID int64, limit, offset int) ([]domain.VPSOperation, int, error) {
+	q := r.gdb.WithContext(ctx).Model(&vpsOperationRow{})
+	if vpsID > 0 {
+		q = q.Where("vps_id = ?", vpsID)
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	var rows []vpsOperationRow
+	if err := q.Order("id DESC").Limit(limit).Offset(offset).Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	out := make([]domain.VPSOperation, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, fromVPSOperationRow(row))
+	}
+	return out, int(total), nil
+}
+
+func (r *GormRepo) ListVPSOperationsByStatus(ctx context.Context, statuses []domain.VPSOperationStatus, limit int) ([]domain.VPSOperation, error) {
+	if len(statuses) == 0 {
+		return nil, nil
+	}
+	if limit <= 0 {
+		limit = 20
+	}
+	values := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		values = append(values, string(status))
+	}
+	var rows []vpsOperationRow
+	if err := r.gdb.WithContext(ctx).
+		Where("status IN ?", values).
+		Order("id ASC").
+		Limit(limit).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]domain.VPSOperation, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, fromVPSOperationRow(row))
+	}
+	return out, nil
+}
+
+func (r *GormRepo) HasActiveVPSOperation(ctx context.Context, vpsID int64) (bool, error) {
+	if vpsID <= 0 {
+		return false, nil
+	}
+	var total int64
+	if err := r.gdb.WithContext(ctx).Model(&vpsOperationRow{}).
+		Where("vps_id = ? AND status IN ?", vpsID, []string{string(domain.VPSOperationStatusPending), string(domain.VPSOperationStatusRunning)}).
+		Count(&total).Error; err != nil {
+		return false, err
+	}
+	return total > 0, nil
+}
+
+func fromVPSOperationRow(row vpsOperationRow) domain.VPSOperation {
+	return domain.VPSOperation{
+		ID:                row.ID,
+		VPSID:             row.VPSID,
+		UserID:            row.UserID,
+		Action:            domain.VPSOperationAction(row.Action),
+		Status:            domain.VPSOperationStatus(row.Status),
+		Progress:          row.Progress,
+		ProgressEstimated: row.Estimated,
+		Message:           row.Message,
+		ParamsJSON:        row.ParamsJSON,
+		ErrorMessage:      row.ErrorMessage,
+		StartedAt:         row.StartedAt,
+		FinishedAt:        row.FinishedAt,
+		CreatedAt:         row.CreatedAt,
+		UpdatedAt:         row.UpdatedAt,
+	}
+}
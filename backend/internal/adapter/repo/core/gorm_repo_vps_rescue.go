@@ -0,0 +1,83 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"xiaoheiplay/internal/domain"
+)
+
+func (r *GormRepo) CreateRescueSession(ctx context.Context, session *domain.VPSRescueSession) error {
+	row := vpsRescueSessionRow{
+		VPSID:     session.VPSID,
+		UserID:    session.UserID,
+		ExpiresAt: session.ExpiresAt,
+	}
+	if err := r.gdb.WithContext(ctx).Create(&row).Error; err != nil {
+		return err
+	}
+	session.ID = row.ID
+	session.CreatedAt = row.CreatedAt
+	return nil
+}
+
+func (r *GormRepo) GetActiveRescueSession(ctx context.Context, vpsID int64) (domain.VPSRescueSession, error) {
+	var row vpsRescueSessionRow
+	if err := r.gdb.WithContext(ctx).Where("vps_id = ? AND ended_at IS NULL", vpsID).Order("id DESC").First(&row).Error; err != nil {
+		return domain.VPSRescueSession{}, r.ensure(err)
+	}
+	return fromVPSRescueSessionRow(row), nil
+}
+
+func (r *GormRepo) EndRescueSession(ctx context.Context, id int64, reason string, endedAt time.Time) error {
+	return r.gdb.WithContext(ctx).Model(&vpsRescueSessionRow{}).Where("id = ? AND ended_at IS NULL", id).Updates(map[string]any{
+		"ended_at":   endedAt,
+		"end_reason": reason,
+	}).Error
+}
+
+func (r *GormRepo) ListExpiredRescueSessions(ctx context.Context, now time.Time, limit int) ([]domain.VPSRescueSession, error) {
+	var rows []vpsRescueSessionRow
+	if err := r.gdb.WithContext(ctx).Where("ended_at IS NULL AND expires_at <= ?", now).Order("expires_at ASC").Limit(limit).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]domain.VPSRescueSession, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, fromVPSRescueSessionRow(row))
+	}
+	return out, nil
+}
+
+func (r *GormRepo) GetISOMount(ctx context.Context, vpsID int64) (domain.VPSISOMount, error) {
+	var row vpsISOMountRow
+	if err := r.gdb.WithContext(ctx).Where("vps_id = ?", vpsID).First(&row).Error; err != nil {
+		return domain.VPSISOMount{}, r.ensure(err)
+	}
+	return domain.VPSISOMount{VPSID: row.VPSID, ISOID: row.ISOID, ISOName: row.ISOName, MountedAt: row.MountedAt}, nil
+}
+
+func (r *GormRepo) SaveISOMount(ctx context.Context, mount domain.VPSISOMount) error {
+	row := vpsISOMountRow{VPSID: mount.VPSID, ISOID: mount.ISOID, ISOName: mount.ISOName, MountedAt: mount.MountedAt}
+	return r.gdb.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "vps_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"iso_id", "iso_name", "mounted_at"}),
+	}).Create(&row).Error
+}
+
+func (r *GormRepo) DeleteISOMount(ctx context.Context, vpsID int64) error {
+	return r.gdb.WithContext(ctx).Where("vps_id = ?", vpsID).Delete(&vpsISOMountRow{}).Error
+}
+
+func fromVPSRescueSessionRow(row vpsRescueSessionRow) domain.VPSRescueSession {
+	return domain.VPSRescueSession{
+		ID:        row.ID,
+		VPSID:     row.VPSID,
+		UserID:    row.UserID,
+		ExpiresAt: row.ExpiresAt,
+		EndedAt:   row.EndedAt,
+		EndReason: row.EndReason,
+		CreatedAt: row.CreatedAt,
+	}
+}
@@ -17,6 +17,7 @@ func migrateGorm(db *gorm.DB) error {
 		&captchaRow{},
 		&verificationCodeRow{},
 		&goodsTypeRow{},
+		&automationBackendRow{},
 		&regionRow{},
 		&planGroupRow{},
 		&packageRow{},
@@ -30,6 +31,7 @@ func migrateGorm(db *gorm.DB) error {
 		&adminAuditLogRow{},
 		&apiKeyRow{},
 		&userAPIKeyRow{},
+		&sshKeyRow{},
 		&settingRow{},
 		&settingListValueRow{},
 		&scheduledTaskConfigRow{},
@@ -42,6 +44,19 @@ func migrateGorm(db *gorm.DB) error {
 		&automationLogRow{},
 		&provisionJobRow{},
 		&resizeTaskRow{},
+		&vpsOperationRow{},
+		&reverseDNSChangeRow{},
+		&vpsExtraIPRow{},
+		&vpsRescueSessionRow{},
+		&vpsISOMountRow{},
+		&consoleSessionRow{},
+		&vpsMetricRow{},
+		&firewallTemplateRow{},
+		&firewallTemplateAttachmentRow{},
+		&vpsFirewallSyncRow{},
+		&vpsActionUsageRow{},
+		&vpsActivityRow{},
+		&provisionAttemptRow{},
 		&integrationSyncLogRow{},
 		&permissionGroupRow{},
 		&permissionGroupPermissionRow{},
@@ -63,6 +78,10 @@ func migrateGorm(db *gorm.DB) error {
 		&ticketRow{},
 		&ticketMessageRow{},
 		&ticketResourceRow{},
+		&abuseCaseRow{},
+		&abuseCaseLinkRow{},
+		&abuseEvidenceRow{},
+		&abuseCaseEventRow{},
 		&walletRow{},
 		&walletTransactionRow{},
 		&walletOrderRow{},
@@ -72,6 +91,10 @@ func migrateGorm(db *gorm.DB) error {
 		&realnameVerificationRow{},
 		&pluginInstallationRow{},
 		&pluginPaymentMethodRow{},
+		&pluginKVRow{},
+		&pluginLogRow{},
+		&pluginHookDeliveryRow{},
+		&pluginVersionRow{},
 		&probeNodeRow{},
 		&probeEnrollTokenRow{},
 		&probeStatusEventRow{},
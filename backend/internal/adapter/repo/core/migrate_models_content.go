@@ -49,6 +49,8 @@ type uploadRow struct {
 	URL        string    `gorm:"column:url;not null"`
 	Mime       string    `gorm:"column:mime;not null"`
 	Size       int64     `gorm:"column:size;not null"`
+	Backend    string    `gorm:"size:191;column:backend;not null;default:'local';index"`
+	ObjectKey  string    `gorm:"size:191;column:object_key;not null;default:'';index"`
 	UploaderID int64     `gorm:"column:uploader_id;not null;index"`
 	CreatedAt  time.Time `gorm:"column:created_at;not null;autoCreateTime"`
 }
@@ -93,3 +95,59 @@ type ticketResourceRow struct {
 }
 
 func (ticketResourceRow) TableName() string { return "ticket_resources" }
+
+type abuseCaseRow struct {
+	ID             int64      `gorm:"primaryKey;autoIncrement;column:id"`
+	Source         string     `gorm:"size:16;column:source;not null;default:admin"`
+	Category       string     `gorm:"size:32;column:category;not null;default:other"`
+	Reporter       string     `gorm:"size:255;column:reporter;not null;default:''"`
+	Subject        string     `gorm:"size:240;column:subject;not null"`
+	Description    string     `gorm:"size:10000;column:description;not null;default:''"`
+	Status         string     `gorm:"size:32;column:status;not null;default:open;index"`
+	AssigneeID     int64      `gorm:"column:assignee_id;not null;default:0"`
+	Resolution     string     `gorm:"size:2000;column:resolution;not null;default:''"`
+	AckDueAt       time.Time  `gorm:"column:ack_due_at;not null"`
+	ResolveDueAt   time.Time  `gorm:"column:resolve_due_at;not null;index"`
+	ResponseDueAt  *time.Time `gorm:"column:response_due_at"`
+	AcknowledgedAt *time.Time `gorm:"column:acknowledged_at"`
+	WarnedAt       *time.Time `gorm:"column:warned_at"`
+	ClosedAt       *time.Time `gorm:"column:closed_at"`
+	CreatedAt      time.Time  `gorm:"column:created_at;not null;autoCreateTime"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at;not null;autoUpdateTime"`
+}
+
+func (abuseCaseRow) TableName() string { return "abuse_cases" }
+
+type abuseCaseLinkRow struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement;column:id"`
+	CaseID     int64     `gorm:"column:case_id;not null;uniqueIndex:idx_abuse_case_links_target,priority:1"`
+	TargetType string    `gorm:"size:16;column:target_type;not null;uniqueIndex:idx_abuse_case_links_target,priority:2;index:idx_abuse_case_links_lookup,priority:1"`
+	TargetID   int64     `gorm:"column:target_id;not null;uniqueIndex:idx_abuse_case_links_target,priority:3;index:idx_abuse_case_links_lookup,priority:2"`
+	TicketID   int64     `gorm:"column:ticket_id;not null;default:0"`
+	CreatedAt  time.Time `gorm:"column:created_at;not null;autoCreateTime"`
+}
+
+func (abuseCaseLinkRow) TableName() string { return "abuse_case_links" }
+
+type abuseEvidenceRow struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement;column:id"`
+	CaseID    int64     `gorm:"column:case_id;not null;index"`
+	Kind      string    `gorm:"size:16;column:kind;not null"`
+	Content   string    `gorm:"size:10000;column:content;not null"`
+	UploadID  int64     `gorm:"column:upload_id;not null;default:0"`
+	AddedBy   int64     `gorm:"column:added_by;not null;default:0"`
+	CreatedAt time.Time `gorm:"column:created_at;not null;autoCreateTime"`
+}
+
+func (abuseEvidenceRow) TableName() string { return "abuse_evidence" }
+
+type abuseCaseEventRow struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement;column:id"`
+	CaseID     int64     `gorm:"column:case_id;not null;index"`
+	AdminID    int64     `gorm:"column:admin_id;not null;default:0"`
+	Type       string    `gorm:"size:32;column:type;not null"`
+	DetailJSON string    `gorm:"type:text;column:detail_json;not null"`
+	CreatedAt  time.Time `gorm:"column:created_at;not null;autoCreateTime"`
+}
+
+func (abuseCaseEventRow) TableName() string { return "abuse_case_events" }
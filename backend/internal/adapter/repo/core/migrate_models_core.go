@@ -69,6 +69,24 @@ type goodsTypeRow struct {
 
 func (goodsTypeRow) TableName() string { return "goods_types" }
 
+type automationBackendRow struct {
+	ID                  int64      `gorm:"primaryKey;autoIncrement;column:id"`
+	GoodsTypeID         int64      `gorm:"column:goods_type_id;not null;index;uniqueIndex:idx_automation_backends_unique"`
+	PluginID            string     `gorm:"size:191;column:plugin_id;not null;uniqueIndex:idx_automation_backends_unique"`
+	InstanceID          string     `gorm:"size:191;column:instance_id;not null;uniqueIndex:idx_automation_backends_unique"`
+	Weight              int        `gorm:"column:weight;not null;default:1"`
+	Capacity            int        `gorm:"column:capacity;not null;default:0"`
+	Enabled             int        `gorm:"column:enabled;not null;default:1"`
+	Health              string     `gorm:"size:32;column:health;not null;default:healthy"`
+	ConsecutiveFailures int        `gorm:"column:consecutive_failures;not null;default:0"`
+	LastError           string     `gorm:"column:last_error;not null;default:''"`
+	LastCheckedAt       *time.Time `gorm:"column:last_checked_at"`
+	CreatedAt           time.Time  `gorm:"column:created_at;not null;autoCreateTime"`
+	UpdatedAt           time.Time  `gorm:"column:updated_at;not null;autoUpdateTime"`
+}
+
+func (automationBackendRow) TableName() string { return "automation_backends" }
+
 type regionRow struct {
 	ID          int64     `gorm:"primaryKey;autoIncrement;column:id"`
 	GoodsTypeID int64     `gorm:"column:goods_type_id;not null;default:0;index;uniqueIndex:idx_regions_gt_code_unique"`
@@ -103,6 +121,10 @@ type planGroupRow struct {
 	AddBWMin          int       `gorm:"column:add_bw_min;not null;default:0"`
 	AddBWMax          int       `gorm:"column:add_bw_max;not null;default:0"`
 	AddBWStep         int       `gorm:"column:add_bw_step;not null;default:1"`
+	UnitIPv4          int64     `gorm:"column:unit_ipv4;not null;default:0"`
+	UnitIPv6          int64     `gorm:"column:unit_ipv6;not null;default:0"`
+	AddIPv4Max        int       `gorm:"column:add_ipv4_max;not null;default:0"`
+	AddIPv6Max        int       `gorm:"column:add_ipv6_max;not null;default:0"`
 	Active            int       `gorm:"column:active;not null;default:1"`
 	Visible           int       `gorm:"column:visible;not null;default:1"`
 	CapacityRemaining int       `gorm:"column:capacity_remaining;not null;default:-1"`
@@ -219,6 +241,8 @@ type vpsInstanceRow struct {
 	OrderItemID          int64      `gorm:"column:order_item_id;not null;index"`
 	AutomationInstanceID string     `gorm:"column:automation_instance_id;not null"`
 	GoodsTypeID          int64      `gorm:"column:goods_type_id;not null;default:0;index"`
+	BackendPluginID      string     `gorm:"size:191;column:backend_plugin_id;not null;default:'';index:idx_vps_instances_backend"`
+	BackendInstanceID    string     `gorm:"size:191;column:backend_instance_id;not null;default:'';index:idx_vps_instances_backend"`
 	Name                 string     `gorm:"size:128;column:name;not null"`
 	Region               string     `gorm:"column:region"`
 	RegionID             int64      `gorm:"column:region_id;not null;default:0"`
@@ -298,6 +322,17 @@ type userAPIKeyRow struct {
 
 func (userAPIKeyRow) TableName() string { return "user_api_keys" }
 
+type sshKeyRow struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement;column:id"`
+	UserID      int64     `gorm:"column:user_id;not null;uniqueIndex:idx_user_ssh_keys_fingerprint,priority:1"`
+	Name        string    `gorm:"column:name;not null"`
+	PublicKey   string    `gorm:"type:text;column:public_key;not null"`
+	Fingerprint string    `gorm:"size:191;column:fingerprint;not null;uniqueIndex:idx_user_ssh_keys_fingerprint,priority:2"`
+	CreatedAt   time.Time `gorm:"column:created_at;not null;autoCreateTime"`
+}
+
+func (sshKeyRow) TableName() string { return "user_ssh_keys" }
+
 type settingRow struct {
 	Key       string    `gorm:"size:191;primaryKey;column:key"`
 	ValueJSON string    `gorm:"column:value_json;not null"`
@@ -398,6 +433,177 @@ type resizeTaskRow struct {
 
 func (resizeTaskRow) TableName() string { return "resize_tasks" }
 
+type vpsOperationRow struct {
+	ID           int64      `gorm:"primaryKey;autoIncrement;column:id"`
+	VPSID        int64      `gorm:"column:vps_id;not null;index"`
+	UserID       int64      `gorm:"column:user_id;not null;index"`
+	Action       string     `gorm:"size:64;column:action;not null"`
+	Status       string     `gorm:"size:32;column:status;not null;index"`
+	Progress     int        `gorm:"column:progress;not null;default:0"`
+	Estimated    bool       `gorm:"column:progress_estimated;not null;default:false"`
+	Message      string     `gorm:"size:1000;column:message;not null;default:''"`
+	ParamsJSON   string     `gorm:"column:params_json;not null"`
+	ErrorMessage string     `gorm:"size:2000;column:error_message;not null;default:''"`
+	StartedAt    *time.Time `gorm:"column:started_at"`
+	FinishedAt   *time.Time `gorm:"column:finished_at"`
+	CreatedAt    time.Time  `gorm:"column:created_at;not null;autoCreateTime"`
+	UpdatedAt    time.Time  `gorm:"column:updated_at;not null;autoUpdateTime"`
+}
+
+func (vpsOperationRow) TableName() string { return "vps_operations" }
+
+type reverseDNSChangeRow struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement;column:id"`
+	VPSID     int64     `gorm:"column:vps_id;not null;index"`
+	UserID    int64     `gorm:"column:user_id;not null;default:0"`
+	AdminID   int64     `gorm:"column:admin_id;not null;default:0"`
+	IP        string    `gorm:"size:64;column:ip;not null"`
+	OldPTR    string    `gorm:"size:255;column:old_ptr;not null;default:''"`
+	NewPTR    string    `gorm:"size:255;column:new_ptr;not null;default:''"`
+	CreatedAt time.Time `gorm:"column:created_at;not null;autoCreateTime"`
+}
+
+func (reverseDNSChangeRow) TableName() string { return "vps_reverse_dns_changes" }
+
+type vpsExtraIPRow struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement;column:id"`
+	VPSID       int64     `gorm:"column:vps_id;not null;index"`
+	OrderItemID int64     `gorm:"column:order_item_id;not null;default:0;index"`
+	Family      string    `gorm:"size:8;column:family;not null"`
+	Address     string    `gorm:"size:64;column:address;not null"`
+	CreatedAt   time.Time `gorm:"column:created_at;not null;autoCreateTime"`
+}
+
+func (vpsExtraIPRow) TableName() string { return "vps_extra_ips" }
+
+type vpsRescueSessionRow struct {
+	ID        int64      `gorm:"primaryKey;autoIncrement;column:id"`
+	VPSID     int64      `gorm:"column:vps_id;not null;index"`
+	UserID    int64      `gorm:"column:user_id;not null;default:0"`
+	ExpiresAt time.Time  `gorm:"column:expires_at;not null;index"`
+	EndedAt   *time.Time `gorm:"column:ended_at;index"`
+	EndReason string     `gorm:"size:16;column:end_reason;not null;default:''"`
+	CreatedAt time.Time  `gorm:"column:created_at;not null;autoCreateTime"`
+}
+
+func (vpsRescueSessionRow) TableName() string { return "vps_rescue_sessions" }
+
+type vpsISOMountRow struct {
+	VPSID     int64     `gorm:"primaryKey;autoIncrement:false;column:vps_id"`
+	ISOID     string    `gorm:"size:64;column:iso_id;not null"`
+	ISOName   string    `gorm:"size:128;column:iso_name;not null;default:''"`
+	MountedAt time.Time `gorm:"column:mounted_at;not null"`
+}
+
+func (vpsISOMountRow) TableName() string { return "vps_iso_mounts" }
+
+type consoleSessionRow struct {
+	ID            int64      `gorm:"primaryKey;autoIncrement;column:id"`
+	VPSID         int64      `gorm:"column:vps_id;not null;index"`
+	UserID        int64      `gorm:"column:user_id;not null;index:idx_console_sessions_user_status,priority:1"`
+	Status        string     `gorm:"size:16;column:status;not null;index:idx_console_sessions_user_status,priority:2"`
+	ClientIP      string     `gorm:"size:64;column:client_ip;not null;default:''"`
+	UpstreamHost  string     `gorm:"size:255;column:upstream_host;not null;default:''"`
+	ExpiresAt     time.Time  `gorm:"column:expires_at;not null"`
+	StartedAt     *time.Time `gorm:"column:started_at"`
+	EndedAt       *time.Time `gorm:"column:ended_at"`
+	EndReason     string     `gorm:"size:16;column:end_reason;not null;default:''"`
+	ClientBytes   int64      `gorm:"column:client_bytes;not null;default:0"`
+	UpstreamBytes int64      `gorm:"column:upstream_bytes;not null;default:0"`
+	CreatedAt     time.Time  `gorm:"column:created_at;not null;autoCreateTime"`
+}
+
+func (consoleSessionRow) TableName() string { return "console_sessions" }
+
+type firewallTemplateRow struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement;column:id"`
+	UserID      int64     `gorm:"column:user_id;not null;default:0;index"`
+	GoodsTypeID int64     `gorm:"column:goods_type_id;not null;default:0;index"`
+	Name        string    `gorm:"size:64;column:name;not null"`
+	Description string    `gorm:"size:255;column:description;not null;default:''"`
+	RulesJSON   string    `gorm:"column:rules_json;not null"`
+	CreatedAt   time.Time `gorm:"column:created_at;not null;autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;not null;autoUpdateTime"`
+}
+
+func (firewallTemplateRow) TableName() string { return "firewall_templates" }
+
+type firewallTemplateAttachmentRow struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement;column:id"`
+	TemplateID int64     `gorm:"column:template_id;not null;uniqueIndex:idx_firewall_template_attachments_pair,priority:1"`
+	VPSID      int64     `gorm:"column:vps_id;not null;uniqueIndex:idx_firewall_template_attachments_pair,priority:2;index"`
+	CreatedAt  time.Time `gorm:"column:created_at;not null;autoCreateTime"`
+}
+
+func (firewallTemplateAttachmentRow) TableName() string { return "firewall_template_attachments" }
+
+type vpsFirewallSyncRow struct {
+	VPSID            int64      `gorm:"primaryKey;autoIncrement:false;column:vps_id"`
+	ManagedRulesJSON string     `gorm:"column:managed_rules_json;not null"`
+	Status           string     `gorm:"size:16;column:status;not null;index"`
+	LastError        string     `gorm:"size:500;column:last_error;not null;default:''"`
+	Attempts         int        `gorm:"column:attempts;not null;default:0"`
+	SyncedAt         *time.Time `gorm:"column:synced_at"`
+	UpdatedAt        time.Time  `gorm:"column:updated_at;not null;autoUpdateTime"`
+}
+
+func (vpsFirewallSyncRow) TableName() string { return "vps_firewall_syncs" }
+
+type vpsActionUsageRow struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement;column:id"`
+	VPSID     int64     `gorm:"column:vps_id;not null;index:idx_vps_action_usages_lookup,priority:1"`
+	Action    string    `gorm:"size:32;column:action;not null;index:idx_vps_action_usages_lookup,priority:2"`
+	CreatedAt time.Time `gorm:"column:created_at;not null;index:idx_vps_action_usages_lookup,priority:3"`
+}
+
+func (vpsActionUsageRow) TableName() string { return "vps_action_usages" }
+
+type vpsActivityRow struct {
+	ID           int64     `gorm:"primaryKey;autoIncrement;column:id"`
+	VPSID        int64     `gorm:"column:vps_id;not null;index:idx_vps_activities_vps_created,priority:1"`
+	UserID       int64     `gorm:"column:user_id;not null;default:0;index"`
+	ActorType    string    `gorm:"size:16;column:actor_type;not null"`
+	ActorID      int64     `gorm:"column:actor_id;not null;default:0"`
+	Action       string    `gorm:"size:32;column:action;not null"`
+	Detail       string    `gorm:"size:255;column:detail;not null;default:''"`
+	IP           string    `gorm:"size:64;column:ip;not null;default:''"`
+	Result       string    `gorm:"size:16;column:result;not null"`
+	ErrorMessage string    `gorm:"size:255;column:error_message;not null;default:''"`
+	CreatedAt    time.Time `gorm:"column:created_at;not null;autoCreateTime;index:idx_vps_activities_vps_created,priority:2;index"`
+}
+
+func (vpsActivityRow) TableName() string { return "vps_activities" }
+
+type provisionAttemptRow struct {
+	ID           int64     `gorm:"primaryKey;autoIncrement;column:id"`
+	OrderID      int64     `gorm:"column:order_id;not null;index"`
+	OrderItemID  int64     `gorm:"column:order_item_id;not null;index"`
+	Attempt      int       `gorm:"column:attempt;not null;default:0"`
+	Strategy     string    `gorm:"size:32;column:strategy;not null"`
+	LineID       int64     `gorm:"column:line_id;not null;default:0"`
+	PackageID    int64     `gorm:"column:package_id;not null;default:0"`
+	Success      int       `gorm:"column:success;not null;default:0;index"`
+	ErrorMessage string    `gorm:"column:error_message;not null;default:''"`
+	CreatedAt    time.Time `gorm:"column:created_at;not null;autoCreateTime"`
+}
+
+func (provisionAttemptRow) TableName() string { return "provision_attempts" }
+
+type vpsMetricRow struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement;column:id"`
+	VPSID       int64     `gorm:"column:vps_id;not null;uniqueIndex:idx_vps_metrics_bucket,priority:1"`
+	Resolution  string    `gorm:"size:8;column:resolution;not null;uniqueIndex:idx_vps_metrics_bucket,priority:2;index:idx_vps_metrics_purge,priority:1"`
+	BucketAt    time.Time `gorm:"column:bucket_at;not null;uniqueIndex:idx_vps_metrics_bucket,priority:3;index:idx_vps_metrics_purge,priority:2"`
+	CPUSum      float64   `gorm:"column:cpu_sum;not null;default:0"`
+	MemorySum   float64   `gorm:"column:memory_sum;not null;default:0"`
+	StorageSum  float64   `gorm:"column:storage_sum;not null;default:0"`
+	BytesInSum  int64     `gorm:"column:bytes_in_sum;not null;default:0"`
+	BytesOutSum int64     `gorm:"column:bytes_out_sum;not null;default:0"`
+	Samples     int       `gorm:"column:samples;not null;default:0"`
+}
+
+func (vpsMetricRow) TableName() string { return "vps_metrics" }
+
 type integrationSyncLogRow struct {
 	ID        int64     `gorm:"primaryKey;autoIncrement;column:id"`
 	Target    string    `gorm:"column:target;not null"`
@@ -30,3 +30,65 @@ type pluginPaymentMethodRow struct {
 }
 
 func (pluginPaymentMethodRow) TableName() string { return "plugin_payment_methods" }
+
+type pluginKVRow struct {
+	ID         int64      `gorm:"primaryKey;autoIncrement;column:id"`
+	Category   string     `gorm:"size:191;column:category;not null;uniqueIndex:idx_plugin_kv_unique"`
+	PluginID   string     `gorm:"size:191;column:plugin_id;not null;uniqueIndex:idx_plugin_kv_unique"`
+	InstanceID string     `gorm:"size:191;column:instance_id;not null;uniqueIndex:idx_plugin_kv_unique"`
+	Key        string     `gorm:"size:191;column:kv_key;not null;uniqueIndex:idx_plugin_kv_unique"`
+	Value      []byte     `gorm:"column:value;not null"`
+	ExpiresAt  *time.Time `gorm:"column:expires_at;index"`
+	CreatedAt  time.Time  `gorm:"column:created_at;not null;autoCreateTime"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at;not null;autoUpdateTime"`
+}
+
+func (pluginKVRow) TableName() string { return "plugin_kv" }
+
+type pluginLogRow struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement;column:id"`
+	Category   string    `gorm:"size:191;column:category;not null;index:idx_plugin_logs_instance,priority:1"`
+	PluginID   string    `gorm:"size:191;column:plugin_id;not null;index:idx_plugin_logs_instance,priority:2"`
+	InstanceID string    `gorm:"size:191;column:instance_id;not null;index:idx_plugin_logs_instance,priority:3"`
+	Level      string    `gorm:"size:16;column:level;not null"`
+	Message    string    `gorm:"type:text;column:message;not null"`
+	FieldsJSON string    `gorm:"type:text;column:fields_json;not null"`
+	CreatedAt  time.Time `gorm:"column:created_at;not null;autoCreateTime;index"`
+}
+
+func (pluginLogRow) TableName() string { return "plugin_logs" }
+
+type pluginHookDeliveryRow struct {
+	ID            int64      `gorm:"primaryKey;autoIncrement;column:id"`
+	EventID       string     `gorm:"size:64;column:event_id;not null;index"`
+	Category      string     `gorm:"size:191;column:category;not null;index:idx_plugin_hook_deliveries_instance,priority:1"`
+	PluginID      string     `gorm:"size:191;column:plugin_id;not null;index:idx_plugin_hook_deliveries_instance,priority:2"`
+	InstanceID    string     `gorm:"size:191;column:instance_id;not null;index:idx_plugin_hook_deliveries_instance,priority:3"`
+	Event         string     `gorm:"size:64;column:event;not null"`
+	PayloadJSON   string     `gorm:"type:text;column:payload_json;not null"`
+	Status        string     `gorm:"size:16;column:status;not null;index:idx_plugin_hook_deliveries_due,priority:1"`
+	Attempts      int        `gorm:"column:attempts;not null;default:0"`
+	LastError     string     `gorm:"type:text;column:last_error;not null"`
+	NextAttemptAt time.Time  `gorm:"column:next_attempt_at;not null;index:idx_plugin_hook_deliveries_due,priority:2"`
+	OccurredAt    time.Time  `gorm:"column:occurred_at;not null"`
+	DeliveredAt   *time.Time `gorm:"column:delivered_at"`
+	CreatedAt     time.Time  `gorm:"column:created_at;not null;autoCreateTime"`
+	UpdatedAt     time.Time  `gorm:"column:updated_at;not null;autoUpdateTime"`
+}
+
+func (pluginHookDeliveryRow) TableName() string { return "plugin_hook_deliveries" }
+
+type pluginVersionRow struct {
+	ID              int64      `gorm:"primaryKey;autoIncrement;column:id"`
+	Category        string     `gorm:"size:191;column:category;not null;uniqueIndex:idx_plugin_versions_unique"`
+	PluginID        string     `gorm:"size:191;column:plugin_id;not null;uniqueIndex:idx_plugin_versions_unique"`
+	Version         string     `gorm:"size:64;column:version;not null;uniqueIndex:idx_plugin_versions_unique"`
+	SignatureStatus string     `gorm:"column:signature_status;not null;default:unsigned"`
+	Status          string     `gorm:"size:16;column:status;not null"`
+	LastError       string     `gorm:"type:text;column:last_error;not null"`
+	ActivatedAt     *time.Time `gorm:"column:activated_at"`
+	CreatedAt       time.Time  `gorm:"column:created_at;not null;autoCreateTime"`
+	UpdatedAt       time.Time  `gorm:"column:updated_at;not null;autoUpdateTime"`
+}
+
+func (pluginVersionRow) TableName() string { return "plugin_versions" }
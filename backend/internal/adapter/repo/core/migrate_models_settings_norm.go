@@ -31,6 +31,8 @@ type robotWebhookRow struct {
 	Secret     string    `gorm:"size:512;column:secret;not null;default:''"`
 	Enabled    int       `gorm:"column:enabled;not null;default:1"`
 	EventsJSON string    `gorm:"type:text;column:events_json;not null"`
+	PluginID   string    `gorm:"size:191;column:plugin_id;not null;default:''"`
+	InstanceID string    `gorm:"size:191;column:instance_id;not null;default:''"`
 	SortOrder  int       `gorm:"column:sort_order;not null;default:0"`
 	CreatedAt  time.Time `gorm:"column:created_at;not null;autoCreateTime"`
 	UpdatedAt  time.Time `gorm:"column:updated_at;not null;autoUpdateTime"`
@@ -24,6 +24,7 @@ type BillingCycleRepo struct{ *GormRepo }
 type AutomationLogRepo struct{ *GormRepo }
 type ProvisionJobRepo struct{ *GormRepo }
 type ResizeTaskRepo struct{ *GormRepo }
+type VPSOperationRepo struct{ *GormRepo }
 type IntegrationLogRepo struct{ *GormRepo }
 type PermissionGroupRepo struct{ *GormRepo }
 type UserTierRepo struct{ *GormRepo }
@@ -36,6 +37,7 @@ type CMSPostRepo struct{ *GormRepo }
 type CMSBlockRepo struct{ *GormRepo }
 type UploadRepo struct{ *GormRepo }
 type TicketRepo struct{ *GormRepo }
+type AbuseCaseRepo struct{ *GormRepo }
 type NotificationRepo struct{ *GormRepo }
 type PushTokenRepo struct{ *GormRepo }
 type WalletRepo struct{ *GormRepo }
@@ -64,6 +66,9 @@ func NewAutomationLogRepo(gdb *gorm.DB) *AutomationLogRepo {
 }
 func NewProvisionJobRepo(gdb *gorm.DB) *ProvisionJobRepo { return &ProvisionJobRepo{NewGormRepo(gdb)} }
 func NewResizeTaskRepo(gdb *gorm.DB) *ResizeTaskRepo     { return &ResizeTaskRepo{NewGormRepo(gdb)} }
+func NewVPSOperationRepo(gdb *gorm.DB) *VPSOperationRepo {
+	return &VPSOperationRepo{NewGormRepo(gdb)}
+}
 func NewIntegrationLogRepo(gdb *gorm.DB) *IntegrationLogRepo {
 	return &IntegrationLogRepo{NewGormRepo(gdb)}
 }
@@ -84,6 +89,7 @@ func NewCMSPostRepo(gdb *gorm.DB) *CMSPostRepo           { return &CMSPostRepo{N
 func NewCMSBlockRepo(gdb *gorm.DB) *CMSBlockRepo         { return &CMSBlockRepo{NewGormRepo(gdb)} }
 func NewUploadRepo(gdb *gorm.DB) *UploadRepo             { return &UploadRepo{NewGormRepo(gdb)} }
 func NewTicketRepo(gdb *gorm.DB) *TicketRepo             { return &TicketRepo{NewGormRepo(gdb)} }
+func NewAbuseCaseRepo(gdb *gorm.DB) *AbuseCaseRepo       { return &AbuseCaseRepo{NewGormRepo(gdb)} }
 func NewNotificationRepo(gdb *gorm.DB) *NotificationRepo { return &NotificationRepo{NewGormRepo(gdb)} }
 func NewPushTokenRepo(gdb *gorm.DB) *PushTokenRepo       { return &PushTokenRepo{NewGormRepo(gdb)} }
 func NewWalletRepo(gdb *gorm.DB) *WalletRepo             { return &WalletRepo{NewGormRepo(gdb)} }
@@ -101,23 +107,39 @@ func NewProbeLogSessionRepo(gdb *gorm.DB) *ProbeLogSessionRepo {
 
 var (
 	_ appports.UserRepository                = (*UserRepo)(nil)
+	_ appports.SSHKeyRepository              = (*UserRepo)(nil)
 	_ appports.CaptchaRepository             = (*CaptchaRepo)(nil)
 	_ appports.CatalogRepository             = (*CatalogRepo)(nil)
+	_ appports.AutomationBackendRepository   = (*CatalogRepo)(nil)
 	_ appports.SystemImageRepository         = (*SystemImageRepo)(nil)
 	_ appports.CartRepository                = (*CartRepo)(nil)
 	_ appports.OrderRepository               = (*OrderRepo)(nil)
 	_ appports.OrderItemRepository           = (*OrderItemRepo)(nil)
 	_ appports.PaymentRepository             = (*PaymentRepo)(nil)
 	_ appports.VPSRepository                 = (*VPSRepo)(nil)
+	_ appports.ReverseDNSLogRepository       = (*VPSRepo)(nil)
+	_ appports.VPSExtraIPRepository          = (*VPSRepo)(nil)
+	_ appports.VPSRescueRepository           = (*VPSRepo)(nil)
+	_ appports.VPSMetricRepository           = (*VPSRepo)(nil)
+	_ appports.ConsoleSessionRepository      = (*VPSRepo)(nil)
+	_ appports.FirewallTemplateRepository    = (*VPSRepo)(nil)
+	_ appports.VPSActionUsageRepository      = (*VPSRepo)(nil)
+	_ appports.VPSActivityRepository         = (*VPSRepo)(nil)
 	_ appports.EventRepository               = (*EventRepo)(nil)
 	_ appports.APIKeyRepository              = (*APIKeyRepo)(nil)
 	_ appports.UserAPIKeyRepository          = (*APIKeyRepo)(nil)
 	_ appports.SettingsRepository            = (*SettingsRepo)(nil)
+	_ appports.PluginKVRepository            = (*SettingsRepo)(nil)
+	_ appports.PluginLogRepository           = (*SettingsRepo)(nil)
+	_ appports.PluginHookDeliveryRepository  = (*SettingsRepo)(nil)
+	_ appports.PluginVersionRepository       = (*SettingsRepo)(nil)
 	_ appports.AuditRepository               = (*AuditRepo)(nil)
 	_ appports.BillingCycleRepository        = (*BillingCycleRepo)(nil)
 	_ appports.AutomationLogRepository       = (*AutomationLogRepo)(nil)
 	_ appports.ProvisionJobRepository        = (*ProvisionJobRepo)(nil)
+	_ appports.ProvisionAttemptRepository    = (*ProvisionJobRepo)(nil)
 	_ appports.ResizeTaskRepository          = (*ResizeTaskRepo)(nil)
+	_ appports.VPSOperationRepository        = (*VPSOperationRepo)(nil)
 	_ appports.IntegrationLogRepository      = (*IntegrationLogRepo)(nil)
 	_ appports.PermissionGroupRepository     = (*PermissionGroupRepo)(nil)
 	_ appports.UserTierRepository            = (*UserTierRepo)(nil)
@@ -130,6 +152,7 @@ var (
 	_ appports.CMSBlockRepository            = (*CMSBlockRepo)(nil)
 	_ appports.UploadRepository              = (*UploadRepo)(nil)
 	_ appports.TicketRepository              = (*TicketRepo)(nil)
+	_ appports.AbuseCaseRepository           = (*AbuseCaseRepo)(nil)
 	_ appports.NotificationRepository        = (*NotificationRepo)(nil)
 	_ appports.PushTokenRepository           = (*PushTokenRepo)(nil)
 	_ appports.WalletRepository              = (*WalletRepo)(nil)
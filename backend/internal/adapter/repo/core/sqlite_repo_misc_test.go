@@ -157,3 +157,45 @@ func TestSQLiteRepo_PaymentsTicketsAndInstances(t *testing.T) {
 			got, inst.GoodsTypeID, inst.RegionID, inst.LineID, inst.PackageID)
 	}
 }
+
+func TestSQLiteRepo_PluginKV(t *testing.T) {
+	_, r := newTestRepo(t)
+	ctx := context.Background()
+
+	put := func(key, value string, expires *time.Time) {
+		t.Helper()
+		kv := &domain.PluginKV{Category: "payment", PluginID: "ezpay", InstanceID: "default", Key: key, Value: []byte(value), ExpiresAt: expires}
+		if err := r.PutPluginKV(ctx, kv); err != nil {
+			t.Fatalf("put %s: %v", key, err)
+		}
+	}
+	past := time.Now().Add(-time.Minute)
+	put("link:a", "1", nil)
+	put("link:a", "2", nil)
+	put("link_b", "3", nil)
+	put("stale", "4", &past)
+
+	got, err := r.GetPluginKV(ctx, "payment", "ezpay", "default", "link:a")
+	if err != nil || string(got.Value) != "2" {
+		t.Fatalf("get: %+v %v", got, err)
+	}
+	if _, err := r.GetPluginKV(ctx, "payment", "ezpay", "other", "link:a"); err == nil {
+		t.Fatalf("expected instance namespacing")
+	}
+	if _, err := r.GetPluginKV(ctx, "payment", "ezpay", "default", "stale"); err == nil {
+		t.Fatalf("expected expired key to be hidden")
+	}
+	items, err := r.ListPluginKV(ctx, "payment", "ezpay", "default", "link_", 10)
+	if err != nil || len(items) != 1 || items[0].Key != "link_b" {
+		t.Fatalf("list prefix: %+v %v", items, err)
+	}
+	if n, err := r.CountPluginKV(ctx, "payment", "ezpay", "default"); err != nil || n != 2 {
+		t.Fatalf("count: %d %v", n, err)
+	}
+	if err := r.DeletePluginKVByInstance(ctx, "payment", "ezpay", "default"); err != nil {
+		t.Fatalf("delete instance: %v", err)
+	}
+	if n, _ := r.CountPluginKV(ctx, "payment", "ezpay", "default"); n != 0 {
+		t.Fatalf("expected empty store, got %d", n)
+	}
+}
@@ -18,6 +18,11 @@ import (
 type WebhookNotifier struct {
 	settings appports.SettingsRepository
 	http     *http.Client
+	notify   notifySender
+}
+
+type notifySender interface {
+	Send(ctx context.Context, pluginID, instanceID string, msg appshared.NotifyMessage) (appshared.NotifyDelivery, error)
 }
 
 func NewWebhookNotifier(settings appports.SettingsRepository) *WebhookNotifier {
@@ -27,13 +32,12 @@ func NewWebhookNotifier(settings appports.SettingsRepository) *WebhookNotifier {
 	}
 }
 
-func (n *WebhookNotifier) NotifyOrderEvent(ctx context.Context, ev domain.OrderEvent) error {
-	event := ev.Type
-	webhooks := n.loadWebhooks(ctx)
-	if len(webhooks) == 0 {
-		return nil
-	}
+// SetNotifySender enables webhooks that deliver through a notify plugin.
+func (n *WebhookNotifier) SetNotifySender(sender notifySender) {
+	n.notify = sender
+}
 
+func (n *WebhookNotifier) NotifyOrderEvent(ctx context.Context, ev domain.OrderEvent) error {
 	envelope := map[string]any{
 		"order_id":    ev.OrderID,
 		"seq":         ev.Seq,
@@ -42,10 +46,65 @@ func (n *WebhookNotifier) NotifyOrderEvent(ctx context.Context, ev domain.OrderE
 		"data":        json.RawMessage(ev.DataJSON),
 		"data_string": ev.DataJSON,
 	}
+	msg := appshared.NotifyMessage{
+		Title:    fmt.Sprintf("%s #%d", ev.Type, ev.OrderID),
+		Body:     fmt.Sprintf("**%s**\n\n- order_id: %d\n- seq: %d\n\n```json\n%s\n```", ev.Type, ev.OrderID, ev.Seq, ev.DataJSON),
+		Markdown: true,
+		Event:    ev.Type,
+	}
+	return n.post(ctx, ev.Type, envelope, msg)
+}
+
+// NotifyPluginRuntimeEvent sends plugin crash, degraded and recovered events
+// to the robot webhooks subscribed to them.
+func (n *WebhookNotifier) NotifyPluginRuntimeEvent(ctx context.Context, ev appshared.PluginRuntimeEvent) error {
+	data, _ := json.Marshal(ev)
+	envelope := map[string]any{
+		"event":       ev.Type,
+		"created_at":  ev.OccurredAt.Unix(),
+		"data":        json.RawMessage(data),
+		"data_string": string(data),
+	}
+	body := fmt.Sprintf("**%s**\n\n- plugin: %s/%s/%s\n- failures: %d\n- restarts: %d", ev.Type, ev.Category, ev.PluginID, ev.InstanceID, ev.Failures, ev.Restarts)
+	if ev.Error != "" {
+		body += "\n- error: " + ev.Error
+	}
+	if ev.NextRestartAt != nil {
+		body += "\n- next_restart_at: " + ev.NextRestartAt.Format(time.RFC3339)
+	}
+	msg := appshared.NotifyMessage{
+		Title:    fmt.Sprintf("%s %s/%s", ev.Type, ev.Category, ev.PluginID),
+		Body:     body,
+		Markdown: true,
+		Event:    ev.Type,
+	}
+	return n.post(ctx, ev.Type, envelope, msg)
+}
+
+// post sends the event to every matching webhook: notify plugin entries get
+// msg, plain entries get envelope as a signed JSON POST.
+func (n *WebhookNotifier) post(ctx context.Context, event string, envelope map[string]any, msg appshared.NotifyMessage) error {
+	webhooks := n.loadWebhooks(ctx)
+	if len(webhooks) == 0 {
+		return nil
+	}
 	body, _ := json.Marshal(envelope)
 
 	for _, hook := range webhooks {
-		if !hook.Enabled || hook.URL == "" || !hook.MatchesEvent(event) {
+		if !hook.Enabled || !hook.MatchesEvent(event) {
+			continue
+		}
+		if hook.PluginID != "" {
+			if n.notify == nil {
+				continue
+			}
+			msg.Target = hook.URL
+			if _, err := n.notify.Send(ctx, hook.PluginID, hook.InstanceID, msg); err != nil {
+				return err
+			}
+			continue
+		}
+		if hook.URL == "" {
 			continue
 		}
 		req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	appshared "xiaoheiplay/internal/app/shared"
 	"xiaoheiplay/internal/domain"
 	"xiaoheiplay/internal/testutil"
 )
@@ -16,3 +17,42 @@ func TestWebhookNotifier_NoWebhooks(t *testing.T) {
 		t.Fatalf("expected nil, got %v", err)
 	}
 }
+
+type fakeNotifySender struct {
+	pluginID   string
+	instanceID string
+	msgs       []appshared.NotifyMessage
+}
+
+func (f *fakeNotifySender) Send(_ context.Context, pluginID, instanceID string, msg appshared.NotifyMessage) (appshared.NotifyDelivery, error) {
+	f.pluginID, f.instanceID = pluginID, instanceID
+	f.msgs = append(f.msgs, msg)
+	return appshared.NotifyDelivery{MessageID: "1"}, nil
+}
+
+func TestWebhookNotifier_RoutesPluginWebhooks(t *testing.T) {
+	_, repo := testutil.NewTestDB(t, false)
+	hooks := `[{"name":"ops","url":"chat-1","plugin_id":"mock_notify","instance_id":"default","enabled":true,"events":["order.approved"]}]`
+	if err := repo.UpsertSetting(context.Background(), domain.Setting{Key: "robot_webhooks", ValueJSON: hooks}); err != nil {
+		t.Fatalf("upsert setting: %v", err)
+	}
+	sender := &fakeNotifySender{}
+	notifier := NewWebhookNotifier(repo)
+	notifier.SetNotifySender(sender)
+
+	if err := notifier.NotifyOrderEvent(context.Background(), domain.OrderEvent{OrderID: 7, Seq: 1, Type: "order.approved", DataJSON: `{}`, CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	if len(sender.msgs) != 1 || sender.pluginID != "mock_notify" || sender.instanceID != "default" {
+		t.Fatalf("expected one message to mock_notify/default, got %+v", sender)
+	}
+	if msg := sender.msgs[0]; msg.Target != "chat-1" || msg.Event != "order.approved" || !msg.Markdown || msg.Title == "" {
+		t.Fatalf("unexpected message: %+v", msg)
+	}
+	if err := notifier.NotifyOrderEvent(context.Background(), domain.OrderEvent{OrderID: 7, Seq: 2, Type: "order.rejected", DataJSON: `{}`, CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	if len(sender.msgs) != 1 {
+		t.Fatalf("unsubscribed event must not be sent, got %d messages", len(sender.msgs))
+	}
+}
@@ -252,6 +252,8 @@ func EnsureSettings(gdb *gorm.DB) error {
 		"payment_plugins":                          "[]",
 		"payment_plugin_dir":                       "plugins/payment",
 		"payment_plugin_upload_password":           defaultPluginUploadPassword,
+		"upload_storage_plugin_id":                 "",
+		"upload_storage_instance_id":               "default",
 		"robot_webhook_url":                        "",
 		"robot_webhook_secret":                     "",
 		"robot_webhook_enabled":                    "false",
@@ -306,6 +308,8 @@ func EnsureSettings(gdb *gorm.DB) error {
 		"scheduled_task_run_retention_days":        "14",
 		"probe_status_event_retention_days":        "30",
 		"probe_log_session_retention_days":         "7",
+		"plugin_log_retention_days":                "14",
+		"plugin_hook_delivery_retention_days":      "14",
 		"provision_watchdog_max_jobs":              "8",
 		"provision_watchdog_max_minutes":           "20",
 		"site_name":                                "Cloud Console",
@@ -342,6 +346,20 @@ func EnsureSettings(gdb *gorm.DB) error {
 		"auth_geetest_captcha_id":                  "",
 		"auth_geetest_captcha_key":                 "",
 		"auth_geetest_api_server":                  "https://gcaptcha4.geetest.com",
+		"auth_captcha_plugin_id":                   "",
+		"auth_captcha_instance_id":                 "default",
+		"auth_register_captcha_provider":           "",
+		"auth_register_captcha_plugin_id":          "",
+		"auth_register_captcha_instance_id":        "",
+		"auth_login_captcha_provider":              "",
+		"auth_login_captcha_plugin_id":             "",
+		"auth_login_captcha_instance_id":           "",
+		"auth_password_reset_captcha_provider":     "",
+		"auth_password_reset_captcha_plugin_id":    "",
+		"auth_password_reset_captcha_instance_id":  "",
+		"auth_order_submit_captcha_provider":       "",
+		"auth_order_submit_captcha_plugin_id":      "",
+		"auth_order_submit_captcha_instance_id":    "",
 		"auth_register_email_subject":              "Your verification code",
 		"auth_register_email_body":                 "Your verification code is: {{code}}",
 		"auth_register_sms_plugin_id":              "",
@@ -357,6 +375,8 @@ func EnsureSettings(gdb *gorm.DB) error {
 		"auth_geoip_mmdb_path":                     "",
 		"auth_password_reset_enabled":              "true",
 		"auth_password_reset_verify_ttl_sec":       "600",
+		"auth_password_reset_captcha_enabled":      "false",
+		"auth_order_submit_captcha_enabled":        "false",
 		"auth_sms_code_len":                        "6",
 		"auth_sms_code_complexity":                 "digits",
 		"auth_email_code_len":                      "6",
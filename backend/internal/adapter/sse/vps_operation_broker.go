@@ -0,0 +1,127 @@
+package sse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"xiaoheiplay/internal/domain"
+)
+
+// VPSOperationBroker fans out VPS operation updates to live subscribers.
+// Operation records are persisted by the operation service, so the broker
+// keeps no history; streams start with a snapshot supplied by the caller.
+type VPSOperationBroker struct {
+	mu   sync.RWMutex
+	subs map[int64]map[chan domain.VPSOperation]struct{}
+}
+
+type vpsOperationEvent struct {
+	ID         int64      `json:"id"`
+	VPSID      int64      `json:"vps_id"`
+	Action     string     `json:"action"`
+	Status     string     `json:"status"`
+	Progress   int        `json:"progress"`
+	Estimated  bool       `json:"progress_estimated"`
+	Message    string     `json:"message"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func NewVPSOperationBroker() *VPSOperationBroker {
+	return &VPSOperationBroker{subs: make(map[int64]map[chan domain.VPSOperation]struct{})}
+}
+
+func (b *VPSOperationBroker) PublishVPSOperation(ctx context.Context, op domain.VPSOperation) {
+	_ = ctx
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for ch := range b.subs[op.VPSID] {
+		select {
+		case ch <- op:
+		default:
+		}
+	}
+}
+
+func (b *VPSOperationBroker) subscribe(vpsID int64) chan domain.VPSOperation {
+	ch := make(chan domain.VPSOperation, 16)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs[vpsID] == nil {
+		b.subs[vpsID] = make(map[chan domain.VPSOperation]struct{})
+	}
+	b.subs[vpsID][ch] = struct{}{}
+	return ch
+}
+
+func (b *VPSOperationBroker) unsubscribe(vpsID int64, ch chan domain.VPSOperation) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs[vpsID] != nil {
+		delete(b.subs[vpsID], ch)
+		if len(b.subs[vpsID]) == 0 {
+			delete(b.subs, vpsID)
+		}
+		close(ch)
+	}
+}
+
+func (b *VPSOperationBroker) StreamVPSOperations(ctx context.Context, w http.ResponseWriter, vpsID int64, initial []domain.VPSOperation) error {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return fmt.Errorf("streaming unsupported")
+	}
+
+	ch := b.subscribe(vpsID)
+	defer b.unsubscribe(vpsID, ch)
+
+	for _, op := range initial {
+		writeOperationEvent(w, op)
+	}
+	flusher.Flush()
+
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case op := <-ch:
+			writeOperationEvent(w, op)
+			flusher.Flush()
+		case <-ticker.C:
+			fmt.Fprintf(w, ": heartbeat\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+func writeOperationEvent(w http.ResponseWriter, op domain.VPSOperation) {
+	data, _ := json.Marshal(vpsOperationEvent{
+		ID:         op.ID,
+		VPSID:      op.VPSID,
+		Action:     string(op.Action),
+		Status:     string(op.Status),
+		Progress:   op.Progress,
+		Estimated:  op.ProgressEstimated,
+		Message:    op.Message,
+		Error:      op.ErrorMessage,
+		StartedAt:  op.StartedAt,
+		FinishedAt: op.FinishedAt,
+		UpdatedAt:  op.UpdatedAt,
+	})
+	fmt.Fprintf(w, "event: vps.operation\n")
+	fmt.Fprintf(w, "data: %s\n\n", data)
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"xiaoheiplay/internal/domain"
+)
+
+// Local keeps uploads on the local disk. The HTTP server serves the
+// directory itself, so there are no presigned URLs.
+type Local struct {
+	root string
+}
+
+func NewLocal(root string) *Local {
+	return &Local{root: root}
+}
+
+func (l *Local) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	_ = ctx
+	_ = contentType
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(p, body, 0o644)
+}
+
+func (l *Local) Get(ctx context.Context, key string) ([]byte, string, error) {
+	_ = ctx
+	p, err := l.path(key)
+	if err != nil {
+		return nil, "", err
+	}
+	body, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, "", domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return body, mime.TypeByExtension(path.Ext(key)), nil
+}
+
+func (l *Local) Delete(ctx context.Context, key string) error {
+	_ = ctx
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (l *Local) PresignURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	_, _, _ = ctx, key, ttl
+	return "", nil
+}
+
+func (l *Local) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if clean == "/" || clean != "/"+key || strings.Contains(key, "\\") {
+		return "", domain.ErrInvalidInput
+	}
+	return filepath.Join(l.root, filepath.FromSlash(strings.TrimPrefix(clean, "/"))), nil
+}
@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"context"
+	"strings"
+
+	appports "xiaoheiplay/internal/app/ports"
+	"xiaoheiplay/internal/domain"
+)
+
+// PluginStorageFunc returns the storage of a storage plugin instance.
+type PluginStorageFunc func(pluginID, instanceID string) appports.ObjectStorage
+
+// Resolver maps upload backend names to storages: "local" is the local disk,
+// "<plugin_id>/<instance_id>" a storage plugin instance.
+type Resolver struct {
+	local   *Local
+	plugins PluginStorageFunc
+}
+
+func NewResolver(local *Local, plugins PluginStorageFunc) *Resolver {
+	return &Resolver{local: local, plugins: plugins}
+}
+
+func (r *Resolver) Storage(ctx context.Context, backend string) (appports.ObjectStorage, error) {
+	_ = ctx
+	backend = strings.TrimSpace(backend)
+	if backend == "" || backend == domain.UploadBackendLocal {
+		return r.local, nil
+	}
+	pluginID, instanceID, _ := strings.Cut(backend, "/")
+	pluginID, instanceID = strings.TrimSpace(pluginID), strings.TrimSpace(instanceID)
+	if pluginID == "" || r.plugins == nil {
+		return nil, domain.ErrNotSupported
+	}
+	if instanceID == "" {
+		instanceID = "default"
+	}
+	return r.plugins(pluginID, instanceID), nil
+}
@@ -0,0 +1,781 @@
+package abuse
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	appports "xiaoheiplay/internal/app/ports"
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+const (
+	settingWebhookToken   = "abuse_webhook_token"
+	settingAckHours       = "abuse_sla_ack_hours"
+	settingResolveHours   = "abuse_sla_resolve_hours"
+	settingResponseWindow = "abuse_response_window_hours"
+
+	// User-facing texts are text/template strings; each falls back to the
+	// default below when the setting is empty.
+	settingTicketSubjectTemplate = "abuse_ticket_subject_template"
+	settingWarningTemplate       = "abuse_warning_template"
+	settingNotifyTitleTemplate   = "abuse_notify_title_template"
+	settingNotifyBodyTemplate    = "abuse_notify_body_template"
+	settingSenderName            = "abuse_sender_name"
+
+	defaultAckHours       = 4
+	defaultResolveHours   = 72
+	defaultResponseWindow = 24
+
+	defaultTicketSubjectTemplate = "滥用投诉 #{{.case_id}}：{{.subject}}"
+	defaultWarningTemplate       = "{{.message}}\n\n请在 {{.due_at}} 前回复此工单。"
+	defaultNotifyTitleTemplate   = "滥用投诉通知"
+	defaultNotifyBodyTemplate    = "您的服务收到一条滥用投诉，请在工单 #{{.ticket_id}} 中回复。"
+	defaultSenderName            = "滥用处理中心"
+
+	maxSubjectLen     = 240
+	maxDescriptionLen = 10000
+	maxEvidenceLen    = 10000
+	maxReporterLen    = 255
+	maxResolutionLen  = 2000
+
+	TargetVPS  = "vps"
+	TargetUser = "user"
+)
+
+var categories = map[string]bool{
+	"spam":      true,
+	"phishing":  true,
+	"malware":   true,
+	"ddos":      true,
+	"scan":      true,
+	"copyright": true,
+	"other":     true,
+}
+
+var evidenceKinds = map[string]bool{"text": true, "url": true, "upload": true}
+
+// Enforcer applies actions to instances. adminvps.Service implements it;
+// Delete there destroys the host without any refund.
+type Enforcer interface {
+	SetAdminStatus(ctx context.Context, adminID int64, vpsID int64, status domain.VPSAdminStatus, reason string) error
+	Delete(ctx context.Context, adminID int64, vpsID int64) error
+}
+
+type messageCenter interface {
+	NotifyUser(ctx context.Context, userID int64, typ, title, content string) error
+}
+
+type EvidenceInput struct {
+	Kind     string
+	Content  string
+	UploadID int64
+}
+
+// CreateInput is a new case from the admin form or the report webhook. IP,
+// when set, is resolved to the instance holding it.
+type CreateInput struct {
+	Category    string
+	Reporter    string
+	Subject     string
+	Description string
+	IP          string
+	VPSIDs      []int64
+	UserIDs     []int64
+	Evidence    []EvidenceInput
+}
+
+// QueueItem is a case with its SLA state evaluated at query time.
+type QueueItem struct {
+	Case            domain.AbuseCase
+	AckOverdue      bool
+	ResolveOverdue  bool
+	ResponseExpired bool
+}
+
+type Detail struct {
+	QueueItem
+	Links    []domain.AbuseCaseLink
+	Evidence []domain.AbuseEvidence
+	Events   []domain.AbuseCaseEvent
+}
+
+type Service struct {
+	cases    appports.AbuseCaseRepository
+	vps      appports.VPSRepository
+	ips      appports.VPSExtraIPRepository
+	tickets  appports.TicketRepository
+	settings appports.SettingsRepository
+	audit    appports.AuditRepository
+	enforcer Enforcer
+	messages messageCenter
+}
+
+func NewService(cases appports.AbuseCaseRepository, vps appports.VPSRepository, ips appports.VPSExtraIPRepository, tickets appports.TicketRepository, settings appports.SettingsRepository, audit appports.AuditRepository) *Service {
+	return &Service{cases: cases, vps: vps, ips: ips, tickets: tickets, settings: settings, audit: audit}
+}
+
+func (s *Service) SetEnforcer(enforcer Enforcer) {
+	s.enforcer = enforcer
+}
+
+func (s *Service) SetMessageCenter(messages messageCenter) {
+	s.messages = messages
+}
+
+// Create opens a case from the admin form.
+func (s *Service) Create(ctx context.Context, adminID int64, input CreateInput) (domain.AbuseCase, error) {
+	c, err := s.create(ctx, adminID, "admin", input)
+	if err != nil {
+		return domain.AbuseCase{}, err
+	}
+	if s.audit != nil {
+		_ = s.audit.AddAuditLog(ctx, domain.AdminAuditLog{AdminID: adminID, Action: "abuse.create", TargetType: "abuse_case", TargetID: fmt.Sprintf("%d", c.ID), DetailJSON: mustJSON(map[string]any{"category": c.Category})})
+	}
+	return c, nil
+}
+
+// Report opens a case from the inbound webhook. The token must match the
+// abuse_webhook_token setting; an empty setting disables the webhook.
+// Reports whose IP matches no instance are still opened unlinked so the desk
+// can triage them.
+func (s *Service) Report(ctx context.Context, token string, input CreateInput) (domain.AbuseCase, error) {
+	expected := strings.TrimSpace(s.stringSetting(ctx, settingWebhookToken))
+	if expected == "" {
+		return domain.AbuseCase{}, domain.ErrAbuseWebhookDisabled
+	}
+	if subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(expected)) != 1 {
+		return domain.AbuseCase{}, appshared.ErrForbidden
+	}
+	input.VPSIDs = nil
+	input.UserIDs = nil
+	for i := range input.Evidence {
+		if input.Evidence[i].Kind == "upload" {
+			input.Evidence[i].Kind = "url"
+		}
+		input.Evidence[i].UploadID = 0
+	}
+	return s.create(ctx, 0, "webhook", input)
+}
+
+func (s *Service) List(ctx context.Context, filter appshared.AbuseCaseFilter) ([]QueueItem, int, error) {
+	items, total, err := s.cases.ListAbuseCases(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	now := time.Now()
+	out := make([]QueueItem, 0, len(items))
+	for _, c := range items {
+		out = append(out, evaluate(c, now))
+	}
+	return out, total, nil
+}
+
+func (s *Service) Get(ctx context.Context, id int64) (Detail, error) {
+	c, err := s.cases.GetAbuseCase(ctx, id)
+	if err != nil {
+		return Detail{}, err
+	}
+	links, err := s.cases.ListAbuseCaseLinks(ctx, id)
+	if err != nil {
+		return Detail{}, err
+	}
+	if c.Status == domain.AbuseCaseStatusAwaitingUser {
+		if updated, changed := s.syncResponse(ctx, c, links, time.Now()); changed {
+			c = updated
+		}
+	}
+	evidence, err := s.cases.ListAbuseEvidence(ctx, id)
+	if err != nil {
+		return Detail{}, err
+	}
+	events, err := s.cases.ListAbuseCaseEvents(ctx, id)
+	if err != nil {
+		return Detail{}, err
+	}
+	return Detail{QueueItem: evaluate(c, time.Now()), Links: links, Evidence: evidence, Events: events}, nil
+}
+
+// Acknowledge takes the case off the ack SLA and assigns it to the admin.
+func (s *Service) Acknowledge(ctx context.Context, adminID, id int64) error {
+	c, err := s.openCase(ctx, id)
+	if err != nil {
+		return err
+	}
+	if c.AcknowledgedAt != nil {
+		return nil
+	}
+	now := time.Now()
+	c.AcknowledgedAt = &now
+	c.AssigneeID = adminID
+	if c.Status == domain.AbuseCaseStatusOpen {
+		c.Status = domain.AbuseCaseStatusInvestigating
+	}
+	if err := s.cases.UpdateAbuseCase(ctx, c); err != nil {
+		return err
+	}
+	s.event(ctx, c.ID, adminID, "acknowledged", nil)
+	return nil
+}
+
+func (s *Service) AddEvidence(ctx context.Context, adminID, id int64, input EvidenceInput) (domain.AbuseEvidence, error) {
+	c, err := s.openCase(ctx, id)
+	if err != nil {
+		return domain.AbuseEvidence{}, err
+	}
+	item, err := normalizeEvidence(input)
+	if err != nil {
+		return domain.AbuseEvidence{}, err
+	}
+	item.CaseID = c.ID
+	item.AddedBy = adminID
+	if err := s.cases.AddAbuseEvidence(ctx, &item); err != nil {
+		return domain.AbuseEvidence{}, err
+	}
+	s.event(ctx, c.ID, adminID, "evidence_added", map[string]any{"evidence_id": item.ID, "kind": item.Kind})
+	return item, nil
+}
+
+// Link attaches an instance or user to the case. Linking an instance also
+// links its owner.
+func (s *Service) Link(ctx context.Context, adminID, id int64, targetType string, targetID int64) error {
+	c, err := s.openCase(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.link(ctx, c.ID, adminID, targetType, targetID)
+}
+
+func (s *Service) AddNote(ctx context.Context, adminID, id int64, note string) error {
+	c, err := s.cases.GetAbuseCase(ctx, id)
+	if err != nil {
+		return err
+	}
+	note = strings.TrimSpace(note)
+	if note == "" || len([]rune(note)) > maxEvidenceLen {
+		return appshared.ErrInvalidInput
+	}
+	s.event(ctx, c.ID, adminID, "note", map[string]any{"note": note})
+	return nil
+}
+
+// Warn notifies every linked user and opens a ticket for them carrying the
+// admin message. The case waits for a reply until the response window ends.
+func (s *Service) Warn(ctx context.Context, adminID, id int64, message string) error {
+	c, err := s.openCase(ctx, id)
+	if err != nil {
+		return err
+	}
+	message = strings.TrimSpace(message)
+	if message == "" || len([]rune(message)) > maxDescriptionLen {
+		return appshared.ErrInvalidInput
+	}
+	links, err := s.cases.ListAbuseCaseLinks(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	due := now.Add(time.Duration(s.hoursSetting(ctx, settingResponseWindow, defaultResponseWindow)) * time.Hour)
+	content := s.renderText(ctx, settingWarningTemplate, defaultWarningTemplate, map[string]any{
+		"case_id": c.ID,
+		"subject": c.Subject,
+		"message": message,
+		"due_at":  due.Format("2006-01-02 15:04 MST"),
+	})
+	warned := 0
+	for _, link := range links {
+		if link.TargetType != TargetUser {
+			continue
+		}
+		ticketID := link.TicketID
+		if ticketID == 0 {
+			ticketID, err = s.openTicket(ctx, adminID, c, link.TargetID, links, content)
+			if err != nil {
+				return err
+			}
+			if err := s.cases.UpdateAbuseCaseLinkTicket(ctx, link.ID, ticketID); err != nil {
+				return err
+			}
+		} else if err := s.replyTicket(ctx, adminID, ticketID, content); err != nil {
+			return err
+		}
+		if s.messages != nil {
+			vars := map[string]any{"case_id": c.ID, "subject": c.Subject, "ticket_id": ticketID}
+			title := s.renderText(ctx, settingNotifyTitleTemplate, defaultNotifyTitleTemplate, vars)
+			body := s.renderText(ctx, settingNotifyBodyTemplate, defaultNotifyBodyTemplate, vars)
+			_ = s.messages.NotifyUser(ctx, link.TargetID, "abuse_warning", title, body)
+		}
+		warned++
+	}
+	if warned == 0 {
+		return domain.ErrAbuseTargetNotLinked
+	}
+	c.Status = domain.AbuseCaseStatusAwaitingUser
+	c.WarnedAt = &now
+	c.ResponseDueAt = &due
+	if c.AcknowledgedAt == nil {
+		c.AcknowledgedAt = &now
+		c.AssigneeID = adminID
+	}
+	if err := s.cases.UpdateAbuseCase(ctx, c); err != nil {
+		return err
+	}
+	s.event(ctx, c.ID, adminID, "warned", map[string]any{"users": warned, "response_due_at": due})
+	s.auditAction(ctx, adminID, "abuse.warn", c.ID, map[string]any{"users": warned})
+	return nil
+}
+
+// Lock sets the abuse admin status on every linked instance.
+func (s *Service) Lock(ctx context.Context, adminID, id int64, reason string) error {
+	c, err := s.openCase(ctx, id)
+	if err != nil {
+		return err
+	}
+	if s.enforcer == nil {
+		return appshared.ErrNotSupported
+	}
+	links, err := s.cases.ListAbuseCaseLinks(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		reason = fmt.Sprintf("abuse case #%d", c.ID)
+	}
+	locked := make([]int64, 0, len(links))
+	for _, link := range links {
+		if link.TargetType != TargetVPS {
+			continue
+		}
+		if err := s.enforcer.SetAdminStatus(ctx, adminID, link.TargetID, domain.VPSAdminStatusAbuse, reason); err != nil {
+			if errors.Is(err, appshared.ErrNotFound) {
+				continue
+			}
+			return fmt.Errorf("vps %d: %w", link.TargetID, err)
+		}
+		locked = append(locked, link.TargetID)
+	}
+	if len(locked) == 0 {
+		return domain.ErrAbuseTargetNotLinked
+	}
+	s.event(ctx, c.ID, adminID, "locked", map[string]any{"vps_ids": locked, "reason": reason})
+	s.auditAction(ctx, adminID, "abuse.lock", c.ID, map[string]any{"vps_ids": locked})
+	return nil
+}
+
+// Terminate destroys one linked instance. No refund is issued.
+func (s *Service) Terminate(ctx context.Context, adminID, id, vpsID int64) error {
+	c, err := s.openCase(ctx, id)
+	if err != nil {
+		return err
+	}
+	if s.enforcer == nil {
+		return appshared.ErrNotSupported
+	}
+	links, err := s.cases.ListAbuseCaseLinks(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+	if !hasLink(links, TargetVPS, vpsID) {
+		return domain.ErrAbuseTargetNotLinked
+	}
+	if err := s.enforcer.Delete(ctx, adminID, vpsID); err != nil {
+		return err
+	}
+	s.event(ctx, c.ID, adminID, "terminated", map[string]any{"vps_id": vpsID, "refund": false})
+	s.auditAction(ctx, adminID, "abuse.terminate", c.ID, map[string]any{"vps_id": vpsID, "refund": false})
+	return nil
+}
+
+func (s *Service) Resolve(ctx context.Context, adminID, id int64, resolution string) error {
+	return s.close(ctx, adminID, id, domain.AbuseCaseStatusResolved, resolution)
+}
+
+func (s *Service) Dismiss(ctx context.Context, adminID, id int64, resolution string) error {
+	return s.close(ctx, adminID, id, domain.AbuseCaseStatusDismissed, resolution)
+}
+
+// CheckResponses moves cases waiting on the user back to investigating once
+// the user replies on a linked ticket or the response window ends.
+func (s *Service) CheckResponses(ctx context.Context, limit int) (int, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	items, _, err := s.cases.ListAbuseCases(ctx, appshared.AbuseCaseFilter{Status: string(domain.AbuseCaseStatusAwaitingUser), Limit: limit})
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	changed := 0
+	for _, c := range items {
+		links, err := s.cases.ListAbuseCaseLinks(ctx, c.ID)
+		if err != nil {
+			return changed, err
+		}
+		if _, ok := s.syncResponse(ctx, c, links, now); ok {
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+func (s *Service) create(ctx context.Context, adminID int64, source string, input CreateInput) (domain.AbuseCase, error) {
+	category := strings.ToLower(strings.TrimSpace(input.Category))
+	if category == "" {
+		category = "other"
+	}
+	if !categories[category] {
+		return domain.AbuseCase{}, appshared.ErrInvalidInput
+	}
+	subject := strings.TrimSpace(input.Subject)
+	description := strings.TrimSpace(input.Description)
+	reporter := strings.TrimSpace(input.Reporter)
+	if subject == "" || len([]rune(subject)) > maxSubjectLen || len([]rune(description)) > maxDescriptionLen || len([]rune(reporter)) > maxReporterLen {
+		return domain.AbuseCase{}, appshared.ErrInvalidInput
+	}
+	evidence := make([]domain.AbuseEvidence, 0, len(input.Evidence))
+	for _, item := range input.Evidence {
+		normalized, err := normalizeEvidence(item)
+		if err != nil {
+			return domain.AbuseCase{}, err
+		}
+		evidence = append(evidence, normalized)
+	}
+	vpsIDs := append([]int64(nil), input.VPSIDs...)
+	if ip := strings.TrimSpace(input.IP); ip != "" {
+		if net.ParseIP(ip) == nil {
+			return domain.AbuseCase{}, appshared.ErrInvalidInput
+		}
+		vpsIDs = append(vpsIDs, s.instancesByIP(ctx, ip)...)
+	}
+	for _, vpsID := range vpsIDs {
+		if _, err := s.vps.GetInstance(ctx, vpsID); err != nil {
+			if source == "webhook" {
+				continue
+			}
+			return domain.AbuseCase{}, err
+		}
+	}
+
+	now := time.Now()
+	c := domain.AbuseCase{
+		Source:       source,
+		Category:     category,
+		Reporter:     reporter,
+		Subject:      subject,
+		Description:  description,
+		Status:       domain.AbuseCaseStatusOpen,
+		AckDueAt:     now.Add(time.Duration(s.hoursSetting(ctx, settingAckHours, defaultAckHours)) * time.Hour),
+		ResolveDueAt: now.Add(time.Duration(s.hoursSetting(ctx, settingResolveHours, defaultResolveHours)) * time.Hour),
+	}
+	if err := s.cases.CreateAbuseCase(ctx, &c); err != nil {
+		return domain.AbuseCase{}, err
+	}
+	s.event(ctx, c.ID, adminID, "created", map[string]any{"source": source, "ip": strings.TrimSpace(input.IP)})
+	for _, vpsID := range vpsIDs {
+		_ = s.link(ctx, c.ID, adminID, TargetVPS, vpsID)
+	}
+	for _, userID := range input.UserIDs {
+		if err := s.link(ctx, c.ID, adminID, TargetUser, userID); err != nil {
+			return domain.AbuseCase{}, err
+		}
+	}
+	for i := range evidence {
+		evidence[i].CaseID = c.ID
+		evidence[i].AddedBy = adminID
+		if err := s.cases.AddAbuseEvidence(ctx, &evidence[i]); err != nil {
+			return domain.AbuseCase{}, err
+		}
+	}
+	return c, nil
+}
+
+func (s *Service) link(ctx context.Context, caseID, adminID int64, targetType string, targetID int64) error {
+	if targetID <= 0 {
+		return appshared.ErrInvalidInput
+	}
+	switch targetType {
+	case TargetVPS:
+		inst, err := s.vps.GetInstance(ctx, targetID)
+		if err != nil {
+			return err
+		}
+		if err := s.addLink(ctx, caseID, adminID, TargetVPS, inst.ID); err != nil {
+			return err
+		}
+		if inst.UserID > 0 {
+			return s.addLink(ctx, caseID, adminID, TargetUser, inst.UserID)
+		}
+		return nil
+	case TargetUser:
+		return s.addLink(ctx, caseID, adminID, TargetUser, targetID)
+	default:
+		return appshared.ErrInvalidInput
+	}
+}
+
+func (s *Service) addLink(ctx context.Context, caseID, adminID int64, targetType string, targetID int64) error {
+	links, err := s.cases.ListAbuseCaseLinks(ctx, caseID)
+	if err != nil {
+		return err
+	}
+	if hasLink(links, targetType, targetID) {
+		return nil
+	}
+	link := domain.AbuseCaseLink{CaseID: caseID, TargetType: targetType, TargetID: targetID}
+	if err := s.cases.AddAbuseCaseLink(ctx, &link); err != nil {
+		return err
+	}
+	s.event(ctx, caseID, adminID, "linked", map[string]any{"target_type": targetType, "target_id": targetID})
+	return nil
+}
+
+func (s *Service) close(ctx context.Context, adminID, id int64, status domain.AbuseCaseStatus, resolution string) error {
+	c, err := s.openCase(ctx, id)
+	if err != nil {
+		return err
+	}
+	resolution = strings.TrimSpace(resolution)
+	if resolution == "" || len([]rune(resolution)) > maxResolutionLen {
+		return appshared.ErrInvalidInput
+	}
+	now := time.Now()
+	c.Status = status
+	c.Resolution = resolution
+	c.ClosedAt = &now
+	if c.AcknowledgedAt == nil {
+		c.AcknowledgedAt = &now
+		c.AssigneeID = adminID
+	}
+	if err := s.cases.UpdateAbuseCase(ctx, c); err != nil {
+		return err
+	}
+	s.event(ctx, c.ID, adminID, string(status), map[string]any{"resolution": resolution})
+	s.auditAction(ctx, adminID, "abuse.close", c.ID, map[string]any{"status": status})
+	return nil
+}
+
+// syncResponse checks the tickets of a case waiting on the user. A user
+// reply after the warning, or the end of the response window, hands the case
+// back to the desk.
+func (s *Service) syncResponse(ctx context.Context, c domain.AbuseCase, links []domain.AbuseCaseLink, now time.Time) (domain.AbuseCase, bool) {
+	if c.Status != domain.AbuseCaseStatusAwaitingUser || c.WarnedAt == nil {
+		return c, false
+	}
+	for _, link := range links {
+		if link.TargetType != TargetUser || link.TicketID == 0 || s.tickets == nil {
+			continue
+		}
+		ticket, err := s.tickets.GetTicket(ctx, link.TicketID)
+		if err != nil {
+			continue
+		}
+		if ticket.LastReplyRole == "user" && ticket.LastReplyAt != nil && ticket.LastReplyAt.After(*c.WarnedAt) {
+			c.Status = domain.AbuseCaseStatusInvestigating
+			if err := s.cases.UpdateAbuseCase(ctx, c); err != nil {
+				return c, false
+			}
+			s.event(ctx, c.ID, 0, "user_responded", map[string]any{"user_id": link.TargetID, "ticket_id": ticket.ID})
+			return c, true
+		}
+	}
+	if c.ResponseDueAt != nil && now.After(*c.ResponseDueAt) {
+		c.Status = domain.AbuseCaseStatusInvestigating
+		if err := s.cases.UpdateAbuseCase(ctx, c); err != nil {
+			return c, false
+		}
+		s.event(ctx, c.ID, 0, "response_expired", nil)
+		return c, true
+	}
+	return c, false
+}
+
+func (s *Service) openTicket(ctx context.Context, adminID int64, c domain.AbuseCase, userID int64, links []domain.AbuseCaseLink, content string) (int64, error) {
+	if s.tickets == nil {
+		return 0, appshared.ErrNotSupported
+	}
+	now := time.Now()
+	ticket := domain.Ticket{
+		UserID:        userID,
+		Subject:       s.renderText(ctx, settingTicketSubjectTemplate, defaultTicketSubjectTemplate, map[string]any{"case_id": c.ID, "subject": c.Subject}),
+		Status:        "open",
+		LastReplyAt:   &now,
+		LastReplyBy:   &adminID,
+		LastReplyRole: "admin",
+	}
+	if runes := []rune(ticket.Subject); len(runes) > maxSubjectLen {
+		ticket.Subject = string(runes[:maxSubjectLen])
+	}
+	msg := domain.TicketMessage{SenderID: adminID, SenderRole: "admin", SenderName: s.senderName(ctx), Content: content}
+	var resources []domain.TicketResource
+	for _, link := range links {
+		if link.TargetType != TargetVPS {
+			continue
+		}
+		inst, err := s.vps.GetInstance(ctx, link.TargetID)
+		if err != nil || inst.UserID != userID {
+			continue
+		}
+		resources = append(resources, domain.TicketResource{ResourceType: "vps", ResourceID: inst.ID, ResourceName: inst.Name})
+	}
+	if err := s.tickets.CreateTicketWithDetails(ctx, &ticket, &msg, resources); err != nil {
+		return 0, err
+	}
+	return ticket.ID, nil
+}
+
+func (s *Service) replyTicket(ctx context.Context, adminID, ticketID int64, content string) error {
+	ticket, err := s.tickets.GetTicket(ctx, ticketID)
+	if err != nil {
+		return err
+	}
+	msg := domain.TicketMessage{TicketID: ticket.ID, SenderID: adminID, SenderRole: "admin", SenderName: s.senderName(ctx), Content: content}
+	if err := s.tickets.AddTicketMessage(ctx, &msg); err != nil {
+		return err
+	}
+	if ticket.Status != "closed" {
+		return nil
+	}
+	ticket.Status = "open"
+	ticket.ClosedAt = nil
+	return s.tickets.UpdateTicket(ctx, ticket)
+}
+
+// instancesByIP finds instances holding ip as their primary address or as an
+// add-on address.
+func (s *Service) instancesByIP(ctx context.Context, ip string) []int64 {
+	var out []int64
+	if s.ips != nil {
+		if extra, err := s.ips.FindVPSExtraIPByAddress(ctx, ip); err == nil {
+			out = append(out, extra.VPSID)
+		}
+	}
+	const pageSize = 200
+	for offset := 0; ; {
+		items, total, err := s.vps.ListInstances(ctx, pageSize, offset)
+		if err != nil {
+			return out
+		}
+		for _, inst := range items {
+			var access map[string]any
+			if json.Unmarshal([]byte(inst.AccessInfoJSON), &access) != nil {
+				continue
+			}
+			if v, _ := access["remote_ip"].(string); strings.TrimSpace(v) == ip {
+				out = append(out, inst.ID)
+			}
+		}
+		offset += len(items)
+		if offset >= total || len(items) == 0 {
+			return out
+		}
+	}
+}
+
+func (s *Service) openCase(ctx context.Context, id int64) (domain.AbuseCase, error) {
+	c, err := s.cases.GetAbuseCase(ctx, id)
+	if err != nil {
+		return domain.AbuseCase{}, err
+	}
+	if c.Status.Closed() {
+		return domain.AbuseCase{}, domain.ErrAbuseCaseClosed
+	}
+	return c, nil
+}
+
+func (s *Service) event(ctx context.Context, caseID, adminID int64, typ string, detail map[string]any) {
+	if detail == nil {
+		detail = map[string]any{}
+	}
+	_ = s.cases.AddAbuseCaseEvent(ctx, &domain.AbuseCaseEvent{CaseID: caseID, AdminID: adminID, Type: typ, DetailJSON: mustJSON(detail)})
+}
+
+func (s *Service) auditAction(ctx context.Context, adminID int64, action string, caseID int64, detail map[string]any) {
+	if s.audit == nil {
+		return
+	}
+	_ = s.audit.AddAuditLog(ctx, domain.AdminAuditLog{AdminID: adminID, Action: action, TargetType: "abuse_case", TargetID: fmt.Sprintf("%d", caseID), DetailJSON: mustJSON(detail)})
+}
+
+func (s *Service) hoursSetting(ctx context.Context, key string, def int) int {
+	v, err := strconv.Atoi(strings.TrimSpace(s.stringSetting(ctx, key)))
+	if err != nil || v <= 0 {
+		return def
+	}
+	return v
+}
+
+// renderText renders the user-facing template stored under key, or def when
+// the setting is empty.
+func (s *Service) renderText(ctx context.Context, key, def string, vars map[string]any) string {
+	tmpl := strings.TrimSpace(s.stringSetting(ctx, key))
+	if tmpl == "" {
+		tmpl = def
+	}
+	return appshared.RenderTemplate(tmpl, vars, false)
+}
+
+func (s *Service) senderName(ctx context.Context) string {
+	if name := strings.TrimSpace(s.stringSetting(ctx, settingSenderName)); name != "" {
+		return name
+	}
+	return defaultSenderName
+}
+
+func (s *Service) stringSetting(ctx context.Context, key string) string {
+	if s.settings == nil {
+		return ""
+	}
+	setting, err := s.settings.GetSetting(ctx, key)
+	if err != nil {
+		return ""
+	}
+	return setting.ValueJSON
+}
+
+func evaluate(c domain.AbuseCase, now time.Time) QueueItem {
+	item := QueueItem{Case: c}
+	if c.Status.Closed() {
+		return item
+	}
+	item.AckOverdue = c.AcknowledgedAt == nil && now.After(c.AckDueAt)
+	item.ResolveOverdue = now.After(c.ResolveDueAt)
+	item.ResponseExpired = c.Status == domain.AbuseCaseStatusAwaitingUser && c.ResponseDueAt != nil && now.After(*c.ResponseDueAt)
+	return item
+}
+
+func normalizeEvidence(input EvidenceInput) (domain.AbuseEvidence, error) {
+	kind := strings.ToLower(strings.TrimSpace(input.Kind))
+	content := strings.TrimSpace(input.Content)
+	if !evidenceKinds[kind] || content == "" || len([]rune(content)) > maxEvidenceLen {
+		return domain.AbuseEvidence{}, appshared.ErrInvalidInput
+	}
+	if kind == "url" && !strings.HasPrefix(content, "http://") && !strings.HasPrefix(content, "https://") {
+		return domain.AbuseEvidence{}, appshared.ErrInvalidInput
+	}
+	if kind == "upload" && input.UploadID <= 0 {
+		return domain.AbuseEvidence{}, appshared.ErrInvalidInput
+	}
+	return domain.AbuseEvidence{Kind: kind, Content: content, UploadID: input.UploadID}, nil
+}
+
+func hasLink(links []domain.AbuseCaseLink, targetType string, targetID int64) bool {
+	for _, link := range links {
+		if link.TargetType == targetType && link.TargetID == targetID {
+			return true
+		}
+	}
+	return false
+}
+
+func mustJSON(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -0,0 +1,182 @@
+package abuse_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	appabuse "xiaoheiplay/internal/app/abuse"
+	appadminvps "xiaoheiplay/internal/app/adminvps"
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+	"xiaoheiplay/internal/testutil"
+)
+
+type recordedNotice struct {
+	userID      int64
+	title, body string
+}
+
+type fakeMessageCenter struct {
+	notices []recordedNotice
+}
+
+func (f *fakeMessageCenter) NotifyUser(ctx context.Context, userID int64, typ, title, content string) error {
+	f.notices = append(f.notices, recordedNotice{userID: userID, title: title, body: content})
+	return nil
+}
+
+func TestService_WebhookReportWarnAndUserResponse(t *testing.T) {
+	_, repo := testutil.NewTestDB(t, false)
+	ctx := context.Background()
+	user := testutil.CreateUser(t, repo, "spammer", "spammer@example.com", "pass")
+	inst := domain.VPSInstance{UserID: user.ID, AutomationInstanceID: "11", Name: "mailer", Status: domain.VPSStatusRunning, SpecJSON: "{}", AccessInfoJSON: `{"remote_ip":"203.0.113.9"}`}
+	if err := repo.CreateInstance(ctx, &inst); err != nil {
+		t.Fatalf("create instance: %v", err)
+	}
+	svc := appabuse.NewService(repo, repo, repo, repo, repo, repo)
+	messages := &fakeMessageCenter{}
+	svc.SetMessageCenter(messages)
+	report := appabuse.CreateInput{Category: "spam", Reporter: "abuse@upstream.example", Subject: "Spam from 203.0.113.9", IP: "203.0.113.9", Evidence: []appabuse.EvidenceInput{{Kind: "text", Content: "Received: from 203.0.113.9"}}}
+
+	if _, err := svc.Report(ctx, "secret", report); !errors.Is(err, domain.ErrAbuseWebhookDisabled) {
+		t.Fatalf("expected webhook disabled without token, got %v", err)
+	}
+	if err := repo.UpsertSetting(ctx, domain.Setting{Key: "abuse_webhook_token", ValueJSON: "secret"}); err != nil {
+		t.Fatalf("set token: %v", err)
+	}
+	if _, err := svc.Report(ctx, "wrong", report); !errors.Is(err, appshared.ErrForbidden) {
+		t.Fatalf("expected forbidden with bad token, got %v", err)
+	}
+	created, err := svc.Report(ctx, "secret", report)
+	if err != nil {
+		t.Fatalf("report: %v", err)
+	}
+	detail, err := svc.Get(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if detail.Case.Source != "webhook" || detail.Case.Status != domain.AbuseCaseStatusOpen || len(detail.Evidence) != 1 {
+		t.Fatalf("unexpected case %+v evidence %+v", detail.Case, detail.Evidence)
+	}
+	if len(detail.Links) != 2 || detail.Links[0].TargetType != appabuse.TargetVPS || detail.Links[0].TargetID != inst.ID || detail.Links[1].TargetID != user.ID {
+		t.Fatalf("expected instance and owner linked from ip, got %+v", detail.Links)
+	}
+	if d := time.Until(detail.Case.AckDueAt); d < 3*time.Hour || d > 5*time.Hour {
+		t.Fatalf("expected default ack sla, got %v", d)
+	}
+
+	if err := repo.UpsertSetting(ctx, domain.Setting{Key: "abuse_notify_body_template", ValueJSON: "Case {{.case_id}}: see ticket #{{.ticket_id}}"}); err != nil {
+		t.Fatalf("set template: %v", err)
+	}
+	if err := svc.Warn(ctx, 1, created.ID, "Stop sending spam."); err != nil {
+		t.Fatalf("warn: %v", err)
+	}
+	detail, _ = svc.Get(ctx, created.ID)
+	if detail.Case.Status != domain.AbuseCaseStatusAwaitingUser || detail.Case.ResponseDueAt == nil || detail.Case.AcknowledgedAt == nil {
+		t.Fatalf("expected awaiting user after warning, got %+v", detail.Case)
+	}
+	ticketID := detail.Links[1].TicketID
+	ticket, err := repo.GetTicket(ctx, ticketID)
+	if err != nil || ticket.UserID != user.ID || ticket.LastReplyRole != "admin" {
+		t.Fatalf("expected linked ticket for owner, got %+v %v", ticket, err)
+	}
+	if want := fmt.Sprintf("滥用投诉 #%d：Spam from 203.0.113.9", created.ID); ticket.Subject != want {
+		t.Fatalf("expected default ticket subject %q, got %q", want, ticket.Subject)
+	}
+	if len(messages.notices) != 1 || messages.notices[0].userID != user.ID || messages.notices[0].title != "滥用投诉通知" || messages.notices[0].body != fmt.Sprintf("Case %d: see ticket #%d", created.ID, ticketID) {
+		t.Fatalf("expected notice rendered from the configured template, got %+v", messages.notices)
+	}
+	resources, _ := repo.ListTicketResources(ctx, ticketID)
+	if len(resources) != 1 || resources[0].ResourceID != inst.ID {
+		t.Fatalf("expected instance attached to ticket, got %+v", resources)
+	}
+
+	if n, err := svc.CheckResponses(ctx, 10); err != nil || n != 0 {
+		t.Fatalf("expected no response yet, got %d %v", n, err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if err := repo.AddTicketMessage(ctx, &domain.TicketMessage{TicketID: ticketID, SenderID: user.ID, SenderRole: "user", Content: "Compromised, cleaned up."}); err != nil {
+		t.Fatalf("user reply: %v", err)
+	}
+	if n, err := svc.CheckResponses(ctx, 10); err != nil || n != 1 {
+		t.Fatalf("expected reply picked up, got %d %v", n, err)
+	}
+	detail, _ = svc.Get(ctx, created.ID)
+	if detail.Case.Status != domain.AbuseCaseStatusInvestigating || detail.Events[len(detail.Events)-1].Type != "user_responded" {
+		t.Fatalf("expected case back with desk, got %s %+v", detail.Case.Status, detail.Events)
+	}
+
+	if err := svc.Resolve(ctx, 1, created.ID, "User cleaned up the host."); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if err := svc.Warn(ctx, 1, created.ID, "again"); !errors.Is(err, domain.ErrAbuseCaseClosed) {
+		t.Fatalf("expected closed case to refuse actions, got %v", err)
+	}
+	queue, total, err := svc.List(ctx, appshared.AbuseCaseFilter{Active: true})
+	if err != nil || total != 0 || len(queue) != 0 {
+		t.Fatalf("expected empty queue, got %d %+v %v", total, queue, err)
+	}
+}
+
+func TestService_LockAndTerminateWithoutRefund(t *testing.T) {
+	_, repo := testutil.NewTestDB(t, false)
+	ctx := context.Background()
+	user := testutil.CreateUser(t, repo, "ddos", "ddos@example.com", "pass")
+	inst := domain.VPSInstance{UserID: user.ID, AutomationInstanceID: "21", Name: "booter", Status: domain.VPSStatusRunning, SpecJSON: "{}"}
+	other := domain.VPSInstance{UserID: user.ID, AutomationInstanceID: "22", Name: "innocent", Status: domain.VPSStatusRunning, SpecJSON: "{}"}
+	for _, item := range []*domain.VPSInstance{&inst, &other} {
+		if err := repo.CreateInstance(ctx, item); err != nil {
+			t.Fatalf("create instance: %v", err)
+		}
+	}
+	client := &testutil.FakeAutomationClient{}
+	adminVPS := appadminvps.NewService(repo, &testutil.FakeAutomationResolver{Client: client}, repo, repo, repo, nil)
+	svc := appabuse.NewService(repo, repo, repo, repo, repo, repo)
+	svc.SetEnforcer(adminVPS)
+
+	created, err := svc.Create(ctx, 1, appabuse.CreateInput{Category: "ddos", Subject: "Outbound flood", VPSIDs: []int64{inst.ID}})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := svc.Create(ctx, 1, appabuse.CreateInput{Category: "bogus", Subject: "x"}); !errors.Is(err, appshared.ErrInvalidInput) {
+		t.Fatalf("expected unknown category rejected, got %v", err)
+	}
+	if err := svc.Lock(ctx, 1, created.ID, ""); err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+	latest, _ := repo.GetInstance(ctx, inst.ID)
+	if latest.AdminStatus != domain.VPSAdminStatusAbuse || len(client.LockCalls) != 1 || client.LockCalls[0] != 21 {
+		t.Fatalf("expected abuse lock, got %s %+v", latest.AdminStatus, client.LockCalls)
+	}
+	if err := svc.Terminate(ctx, 1, created.ID, other.ID); !errors.Is(err, domain.ErrAbuseTargetNotLinked) {
+		t.Fatalf("expected unlinked instance refused, got %v", err)
+	}
+	if err := svc.Terminate(ctx, 1, created.ID, inst.ID); err != nil {
+		t.Fatalf("terminate: %v", err)
+	}
+	if _, err := repo.GetInstance(ctx, inst.ID); !errors.Is(err, appshared.ErrNotFound) {
+		t.Fatalf("expected instance deleted, got %v", err)
+	}
+	if len(client.DeleteCalls) != 1 || client.DeleteCalls[0] != 21 {
+		t.Fatalf("expected host deleted, got %+v", client.DeleteCalls)
+	}
+	detail, _ := svc.Get(ctx, created.ID)
+	last := detail.Events[len(detail.Events)-1]
+	if last.Type != "terminated" || last.DetailJSON != `{"refund":false,"vps_id":`+strconv.FormatInt(inst.ID, 10)+`}` {
+		t.Fatalf("expected termination on timeline, got %+v", last)
+	}
+	logs, _, _ := repo.ListAuditLogs(ctx, 10, 0)
+	found := false
+	for _, log := range logs {
+		if log.Action == "abuse.terminate" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected terminate audited, got %+v", logs)
+	}
+}
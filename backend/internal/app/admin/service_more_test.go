@@ -202,6 +202,14 @@ func (f *usecaseTestAutomation) ClientForGoodsType(ctx context.Context, goodsTyp
 	return f, nil
 }
 
+func (f *usecaseTestAutomation) ClientForBackend(ctx context.Context, pluginID, instanceID string) (appshared.AutomationClient, error) {
+	return f, nil
+}
+
+func (f *usecaseTestAutomation) ClientForInstance(ctx context.Context, inst domain.VPSInstance) (appshared.AutomationClient, error) {
+	return f, nil
+}
+
 func (f *usecaseTestAutomation) CreateHost(ctx context.Context, req appshared.AutomationCreateHostRequest) (appshared.AutomationCreateHostResult, error) {
 	if f.hostID == 0 {
 		f.hostID = 1001
@@ -239,7 +247,7 @@ func (f *usecaseTestAutomation) ShutdownHost(ctx context.Context, hostID int64)
 func (f *usecaseTestAutomation) RebootHost(ctx context.Context, hostID int64) error {
 	return nil
 }
-func (f *usecaseTestAutomation) ResetOS(ctx context.Context, hostID int64, templateID int64, password string) error {
+func (f *usecaseTestAutomation) ResetOS(ctx context.Context, hostID int64, templateID int64, password string, guest appshared.AutomationGuestInit) error {
 	return nil
 }
 func (f *usecaseTestAutomation) ResetOSPassword(ctx context.Context, hostID int64, password string) error {
@@ -274,6 +282,30 @@ func (f *usecaseTestAutomation) AddFirewallRule(ctx context.Context, req appshar
 func (f *usecaseTestAutomation) DeleteFirewallRule(ctx context.Context, hostID int64, ruleID int64) error {
 	return nil
 }
+func (f *usecaseTestAutomation) ListReverseDNS(ctx context.Context, hostID int64) ([]appshared.AutomationReverseDNSRecord, error) {
+	return nil, nil
+}
+func (f *usecaseTestAutomation) SetReverseDNS(ctx context.Context, hostID int64, ip, ptr string) error {
+	return nil
+}
+func (f *usecaseTestAutomation) AssignIP(ctx context.Context, hostID int64, family string) (string, error) {
+	return "", nil
+}
+func (f *usecaseTestAutomation) ReleaseIP(ctx context.Context, hostID int64, ip string) error {
+	return nil
+}
+func (f *usecaseTestAutomation) EnterRescue(ctx context.Context, hostID int64, password string) (appshared.AutomationRescueCredentials, error) {
+	return appshared.AutomationRescueCredentials{}, nil
+}
+func (f *usecaseTestAutomation) ExitRescue(ctx context.Context, hostID int64) error {
+	return nil
+}
+func (f *usecaseTestAutomation) MountISO(ctx context.Context, hostID int64, iso string) error {
+	return nil
+}
+func (f *usecaseTestAutomation) UnmountISO(ctx context.Context, hostID int64) error {
+	return nil
+}
 func (f *usecaseTestAutomation) ListPortMappings(ctx context.Context, hostID int64) ([]appshared.AutomationPortMapping, error) {
 	return nil, nil
 }
@@ -32,12 +32,28 @@ type Service struct {
 	audit      appports.AuditRepository
 	users      appports.UserRepository
 	messages   messageCenter
+	activity   appports.VPSActivityRepository
 }
 
 func NewService(vps appports.VPSRepository, automation appports.AutomationClientResolver, settings appports.SettingsRepository, audit appports.AuditRepository, users appports.UserRepository, messages messageCenter) *Service {
 	return &Service{vps: vps, automation: automation, settings: settings, audit: audit, users: users, messages: messages}
 }
 
+// SetActivityLog shows admin actions in the activity feed of the instance.
+func (s *Service) SetActivityLog(activity appports.VPSActivityRepository) {
+	s.activity = activity
+}
+
+func (s *Service) recordActivity(ctx context.Context, adminID int64, inst domain.VPSInstance, action, detail string) {
+	if s.activity == nil {
+		return
+	}
+	actor, _ := appshared.GetVPSActivityActor(ctx)
+	actor.Type = domain.VPSActivityActorAdmin
+	actor.ID = adminID
+	appshared.RecordVPSActivity(appshared.WithVPSActivityActor(ctx, actor), s.activity, inst, action, detail, nil)
+}
+
 func (s *Service) Get(ctx context.Context, vpsID int64) (domain.VPSInstance, error) {
 	return s.vps.GetInstance(ctx, vpsID)
 }
@@ -240,7 +256,7 @@ func (s *Service) Refresh(ctx context.Context, adminID int64, vpsID int64) (doma
 	if hostID == 0 {
 		return domain.VPSInstance{}, appshared.ErrInvalidInput
 	}
-	cli, err := s.automation.ClientForGoodsType(ctx, inst.GoodsTypeID)
+	cli, err := s.automation.ClientForInstance(ctx, inst)
 	if err != nil {
 		return domain.VPSInstance{}, err
 	}
@@ -249,6 +265,9 @@ func (s *Service) Refresh(ctx context.Context, adminID int64, vpsID int64) (doma
 		return domain.VPSInstance{}, err
 	}
 	status := mapAutomationState(info.State)
+	if inst.Status == domain.VPSStatusRescue && (status == domain.VPSStatusRunning || status == domain.VPSStatusStopped) {
+		status = domain.VPSStatusRescue
+	}
 	_ = s.vps.UpdateInstanceStatus(ctx, inst.ID, status, info.State)
 	if s.audit != nil {
 		_ = s.audit.AddAuditLog(ctx, domain.AdminAuditLog{AdminID: adminID, Action: "vps.refresh", TargetType: "vps", TargetID: fmt.Sprintf("%d", inst.ID), DetailJSON: "{}"})
@@ -265,7 +284,7 @@ func (s *Service) SetAdminStatus(ctx context.Context, adminID int64, vpsID int64
 	if hostID == 0 {
 		return appshared.ErrInvalidInput
 	}
-	cli, err := s.automation.ClientForGoodsType(ctx, inst.GoodsTypeID)
+	cli, err := s.automation.ClientForInstance(ctx, inst)
 	if err != nil {
 		return err
 	}
@@ -287,6 +306,7 @@ func (s *Service) SetAdminStatus(ctx context.Context, adminID int64, vpsID int64
 	if s.audit != nil {
 		_ = s.audit.AddAuditLog(ctx, domain.AdminAuditLog{AdminID: adminID, Action: "vps.admin_status", TargetType: "vps", TargetID: fmt.Sprintf("%d", inst.ID), DetailJSON: mustJSON(map[string]any{"status": status, "reason": reason})})
 	}
+	s.recordActivity(ctx, adminID, inst, appshared.VPSActivityAdminStatus, string(status))
 	return nil
 }
 
@@ -311,7 +331,7 @@ func (s *Service) EmergencyRenew(ctx context.Context, adminID int64, vpsID int64
 	if hostID == 0 {
 		return domain.VPSInstance{}, appshared.ErrInvalidInput
 	}
-	cli, err := s.automation.ClientForGoodsType(ctx, inst.GoodsTypeID)
+	cli, err := s.automation.ClientForInstance(ctx, inst)
 	if err != nil {
 		return domain.VPSInstance{}, err
 	}
@@ -330,6 +350,7 @@ func (s *Service) EmergencyRenew(ctx context.Context, adminID int64, vpsID int64
 	if s.audit != nil {
 		_ = s.audit.AddAuditLog(ctx, domain.AdminAuditLog{AdminID: adminID, Action: "vps.emergency_renew", TargetType: "vps", TargetID: fmt.Sprintf("%d", inst.ID), DetailJSON: mustJSON(map[string]any{"days": policy.RenewDays})})
 	}
+	s.recordActivity(ctx, adminID, inst, appshared.VPSActivityEmergencyRenew, fmt.Sprintf("%d days", policy.RenewDays))
 	return s.vps.GetInstance(ctx, inst.ID)
 }
 
@@ -342,7 +363,7 @@ func (s *Service) Resize(ctx context.Context, adminID int64, vpsID int64, req Au
 	if req.HostID == 0 {
 		return appshared.ErrInvalidInput
 	}
-	cli, err := s.automation.ClientForGoodsType(ctx, inst.GoodsTypeID)
+	cli, err := s.automation.ClientForInstance(ctx, inst)
 	if err != nil {
 		return err
 	}
@@ -355,6 +376,7 @@ func (s *Service) Resize(ctx context.Context, adminID int64, vpsID int64, req Au
 	if s.audit != nil {
 		_ = s.audit.AddAuditLog(ctx, domain.AdminAuditLog{AdminID: adminID, Action: "vps.resize", TargetType: "vps", TargetID: fmt.Sprintf("%d", inst.ID), DetailJSON: mustJSON(map[string]any{"spec": specJSON})})
 	}
+	s.recordActivity(ctx, adminID, inst, appshared.VPSActivityResize, "")
 	return nil
 }
 
@@ -367,10 +389,13 @@ func (s *Service) Delete(ctx context.Context, adminID int64, vpsID int64) error
 	if hostID == 0 {
 		return appshared.ErrInvalidInput
 	}
-	cli, err := s.automation.ClientForGoodsType(ctx, inst.GoodsTypeID)
+	cli, err := s.automation.ClientForInstance(ctx, inst)
 	if err != nil {
 		return err
 	}
+	if ips, ok := s.vps.(appshared.ExtraIPStore); ok {
+		appshared.ReleaseExtraIPs(ctx, ips, cli, hostID, inst.ID)
+	}
 	if err := cli.DeleteHost(ctx, hostID); err != nil {
 		return err
 	}
@@ -395,7 +420,7 @@ func (s *Service) UpdateExpireAt(ctx context.Context, adminID int64, vpsID int64
 	if hostID == 0 {
 		return domain.VPSInstance{}, appshared.ErrInvalidInput
 	}
-	cli, err := s.automation.ClientForGoodsType(ctx, inst.GoodsTypeID)
+	cli, err := s.automation.ClientForInstance(ctx, inst)
 	if err != nil {
 		return domain.VPSInstance{}, err
 	}
@@ -408,6 +433,7 @@ func (s *Service) UpdateExpireAt(ctx context.Context, adminID int64, vpsID int64
 	if s.audit != nil {
 		_ = s.audit.AddAuditLog(ctx, domain.AdminAuditLog{AdminID: adminID, Action: "vps.update_expire", TargetType: "vps", TargetID: fmt.Sprintf("%d", inst.ID), DetailJSON: mustJSON(map[string]any{"expire_at": expireAt})})
 	}
+	s.recordActivity(ctx, adminID, inst, appshared.VPSActivityExpireChange, expireAt.Format(time.RFC3339))
 	return s.vps.GetInstance(ctx, inst.ID)
 }
 
@@ -466,7 +492,7 @@ func (s *Service) Update(ctx context.Context, adminID int64, vpsID int64, input
 		if hostID == 0 {
 			return domain.VPSInstance{}, appshared.ErrInvalidInput
 		}
-		cli, err := s.automation.ClientForGoodsType(ctx, inst.GoodsTypeID)
+		cli, err := s.automation.ClientForInstance(ctx, inst)
 		if err != nil {
 			return domain.VPSInstance{}, err
 		}
@@ -29,12 +29,17 @@ type Service struct {
 	captchas         appports.CaptchaRepository
 	verify           appports.VerificationCodeRepository
 	userTierAssigner userTierAssigner
+	hooks            pluginHookEmitter
 }
 
 type userTierAssigner interface {
 	EnsureUserHasGroup(ctx context.Context, userID int64) error
 }
 
+type pluginHookEmitter interface {
+	Emit(ctx context.Context, event string, payload any)
+}
+
 const (
 	CodeComplexityDigits  = "digits"
 	CodeComplexityLetters = "letters"
@@ -52,6 +57,11 @@ func (s *Service) SetUserTierAssigner(assigner userTierAssigner) {
 	s.userTierAssigner = assigner
 }
 
+// SetPluginHooks emits user.registered after a successful Register.
+func (s *Service) SetPluginHooks(hooks pluginHookEmitter) {
+	s.hooks = hooks
+}
+
 func (s *Service) CreateCaptcha(ctx context.Context, ttl time.Duration) (domain.Captcha, string, error) {
 	return s.CreateCaptchaWithPolicy(ctx, ttl, 5, CodeComplexityAlnum)
 }
@@ -132,6 +142,13 @@ func (s *Service) Register(ctx context.Context, in RegisterInput) (domain.User,
 			user = refreshed
 		}
 	}
+	if s.hooks != nil {
+		s.hooks.Emit(ctx, appshared.PluginHookUserRegistered, map[string]any{
+			"user_id":  user.ID,
+			"username": user.Username,
+			"email":    user.Email,
+		})
+	}
 	return user, nil
 }
 
@@ -72,7 +72,7 @@ func (s *Service) Add(ctx context.Context, userID int64, packageID int64, system
 			unitBW = pricing.UnitBW
 		}
 	}
-	addonMonthly := int64(spec.AddCores)*unitCore + int64(spec.AddMemGB)*unitMem + int64(spec.AddDiskGB)*unitDisk + int64(spec.AddBWMbps)*unitBW
+	addonMonthly := int64(spec.AddCores)*unitCore + int64(spec.AddMemGB)*unitMem + int64(spec.AddDiskGB)*unitDisk + int64(spec.AddBWMbps)*unitBW + spec.ExtraIPMonthly(plan)
 	unitAmount := int64(math.Round(float64(baseMonthly+addonMonthly) * multiplier))
 	specJSON := mustJSON(spec)
 	item := domain.CartItem{
@@ -140,7 +140,7 @@ func (s *Service) Update(ctx context.Context, userID int64, itemID int64, spec C
 			unitBW = pricing.UnitBW
 		}
 	}
-	addonMonthly := int64(spec.AddCores)*unitCore + int64(spec.AddMemGB)*unitMem + int64(spec.AddDiskGB)*unitDisk + int64(spec.AddBWMbps)*unitBW
+	addonMonthly := int64(spec.AddCores)*unitCore + int64(spec.AddMemGB)*unitMem + int64(spec.AddDiskGB)*unitDisk + int64(spec.AddBWMbps)*unitBW + spec.ExtraIPMonthly(plan)
 	unitAmount := int64(math.Round(float64(baseMonthly+addonMonthly) * multiplier))
 	updated := domain.CartItem{
 		ID:        itemID,
@@ -206,7 +206,7 @@ func validateAddonSpec(spec CartSpec, plan domain.PlanGroup) error {
 	if err := validateAddonValue(spec.AddBWMbps, plan.AddBWMin, plan.AddBWMax, plan.AddBWStep); err != nil {
 		return err
 	}
-	return nil
+	return appshared.ValidateExtraIPs(spec.AddIPv4, spec.AddIPv6, plan)
 }
 
 func validateAddonValue(value, min, max, step int) error {
@@ -241,6 +241,9 @@ func normalizeCartSpec(spec *CartSpec) error {
 	if spec.CycleQty < 0 {
 		return appshared.ErrInvalidInput
 	}
+	if len(spec.UserData) > appshared.MaxGuestUserDataBytes || len(spec.StartupScript) > appshared.MaxGuestUserDataBytes {
+		return domain.ErrUserDataTooLarge
+	}
 	return nil
 }
 
@@ -0,0 +1,391 @@
+package console
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	appports "xiaoheiplay/internal/app/ports"
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+const (
+	settingEnabled     = "vps_console_proxy_enabled"
+	settingPageURL     = "vps_console_page_url"
+	settingTicketTTL   = "vps_console_ticket_ttl_seconds"
+	settingIdleTimeout = "vps_console_idle_timeout_seconds"
+	settingMaxDuration = "vps_console_max_duration_minutes"
+	settingMaxSessions = "vps_console_max_sessions_per_user"
+	settingInsecureTLS = "vps_console_upstream_insecure_tls"
+
+	// defaultPageURL is the console page bundled with the frontend.
+	defaultPageURL     = "/vnc/console.html"
+	defaultTicketTTL   = time.Minute
+	defaultIdleTimeout = 10 * time.Minute
+	defaultMaxDuration = 4 * time.Hour
+	defaultMaxSessions = 2
+
+	// WSPath is the relay endpoint tickets are redeemed at.
+	WSPath = "/api/v1/console/ws"
+)
+
+const (
+	StatusPending = "pending"
+	StatusActive  = "active"
+	StatusEnded   = "ended"
+
+	ReasonClosed         = "closed"
+	ReasonUpstreamClosed = "upstream_closed"
+	ReasonUpstreamError  = "upstream_error"
+	ReasonIdle           = "idle"
+	ReasonMaxDuration    = "max_duration"
+	ReasonRevoked        = "revoked"
+	ReasonRestart        = "restart"
+)
+
+// Ticket is a single-use credential for one console session.
+type Ticket struct {
+	SessionID int64
+	Token     string
+	ExpiresAt time.Time
+}
+
+// Limits bound a running relay. Zero disables the limit.
+type Limits struct {
+	IdleTimeout time.Duration
+	MaxDuration time.Duration
+	InsecureTLS bool
+}
+
+// Target is a redeemed ticket: the active session and the upstream websocket
+// the relay should dial.
+type Target struct {
+	Session domain.ConsoleSession
+	URL     string
+	Limits  Limits
+}
+
+// Service issues console tickets and keeps the session log. The websocket
+// relay itself lives in the HTTP adapter; it registers running sessions here
+// so admins can revoke them.
+type Service struct {
+	vps        appports.VPSRepository
+	automation appports.AutomationClientResolver
+	sessions   appports.ConsoleSessionRepository
+	settings   appports.SettingsRepository
+	audit      appports.AuditRepository
+	key        []byte
+
+	mu   sync.Mutex
+	live map[int64]context.CancelFunc
+}
+
+// NewService derives the ticket signing key from secret. Without a secret a
+// random key is used, so outstanding tickets do not survive a restart.
+func NewService(vps appports.VPSRepository, automation appports.AutomationClientResolver, sessions appports.ConsoleSessionRepository, settings appports.SettingsRepository, audit appports.AuditRepository, secret string) *Service {
+	var key []byte
+	if strings.TrimSpace(secret) != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		_, _ = mac.Write([]byte("console-ticket"))
+		key = mac.Sum(nil)
+	} else {
+		key = make([]byte, 32)
+		_, _ = rand.Read(key)
+	}
+	return &Service{vps: vps, automation: automation, sessions: sessions, settings: settings, audit: audit, key: key, live: map[int64]context.CancelFunc{}}
+}
+
+// Enabled reports whether consoles go through the proxy. The legacy redirect
+// to the provider console URL is only used when an admin sets
+// vps_console_proxy_enabled to false.
+func (s *Service) Enabled(ctx context.Context) bool {
+	enabled, err := strconv.ParseBool(s.setting(ctx, settingEnabled))
+	return err != nil || enabled
+}
+
+// Issue opens a pending session for inst and returns its ticket. Pending
+// tickets count towards the per-user limit until they expire.
+func (s *Service) Issue(ctx context.Context, inst domain.VPSInstance, clientIP string) (Ticket, error) {
+	if err := checkUsable(inst); err != nil {
+		return Ticket{}, err
+	}
+	now := time.Now()
+	open, err := s.sessions.CountOpenConsoleSessions(ctx, inst.UserID, now)
+	if err != nil {
+		return Ticket{}, err
+	}
+	if open >= s.intSetting(ctx, settingMaxSessions, defaultMaxSessions) {
+		return Ticket{}, domain.ErrConsoleSessionLimit
+	}
+	ttl := time.Duration(s.intSetting(ctx, settingTicketTTL, int(defaultTicketTTL/time.Second))) * time.Second
+	session := domain.ConsoleSession{VPSID: inst.ID, UserID: inst.UserID, Status: StatusPending, ClientIP: clientIP, ExpiresAt: now.Add(ttl)}
+	if err := s.sessions.CreateConsoleSession(ctx, &session); err != nil {
+		return Ticket{}, err
+	}
+	return Ticket{SessionID: session.ID, Token: s.sign(session.ID, session.ExpiresAt), ExpiresAt: session.ExpiresAt}, nil
+}
+
+// PageURL is the console page that connects back to the relay with token.
+// The parameters follow noVNC's vnc.html, so a deployed noVNC can replace the
+// bundled page through vps_console_page_url.
+func (s *Service) PageURL(ctx context.Context, token string) string {
+	page := s.setting(ctx, settingPageURL)
+	if page == "" {
+		page = defaultPageURL
+	}
+	path := strings.TrimPrefix(WSPath, "/") + "?ticket=" + url.QueryEscape(token)
+	sep := "?"
+	if strings.Contains(page, "?") {
+		sep = "&"
+	}
+	return page + sep + "autoconnect=1&reconnect=0&resize=scale&path=" + url.QueryEscape(path)
+}
+
+// Redeem consumes a ticket and resolves the upstream console. A ticket can be
+// redeemed once; the session is active from here until Finish.
+func (s *Service) Redeem(ctx context.Context, token string) (Target, error) {
+	id, ok := s.verify(token, time.Now())
+	if !ok {
+		return Target{}, domain.ErrConsoleTicketInvalid
+	}
+	session, err := s.sessions.GetConsoleSession(ctx, id)
+	if errors.Is(err, appshared.ErrNotFound) {
+		return Target{}, domain.ErrConsoleTicketInvalid
+	}
+	if err != nil {
+		return Target{}, err
+	}
+	if session.Status != StatusPending {
+		return Target{}, domain.ErrConsoleTicketInvalid
+	}
+	inst, err := s.vps.GetInstance(ctx, session.VPSID)
+	if err != nil || inst.UserID != session.UserID {
+		return Target{}, domain.ErrConsoleTicketInvalid
+	}
+	if err := checkUsable(inst); err != nil {
+		return Target{}, err
+	}
+	target, err := s.resolve(ctx, inst)
+	if err != nil {
+		_ = s.sessions.EndConsoleSession(ctx, session.ID, ReasonUpstreamError, 0, 0, time.Now())
+		return Target{}, err
+	}
+	upstream, _ := url.Parse(target)
+	now := time.Now()
+	activated, err := s.sessions.ActivateConsoleSession(ctx, session.ID, upstream.Host, now)
+	if err != nil {
+		return Target{}, err
+	}
+	if !activated {
+		return Target{}, domain.ErrConsoleTicketInvalid
+	}
+	session.Status = StatusActive
+	session.UpstreamHost = upstream.Host
+	session.StartedAt = &now
+	return Target{Session: session, URL: target, Limits: s.limits(ctx)}, nil
+}
+
+// Track registers the cancel func of a running relay so Terminate can stop
+// it. The returned func must be called when the relay exits.
+func (s *Service) Track(id int64, cancel context.CancelFunc) func() {
+	s.mu.Lock()
+	s.live[id] = cancel
+	s.mu.Unlock()
+	return func() {
+		s.mu.Lock()
+		delete(s.live, id)
+		s.mu.Unlock()
+	}
+}
+
+// Finish records how a session ended and the bytes relayed.
+func (s *Service) Finish(ctx context.Context, id int64, reason string, clientBytes, upstreamBytes int64) error {
+	return s.sessions.EndConsoleSession(ctx, id, reason, clientBytes, upstreamBytes, time.Now())
+}
+
+// Terminate revokes a pending ticket or disconnects a running session. The
+// change is audited.
+func (s *Service) Terminate(ctx context.Context, adminID, id int64) error {
+	session, err := s.sessions.GetConsoleSession(ctx, id)
+	if err != nil {
+		return err
+	}
+	if session.Status == StatusEnded {
+		return appshared.ErrConflict
+	}
+	s.mu.Lock()
+	cancel := s.live[id]
+	s.mu.Unlock()
+	if cancel != nil {
+		// The relay records the end with its byte counts.
+		cancel()
+	} else if err := s.sessions.EndConsoleSession(ctx, id, ReasonRevoked, session.ClientBytes, session.UpstreamBytes, time.Now()); err != nil {
+		return err
+	}
+	if s.audit != nil {
+		_ = s.audit.AddAuditLog(ctx, domain.AdminAuditLog{AdminID: adminID, Action: "vps.console_terminate", TargetType: "vps", TargetID: fmt.Sprintf("%d", session.VPSID), DetailJSON: mustJSON(map[string]any{"session_id": session.ID, "user_id": session.UserID})})
+	}
+	return nil
+}
+
+func (s *Service) List(ctx context.Context, filter appshared.ConsoleSessionFilter) ([]domain.ConsoleSession, int, error) {
+	return s.sessions.ListConsoleSessions(ctx, filter)
+}
+
+// CloseOrphans ends sessions left active by a previous process. Call it once
+// at startup, before the relay accepts connections.
+func (s *Service) CloseOrphans(ctx context.Context) (int64, error) {
+	return s.sessions.EndActiveConsoleSessions(ctx, ReasonRestart, time.Now())
+}
+
+func (s *Service) resolve(ctx context.Context, inst domain.VPSInstance) (string, error) {
+	hostID, _ := strconv.ParseInt(strings.TrimSpace(inst.AutomationInstanceID), 10, 64)
+	if hostID == 0 || s.automation == nil {
+		return "", appshared.ErrInvalidInput
+	}
+	cli, err := s.automation.ClientForInstance(ctx, inst)
+	if err != nil {
+		return "", err
+	}
+	raw, err := cli.GetVNCURL(ctx, hostID)
+	if err != nil {
+		return "", err
+	}
+	return UpstreamURL(raw)
+}
+
+func (s *Service) limits(ctx context.Context) Limits {
+	insecure, _ := strconv.ParseBool(s.setting(ctx, settingInsecureTLS))
+	return Limits{
+		IdleTimeout: time.Duration(s.intSetting(ctx, settingIdleTimeout, int(defaultIdleTimeout/time.Second))) * time.Second,
+		MaxDuration: time.Duration(s.intSetting(ctx, settingMaxDuration, int(defaultMaxDuration/time.Minute))) * time.Minute,
+		InsecureTLS: insecure,
+	}
+}
+
+// UpstreamURL turns the console URL returned by a plugin into the websocket
+// the relay dials. Websocket URLs are used as-is. noVNC page URLs carry the
+// real endpoint in their host, port, path and encrypt parameters; any other
+// http URL is assumed to be the websocket endpoint itself.
+func UpstreamURL(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return "", appshared.ErrNotSupported
+	}
+	switch u.Scheme {
+	case "ws", "wss":
+		return u.String(), nil
+	case "http", "https":
+	default:
+		return "", appshared.ErrNotSupported
+	}
+	secure := u.Scheme == "https"
+	q := u.Query()
+	if q.Get("path") == "" && q.Get("host") == "" && q.Get("port") == "" {
+		u.Scheme = map[bool]string{true: "wss", false: "ws"}[secure]
+		return u.String(), nil
+	}
+	host := q.Get("host")
+	if host == "" {
+		host = u.Hostname()
+	}
+	port := q.Get("port")
+	if port == "" {
+		port = u.Port()
+	}
+	if encrypt := q.Get("encrypt"); encrypt != "" {
+		secure, _ = strconv.ParseBool(encrypt)
+	}
+	path := strings.TrimPrefix(q.Get("path"), "/")
+	if path == "" {
+		path = "websockify"
+	}
+	if token := q.Get("token"); token != "" && !strings.Contains(path, "?") {
+		path += "?token=" + url.QueryEscape(token)
+	}
+	if port != "" {
+		host = net.JoinHostPort(host, port)
+	}
+	out, err := url.Parse(map[bool]string{true: "wss", false: "ws"}[secure] + "://" + host + "/" + path)
+	if err != nil {
+		return "", appshared.ErrNotSupported
+	}
+	return out.String(), nil
+}
+
+func (s *Service) sign(id int64, expiresAt time.Time) string {
+	payload := strconv.FormatInt(id, 10) + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+	return payload + "." + s.mac(payload)
+}
+
+func (s *Service) verify(token string, now time.Time) (int64, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return 0, false
+	}
+	payload := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(s.mac(payload))) {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	exp, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || now.Unix() >= exp {
+		return 0, false
+	}
+	return id, true
+}
+
+func (s *Service) mac(payload string) string {
+	mac := hmac.New(sha256.New, s.key)
+	_, _ = mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (s *Service) setting(ctx context.Context, key string) string {
+	if s.settings == nil {
+		return ""
+	}
+	setting, err := s.settings.GetSetting(ctx, key)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(setting.ValueJSON)
+}
+
+func (s *Service) intSetting(ctx context.Context, key string, fallback int) int {
+	v, err := strconv.Atoi(s.setting(ctx, key))
+	if err != nil || v <= 0 {
+		return fallback
+	}
+	return v
+}
+
+func checkUsable(inst domain.VPSInstance) error {
+	if inst.AdminStatus != "" && inst.AdminStatus != domain.VPSAdminStatusNormal {
+		return appshared.ErrForbidden
+	}
+	if inst.Status == domain.VPSStatusLocked || inst.Status == domain.VPSStatusExpiredLocked {
+		return appshared.ErrForbidden
+	}
+	return nil
+}
+
+func mustJSON(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
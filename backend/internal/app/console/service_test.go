@@ -0,0 +1,77 @@
+package console_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	appconsole "xiaoheiplay/internal/app/console"
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+	"xiaoheiplay/internal/testutil"
+)
+
+func TestUpstreamURL(t *testing.T) {
+	cases := map[string]string{
+		"wss://console.example/websockify?token=abc":                                  "wss://console.example/websockify?token=abc",
+		"https://panel.example/vnc/abc":                                               "wss://panel.example/vnc/abc",
+		"http://panel.example:8080/vnc.html?host=10.0.0.5&port=6080&path=websockify":  "ws://10.0.0.5:6080/websockify",
+		"https://panel.example/vnc.html?path=websockify%3Ftoken%3Dabc":                "wss://panel.example/websockify?token=abc",
+		"https://panel.example/vnc_auto.html?port=443&token=abc&encrypt=1":            "wss://panel.example:443/websockify?token=abc",
+		"https://panel.example/vnc.html?host=vnc.example&port=5901&encrypt=0&path=ws": "ws://vnc.example:5901/ws",
+	}
+	for raw, want := range cases {
+		got, err := appconsole.UpstreamURL(raw)
+		if err != nil || got != want {
+			t.Fatalf("UpstreamURL(%q) = %q, %v; want %q", raw, got, err, want)
+		}
+	}
+	if _, err := appconsole.UpstreamURL("vnc://10.0.0.5:5901"); !errors.Is(err, appshared.ErrNotSupported) {
+		t.Fatalf("expected raw vnc scheme unsupported, got %v", err)
+	}
+}
+
+func TestService_TicketLifecycle(t *testing.T) {
+	_, repo := testutil.NewTestDB(t, false)
+	ctx := context.Background()
+	user := testutil.CreateUser(t, repo, "viewer", "viewer@example.com", "pass")
+	inst := domain.VPSInstance{UserID: user.ID, AutomationInstanceID: "7", Name: "vm", Status: domain.VPSStatusRunning, SpecJSON: "{}"}
+	if err := repo.CreateInstance(ctx, &inst); err != nil {
+		t.Fatalf("create instance: %v", err)
+	}
+	client := &testutil.FakeAutomationClient{VNCURL: "wss://console.example/websockify"}
+	svc := appconsole.NewService(repo, &testutil.FakeAutomationResolver{Client: client}, repo, repo, repo, "secret")
+	other := appconsole.NewService(repo, &testutil.FakeAutomationResolver{Client: client}, repo, repo, repo, "other")
+
+	ticket, err := svc.Issue(ctx, inst, "198.51.100.7")
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	if _, err := other.Redeem(ctx, ticket.Token); !errors.Is(err, domain.ErrConsoleTicketInvalid) {
+		t.Fatalf("expected ticket bound to signing key, got %v", err)
+	}
+	if err := svc.Terminate(ctx, 1, ticket.SessionID); err != nil {
+		t.Fatalf("terminate: %v", err)
+	}
+	if _, err := svc.Redeem(ctx, ticket.Token); !errors.Is(err, domain.ErrConsoleTicketInvalid) {
+		t.Fatalf("expected revoked ticket rejected, got %v", err)
+	}
+	session, _ := repo.GetConsoleSession(ctx, ticket.SessionID)
+	if session.Status != appconsole.StatusEnded || session.EndReason != appconsole.ReasonRevoked || session.ClientIP != "198.51.100.7" {
+		t.Fatalf("unexpected revoked session %+v", session)
+	}
+
+	ticket, _ = svc.Issue(ctx, inst, "198.51.100.7")
+	target, err := svc.Redeem(ctx, ticket.Token)
+	if err != nil || target.URL != "wss://console.example/websockify" || target.Limits.IdleTimeout <= 0 {
+		t.Fatalf("unexpected target %+v %v", target, err)
+	}
+	if n, err := svc.CloseOrphans(ctx); err != nil || n != 1 {
+		t.Fatalf("expected active session closed at startup, got %d %v", n, err)
+	}
+
+	inst.AdminStatus = domain.VPSAdminStatusAbuse
+	if _, err := svc.Issue(ctx, inst, ""); !errors.Is(err, appshared.ErrForbidden) {
+		t.Fatalf("expected locked instance refused, got %v", err)
+	}
+}
@@ -0,0 +1,683 @@
+package firewall
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	appports "xiaoheiplay/internal/app/ports"
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+const (
+	maxTemplatesPerUser = 20
+	maxRulesPerTemplate = 50
+	maxNameLen          = 64
+	maxDescriptionLen   = 255
+	maxSyncAttempts     = 5
+	maxSyncErrorLen     = 500
+)
+
+// Service manages firewall templates and keeps the provider firewall of each
+// instance in line with the templates attached to it. Only rules the
+// reconciler created itself are ever removed, so rules users add by hand
+// survive.
+type Service struct {
+	templates  appports.FirewallTemplateRepository
+	vps        appports.VPSRepository
+	automation appports.AutomationClientResolver
+	audit      appports.AuditRepository
+	quotas     quotaPolicySource
+}
+
+// quotaPolicySource resolves the per-instance limits. vps.Service implements
+// it; without one the reconciler adds rules without a cap.
+type quotaPolicySource interface {
+	QuotaPolicy(ctx context.Context, inst domain.VPSInstance) appshared.VPSQuotaPolicy
+}
+
+func NewService(templates appports.FirewallTemplateRepository, vps appports.VPSRepository, automation appports.AutomationClientResolver, audit appports.AuditRepository) *Service {
+	return &Service{templates: templates, vps: vps, automation: automation, audit: audit}
+}
+
+// SetQuotaPolicy makes template rules count against firewall_rules_max like
+// rules added by hand.
+func (s *Service) SetQuotaPolicy(quotas quotaPolicySource) {
+	s.quotas = quotas
+}
+
+// AttachedTemplates is what an instance has attached and how far the last
+// reconcile got.
+type AttachedTemplates struct {
+	Templates []domain.FirewallTemplate
+	Sync      domain.VPSFirewallSync
+}
+
+func (s *Service) List(ctx context.Context, userID int64) ([]domain.FirewallTemplate, error) {
+	if userID <= 0 {
+		return nil, appshared.ErrInvalidInput
+	}
+	return s.templates.ListFirewallTemplates(ctx, userID)
+}
+
+func (s *Service) Create(ctx context.Context, userID int64, tpl domain.FirewallTemplate) (domain.FirewallTemplate, error) {
+	if userID <= 0 {
+		return domain.FirewallTemplate{}, appshared.ErrInvalidInput
+	}
+	existing, err := s.templates.ListFirewallTemplates(ctx, userID)
+	if err != nil {
+		return domain.FirewallTemplate{}, err
+	}
+	if len(existing) >= maxTemplatesPerUser {
+		return domain.FirewallTemplate{}, domain.ErrFirewallTemplateLimitReached
+	}
+	tpl.UserID = userID
+	tpl.GoodsTypeID = 0
+	return s.create(ctx, tpl)
+}
+
+// Update replaces the name, description and rules of a template. Every
+// instance it is attached to is marked for reconcile.
+func (s *Service) Update(ctx context.Context, userID, id int64, tpl domain.FirewallTemplate) (domain.FirewallTemplate, error) {
+	current, err := s.owned(ctx, userID, id)
+	if err != nil {
+		return domain.FirewallTemplate{}, err
+	}
+	tpl.GoodsTypeID = 0
+	return s.update(ctx, current, tpl)
+}
+
+// Delete removes a template; the rules it put on instances are removed by the
+// next reconcile.
+func (s *Service) Delete(ctx context.Context, userID, id int64) error {
+	if _, err := s.owned(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.delete(ctx, id)
+}
+
+func (s *Service) AdminList(ctx context.Context) ([]domain.FirewallTemplate, error) {
+	return s.templates.ListFirewallTemplates(ctx, 0)
+}
+
+// AdminCreate creates an admin template. With GoodsTypeID set it is attached
+// to every instance of that goods type when it is provisioned.
+func (s *Service) AdminCreate(ctx context.Context, adminID int64, tpl domain.FirewallTemplate) (domain.FirewallTemplate, error) {
+	if tpl.GoodsTypeID < 0 {
+		return domain.FirewallTemplate{}, appshared.ErrInvalidInput
+	}
+	tpl.UserID = 0
+	created, err := s.create(ctx, tpl)
+	if err != nil {
+		return domain.FirewallTemplate{}, err
+	}
+	s.auditLog(ctx, adminID, "firewall_template.create", created.ID, map[string]any{"name": created.Name, "goods_type_id": created.GoodsTypeID, "rules": len(created.Rules)})
+	return created, nil
+}
+
+func (s *Service) AdminUpdate(ctx context.Context, adminID, id int64, tpl domain.FirewallTemplate) (domain.FirewallTemplate, error) {
+	current, err := s.owned(ctx, 0, id)
+	if err != nil {
+		return domain.FirewallTemplate{}, err
+	}
+	if tpl.GoodsTypeID < 0 {
+		return domain.FirewallTemplate{}, appshared.ErrInvalidInput
+	}
+	updated, err := s.update(ctx, current, tpl)
+	if err != nil {
+		return domain.FirewallTemplate{}, err
+	}
+	s.auditLog(ctx, adminID, "firewall_template.update", id, map[string]any{"name": updated.Name, "goods_type_id": updated.GoodsTypeID, "rules": len(updated.Rules)})
+	return updated, nil
+}
+
+func (s *Service) AdminDelete(ctx context.Context, adminID, id int64) error {
+	current, err := s.owned(ctx, 0, id)
+	if err != nil {
+		return err
+	}
+	if err := s.delete(ctx, id); err != nil {
+		return err
+	}
+	s.auditLog(ctx, adminID, "firewall_template.delete", id, map[string]any{"name": current.Name})
+	return nil
+}
+
+// Attached lists the templates attached to an instance with its sync state.
+func (s *Service) Attached(ctx context.Context, inst domain.VPSInstance) (AttachedTemplates, error) {
+	ids, err := s.templates.ListFirewallTemplateIDsForVPS(ctx, inst.ID)
+	if err != nil {
+		return AttachedTemplates{}, err
+	}
+	out := AttachedTemplates{Templates: make([]domain.FirewallTemplate, 0, len(ids))}
+	for _, id := range ids {
+		tpl, err := s.templates.GetFirewallTemplate(ctx, id)
+		if err != nil {
+			continue
+		}
+		out.Templates = append(out.Templates, tpl)
+	}
+	out.Sync, err = s.syncState(ctx, inst.ID)
+	if err != nil {
+		return AttachedTemplates{}, err
+	}
+	return out, nil
+}
+
+// Attach attaches one of the owner's templates to the instance and applies it.
+func (s *Service) Attach(ctx context.Context, inst domain.VPSInstance, templateID int64) (domain.VPSFirewallSync, error) {
+	tpl, err := s.owned(ctx, inst.UserID, templateID)
+	if err != nil {
+		return domain.VPSFirewallSync{}, err
+	}
+	if limit := s.rulesLimit(ctx, inst); limit > 0 {
+		desired, err := s.desiredRules(ctx, inst.ID)
+		if err != nil {
+			return domain.VPSFirewallSync{}, err
+		}
+		if n := len(mergeRules(desired, tpl.Rules)); n > limit {
+			return domain.VPSFirewallSync{}, fmt.Errorf("%w: at most %d firewall rules", domain.ErrVPSActionQuotaExceeded, limit)
+		}
+	}
+	if err := s.templates.AttachFirewallTemplate(ctx, templateID, inst.ID); err != nil {
+		return domain.VPSFirewallSync{}, err
+	}
+	return s.Reconcile(ctx, inst)
+}
+
+// Detach removes a template from the instance, including admin defaults, and
+// takes its rules off the provider firewall.
+func (s *Service) Detach(ctx context.Context, inst domain.VPSInstance, templateID int64) (domain.VPSFirewallSync, error) {
+	if err := s.templates.DetachFirewallTemplate(ctx, templateID, inst.ID); err != nil {
+		return domain.VPSFirewallSync{}, err
+	}
+	return s.Reconcile(ctx, inst)
+}
+
+// OnProvisioned attaches the admin defaults of the instance goods type. The
+// new instance is usually still being created, so the rules are applied by
+// the reconcile task once it is running.
+func (s *Service) OnProvisioned(ctx context.Context, inst domain.VPSInstance) error {
+	defaults, err := s.templates.ListDefaultFirewallTemplates(ctx, inst.GoodsTypeID)
+	if err != nil || len(defaults) == 0 {
+		return err
+	}
+	for _, tpl := range defaults {
+		if err := s.templates.AttachFirewallTemplate(ctx, tpl.ID, inst.ID); err != nil {
+			return err
+		}
+	}
+	_, err = s.Reconcile(ctx, inst)
+	return err
+}
+
+// Reapply puts the template rules back after a reinstall wiped the provider
+// firewall. Rules still present are left as they are.
+func (s *Service) Reapply(ctx context.Context, inst domain.VPSInstance) error {
+	ids, err := s.templates.ListFirewallTemplateIDsForVPS(ctx, inst.ID)
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+	if fresh, err := s.vps.GetInstance(ctx, inst.ID); err == nil {
+		inst = fresh
+	}
+	_, err = s.Reconcile(ctx, inst)
+	return err
+}
+
+// ReconcilePending retries instances that are pending or failed and returns
+// how many reached the synced state.
+func (s *Service) ReconcilePending(ctx context.Context, limit int) (int, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	items, err := s.templates.ListPendingVPSFirewallSyncs(ctx, maxSyncAttempts, limit)
+	if err != nil {
+		return 0, err
+	}
+	synced := 0
+	for _, item := range items {
+		inst, err := s.vps.GetInstance(ctx, item.VPSID)
+		if errors.Is(err, appshared.ErrNotFound) {
+			item.Status = domain.FirewallSyncSynced
+			item.LastError = ""
+			_ = s.templates.SaveVPSFirewallSync(ctx, item)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if res, err := s.Reconcile(ctx, inst); err == nil && res.Status == domain.FirewallSyncSynced {
+			synced++
+		}
+	}
+	return synced, nil
+}
+
+// AdminReconcile forces a reconcile and resets the retry budget.
+func (s *Service) AdminReconcile(ctx context.Context, adminID, vpsID int64) (domain.VPSFirewallSync, error) {
+	inst, err := s.vps.GetInstance(ctx, vpsID)
+	if err != nil {
+		return domain.VPSFirewallSync{}, err
+	}
+	state, err := s.syncState(ctx, vpsID)
+	if err != nil {
+		return domain.VPSFirewallSync{}, err
+	}
+	state.Attempts = 0
+	if err := s.templates.SaveVPSFirewallSync(ctx, state); err != nil {
+		return domain.VPSFirewallSync{}, err
+	}
+	res, err := s.Reconcile(ctx, inst)
+	s.auditLog(ctx, adminID, "vps.firewall_reconcile", vpsID, map[string]any{"status": res.Status, "error": res.LastError})
+	return res, err
+}
+
+// Reconcile diffs the provider firewall against the union of the attached
+// templates. Managed rules no longer wanted are deleted and missing ones are
+// added. Rules are matched on direction, protocol, method, port and ip;
+// priority is only used when adding. Instances that are not running or
+// stopped are left pending for the reconcile task.
+func (s *Service) Reconcile(ctx context.Context, inst domain.VPSInstance) (domain.VPSFirewallSync, error) {
+	state, err := s.syncState(ctx, inst.ID)
+	if err != nil {
+		return domain.VPSFirewallSync{}, err
+	}
+	desired, err := s.desiredRules(ctx, inst.ID)
+	if err != nil {
+		return state, err
+	}
+	if len(desired) == 0 && len(state.ManagedRules) == 0 {
+		state.Status = domain.FirewallSyncSynced
+		state.LastError = ""
+		return state, s.templates.SaveVPSFirewallSync(ctx, state)
+	}
+	if inst.Status != domain.VPSStatusRunning && inst.Status != domain.VPSStatusStopped {
+		state.Status = domain.FirewallSyncPending
+		return state, s.templates.SaveVPSFirewallSync(ctx, state)
+	}
+	managed, applyErr := s.apply(ctx, inst, desired, state.ManagedRules)
+	state.ManagedRules = managed
+	if applyErr != nil {
+		state.Status = domain.FirewallSyncFailed
+		state.Attempts++
+		state.LastError = truncate(applyErr.Error(), maxSyncErrorLen)
+	} else {
+		now := time.Now()
+		state.Status = domain.FirewallSyncSynced
+		state.Attempts = 0
+		state.LastError = ""
+		state.SyncedAt = &now
+	}
+	if err := s.templates.SaveVPSFirewallSync(ctx, state); err != nil {
+		return state, err
+	}
+	return state, applyErr
+}
+
+// apply returns the managed set as it stands after the changes that
+// succeeded, so a partial run is picked up correctly by the next one.
+func (s *Service) apply(ctx context.Context, inst domain.VPSInstance, desired, previous []domain.FirewallTemplateRule) ([]domain.FirewallTemplateRule, error) {
+	cli, hostID, err := s.client(ctx, inst)
+	if err != nil {
+		return previous, err
+	}
+	current, err := cli.ListFirewallRules(ctx, hostID)
+	if err != nil {
+		return previous, err
+	}
+	wanted := map[string]domain.FirewallTemplateRule{}
+	for _, rule := range desired {
+		wanted[ruleKey(rule)] = rule
+	}
+	managed := map[string]domain.FirewallTemplateRule{}
+	for _, rule := range previous {
+		managed[ruleKey(rule)] = rule
+	}
+	limit := s.rulesLimit(ctx, inst)
+	count := len(current)
+	present := map[string]bool{}
+	for _, item := range current {
+		rule := ruleFromAutomation(item)
+		key := ruleKey(rule)
+		present[key] = true
+		if _, ok := managed[key]; !ok {
+			continue
+		}
+		if _, ok := wanted[key]; ok {
+			continue
+		}
+		if err := cli.DeleteFirewallRule(ctx, hostID, automationRuleID(item)); err != nil {
+			return managedList(managed), fmt.Errorf("delete rule %s: %w", key, err)
+		}
+		count--
+	}
+	for key := range managed {
+		if _, ok := wanted[key]; !ok {
+			delete(managed, key)
+		}
+	}
+	for _, rule := range desired {
+		key := ruleKey(rule)
+		if present[key] {
+			continue
+		}
+		if limit > 0 && count >= limit {
+			return managedList(managed), fmt.Errorf("add rule %s: %w: at most %d firewall rules", key, domain.ErrVPSActionQuotaExceeded, limit)
+		}
+		err := cli.AddFirewallRule(ctx, appshared.AutomationFirewallRuleCreate{
+			HostID:    hostID,
+			Direction: rule.Direction,
+			Protocol:  rule.Protocol,
+			Method:    rule.Method,
+			Port:      rule.Port,
+			IP:        rule.IP,
+			Priority:  rule.Priority,
+		})
+		if err != nil {
+			return managedList(managed), fmt.Errorf("add rule %s: %w", key, err)
+		}
+		present[key] = true
+		managed[key] = rule
+		count++
+	}
+	return managedList(managed), nil
+}
+
+func (s *Service) rulesLimit(ctx context.Context, inst domain.VPSInstance) int {
+	if s.quotas == nil {
+		return 0
+	}
+	limit := s.quotas.QuotaPolicy(ctx, inst).FirewallRulesMax
+	if limit == nil || *limit < 0 {
+		return 0
+	}
+	return *limit
+}
+
+func (s *Service) desiredRules(ctx context.Context, vpsID int64) ([]domain.FirewallTemplateRule, error) {
+	ids, err := s.templates.ListFirewallTemplateIDsForVPS(ctx, vpsID)
+	if err != nil {
+		return nil, err
+	}
+	var out []domain.FirewallTemplateRule
+	for _, id := range ids {
+		tpl, err := s.templates.GetFirewallTemplate(ctx, id)
+		if errors.Is(err, appshared.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = mergeRules(out, tpl.Rules)
+	}
+	return out, nil
+}
+
+// mergeRules appends the rules not already in base, keeping the first of
+// any duplicates.
+func mergeRules(base, rules []domain.FirewallTemplateRule) []domain.FirewallTemplateRule {
+	seen := map[string]bool{}
+	for _, rule := range base {
+		seen[ruleKey(rule)] = true
+	}
+	out := append([]domain.FirewallTemplateRule{}, base...)
+	for _, rule := range rules {
+		key := ruleKey(rule)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, rule)
+	}
+	return out
+}
+
+func (s *Service) syncState(ctx context.Context, vpsID int64) (domain.VPSFirewallSync, error) {
+	state, err := s.templates.GetVPSFirewallSync(ctx, vpsID)
+	if errors.Is(err, appshared.ErrNotFound) {
+		return domain.VPSFirewallSync{VPSID: vpsID, Status: domain.FirewallSyncPending}, nil
+	}
+	return state, err
+}
+
+func (s *Service) create(ctx context.Context, tpl domain.FirewallTemplate) (domain.FirewallTemplate, error) {
+	if err := normalizeTemplate(&tpl); err != nil {
+		return domain.FirewallTemplate{}, err
+	}
+	if err := s.templates.CreateFirewallTemplate(ctx, &tpl); err != nil {
+		return domain.FirewallTemplate{}, err
+	}
+	return tpl, nil
+}
+
+func (s *Service) update(ctx context.Context, current, tpl domain.FirewallTemplate) (domain.FirewallTemplate, error) {
+	if err := normalizeTemplate(&tpl); err != nil {
+		return domain.FirewallTemplate{}, err
+	}
+	current.Name = tpl.Name
+	current.Description = tpl.Description
+	current.GoodsTypeID = tpl.GoodsTypeID
+	current.Rules = tpl.Rules
+	if err := s.templates.UpdateFirewallTemplate(ctx, current); err != nil {
+		return domain.FirewallTemplate{}, err
+	}
+	s.markAttachedPending(ctx, current.ID)
+	return current, nil
+}
+
+func (s *Service) delete(ctx context.Context, id int64) error {
+	vpsIDs, err := s.templates.ListVPSIDsForFirewallTemplate(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.templates.DeleteFirewallTemplate(ctx, id); err != nil {
+		return err
+	}
+	s.markPending(ctx, vpsIDs)
+	return nil
+}
+
+func (s *Service) markAttachedPending(ctx context.Context, templateID int64) {
+	vpsIDs, err := s.templates.ListVPSIDsForFirewallTemplate(ctx, templateID)
+	if err != nil {
+		return
+	}
+	s.markPending(ctx, vpsIDs)
+}
+
+func (s *Service) markPending(ctx context.Context, vpsIDs []int64) {
+	for _, vpsID := range vpsIDs {
+		state, err := s.syncState(ctx, vpsID)
+		if err != nil {
+			continue
+		}
+		state.Status = domain.FirewallSyncPending
+		state.Attempts = 0
+		_ = s.templates.SaveVPSFirewallSync(ctx, state)
+	}
+}
+
+// owned loads a template and checks it belongs to userID; 0 means an admin
+// template.
+func (s *Service) owned(ctx context.Context, userID, id int64) (domain.FirewallTemplate, error) {
+	if id <= 0 {
+		return domain.FirewallTemplate{}, appshared.ErrInvalidInput
+	}
+	tpl, err := s.templates.GetFirewallTemplate(ctx, id)
+	if err != nil {
+		return domain.FirewallTemplate{}, err
+	}
+	if tpl.UserID != userID {
+		return domain.FirewallTemplate{}, appshared.ErrNotFound
+	}
+	return tpl, nil
+}
+
+func (s *Service) client(ctx context.Context, inst domain.VPSInstance) (appshared.AutomationClient, int64, error) {
+	hostID, _ := strconv.ParseInt(strings.TrimSpace(inst.AutomationInstanceID), 10, 64)
+	if hostID == 0 || s.automation == nil {
+		return nil, 0, appshared.ErrInvalidInput
+	}
+	cli, err := s.automation.ClientForInstance(ctx, inst)
+	if err != nil {
+		return nil, 0, err
+	}
+	return cli, hostID, nil
+}
+
+func (s *Service) auditLog(ctx context.Context, adminID int64, action string, targetID int64, detail map[string]any) {
+	if s.audit == nil {
+		return
+	}
+	targetType := "firewall_template"
+	if strings.HasPrefix(action, "vps.") {
+		targetType = "vps"
+	}
+	_ = s.audit.AddAuditLog(ctx, domain.AdminAuditLog{AdminID: adminID, Action: action, TargetType: targetType, TargetID: fmt.Sprintf("%d", targetID), DetailJSON: mustJSON(detail)})
+}
+
+func normalizeTemplate(tpl *domain.FirewallTemplate) error {
+	tpl.Name = strings.TrimSpace(tpl.Name)
+	tpl.Description = strings.TrimSpace(tpl.Description)
+	if tpl.Name == "" || len([]rune(tpl.Name)) > maxNameLen || len([]rune(tpl.Description)) > maxDescriptionLen {
+		return appshared.ErrInvalidInput
+	}
+	if len(tpl.Rules) > maxRulesPerTemplate {
+		return domain.ErrFirewallTemplateTooManyRules
+	}
+	rules := make([]domain.FirewallTemplateRule, 0, len(tpl.Rules))
+	for _, rule := range tpl.Rules {
+		normalized, err := normalizeRule(rule)
+		if err != nil {
+			return err
+		}
+		rules = append(rules, normalized)
+	}
+	tpl.Rules = rules
+	return nil
+}
+
+// normalizeRule maps a rule onto the values the automation plugins accept.
+func normalizeRule(rule domain.FirewallTemplateRule) (domain.FirewallTemplateRule, error) {
+	switch strings.ToLower(strings.TrimSpace(rule.Direction)) {
+	case "in":
+		rule.Direction = "In"
+	case "out":
+		rule.Direction = "Out"
+	default:
+		return rule, domain.ErrFirewallRuleInvalid
+	}
+	rule.Protocol = strings.ToLower(strings.TrimSpace(rule.Protocol))
+	switch rule.Protocol {
+	case "tcp", "udp", "all":
+	default:
+		return rule, domain.ErrFirewallRuleInvalid
+	}
+	rule.Method = strings.ToLower(strings.TrimSpace(rule.Method))
+	switch rule.Method {
+	case "allowed", "denied":
+	default:
+		return rule, domain.ErrFirewallRuleInvalid
+	}
+	rule.Port = strings.TrimSpace(rule.Port)
+	if rule.Port != "" && !validPort(rule.Port) {
+		return rule, domain.ErrFirewallRuleInvalid
+	}
+	rule.IP = strings.TrimSpace(rule.IP)
+	if rule.IP != "" && net.ParseIP(rule.IP) == nil {
+		if _, _, err := net.ParseCIDR(rule.IP); err != nil {
+			return rule, domain.ErrFirewallRuleInvalid
+		}
+	}
+	if rule.Priority < 0 || rule.Priority > 1000 {
+		return rule, domain.ErrFirewallRuleInvalid
+	}
+	return rule, nil
+}
+
+// validPort accepts a port or a from-to range.
+func validPort(value string) bool {
+	parts := strings.SplitN(value, "-", 2)
+	prev := 0
+	for _, part := range parts {
+		port, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || port < 1 || port > 65535 || port < prev {
+			return false
+		}
+		prev = port
+	}
+	return true
+}
+
+func ruleKey(rule domain.FirewallTemplateRule) string {
+	return strings.ToLower(strings.Join([]string{
+		strings.TrimSpace(rule.Direction),
+		strings.TrimSpace(rule.Protocol),
+		strings.TrimSpace(rule.Method),
+		strings.TrimSpace(rule.Port),
+		strings.TrimSpace(rule.IP),
+	}, "|"))
+}
+
+func ruleFromAutomation(item appshared.AutomationFirewallRule) domain.FirewallTemplateRule {
+	str := func(key string) string {
+		if v, ok := item[key]; ok && v != nil {
+			return fmt.Sprint(v)
+		}
+		return ""
+	}
+	return domain.FirewallTemplateRule{
+		Direction: str("direction"),
+		Protocol:  str("protocol"),
+		Method:    str("method"),
+		Port:      str("port"),
+		IP:        str("ip"),
+	}
+}
+
+func automationRuleID(item appshared.AutomationFirewallRule) int64 {
+	switch v := item["id"].(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	default:
+		id, _ := strconv.ParseInt(fmt.Sprint(v), 10, 64)
+		return id
+	}
+}
+
+func managedList(managed map[string]domain.FirewallTemplateRule) []domain.FirewallTemplateRule {
+	keys := make([]string, 0, len(managed))
+	for key := range managed {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	out := make([]domain.FirewallTemplateRule, 0, len(keys))
+	for _, key := range keys {
+		out = append(out, managed[key])
+	}
+	return out
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
+
+func mustJSON(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
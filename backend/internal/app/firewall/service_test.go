@@ -0,0 +1,200 @@
+package firewall_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	appfirewall "xiaoheiplay/internal/app/firewall"
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+	"xiaoheiplay/internal/testutil"
+)
+
+func ruleSet(client *testutil.FakeAutomationClient) map[string]bool {
+	out := map[string]bool{}
+	rules, _ := client.ListFirewallRules(context.Background(), 0)
+	for _, rule := range rules {
+		out[rule["direction"].(string)+" "+rule["protocol"].(string)+" "+rule["port"].(string)] = true
+	}
+	return out
+}
+
+func TestService_ReconcileKeepsManualRules(t *testing.T) {
+	_, repo := testutil.NewTestDB(t, false)
+	ctx := context.Background()
+	user := testutil.CreateUser(t, repo, "fw", "fw@example.com", "pass")
+	inst := domain.VPSInstance{UserID: user.ID, AutomationInstanceID: "42", Name: "web", Status: domain.VPSStatusRunning, SpecJSON: "{}"}
+	if err := repo.CreateInstance(ctx, &inst); err != nil {
+		t.Fatalf("create instance: %v", err)
+	}
+	client := &testutil.FakeAutomationClient{FirewallList: []appshared.AutomationFirewallRule{
+		{"id": int64(1), "direction": "In", "protocol": "tcp", "method": "allowed", "port": "8080", "ip": ""},
+	}}
+	svc := appfirewall.NewService(repo, repo, &testutil.FakeAutomationResolver{Client: client}, repo)
+
+	if _, err := svc.Create(ctx, user.ID, domain.FirewallTemplate{Name: "bad", Rules: []domain.FirewallTemplateRule{{Direction: "sideways", Protocol: "tcp", Method: "allowed"}}}); !errors.Is(err, domain.ErrFirewallRuleInvalid) {
+		t.Fatalf("expected invalid rule, got %v", err)
+	}
+	web, err := svc.Create(ctx, user.ID, domain.FirewallTemplate{Name: "web", Rules: []domain.FirewallTemplateRule{
+		{Direction: "in", Protocol: "TCP", Method: "allowed", Port: "80"},
+		{Direction: "in", Protocol: "tcp", Method: "allowed", Port: "443"},
+	}})
+	if err != nil {
+		t.Fatalf("create template: %v", err)
+	}
+	ssh, err := svc.Create(ctx, user.ID, domain.FirewallTemplate{Name: "ssh", Rules: []domain.FirewallTemplateRule{
+		{Direction: "In", Protocol: "tcp", Method: "allowed", Port: "22", IP: "198.51.100.0/24"},
+		{Direction: "In", Protocol: "tcp", Method: "allowed", Port: "443"},
+	}})
+	if err != nil {
+		t.Fatalf("create template: %v", err)
+	}
+
+	if state, err := svc.Attach(ctx, inst, web.ID); err != nil || state.Status != domain.FirewallSyncSynced {
+		t.Fatalf("attach web: %+v %v", state, err)
+	}
+	if _, err := svc.Attach(ctx, inst, ssh.ID); err != nil {
+		t.Fatalf("attach ssh: %v", err)
+	}
+	got := ruleSet(client)
+	if len(got) != 4 || !got["In tcp 8080"] || !got["In tcp 80"] || !got["In tcp 443"] || !got["In tcp 22"] {
+		t.Fatalf("unexpected rules after attach: %v", got)
+	}
+
+	if _, err := svc.Detach(ctx, inst, web.ID); err != nil {
+		t.Fatalf("detach: %v", err)
+	}
+	got = ruleSet(client)
+	if len(got) != 3 || got["In tcp 80"] || !got["In tcp 443"] || !got["In tcp 8080"] {
+		t.Fatalf("shared and manual rules should stay: %v", got)
+	}
+
+	// A reinstall wipes the provider firewall; only template rules come back.
+	client.FirewallList = nil
+	if err := svc.Reapply(ctx, inst); err != nil {
+		t.Fatalf("reapply: %v", err)
+	}
+	got = ruleSet(client)
+	if len(got) != 2 || !got["In tcp 22"] || !got["In tcp 443"] {
+		t.Fatalf("unexpected rules after reapply: %v", got)
+	}
+
+	other := testutil.CreateUser(t, repo, "other", "other@example.com", "pass")
+	if err := svc.Delete(ctx, other.ID, ssh.ID); !errors.Is(err, appshared.ErrNotFound) {
+		t.Fatalf("foreign delete should be not found, got %v", err)
+	}
+}
+
+func TestService_DefaultsAndPendingRetry(t *testing.T) {
+	_, repo := testutil.NewTestDB(t, false)
+	ctx := context.Background()
+	user := testutil.CreateUser(t, repo, "fwd", "fwd@example.com", "pass")
+	inst := domain.VPSInstance{UserID: user.ID, AutomationInstanceID: "7", GoodsTypeID: 3, Name: "db", Status: domain.VPSStatusProvisioning, SpecJSON: "{}"}
+	if err := repo.CreateInstance(ctx, &inst); err != nil {
+		t.Fatalf("create instance: %v", err)
+	}
+	client := &testutil.FakeAutomationClient{}
+	svc := appfirewall.NewService(repo, repo, &testutil.FakeAutomationResolver{Client: client}, repo)
+
+	tpl, err := svc.AdminCreate(ctx, 1, domain.FirewallTemplate{Name: "baseline", GoodsTypeID: 3, Rules: []domain.FirewallTemplateRule{
+		{Direction: "In", Protocol: "all", Method: "denied", IP: "192.0.2.0/24"},
+	}})
+	if err != nil {
+		t.Fatalf("admin create: %v", err)
+	}
+	if err := svc.OnProvisioned(ctx, inst); err != nil {
+		t.Fatalf("on provisioned: %v", err)
+	}
+	attached, err := svc.Attached(ctx, inst)
+	if err != nil || len(attached.Templates) != 1 || attached.Templates[0].ID != tpl.ID || attached.Sync.Status != domain.FirewallSyncPending {
+		t.Fatalf("expected pending default template, got %+v %v", attached, err)
+	}
+	if len(client.FirewallList) != 0 {
+		t.Fatalf("rules must wait for the instance to run: %v", client.FirewallList)
+	}
+
+	inst.Status = domain.VPSStatusRunning
+	if err := repo.UpdateInstanceStatus(ctx, inst.ID, inst.Status, 2); err != nil {
+		t.Fatalf("update status: %v", err)
+	}
+	client.FirewallAddErr = errors.New("panel down")
+	if n, err := svc.ReconcilePending(ctx, 10); err != nil || n != 0 {
+		t.Fatalf("reconcile while failing: %d %v", n, err)
+	}
+	attached, _ = svc.Attached(ctx, inst)
+	if attached.Sync.Status != domain.FirewallSyncFailed || attached.Sync.Attempts != 1 || attached.Sync.LastError == "" {
+		t.Fatalf("expected failed sync, got %+v", attached.Sync)
+	}
+	client.FirewallAddErr = nil
+	if n, err := svc.ReconcilePending(ctx, 10); err != nil || n != 1 {
+		t.Fatalf("reconcile retry: %d %v", n, err)
+	}
+	if len(client.FirewallList) != 1 {
+		t.Fatalf("expected default rule applied, got %v", client.FirewallList)
+	}
+
+	if err := svc.AdminDelete(ctx, 1, tpl.ID); err != nil {
+		t.Fatalf("admin delete: %v", err)
+	}
+	if n, err := svc.ReconcilePending(ctx, 10); err != nil || n != 1 {
+		t.Fatalf("reconcile after delete: %d %v", n, err)
+	}
+	if len(client.FirewallList) != 0 {
+		t.Fatalf("deleted template rules should be removed, got %v", client.FirewallList)
+	}
+}
+
+type fixedQuota struct {
+	rulesMax int
+}
+
+func (q fixedQuota) QuotaPolicy(ctx context.Context, inst domain.VPSInstance) appshared.VPSQuotaPolicy {
+	return appshared.VPSQuotaPolicy{FirewallRulesMax: &q.rulesMax}
+}
+
+func TestService_TemplateRulesCountAgainstQuota(t *testing.T) {
+	_, repo := testutil.NewTestDB(t, false)
+	ctx := context.Background()
+	user := testutil.CreateUser(t, repo, "fwq", "fwq@example.com", "pass")
+	inst := domain.VPSInstance{UserID: user.ID, AutomationInstanceID: "44", Name: "capped", Status: domain.VPSStatusRunning, SpecJSON: "{}"}
+	if err := repo.CreateInstance(ctx, &inst); err != nil {
+		t.Fatalf("create instance: %v", err)
+	}
+	client := &testutil.FakeAutomationClient{FirewallList: []appshared.AutomationFirewallRule{
+		{"id": int64(1), "direction": "In", "protocol": "tcp", "method": "allowed", "port": "8080", "ip": ""},
+		{"id": int64(2), "direction": "In", "protocol": "tcp", "method": "allowed", "port": "8443", "ip": ""},
+	}}
+	svc := appfirewall.NewService(repo, repo, &testutil.FakeAutomationResolver{Client: client}, repo)
+	svc.SetQuotaPolicy(fixedQuota{rulesMax: 3})
+
+	web, err := svc.Create(ctx, user.ID, domain.FirewallTemplate{Name: "web", Rules: []domain.FirewallTemplateRule{
+		{Direction: "in", Protocol: "tcp", Method: "allowed", Port: "80"},
+		{Direction: "in", Protocol: "tcp", Method: "allowed", Port: "443"},
+	}})
+	if err != nil {
+		t.Fatalf("create template: %v", err)
+	}
+	state, err := svc.Attach(ctx, inst, web.ID)
+	if !errors.Is(err, domain.ErrVPSActionQuotaExceeded) || state.Status != domain.FirewallSyncFailed {
+		t.Fatalf("expected the reconcile to stop at the quota, got %+v %v", state, err)
+	}
+	if got := ruleSet(client); len(got) != 3 {
+		t.Fatalf("expected no more than three rules, got %v", got)
+	}
+
+	big, err := svc.Create(ctx, user.ID, domain.FirewallTemplate{Name: "big", Rules: []domain.FirewallTemplateRule{
+		{Direction: "in", Protocol: "tcp", Method: "allowed", Port: "22"},
+		{Direction: "in", Protocol: "udp", Method: "allowed", Port: "53"},
+	}})
+	if err != nil {
+		t.Fatalf("create template: %v", err)
+	}
+	if _, err := svc.Attach(ctx, inst, big.ID); !errors.Is(err, domain.ErrVPSActionQuotaExceeded) {
+		t.Fatalf("expected attach over the quota rejected, got %v", err)
+	}
+	attached, err := svc.Attached(ctx, inst)
+	if err != nil || len(attached.Templates) != 1 {
+		t.Fatalf("expected only the first template attached, got %+v %v", attached.Templates, err)
+	}
+}
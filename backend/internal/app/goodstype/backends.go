@@ -0,0 +1,254 @@
+package goodstype
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	appports "xiaoheiplay/internal/app/ports"
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+const (
+	backendDownAfterFailures = 3
+	backendProbeTimeout      = 10 * time.Second
+)
+
+// SetBackendPool enables multi-backend placement. Without it every goods type
+// keeps using its single automation binding.
+func (s *Service) SetBackendPool(backends appports.AutomationBackendRepository, automation appports.AutomationClientResolver) {
+	s.backends = backends
+	s.automation = automation
+}
+
+func (s *Service) ListBackends(ctx context.Context, goodsTypeID int64) ([]domain.AutomationBackend, error) {
+	if s.backends == nil {
+		return nil, appshared.ErrNotSupported
+	}
+	if goodsTypeID <= 0 {
+		return nil, appshared.ErrInvalidInput
+	}
+	return s.backends.ListAutomationBackends(ctx, goodsTypeID)
+}
+
+func (s *Service) AddBackend(ctx context.Context, backend *domain.AutomationBackend) error {
+	if s.backends == nil {
+		return appshared.ErrNotSupported
+	}
+	if backend == nil || backend.GoodsTypeID <= 0 {
+		return appshared.ErrInvalidInput
+	}
+	backend.PluginID = strings.TrimSpace(backend.PluginID)
+	backend.InstanceID = strings.TrimSpace(backend.InstanceID)
+	if backend.PluginID == "" || backend.InstanceID == "" {
+		return domain.ErrAutomationBindingInvalid
+	}
+	if backend.Weight <= 0 {
+		backend.Weight = 1
+	}
+	if backend.Capacity < 0 {
+		return appshared.ErrInvalidInput
+	}
+	if _, err := s.repo.GetGoodsType(ctx, backend.GoodsTypeID); err != nil {
+		return err
+	}
+	if s.plugins != nil {
+		if _, err := s.plugins.GetPluginInstallation(ctx, "automation", backend.PluginID, backend.InstanceID); err != nil {
+			return domain.ErrAutomationPluginInstanceNotFound
+		}
+	}
+	existing, err := s.backends.ListAutomationBackends(ctx, backend.GoodsTypeID)
+	if err != nil {
+		return err
+	}
+	for _, item := range existing {
+		if item.PluginID == backend.PluginID && item.InstanceID == backend.InstanceID {
+			return domain.ErrAutomationBackendExists
+		}
+	}
+	backend.Health = domain.AutomationBackendHealthy
+	return s.backends.CreateAutomationBackend(ctx, backend)
+}
+
+func (s *Service) UpdateBackend(ctx context.Context, backend domain.AutomationBackend) error {
+	if s.backends == nil {
+		return appshared.ErrNotSupported
+	}
+	if backend.ID <= 0 || backend.Capacity < 0 {
+		return appshared.ErrInvalidInput
+	}
+	current, err := s.backends.GetAutomationBackend(ctx, backend.ID)
+	if err != nil {
+		return err
+	}
+	if backend.GoodsTypeID > 0 && current.GoodsTypeID != backend.GoodsTypeID {
+		return appshared.ErrNotFound
+	}
+	if backend.Weight <= 0 {
+		backend.Weight = 1
+	}
+	current.Weight = backend.Weight
+	current.Capacity = backend.Capacity
+	current.Enabled = backend.Enabled
+	return s.backends.UpdateAutomationBackend(ctx, current)
+}
+
+func (s *Service) DeleteBackend(ctx context.Context, goodsTypeID, id int64) error {
+	if s.backends == nil {
+		return appshared.ErrNotSupported
+	}
+	if id <= 0 {
+		return appshared.ErrInvalidInput
+	}
+	current, err := s.backends.GetAutomationBackend(ctx, id)
+	if err != nil {
+		return err
+	}
+	if goodsTypeID > 0 && current.GoodsTypeID != goodsTypeID {
+		return appshared.ErrNotFound
+	}
+	return s.backends.DeleteAutomationBackend(ctx, id)
+}
+
+// PlacementCandidates returns the backends a new host may be created on,
+// best first. Backends that are disabled, down or full are left out. A goods
+// type without a pool yields its own binding as the only candidate (ID 0);
+// an unresolved goods type yields none and callers fall back to the resolver.
+func (s *Service) PlacementCandidates(ctx context.Context, goodsTypeID int64) ([]domain.AutomationBackend, error) {
+	if s.repo == nil {
+		return nil, appshared.ErrInvalidInput
+	}
+	if goodsTypeID <= 0 {
+		return nil, nil
+	}
+	var pool []domain.AutomationBackend
+	if s.backends != nil {
+		items, err := s.backends.ListAutomationBackends(ctx, goodsTypeID)
+		if err != nil {
+			return nil, err
+		}
+		pool = items
+	}
+	if len(pool) == 0 {
+		gt, err := s.repo.GetGoodsType(ctx, goodsTypeID)
+		if err != nil || strings.TrimSpace(gt.AutomationPluginID) == "" || strings.TrimSpace(gt.AutomationInstanceID) == "" {
+			return nil, nil
+		}
+		return []domain.AutomationBackend{{
+			GoodsTypeID: gt.ID,
+			PluginID:    gt.AutomationPluginID,
+			InstanceID:  gt.AutomationInstanceID,
+			Weight:      1,
+			Enabled:     true,
+			Health:      domain.AutomationBackendHealthy,
+		}}, nil
+	}
+	ranked := RankBackends(pool)
+	if len(ranked) == 0 {
+		return nil, domain.ErrNoAvailableAutomationBackend
+	}
+	return ranked, nil
+}
+
+// RankBackends orders placeable backends: healthy before degraded, then by
+// weight scaled by the share of capacity still free.
+func RankBackends(items []domain.AutomationBackend) []domain.AutomationBackend {
+	out := make([]domain.AutomationBackend, 0, len(items))
+	for _, item := range items {
+		if !item.Enabled || item.Weight <= 0 || item.Health == domain.AutomationBackendDown {
+			continue
+		}
+		if item.Capacity > 0 && item.ActiveHosts >= item.Capacity {
+			continue
+		}
+		out = append(out, item)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		hi, hj := healthRank(out[i].Health), healthRank(out[j].Health)
+		if hi != hj {
+			return hi < hj
+		}
+		si, sj := placementScore(out[i]), placementScore(out[j])
+		if si != sj {
+			return si > sj
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+func healthRank(health domain.AutomationBackendHealth) int {
+	switch health {
+	case domain.AutomationBackendHealthy, "":
+		return 0
+	case domain.AutomationBackendDegraded:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func placementScore(b domain.AutomationBackend) float64 {
+	if b.Capacity <= 0 {
+		return float64(b.Weight)
+	}
+	free := float64(b.Capacity-b.ActiveHosts) / float64(b.Capacity)
+	return float64(b.Weight) * free
+}
+
+// ReportBackendResult feeds a provisioning or probe outcome into the
+// backend's health. Consecutive failures degrade it and eventually take it
+// out of rotation; one success restores it.
+func (s *Service) ReportBackendResult(ctx context.Context, backend domain.AutomationBackend, runErr error) {
+	if s.backends == nil || backend.ID <= 0 {
+		return
+	}
+	current, err := s.backends.GetAutomationBackend(ctx, backend.ID)
+	if err != nil {
+		return
+	}
+	now := time.Now()
+	current.LastCheckedAt = &now
+	if runErr == nil {
+		current.Health = domain.AutomationBackendHealthy
+		current.ConsecutiveFailures = 0
+		current.LastError = ""
+	} else {
+		current.ConsecutiveFailures++
+		current.LastError = runErr.Error()
+		current.Health = domain.AutomationBackendDegraded
+		if current.ConsecutiveFailures >= backendDownAfterFailures {
+			current.Health = domain.AutomationBackendDown
+		}
+	}
+	_ = s.backends.UpdateAutomationBackendHealth(ctx, current)
+}
+
+// CheckBackendHealth probes every enabled pool member with a cheap catalog
+// call so down backends can return to rotation without a provisioning attempt.
+func (s *Service) CheckBackendHealth(ctx context.Context) (int, error) {
+	if s.backends == nil || s.automation == nil {
+		return 0, nil
+	}
+	items, err := s.backends.ListAutomationBackends(ctx, 0)
+	if err != nil {
+		return 0, err
+	}
+	checked := 0
+	for _, item := range items {
+		if !item.Enabled {
+			continue
+		}
+		cli, err := s.automation.ClientForBackend(ctx, item.PluginID, item.InstanceID)
+		if err == nil {
+			probeCtx, cancel := context.WithTimeout(ctx, backendProbeTimeout)
+			_, err = cli.ListAreas(probeCtx)
+			cancel()
+		}
+		s.ReportBackendResult(ctx, item, err)
+		checked++
+	}
+	return checked, nil
+}
@@ -0,0 +1,86 @@
+package goodstype_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	appgoodstype "xiaoheiplay/internal/app/goodstype"
+	"xiaoheiplay/internal/domain"
+	"xiaoheiplay/internal/testutil"
+)
+
+func TestRankBackends_PrefersHealthThenFreeCapacity(t *testing.T) {
+	ranked := appgoodstype.RankBackends([]domain.AutomationBackend{
+		{ID: 1, Weight: 1, Enabled: true, Health: domain.AutomationBackendDegraded},
+		{ID: 2, Weight: 1, Enabled: true, Health: domain.AutomationBackendHealthy, Capacity: 10, ActiveHosts: 8},
+		{ID: 3, Weight: 1, Enabled: true, Health: domain.AutomationBackendHealthy, Capacity: 10, ActiveHosts: 2},
+		{ID: 4, Weight: 9, Enabled: true, Health: domain.AutomationBackendDown},
+		{ID: 5, Weight: 9, Enabled: false, Health: domain.AutomationBackendHealthy},
+		{ID: 6, Weight: 9, Enabled: true, Health: domain.AutomationBackendHealthy, Capacity: 3, ActiveHosts: 3},
+	})
+	var ids []int64
+	for _, item := range ranked {
+		ids = append(ids, item.ID)
+	}
+	if len(ids) != 3 || ids[0] != 3 || ids[1] != 2 || ids[2] != 1 {
+		t.Fatalf("unexpected ranking: %v", ids)
+	}
+}
+
+func TestService_PlacementCandidatesAndHealth(t *testing.T) {
+	_, repo := testutil.NewTestDB(t, false)
+	ctx := context.Background()
+	gt := domain.GoodsType{Name: "Cloud", Active: true, AutomationCategory: "automation", AutomationPluginID: "auto", AutomationInstanceID: "primary"}
+	if err := repo.CreateGoodsType(ctx, &gt); err != nil {
+		t.Fatalf("create goods type: %v", err)
+	}
+	client := &testutil.FakeAutomationClient{}
+	svc := appgoodstype.NewService(repo, nil)
+	svc.SetBackendPool(repo, &testutil.FakeAutomationResolver{Client: client})
+
+	candidates, err := svc.PlacementCandidates(ctx, gt.ID)
+	if err != nil {
+		t.Fatalf("candidates without pool: %v", err)
+	}
+	if len(candidates) != 1 || candidates[0].ID != 0 || candidates[0].InstanceID != "primary" {
+		t.Fatalf("expected goods type binding as sole candidate, got %+v", candidates)
+	}
+
+	backend := domain.AutomationBackend{GoodsTypeID: gt.ID, PluginID: "auto", InstanceID: "east", Capacity: 1, Enabled: true}
+	if err := svc.AddBackend(ctx, &backend); err != nil {
+		t.Fatalf("add backend: %v", err)
+	}
+	dup := domain.AutomationBackend{GoodsTypeID: gt.ID, PluginID: "auto", InstanceID: "east", Enabled: true}
+	if err := svc.AddBackend(ctx, &dup); !errors.Is(err, domain.ErrAutomationBackendExists) {
+		t.Fatalf("expected duplicate rejected, got %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		svc.ReportBackendResult(ctx, backend, errors.New("panel unreachable"))
+	}
+	got, _ := repo.GetAutomationBackend(ctx, backend.ID)
+	if got.Health != domain.AutomationBackendDown || got.ConsecutiveFailures != 3 || got.LastError != "panel unreachable" {
+		t.Fatalf("expected backend down, got %+v", got)
+	}
+	if _, err := svc.PlacementCandidates(ctx, gt.ID); !errors.Is(err, domain.ErrNoAvailableAutomationBackend) {
+		t.Fatalf("expected no available backend, got %v", err)
+	}
+
+	checked, err := svc.CheckBackendHealth(ctx)
+	if err != nil || checked != 1 {
+		t.Fatalf("check health: %d %v", checked, err)
+	}
+	got, _ = repo.GetAutomationBackend(ctx, backend.ID)
+	if got.Health != domain.AutomationBackendHealthy || got.ConsecutiveFailures != 0 || got.LastCheckedAt == nil {
+		t.Fatalf("expected probe to restore backend, got %+v", got)
+	}
+
+	inst := domain.VPSInstance{UserID: 1, AutomationInstanceID: "9", GoodsTypeID: gt.ID, BackendPluginID: "auto", BackendInstanceID: "east", Name: "vm", Status: domain.VPSStatusRunning, SpecJSON: "{}"}
+	if err := repo.CreateInstance(ctx, &inst); err != nil {
+		t.Fatalf("create instance: %v", err)
+	}
+	if _, err := svc.PlacementCandidates(ctx, gt.ID); !errors.Is(err, domain.ErrNoAvailableAutomationBackend) {
+		t.Fatalf("expected full backend skipped, got %v", err)
+	}
+}
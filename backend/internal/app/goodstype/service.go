@@ -10,8 +10,10 @@ import (
 )
 
 type Service struct {
-	repo    appports.GoodsTypeRepository
-	plugins appports.PluginInstallationRepository
+	repo       appports.GoodsTypeRepository
+	plugins    appports.PluginInstallationRepository
+	backends   appports.AutomationBackendRepository
+	automation appports.AutomationClientResolver
 }
 
 func NewService(repo appports.GoodsTypeRepository, plugins appports.PluginInstallationRepository) *Service {
@@ -88,5 +90,19 @@ func (s *Service) Delete(ctx context.Context, id int64) error {
 	if s.repo == nil || id <= 0 {
 		return appshared.ErrInvalidInput
 	}
-	return s.repo.DeleteGoodsType(ctx, id)
+	if err := s.repo.DeleteGoodsType(ctx, id); err != nil {
+		return err
+	}
+	if s.backends != nil {
+		items, err := s.backends.ListAutomationBackends(ctx, id)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := s.backends.DeleteAutomationBackend(ctx, item.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
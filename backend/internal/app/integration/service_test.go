@@ -38,7 +38,7 @@ func (f fakeAutomationSync) DeleteHost(ctx context.Context, hostID int64) error
 func (f fakeAutomationSync) StartHost(ctx context.Context, hostID int64) error    { return nil }
 func (f fakeAutomationSync) ShutdownHost(ctx context.Context, hostID int64) error { return nil }
 func (f fakeAutomationSync) RebootHost(ctx context.Context, hostID int64) error   { return nil }
-func (f fakeAutomationSync) ResetOS(ctx context.Context, hostID int64, templateID int64, password string) error {
+func (f fakeAutomationSync) ResetOS(ctx context.Context, hostID int64, templateID int64, password string, guest appshared.AutomationGuestInit) error {
 	return nil
 }
 func (f fakeAutomationSync) ResetOSPassword(ctx context.Context, hostID int64, password string) error {
@@ -73,6 +73,30 @@ func (f fakeAutomationSync) AddFirewallRule(ctx context.Context, req appshared.A
 func (f fakeAutomationSync) DeleteFirewallRule(ctx context.Context, hostID int64, ruleID int64) error {
 	return nil
 }
+func (f fakeAutomationSync) ListReverseDNS(ctx context.Context, hostID int64) ([]appshared.AutomationReverseDNSRecord, error) {
+	return nil, nil
+}
+func (f fakeAutomationSync) SetReverseDNS(ctx context.Context, hostID int64, ip, ptr string) error {
+	return nil
+}
+func (f fakeAutomationSync) AssignIP(ctx context.Context, hostID int64, family string) (string, error) {
+	return "", nil
+}
+func (f fakeAutomationSync) ReleaseIP(ctx context.Context, hostID int64, ip string) error {
+	return nil
+}
+func (f fakeAutomationSync) EnterRescue(ctx context.Context, hostID int64, password string) (appshared.AutomationRescueCredentials, error) {
+	return appshared.AutomationRescueCredentials{}, nil
+}
+func (f fakeAutomationSync) ExitRescue(ctx context.Context, hostID int64) error {
+	return nil
+}
+func (f fakeAutomationSync) MountISO(ctx context.Context, hostID int64, iso string) error {
+	return nil
+}
+func (f fakeAutomationSync) UnmountISO(ctx context.Context, hostID int64) error {
+	return nil
+}
 func (f fakeAutomationSync) ListPortMappings(ctx context.Context, hostID int64) ([]appshared.AutomationPortMapping, error) {
 	return nil, nil
 }
@@ -33,6 +33,18 @@ type probeLogSessionPurger interface {
 	PurgeProbeLogSessions(ctx context.Context, before time.Time) error
 }
 
+type vpsActivityPurger interface {
+	PurgeVPSActivities(ctx context.Context, before time.Time) error
+}
+
+type pluginLogPurger interface {
+	PurgePluginLogs(ctx context.Context, before time.Time) error
+}
+
+type pluginHookPurger interface {
+	PurgePluginHookDeliveries(ctx context.Context, before time.Time) error
+}
+
 type Service struct {
 	settings      appports.SettingsRepository
 	audit         auditLogPurger
@@ -41,6 +53,9 @@ type Service struct {
 	taskRuns      taskRunPurger
 	probeEvents   probeStatusEventPurger
 	probeSessions probeLogSessionPurger
+	vpsActivity   vpsActivityPurger
+	pluginLogs    pluginLogPurger
+	pluginHooks   pluginHookPurger
 }
 
 func NewService(
@@ -51,6 +66,9 @@ func NewService(
 	taskRuns taskRunPurger,
 	probeEvents probeStatusEventPurger,
 	probeSessions probeLogSessionPurger,
+	vpsActivity vpsActivityPurger,
+	pluginLogs pluginLogPurger,
+	pluginHooks pluginHookPurger,
 ) *Service {
 	return &Service{
 		settings:      settings,
@@ -60,12 +78,15 @@ func NewService(
 		taskRuns:      taskRuns,
 		probeEvents:   probeEvents,
 		probeSessions: probeSessions,
+		vpsActivity:   vpsActivity,
+		pluginLogs:    pluginLogs,
+		pluginHooks:   pluginHooks,
 	}
 }
 
 func (s *Service) Cleanup(ctx context.Context) (string, error) {
 	now := time.Now()
-	parts := make([]string, 0, 6)
+	parts := make([]string, 0, 10)
 
 	run := func(settingKey string, fallbackDays int, label string, fn func(before time.Time) error) error {
 		if fn == nil {
@@ -125,6 +146,27 @@ func (s *Service) Cleanup(ctx context.Context) (string, error) {
 	if err := run("probe_log_session_retention_days", 7, "probe_session", probeSessionFn); err != nil {
 		return strings.Join(parts, ","), err
 	}
+	var vpsActivityFn func(before time.Time) error
+	if s.vpsActivity != nil {
+		vpsActivityFn = func(before time.Time) error { return s.vpsActivity.PurgeVPSActivities(ctx, before) }
+	}
+	if err := run("vps_activity_retention_days", 180, "vps_activity", vpsActivityFn); err != nil {
+		return strings.Join(parts, ","), err
+	}
+	var pluginLogFn func(before time.Time) error
+	if s.pluginLogs != nil {
+		pluginLogFn = func(before time.Time) error { return s.pluginLogs.PurgePluginLogs(ctx, before) }
+	}
+	if err := run("plugin_log_retention_days", 14, "plugin_log", pluginLogFn); err != nil {
+		return strings.Join(parts, ","), err
+	}
+	var pluginHookFn func(before time.Time) error
+	if s.pluginHooks != nil {
+		pluginHookFn = func(before time.Time) error { return s.pluginHooks.PurgePluginHookDeliveries(ctx, before) }
+	}
+	if err := run("plugin_hook_delivery_retention_days", 14, "plugin_hook", pluginHookFn); err != nil {
+		return strings.Join(parts, ","), err
+	}
 
 	return strings.Join(parts, ","), nil
 }
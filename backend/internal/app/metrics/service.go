@@ -0,0 +1,310 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	appports "xiaoheiplay/internal/app/ports"
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+const (
+	settingEnabled   = "vps_metrics_enabled"
+	settingBatchSize = "vps_metrics_batch_size"
+	settingCallGapMS = "vps_metrics_call_gap_ms"
+
+	defaultBatchSize = 30
+	defaultCallGap   = 500 * time.Millisecond
+	maxCallGap       = 10 * time.Second
+	pruneEvery       = time.Hour
+)
+
+// retention is how long each resolution is kept. Older buckets of a finer
+// resolution are only dropped; coarser buckets are filled as samples arrive.
+var retention = []struct {
+	resolution domain.MetricResolution
+	bucket     time.Duration
+	keep       time.Duration
+}{
+	{domain.MetricResolutionRaw, 0, 24 * time.Hour},
+	{domain.MetricResolution5Min, 5 * time.Minute, 30 * 24 * time.Hour},
+	{domain.MetricResolutionHour, time.Hour, 365 * 24 * time.Hour},
+}
+
+// Series is the answer to one chart query.
+type Series struct {
+	Resolution domain.MetricResolution
+	From       time.Time
+	To         time.Time
+	Points     []domain.VPSMetricPoint
+}
+
+type Service struct {
+	vps        appports.VPSRepository
+	automation appports.AutomationClientResolver
+	metrics    appports.VPSMetricRepository
+	settings   appports.SettingsRepository
+
+	mu        sync.Mutex
+	cursors   map[string]int
+	lastPrune time.Time
+}
+
+func NewService(vps appports.VPSRepository, automation appports.AutomationClientResolver, metrics appports.VPSMetricRepository, settings appports.SettingsRepository) *Service {
+	return &Service{
+		vps:        vps,
+		automation: automation,
+		metrics:    metrics,
+		settings:   settings,
+		cursors:    make(map[string]int),
+	}
+}
+
+// Collect samples running instances once. Instances are grouped by automation
+// backend; each backend gets at most batch calls per run, spaced by the call
+// gap, and a rotating cursor makes later runs continue where this one stopped.
+// Backends are sampled in parallel since their rate limits are independent.
+func (s *Service) Collect(ctx context.Context) (int, error) {
+	if !s.enabled(ctx) {
+		return 0, nil
+	}
+	groups, err := s.runningByBackend(ctx)
+	if err != nil {
+		return 0, err
+	}
+	batch := s.intSetting(ctx, settingBatchSize, defaultBatchSize)
+	if batch <= 0 {
+		batch = defaultBatchSize
+	}
+	gap := time.Duration(s.intSetting(ctx, settingCallGapMS, int(defaultCallGap/time.Millisecond))) * time.Millisecond
+	if gap > maxCallGap {
+		gap = maxCallGap
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		total   int
+		lastErr error
+	)
+	for key, items := range groups {
+		picked := s.nextBatch(key, items, batch)
+		wg.Add(1)
+		go func(picked []domain.VPSInstance) {
+			defer wg.Done()
+			n, err := s.sampleBatch(ctx, picked, gap)
+			mu.Lock()
+			total += n
+			if err != nil {
+				lastErr = err
+			}
+			mu.Unlock()
+		}(picked)
+	}
+	wg.Wait()
+	s.prune(ctx)
+	// Individual hosts failing (booting, provider hiccups) is expected; only
+	// surface an error when nothing could be sampled at all.
+	if total > 0 {
+		return total, nil
+	}
+	return 0, lastErr
+}
+
+// Query returns the series for one instance. An empty resolution picks the
+// finest one still retained for the whole range.
+func (s *Service) Query(ctx context.Context, vpsID int64, from, to time.Time, resolution domain.MetricResolution) (Series, error) {
+	now := time.Now().UTC()
+	from, to = from.UTC(), to.UTC()
+	if to.IsZero() || to.After(now) {
+		to = now
+	}
+	if from.IsZero() {
+		from = to.Add(-24 * time.Hour)
+	}
+	if !from.Before(to) {
+		return Series{}, appshared.ErrInvalidInput
+	}
+	switch resolution {
+	case "":
+		resolution = pickResolution(now, from)
+	case domain.MetricResolutionRaw, domain.MetricResolution5Min, domain.MetricResolutionHour:
+	default:
+		return Series{}, appshared.ErrInvalidInput
+	}
+	points, err := s.metrics.ListVPSMetrics(ctx, vpsID, resolution, from, to)
+	if err != nil {
+		return Series{}, err
+	}
+	return Series{Resolution: resolution, From: from, To: to, Points: points}, nil
+}
+
+func pickResolution(now, from time.Time) domain.MetricResolution {
+	for _, r := range retention {
+		if !from.Before(now.Add(-r.keep)) {
+			return r.resolution
+		}
+	}
+	return domain.MetricResolutionHour
+}
+
+func (s *Service) sampleBatch(ctx context.Context, items []domain.VPSInstance, gap time.Duration) (int, error) {
+	done := 0
+	var lastErr error
+	for i, inst := range items {
+		if i > 0 && !sleepCtx(ctx, gap) {
+			return done, ctx.Err()
+		}
+		if err := s.sample(ctx, inst); err != nil {
+			lastErr = fmt.Errorf("vps %d: %w", inst.ID, err)
+			continue
+		}
+		done++
+	}
+	return done, lastErr
+}
+
+func (s *Service) sample(ctx context.Context, inst domain.VPSInstance) error {
+	hostID, _ := strconv.ParseInt(strings.TrimSpace(inst.AutomationInstanceID), 10, 64)
+	if hostID == 0 {
+		return appshared.ErrInvalidInput
+	}
+	cli, err := s.automation.ClientForInstance(ctx, inst)
+	if err != nil {
+		return err
+	}
+	monitor, err := cli.GetMonitor(ctx, hostID)
+	if err != nil {
+		return err
+	}
+	at := time.Now().UTC()
+	for _, r := range retention {
+		bucket := at.Truncate(time.Second)
+		if r.bucket > 0 {
+			bucket = at.Truncate(r.bucket)
+		}
+		point := domain.VPSMetricPoint{
+			VPSID:          inst.ID,
+			Resolution:     r.resolution,
+			BucketAt:       bucket,
+			CPUPercent:     float64(monitor.CPUPercent),
+			MemoryPercent:  float64(monitor.MemoryPercent),
+			StoragePercent: float64(monitor.StoragePercent),
+			BytesIn:        monitor.BytesIn,
+			BytesOut:       monitor.BytesOut,
+		}
+		if err := s.metrics.AccumulateVPSMetric(ctx, point); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) runningByBackend(ctx context.Context) (map[string][]domain.VPSInstance, error) {
+	groups := map[string][]domain.VPSInstance{}
+	const pageSize = 200
+	for offset := 0; ; {
+		items, total, err := s.vps.ListInstances(ctx, pageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, inst := range items {
+			if inst.Status != domain.VPSStatusRunning || strings.TrimSpace(inst.AutomationInstanceID) == "" {
+				continue
+			}
+			key := backendKey(inst)
+			groups[key] = append(groups[key], inst)
+		}
+		offset += len(items)
+		if offset >= total || len(items) == 0 {
+			break
+		}
+	}
+	for key := range groups {
+		items := groups[key]
+		sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	}
+	return groups, nil
+}
+
+func (s *Service) nextBatch(key string, items []domain.VPSInstance, batch int) []domain.VPSInstance {
+	if batch >= len(items) {
+		return items
+	}
+	s.mu.Lock()
+	start := s.cursors[key] % len(items)
+	s.cursors[key] = (start + batch) % len(items)
+	s.mu.Unlock()
+	out := make([]domain.VPSInstance, 0, batch)
+	for i := 0; i < batch; i++ {
+		out = append(out, items[(start+i)%len(items)])
+	}
+	return out
+}
+
+func (s *Service) prune(ctx context.Context) {
+	now := time.Now().UTC()
+	s.mu.Lock()
+	if now.Sub(s.lastPrune) < pruneEvery {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPrune = now
+	s.mu.Unlock()
+	for _, r := range retention {
+		_, _ = s.metrics.PurgeVPSMetrics(ctx, r.resolution, now.Add(-r.keep))
+	}
+}
+
+func (s *Service) enabled(ctx context.Context) bool {
+	if s.settings == nil {
+		return true
+	}
+	setting, err := s.settings.GetSetting(ctx, settingEnabled)
+	if err != nil {
+		return true
+	}
+	return strings.ToLower(strings.TrimSpace(setting.ValueJSON)) != "false"
+}
+
+func (s *Service) intSetting(ctx context.Context, key string, def int) int {
+	if s.settings == nil {
+		return def
+	}
+	setting, err := s.settings.GetSetting(ctx, key)
+	if err != nil {
+		return def
+	}
+	v, err := strconv.Atoi(strings.TrimSpace(setting.ValueJSON))
+	if err != nil || v < 0 {
+		return def
+	}
+	return v
+}
+
+func backendKey(inst domain.VPSInstance) string {
+	if strings.TrimSpace(inst.BackendPluginID) != "" && strings.TrimSpace(inst.BackendInstanceID) != "" {
+		return inst.BackendPluginID + "/" + inst.BackendInstanceID
+	}
+	return fmt.Sprintf("goods_type:%d", inst.GoodsTypeID)
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,90 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	appmetrics "xiaoheiplay/internal/app/metrics"
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+	"xiaoheiplay/internal/testutil"
+)
+
+func TestService_CollectBatchesPerBackendAndDownsamples(t *testing.T) {
+	_, repo := testutil.NewTestDB(t, false)
+	ctx := context.Background()
+	user := testutil.CreateUser(t, repo, "metrics", "metrics@example.com", "pass")
+	var running []domain.VPSInstance
+	for i := 1; i <= 4; i++ {
+		status := domain.VPSStatusRunning
+		if i == 4 {
+			status = domain.VPSStatusStopped
+		}
+		inst := domain.VPSInstance{UserID: user.ID, AutomationInstanceID: strconv.Itoa(100 + i), Name: "m" + strconv.Itoa(i), Status: status, SpecJSON: "{}"}
+		if err := repo.CreateInstance(ctx, &inst); err != nil {
+			t.Fatalf("create instance: %v", err)
+		}
+		if status == domain.VPSStatusRunning {
+			running = append(running, inst)
+		}
+	}
+	for key, value := range map[string]string{"vps_metrics_batch_size": "2", "vps_metrics_call_gap_ms": "0"} {
+		if err := repo.UpsertSetting(ctx, domain.Setting{Key: key, ValueJSON: value}); err != nil {
+			t.Fatalf("upsert setting: %v", err)
+		}
+	}
+	client := &testutil.FakeAutomationClient{}
+	svc := appmetrics.NewService(repo, &testutil.FakeAutomationResolver{Client: client}, repo, repo)
+
+	if n, err := svc.Collect(ctx); err != nil || n != 2 {
+		t.Fatalf("first collect: %d %v", n, err)
+	}
+	if n, err := svc.Collect(ctx); err != nil || n != 2 {
+		t.Fatalf("second collect: %d %v", n, err)
+	}
+	want := []int64{101, 102, 103, 101}
+	if len(client.MonitorCalls) != len(want) {
+		t.Fatalf("expected rotating batches %v, got %v", want, client.MonitorCalls)
+	}
+	for i := range want {
+		if client.MonitorCalls[i] != want[i] {
+			t.Fatalf("expected rotating batches %v, got %v", want, client.MonitorCalls)
+		}
+	}
+
+	samples := func(resolution domain.MetricResolution) (int, float64) {
+		series, err := svc.Query(ctx, running[0].ID, time.Now().Add(-time.Hour), time.Time{}, resolution)
+		if err != nil {
+			t.Fatalf("query %s: %v", resolution, err)
+		}
+		total, cpu := 0, 0.0
+		for _, p := range series.Points {
+			total += p.Samples
+			cpu += p.CPUPercent * float64(p.Samples)
+		}
+		if total == 0 {
+			return 0, 0
+		}
+		return total, cpu / float64(total)
+	}
+	for _, res := range []domain.MetricResolution{domain.MetricResolutionRaw, domain.MetricResolution5Min, domain.MetricResolutionHour} {
+		if n, cpu := samples(res); n != 2 || cpu != 10 {
+			t.Fatalf("%s: expected 2 samples averaging 10%% cpu, got %d %.1f", res, n, cpu)
+		}
+	}
+
+	series, err := svc.Query(ctx, running[0].ID, time.Now().Add(-72*time.Hour), time.Time{}, "")
+	if err != nil || series.Resolution != domain.MetricResolution5Min {
+		t.Fatalf("expected 5m for a 3 day range, got %s %v", series.Resolution, err)
+	}
+	series, err = svc.Query(ctx, running[0].ID, time.Now().Add(-90*24*time.Hour), time.Time{}, "")
+	if err != nil || series.Resolution != domain.MetricResolutionHour {
+		t.Fatalf("expected 1h for a 90 day range, got %s %v", series.Resolution, err)
+	}
+	if _, err := svc.Query(ctx, running[0].ID, time.Time{}, time.Time{}, "1m"); !errors.Is(err, appshared.ErrInvalidInput) {
+		t.Fatalf("expected unknown resolution rejected, got %v", err)
+	}
+}
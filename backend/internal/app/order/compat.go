@@ -12,31 +12,35 @@ import (
 )
 
 type (
-	OrderRepository          = appports.OrderRepository
-	OrderItemRepository      = appports.OrderItemRepository
-	CartRepository           = appports.CartRepository
-	CatalogRepository        = appports.CatalogRepository
-	SystemImageRepository    = appports.SystemImageRepository
-	BillingCycleRepository   = appports.BillingCycleRepository
-	VPSRepository            = appports.VPSRepository
-	WalletRepository         = appports.WalletRepository
-	PaymentRepository        = appports.PaymentRepository
-	EventPublisher           = appports.EventPublisher
-	AutomationClientResolver = appports.AutomationClientResolver
-	RobotNotifier            = appshared.RobotNotifier
-	AuditRepository          = appports.AuditRepository
-	UserRepository           = appports.UserRepository
-	EmailSender              = appports.EmailSender
-	SettingsRepository       = appports.SettingsRepository
-	AutomationLogRepository  = appports.AutomationLogRepository
-	ProvisionJobRepository   = appports.ProvisionJobRepository
-	ResizeTaskRepository     = appports.ResizeTaskRepository
-	WalletOrderRepository    = appports.WalletOrderRepository
+	OrderRepository            = appports.OrderRepository
+	OrderItemRepository        = appports.OrderItemRepository
+	CartRepository             = appports.CartRepository
+	CatalogRepository          = appports.CatalogRepository
+	SystemImageRepository      = appports.SystemImageRepository
+	BillingCycleRepository     = appports.BillingCycleRepository
+	VPSRepository              = appports.VPSRepository
+	WalletRepository           = appports.WalletRepository
+	PaymentRepository          = appports.PaymentRepository
+	EventPublisher             = appports.EventPublisher
+	AutomationClientResolver   = appports.AutomationClientResolver
+	RobotNotifier              = appshared.RobotNotifier
+	AuditRepository            = appports.AuditRepository
+	UserRepository             = appports.UserRepository
+	EmailSender                = appports.EmailSender
+	SettingsRepository         = appports.SettingsRepository
+	AutomationLogRepository    = appports.AutomationLogRepository
+	ProvisionJobRepository     = appports.ProvisionJobRepository
+	ResizeTaskRepository       = appports.ResizeTaskRepository
+	WalletOrderRepository      = appports.WalletOrderRepository
+	SSHKeyRepository           = appports.SSHKeyRepository
+	VPSExtraIPRepository       = appports.VPSExtraIPRepository
+	ProvisionAttemptRepository = appports.ProvisionAttemptRepository
 
 	AutomationClient               = appshared.AutomationClient
 	AutomationHostInfo             = appshared.AutomationHostInfo
 	AutomationCreateHostResult     = appshared.AutomationCreateHostResult
 	AutomationCreateHostRequest    = appshared.AutomationCreateHostRequest
+	AutomationGuestInit            = appshared.AutomationGuestInit
 	AutomationHostSimple           = appshared.AutomationHostSimple
 	AutomationElasticUpdateRequest = appshared.AutomationElasticUpdateRequest
 	AutomationArea                 = appshared.AutomationArea
@@ -50,24 +54,28 @@ type (
 	AutomationPortMapping          = appshared.AutomationPortMapping
 	AutomationFirewallRuleCreate   = appshared.AutomationFirewallRuleCreate
 	AutomationPortMappingCreate    = appshared.AutomationPortMappingCreate
+	AutomationReverseDNSRecord     = appshared.AutomationReverseDNSRecord
+	AutomationRescueCredentials    = appshared.AutomationRescueCredentials
 	CartSpec                       = appshared.CartSpec
+	ExtraIPQuote                   = appshared.ExtraIPQuote
 	OrderFilter                    = appshared.OrderFilter
 	RobotOrderPayload              = appshared.RobotOrderPayload
 	RobotOrderItem                 = appshared.RobotOrderItem
 )
 
 var (
-	ErrConflict            = appshared.ErrConflict
-	ErrInvalidInput        = appshared.ErrInvalidInput
-	ErrInsufficientBalance = appshared.ErrInsufficientBalance
-	ErrNoPaymentRequired   = appshared.ErrNoPaymentRequired
-	ErrRealNameRequired    = appshared.ErrRealNameRequired
-	ErrNotSupported        = appshared.ErrNotSupported
-	ErrResizeDisabled      = appshared.ErrResizeDisabled
-	ErrResizeInProgress    = appshared.ErrResizeInProgress
-	ErrForbidden           = appshared.ErrForbidden
-	ErrNotFound            = appshared.ErrNotFound
-	ErrResizeSamePlan      = domain.ErrResizeSamePlan
+	ErrConflict               = appshared.ErrConflict
+	ErrInvalidInput           = appshared.ErrInvalidInput
+	ErrInsufficientBalance    = appshared.ErrInsufficientBalance
+	ErrNoPaymentRequired      = appshared.ErrNoPaymentRequired
+	ErrRealNameRequired       = appshared.ErrRealNameRequired
+	ErrNotSupported           = appshared.ErrNotSupported
+	ErrResizeDisabled         = appshared.ErrResizeDisabled
+	ErrResizeInProgress       = appshared.ErrResizeInProgress
+	ErrVPSOperationInProgress = appshared.ErrVPSOperationInProgress
+	ErrForbidden              = appshared.ErrForbidden
+	ErrNotFound               = appshared.ErrNotFound
+	ErrResizeSamePlan         = domain.ErrResizeSamePlan
 )
 
 func WithAutomationLogContext(ctx context.Context, orderID, orderItemID int64) context.Context {
@@ -0,0 +1,327 @@
+package order
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+	"xiaoheiplay/internal/pkg/money"
+)
+
+const featureExtraIP = "extra_ip"
+
+var extraIPFamilies = []string{"ipv4", "ipv6"}
+
+// SetExtraIPs enables extra IP add-ons. Without it specs asking for extra
+// addresses are still priced, but nothing is assigned.
+func (s *OrderService) SetExtraIPs(repo VPSExtraIPRepository) {
+	s.extraIPs = repo
+}
+
+func wantsExtraIPs(spec CartSpec) bool {
+	return spec.AddIPv4 > 0 || spec.AddIPv6 > 0
+}
+
+func extraIPCount(spec CartSpec, family string) int {
+	if family == "ipv6" {
+		return spec.AddIPv6
+	}
+	return spec.AddIPv4
+}
+
+// validateExtraIPPlacement makes sure a checkout item asking for extra IPs
+// can land on at least one backend that supports them.
+func (s *OrderService) validateExtraIPPlacement(ctx context.Context, goodsTypeID int64, spec CartSpec) error {
+	if !wantsExtraIPs(spec) {
+		return nil
+	}
+	if s.extraIPs == nil {
+		return domain.ErrExtraIPNotSupported
+	}
+	if s.features == nil || s.placer == nil {
+		return nil
+	}
+	candidates, err := s.placer.PlacementCandidates(ctx, goodsTypeID)
+	if err != nil || len(candidates) == 0 {
+		return nil
+	}
+	for _, backend := range candidates {
+		if s.backendSupportsExtraIP(ctx, backend) {
+			return nil
+		}
+	}
+	return domain.ErrExtraIPNotSupported
+}
+
+func (s *OrderService) backendSupportsExtraIP(ctx context.Context, backend domain.AutomationBackend) bool {
+	if s.features == nil {
+		return true
+	}
+	return s.features.AutomationFeatureSupported(ctx, backend.PluginID, backend.InstanceID, featureExtraIP)
+}
+
+func (s *OrderService) ListExtraIPs(ctx context.Context, vpsID int64) ([]domain.VPSExtraIP, error) {
+	if s.extraIPs == nil {
+		return []domain.VPSExtraIP{}, nil
+	}
+	return s.extraIPs.ListVPSExtraIPs(ctx, vpsID)
+}
+
+func (s *OrderService) QuoteExtraIPs(ctx context.Context, userID, vpsID int64, ipv4, ipv6 int) (ExtraIPQuote, error) {
+	inst, err := s.extraIPInstance(ctx, userID, vpsID)
+	if err != nil {
+		return ExtraIPQuote{}, err
+	}
+	return s.quoteExtraIPs(ctx, inst, ipv4, ipv6, time.Now())
+}
+
+// CreateExtraIPOrder orders more addresses for a running instance. Like a
+// resize it is exclusive with other pending orders on the same VPS and is
+// approved right away when nothing is due.
+func (s *OrderService) CreateExtraIPOrder(ctx context.Context, userID, vpsID int64, ipv4, ipv6 int) (domain.Order, ExtraIPQuote, error) {
+	if s.realname != nil {
+		if err := s.realname.RequireAction(ctx, userID, "resize_vps"); err != nil {
+			return domain.Order{}, ExtraIPQuote{}, err
+		}
+	}
+	inst, err := s.extraIPInstance(ctx, userID, vpsID)
+	if err != nil {
+		return domain.Order{}, ExtraIPQuote{}, err
+	}
+	if s.items != nil {
+		if pending, err := s.items.HasPendingResizeOrder(ctx, userID, vpsID); err != nil {
+			return domain.Order{}, ExtraIPQuote{}, err
+		} else if pending {
+			return domain.Order{}, ExtraIPQuote{}, ErrResizeInProgress
+		}
+	}
+	if err := s.ensureNoActiveOperation(ctx, vpsID); err != nil {
+		return domain.Order{}, ExtraIPQuote{}, err
+	}
+	quote, err := s.quoteExtraIPs(ctx, inst, ipv4, ipv6, time.Now())
+	if err != nil {
+		return domain.Order{}, ExtraIPQuote{}, err
+	}
+	amount := quote.ChargeAmount
+	status := domain.OrderStatusPendingPayment
+	itemStatus := domain.OrderItemStatusPendingPayment
+	if amount <= 0 {
+		status = domain.OrderStatusPendingReview
+		itemStatus = domain.OrderItemStatusPendingReview
+	}
+	order := domain.Order{
+		UserID:      userID,
+		OrderNo:     fmt.Sprintf("IP-%d-%d", userID, time.Now().Unix()),
+		Source:      resolveOrderSource(ctx),
+		Status:      status,
+		TotalAmount: amount,
+		Currency:    "CNY",
+	}
+	if err := s.orders.CreateOrder(ctx, &order); err != nil {
+		return domain.Order{}, ExtraIPQuote{}, err
+	}
+	item := domain.OrderItem{
+		OrderID:     order.ID,
+		PackageID:   inst.PackageID,
+		GoodsTypeID: inst.GoodsTypeID,
+		Qty:         1,
+		Amount:      amount,
+		Status:      itemStatus,
+		Action:      "add_ip",
+		SpecJSON:    mustJSON(quote),
+	}
+	if err := s.items.CreateOrderItems(ctx, []domain.OrderItem{item}); err != nil {
+		return domain.Order{}, ExtraIPQuote{}, err
+	}
+	if s.events != nil {
+		eventName := "order.pending_payment"
+		if status == domain.OrderStatusPendingReview {
+			eventName = "order.pending_review"
+		}
+		_, _ = s.events.Publish(ctx, order.ID, eventName, map[string]any{"status": order.Status, "total": amount})
+	}
+	if amount <= 0 {
+		if err := s.ApproveOrder(ctx, 0, order.ID); err != nil {
+			return domain.Order{}, ExtraIPQuote{}, err
+		}
+		if updated, err := s.orders.GetOrder(ctx, order.ID); err == nil {
+			order = updated
+		}
+	}
+	return order, quote, nil
+}
+
+// SyncExtraIPs assigns addresses that were paid for but not handed out, e.g.
+// when the plugin failed right after the host was created.
+func (s *OrderService) SyncExtraIPs(ctx context.Context, adminID, vpsID int64) ([]domain.VPSExtraIP, error) {
+	inst, err := s.vps.GetInstance(ctx, vpsID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.assignExtraIPs(ctx, 0, inst.OrderItemID, inst); err != nil {
+		return nil, err
+	}
+	if s.audit != nil {
+		_ = s.audit.AddAuditLog(ctx, domain.AdminAuditLog{AdminID: adminID, Action: "vps.extra_ip_sync", TargetType: "vps", TargetID: fmt.Sprintf("%d", inst.ID), DetailJSON: "{}"})
+	}
+	return s.ListExtraIPs(ctx, inst.ID)
+}
+
+func (s *OrderService) extraIPInstance(ctx context.Context, userID, vpsID int64) (domain.VPSInstance, error) {
+	if s.vps == nil || s.extraIPs == nil {
+		return domain.VPSInstance{}, ErrNotSupported
+	}
+	inst, err := s.vps.GetInstance(ctx, vpsID)
+	if err != nil {
+		return domain.VPSInstance{}, err
+	}
+	if inst.UserID != userID {
+		return domain.VPSInstance{}, ErrForbidden
+	}
+	if inst.ExpireAt != nil && !inst.ExpireAt.After(time.Now()) {
+		return domain.VPSInstance{}, ErrForbidden
+	}
+	return inst, nil
+}
+
+func (s *OrderService) quoteExtraIPs(ctx context.Context, inst domain.VPSInstance, ipv4, ipv6 int, now time.Time) (ExtraIPQuote, error) {
+	if ipv4 < 0 || ipv6 < 0 || ipv4+ipv6 == 0 {
+		return ExtraIPQuote{}, ErrInvalidInput
+	}
+	pkg, err := s.catalog.GetPackage(ctx, inst.PackageID)
+	if err != nil {
+		return ExtraIPQuote{}, err
+	}
+	plan, err := s.catalog.GetPlanGroup(ctx, pkg.PlanGroupID)
+	if err != nil {
+		return ExtraIPQuote{}, err
+	}
+	spec := parseCartSpecJSON(inst.SpecJSON)
+	quote := ExtraIPQuote{
+		VPSID:      inst.ID,
+		AddIPv4:    ipv4,
+		AddIPv6:    ipv6,
+		TargetIPv4: spec.AddIPv4 + ipv4,
+		TargetIPv6: spec.AddIPv6 + ipv6,
+	}
+	if err := appshared.ValidateExtraIPs(quote.TargetIPv4, quote.TargetIPv6, plan); err != nil {
+		return ExtraIPQuote{}, err
+	}
+	quote.MonthlyDelta = CartSpec{AddIPv4: ipv4, AddIPv6: ipv6}.ExtraIPMonthly(plan)
+	quote.ChargeAmount = extraIPCharge(quote.MonthlyDelta, inst, now)
+	return quote, nil
+}
+
+// extraIPCharge prorates a monthly price over what is left of the current
+// period. The period may span several months, so the monthly price is scaled
+// to the whole period first.
+func extraIPCharge(monthly int64, inst domain.VPSInstance, now time.Time) int64 {
+	start, end, ok := currentPeriod(inst, now)
+	if !ok || monthly <= 0 {
+		return 0
+	}
+	months := int64(math.Round(end.Sub(start).Hours() / (24 * 30.4375)))
+	if months < 1 {
+		months = 1
+	}
+	return money.ProrateCents(monthly*months, remainingNanos(inst, now), periodNanos(inst, now))
+}
+
+func (s *OrderService) handleAddIP(ctx context.Context, item domain.OrderItem) error {
+	ctx = WithAutomationLogContext(ctx, item.OrderID, item.ID)
+	var payload ExtraIPQuote
+	if err := json.Unmarshal([]byte(item.SpecJSON), &payload); err != nil {
+		return err
+	}
+	if payload.VPSID <= 0 || s.vps == nil {
+		return ErrInvalidInput
+	}
+	inst, err := s.vps.GetInstance(ctx, payload.VPSID)
+	if err != nil {
+		return err
+	}
+	// Retries of a failed item must not bill the delta twice.
+	spec := parseCartSpecJSON(inst.SpecJSON)
+	if spec.AddIPv4 < payload.TargetIPv4 || spec.AddIPv6 < payload.TargetIPv6 {
+		inst.SpecJSON = setExtraIPCounts(inst.SpecJSON, payload.TargetIPv4, payload.TargetIPv6)
+		inst.MonthlyPrice += payload.MonthlyDelta
+		if err := s.vps.UpdateInstanceLocal(ctx, inst); err != nil {
+			return err
+		}
+	}
+	return s.assignExtraIPs(ctx, item.OrderID, item.ID, inst)
+}
+
+// assignExtraIPs tops the instance up to the counts in its spec. It only
+// requests the missing addresses, so it is safe to call again after a partial
+// failure.
+func (s *OrderService) assignExtraIPs(ctx context.Context, orderID, itemID int64, inst domain.VPSInstance) error {
+	spec := parseCartSpecJSON(inst.SpecJSON)
+	if !wantsExtraIPs(spec) {
+		return nil
+	}
+	if s.extraIPs == nil {
+		return domain.ErrExtraIPNotSupported
+	}
+	hostID := parseHostID(inst.AutomationInstanceID)
+	if hostID == 0 {
+		return ErrInvalidInput
+	}
+	existing, err := s.extraIPs.ListVPSExtraIPs(ctx, inst.ID)
+	if err != nil {
+		return err
+	}
+	have := map[string]int{}
+	for _, ip := range existing {
+		have[ip.Family]++
+	}
+	var cli AutomationClient
+	for _, family := range extraIPFamilies {
+		for i := have[family]; i < extraIPCount(spec, family); i++ {
+			if cli == nil {
+				if cli, err = s.instanceClient(ctx, inst); err != nil {
+					return err
+				}
+			}
+			req := map[string]any{"host_id": hostID, "family": family}
+			addr, err := cli.AssignIP(ctx, hostID, family)
+			if err == nil && strings.TrimSpace(addr) == "" {
+				err = domain.ErrExtraIPNotAssigned
+			}
+			if err != nil {
+				s.logAutomation(ctx, orderID, itemID, "assign_ip", req, map[string]any{"error": err.Error()}, false, err.Error())
+				return err
+			}
+			record := domain.VPSExtraIP{VPSID: inst.ID, OrderItemID: itemID, Family: family, Address: strings.TrimSpace(addr)}
+			if err := s.extraIPs.CreateVPSExtraIP(ctx, &record); err != nil {
+				return err
+			}
+			s.logAutomation(ctx, orderID, itemID, "assign_ip", req, map[string]any{"ip": record.Address}, true, "ok")
+		}
+	}
+	return nil
+}
+
+// provisionExtraIPs runs after a new host is up. A failure does not fail the
+// create item: the host is usable and the missing addresses can be assigned
+// later with SyncExtraIPs.
+func (s *OrderService) provisionExtraIPs(ctx context.Context, orderID, itemID int64, inst domain.VPSInstance) {
+	if err := s.assignExtraIPs(ctx, orderID, itemID, inst); err != nil && s.events != nil {
+		_, _ = s.events.Publish(ctx, orderID, "order.item.extra_ip_failed", map[string]any{"item_id": itemID, "instance_id": inst.ID, "reason": err.Error()})
+	}
+}
+
+func setExtraIPCounts(specJSON string, ipv4, ipv6 int) string {
+	payload := map[string]any{}
+	if strings.TrimSpace(specJSON) != "" {
+		_ = json.Unmarshal([]byte(specJSON), &payload)
+	}
+	payload["add_ipv4"] = ipv4
+	payload["add_ipv6"] = ipv6
+	return mustJSON(payload)
+}
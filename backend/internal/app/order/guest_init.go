@@ -0,0 +1,103 @@
+package order
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	appsshkey "xiaoheiplay/internal/app/sshkey"
+	"xiaoheiplay/internal/domain"
+)
+
+const featureCloudInit = "cloud_init"
+
+type automationFeatureChecker interface {
+	AutomationFeatureSupported(ctx context.Context, pluginID, instanceID, feature string) bool
+}
+
+// SetGuestInit enables SSH keys and user data at checkout. Without a feature
+// checker the plugin client still refuses backends lacking cloud_init, but only
+// once the order is paid.
+func (s *OrderService) SetGuestInit(keys SSHKeyRepository, features automationFeatureChecker) {
+	s.sshKeys = keys
+	s.features = features
+}
+
+func wantsGuestInit(spec CartSpec) bool {
+	return len(spec.SSHKeyIDs) > 0 || spec.UserData != "" || spec.StartupScript != "" || spec.KeyOnly
+}
+
+// validateGuestInit checks the guest init choices of a checkout item: the keys
+// must belong to the buyer and at least one placement backend must support it.
+func (s *OrderService) validateGuestInit(ctx context.Context, userID, goodsTypeID int64, spec CartSpec) error {
+	if !wantsGuestInit(spec) {
+		return nil
+	}
+	guest := AutomationGuestInit{UserData: spec.UserData, StartupScript: spec.StartupScript, DisablePassword: spec.KeyOnly}
+	if len(spec.SSHKeyIDs) > 0 {
+		if s.sshKeys == nil {
+			return ErrNotSupported
+		}
+		keys, err := appsshkey.ResolvePublicKeys(ctx, s.sshKeys, userID, spec.SSHKeyIDs, true)
+		if err != nil {
+			return err
+		}
+		guest.SSHKeys = keys
+	}
+	if err := guest.Validate(); err != nil {
+		return err
+	}
+	if s.features == nil || s.placer == nil {
+		return nil
+	}
+	candidates, err := s.placer.PlacementCandidates(ctx, goodsTypeID)
+	if err != nil || len(candidates) == 0 {
+		return nil
+	}
+	for _, backend := range candidates {
+		if s.backendSupportsGuestInit(ctx, backend) {
+			return nil
+		}
+	}
+	return domain.ErrGuestInitNotSupported
+}
+
+// guestInitForItem resolves the checkout choices at provisioning time. Keys
+// deleted since checkout are skipped; if none remain the host falls back to a
+// password install rather than becoming unreachable.
+func (s *OrderService) guestInitForItem(ctx context.Context, userID int64, spec CartSpec) AutomationGuestInit {
+	guest := AutomationGuestInit{UserData: spec.UserData, StartupScript: spec.StartupScript}
+	if len(spec.SSHKeyIDs) > 0 && s.sshKeys != nil {
+		guest.SSHKeys, _ = appsshkey.ResolvePublicKeys(ctx, s.sshKeys, userID, spec.SSHKeyIDs, false)
+	}
+	guest.DisablePassword = spec.KeyOnly && len(guest.SSHKeys) > 0
+	return guest
+}
+
+// dropGuestScripts removes user data and the startup script from an order
+// item once its host was created. They may hold secrets and are not needed
+// again; a reinstall takes them anew.
+func (s *OrderService) dropGuestScripts(ctx context.Context, item domain.OrderItem) {
+	if strings.TrimSpace(item.SpecJSON) == "" {
+		return
+	}
+	var payload map[string]any
+	if err := json.Unmarshal([]byte(item.SpecJSON), &payload); err != nil {
+		return
+	}
+	_, hasUserData := payload["user_data"]
+	_, hasScript := payload["startup_script"]
+	if !hasUserData && !hasScript {
+		return
+	}
+	delete(payload, "user_data")
+	delete(payload, "startup_script")
+	_ = s.items.UpdateOrderItemSpec(ctx, item.ID, mustJSON(payload))
+}
+
+func (s *OrderService) backendSupportsGuestInit(ctx context.Context, backend domain.AutomationBackend) bool {
+	if s.features == nil {
+		return true
+	}
+	return s.features.AutomationFeatureSupported(ctx, backend.PluginID, backend.InstanceID, featureCloudInit)
+}
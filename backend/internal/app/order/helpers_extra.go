@@ -50,7 +50,7 @@ func validateAddonSpec(spec CartSpec, plan domain.PlanGroup) error {
 	if err := validateAddonValue(spec.AddBWMbps, plan.AddBWMin, plan.AddBWMax, plan.AddBWStep); err != nil {
 		return err
 	}
-	return nil
+	return appshared.ValidateExtraIPs(spec.AddIPv4, spec.AddIPv6, plan)
 }
 
 func validateAddonValue(value, min, max, step int) error {
@@ -48,6 +48,13 @@ type OrderService struct {
 	pricer      userTierPricingResolver
 	userTiers   userTierAutoApprover
 	coupon      couponEngine
+	operations  vpsOperationTracker
+	placer      backendPlacer
+	sshKeys     SSHKeyRepository
+	features    automationFeatureChecker
+	extraIPs    VPSExtraIPRepository
+	firewall    firewallDefaults
+	attempts    ProvisionAttemptRepository
 }
 
 type messageNotifier interface {
@@ -100,6 +107,30 @@ func (s *OrderService) SetCouponService(coupon couponEngine) {
 	s.coupon = coupon
 }
 
+type vpsOperationTracker interface {
+	HasActive(ctx context.Context, vpsID int64) (bool, error)
+	Begin(ctx context.Context, inst domain.VPSInstance, action domain.VPSOperationAction, params any) (domain.VPSOperation, error)
+	Finish(ctx context.Context, op domain.VPSOperation, runErr error)
+}
+
+func (s *OrderService) SetVPSOperationTracker(tracker vpsOperationTracker) {
+	s.operations = tracker
+}
+
+func (s *OrderService) ensureNoActiveOperation(ctx context.Context, vpsID int64) error {
+	if s.operations == nil {
+		return nil
+	}
+	active, err := s.operations.HasActive(ctx, vpsID)
+	if err != nil {
+		return err
+	}
+	if active {
+		return ErrVPSOperationInProgress
+	}
+	return nil
+}
+
 func (s *OrderService) client(ctx context.Context, goodsTypeID int64) (AutomationClient, error) {
 	if s.automation == nil {
 		return nil, ErrInvalidInput
@@ -107,6 +138,132 @@ func (s *OrderService) client(ctx context.Context, goodsTypeID int64) (Automatio
 	return s.automation.ClientForGoodsType(ctx, goodsTypeID)
 }
 
+func (s *OrderService) instanceClient(ctx context.Context, inst domain.VPSInstance) (AutomationClient, error) {
+	if s.automation == nil {
+		return nil, ErrInvalidInput
+	}
+	return s.automation.ClientForInstance(ctx, inst)
+}
+
+type backendPlacer interface {
+	PlacementCandidates(ctx context.Context, goodsTypeID int64) ([]domain.AutomationBackend, error)
+	ReportBackendResult(ctx context.Context, backend domain.AutomationBackend, runErr error)
+}
+
+func (s *OrderService) SetBackendPlacer(placer backendPlacer) {
+	s.placer = placer
+}
+
+type firewallDefaults interface {
+	OnProvisioned(ctx context.Context, inst domain.VPSInstance) error
+}
+
+// SetFirewallTemplates attaches the goods type default firewall templates to
+// newly provisioned instances.
+func (s *OrderService) SetFirewallTemplates(firewall firewallDefaults) {
+	s.firewall = firewall
+}
+
+func (s *OrderService) applyFirewallDefaults(ctx context.Context, orderID, itemID int64, inst domain.VPSInstance) {
+	if s.firewall == nil {
+		return
+	}
+	if err := s.firewall.OnProvisioned(ctx, inst); err != nil && s.events != nil {
+		_, _ = s.events.Publish(ctx, orderID, "order.item.firewall_failed", map[string]any{"item_id": itemID, "instance_id": inst.ID, "reason": err.Error()})
+	}
+}
+
+// createHost places a new host for the goods type. With a backend pool the
+// request fails over down the ranked candidates until one backend accepts it;
+// the returned backend is zero when the goods type binding was used directly.
+func (s *OrderService) createHost(ctx context.Context, order domain.Order, item domain.OrderItem, goodsTypeID int64, req AutomationCreateHostRequest) (AutomationClient, domain.AutomationBackend, AutomationCreateHostResult, error) {
+	var candidates []domain.AutomationBackend
+	if s.placer != nil {
+		items, err := s.placer.PlacementCandidates(ctx, goodsTypeID)
+		if err != nil {
+			return nil, domain.AutomationBackend{}, AutomationCreateHostResult{}, err
+		}
+		candidates = items
+	}
+	if len(candidates) == 0 {
+		cli, err := s.client(ctx, goodsTypeID)
+		if err != nil {
+			return nil, domain.AutomationBackend{}, AutomationCreateHostResult{}, err
+		}
+		res, err := cli.CreateHost(ctx, req)
+		return cli, domain.AutomationBackend{}, res, err
+	}
+	if s.automation == nil {
+		return nil, domain.AutomationBackend{}, AutomationCreateHostResult{}, ErrInvalidInput
+	}
+	needsExtraIP := wantsExtraIPs(parseCartSpecJSON(item.SpecJSON))
+	var lastErr error
+	for _, backend := range candidates {
+		if !req.Init.Empty() && !s.backendSupportsGuestInit(ctx, backend) {
+			lastErr = domain.ErrGuestInitNotSupported
+			continue
+		}
+		if needsExtraIP && !s.backendSupportsExtraIP(ctx, backend) {
+			lastErr = domain.ErrExtraIPNotSupported
+			continue
+		}
+		cli, err := s.automation.ClientForBackend(ctx, backend.PluginID, backend.InstanceID)
+		if err != nil {
+			s.placer.ReportBackendResult(ctx, backend, err)
+			lastErr = err
+			continue
+		}
+		if len(candidates) > 1 && !backendCarriesCatalog(ctx, cli, req) {
+			lastErr = domain.ErrAutomationBackendCatalogMismatch
+			s.logAutomation(ctx, order.ID, item.ID, "create_host", req, map[string]any{"error": lastErr.Error(), "plugin_id": backend.PluginID, "instance_id": backend.InstanceID}, false, lastErr.Error())
+			continue
+		}
+		res, err := cli.CreateHost(ctx, req)
+		s.placer.ReportBackendResult(ctx, backend, err)
+		if err == nil {
+			return cli, backend, res, nil
+		}
+		lastErr = err
+		if len(candidates) > 1 {
+			s.logAutomation(ctx, order.ID, item.ID, "create_host", req, map[string]any{"error": err.Error(), "plugin_id": backend.PluginID, "instance_id": backend.InstanceID}, false, err.Error())
+		}
+	}
+	return nil, domain.AutomationBackend{}, AutomationCreateHostResult{}, lastErr
+}
+
+// backendCarriesCatalog reports whether a pooled backend lists the line and
+// image the request was built from. The catalog is synced from one panel, so
+// another panel that lacks them would create the wrong product.
+func backendCarriesCatalog(ctx context.Context, cli AutomationClient, req AutomationCreateHostRequest) bool {
+	lines, err := cli.ListLines(ctx)
+	if err != nil {
+		return false
+	}
+	found := false
+	for _, line := range lines {
+		if line.ID == req.LineID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return false
+	}
+	if req.OS == "" {
+		return true
+	}
+	images, err := cli.ListImages(ctx, req.LineID)
+	if err != nil {
+		return false
+	}
+	for _, img := range images {
+		if img.Name == req.OS {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *OrderService) CreateOrderFromCart(ctx context.Context, userID int64, currency string, idemKey string, couponCode string) (domain.Order, []domain.OrderItem, error) {
 	if s.realname != nil {
 		if err := s.realname.RequireAction(ctx, userID, "purchase_vps"); err != nil {
@@ -163,6 +320,12 @@ func (s *OrderService) CreateOrderFromCart(ctx context.Context, userID int64, cu
 		if err := normalizeCartSpec(&spec); err != nil {
 			return domain.Order{}, nil, err
 		}
+		if err := s.validateGuestInit(ctx, userID, pkg.GoodsTypeID, spec); err != nil {
+			return domain.Order{}, nil, err
+		}
+		if err := s.validateExtraIPPlacement(ctx, pkg.GoodsTypeID, spec); err != nil {
+			return domain.Order{}, nil, err
+		}
 		unitTotal, unitBase, addonCore, addonMem, addonDisk, addonBW, months, err := s.priceBreakdownForPackage(ctx, userID, pkg, plan, spec)
 		if err != nil {
 			return domain.Order{}, nil, err
@@ -341,6 +504,12 @@ func (s *OrderService) CreateOrderFromItems(ctx context.Context, userID int64, c
 		if err != nil {
 			return domain.Order{}, nil, err
 		}
+		if err := s.validateGuestInit(ctx, userID, pkg.GoodsTypeID, in.Spec); err != nil {
+			return domain.Order{}, nil, err
+		}
+		if err := s.validateExtraIPPlacement(ctx, pkg.GoodsTypeID, in.Spec); err != nil {
+			return domain.Order{}, nil, err
+		}
 		unitTotal, unitBase, addonCore, addonMem, addonDisk, addonBW, months, err := s.priceBreakdownForPackage(ctx, userID, pkg, plan, in.Spec)
 		if err != nil {
 			return domain.Order{}, nil, err
@@ -863,7 +1032,7 @@ func (s *OrderService) RefreshOrder(ctx context.Context, userID int64, orderID i
 		if hostID == 0 {
 			continue
 		}
-		cli, err := s.client(itemCtx, inst.GoodsTypeID)
+		cli, err := s.instanceClient(itemCtx, inst)
 		if err != nil {
 			continue
 		}
@@ -1048,7 +1217,7 @@ func (s *OrderService) RetryProvision(orderID int64) error {
 		}
 		for _, item := range items {
 			switch item.Action {
-			case "renew", "emergency_renew", "resize", "refund":
+			case "renew", "emergency_renew", "resize", "refund", "add_ip":
 				vpsID := parseOrderItemVPSID(item.SpecJSON)
 				if vpsID <= 0 {
 					continue
@@ -1139,6 +1308,8 @@ func (s *OrderService) provisionOrder(orderID int64) {
 	allActive := true
 	anyProvisioning := false
 	anyFailed := false
+	anyRefunded := false
+	anyActive := false
 	shouldProcess := func(status domain.OrderItemStatus) bool {
 		switch status {
 		case domain.OrderItemStatusActive, domain.OrderItemStatusRejected, domain.OrderItemStatusCanceled:
@@ -1151,6 +1322,8 @@ func (s *OrderService) provisionOrder(orderID int64) {
 		if !shouldProcess(item.Status) {
 			if item.Status != domain.OrderItemStatusActive {
 				allActive = false
+			} else {
+				anyActive = true
 			}
 			continue
 		}
@@ -1158,6 +1331,16 @@ func (s *OrderService) provisionOrder(orderID int64) {
 		case "create":
 			_ = s.items.UpdateOrderItemStatus(ctx, item.ID, domain.OrderItemStatusProvisioning)
 			inst, err := s.provisionItem(ctx, order, item)
+			if errors.Is(err, domain.ErrProvisionRefunded) {
+				// The item was refunded after every fallback failed; it no
+				// longer holds the order back from completing.
+				anyRefunded = true
+				_ = s.items.UpdateOrderItemStatus(ctx, item.ID, domain.OrderItemStatusCanceled)
+				if s.events != nil {
+					_, _ = s.events.Publish(ctx, order.ID, "order.item.refunded", map[string]any{"item_id": item.ID, "reason": err.Error()})
+				}
+				continue
+			}
 			if err != nil {
 				allActive = false
 				if errors.Is(err, ErrProvisioning) {
@@ -1171,6 +1354,7 @@ func (s *OrderService) provisionOrder(orderID int64) {
 				}
 				continue
 			}
+			anyActive = true
 			_ = s.items.UpdateOrderItemStatus(ctx, item.ID, domain.OrderItemStatusActive)
 			_ = s.items.UpdateOrderItemAutomation(ctx, item.ID, inst.AutomationInstanceID)
 			if s.events != nil {
@@ -1209,6 +1393,11 @@ func (s *OrderService) provisionOrder(orderID int64) {
 		case "resize":
 			_ = s.items.UpdateOrderItemStatus(ctx, item.ID, domain.OrderItemStatusProvisioning)
 			if err := s.handleResize(ctx, item); err != nil {
+				if errors.Is(err, ErrVPSOperationInProgress) && s.queueResizeRetry(ctx, order.ID, item) == nil {
+					allActive = false
+					anyProvisioning = true
+					continue
+				}
 				allActive = false
 				anyFailed = true
 				_ = s.items.UpdateOrderItemStatus(ctx, item.ID, domain.OrderItemStatusFailed)
@@ -1236,6 +1425,21 @@ func (s *OrderService) provisionOrder(orderID int64) {
 			if s.events != nil {
 				_, _ = s.events.Publish(ctx, order.ID, "order.item.active", map[string]any{"item_id": item.ID})
 			}
+		case "add_ip":
+			_ = s.items.UpdateOrderItemStatus(ctx, item.ID, domain.OrderItemStatusProvisioning)
+			if err := s.handleAddIP(ctx, item); err != nil {
+				allActive = false
+				anyFailed = true
+				_ = s.items.UpdateOrderItemStatus(ctx, item.ID, domain.OrderItemStatusFailed)
+				if s.events != nil {
+					_, _ = s.events.Publish(ctx, order.ID, "order.item.failed", map[string]any{"item_id": item.ID, "reason": err.Error()})
+				}
+				continue
+			}
+			_ = s.items.UpdateOrderItemStatus(ctx, item.ID, domain.OrderItemStatusActive)
+			if s.events != nil {
+				_, _ = s.events.Publish(ctx, order.ID, "order.item.active", map[string]any{"item_id": item.ID})
+			}
 		default:
 			continue
 		}
@@ -1244,6 +1448,8 @@ func (s *OrderService) provisionOrder(orderID int64) {
 	finalStatus := domain.OrderStatusActive
 	if anyFailed {
 		finalStatus = domain.OrderStatusFailed
+	} else if anyRefunded && !anyActive {
+		finalStatus = domain.OrderStatusCanceled
 	} else if !allActive || anyProvisioning {
 		finalStatus = domain.OrderStatusProvisioning
 	}
@@ -1260,6 +1466,9 @@ func (s *OrderService) provisionOrder(orderID int64) {
 	} else if finalStatus == domain.OrderStatusFailed && s.messages != nil {
 		_ = s.messages.NotifyUser(ctx, order.UserID, "provision_failed", "Provision Failed", "Order "+order.OrderNo+" failed to provision.")
 	}
+	if anyRefunded && s.messages != nil {
+		_ = s.messages.NotifyUser(ctx, order.UserID, "provision_refunded", "Provision Refunded", "Order "+order.OrderNo+" could not be provisioned and the amount was refunded to your wallet.")
+	}
 }
 
 func (s *OrderService) provisionItem(ctx context.Context, order domain.Order, item domain.OrderItem) (domain.VPSInstance, error) {
@@ -1268,10 +1477,6 @@ func (s *OrderService) provisionItem(ctx context.Context, order domain.Order, it
 	if err != nil {
 		return domain.VPSInstance{}, err
 	}
-	cli, err := s.client(ctx, pkg.GoodsTypeID)
-	if err != nil {
-		return domain.VPSInstance{}, err
-	}
 	plan, err := s.catalog.GetPlanGroup(ctx, pkg.PlanGroupID)
 	if err != nil {
 		return domain.VPSInstance{}, err
@@ -1282,15 +1487,6 @@ func (s *OrderService) provisionItem(ctx context.Context, order domain.Order, it
 	}
 	var spec CartSpec
 	_ = json.Unmarshal([]byte(item.SpecJSON), &spec)
-	cpu := pkg.Cores + spec.AddCores
-	mem := pkg.MemoryGB + spec.AddMemGB
-	disk := pkg.DiskGB + spec.AddDiskGB
-	bw := pkg.BandwidthMB + spec.AddBWMbps
-
-	portNum := pkg.PortNum
-	if portNum <= 0 {
-		portNum = defaultPortNum
-	}
 
 	hostName := fmt.Sprintf("ecs-%d-%d", order.UserID, time.Now().UnixNano())
 	sysPwd := randomPass(systemPasswordLength)
@@ -1300,24 +1496,28 @@ func (s *OrderService) provisionItem(ctx context.Context, order domain.Order, it
 		months = 1
 	}
 	expireAt := time.Now().AddDate(0, months, 0)
-	req := AutomationCreateHostRequest{
-		LineID:     plan.LineID,
+	base := AutomationCreateHostRequest{
 		OS:         img.Name,
-		CPU:        cpu,
-		MemoryGB:   mem,
-		DiskGB:     disk,
-		Bandwidth:  bw,
-		PortNum:    portNum,
 		ExpireTime: expireAt,
 		HostName:   hostName,
 		SysPwd:     sysPwd,
 		VNCPwd:     vncPwd,
+		Init:       s.guestInitForItem(ctx, order.UserID, spec),
+	}
+	if base.Init.DisablePassword {
+		base.SysPwd = ""
 	}
-	res, err := cli.CreateHost(ctx, req)
+	primary := provisionTarget{Strategy: domain.ProvisionAttemptPrimary, Package: pkg, LineID: plan.LineID}
+	placed, err := s.placeHost(ctx, order, item, plan, primary, spec, base)
 	if err != nil {
-		s.logAutomation(ctx, order.ID, item.ID, "create_host", req, map[string]any{"error": err.Error()}, false, err.Error())
 		return domain.VPSInstance{}, err
 	}
+	cli, backend, res, req := placed.Client, placed.Backend, placed.Result, placed.Request
+	s.dropGuestScripts(ctx, item)
+	if placed.Target.Package.ID != pkg.ID {
+		item = s.switchItemPackage(ctx, order, item, pkg, placed.Target.Package)
+	}
+	pkg = placed.Target.Package
 	var hostID int64
 	if res.HostID > 0 {
 		hostID = res.HostID
@@ -1337,7 +1537,7 @@ func (s *OrderService) provisionItem(ctx context.Context, order domain.Order, it
 	info, err := s.waitHostActive(ctx, cli, hostID, 5, 6*time.Second)
 	if err != nil {
 		if errors.Is(err, ErrProvisioning) {
-			if err := s.ensureProvisioningInstance(ctx, order, item, hostID, hostName, sysPwd, vncPwd, expireAt); err != nil {
+			if err := s.ensureProvisioningInstance(ctx, order, item, backend, placed.Target.LineID, hostID, hostName, sysPwd, vncPwd, expireAt); err != nil {
 				return domain.VPSInstance{}, err
 			}
 			_ = s.items.UpdateOrderItemAutomation(ctx, item.ID, fmt.Sprintf("%d", hostID))
@@ -1359,12 +1559,17 @@ func (s *OrderService) provisionItem(ctx context.Context, order domain.Order, it
 		exp = &expireAt
 	}
 	snap := s.buildVPSLocalSnapshot(ctx, order.UserID, item)
+	if placed.Target.LineID > 0 {
+		snap.LineID = placed.Target.LineID
+	}
 	specJSON := setCurrentPeriod(item.SpecJSON, time.Now(), *exp)
 	inst := domain.VPSInstance{
 		UserID:               order.UserID,
 		OrderItemID:          item.ID,
 		AutomationInstanceID: fmt.Sprintf("%d", hostID),
 		GoodsTypeID:          pkg.GoodsTypeID,
+		BackendPluginID:      backend.PluginID,
+		BackendInstanceID:    backend.InstanceID,
 		Name:                 info.HostName,
 		Region:               snap.Region,
 		RegionID:             snap.RegionID,
@@ -1389,6 +1594,8 @@ func (s *OrderService) provisionItem(ctx context.Context, order domain.Order, it
 		return domain.VPSInstance{}, err
 	}
 	s.logAutomation(ctx, order.ID, item.ID, "create_host", req, res.Raw, true, "ok")
+	s.provisionExtraIPs(ctx, order.ID, item.ID, inst)
+	s.applyFirewallDefaults(ctx, order.ID, item.ID, inst)
 	return inst, nil
 }
 
@@ -1422,7 +1629,7 @@ func (s *OrderService) handleRenew(ctx context.Context, item domain.OrderItem) e
 	if hostID == 0 {
 		return ErrInvalidInput
 	}
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.instanceClient(ctx, inst)
 	if err != nil {
 		return err
 	}
@@ -1485,7 +1692,7 @@ func (s *OrderService) handleEmergencyRenew(ctx context.Context, item domain.Ord
 	if hostID == 0 {
 		return ErrInvalidInput
 	}
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.instanceClient(ctx, inst)
 	if err != nil {
 		return err
 	}
@@ -1532,7 +1739,7 @@ func (s *OrderService) handleResize(ctx context.Context, item domain.OrderItem)
 	if hostID == 0 {
 		return ErrInvalidInput
 	}
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.instanceClient(ctx, inst)
 	if err != nil {
 		return err
 	}
@@ -1556,10 +1763,23 @@ func (s *OrderService) handleResize(ctx context.Context, item domain.OrderItem)
 	if bw > 0 {
 		req.Bandwidth = &bw
 	}
+	var op domain.VPSOperation
+	if s.operations != nil {
+		op, err = s.operations.Begin(ctx, inst, domain.VPSOperationElasticUpdate, req)
+		if err != nil {
+			return err
+		}
+	}
 	if err := cli.ElasticUpdate(ctx, req); err != nil {
 		s.logAutomation(ctx, item.OrderID, item.ID, "elastic_update", req, map[string]any{"error": err.Error()}, false, err.Error())
+		if s.operations != nil {
+			s.operations.Finish(ctx, op, err)
+		}
 		return err
 	}
+	if s.operations != nil {
+		s.operations.Finish(ctx, op, nil)
+	}
 	targetPkgID := inst.PackageID
 	if payload.TargetPackageID > 0 {
 		targetPkgID = payload.TargetPackageID
@@ -1581,7 +1801,8 @@ func (s *OrderService) handleResize(ctx context.Context, item domain.OrderItem)
 			inst.MonthlyPrice += int64(payload.Spec.AddCores)*plan.UnitCore +
 				int64(payload.Spec.AddMemGB)*plan.UnitMem +
 				int64(payload.Spec.AddDiskGB)*plan.UnitDisk +
-				int64(payload.Spec.AddBWMbps)*plan.UnitBW
+				int64(payload.Spec.AddBWMbps)*plan.UnitBW +
+				parseCartSpecJSON(inst.SpecJSON).ExtraIPMonthly(plan)
 		}
 		inst.SpecJSON = mergeSpecJSON(inst.SpecJSON, payload.Spec)
 		_ = s.vps.UpdateInstanceLocal(ctx, inst)
@@ -1693,10 +1914,13 @@ func (s *OrderService) deleteVPSForRefund(ctx context.Context, inst domain.VPSIn
 	if hostID == 0 {
 		return ErrInvalidInput
 	}
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.instanceClient(ctx, inst)
 	if err != nil {
 		return err
 	}
+	if s.extraIPs != nil {
+		appshared.ReleaseExtraIPs(ctx, s.extraIPs, cli, hostID, inst.ID)
+	}
 	if err := cli.DeleteHost(ctx, hostID); err != nil {
 		return err
 	}
@@ -1840,6 +2064,11 @@ func (s *OrderService) executeResizeTask(ctx context.Context, task domain.Resize
 	}
 	_ = s.items.UpdateOrderItemStatus(ctx, item.ID, domain.OrderItemStatusProvisioning)
 	if err := s.handleResize(ctx, item); err != nil {
+		if errors.Is(err, ErrVPSOperationInProgress) {
+			// The item is paid for; wait for the running operation instead of
+			// failing it.
+			return s.requeueResizeTask(ctx, task, item)
+		}
 		_ = s.items.UpdateOrderItemStatus(ctx, item.ID, domain.OrderItemStatusFailed)
 		if s.events != nil {
 			_, _ = s.events.Publish(ctx, task.OrderID, "order.item.failed", map[string]any{"item_id": item.ID, "reason": err.Error()})
@@ -2003,7 +2232,8 @@ func (s *OrderService) priceBreakdownForPackage(ctx context.Context, userID int6
 	diskAmount := int64(math.Round(float64(diskMonthly) * multiplier))
 	bwAmount := int64(math.Round(float64(bwMonthly) * multiplier))
 	addonAmount := coreAmount + memAmount + diskAmount + bwAmount
-	total := baseAmount + addonAmount
+	ipAmount := int64(math.Round(float64(spec.ExtraIPMonthly(plan)) * multiplier))
+	total := baseAmount + addonAmount + ipAmount
 	return total, baseAmount, coreAmount, memAmount, diskAmount, bwAmount, months, nil
 }
 
@@ -2107,7 +2337,8 @@ func (s *OrderService) buildVPSLocalSnapshot(ctx context.Context, userID int64,
 			addon = int64(spec.AddCores)*unitCore +
 				int64(spec.AddMemGB)*unitMem +
 				int64(spec.AddDiskGB)*unitDisk +
-				int64(spec.AddBWMbps)*unitBW
+				int64(spec.AddBWMbps)*unitBW +
+				spec.ExtraIPMonthly(plan)
 		}
 		snap.MonthlyPrice += addon
 		if region, err := s.catalog.GetRegion(ctx, plan.RegionID); err == nil {
@@ -2117,7 +2348,7 @@ func (s *OrderService) buildVPSLocalSnapshot(ctx context.Context, userID int64,
 	return snap
 }
 
-func (s *OrderService) ensureProvisioningInstance(ctx context.Context, order domain.Order, item domain.OrderItem, hostID int64, hostName, sysPwd, vncPwd string, expireAt time.Time) error {
+func (s *OrderService) ensureProvisioningInstance(ctx context.Context, order domain.Order, item domain.OrderItem, backend domain.AutomationBackend, lineID, hostID int64, hostName, sysPwd, vncPwd string, expireAt time.Time) error {
 	if s.vps == nil {
 		return nil
 	}
@@ -2130,6 +2361,8 @@ func (s *OrderService) ensureProvisioningInstance(ctx context.Context, order dom
 	if err == nil {
 		if hostID > 0 && inst.AutomationInstanceID != fmt.Sprintf("%d", hostID) {
 			inst.AutomationInstanceID = fmt.Sprintf("%d", hostID)
+			inst.BackendPluginID = backend.PluginID
+			inst.BackendInstanceID = backend.InstanceID
 			if hostName != "" {
 				inst.Name = hostName
 			}
@@ -2162,11 +2395,16 @@ func (s *OrderService) ensureProvisioningInstance(ctx context.Context, order dom
 		return err
 	}
 	snap := s.buildVPSLocalSnapshot(ctx, order.UserID, item)
+	if lineID > 0 {
+		snap.LineID = lineID
+	}
 	inst = domain.VPSInstance{
 		UserID:               order.UserID,
 		OrderItemID:          item.ID,
 		AutomationInstanceID: fmt.Sprintf("%d", hostID),
 		GoodsTypeID:          item.GoodsTypeID,
+		BackendPluginID:      backend.PluginID,
+		BackendInstanceID:    backend.InstanceID,
 		Name:                 hostName,
 		Region:               snap.Region,
 		RegionID:             snap.RegionID,
@@ -2537,6 +2775,9 @@ func (s *OrderService) CreateResizeOrder(ctx context.Context, userID int64, vpsI
 			return domain.Order{}, ResizeQuote{}, ErrResizeInProgress
 		}
 	}
+	if err := s.ensureNoActiveOperation(ctx, vpsID); err != nil {
+		return domain.Order{}, ResizeQuote{}, err
+	}
 	if inst.ExpireAt != nil && !inst.ExpireAt.After(time.Now()) {
 		return domain.Order{}, ResizeQuote{}, ErrForbidden
 	}
@@ -2764,6 +3005,9 @@ func (s *OrderService) QuoteResizeOrder(ctx context.Context, userID int64, vpsID
 			return ResizeQuote{}, CartSpec{}, ErrResizeInProgress
 		}
 	}
+	if err := s.ensureNoActiveOperation(ctx, vpsID); err != nil {
+		return ResizeQuote{}, CartSpec{}, err
+	}
 	if inst.ExpireAt != nil && !inst.ExpireAt.After(time.Now()) {
 		return ResizeQuote{}, CartSpec{}, ErrForbidden
 	}
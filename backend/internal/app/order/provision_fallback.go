@@ -0,0 +1,240 @@
+package order
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+const (
+	provisionRefundRefType         = "provision_refund"
+	provisionFallbackRefundRefType = "provision_fallback_refund"
+)
+
+// provisionTarget is one place a create item can be provisioned: the ordered
+// package on its own line, the same package on a sibling line or an
+// equivalent fallback package.
+type provisionTarget struct {
+	Strategy domain.ProvisionAttemptStrategy
+	Package  domain.Package
+	LineID   int64
+}
+
+type provisionPlacement struct {
+	Client  AutomationClient
+	Backend domain.AutomationBackend
+	Result  AutomationCreateHostResult
+	Request AutomationCreateHostRequest
+	Target  provisionTarget
+}
+
+func (s *OrderService) SetProvisionAttempts(repo ProvisionAttemptRepository) {
+	s.attempts = repo
+}
+
+func (s *OrderService) ProvisionAttempts(ctx context.Context, filter appshared.ProvisionAttemptFilter) ([]domain.ProvisionAttempt, int, error) {
+	if s.attempts == nil {
+		return nil, 0, nil
+	}
+	return s.attempts.ListProvisionAttempts(ctx, filter)
+}
+
+// placeHost creates the host for the primary target and, when the plan group
+// fallback policy matches the failure, retries on sibling lines and fallback
+// packages. With auto refund enabled an exhausted item is refunded to the
+// wallet and domain.ErrProvisionRefunded is returned.
+func (s *OrderService) placeHost(ctx context.Context, order domain.Order, item domain.OrderItem, plan domain.PlanGroup, primary provisionTarget, spec CartSpec, base AutomationCreateHostRequest) (provisionPlacement, error) {
+	attempt := 0
+	try := func(target provisionTarget) (provisionPlacement, error) {
+		attempt++
+		req := provisionRequestFor(base, target, spec)
+		placedItem := item
+		placedItem.PackageID = target.Package.ID
+		cli, backend, res, err := s.createHost(ctx, order, placedItem, target.Package.GoodsTypeID, req)
+		if err != nil {
+			s.logAutomation(ctx, order.ID, item.ID, "create_host", req, map[string]any{"error": err.Error(), "strategy": target.Strategy}, false, err.Error())
+		}
+		s.recordProvisionAttempt(ctx, order, item, attempt, target, err)
+		return provisionPlacement{Client: cli, Backend: backend, Result: res, Request: req, Target: target}, err
+	}
+	placed, err := try(primary)
+	if err == nil {
+		return placed, nil
+	}
+	policy := loadProvisionFallbackPolicy(ctx, s.settings, plan.ID)
+	if policy.Empty() || !policy.Matches(err) {
+		return provisionPlacement{}, err
+	}
+	lastErr := err
+	for _, target := range s.provisionFallbackTargets(ctx, plan, primary, policy) {
+		placed, err := try(target)
+		if err == nil {
+			return placed, nil
+		}
+		lastErr = err
+	}
+	if !policy.AutoRefund {
+		return provisionPlacement{}, lastErr
+	}
+	attempt++
+	refundErr := s.refundFailedProvision(ctx, order, item, lastErr)
+	s.recordProvisionAttempt(ctx, order, item, attempt, provisionTarget{Strategy: domain.ProvisionAttemptRefund, Package: primary.Package}, refundErr)
+	if refundErr != nil {
+		return provisionPlacement{}, lastErr
+	}
+	return provisionPlacement{}, domain.ErrProvisionRefunded
+}
+
+func (s *OrderService) provisionFallbackTargets(ctx context.Context, plan domain.PlanGroup, primary provisionTarget, policy appshared.ProvisionFallbackPolicy) []provisionTarget {
+	var targets []provisionTarget
+	if policy.SiblingLines && plan.RegionID > 0 {
+		if groups, err := s.catalog.ListPlanGroups(ctx); err == nil {
+			seen := map[int64]bool{primary.LineID: true}
+			for _, group := range groups {
+				if group.ID == plan.ID || group.GoodsTypeID != plan.GoodsTypeID || group.RegionID != plan.RegionID {
+					continue
+				}
+				if !group.Active || group.LineID <= 0 || group.CapacityRemaining == 0 || seen[group.LineID] {
+					continue
+				}
+				seen[group.LineID] = true
+				targets = append(targets, provisionTarget{Strategy: domain.ProvisionAttemptSiblingLine, Package: primary.Package, LineID: group.LineID})
+			}
+		}
+	}
+	for _, id := range policy.FallbackPackageIDs {
+		if id == primary.Package.ID {
+			continue
+		}
+		pkg, err := s.catalog.GetPackage(ctx, id)
+		if err != nil || !pkg.Active || pkg.CapacityRemaining == 0 {
+			continue
+		}
+		group, err := s.catalog.GetPlanGroup(ctx, pkg.PlanGroupID)
+		if err != nil || !group.Active || group.CapacityRemaining == 0 {
+			continue
+		}
+		targets = append(targets, provisionTarget{Strategy: domain.ProvisionAttemptFallbackPackage, Package: pkg, LineID: group.LineID})
+	}
+	return targets
+}
+
+func (s *OrderService) recordProvisionAttempt(ctx context.Context, order domain.Order, item domain.OrderItem, attempt int, target provisionTarget, runErr error) {
+	row := domain.ProvisionAttempt{
+		OrderID:     order.ID,
+		OrderItemID: item.ID,
+		Attempt:     attempt,
+		Strategy:    target.Strategy,
+		LineID:      target.LineID,
+		PackageID:   target.Package.ID,
+		Success:     runErr == nil,
+	}
+	if runErr != nil {
+		row.ErrorMessage = runErr.Error()
+	}
+	if s.attempts != nil {
+		_ = s.attempts.CreateProvisionAttempt(ctx, &row)
+	}
+	if s.events != nil {
+		_, _ = s.events.Publish(ctx, order.ID, "order.item.provision_attempt", map[string]any{
+			"item_id":    item.ID,
+			"attempt":    attempt,
+			"strategy":   target.Strategy,
+			"line_id":    target.LineID,
+			"package_id": target.Package.ID,
+			"success":    row.Success,
+			"reason":     row.ErrorMessage,
+		})
+	}
+}
+
+// refundFailedProvision credits the item amount back to the wallet. The
+// wallet transaction is keyed by the order item so a retried order is never
+// refunded twice.
+func (s *OrderService) refundFailedProvision(ctx context.Context, order domain.Order, item domain.OrderItem, cause error) error {
+	amount := item.Amount
+	if order.TotalAmount > 0 && amount > order.TotalAmount {
+		amount = order.TotalAmount
+	}
+	if amount <= 0 {
+		return nil
+	}
+	meta := map[string]any{
+		"order_id":      order.ID,
+		"order_item_id": item.ID,
+		"reason":        cause.Error(),
+	}
+	return s.createAndApproveWalletRefund(ctx, order.UserID, amount, fmt.Sprintf("provision refund order %s", order.OrderNo), meta, provisionRefundRefType, item.ID)
+}
+
+// switchItemPackage records that the item was provisioned on a fallback
+// package. A cheaper fallback refunds the base price difference to the
+// wallet; a dearer one is not charged to an order that is already paid.
+func (s *OrderService) switchItemPackage(ctx context.Context, order domain.Order, item domain.OrderItem, ordered, used domain.Package) domain.OrderItem {
+	months := item.DurationMonths
+	if months <= 0 {
+		months = 1
+	}
+	amount := item.Amount
+	diff := (ordered.Monthly - used.Monthly) * int64(months)
+	if diff > amount {
+		diff = amount
+	}
+	if diff > 0 {
+		meta := map[string]any{
+			"order_id":        order.ID,
+			"order_item_id":   item.ID,
+			"from_package_id": ordered.ID,
+			"to_package_id":   used.ID,
+			"reason":          "fallback package",
+		}
+		if err := s.createAndApproveWalletRefund(ctx, order.UserID, diff, fmt.Sprintf("fallback package difference order %s", order.OrderNo), meta, provisionFallbackRefundRefType, item.ID); err == nil {
+			amount -= diff
+		}
+	}
+	if err := s.items.SwitchOrderItemPackage(ctx, item.ID, used.ID, amount); err != nil {
+		s.logAutomation(ctx, order.ID, item.ID, "switch_package", map[string]any{"package_id": used.ID}, map[string]any{"error": err.Error()}, false, err.Error())
+	}
+	item.PackageID = used.ID
+	item.Amount = amount
+	return item
+}
+
+func provisionRequestFor(base AutomationCreateHostRequest, target provisionTarget, spec CartSpec) AutomationCreateHostRequest {
+	req := base
+	pkg := target.Package
+	req.LineID = target.LineID
+	req.CPU = pkg.Cores + spec.AddCores
+	req.MemoryGB = pkg.MemoryGB + spec.AddMemGB
+	req.DiskGB = pkg.DiskGB + spec.AddDiskGB
+	req.Bandwidth = pkg.BandwidthMB + spec.AddBWMbps
+	req.PortNum = pkg.PortNum
+	if req.PortNum <= 0 {
+		req.PortNum = defaultPortNum
+	}
+	return req
+}
+
+func loadProvisionFallbackPolicy(ctx context.Context, repo SettingsRepository, planGroupID int64) appshared.ProvisionFallbackPolicy {
+	if repo == nil || planGroupID <= 0 {
+		return appshared.ProvisionFallbackPolicy{}
+	}
+	setting, err := repo.GetSetting(ctx, appshared.PlanGroupProvisionFallbackSettingKey)
+	if err != nil {
+		return appshared.ProvisionFallbackPolicy{}
+	}
+	raw := strings.TrimSpace(setting.ValueJSON)
+	if raw == "" || raw == "{}" {
+		return appshared.ProvisionFallbackPolicy{}
+	}
+	var all map[string]appshared.ProvisionFallbackPolicy
+	if err := json.Unmarshal([]byte(raw), &all); err != nil || all == nil {
+		return appshared.ProvisionFallbackPolicy{}
+	}
+	return all[strconv.FormatInt(planGroupID, 10)]
+}
@@ -2,10 +2,20 @@ package order_test
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"strconv"
 	"testing"
 	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	appgoodstype "xiaoheiplay/internal/app/goodstype"
 	apporder "xiaoheiplay/internal/app/order"
 	appshared "xiaoheiplay/internal/app/shared"
+	appsshkey "xiaoheiplay/internal/app/sshkey"
 	"xiaoheiplay/internal/domain"
 	"xiaoheiplay/internal/testutil"
 )
@@ -106,3 +116,463 @@ func TestOrderService_ApproveProvisionFailure(t *testing.T) {
 	}
 	t.Fatalf("expected item failed")
 }
+
+func TestOrderService_ProvisionFailsOverAcrossBackendPool(t *testing.T) {
+	_, repo := testutil.NewTestDB(t, false)
+	seed := testutil.SeedCatalog(t, repo)
+	user := testutil.CreateUser(t, repo, "prov3", "prov3@example.com", "pass")
+	ctx := context.Background()
+
+	gt := domain.GoodsType{Name: "Pooled", Active: true, AutomationCategory: "automation", AutomationPluginID: "auto", AutomationInstanceID: "primary"}
+	if err := repo.CreateGoodsType(ctx, &gt); err != nil {
+		t.Fatalf("create goods type: %v", err)
+	}
+	seed.Package.GoodsTypeID = gt.ID
+	if err := repo.UpdatePackage(ctx, seed.Package); err != nil {
+		t.Fatalf("update package: %v", err)
+	}
+	goodsTypes := appgoodstype.NewService(repo, nil)
+	lines := []appshared.AutomationLine{{ID: seed.PlanGroup.LineID, Name: "line"}}
+	images := []appshared.AutomationImage{{ImageID: 1, Name: seed.SystemImage.Name}}
+	broken := &testutil.FakeAutomationClient{CreateHostErr: context.DeadlineExceeded, Lines: lines, Images: images}
+	healthy := &testutil.FakeAutomationClient{
+		CreateHostResult: appshared.AutomationCreateHostResult{HostID: 2001},
+		HostInfo: map[int64]appshared.AutomationHostInfo{
+			2001: {HostID: 2001, HostName: "pooled", State: 2},
+		},
+		Lines:  lines,
+		Images: images,
+	}
+	// A panel with its own catalog must not receive IDs synced from another.
+	foreign := &testutil.FakeAutomationClient{
+		CreateHostResult: appshared.AutomationCreateHostResult{HostID: 3001},
+		Lines:            []appshared.AutomationLine{{ID: seed.PlanGroup.LineID + 100, Name: "other"}},
+		Images:           images,
+	}
+	autoResolver := &testutil.FakeAutomationResolver{Backends: map[string]appshared.AutomationClient{
+		"auto/a":  broken,
+		"auto/b":  healthy,
+		"other/c": foreign,
+	}}
+	goodsTypes.SetBackendPool(repo, autoResolver)
+	mismatched := domain.AutomationBackend{GoodsTypeID: gt.ID, PluginID: "other", InstanceID: "c", Weight: 9, Enabled: true}
+	first := domain.AutomationBackend{GoodsTypeID: gt.ID, PluginID: "auto", InstanceID: "a", Weight: 5, Enabled: true}
+	second := domain.AutomationBackend{GoodsTypeID: gt.ID, PluginID: "auto", InstanceID: "b", Weight: 1, Enabled: true}
+	for _, b := range []*domain.AutomationBackend{&mismatched, &first, &second} {
+		if err := goodsTypes.AddBackend(ctx, b); err != nil {
+			t.Fatalf("add backend: %v", err)
+		}
+	}
+
+	order := domain.Order{UserID: user.ID, OrderNo: "ORD-PROV-POOL", Status: domain.OrderStatusPendingPayment, TotalAmount: 1000, Currency: "CNY"}
+	if err := repo.CreateOrder(ctx, &order); err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	item := domain.OrderItem{OrderID: order.ID, PackageID: seed.Package.ID, SystemID: seed.SystemImage.ID, Amount: 1000, Status: domain.OrderItemStatusPendingPayment, Action: "create", SpecJSON: "{}"}
+	if err := repo.CreateOrderItems(ctx, []domain.OrderItem{item}); err != nil {
+		t.Fatalf("create item: %v", err)
+	}
+	svc := apporder.NewService(repo, repo, repo, repo, repo, repo, repo, repo, repo, nil, autoResolver, nil, repo, repo, nil, repo, repo, repo, nil, nil, nil)
+	svc.SetBackendPlacer(goodsTypes)
+	if err := svc.ApproveOrder(ctx, 1, order.ID); err != nil {
+		t.Fatalf("approve order: %v", err)
+	}
+
+	var inst domain.VPSInstance
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		items, err := repo.ListOrderItems(ctx, order.ID)
+		if err == nil && len(items) > 0 {
+			if got, err := repo.GetInstanceByOrderItem(ctx, items[0].ID); err == nil && got.ID > 0 {
+				inst = got
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if inst.ID == 0 {
+		t.Fatalf("expected vps instance created")
+	}
+	if inst.BackendPluginID != "auto" || inst.BackendInstanceID != "b" {
+		t.Fatalf("expected host placed on fallback backend, got %s/%s", inst.BackendPluginID, inst.BackendInstanceID)
+	}
+	if len(broken.CreateHostRequests) != 1 || len(healthy.CreateHostRequests) != 1 {
+		t.Fatalf("expected one attempt per backend, got %d/%d", len(broken.CreateHostRequests), len(healthy.CreateHostRequests))
+	}
+	if len(foreign.CreateHostRequests) != 0 {
+		t.Fatalf("expected backend without the ordered line to be skipped, got %d requests", len(foreign.CreateHostRequests))
+	}
+	failed, err := repo.GetAutomationBackend(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("get backend: %v", err)
+	}
+	if failed.Health != domain.AutomationBackendDegraded || failed.ConsecutiveFailures != 1 {
+		t.Fatalf("expected failing backend degraded, got %+v", failed)
+	}
+}
+
+type guestInitFeatures map[string]bool
+
+func (f guestInitFeatures) AutomationFeatureSupported(ctx context.Context, pluginID, instanceID, feature string) bool {
+	return feature == "cloud_init" && f[pluginID+"/"+instanceID]
+}
+
+func TestOrderService_GuestInitCheckoutAndProvision(t *testing.T) {
+	_, repo := testutil.NewTestDB(t, false)
+	seed := testutil.SeedCatalog(t, repo)
+	user := testutil.CreateUser(t, repo, "prov4", "prov4@example.com", "pass")
+	other := testutil.CreateUser(t, repo, "prov5", "prov5@example.com", "pass")
+	ctx := context.Background()
+
+	gt := domain.GoodsType{Name: "Keyed", Active: true, AutomationCategory: "automation", AutomationPluginID: "auto", AutomationInstanceID: "a"}
+	if err := repo.CreateGoodsType(ctx, &gt); err != nil {
+		t.Fatalf("create goods type: %v", err)
+	}
+	seed.Package.GoodsTypeID = gt.ID
+	if err := repo.UpdatePackage(ctx, seed.Package); err != nil {
+		t.Fatalf("update package: %v", err)
+	}
+	legacy := &testutil.FakeAutomationClient{}
+	modern := &testutil.FakeAutomationClient{
+		CreateHostResult: appshared.AutomationCreateHostResult{HostID: 3001},
+		HostInfo: map[int64]appshared.AutomationHostInfo{
+			3001: {HostID: 3001, HostName: "keyed", State: 2},
+		},
+		Lines:  []appshared.AutomationLine{{ID: seed.PlanGroup.LineID}},
+		Images: []appshared.AutomationImage{{ImageID: 1, Name: seed.SystemImage.Name}},
+	}
+	autoResolver := &testutil.FakeAutomationResolver{Backends: map[string]appshared.AutomationClient{
+		"auto/a": legacy,
+		"auto/b": modern,
+	}}
+	goodsTypes := appgoodstype.NewService(repo, nil)
+	goodsTypes.SetBackendPool(repo, autoResolver)
+	for _, b := range []domain.AutomationBackend{
+		{GoodsTypeID: gt.ID, PluginID: "auto", InstanceID: "a", Weight: 5, Enabled: true},
+		{GoodsTypeID: gt.ID, PluginID: "auto", InstanceID: "b", Weight: 1, Enabled: true},
+	} {
+		backend := b
+		if err := goodsTypes.AddBackend(ctx, &backend); err != nil {
+			t.Fatalf("add backend: %v", err)
+		}
+	}
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	sshPub, _ := ssh.NewPublicKey(pub)
+	key, err := appsshkey.NewService(repo).Create(ctx, user.ID, "laptop", string(ssh.MarshalAuthorizedKey(sshPub)))
+	if err != nil {
+		t.Fatalf("create ssh key: %v", err)
+	}
+
+	svc := apporder.NewService(repo, repo, repo, repo, repo, repo, repo, repo, repo, nil, autoResolver, nil, repo, repo, nil, repo, repo, repo, nil, nil, nil)
+	svc.SetBackendPlacer(goodsTypes)
+	svc.SetGuestInit(repo, guestInitFeatures{})
+	spec := appshared.CartSpec{SSHKeyIDs: []int64{key.ID}, UserData: "#cloud-config\n", KeyOnly: true}
+	input := []appshared.OrderItemInput{{PackageID: seed.Package.ID, SystemID: seed.SystemImage.ID, Spec: spec, Qty: 1}}
+	if _, _, err := svc.CreateOrderFromItems(ctx, user.ID, "CNY", input, "", ""); !errors.Is(err, domain.ErrGuestInitNotSupported) {
+		t.Fatalf("expected unsupported pool rejected, got %v", err)
+	}
+	svc.SetGuestInit(repo, guestInitFeatures{"auto/b": true})
+	if _, _, err := svc.CreateOrderFromItems(ctx, other.ID, "CNY", input, "", ""); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected foreign key rejected, got %v", err)
+	}
+	order, _, err := svc.CreateOrderFromItems(ctx, user.ID, "CNY", input, "", "")
+	if err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	if err := svc.ApproveOrder(ctx, 1, order.ID); err != nil {
+		t.Fatalf("approve order: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) && len(modern.CreateHostRequests) == 0 {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if len(legacy.CreateHostRequests) != 0 || len(modern.CreateHostRequests) != 1 {
+		t.Fatalf("expected only the cloud_init backend used, got %d/%d", len(legacy.CreateHostRequests), len(modern.CreateHostRequests))
+	}
+	req := modern.CreateHostRequests[0]
+	if req.SysPwd != "" || !req.Init.DisablePassword || len(req.Init.SSHKeys) != 1 || req.Init.SSHKeys[0] != key.PublicKey || req.Init.UserData != "#cloud-config\n" {
+		t.Fatalf("unexpected guest init request: %+v", req)
+	}
+	var stored appshared.CartSpec
+	var specJSON string
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		items, err := repo.ListOrderItems(ctx, order.ID)
+		if err != nil || len(items) != 1 {
+			t.Fatalf("list items: %v", err)
+		}
+		specJSON = items[0].SpecJSON
+		stored = appshared.CartSpec{}
+		if err := json.Unmarshal([]byte(specJSON), &stored); err != nil {
+			t.Fatalf("decode spec: %v", err)
+		}
+		if stored.UserData == "" {
+			break
+		}
+	}
+	if stored.UserData != "" || len(stored.SSHKeyIDs) != 1 || !stored.KeyOnly {
+		t.Fatalf("expected user data dropped from the provisioned item, got %s", specJSON)
+	}
+}
+
+func TestOrderService_ExtraIPCheckoutAndAddOn(t *testing.T) {
+	ctx := context.Background()
+	_, repo := testutil.NewTestDB(t, false)
+	seed := testutil.SeedCatalog(t, repo)
+	user := testutil.CreateUser(t, repo, "ipbuyer", "ipbuyer@example.com", "pass")
+	plan := seed.PlanGroup
+	plan.UnitIPv4 = 300
+	plan.AddIPv4Max = 2
+	if err := repo.UpdatePlanGroup(ctx, plan); err != nil {
+		t.Fatalf("update plan group: %v", err)
+	}
+
+	fakeAuto := &testutil.FakeAutomationClient{
+		CreateHostResult: appshared.AutomationCreateHostResult{HostID: 1001},
+		HostInfo: map[int64]appshared.AutomationHostInfo{
+			1001: {HostID: 1001, HostName: "host", State: 2, RemoteIP: "1.1.1.1"},
+		},
+	}
+	svc := apporder.NewService(repo, repo, repo, repo, repo, repo, repo, repo, repo, nil, &testutil.FakeAutomationResolver{Client: fakeAuto}, nil, repo, repo, nil, repo, repo, repo, nil, nil, nil)
+	svc.SetExtraIPs(repo)
+
+	over := []appshared.OrderItemInput{{PackageID: seed.Package.ID, SystemID: seed.SystemImage.ID, Spec: appshared.CartSpec{AddIPv4: 3}, Qty: 1}}
+	if _, _, err := svc.CreateOrderFromItems(ctx, user.ID, "CNY", over, "", ""); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("expected ip limit rejected, got %v", err)
+	}
+	input := []appshared.OrderItemInput{{PackageID: seed.Package.ID, SystemID: seed.SystemImage.ID, Spec: appshared.CartSpec{AddIPv4: 1}, Qty: 1}}
+	order, _, err := svc.CreateOrderFromItems(ctx, user.ID, "CNY", input, "", "")
+	if err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	if order.TotalAmount != seed.Package.Monthly+300 {
+		t.Fatalf("expected ip priced into order, got %d", order.TotalAmount)
+	}
+	if err := svc.ApproveOrder(ctx, 1, order.ID); err != nil {
+		t.Fatalf("approve order: %v", err)
+	}
+
+	waitForIPs := func(vpsID int64, want int) []domain.VPSExtraIP {
+		deadline := time.Now().Add(2 * time.Second)
+		var ips []domain.VPSExtraIP
+		for time.Now().Before(deadline) {
+			ips, _ = repo.ListVPSExtraIPs(ctx, vpsID)
+			if len(ips) >= want {
+				break
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		return ips
+	}
+	var inst domain.VPSInstance
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) && inst.ID == 0 {
+		if items, err := repo.ListOrderItems(ctx, order.ID); err == nil && len(items) > 0 {
+			inst, _ = repo.GetInstanceByOrderItem(ctx, items[0].ID)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if inst.ID == 0 {
+		t.Fatalf("expected vps instance created")
+	}
+	if ips := waitForIPs(inst.ID, 1); len(ips) != 1 || ips[0].Family != "ipv4" {
+		t.Fatalf("expected one ipv4 assigned at create, got %+v", ips)
+	}
+	if inst.MonthlyPrice != seed.Package.Monthly+300 {
+		t.Fatalf("expected ip in monthly price, got %d", inst.MonthlyPrice)
+	}
+
+	if _, err := svc.QuoteExtraIPs(ctx, user.ID, inst.ID, 2, 0); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("expected add-on over limit rejected, got %v", err)
+	}
+	addOrder, quote, err := svc.CreateExtraIPOrder(ctx, user.ID, inst.ID, 1, 0)
+	if err != nil {
+		t.Fatalf("create extra ip order: %v", err)
+	}
+	if quote.MonthlyDelta != 300 || quote.ChargeAmount <= 0 || quote.ChargeAmount > 300 || addOrder.TotalAmount != quote.ChargeAmount {
+		t.Fatalf("expected prorated charge, got %+v total=%d", quote, addOrder.TotalAmount)
+	}
+	if err := svc.ApproveOrder(ctx, 1, addOrder.ID); err != nil {
+		t.Fatalf("approve extra ip order: %v", err)
+	}
+	if ips := waitForIPs(inst.ID, 2); len(ips) != 2 {
+		t.Fatalf("expected second ip assigned, got %+v", ips)
+	}
+	deadline = time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if inst, _ = repo.GetInstance(ctx, inst.ID); inst.MonthlyPrice == seed.Package.Monthly+600 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if inst.MonthlyPrice != seed.Package.Monthly+600 {
+		t.Fatalf("expected renewal price to include both ips, got %d", inst.MonthlyPrice)
+	}
+}
+
+func TestOrderService_ProvisionFallsBackToSiblingLineThenRefunds(t *testing.T) {
+	_, repo := testutil.NewTestDB(t, false)
+	seed := testutil.SeedCatalog(t, repo)
+	user := testutil.CreateUser(t, repo, "prov4", "prov4@example.com", "pass")
+	ctx := context.Background()
+
+	sibling := seed.PlanGroup
+	sibling.ID = 0
+	sibling.Name = "Plan B"
+	sibling.LineID = 2
+	if err := repo.CreatePlanGroup(ctx, &sibling); err != nil {
+		t.Fatalf("create sibling plan group: %v", err)
+	}
+	policy := `{"` + strconv.FormatInt(seed.PlanGroup.ID, 10) + `":{"error_patterns":["out of stock"],"sibling_lines":true,"auto_refund":true}}`
+	if err := repo.UpsertSetting(ctx, domain.Setting{Key: appshared.PlanGroupProvisionFallbackSettingKey, ValueJSON: policy}); err != nil {
+		t.Fatalf("save policy: %v", err)
+	}
+
+	newOrder := func(no string) domain.Order {
+		order := domain.Order{UserID: user.ID, OrderNo: no, Status: domain.OrderStatusPendingPayment, TotalAmount: 1000, Currency: "CNY"}
+		if err := repo.CreateOrder(ctx, &order); err != nil {
+			t.Fatalf("create order: %v", err)
+		}
+		item := domain.OrderItem{OrderID: order.ID, PackageID: seed.Package.ID, SystemID: seed.SystemImage.ID, Amount: 1000, Status: domain.OrderItemStatusPendingPayment, Action: "create", SpecJSON: "{}"}
+		if err := repo.CreateOrderItems(ctx, []domain.OrderItem{item}); err != nil {
+			t.Fatalf("create item: %v", err)
+		}
+		return order
+	}
+	waitOrder := func(orderID int64, status domain.OrderStatus) {
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			if got, err := repo.GetOrder(ctx, orderID); err == nil && got.Status == status {
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		t.Fatalf("expected order %d to reach %s", orderID, status)
+	}
+
+	fakeAuto := &testutil.FakeAutomationClient{
+		CreateHostResult:    appshared.AutomationCreateHostResult{HostID: 1001},
+		CreateHostErrByLine: map[int64]error{1: errors.New("line out of stock")},
+		HostInfo: map[int64]appshared.AutomationHostInfo{
+			1001: {HostID: 1001, HostName: "host", State: 2},
+		},
+	}
+	autoResolver := &testutil.FakeAutomationResolver{Client: fakeAuto}
+	svc := apporder.NewService(repo, repo, repo, repo, repo, repo, repo, repo, repo, nil, autoResolver, nil, repo, repo, nil, repo, repo, repo, nil, nil, nil)
+	svc.SetProvisionAttempts(repo)
+
+	moved := newOrder("ORD-PROV-SIBLING")
+	if err := svc.ApproveOrder(ctx, 1, moved.ID); err != nil {
+		t.Fatalf("approve order: %v", err)
+	}
+	waitOrder(moved.ID, domain.OrderStatusActive)
+	items, _ := repo.ListOrderItems(ctx, moved.ID)
+	inst, err := repo.GetInstanceByOrderItem(ctx, items[0].ID)
+	if err != nil || inst.LineID != 2 {
+		t.Fatalf("expected instance on sibling line, got %+v err=%v", inst, err)
+	}
+	attempts, total, err := svc.ProvisionAttempts(ctx, appshared.ProvisionAttemptFilter{OrderID: moved.ID})
+	if err != nil || total != 2 {
+		t.Fatalf("expected two attempts, got %d err=%v", total, err)
+	}
+	if attempts[0].Strategy != domain.ProvisionAttemptSiblingLine || !attempts[0].Success || attempts[1].Success {
+		t.Fatalf("unexpected attempts: %+v", attempts)
+	}
+
+	fakeAuto.CreateHostErrByLine[2] = errors.New("line out of stock")
+	refunded := newOrder("ORD-PROV-REFUND")
+	if err := svc.ApproveOrder(ctx, 1, refunded.ID); err != nil {
+		t.Fatalf("approve order: %v", err)
+	}
+	waitOrder(refunded.ID, domain.OrderStatusCanceled)
+	wallet, err := repo.GetWallet(ctx, user.ID)
+	if err != nil || wallet.Balance != 1000 {
+		t.Fatalf("expected refund to wallet, got %+v err=%v", wallet, err)
+	}
+	attempts, _, _ = svc.ProvisionAttempts(ctx, appshared.ProvisionAttemptFilter{OrderID: refunded.ID})
+	if len(attempts) != 3 || attempts[0].Strategy != domain.ProvisionAttemptRefund || !attempts[0].Success {
+		t.Fatalf("unexpected attempts: %+v", attempts)
+	}
+}
+
+func TestOrderService_ProvisionFallbackPackageIsRecordedOnItem(t *testing.T) {
+	_, repo := testutil.NewTestDB(t, false)
+	seed := testutil.SeedCatalog(t, repo)
+	user := testutil.CreateUser(t, repo, "prov6", "prov6@example.com", "pass")
+	ctx := context.Background()
+
+	other := seed.PlanGroup
+	other.ID = 0
+	other.Name = "Plan C"
+	other.LineID = 3
+	if err := repo.CreatePlanGroup(ctx, &other); err != nil {
+		t.Fatalf("create plan group: %v", err)
+	}
+	fallback := seed.Package
+	fallback.ID = 0
+	fallback.PlanGroupID = other.ID
+	fallback.Name = "Basic C"
+	fallback.Monthly = 4
+	fallback.CapacityRemaining = 3
+	if err := repo.CreatePackage(ctx, &fallback); err != nil {
+		t.Fatalf("create package: %v", err)
+	}
+	policy := `{"` + strconv.FormatInt(seed.PlanGroup.ID, 10) + `":{"error_patterns":["out of stock"],"fallback_package_ids":[` + strconv.FormatInt(fallback.ID, 10) + `]}}`
+	if err := repo.UpsertSetting(ctx, domain.Setting{Key: appshared.PlanGroupProvisionFallbackSettingKey, ValueJSON: policy}); err != nil {
+		t.Fatalf("save policy: %v", err)
+	}
+
+	order := domain.Order{UserID: user.ID, OrderNo: "ORD-PROV-PKG", Status: domain.OrderStatusPendingPayment, TotalAmount: 1000, Currency: "CNY"}
+	if err := repo.CreateOrder(ctx, &order); err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	item := domain.OrderItem{OrderID: order.ID, PackageID: seed.Package.ID, SystemID: seed.SystemImage.ID, Amount: 1000, DurationMonths: 1, Status: domain.OrderItemStatusPendingPayment, Action: "create", SpecJSON: "{}"}
+	if err := repo.CreateOrderItems(ctx, []domain.OrderItem{item}); err != nil {
+		t.Fatalf("create item: %v", err)
+	}
+	fakeAuto := &testutil.FakeAutomationClient{
+		CreateHostResult:    appshared.AutomationCreateHostResult{HostID: 1002},
+		CreateHostErrByLine: map[int64]error{1: errors.New("line out of stock")},
+		HostInfo: map[int64]appshared.AutomationHostInfo{
+			1002: {HostID: 1002, HostName: "host", State: 2},
+		},
+	}
+	svc := apporder.NewService(repo, repo, repo, repo, repo, repo, repo, repo, repo, nil, &testutil.FakeAutomationResolver{Client: fakeAuto}, nil, repo, repo, nil, repo, repo, repo, nil, nil, nil)
+	if err := svc.ApproveOrder(ctx, 1, order.ID); err != nil {
+		t.Fatalf("approve order: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if got, err := repo.GetOrder(ctx, order.ID); err == nil && got.Status == domain.OrderStatusActive {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	items, err := repo.ListOrderItems(ctx, order.ID)
+	if err != nil || len(items) != 1 {
+		t.Fatalf("list items: %v", err)
+	}
+	if items[0].PackageID != fallback.ID || items[0].Amount != 994 {
+		t.Fatalf("expected item moved to the fallback package with the difference taken off, got package %d amount %d", items[0].PackageID, items[0].Amount)
+	}
+	inst, err := repo.GetInstanceByOrderItem(ctx, items[0].ID)
+	if err != nil || inst.PackageID != fallback.ID {
+		t.Fatalf("expected instance on fallback package, got %+v err=%v", inst, err)
+	}
+	wallet, err := repo.GetWallet(ctx, user.ID)
+	if err != nil || wallet.Balance != 6 {
+		t.Fatalf("expected price difference refunded, got %+v err=%v", wallet, err)
+	}
+	pkg, err := repo.GetPackage(ctx, fallback.ID)
+	if err != nil || pkg.CapacityRemaining != 2 {
+		t.Fatalf("expected one unit of fallback capacity taken, got %+v err=%v", pkg, err)
+	}
+	primary, _ := repo.GetPackage(ctx, seed.Package.ID)
+	if primary.CapacityRemaining != -1 {
+		t.Fatalf("expected unlimited primary package untouched, got %d", primary.CapacityRemaining)
+	}
+}
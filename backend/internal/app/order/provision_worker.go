@@ -89,7 +89,12 @@ func (s *OrderService) handleProvisionJob(ctx context.Context, job domain.Provis
 		s.finishProvisionJob(ctx, job, "order stopped")
 		return
 	}
-	cli, err := s.client(jobCtx, item.GoodsTypeID)
+	var cli AutomationClient
+	if inst, instErr := s.vps.GetInstanceByOrderItem(jobCtx, item.ID); instErr == nil {
+		cli, err = s.instanceClient(jobCtx, inst)
+	} else {
+		cli, err = s.client(jobCtx, item.GoodsTypeID)
+	}
 	if err != nil {
 		s.retryProvisionJob(ctx, job, err.Error())
 		return
@@ -250,6 +255,10 @@ func (s *OrderService) completeProvision(ctx context.Context, job domain.Provisi
 			return err
 		}
 	}
+	if inst, err := s.vps.GetInstanceByOrderItem(ctx, item.ID); err == nil {
+		s.provisionExtraIPs(ctx, order.ID, item.ID, inst)
+		s.applyFirewallDefaults(ctx, order.ID, item.ID, inst)
+	}
 	_ = s.items.UpdateOrderItemStatus(ctx, item.ID, domain.OrderItemStatusActive)
 	_ = s.items.UpdateOrderItemAutomation(ctx, item.ID, fmt.Sprintf("%d", effectiveHostID))
 	if s.events != nil {
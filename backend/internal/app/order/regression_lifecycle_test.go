@@ -226,6 +226,14 @@ func (f *fakeLifecycleOrderItemRepo) UpdateOrderItemStatus(ctx context.Context,
 func (f *fakeLifecycleOrderItemRepo) UpdateOrderItemAutomation(ctx context.Context, id int64, automationID string) error {
 	return nil
 }
+
+func (f *fakeLifecycleOrderItemRepo) UpdateOrderItemSpec(ctx context.Context, id int64, specJSON string) error {
+	return nil
+}
+
+func (f *fakeLifecycleOrderItemRepo) SwitchOrderItemPackage(ctx context.Context, id, packageID, amount int64) error {
+	return nil
+}
 func (f *fakeLifecycleOrderItemRepo) HasPendingRenewOrder(ctx context.Context, userID, vpsID int64) (bool, error) {
 	return f.pendingRenew, nil
 }
@@ -289,6 +297,14 @@ func (f *fakeLifecycleAutomationClient) ClientForGoodsType(ctx context.Context,
 	return f, nil
 }
 
+func (f *fakeLifecycleAutomationClient) ClientForBackend(ctx context.Context, pluginID, instanceID string) (AutomationClient, error) {
+	return f, nil
+}
+
+func (f *fakeLifecycleAutomationClient) ClientForInstance(ctx context.Context, inst domain.VPSInstance) (AutomationClient, error) {
+	return f, nil
+}
+
 func (f *fakeLifecycleAutomationClient) CreateHost(ctx context.Context, req AutomationCreateHostRequest) (AutomationCreateHostResult, error) {
 	return AutomationCreateHostResult{}, nil
 }
@@ -328,7 +344,7 @@ func (f *fakeLifecycleAutomationClient) ShutdownHost(ctx context.Context, hostID
 func (f *fakeLifecycleAutomationClient) RebootHost(ctx context.Context, hostID int64) error {
 	return nil
 }
-func (f *fakeLifecycleAutomationClient) ResetOS(ctx context.Context, hostID int64, templateID int64, password string) error {
+func (f *fakeLifecycleAutomationClient) ResetOS(ctx context.Context, hostID int64, templateID int64, password string, guest AutomationGuestInit) error {
 	return nil
 }
 func (f *fakeLifecycleAutomationClient) ResetOSPassword(ctx context.Context, hostID int64, password string) error {
@@ -367,6 +383,30 @@ func (f *fakeLifecycleAutomationClient) AddFirewallRule(ctx context.Context, req
 func (f *fakeLifecycleAutomationClient) DeleteFirewallRule(ctx context.Context, hostID int64, ruleID int64) error {
 	return nil
 }
+func (f *fakeLifecycleAutomationClient) ListReverseDNS(ctx context.Context, hostID int64) ([]AutomationReverseDNSRecord, error) {
+	return nil, nil
+}
+func (f *fakeLifecycleAutomationClient) SetReverseDNS(ctx context.Context, hostID int64, ip, ptr string) error {
+	return nil
+}
+func (f *fakeLifecycleAutomationClient) AssignIP(ctx context.Context, hostID int64, family string) (string, error) {
+	return "", nil
+}
+func (f *fakeLifecycleAutomationClient) ReleaseIP(ctx context.Context, hostID int64, ip string) error {
+	return nil
+}
+func (f *fakeLifecycleAutomationClient) EnterRescue(ctx context.Context, hostID int64, password string) (AutomationRescueCredentials, error) {
+	return AutomationRescueCredentials{}, nil
+}
+func (f *fakeLifecycleAutomationClient) ExitRescue(ctx context.Context, hostID int64) error {
+	return nil
+}
+func (f *fakeLifecycleAutomationClient) MountISO(ctx context.Context, hostID int64, iso string) error {
+	return nil
+}
+func (f *fakeLifecycleAutomationClient) UnmountISO(ctx context.Context, hostID int64) error {
+	return nil
+}
 func (f *fakeLifecycleAutomationClient) ListPortMappings(ctx context.Context, hostID int64) ([]AutomationPortMapping, error) {
 	return nil, nil
 }
@@ -262,6 +262,14 @@ func (f *fakeResizeOrderItemRepo) UpdateOrderItemStatus(ctx context.Context, id
 func (f *fakeResizeOrderItemRepo) UpdateOrderItemAutomation(ctx context.Context, id int64, automationID string) error {
 	return nil
 }
+
+func (f *fakeResizeOrderItemRepo) UpdateOrderItemSpec(ctx context.Context, id int64, specJSON string) error {
+	return nil
+}
+
+func (f *fakeResizeOrderItemRepo) SwitchOrderItemPackage(ctx context.Context, id, packageID, amount int64) error {
+	return nil
+}
 func (f *fakeResizeOrderItemRepo) HasPendingRenewOrder(ctx context.Context, userID, vpsID int64) (bool, error) {
 	return false, nil
 }
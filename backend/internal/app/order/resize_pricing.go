@@ -59,7 +59,8 @@ func (s *OrderService) quoteResize(ctx context.Context, inst domain.VPSInstance,
 		int64(currentSpec.AddMemGB)*currentUnitMem +
 		int64(currentSpec.AddDiskGB)*currentUnitDisk +
 		int64(currentSpec.AddBWMbps)*currentUnitBW
-	currentMonthly := currentBase + currentAddon
+	ipMonthly := currentSpec.ExtraIPMonthly(plan)
+	currentMonthly := currentBase + currentAddon + ipMonthly
 
 	quote := ResizeQuote{
 		RefundToWallet: policy.RefundToWallet,
@@ -92,6 +93,9 @@ func (s *OrderService) quoteResize(ctx context.Context, inst domain.VPSInstance,
 	if spec != nil {
 		targetSpec = *spec
 	}
+	// Extra IPs are ordered separately and carry over unchanged.
+	targetSpec.AddIPv4 = currentSpec.AddIPv4
+	targetSpec.AddIPv6 = currentSpec.AddIPv6
 
 	if err := normalizeCartSpec(&targetSpec); err != nil {
 		return ResizeQuote{}, CartSpec{}, err
@@ -122,7 +126,7 @@ func (s *OrderService) quoteResize(ctx context.Context, inst domain.VPSInstance,
 		int64(targetSpec.AddMemGB)*targetUnitMem +
 		int64(targetSpec.AddDiskGB)*targetUnitDisk +
 		int64(targetSpec.AddBWMbps)*targetUnitBW
-	targetMonthly := targetBase + targetAddon
+	targetMonthly := targetBase + targetAddon + ipMonthly
 	quote.TargetCPU = targetPkg.Cores + targetSpec.AddCores
 	quote.TargetMemGB = targetPkg.MemoryGB + targetSpec.AddMemGB
 	quote.TargetDiskGB = targetPkg.DiskGB + targetSpec.AddDiskGB
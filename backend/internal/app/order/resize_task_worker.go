@@ -1,6 +1,15 @@
 package order
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"xiaoheiplay/internal/domain"
+)
+
+// resizeBusyRetryDelay is how long a paid resize waits before retrying when
+// another operation is running on the instance.
+const resizeBusyRetryDelay = time.Minute
 
 func (s *OrderService) ProcessResizeTasks(ctx context.Context, limit int) error {
 	if s.resizeTasks == nil {
@@ -21,3 +30,42 @@ func (s *OrderService) ProcessResizeTasks(ctx context.Context, limit int) error
 	}
 	return firstErr
 }
+
+// requeueResizeTask puts a task back in the queue because the instance is
+// busy with another operation. The item stays approved until it runs.
+func (s *OrderService) requeueResizeTask(ctx context.Context, task domain.ResizeTask, item domain.OrderItem) error {
+	retryAt := time.Now().Add(resizeBusyRetryDelay)
+	task.Status = domain.ResizeTaskStatusPending
+	task.ScheduledAt = &retryAt
+	task.StartedAt = nil
+	if err := s.resizeTasks.UpdateResizeTask(ctx, task); err != nil {
+		return err
+	}
+	_ = s.items.UpdateOrderItemStatus(ctx, item.ID, domain.OrderItemStatusApproved)
+	return nil
+}
+
+// queueResizeRetry hands a paid resize that hit a running operation to the
+// resize task queue when it was applied directly by provisioning.
+func (s *OrderService) queueResizeRetry(ctx context.Context, orderID int64, item domain.OrderItem) error {
+	if s.resizeTasks == nil {
+		return ErrInvalidInput
+	}
+	vpsID := parseOrderItemVPSID(item.SpecJSON)
+	if vpsID <= 0 {
+		return ErrInvalidInput
+	}
+	retryAt := time.Now().Add(resizeBusyRetryDelay)
+	task := &domain.ResizeTask{
+		VPSID:       vpsID,
+		OrderID:     orderID,
+		OrderItemID: item.ID,
+		Status:      domain.ResizeTaskStatusPending,
+		ScheduledAt: &retryAt,
+	}
+	if err := s.resizeTasks.CreateResizeTask(ctx, task); err != nil {
+		return err
+	}
+	_ = s.items.UpdateOrderItemStatus(ctx, item.ID, domain.OrderItemStatusApproved)
+	return nil
+}
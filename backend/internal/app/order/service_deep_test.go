@@ -7,6 +7,7 @@ import (
 	apporder "xiaoheiplay/internal/app/order"
 	appports "xiaoheiplay/internal/app/ports"
 	appshared "xiaoheiplay/internal/app/shared"
+	appvpsoperation "xiaoheiplay/internal/app/vpsoperation"
 	"xiaoheiplay/internal/domain"
 	"xiaoheiplay/internal/testutil"
 )
@@ -369,3 +370,84 @@ func TestOrderService_CreateResizeOrder_NegativeAmountAutoApproves(t *testing.T)
 		t.Fatalf("expected auto-approved flow, got %s", updated.Status)
 	}
 }
+
+func TestOrderService_ResizeTaskWaitsForRunningOperation(t *testing.T) {
+	_, repo := testutil.NewTestDB(t, false)
+	seed := testutil.SeedCatalog(t, repo)
+	user := testutil.CreateUser(t, repo, "resizebusy", "resizebusy@example.com", "pass")
+	ctx := context.Background()
+
+	inst := domain.VPSInstance{
+		UserID:               user.ID,
+		AutomationInstanceID: "321",
+		GoodsTypeID:          seed.Package.GoodsTypeID,
+		Name:                 "vm-busy",
+		PackageID:            seed.Package.ID,
+		PackageName:          seed.Package.Name,
+		SpecJSON:             "{}",
+		Status:               domain.VPSStatusRunning,
+		ExpireAt:             timePtr(time.Now().Add(30 * 24 * time.Hour)),
+	}
+	if err := repo.CreateInstance(ctx, &inst); err != nil {
+		t.Fatalf("create instance: %v", err)
+	}
+	reinstall := domain.VPSOperation{VPSID: inst.ID, UserID: user.ID, Action: domain.VPSOperationResetOS, Status: domain.VPSOperationStatusRunning, ParamsJSON: "{}"}
+	if err := repo.CreateVPSOperation(ctx, &reinstall); err != nil {
+		t.Fatalf("create operation: %v", err)
+	}
+
+	order := domain.Order{UserID: user.ID, OrderNo: "UPG-BUSY", Status: domain.OrderStatusApproved, TotalAmount: 100, Currency: "CNY"}
+	if err := repo.CreateOrder(ctx, &order); err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	item := domain.OrderItem{
+		OrderID:  order.ID,
+		Amount:   100,
+		Status:   domain.OrderItemStatusApproved,
+		Action:   "resize",
+		SpecJSON: `{"vps_id":` + testutil.Itoa(inst.ID) + `,"target_cpu":2,"target_mem_gb":4,"target_disk_gb":40,"target_bw_mbps":50}`,
+	}
+	if err := repo.CreateOrderItems(ctx, []domain.OrderItem{item}); err != nil {
+		t.Fatalf("create item: %v", err)
+	}
+	items, _ := repo.ListOrderItems(ctx, order.ID)
+	task := domain.ResizeTask{VPSID: inst.ID, OrderID: order.ID, OrderItemID: items[0].ID, Status: domain.ResizeTaskStatusPending}
+	if err := repo.CreateResizeTask(ctx, &task); err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+
+	fakeAuto := &testutil.FakeAutomationClient{}
+	svc := apporder.NewService(repo, repo, repo, repo, repo, repo, repo, repo, repo, nil, &testutil.FakeAutomationResolver{Client: fakeAuto}, nil, repo, repo, nil, repo, repo, repo, repo, nil, nil)
+	svc.SetVPSOperationTracker(appvpsoperation.NewService(repo, repo, repo, nil, nil))
+
+	if err := svc.ProcessResizeTasks(ctx, 10); err != nil {
+		t.Fatalf("process while busy: %v", err)
+	}
+	queued, err := repo.GetResizeTask(ctx, task.ID)
+	if err != nil || queued.Status != domain.ResizeTaskStatusPending || queued.ScheduledAt == nil || !queued.ScheduledAt.After(time.Now()) {
+		t.Fatalf("expected the task to be requeued, got %+v %v", queued, err)
+	}
+	if got, _ := repo.GetOrderItem(ctx, items[0].ID); got.Status != domain.OrderItemStatusApproved {
+		t.Fatalf("expected the paid item to stay approved, got %s", got.Status)
+	}
+	if len(fakeAuto.ElasticUpdates) != 0 {
+		t.Fatalf("expected no elastic update while busy")
+	}
+
+	reinstall.Status = domain.VPSOperationStatusSucceeded
+	if err := repo.UpdateVPSOperation(ctx, reinstall); err != nil {
+		t.Fatalf("finish operation: %v", err)
+	}
+	due := time.Now().Add(-time.Minute)
+	queued.ScheduledAt = &due
+	if err := repo.UpdateResizeTask(ctx, queued); err != nil {
+		t.Fatalf("reschedule task: %v", err)
+	}
+	if err := svc.ProcessResizeTasks(ctx, 10); err != nil {
+		t.Fatalf("process: %v", err)
+	}
+	done, _ := repo.GetResizeTask(ctx, task.ID)
+	if done.Status != domain.ResizeTaskStatusDone || len(fakeAuto.ElasticUpdates) != 1 {
+		t.Fatalf("expected the resize to run once the operation finished, got %+v", done)
+	}
+}
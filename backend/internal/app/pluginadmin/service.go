@@ -28,18 +28,42 @@ type Manager interface {
 	UpdateConfigInstance(ctx context.Context, category, pluginID, instanceID string, configJSON string) error
 	CreateInstance(ctx context.Context, category, pluginID, instanceID string) (domain.PluginInstallation, error)
 	DeletePluginFiles(ctx context.Context, category, pluginID string) error
+	ListVersions(ctx context.Context, category, pluginID string) ([]domain.PluginVersion, error)
+	Upgrade(ctx context.Context, category, pluginID, filename string, r io.Reader, trustUnofficial bool) (domain.PluginVersion, error)
+	Rollback(ctx context.Context, category, pluginID, version string) (domain.PluginVersion, error)
+	RepositoryPlugins(ctx context.Context) ([]appshared.PluginRepositoryItem, error)
+	InstallFromRepository(ctx context.Context, category, pluginID, version string) (domain.PluginVersion, error)
 }
 
 type Service struct {
 	manager        Manager
 	paymentMethods appports.PluginPaymentMethodRepository
 	settings       appports.SettingsRepository
+	logs           appports.PluginLogRepository
 }
 
 func NewService(manager Manager, paymentMethods appports.PluginPaymentMethodRepository, settings appports.SettingsRepository) *Service {
 	return &Service{manager: manager, paymentMethods: paymentMethods, settings: settings}
 }
 
+func (s *Service) SetPluginLogs(repo appports.PluginLogRepository) {
+	s.logs = repo
+}
+
+// ListLogs returns the entries an instance wrote through HostService.Log.
+func (s *Service) ListLogs(ctx context.Context, filter appshared.PluginLogFilter) ([]domain.PluginLog, int, error) {
+	if s.logs == nil {
+		return nil, 0, domain.ErrPluginsDisabled
+	}
+	filter.Category = strings.TrimSpace(filter.Category)
+	filter.PluginID = strings.TrimSpace(filter.PluginID)
+	filter.InstanceID = strings.TrimSpace(filter.InstanceID)
+	if filter.Category == "" || filter.PluginID == "" || filter.InstanceID == "" {
+		return nil, 0, appshared.ErrInvalidInput
+	}
+	return s.logs.ListPluginLogs(ctx, filter)
+}
+
 func (s *Service) List(ctx context.Context) ([]appshared.PluginListItem, error) {
 	if s.manager == nil {
 		return nil, domain.ErrPluginsDisabled
@@ -47,6 +71,31 @@ func (s *Service) List(ctx context.Context) ([]appshared.PluginListItem, error)
 	return s.manager.List(ctx)
 }
 
+// AutomationFeatureSupported reports whether an automation plugin instance
+// declares feature in its manifest.
+func (s *Service) AutomationFeatureSupported(ctx context.Context, pluginID, instanceID, feature string) bool {
+	items, err := s.List(ctx)
+	if err != nil {
+		return false
+	}
+	feature = strings.ToLower(strings.TrimSpace(feature))
+	for _, it := range items {
+		if it.Category != "automation" || it.PluginID != pluginID || it.InstanceID != instanceID {
+			continue
+		}
+		if it.Capabilities.Capabilities.Automation == nil {
+			return false
+		}
+		for _, f := range it.Capabilities.Capabilities.Automation.Features {
+			if strings.ToLower(strings.TrimSpace(f)) == feature {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
 func (s *Service) DiscoverOnDisk(ctx context.Context) ([]appshared.PluginDiscoverItem, error) {
 	if s.manager == nil {
 		return nil, domain.ErrPluginsDisabled
@@ -138,6 +187,54 @@ func (s *Service) DeletePluginFiles(ctx context.Context, category, pluginID stri
 	return s.manager.DeletePluginFiles(ctx, category, pluginID)
 }
 
+// ListVersions returns the versions kept for a plugin, newest first.
+func (s *Service) ListVersions(ctx context.Context, category, pluginID string) ([]domain.PluginVersion, error) {
+	if s.manager == nil {
+		return nil, domain.ErrPluginsDisabled
+	}
+	return s.manager.ListVersions(ctx, category, pluginID)
+}
+
+// Upgrade switches an installed plugin to the package in r, rolling back
+// automatically when the new version fails validation or health checks.
+func (s *Service) Upgrade(ctx context.Context, category, pluginID, filename string, r io.Reader, trustUnofficial bool) (domain.PluginVersion, error) {
+	if s.manager == nil {
+		return domain.PluginVersion{}, domain.ErrPluginsDisabled
+	}
+	return s.manager.Upgrade(ctx, category, pluginID, filename, r, trustUnofficial)
+}
+
+// Rollback switches a plugin back to a previously installed version.
+func (s *Service) Rollback(ctx context.Context, category, pluginID, version string) (domain.PluginVersion, error) {
+	if s.manager == nil {
+		return domain.PluginVersion{}, domain.ErrPluginsDisabled
+	}
+	return s.manager.Rollback(ctx, category, pluginID, version)
+}
+
+// RepositoryPlugins lists the plugins offered by the configured repository
+// indexes, flagging the ones with an update.
+func (s *Service) RepositoryPlugins(ctx context.Context) ([]appshared.PluginRepositoryItem, error) {
+	if s.manager == nil {
+		return nil, domain.ErrPluginsDisabled
+	}
+	return s.manager.RepositoryPlugins(ctx)
+}
+
+// InstallFromRepository installs or upgrades a plugin from the repository
+// indexes. An empty version picks the newest one.
+func (s *Service) InstallFromRepository(ctx context.Context, category, pluginID, version string) (domain.PluginVersion, error) {
+	if s.manager == nil {
+		return domain.PluginVersion{}, domain.ErrPluginsDisabled
+	}
+	category = strings.TrimSpace(category)
+	pluginID = strings.TrimSpace(pluginID)
+	if category == "" || pluginID == "" {
+		return domain.PluginVersion{}, appshared.ErrInvalidInput
+	}
+	return s.manager.InstallFromRepository(ctx, category, pluginID, strings.TrimSpace(version))
+}
+
 func (s *Service) UpsertPaymentMethod(ctx context.Context, category, pluginID, instanceID, method string, enabled bool) error {
 	if s.paymentMethods == nil {
 		return domain.ErrPaymentMethodRepoMissing
@@ -0,0 +1,260 @@
+package pluginhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	appports "xiaoheiplay/internal/app/ports"
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+const (
+	defaultWorkers    = 4
+	maxErrorLen       = 2000
+	dispatchBatchSize = 50
+)
+
+// retryDelays is the wait before each retry; a delivery fails for good after
+// len(retryDelays)+1 attempts.
+var retryDelays = []time.Duration{
+	30 * time.Second,
+	2 * time.Minute,
+	10 * time.Minute,
+	30 * time.Minute,
+	2 * time.Hour,
+	6 * time.Hour,
+}
+
+type hookHost interface {
+	ListHookSubscribers(ctx context.Context, event string) ([]appshared.PluginHookSubscriber, error)
+	// DeliverHook calls HookService.OnEvent. ErrNotSupported means the plugin
+	// does not serve HookService and the delivery is not retried.
+	DeliverHook(ctx context.Context, delivery domain.PluginHookDelivery) error
+}
+
+// Service queues host events for the plugin instances subscribed to them and
+// delivers them at least once. Every delivery is persisted before it is
+// attempted, so pending ones survive a restart.
+type Service struct {
+	deliveries appports.PluginHookDeliveryRepository
+	host       hookHost
+
+	mu       sync.Mutex
+	inflight map[int64]struct{}
+	wake     chan struct{}
+	slots    chan struct{}
+}
+
+func NewService(deliveries appports.PluginHookDeliveryRepository, host hookHost) *Service {
+	return &Service{
+		deliveries: deliveries,
+		host:       host,
+		inflight:   make(map[int64]struct{}),
+		wake:       make(chan struct{}, 1),
+		slots:      make(chan struct{}, defaultWorkers),
+	}
+}
+
+// Emit queues event for every subscribed instance. Errors are dropped: a hook
+// must never fail the action that raised it.
+func (s *Service) Emit(ctx context.Context, event string, payload any) {
+	_ = s.emit(ctx, event, payload, time.Now())
+}
+
+func (s *Service) emit(ctx context.Context, event string, payload any, occurredAt time.Time) error {
+	if s == nil || s.deliveries == nil || s.host == nil || !appshared.IsPluginHookEvent(event) {
+		return nil
+	}
+	subscribers, err := s.host.ListHookSubscribers(ctx, event)
+	if err != nil || len(subscribers) == 0 {
+		return err
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	eventID := newEventID()
+	items := make([]domain.PluginHookDelivery, 0, len(subscribers))
+	for _, sub := range subscribers {
+		items = append(items, domain.PluginHookDelivery{
+			EventID:       eventID,
+			Category:      sub.Category,
+			PluginID:      sub.PluginID,
+			InstanceID:    sub.InstanceID,
+			Event:         event,
+			PayloadJSON:   string(raw),
+			Status:        domain.PluginHookDeliveryPending,
+			NextAttemptAt: occurredAt,
+			OccurredAt:    occurredAt,
+		})
+	}
+	if err := s.deliveries.CreatePluginHookDeliveries(ctx, items); err != nil {
+		return err
+	}
+	s.notify()
+	return nil
+}
+
+// NotifyOrderEvent maps order events published on the event bus to hooks.
+func (s *Service) NotifyOrderEvent(ctx context.Context, ev domain.OrderEvent) error {
+	event := ""
+	switch ev.Type {
+	case "order.pending_payment":
+		event = appshared.PluginHookOrderCreated
+	case "payment.confirmed", "payment.approved":
+		event = appshared.PluginHookOrderPaid
+	case "order.completed":
+		var data struct {
+			Status domain.OrderStatus `json:"status"`
+		}
+		_ = json.Unmarshal([]byte(ev.DataJSON), &data)
+		if data.Status == domain.OrderStatusActive {
+			event = appshared.PluginHookOrderActive
+		}
+	}
+	if event == "" {
+		return nil
+	}
+	data := json.RawMessage(ev.DataJSON)
+	if !json.Valid(data) {
+		data = json.RawMessage("{}")
+	}
+	occurredAt := ev.CreatedAt
+	if occurredAt.IsZero() {
+		occurredAt = time.Now()
+	}
+	return s.emit(ctx, event, map[string]any{"order_id": ev.OrderID, "data": data}, occurredAt)
+}
+
+func (s *Service) ListDeliveries(ctx context.Context, filter appshared.PluginHookDeliveryFilter) ([]domain.PluginHookDelivery, int, error) {
+	if s.deliveries == nil {
+		return nil, 0, domain.ErrPluginsDisabled
+	}
+	filter.Category = strings.TrimSpace(filter.Category)
+	filter.PluginID = strings.TrimSpace(filter.PluginID)
+	filter.InstanceID = strings.TrimSpace(filter.InstanceID)
+	if filter.Category == "" || filter.PluginID == "" || filter.InstanceID == "" {
+		return nil, 0, appshared.ErrInvalidInput
+	}
+	return s.deliveries.ListPluginHookDeliveries(ctx, filter)
+}
+
+// Retry schedules one more attempt of a failed delivery.
+func (s *Service) Retry(ctx context.Context, category, pluginID, instanceID string, id int64) (domain.PluginHookDelivery, error) {
+	if s.deliveries == nil {
+		return domain.PluginHookDelivery{}, domain.ErrPluginsDisabled
+	}
+	item, err := s.deliveries.GetPluginHookDelivery(ctx, id)
+	if err != nil {
+		return domain.PluginHookDelivery{}, err
+	}
+	if item.Category != category || item.PluginID != pluginID || item.InstanceID != instanceID {
+		return domain.PluginHookDelivery{}, appshared.ErrNotFound
+	}
+	if item.Status != domain.PluginHookDeliveryFailed {
+		return domain.PluginHookDelivery{}, appshared.ErrConflict
+	}
+	item.Status = domain.PluginHookDeliveryPending
+	item.NextAttemptAt = time.Now()
+	if err := s.deliveries.UpdatePluginHookDelivery(ctx, item); err != nil {
+		return domain.PluginHookDelivery{}, err
+	}
+	s.notify()
+	return item, nil
+}
+
+// Start drives the delivery worker until ctx is canceled.
+func (s *Service) Start(ctx context.Context) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+	for {
+		s.dispatch(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Service) dispatch(ctx context.Context) {
+	if s.deliveries == nil || s.host == nil {
+		return
+	}
+	items, err := s.deliveries.ListDuePluginHookDeliveries(ctx, time.Now(), dispatchBatchSize)
+	if err != nil {
+		return
+	}
+	for _, item := range items {
+		s.mu.Lock()
+		if _, ok := s.inflight[item.ID]; ok {
+			s.mu.Unlock()
+			continue
+		}
+		s.inflight[item.ID] = struct{}{}
+		s.mu.Unlock()
+
+		select {
+		case s.slots <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+		go func(item domain.PluginHookDelivery) {
+			defer func() {
+				<-s.slots
+				s.mu.Lock()
+				delete(s.inflight, item.ID)
+				s.mu.Unlock()
+			}()
+			s.deliver(ctx, item)
+		}(item)
+	}
+}
+
+func (s *Service) deliver(ctx context.Context, item domain.PluginHookDelivery) {
+	item.Attempts++
+	err := s.host.DeliverHook(ctx, item)
+	now := time.Now()
+	switch {
+	case err == nil:
+		item.Status = domain.PluginHookDeliveryDelivered
+		item.LastError = ""
+		item.DeliveredAt = &now
+	case errors.Is(err, appshared.ErrNotSupported) || item.Attempts > len(retryDelays):
+		item.Status = domain.PluginHookDeliveryFailed
+		item.LastError = trimError(err)
+	default:
+		item.LastError = trimError(err)
+		item.NextAttemptAt = now.Add(retryDelays[item.Attempts-1])
+	}
+	_ = s.deliveries.UpdatePluginHookDelivery(context.WithoutCancel(ctx), item)
+}
+
+func trimError(err error) string {
+	msg := strings.TrimSpace(err.Error())
+	if len(msg) > maxErrorLen {
+		msg = msg[:maxErrorLen]
+	}
+	return msg
+}
+
+func newEventID() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
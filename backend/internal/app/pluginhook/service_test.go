@@ -0,0 +1,162 @@
+package pluginhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"xiaoheiplay/internal/adapter/repo/core"
+	apppluginhook "xiaoheiplay/internal/app/pluginhook"
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+	"xiaoheiplay/internal/testutil"
+)
+
+type fakeHookHost struct {
+	mu          sync.Mutex
+	subscribers map[string][]appshared.PluginHookSubscriber
+	err         error
+	delivered   []domain.PluginHookDelivery
+}
+
+func (h *fakeHookHost) ListHookSubscribers(_ context.Context, event string) ([]appshared.PluginHookSubscriber, error) {
+	return h.subscribers[event], nil
+}
+
+func (h *fakeHookHost) DeliverHook(_ context.Context, delivery domain.PluginHookDelivery) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.delivered = append(h.delivered, delivery)
+	return h.err
+}
+
+func (h *fakeHookHost) calls() []domain.PluginHookDelivery {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]domain.PluginHookDelivery(nil), h.delivered...)
+}
+
+var crmInstance = appshared.PluginHookSubscriber{Category: "automation", PluginID: "crm", InstanceID: "default"}
+
+func listDeliveries(t *testing.T, svc *apppluginhook.Service) []domain.PluginHookDelivery {
+	t.Helper()
+	items, _, err := svc.ListDeliveries(context.Background(), appshared.PluginHookDeliveryFilter{
+		Category:   crmInstance.Category,
+		PluginID:   crmInstance.PluginID,
+		InstanceID: crmInstance.InstanceID,
+	})
+	if err != nil {
+		t.Fatalf("list deliveries: %v", err)
+	}
+	return items
+}
+
+func waitForStatus(t *testing.T, svc *apppluginhook.Service, want func(domain.PluginHookDelivery) bool) domain.PluginHookDelivery {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		if items := listDeliveries(t, svc); len(items) == 1 && want(items[0]) {
+			return items[0]
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("delivery did not reach the expected state: %+v", listDeliveries(t, svc))
+	return domain.PluginHookDelivery{}
+}
+
+func startService(t *testing.T, repo *repo.GormRepo, host *fakeHookHost) *apppluginhook.Service {
+	t.Helper()
+	svc := apppluginhook.NewService(repo, host)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go svc.Start(ctx)
+	return svc
+}
+
+func TestOrderEventsAreDeliveredToSubscribers(t *testing.T) {
+	_, repo := testutil.NewTestDB(t, false)
+	host := &fakeHookHost{subscribers: map[string][]appshared.PluginHookSubscriber{
+		appshared.PluginHookOrderPaid: {crmInstance},
+	}}
+	svc := startService(t, repo, host)
+	ctx := context.Background()
+
+	if err := svc.NotifyOrderEvent(ctx, domain.OrderEvent{OrderID: 9, Type: "order.completed", DataJSON: `{"status":"failed"}`}); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	if err := svc.NotifyOrderEvent(ctx, domain.OrderEvent{OrderID: 9, Type: "payment.confirmed", DataJSON: `{"method":"ezpay"}`}); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	got := waitForStatus(t, svc, func(d domain.PluginHookDelivery) bool { return d.Status == domain.PluginHookDeliveryDelivered })
+	if got.Event != appshared.PluginHookOrderPaid || got.Attempts != 1 || got.DeliveredAt == nil || got.EventID == "" {
+		t.Fatalf("unexpected delivery: %+v", got)
+	}
+	var payload struct {
+		OrderID int64 `json:"order_id"`
+		Data    struct {
+			Method string `json:"method"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(got.PayloadJSON), &payload); err != nil || payload.OrderID != 9 || payload.Data.Method != "ezpay" {
+		t.Fatalf("unexpected payload %s: %v", got.PayloadJSON, err)
+	}
+	if calls := host.calls(); len(calls) != 1 || calls[0].Attempts != 1 {
+		t.Fatalf("expected one delivery call, got %+v", calls)
+	}
+}
+
+func TestFailedDeliveriesAreRetried(t *testing.T) {
+	_, repo := testutil.NewTestDB(t, false)
+	host := &fakeHookHost{
+		subscribers: map[string][]appshared.PluginHookSubscriber{appshared.PluginHookTicketCreated: {crmInstance}},
+		err:         errors.New("crm unavailable"),
+	}
+	svc := startService(t, repo, host)
+	ctx := context.Background()
+
+	svc.Emit(ctx, appshared.PluginHookTicketCreated, map[string]any{"ticket_id": 1})
+	got := waitForStatus(t, svc, func(d domain.PluginHookDelivery) bool { return d.Attempts == 1 })
+	if got.Status != domain.PluginHookDeliveryPending || got.LastError != "crm unavailable" {
+		t.Fatalf("expected a scheduled retry, got %+v", got)
+	}
+	if wait := time.Until(got.NextAttemptAt); wait < 20*time.Second || wait > time.Minute {
+		t.Fatalf("unexpected retry delay %s", wait)
+	}
+	if _, err := svc.Retry(ctx, crmInstance.Category, crmInstance.PluginID, crmInstance.InstanceID, got.ID); err != appshared.ErrConflict {
+		t.Fatalf("expected pending delivery retry to conflict, got %v", err)
+	}
+}
+
+func TestUnsupportedDeliveriesFailAndCanBeRetried(t *testing.T) {
+	_, repo := testutil.NewTestDB(t, false)
+	host := &fakeHookHost{
+		subscribers: map[string][]appshared.PluginHookSubscriber{appshared.PluginHookUserRegistered: {crmInstance}},
+		err:         appshared.ErrNotSupported,
+	}
+	svc := startService(t, repo, host)
+	ctx := context.Background()
+
+	svc.Emit(ctx, appshared.PluginHookUserRegistered, map[string]any{"user_id": 1})
+	svc.Emit(ctx, appshared.PluginHookVPSExpired, map[string]any{"vps_id": 1})
+	failed := waitForStatus(t, svc, func(d domain.PluginHookDelivery) bool { return d.Status == domain.PluginHookDeliveryFailed })
+	if failed.Attempts != 1 {
+		t.Fatalf("unsupported deliveries should not be retried: %+v", failed)
+	}
+	if _, err := svc.Retry(ctx, crmInstance.Category, crmInstance.PluginID, "other", failed.ID); err != appshared.ErrNotFound {
+		t.Fatalf("expected not found for another instance, got %v", err)
+	}
+
+	host.mu.Lock()
+	host.err = nil
+	host.mu.Unlock()
+	if _, err := svc.Retry(ctx, crmInstance.Category, crmInstance.PluginID, crmInstance.InstanceID, failed.ID); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	got := waitForStatus(t, svc, func(d domain.PluginHookDelivery) bool { return d.Status == domain.PluginHookDeliveryDelivered })
+	if got.Attempts != 2 || got.LastError != "" {
+		t.Fatalf("unexpected delivery after retry: %+v", got)
+	}
+}
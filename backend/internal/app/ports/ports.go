@@ -59,6 +59,15 @@ type GoodsTypeRepository interface {
 	DeleteGoodsType(ctx context.Context, id int64) error
 }
 
+type AutomationBackendRepository interface {
+	ListAutomationBackends(ctx context.Context, goodsTypeID int64) ([]domain.AutomationBackend, error)
+	GetAutomationBackend(ctx context.Context, id int64) (domain.AutomationBackend, error)
+	CreateAutomationBackend(ctx context.Context, backend *domain.AutomationBackend) error
+	UpdateAutomationBackend(ctx context.Context, backend domain.AutomationBackend) error
+	UpdateAutomationBackendHealth(ctx context.Context, backend domain.AutomationBackend) error
+	DeleteAutomationBackend(ctx context.Context, id int64) error
+}
+
 type SystemImageRepository interface {
 	ListSystemImages(ctx context.Context, lineID int64) ([]domain.SystemImage, error)
 	ListAllSystemImages(ctx context.Context) ([]domain.SystemImage, error)
@@ -94,6 +103,10 @@ type OrderItemRepository interface {
 	GetOrderItem(ctx context.Context, id int64) (domain.OrderItem, error)
 	UpdateOrderItemStatus(ctx context.Context, id int64, status domain.OrderItemStatus) error
 	UpdateOrderItemAutomation(ctx context.Context, id int64, automationID string) error
+	UpdateOrderItemSpec(ctx context.Context, id int64, specJSON string) error
+	// SwitchOrderItemPackage moves an item to the package it was provisioned
+	// on and takes one unit of that package's tracked capacity.
+	SwitchOrderItemPackage(ctx context.Context, id, packageID, amount int64) error
 	HasPendingRenewOrder(ctx context.Context, userID, vpsID int64) (bool, error)
 	HasPendingResizeOrder(ctx context.Context, userID, vpsID int64) (bool, error)
 	HasPendingRefundOrder(ctx context.Context, userID, vpsID int64) (bool, error)
@@ -172,6 +185,93 @@ type UserAPIKeyRepository interface {
 	TouchUserAPIKey(ctx context.Context, id int64) error
 }
 
+type SSHKeyRepository interface {
+	ListSSHKeys(ctx context.Context, userID int64) ([]domain.SSHKey, error)
+	GetSSHKey(ctx context.Context, id int64) (domain.SSHKey, error)
+	CreateSSHKey(ctx context.Context, key *domain.SSHKey) error
+	DeleteSSHKey(ctx context.Context, userID, id int64) error
+}
+
+type ReverseDNSLogRepository interface {
+	AddReverseDNSChange(ctx context.Context, change *domain.ReverseDNSChange) error
+	ListReverseDNSChanges(ctx context.Context, vpsID int64, limit, offset int) ([]domain.ReverseDNSChange, int, error)
+}
+
+type VPSExtraIPRepository interface {
+	CreateVPSExtraIP(ctx context.Context, ip *domain.VPSExtraIP) error
+	ListVPSExtraIPs(ctx context.Context, vpsID int64) ([]domain.VPSExtraIP, error)
+	DeleteVPSExtraIP(ctx context.Context, id int64) error
+	FindVPSExtraIPByAddress(ctx context.Context, address string) (domain.VPSExtraIP, error)
+}
+
+type VPSRescueRepository interface {
+	CreateRescueSession(ctx context.Context, session *domain.VPSRescueSession) error
+	GetActiveRescueSession(ctx context.Context, vpsID int64) (domain.VPSRescueSession, error)
+	EndRescueSession(ctx context.Context, id int64, reason string, endedAt time.Time) error
+	ListExpiredRescueSessions(ctx context.Context, now time.Time, limit int) ([]domain.VPSRescueSession, error)
+	GetISOMount(ctx context.Context, vpsID int64) (domain.VPSISOMount, error)
+	SaveISOMount(ctx context.Context, mount domain.VPSISOMount) error
+	DeleteISOMount(ctx context.Context, vpsID int64) error
+}
+
+type VPSMetricRepository interface {
+	AccumulateVPSMetric(ctx context.Context, point domain.VPSMetricPoint) error
+	ListVPSMetrics(ctx context.Context, vpsID int64, resolution domain.MetricResolution, from, to time.Time) ([]domain.VPSMetricPoint, error)
+	PurgeVPSMetrics(ctx context.Context, resolution domain.MetricResolution, before time.Time) (int64, error)
+}
+
+type ConsoleSessionRepository interface {
+	CreateConsoleSession(ctx context.Context, session *domain.ConsoleSession) error
+	GetConsoleSession(ctx context.Context, id int64) (domain.ConsoleSession, error)
+	ActivateConsoleSession(ctx context.Context, id int64, upstreamHost string, startedAt time.Time) (bool, error)
+	EndConsoleSession(ctx context.Context, id int64, reason string, clientBytes, upstreamBytes int64, endedAt time.Time) error
+	CountOpenConsoleSessions(ctx context.Context, userID int64, now time.Time) (int, error)
+	ListConsoleSessions(ctx context.Context, filter appshared.ConsoleSessionFilter) ([]domain.ConsoleSession, int, error)
+	EndActiveConsoleSessions(ctx context.Context, reason string, endedAt time.Time) (int64, error)
+}
+
+type VPSActionUsageRepository interface {
+	RecordVPSActionUsage(ctx context.Context, vpsID int64, action string, at time.Time) error
+	CountVPSActionUsage(ctx context.Context, vpsID int64, action string, since time.Time) (int, error)
+	LastVPSActionUsage(ctx context.Context, vpsID int64, action string) (time.Time, error)
+}
+
+type VPSActivityRepository interface {
+	CreateVPSActivity(ctx context.Context, activity *domain.VPSActivity) error
+	ListVPSActivities(ctx context.Context, filter appshared.VPSActivityFilter) ([]domain.VPSActivity, int, error)
+	PurgeVPSActivities(ctx context.Context, before time.Time) error
+}
+
+type FirewallTemplateRepository interface {
+	CreateFirewallTemplate(ctx context.Context, tpl *domain.FirewallTemplate) error
+	GetFirewallTemplate(ctx context.Context, id int64) (domain.FirewallTemplate, error)
+	UpdateFirewallTemplate(ctx context.Context, tpl domain.FirewallTemplate) error
+	DeleteFirewallTemplate(ctx context.Context, id int64) error
+	ListFirewallTemplates(ctx context.Context, userID int64) ([]domain.FirewallTemplate, error)
+	ListDefaultFirewallTemplates(ctx context.Context, goodsTypeID int64) ([]domain.FirewallTemplate, error)
+	AttachFirewallTemplate(ctx context.Context, templateID, vpsID int64) error
+	DetachFirewallTemplate(ctx context.Context, templateID, vpsID int64) error
+	ListFirewallTemplateIDsForVPS(ctx context.Context, vpsID int64) ([]int64, error)
+	ListVPSIDsForFirewallTemplate(ctx context.Context, templateID int64) ([]int64, error)
+	GetVPSFirewallSync(ctx context.Context, vpsID int64) (domain.VPSFirewallSync, error)
+	SaveVPSFirewallSync(ctx context.Context, sync domain.VPSFirewallSync) error
+	ListPendingVPSFirewallSyncs(ctx context.Context, maxAttempts, limit int) ([]domain.VPSFirewallSync, error)
+}
+
+type AbuseCaseRepository interface {
+	CreateAbuseCase(ctx context.Context, c *domain.AbuseCase) error
+	GetAbuseCase(ctx context.Context, id int64) (domain.AbuseCase, error)
+	ListAbuseCases(ctx context.Context, filter appshared.AbuseCaseFilter) ([]domain.AbuseCase, int, error)
+	UpdateAbuseCase(ctx context.Context, c domain.AbuseCase) error
+	AddAbuseCaseLink(ctx context.Context, link *domain.AbuseCaseLink) error
+	UpdateAbuseCaseLinkTicket(ctx context.Context, linkID, ticketID int64) error
+	ListAbuseCaseLinks(ctx context.Context, caseID int64) ([]domain.AbuseCaseLink, error)
+	AddAbuseEvidence(ctx context.Context, evidence *domain.AbuseEvidence) error
+	ListAbuseEvidence(ctx context.Context, caseID int64) ([]domain.AbuseEvidence, error)
+	AddAbuseCaseEvent(ctx context.Context, event *domain.AbuseCaseEvent) error
+	ListAbuseCaseEvents(ctx context.Context, caseID int64) ([]domain.AbuseCaseEvent, error)
+}
+
 type SettingsRepository interface {
 	GetSetting(ctx context.Context, key string) (domain.Setting, error)
 	UpsertSetting(ctx context.Context, setting domain.Setting) error
@@ -189,6 +289,37 @@ type PluginInstallationRepository interface {
 	DeletePluginInstallation(ctx context.Context, category, pluginID, instanceID string) error
 }
 
+type PluginKVRepository interface {
+	GetPluginKV(ctx context.Context, category, pluginID, instanceID, key string) (domain.PluginKV, error)
+	PutPluginKV(ctx context.Context, kv *domain.PluginKV) error
+	DeletePluginKV(ctx context.Context, category, pluginID, instanceID, key string) error
+	ListPluginKV(ctx context.Context, category, pluginID, instanceID, prefix string, limit int) ([]domain.PluginKV, error)
+	CountPluginKV(ctx context.Context, category, pluginID, instanceID string) (int, error)
+	DeletePluginKVByInstance(ctx context.Context, category, pluginID, instanceID string) error
+}
+
+type PluginLogRepository interface {
+	CreatePluginLog(ctx context.Context, log *domain.PluginLog) error
+	ListPluginLogs(ctx context.Context, filter appshared.PluginLogFilter) ([]domain.PluginLog, int, error)
+	PurgePluginLogs(ctx context.Context, before time.Time) error
+}
+
+type PluginHookDeliveryRepository interface {
+	CreatePluginHookDeliveries(ctx context.Context, items []domain.PluginHookDelivery) error
+	GetPluginHookDelivery(ctx context.Context, id int64) (domain.PluginHookDelivery, error)
+	UpdatePluginHookDelivery(ctx context.Context, item domain.PluginHookDelivery) error
+	ListDuePluginHookDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.PluginHookDelivery, error)
+	ListPluginHookDeliveries(ctx context.Context, filter appshared.PluginHookDeliveryFilter) ([]domain.PluginHookDelivery, int, error)
+	PurgePluginHookDeliveries(ctx context.Context, before time.Time) error
+}
+
+type PluginVersionRepository interface {
+	UpsertPluginVersion(ctx context.Context, v *domain.PluginVersion) error
+	GetPluginVersion(ctx context.Context, category, pluginID, version string) (domain.PluginVersion, error)
+	ListPluginVersions(ctx context.Context, category, pluginID string) ([]domain.PluginVersion, error)
+	DeletePluginVersions(ctx context.Context, category, pluginID string) error
+}
+
 type PluginPaymentMethodRepository interface {
 	ListPluginPaymentMethods(ctx context.Context, category, pluginID, instanceID string) ([]domain.PluginPaymentMethod, error)
 	UpsertPluginPaymentMethod(ctx context.Context, m *domain.PluginPaymentMethod) error
@@ -220,6 +351,11 @@ type ProvisionJobRepository interface {
 	UpdateProvisionJob(ctx context.Context, job domain.ProvisionJob) error
 }
 
+type ProvisionAttemptRepository interface {
+	CreateProvisionAttempt(ctx context.Context, attempt *domain.ProvisionAttempt) error
+	ListProvisionAttempts(ctx context.Context, filter appshared.ProvisionAttemptFilter) ([]domain.ProvisionAttempt, int, error)
+}
+
 type ResizeTaskRepository interface {
 	CreateResizeTask(ctx context.Context, task *domain.ResizeTask) error
 	GetResizeTask(ctx context.Context, id int64) (domain.ResizeTask, error)
@@ -228,6 +364,19 @@ type ResizeTaskRepository interface {
 	HasPendingResizeTask(ctx context.Context, vpsID int64) (bool, error)
 }
 
+type VPSOperationRepository interface {
+	CreateVPSOperation(ctx context.Context, op *domain.VPSOperation) error
+	GetVPSOperation(ctx context.Context, id int64) (domain.VPSOperation, error)
+	UpdateVPSOperation(ctx context.Context, op domain.VPSOperation) error
+	ListVPSOperations(ctx context.Context, vpsID int64, limit, offset int) ([]domain.VPSOperation, int, error)
+	ListVPSOperationsByStatus(ctx context.Context, statuses []domain.VPSOperationStatus, limit int) ([]domain.VPSOperation, error)
+	HasActiveVPSOperation(ctx context.Context, vpsID int64) (bool, error)
+}
+
+type VPSOperationPublisher interface {
+	PublishVPSOperation(ctx context.Context, op domain.VPSOperation)
+}
+
 type ScheduledTaskRunRepository interface {
 	CreateTaskRun(ctx context.Context, run *domain.ScheduledTaskRun) error
 	UpdateTaskRun(ctx context.Context, run domain.ScheduledTaskRun) error
@@ -345,6 +494,26 @@ type CMSBlockRepository interface {
 type UploadRepository interface {
 	CreateUpload(ctx context.Context, upload *domain.Upload) error
 	ListUploads(ctx context.Context, limit, offset int) ([]domain.Upload, int, error)
+	GetUploadByObjectKey(ctx context.Context, key string) (domain.Upload, error)
+	// ListUploadsByBackend pages by id, returning uploads with ID > afterID.
+	ListUploadsByBackend(ctx context.Context, backend string, afterID int64, limit int) ([]domain.Upload, error)
+	UpdateUploadStorage(ctx context.Context, id int64, backend, key, path string) error
+}
+
+// ObjectStorage is an upload backend holding file contents by object key.
+type ObjectStorage interface {
+	Put(ctx context.Context, key string, body []byte, contentType string) error
+	// Get returns domain.ErrNotFound for a missing object.
+	Get(ctx context.Context, key string) ([]byte, string, error)
+	Delete(ctx context.Context, key string) error
+	// PresignURL returns a time limited download URL, or "" when the backend
+	// can only be read through Get.
+	PresignURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// ObjectStorageResolver returns the backend named on an upload record.
+type ObjectStorageResolver interface {
+	Storage(ctx context.Context, backend string) (ObjectStorage, error)
 }
 
 type TicketRepository interface {
@@ -444,6 +613,8 @@ type OrderApprover interface {
 
 type AutomationClientResolver interface {
 	ClientForGoodsType(ctx context.Context, goodsTypeID int64) (appshared.AutomationClient, error)
+	ClientForBackend(ctx context.Context, pluginID, instanceID string) (appshared.AutomationClient, error)
+	ClientForInstance(ctx context.Context, inst domain.VPSInstance) (appshared.AutomationClient, error)
 }
 
 type EmailSender interface {
@@ -460,3 +631,10 @@ type RealNameRepository interface {
 type SMSSender interface {
 	Send(ctx context.Context, pluginID, instanceID string, msg appshared.SMSMessage) (appshared.SMSDelivery, error)
 }
+
+// CaptchaProvider runs third-party captcha plugins. Verify returns
+// domain.ErrCaptchaFailed when the plugin rejects the answer.
+type CaptchaProvider interface {
+	Init(ctx context.Context, pluginID, instanceID, scene, remoteIP string) (appshared.CaptchaChallenge, error)
+	Verify(ctx context.Context, pluginID, instanceID string, answer appshared.CaptchaAnswer) error
+}
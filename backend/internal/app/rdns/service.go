@@ -0,0 +1,203 @@
+package rdns
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	appports "xiaoheiplay/internal/app/ports"
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+const lookupTimeout = 5 * time.Second
+
+type hostResolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+type Service struct {
+	vps        appports.VPSRepository
+	automation appports.AutomationClientResolver
+	changes    appports.ReverseDNSLogRepository
+	audit      appports.AuditRepository
+	resolver   hostResolver
+}
+
+func NewService(vps appports.VPSRepository, automation appports.AutomationClientResolver, changes appports.ReverseDNSLogRepository, audit appports.AuditRepository) *Service {
+	return &Service{vps: vps, automation: automation, changes: changes, audit: audit, resolver: net.DefaultResolver}
+}
+
+// SetResolver replaces the DNS resolver used for forward confirmation.
+func (s *Service) SetResolver(r hostResolver) {
+	if r != nil {
+		s.resolver = r
+	}
+}
+
+func (s *Service) List(ctx context.Context, inst domain.VPSInstance) ([]appshared.AutomationReverseDNSRecord, error) {
+	cli, hostID, err := s.client(ctx, inst)
+	if err != nil {
+		return nil, err
+	}
+	return cli.ListReverseDNS(ctx, hostID)
+}
+
+// Set updates the PTR of one instance IP on behalf of its owner. The hostname
+// must resolve back to the IP (forward-confirmed reverse DNS) so customers
+// cannot claim names they do not control. An empty ptr resets the record.
+func (s *Service) Set(ctx context.Context, inst domain.VPSInstance, ip, ptr string) error {
+	ptr, err := normalizePTR(ptr)
+	if err != nil {
+		return err
+	}
+	addr := net.ParseIP(strings.TrimSpace(ip))
+	if addr == nil {
+		return appshared.ErrInvalidInput
+	}
+	if ptr != "" {
+		if err := s.forwardConfirm(ctx, ptr, addr); err != nil {
+			return err
+		}
+	}
+	return s.apply(ctx, inst, addr, ptr, domain.ReverseDNSChange{UserID: inst.UserID})
+}
+
+// AdminSet overrides a PTR without forward confirmation, e.g. for hostnames
+// whose forward zone is not published yet. The change is audited.
+func (s *Service) AdminSet(ctx context.Context, adminID, vpsID int64, ip, ptr string) error {
+	inst, err := s.vps.GetInstance(ctx, vpsID)
+	if err != nil {
+		return err
+	}
+	ptr, err = normalizePTR(ptr)
+	if err != nil {
+		return err
+	}
+	addr := net.ParseIP(strings.TrimSpace(ip))
+	if addr == nil {
+		return appshared.ErrInvalidInput
+	}
+	if err := s.apply(ctx, inst, addr, ptr, domain.ReverseDNSChange{AdminID: adminID}); err != nil {
+		return err
+	}
+	if s.audit != nil {
+		_ = s.audit.AddAuditLog(ctx, domain.AdminAuditLog{AdminID: adminID, Action: "vps.rdns_override", TargetType: "vps", TargetID: fmt.Sprintf("%d", inst.ID), DetailJSON: mustJSON(map[string]any{"ip": addr.String(), "ptr": ptr})})
+	}
+	return nil
+}
+
+func (s *Service) ListChanges(ctx context.Context, vpsID int64, limit, offset int) ([]domain.ReverseDNSChange, int, error) {
+	if s.changes == nil {
+		return nil, 0, appshared.ErrNotSupported
+	}
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.changes.ListReverseDNSChanges(ctx, vpsID, limit, offset)
+}
+
+func (s *Service) apply(ctx context.Context, inst domain.VPSInstance, addr net.IP, ptr string, change domain.ReverseDNSChange) error {
+	cli, hostID, err := s.client(ctx, inst)
+	if err != nil {
+		return err
+	}
+	records, err := cli.ListReverseDNS(ctx, hostID)
+	if err != nil {
+		return err
+	}
+	var current *appshared.AutomationReverseDNSRecord
+	for i := range records {
+		if ip := net.ParseIP(strings.TrimSpace(records[i].IP)); ip != nil && ip.Equal(addr) {
+			current = &records[i]
+			break
+		}
+	}
+	if current == nil {
+		return domain.ErrReverseDNSUnknownIP
+	}
+	oldPTR := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(current.PTR)), ".")
+	if oldPTR == ptr {
+		return nil
+	}
+	if err := cli.SetReverseDNS(ctx, hostID, current.IP, ptr); err != nil {
+		return err
+	}
+	if s.changes != nil {
+		change.VPSID = inst.ID
+		change.IP = current.IP
+		change.OldPTR = oldPTR
+		change.NewPTR = ptr
+		_ = s.changes.AddReverseDNSChange(ctx, &change)
+	}
+	return nil
+}
+
+func (s *Service) client(ctx context.Context, inst domain.VPSInstance) (appshared.AutomationClient, int64, error) {
+	hostID, _ := strconv.ParseInt(strings.TrimSpace(inst.AutomationInstanceID), 10, 64)
+	if hostID == 0 || s.automation == nil {
+		return nil, 0, appshared.ErrInvalidInput
+	}
+	cli, err := s.automation.ClientForInstance(ctx, inst)
+	if err != nil {
+		return nil, 0, err
+	}
+	return cli, hostID, nil
+}
+
+func (s *Service) forwardConfirm(ctx context.Context, host string, addr net.IP) error {
+	lookupCtx, cancel := context.WithTimeout(ctx, lookupTimeout)
+	defer cancel()
+	addrs, err := s.resolver.LookupIPAddr(lookupCtx, host)
+	if err != nil {
+		return domain.ErrReverseDNSNotConfirmed
+	}
+	for _, item := range addrs {
+		if item.IP.Equal(addr) {
+			return nil
+		}
+	}
+	return domain.ErrReverseDNSNotConfirmed
+}
+
+// normalizePTR lowercases the hostname and drops the trailing root dot. It
+// requires at least two labels and a non-numeric TLD.
+func normalizePTR(ptr string) (string, error) {
+	ptr = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(ptr)), ".")
+	if ptr == "" {
+		return "", nil
+	}
+	if len(ptr) > 253 {
+		return "", domain.ErrReverseDNSInvalidHostname
+	}
+	labels := strings.Split(ptr, ".")
+	if len(labels) < 2 {
+		return "", domain.ErrReverseDNSInvalidHostname
+	}
+	for _, label := range labels {
+		if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return "", domain.ErrReverseDNSInvalidHostname
+		}
+		for _, ch := range label {
+			if (ch < 'a' || ch > 'z') && (ch < '0' || ch > '9') && ch != '-' {
+				return "", domain.ErrReverseDNSInvalidHostname
+			}
+		}
+	}
+	if _, err := strconv.Atoi(labels[len(labels)-1]); err == nil {
+		return "", domain.ErrReverseDNSInvalidHostname
+	}
+	return ptr, nil
+}
+
+func mustJSON(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -0,0 +1,80 @@
+package rdns_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	apprdns "xiaoheiplay/internal/app/rdns"
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+	"xiaoheiplay/internal/testutil"
+)
+
+type staticResolver map[string][]string
+
+func (r staticResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	addrs, ok := r[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	out := make([]net.IPAddr, 0, len(addrs))
+	for _, addr := range addrs {
+		out = append(out, net.IPAddr{IP: net.ParseIP(addr)})
+	}
+	return out, nil
+}
+
+func TestService_SetForwardConfirmedAndAdminOverride(t *testing.T) {
+	_, repo := testutil.NewTestDB(t, false)
+	ctx := context.Background()
+	user := testutil.CreateUser(t, repo, "rdns", "rdns@example.com", "pass")
+	inst := domain.VPSInstance{UserID: user.ID, AutomationInstanceID: "42", Name: "mail", Status: domain.VPSStatusRunning, SpecJSON: "{}"}
+	if err := repo.CreateInstance(ctx, &inst); err != nil {
+		t.Fatalf("create instance: %v", err)
+	}
+	client := &testutil.FakeAutomationClient{ReverseDNS: []appshared.AutomationReverseDNSRecord{
+		{IP: "203.0.113.10"},
+		{IP: "2001:db8::10"},
+	}}
+	svc := apprdns.NewService(repo, &testutil.FakeAutomationResolver{Client: client}, repo, repo)
+	svc.SetResolver(staticResolver{
+		"mail.example.com":  {"203.0.113.10", "2001:db8::10"},
+		"other.example.com": {"198.51.100.7"},
+	})
+
+	if err := svc.Set(ctx, inst, "203.0.113.10", "not_a host"); !errors.Is(err, domain.ErrReverseDNSInvalidHostname) {
+		t.Fatalf("expected invalid hostname, got %v", err)
+	}
+	if err := svc.Set(ctx, inst, "203.0.113.10", "other.example.com"); !errors.Is(err, domain.ErrReverseDNSNotConfirmed) {
+		t.Fatalf("expected forward confirmation failure, got %v", err)
+	}
+	if err := svc.Set(ctx, inst, "198.51.100.7", "other.example.com"); !errors.Is(err, domain.ErrReverseDNSUnknownIP) {
+		t.Fatalf("expected foreign ip rejected, got %v", err)
+	}
+	if err := svc.Set(ctx, inst, "2001:0db8::0010", "Mail.Example.com."); err != nil {
+		t.Fatalf("set ptr: %v", err)
+	}
+	if len(client.SetReverseDNSCalls) != 1 || client.SetReverseDNSCalls[0].IP != "2001:db8::10" || client.SetReverseDNSCalls[0].PTR != "mail.example.com" {
+		t.Fatalf("unexpected provider call: %+v", client.SetReverseDNSCalls)
+	}
+	if err := svc.Set(ctx, inst, "2001:db8::10", "mail.example.com"); err != nil || len(client.SetReverseDNSCalls) != 1 {
+		t.Fatalf("expected unchanged ptr to be a no-op, got %v", err)
+	}
+
+	if err := svc.AdminSet(ctx, 9, inst.ID, "203.0.113.10", "pending.example.net"); err != nil {
+		t.Fatalf("admin override: %v", err)
+	}
+	changes, total, err := svc.ListChanges(ctx, inst.ID, 10, 0)
+	if err != nil || total != 2 {
+		t.Fatalf("list changes: %d %v", total, err)
+	}
+	if changes[0].AdminID != 9 || changes[0].NewPTR != "pending.example.net" || changes[1].UserID != user.ID || changes[1].OldPTR != "" {
+		t.Fatalf("unexpected change log: %+v", changes)
+	}
+	logs, _, err := repo.ListAuditLogs(ctx, 10, 0)
+	if err != nil || len(logs) != 1 || logs[0].Action != "vps.rdns_override" {
+		t.Fatalf("expected override audited, got %+v %v", logs, err)
+	}
+}
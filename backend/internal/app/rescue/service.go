@@ -0,0 +1,324 @@
+package rescue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	appports "xiaoheiplay/internal/app/ports"
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+const (
+	settingTimeoutMinutes = "vps_rescue_timeout_minutes"
+	settingISOImages      = "vps_iso_images_json"
+
+	defaultTimeout = 2 * time.Hour
+	maxTimeout     = 24 * time.Hour
+	passwordLength = 16
+)
+
+// ISOImage is one admin-configured ISO. ISO is the provider reference passed
+// to the plugin and is not shown to users.
+type ISOImage struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	ISO  string `json:"iso"`
+}
+
+// Credentials are returned once when entering rescue and never stored.
+type Credentials struct {
+	Username  string    `json:"username"`
+	Password  string    `json:"password"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Status is the rescue session and mounted ISO of one instance.
+type Status struct {
+	Active    bool
+	ExpiresAt *time.Time
+	ISO       *domain.VPSISOMount
+}
+
+type Service struct {
+	vps        appports.VPSRepository
+	automation appports.AutomationClientResolver
+	sessions   appports.VPSRescueRepository
+	settings   appports.SettingsRepository
+	audit      appports.AuditRepository
+}
+
+func NewService(vps appports.VPSRepository, automation appports.AutomationClientResolver, sessions appports.VPSRescueRepository, settings appports.SettingsRepository, audit appports.AuditRepository) *Service {
+	return &Service{vps: vps, automation: automation, sessions: sessions, settings: settings, audit: audit}
+}
+
+func (s *Service) Status(ctx context.Context, inst domain.VPSInstance) (Status, error) {
+	var out Status
+	session, err := s.sessions.GetActiveRescueSession(ctx, inst.ID)
+	switch {
+	case err == nil:
+		out.Active = true
+		out.ExpiresAt = &session.ExpiresAt
+	case !errors.Is(err, appshared.ErrNotFound):
+		return Status{}, err
+	}
+	mount, err := s.sessions.GetISOMount(ctx, inst.ID)
+	switch {
+	case err == nil:
+		out.ISO = &mount
+	case !errors.Is(err, appshared.ErrNotFound):
+		return Status{}, err
+	}
+	return out, nil
+}
+
+// Enter boots the instance into rescue with a fresh root password. The
+// session ends automatically after the configured timeout.
+func (s *Service) Enter(ctx context.Context, inst domain.VPSInstance) (Credentials, error) {
+	if err := checkUsable(inst); err != nil {
+		return Credentials{}, err
+	}
+	if inst.Status != domain.VPSStatusRunning && inst.Status != domain.VPSStatusStopped {
+		if inst.Status == domain.VPSStatusRescue {
+			return Credentials{}, domain.ErrRescueActive
+		}
+		return Credentials{}, appshared.ErrConflict
+	}
+	if _, err := s.sessions.GetActiveRescueSession(ctx, inst.ID); err == nil {
+		return Credentials{}, domain.ErrRescueActive
+	} else if !errors.Is(err, appshared.ErrNotFound) {
+		return Credentials{}, err
+	}
+	cli, hostID, err := s.client(ctx, inst)
+	if err != nil {
+		return Credentials{}, err
+	}
+	password := randomPassword(passwordLength)
+	creds, err := cli.EnterRescue(ctx, hostID, password)
+	if err != nil {
+		return Credentials{}, err
+	}
+	out := Credentials{Username: strings.TrimSpace(creds.Username), Password: creds.Password}
+	if out.Username == "" {
+		out.Username = "root"
+	}
+	if out.Password == "" {
+		out.Password = password
+	}
+	session := domain.VPSRescueSession{VPSID: inst.ID, UserID: inst.UserID, ExpiresAt: time.Now().Add(s.timeout(ctx))}
+	if err := s.sessions.CreateRescueSession(ctx, &session); err != nil {
+		// Without a session nothing would ever boot the host back from rescue.
+		_ = cli.ExitRescue(ctx, hostID)
+		return Credentials{}, err
+	}
+	_ = s.vps.UpdateInstanceStatus(ctx, inst.ID, domain.VPSStatusRescue, inst.AutomationState)
+	out.ExpiresAt = session.ExpiresAt
+	return out, nil
+}
+
+func (s *Service) Exit(ctx context.Context, inst domain.VPSInstance) error {
+	session, err := s.sessions.GetActiveRescueSession(ctx, inst.ID)
+	if errors.Is(err, appshared.ErrNotFound) {
+		return domain.ErrRescueNotActive
+	}
+	if err != nil {
+		return err
+	}
+	return s.exit(ctx, inst, session, "user")
+}
+
+// AdminExit ends rescue on behalf of support staff. The change is audited.
+func (s *Service) AdminExit(ctx context.Context, adminID, vpsID int64) error {
+	inst, err := s.vps.GetInstance(ctx, vpsID)
+	if err != nil {
+		return err
+	}
+	session, err := s.sessions.GetActiveRescueSession(ctx, inst.ID)
+	if errors.Is(err, appshared.ErrNotFound) {
+		return domain.ErrRescueNotActive
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.exit(ctx, inst, session, "admin"); err != nil {
+		return err
+	}
+	if s.audit != nil {
+		_ = s.audit.AddAuditLog(ctx, domain.AdminAuditLog{AdminID: adminID, Action: "vps.rescue_exit", TargetType: "vps", TargetID: fmt.Sprintf("%d", inst.ID), DetailJSON: mustJSON(map[string]any{"session_id": session.ID})})
+	}
+	return nil
+}
+
+// ExpireSessions boots timed-out rescue sessions back from disk. Sessions
+// whose exit fails stay open and are retried on the next run.
+func (s *Service) ExpireSessions(ctx context.Context, limit int) (int, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	sessions, err := s.sessions.ListExpiredRescueSessions(ctx, time.Now(), limit)
+	if err != nil {
+		return 0, err
+	}
+	done := 0
+	var lastErr error
+	for _, session := range sessions {
+		inst, err := s.vps.GetInstance(ctx, session.VPSID)
+		if errors.Is(err, appshared.ErrNotFound) {
+			_ = s.sessions.EndRescueSession(ctx, session.ID, "timeout", time.Now())
+			continue
+		}
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if err := s.exit(ctx, inst, session, "timeout"); err != nil {
+			lastErr = fmt.Errorf("vps %d: %w", inst.ID, err)
+			continue
+		}
+		done++
+	}
+	return done, lastErr
+}
+
+func (s *Service) ListISOs(ctx context.Context) []ISOImage {
+	if s.settings == nil {
+		return nil
+	}
+	setting, err := s.settings.GetSetting(ctx, settingISOImages)
+	if err != nil || strings.TrimSpace(setting.ValueJSON) == "" {
+		return nil
+	}
+	var items []ISOImage
+	if err := json.Unmarshal([]byte(setting.ValueJSON), &items); err != nil {
+		return nil
+	}
+	out := make([]ISOImage, 0, len(items))
+	for _, item := range items {
+		item.ID = strings.TrimSpace(item.ID)
+		item.ISO = strings.TrimSpace(item.ISO)
+		if item.ID == "" || item.ISO == "" {
+			continue
+		}
+		if strings.TrimSpace(item.Name) == "" {
+			item.Name = item.ID
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+func (s *Service) MountISO(ctx context.Context, inst domain.VPSInstance, isoID string) error {
+	if err := checkUsable(inst); err != nil {
+		return err
+	}
+	isoID = strings.TrimSpace(isoID)
+	var image *ISOImage
+	for _, item := range s.ListISOs(ctx) {
+		if item.ID == isoID {
+			image = &item
+			break
+		}
+	}
+	if image == nil {
+		return domain.ErrISONotFound
+	}
+	cli, hostID, err := s.client(ctx, inst)
+	if err != nil {
+		return err
+	}
+	if err := cli.MountISO(ctx, hostID, image.ISO); err != nil {
+		return err
+	}
+	return s.sessions.SaveISOMount(ctx, domain.VPSISOMount{VPSID: inst.ID, ISOID: image.ID, ISOName: image.Name, MountedAt: time.Now()})
+}
+
+func (s *Service) UnmountISO(ctx context.Context, inst domain.VPSInstance) error {
+	cli, hostID, err := s.client(ctx, inst)
+	if err != nil {
+		return err
+	}
+	if err := cli.UnmountISO(ctx, hostID); err != nil {
+		return err
+	}
+	return s.sessions.DeleteISOMount(ctx, inst.ID)
+}
+
+func (s *Service) exit(ctx context.Context, inst domain.VPSInstance, session domain.VPSRescueSession, reason string) error {
+	cli, hostID, err := s.client(ctx, inst)
+	if err != nil {
+		return err
+	}
+	if err := cli.ExitRescue(ctx, hostID); err != nil {
+		return err
+	}
+	if err := s.sessions.EndRescueSession(ctx, session.ID, reason, time.Now()); err != nil {
+		return err
+	}
+	// Leave lock states set while in rescue alone; the next refresh picks up
+	// the real power state.
+	if latest, err := s.vps.GetInstance(ctx, inst.ID); err == nil && latest.Status == domain.VPSStatusRescue {
+		_ = s.vps.UpdateInstanceStatus(ctx, inst.ID, domain.VPSStatusRunning, latest.AutomationState)
+	}
+	return nil
+}
+
+func (s *Service) timeout(ctx context.Context) time.Duration {
+	if s.settings == nil {
+		return defaultTimeout
+	}
+	setting, err := s.settings.GetSetting(ctx, settingTimeoutMinutes)
+	if err != nil {
+		return defaultTimeout
+	}
+	minutes, err := strconv.Atoi(strings.TrimSpace(setting.ValueJSON))
+	if err != nil || minutes <= 0 {
+		return defaultTimeout
+	}
+	if d := time.Duration(minutes) * time.Minute; d < maxTimeout {
+		return d
+	}
+	return maxTimeout
+}
+
+func (s *Service) client(ctx context.Context, inst domain.VPSInstance) (appshared.AutomationClient, int64, error) {
+	hostID, _ := strconv.ParseInt(strings.TrimSpace(inst.AutomationInstanceID), 10, 64)
+	if hostID == 0 || s.automation == nil {
+		return nil, 0, appshared.ErrInvalidInput
+	}
+	cli, err := s.automation.ClientForInstance(ctx, inst)
+	if err != nil {
+		return nil, 0, err
+	}
+	return cli, hostID, nil
+}
+
+func checkUsable(inst domain.VPSInstance) error {
+	if inst.AdminStatus != "" && inst.AdminStatus != domain.VPSAdminStatusNormal {
+		return appshared.ErrForbidden
+	}
+	if inst.Status == domain.VPSStatusLocked || inst.Status == domain.VPSStatusExpiredLocked {
+		return appshared.ErrForbidden
+	}
+	return nil
+}
+
+func randomPassword(n int) string {
+	letters := []byte("abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789")
+	buf := make([]byte, n)
+	_, _ = rand.Read(buf)
+	for i := range buf {
+		buf[i] = letters[int(buf[i])%len(letters)]
+	}
+	return string(buf)
+}
+
+func mustJSON(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -0,0 +1,141 @@
+package rescue_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	appports "xiaoheiplay/internal/app/ports"
+	apprescue "xiaoheiplay/internal/app/rescue"
+	"xiaoheiplay/internal/domain"
+	"xiaoheiplay/internal/testutil"
+)
+
+func TestService_RescueLifecycleAndTimeout(t *testing.T) {
+	_, repo := testutil.NewTestDB(t, false)
+	ctx := context.Background()
+	user := testutil.CreateUser(t, repo, "rescue", "rescue@example.com", "pass")
+	inst := domain.VPSInstance{UserID: user.ID, AutomationInstanceID: "42", Name: "broken", Status: domain.VPSStatusStopped, SpecJSON: "{}"}
+	if err := repo.CreateInstance(ctx, &inst); err != nil {
+		t.Fatalf("create instance: %v", err)
+	}
+	client := &testutil.FakeAutomationClient{}
+	svc := apprescue.NewService(repo, &testutil.FakeAutomationResolver{Client: client}, repo, repo, repo)
+
+	creds, err := svc.Enter(ctx, inst)
+	if err != nil {
+		t.Fatalf("enter rescue: %v", err)
+	}
+	if creds.Username != "root" || len(creds.Password) < 12 || len(client.RescueCalls) != 1 || client.RescueCalls[0].Password != creds.Password {
+		t.Fatalf("unexpected credentials %+v calls %+v", creds, client.RescueCalls)
+	}
+	if d := time.Until(creds.ExpiresAt); d < time.Hour || d > 3*time.Hour {
+		t.Fatalf("expected default timeout, got %v", d)
+	}
+	latest, _ := repo.GetInstance(ctx, inst.ID)
+	if latest.Status != domain.VPSStatusRescue {
+		t.Fatalf("expected rescue status, got %s", latest.Status)
+	}
+	if _, err := svc.Enter(ctx, latest); !errors.Is(err, domain.ErrRescueActive) {
+		t.Fatalf("expected already in rescue, got %v", err)
+	}
+	if err := svc.Exit(ctx, latest); err != nil {
+		t.Fatalf("exit rescue: %v", err)
+	}
+	latest, _ = repo.GetInstance(ctx, inst.ID)
+	if latest.Status != domain.VPSStatusRunning || len(client.ExitRescueCalls) != 1 {
+		t.Fatalf("expected running after exit, got %s %+v", latest.Status, client.ExitRescueCalls)
+	}
+	if err := svc.Exit(ctx, latest); !errors.Is(err, domain.ErrRescueNotActive) {
+		t.Fatalf("expected not in rescue, got %v", err)
+	}
+
+	stale := domain.VPSInstance{UserID: user.ID, AutomationInstanceID: "43", Name: "forgotten", Status: domain.VPSStatusRescue, SpecJSON: "{}"}
+	if err := repo.CreateInstance(ctx, &stale); err != nil {
+		t.Fatalf("create instance: %v", err)
+	}
+	if err := repo.CreateRescueSession(ctx, &domain.VPSRescueSession{VPSID: stale.ID, UserID: user.ID, ExpiresAt: time.Now().Add(-time.Minute)}); err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	n, err := svc.ExpireSessions(ctx, 10)
+	if err != nil || n != 1 {
+		t.Fatalf("expire sessions: %d %v", n, err)
+	}
+	latest, _ = repo.GetInstance(ctx, stale.ID)
+	if latest.Status != domain.VPSStatusRunning || client.ExitRescueCalls[1] != 43 {
+		t.Fatalf("expected timed-out rescue exited, got %s %+v", latest.Status, client.ExitRescueCalls)
+	}
+	if n, err := svc.ExpireSessions(ctx, 10); err != nil || n != 0 {
+		t.Fatalf("expected nothing left to expire, got %d %v", n, err)
+	}
+}
+
+type failingRescueSessions struct {
+	appports.VPSRescueRepository
+}
+
+func (failingRescueSessions) CreateRescueSession(ctx context.Context, session *domain.VPSRescueSession) error {
+	return errors.New("db down")
+}
+
+func TestService_EnterExitsRescueWhenSessionIsNotSaved(t *testing.T) {
+	_, repo := testutil.NewTestDB(t, false)
+	ctx := context.Background()
+	user := testutil.CreateUser(t, repo, "rescue2", "rescue2@example.com", "pass")
+	inst := domain.VPSInstance{UserID: user.ID, AutomationInstanceID: "44", Name: "flaky", Status: domain.VPSStatusRunning, SpecJSON: "{}"}
+	if err := repo.CreateInstance(ctx, &inst); err != nil {
+		t.Fatalf("create instance: %v", err)
+	}
+	client := &testutil.FakeAutomationClient{}
+	svc := apprescue.NewService(repo, &testutil.FakeAutomationResolver{Client: client}, failingRescueSessions{repo}, repo, repo)
+
+	if _, err := svc.Enter(ctx, inst); err == nil {
+		t.Fatalf("expected enter to fail")
+	}
+	if len(client.RescueCalls) != 1 || len(client.ExitRescueCalls) != 1 || client.ExitRescueCalls[0] != 44 {
+		t.Fatalf("expected rescue rolled back, got enter %+v exit %+v", client.RescueCalls, client.ExitRescueCalls)
+	}
+	latest, _ := repo.GetInstance(ctx, inst.ID)
+	if latest.Status != domain.VPSStatusRunning {
+		t.Fatalf("expected status unchanged, got %s", latest.Status)
+	}
+}
+
+func TestService_MountISOFromAdminCatalog(t *testing.T) {
+	_, repo := testutil.NewTestDB(t, false)
+	ctx := context.Background()
+	user := testutil.CreateUser(t, repo, "iso", "iso@example.com", "pass")
+	inst := domain.VPSInstance{UserID: user.ID, AutomationInstanceID: "7", Name: "iso", Status: domain.VPSStatusRunning, SpecJSON: "{}"}
+	if err := repo.CreateInstance(ctx, &inst); err != nil {
+		t.Fatalf("create instance: %v", err)
+	}
+	if err := repo.UpsertSetting(ctx, domain.Setting{Key: "vps_iso_images_json", ValueJSON: `[{"id":"sysrescue","name":"SystemRescue 11","iso":"https://mirror.example.com/sysrescue.iso"},{"id":"","iso":"x"}]`}); err != nil {
+		t.Fatalf("upsert setting: %v", err)
+	}
+	client := &testutil.FakeAutomationClient{}
+	svc := apprescue.NewService(repo, &testutil.FakeAutomationResolver{Client: client}, repo, repo, repo)
+
+	if isos := svc.ListISOs(ctx); len(isos) != 1 || isos[0].ID != "sysrescue" {
+		t.Fatalf("unexpected iso catalog: %+v", isos)
+	}
+	if err := svc.MountISO(ctx, inst, "https://evil.example.com/x.iso"); !errors.Is(err, domain.ErrISONotFound) {
+		t.Fatalf("expected unknown iso rejected, got %v", err)
+	}
+	if err := svc.MountISO(ctx, inst, "sysrescue"); err != nil {
+		t.Fatalf("mount iso: %v", err)
+	}
+	if len(client.MountISOCalls) != 1 || client.MountISOCalls[0].ISO != "https://mirror.example.com/sysrescue.iso" {
+		t.Fatalf("unexpected mount calls: %+v", client.MountISOCalls)
+	}
+	status, err := svc.Status(ctx, inst)
+	if err != nil || status.Active || status.ISO == nil || status.ISO.ISOName != "SystemRescue 11" {
+		t.Fatalf("unexpected status %+v %v", status, err)
+	}
+	if err := svc.UnmountISO(ctx, inst); err != nil {
+		t.Fatalf("unmount iso: %v", err)
+	}
+	if status, _ := svc.Status(ctx, inst); status.ISO != nil {
+		t.Fatalf("expected iso cleared, got %+v", status.ISO)
+	}
+}
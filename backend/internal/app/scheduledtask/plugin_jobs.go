@@ -0,0 +1,222 @@
+package scheduledtask
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+type pluginJobHost interface {
+	ListJobs(ctx context.Context) ([]appshared.PluginJob, error)
+	RunJob(ctx context.Context, job appshared.PluginJob, scheduledAt time.Time, manual bool) (string, error)
+}
+
+type pluginJobOverride struct {
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// SetPluginJobHost lets the scheduler loop run the jobs declared by enabled
+// plugin instances.
+func (s *Service) SetPluginJobHost(host pluginJobHost) {
+	s.pluginJobs = host
+}
+
+func (s *Service) ListPluginJobs(ctx context.Context) ([]appshared.PluginJobStatus, error) {
+	if s.pluginJobs == nil {
+		return []appshared.PluginJobStatus{}, nil
+	}
+	jobs, err := s.pluginJobs.ListJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	overrides := s.loadPluginJobOverrides(ctx)
+	now := time.Now()
+	out := make([]appshared.PluginJobStatus, 0, len(jobs))
+	for _, job := range jobs {
+		out = append(out, s.pluginJobStatus(job, overrides, now))
+	}
+	return out, nil
+}
+
+// UpdatePluginJob enables or disables the scheduled runs of a job. Disabled
+// jobs can still be triggered manually.
+func (s *Service) UpdatePluginJob(ctx context.Context, key string, enabled bool) (appshared.PluginJobStatus, error) {
+	job, err := s.findPluginJob(ctx, key)
+	if err != nil {
+		return appshared.PluginJobStatus{}, err
+	}
+	if s.settings == nil {
+		return appshared.PluginJobStatus{}, appshared.ErrInvalidInput
+	}
+	overrides := s.loadPluginJobOverrides(ctx)
+	if enabled {
+		delete(overrides, key)
+	} else {
+		overrides[key] = pluginJobOverride{Enabled: &enabled}
+	}
+	raw, err := json.Marshal(overrides)
+	if err != nil {
+		return appshared.PluginJobStatus{}, err
+	}
+	if err := s.settings.UpsertSetting(ctx, domain.Setting{Key: appshared.PluginJobsSettingKey, ValueJSON: string(raw), UpdatedAt: time.Now()}); err != nil {
+		return appshared.PluginJobStatus{}, err
+	}
+	return s.pluginJobStatus(job, overrides, time.Now()), nil
+}
+
+// TriggerPluginJob starts a manual run in the background. The run keeps
+// using ctx after this returns, so it must not be request scoped. It returns
+// ErrConflict while a run of the job is in flight.
+func (s *Service) TriggerPluginJob(ctx context.Context, key string) error {
+	job, err := s.findPluginJob(ctx, key)
+	if err != nil {
+		return err
+	}
+	host := s.pluginJobs
+	started := s.startTracked(ctx, key, func(ctx context.Context) (string, error) {
+		return host.RunJob(ctx, job, time.Time{}, true)
+	})
+	if !started {
+		return appshared.ErrConflict
+	}
+	return nil
+}
+
+// dispatchPluginJobs starts every enabled plugin job that is due. Runs are
+// tracked like built-in tasks so their history lands in scheduled_task_runs.
+func (s *Service) dispatchPluginJobs(ctx context.Context) error {
+	if s.pluginJobs == nil {
+		return nil
+	}
+	jobs, err := s.pluginJobs.ListJobs(ctx)
+	if err != nil {
+		return err
+	}
+	overrides := s.loadPluginJobOverrides(ctx)
+	now := time.Now()
+	host := s.pluginJobs
+	for _, job := range jobs {
+		if !pluginJobEnabled(overrides, job.Key()) {
+			continue
+		}
+		slot, due := s.pluginJobDue(job, now)
+		if !due {
+			continue
+		}
+		job := job
+		s.startTracked(ctx, job.Key(), func(ctx context.Context) (string, error) {
+			return host.RunJob(ctx, job, slot, false)
+		})
+	}
+	return nil
+}
+
+// pluginJobDue reports whether job should run at now and the slot it runs
+// for. Cron jobs first seen by this process wait for their next slot rather
+// than firing immediately.
+func (s *Service) pluginJobDue(job appshared.PluginJob, now time.Time) (time.Time, bool) {
+	next := s.pluginJobNextRun(job, now)
+	if next.IsZero() || next.After(now) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+func (s *Service) pluginJobNextRun(job appshared.PluginJob, now time.Time) time.Time {
+	s.mu.Lock()
+	rt := s.ensureRuntime(job.Key())
+	if rt.since.IsZero() {
+		rt.since = now
+	}
+	base := rt.lastRun
+	since := rt.since
+	s.mu.Unlock()
+	def := job.Definition
+	if strings.TrimSpace(def.Cron) != "" {
+		sched, err := appshared.ParseCron(def.Cron)
+		if err != nil {
+			return time.Time{}
+		}
+		if base.IsZero() {
+			base = since
+		}
+		return sched.Next(base)
+	}
+	if base.IsZero() {
+		return now
+	}
+	return base.Add(time.Duration(def.IntervalSec) * time.Second)
+}
+
+func (s *Service) pluginJobStatus(job appshared.PluginJob, overrides map[string]pluginJobOverride, now time.Time) appshared.PluginJobStatus {
+	key := job.Key()
+	out := appshared.PluginJobStatus{
+		Key:       key,
+		PluginJob: job,
+		Enabled:   pluginJobEnabled(overrides, key),
+	}
+	if out.Enabled {
+		if next := s.pluginJobNextRun(job, now); !next.IsZero() {
+			out.NextRunAt = &next
+		}
+	}
+	s.mu.Lock()
+	rt := s.ensureRuntime(key)
+	if !rt.lastRun.IsZero() {
+		last := rt.lastRun
+		out.LastRunAt = &last
+	}
+	out.Running = rt.running
+	out.LastStatus = rt.lastStatus
+	out.LastError = rt.lastError
+	out.LastElapsed = rt.lastElapsed
+	s.mu.Unlock()
+	return out
+}
+
+func (s *Service) findPluginJob(ctx context.Context, key string) (appshared.PluginJob, error) {
+	if s.pluginJobs == nil {
+		return appshared.PluginJob{}, appshared.ErrNotFound
+	}
+	jobs, err := s.pluginJobs.ListJobs(ctx)
+	if err != nil {
+		return appshared.PluginJob{}, err
+	}
+	for _, job := range jobs {
+		if job.Key() == key {
+			return job, nil
+		}
+	}
+	return appshared.PluginJob{}, appshared.ErrNotFound
+}
+
+func (s *Service) loadPluginJobOverrides(ctx context.Context) map[string]pluginJobOverride {
+	out := map[string]pluginJobOverride{}
+	if s.settings == nil {
+		return out
+	}
+	setting, err := s.settings.GetSetting(ctx, appshared.PluginJobsSettingKey)
+	if err != nil {
+		return out
+	}
+	raw := strings.TrimSpace(setting.ValueJSON)
+	if raw == "" || raw == "{}" {
+		return out
+	}
+	if err := json.Unmarshal([]byte(raw), &out); err != nil || out == nil {
+		return map[string]pluginJobOverride{}
+	}
+	return out
+}
+
+func pluginJobEnabled(overrides map[string]pluginJobOverride, key string) bool {
+	o, ok := overrides[key]
+	if !ok || o.Enabled == nil {
+		return true
+	}
+	return *o.Enabled
+}
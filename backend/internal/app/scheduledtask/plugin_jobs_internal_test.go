@@ -0,0 +1,224 @@
+package scheduledtask
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/testutil"
+)
+
+type fakeJobRun struct {
+	key    string
+	manual bool
+}
+
+type fakePluginJobHost struct {
+	jobs    []appshared.PluginJob
+	release chan struct{}
+	mu      sync.Mutex
+	runs    []fakeJobRun
+}
+
+func (f *fakePluginJobHost) ListJobs(ctx context.Context) ([]appshared.PluginJob, error) {
+	return f.jobs, nil
+}
+
+func (f *fakePluginJobHost) RunJob(ctx context.Context, job appshared.PluginJob, scheduledAt time.Time, manual bool) (string, error) {
+	f.mu.Lock()
+	f.runs = append(f.runs, fakeJobRun{key: job.Key(), manual: manual})
+	f.mu.Unlock()
+	if f.release != nil {
+		<-f.release
+	}
+	return "ok", nil
+}
+
+func (f *fakePluginJobHost) runCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.runs)
+}
+
+func newPluginJobTestService(t *testing.T, host *fakePluginJobHost) *Service {
+	t.Helper()
+	_, repo := testutil.NewTestDB(t, false)
+	svc := NewService(repo, nil, nil, nil, repo)
+	svc.SetPluginJobHost(host)
+	return svc
+}
+
+func testPluginJob(name string, def appshared.PluginJobDefinition) appshared.PluginJob {
+	def.Name = name
+	return appshared.PluginJob{Category: "automation", PluginID: "demo", InstanceID: "default", Definition: def}
+}
+
+func waitPluginJobIdle(t *testing.T, svc *Service, key string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		svc.mu.Lock()
+		rt := svc.ensureRuntime(key)
+		idle := !rt.running && !rt.lastRun.IsZero()
+		svc.mu.Unlock()
+		if idle {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish", key)
+}
+
+func TestDispatchPluginJobs_RunsDueJobs(t *testing.T) {
+	interval := testPluginJob("sync", appshared.PluginJobDefinition{IntervalSec: 60})
+	cron := testPluginJob("report", appshared.PluginJobDefinition{Cron: "0 0 1 1 *"})
+	host := &fakePluginJobHost{jobs: []appshared.PluginJob{interval, cron}}
+	svc := newPluginJobTestService(t, host)
+	ctx := context.Background()
+
+	if err := svc.dispatchPluginJobs(ctx); err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+	waitPluginJobIdle(t, svc, interval.Key())
+	if host.runCount() != 1 || host.runs[0].key != interval.Key() || host.runs[0].manual {
+		t.Fatalf("expected one scheduled run of the interval job, got %+v", host.runs)
+	}
+
+	if err := svc.dispatchPluginJobs(ctx); err != nil {
+		t.Fatalf("dispatch again: %v", err)
+	}
+	if host.runCount() != 1 {
+		t.Fatalf("expected the interval job to wait for its next slot, got %+v", host.runs)
+	}
+	runs, err := svc.ListTaskRuns(ctx, interval.Key(), 10)
+	if err != nil || len(runs) != 1 {
+		t.Fatalf("expected one recorded run, got %+v %v", runs, err)
+	}
+}
+
+func TestRunOnce_DispatchesPluginJobsWithBuiltinTasksDisabled(t *testing.T) {
+	job := testPluginJob("sync", appshared.PluginJobDefinition{IntervalSec: 60})
+	host := &fakePluginJobHost{jobs: []appshared.PluginJob{job}}
+	svc := newPluginJobTestService(t, host)
+	ctx := context.Background()
+
+	disabled := false
+	for key := range defaultTaskDefinitions() {
+		if _, err := svc.UpdateTask(ctx, key, ScheduledTaskUpdate{Enabled: &disabled}); err != nil {
+			t.Fatalf("disable %s: %v", key, err)
+		}
+	}
+	svc.runOnce(ctx)
+	waitPluginJobIdle(t, svc, job.Key())
+	if host.runCount() != 1 {
+		t.Fatalf("expected the plugin job to run, got %+v", host.runs)
+	}
+}
+
+func TestDispatchPluginJobs_SkipsDisabledJobs(t *testing.T) {
+	job := testPluginJob("sync", appshared.PluginJobDefinition{IntervalSec: 60})
+	host := &fakePluginJobHost{jobs: []appshared.PluginJob{job}}
+	svc := newPluginJobTestService(t, host)
+	ctx := context.Background()
+
+	status, err := svc.UpdatePluginJob(ctx, job.Key(), false)
+	if err != nil {
+		t.Fatalf("disable job: %v", err)
+	}
+	if status.Enabled || status.NextRunAt != nil {
+		t.Fatalf("expected disabled job without next run, got %+v", status)
+	}
+	if err := svc.dispatchPluginJobs(ctx); err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+	if host.runCount() != 0 {
+		t.Fatalf("expected disabled job to be skipped, got %+v", host.runs)
+	}
+
+	if err := svc.TriggerPluginJob(ctx, job.Key()); err != nil {
+		t.Fatalf("trigger disabled job: %v", err)
+	}
+	waitPluginJobIdle(t, svc, job.Key())
+	if host.runCount() != 1 || !host.runs[0].manual {
+		t.Fatalf("expected one manual run, got %+v", host.runs)
+	}
+
+	status, err = svc.UpdatePluginJob(ctx, job.Key(), true)
+	if err != nil || !status.Enabled {
+		t.Fatalf("enable job: %+v %v", status, err)
+	}
+	if overrides := svc.loadPluginJobOverrides(ctx); len(overrides) != 0 {
+		t.Fatalf("expected enabling to drop the override, got %+v", overrides)
+	}
+	if _, err := svc.UpdatePluginJob(ctx, "plugin:automation:demo:default:missing", false); !errors.Is(err, appshared.ErrNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestDispatchPluginJobs_DoesNotOverlapRuns(t *testing.T) {
+	job := testPluginJob("sync", appshared.PluginJobDefinition{IntervalSec: 30})
+	host := &fakePluginJobHost{jobs: []appshared.PluginJob{job}, release: make(chan struct{})}
+	svc := newPluginJobTestService(t, host)
+	ctx := context.Background()
+
+	if err := svc.dispatchPluginJobs(ctx); err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+	if err := svc.TriggerPluginJob(ctx, job.Key()); !errors.Is(err, appshared.ErrConflict) {
+		t.Fatalf("expected conflict while running, got %v", err)
+	}
+	if err := svc.dispatchPluginJobs(ctx); err != nil {
+		t.Fatalf("dispatch again: %v", err)
+	}
+	list, err := svc.ListPluginJobs(ctx)
+	if err != nil || len(list) != 1 || !list[0].Running {
+		t.Fatalf("expected running job status, got %+v %v", list, err)
+	}
+
+	close(host.release)
+	waitPluginJobIdle(t, svc, job.Key())
+	if host.runCount() != 1 {
+		t.Fatalf("expected a single run, got %+v", host.runs)
+	}
+}
+
+func TestPluginJobNextRun(t *testing.T) {
+	svc := newPluginJobTestService(t, &fakePluginJobHost{})
+	now := time.Date(2026, 3, 10, 10, 7, 30, 0, time.UTC)
+
+	cron := testPluginJob("report", appshared.PluginJobDefinition{Cron: "*/15 * * * *"})
+	if next := svc.pluginJobNextRun(cron, now); !next.Equal(time.Date(2026, 3, 10, 10, 15, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected first cron slot: %s", next)
+	}
+	// A cron job stays anchored on when it was first seen, so a slot that
+	// passed between ticks is still due.
+	later := now.Add(10 * time.Minute)
+	if slot, due := svc.pluginJobDue(cron, later); !due || !slot.Equal(time.Date(2026, 3, 10, 10, 15, 0, 0, time.UTC)) {
+		t.Fatalf("expected the missed slot to be due, got %s %v", slot, due)
+	}
+	svc.mu.Lock()
+	svc.ensureRuntime(cron.Key()).lastRun = later
+	svc.mu.Unlock()
+	if next := svc.pluginJobNextRun(cron, later); !next.Equal(time.Date(2026, 3, 10, 10, 30, 0, 0, time.UTC)) {
+		t.Fatalf("expected next slot after the last run, got %s", next)
+	}
+
+	interval := testPluginJob("sync", appshared.PluginJobDefinition{IntervalSec: 120})
+	if next := svc.pluginJobNextRun(interval, now); !next.Equal(now) {
+		t.Fatalf("expected an interval job to be due at once, got %s", next)
+	}
+	svc.mu.Lock()
+	svc.ensureRuntime(interval.Key()).lastRun = now
+	svc.mu.Unlock()
+	if next := svc.pluginJobNextRun(interval, now); !next.Equal(now.Add(2 * time.Minute)) {
+		t.Fatalf("expected the interval after the last run, got %s", next)
+	}
+
+	broken := testPluginJob("broken", appshared.PluginJobDefinition{Cron: "not a cron"})
+	if next := svc.pluginJobNextRun(broken, now); !next.IsZero() {
+		t.Fatalf("expected no next run for an invalid cron, got %s", next)
+	}
+}
@@ -68,7 +68,29 @@ type logRetentionCleaner interface {
 	Cleanup(ctx context.Context) (string, error)
 }
 
+type backendHealthChecker interface {
+	CheckBackendHealth(ctx context.Context) (int, error)
+}
+
+type rescueExpirer interface {
+	ExpireSessions(ctx context.Context, limit int) (int, error)
+}
+
+type metricsCollector interface {
+	Collect(ctx context.Context) (int, error)
+}
+
+type abuseResponseChecker interface {
+	CheckResponses(ctx context.Context, limit int) (int, error)
+}
+
+type firewallReconciler interface {
+	ReconcilePending(ctx context.Context, limit int) (int, error)
+}
+
 type taskRuntime struct {
+	// since anchors cron schedules that have not run in this process yet.
+	since       time.Time
 	lastRun     time.Time
 	running     bool
 	lastStatus  string
@@ -85,6 +107,12 @@ type Service struct {
 	userTier    userTierTaskService
 	integration integrationInventorySyncService
 	logCleaner  logRetentionCleaner
+	backends    backendHealthChecker
+	rescue      rescueExpirer
+	metrics     metricsCollector
+	abuse       abuseResponseChecker
+	firewall    firewallReconciler
+	pluginJobs  pluginJobHost
 	runs        appports.ScheduledTaskRunRepository
 	mu          sync.Mutex
 	runtime     map[string]*taskRuntime
@@ -118,6 +146,26 @@ func (s *Service) SetLogRetentionCleaner(svc logRetentionCleaner) {
 	s.logCleaner = svc
 }
 
+func (s *Service) SetBackendHealthChecker(svc backendHealthChecker) {
+	s.backends = svc
+}
+
+func (s *Service) SetRescueExpirer(svc rescueExpirer) {
+	s.rescue = svc
+}
+
+func (s *Service) SetMetricsCollector(svc metricsCollector) {
+	s.metrics = svc
+}
+
+func (s *Service) SetAbuseResponseChecker(svc abuseResponseChecker) {
+	s.abuse = svc
+}
+
+func (s *Service) SetFirewallReconciler(svc firewallReconciler) {
+	s.firewall = svc
+}
+
 func (s *Service) Start(ctx context.Context) {
 	ticker := time.NewTicker(10 * time.Second)
 	defer ticker.Stop()
@@ -204,6 +252,9 @@ func (s *Service) runOnce(ctx context.Context) {
 		}
 		s.executeTask(ctx, cfg)
 	}
+	// Plugin jobs have their own enable switch and do not depend on any
+	// built-in task. Listing errors surface through ListPluginJobs.
+	_ = s.dispatchPluginJobs(ctx)
 }
 
 func (s *Service) shouldRun(cfg ScheduledTaskConfig) bool {
@@ -226,21 +277,30 @@ func (s *Service) shouldRun(cfg ScheduledTaskConfig) bool {
 }
 
 func (s *Service) executeTask(ctx context.Context, cfg ScheduledTaskConfig) {
+	s.startTracked(ctx, cfg.Key, func(ctx context.Context) (string, error) {
+		return "", s.runBuiltinTask(ctx, cfg.Key)
+	})
+}
+
+// startTracked runs fn in the background under the runtime state and run
+// history of key. It returns false when a run of key is already in flight.
+func (s *Service) startTracked(ctx context.Context, key string, fn func(ctx context.Context) (string, error)) bool {
 	s.mu.Lock()
-	rt := s.ensureRuntime(cfg.Key)
+	rt := s.ensureRuntime(key)
 	if rt.running {
 		s.mu.Unlock()
-		return
+		return false
 	}
 	rt.running = true
 	s.mu.Unlock()
 
 	go func() {
 		start := time.Now()
-		run := &domain.ScheduledTaskRun{TaskKey: cfg.Key, Status: "running", StartedAt: start}
+		run := &domain.ScheduledTaskRun{TaskKey: key, Status: "running", StartedAt: start}
 		if s.runs != nil {
 			_ = s.runs.CreateTaskRun(ctx, run)
 		}
+		var runMsg string
 		var runErr error
 		defer func() {
 			elapsed := int(time.Since(start).Seconds())
@@ -253,14 +313,16 @@ func (s *Service) executeTask(ctx context.Context, cfg ScheduledTaskConfig) {
 			rt.lastElapsed = elapsed
 			s.mu.Unlock()
 			status := "success"
-			msg := ""
+			msg := runMsg
+			errMsg := ""
 			if runErr != nil {
 				status = "failed"
 				msg = runErr.Error()
+				errMsg = msg
 			}
 			s.mu.Lock()
 			rt.lastStatus = status
-			rt.lastError = msg
+			rt.lastError = errMsg
 			s.mu.Unlock()
 			if s.runs != nil {
 				finish := time.Now()
@@ -271,52 +333,79 @@ func (s *Service) executeTask(ctx context.Context, cfg ScheduledTaskConfig) {
 				_ = s.runs.UpdateTaskRun(ctx, *run)
 			}
 		}()
-		switch cfg.Key {
-		case "vps_refresh":
-			if s.vps != nil {
-				_, runErr = s.vps.RefreshAll(ctx, 200)
-				if runErr == nil && s.orders != nil {
-					_, _ = s.orders.ReconcileProvisioningOrders(ctx, 50)
-				}
-			}
-		case "order_provision_watchdog":
-			if s.orders != nil {
-				runErr = s.orders.ProcessProvisionJobs(ctx, 50)
-			}
-		case "resize_task_runner":
-			if s.orders != nil {
-				runErr = s.orders.ProcessResizeTasks(ctx, 50)
-			}
-		case "expire_reminder":
-			if s.notify != nil {
-				runErr = s.notify.SendExpireReminders(ctx)
-			}
-		case "vps_expire_cleanup":
-			if s.vps != nil {
-				runErr = s.vps.AutoDeleteExpired(ctx)
-			}
-		case "vps_expire_lock":
-			if s.vps != nil {
-				runErr = s.vps.AutoLockExpired(ctx)
-			}
-		case "plugin_schedule":
-			if s.realname != nil {
-				_, runErr = s.realname.PollPending(ctx, 200)
-			}
-		case "user_tier_expire_reconcile":
-			if s.userTier != nil {
-				_, runErr = s.userTier.ReconcileExpired(ctx, 500)
-			}
-		case "integration_inventory_sync":
-			if s.integration != nil {
-				_, runErr = s.integration.SyncAutomationInventoryForGoodsType(ctx, 0)
-			}
-		case "log_retention_cleanup":
-			if s.logCleaner != nil {
-				_, runErr = s.logCleaner.Cleanup(ctx)
+		runMsg, runErr = fn(ctx)
+	}()
+	return true
+}
+
+func (s *Service) runBuiltinTask(ctx context.Context, key string) error {
+	var runErr error
+	switch key {
+	case "vps_refresh":
+		if s.vps != nil {
+			_, runErr = s.vps.RefreshAll(ctx, 200)
+			if runErr == nil && s.orders != nil {
+				_, _ = s.orders.ReconcileProvisioningOrders(ctx, 50)
 			}
 		}
-	}()
+	case "order_provision_watchdog":
+		if s.orders != nil {
+			runErr = s.orders.ProcessProvisionJobs(ctx, 50)
+		}
+	case "resize_task_runner":
+		if s.orders != nil {
+			runErr = s.orders.ProcessResizeTasks(ctx, 50)
+		}
+	case "expire_reminder":
+		if s.notify != nil {
+			runErr = s.notify.SendExpireReminders(ctx)
+		}
+	case "vps_expire_cleanup":
+		if s.vps != nil {
+			runErr = s.vps.AutoDeleteExpired(ctx)
+		}
+	case "vps_expire_lock":
+		if s.vps != nil {
+			runErr = s.vps.AutoLockExpired(ctx)
+		}
+	case "plugin_schedule":
+		if s.realname != nil {
+			_, runErr = s.realname.PollPending(ctx, 200)
+		}
+	case "user_tier_expire_reconcile":
+		if s.userTier != nil {
+			_, runErr = s.userTier.ReconcileExpired(ctx, 500)
+		}
+	case "integration_inventory_sync":
+		if s.integration != nil {
+			_, runErr = s.integration.SyncAutomationInventoryForGoodsType(ctx, 0)
+		}
+	case "log_retention_cleanup":
+		if s.logCleaner != nil {
+			_, runErr = s.logCleaner.Cleanup(ctx)
+		}
+	case "automation_backend_health":
+		if s.backends != nil {
+			_, runErr = s.backends.CheckBackendHealth(ctx)
+		}
+	case "vps_rescue_expire":
+		if s.rescue != nil {
+			_, runErr = s.rescue.ExpireSessions(ctx, 50)
+		}
+	case "vps_metrics_collect":
+		if s.metrics != nil {
+			_, runErr = s.metrics.Collect(ctx)
+		}
+	case "abuse_response_check":
+		if s.abuse != nil {
+			_, runErr = s.abuse.CheckResponses(ctx, 100)
+		}
+	case "vps_firewall_reconcile":
+		if s.firewall != nil {
+			_, runErr = s.firewall.ReconcilePending(ctx, 50)
+		}
+	}
+	return runErr
 }
 
 func (s *Service) ensureRuntime(key string) *taskRuntime {
@@ -512,7 +601,7 @@ func defaultTaskDefinitions() map[string]ScheduledTaskConfig {
 		"plugin_schedule": {
 			Key:         "plugin_schedule",
 			Name:        "Plugin Schedule",
-			Description: "Process scheduled tasks registered by plugins.",
+			Description: "Poll pending real-name checks.",
 			Enabled:     true,
 			Strategy:    TaskStrategyInterval,
 			IntervalSec: 20,
@@ -541,5 +630,45 @@ func defaultTaskDefinitions() map[string]ScheduledTaskConfig {
 			Strategy:    TaskStrategyDaily,
 			DailyAt:     "03:30",
 		},
+		"automation_backend_health": {
+			Key:         "automation_backend_health",
+			Name:        "Automation Backend Health",
+			Description: "Probe pooled automation backends and update their placement health.",
+			Enabled:     true,
+			Strategy:    TaskStrategyInterval,
+			IntervalSec: 120,
+		},
+		"vps_rescue_expire": {
+			Key:         "vps_rescue_expire",
+			Name:        "VPS Rescue Expire",
+			Description: "Boot instances out of rescue mode once their rescue session times out.",
+			Enabled:     true,
+			Strategy:    TaskStrategyInterval,
+			IntervalSec: 60,
+		},
+		"vps_metrics_collect": {
+			Key:         "vps_metrics_collect",
+			Name:        "VPS Metrics Collect",
+			Description: "Sample CPU, memory, disk and network of running instances into the monitoring history.",
+			Enabled:     true,
+			Strategy:    TaskStrategyInterval,
+			IntervalSec: 60,
+		},
+		"abuse_response_check": {
+			Key:         "abuse_response_check",
+			Name:        "Abuse Response Check",
+			Description: "Return warned abuse cases to the desk once the user replies or the response window ends.",
+			Enabled:     true,
+			Strategy:    TaskStrategyInterval,
+			IntervalSec: 300,
+		},
+		"vps_firewall_reconcile": {
+			Key:         "vps_firewall_reconcile",
+			Name:        "VPS Firewall Reconcile",
+			Description: "Apply pending firewall template changes and retry instances whose last sync failed.",
+			Enabled:     true,
+			Strategy:    TaskStrategyInterval,
+			IntervalSec: 120,
+		},
 	}
 }
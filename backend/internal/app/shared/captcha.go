@@ -0,0 +1,24 @@
+package shared
+
+// Captcha scenes that can each pick their own provider.
+const (
+	CaptchaSceneRegister      = "register"
+	CaptchaSceneLogin         = "login"
+	CaptchaScenePasswordReset = "password_reset"
+	CaptchaSceneOrderSubmit   = "order_submit"
+)
+
+// CaptchaChallenge is what a captcha plugin hands to the front end to render
+// its widget. Params are public and sent to browsers unchanged.
+type CaptchaChallenge struct {
+	Widget string
+	Params map[string]string
+}
+
+// CaptchaAnswer is the widget output submitted with a protected request.
+type CaptchaAnswer struct {
+	Scene    string
+	Token    string
+	Params   map[string]string
+	RemoteIP string
+}
@@ -0,0 +1,157 @@
+package shared
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule is a parsed five field cron expression (minute hour
+// day-of-month month day-of-week). Fields accept *, single values, ranges,
+// lists and /step; day-of-week 0 and 7 are both Sunday. When both day fields
+// are restricted a time matches if either does, as in classic cron.
+type CronSchedule struct {
+	minute   [60]bool
+	hour     [24]bool
+	dom      [32]bool
+	month    [13]bool
+	dow      [7]bool
+	domStar  bool
+	dowStar  bool
+	original string
+}
+
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+func ParseCron(expr string) (CronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	spec := expr
+	if v, ok := cronDescriptors[strings.ToLower(expr)]; ok {
+		spec = v
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return CronSchedule{}, ErrInvalidInput
+	}
+	c := CronSchedule{original: expr}
+	if err := parseCronField(fields[0], 0, 59, c.minute[:]); err != nil {
+		return CronSchedule{}, err
+	}
+	if err := parseCronField(fields[1], 0, 23, c.hour[:]); err != nil {
+		return CronSchedule{}, err
+	}
+	if err := parseCronField(fields[2], 1, 31, c.dom[:]); err != nil {
+		return CronSchedule{}, err
+	}
+	if err := parseCronField(fields[3], 1, 12, c.month[:]); err != nil {
+		return CronSchedule{}, err
+	}
+	var dow [8]bool
+	if err := parseCronField(fields[4], 0, 7, dow[:]); err != nil {
+		return CronSchedule{}, err
+	}
+	copy(c.dow[:], dow[:7])
+	if dow[7] {
+		c.dow[0] = true
+	}
+	c.domStar = fields[2] == "*"
+	c.dowStar = fields[4] == "*"
+	return c, nil
+}
+
+func (c CronSchedule) String() string {
+	return c.original
+}
+
+// Next returns the first matching minute strictly after t, or the zero time
+// when nothing matches within five years.
+func (c CronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if !c.month[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.hour[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if !c.minute[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (c CronSchedule) dayMatches(t time.Time) bool {
+	dom := c.dom[t.Day()]
+	dow := c.dow[int(t.Weekday())]
+	switch {
+	case c.domStar && c.dowStar:
+		return true
+	case c.domStar:
+		return dow
+	case c.dowStar:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+func parseCronField(field string, min, max int, out []bool) error {
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return ErrInvalidInput
+			}
+			step = n
+			part = part[:i]
+		}
+		lo, hi := min, max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			a, err1 := strconv.Atoi(bounds[0])
+			b, err2 := strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil || a > b {
+				return ErrInvalidInput
+			}
+			lo, hi = a, b
+		default:
+			v, err := strconv.Atoi(part)
+			if err != nil {
+				return ErrInvalidInput
+			}
+			lo = v
+			if step == 1 {
+				hi = v
+			}
+		}
+		if lo < min || hi > max {
+			return ErrInvalidInput
+		}
+		for v := lo; v <= hi; v += step {
+			out[v] = true
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package shared_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	appshared "xiaoheiplay/internal/app/shared"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr bool
+	}{
+		{name: "every minute", expr: "* * * * *"},
+		{name: "lists ranges and steps", expr: "0,30 9-17/2 */5 1-6 1-5"},
+		{name: "sunday as seven", expr: "0 0 * * 7"},
+		{name: "descriptor", expr: "@daily"},
+		{name: "descriptor upper case", expr: "@WEEKLY"},
+		{name: "surrounding space", expr: "  0 0 1 1 *  "},
+		{name: "empty", expr: "", wantErr: true},
+		{name: "four fields", expr: "* * * *", wantErr: true},
+		{name: "six fields", expr: "0 * * * * *", wantErr: true},
+		{name: "minute out of range", expr: "60 * * * *", wantErr: true},
+		{name: "hour out of range", expr: "0 24 * * *", wantErr: true},
+		{name: "day zero", expr: "0 0 0 * *", wantErr: true},
+		{name: "month out of range", expr: "0 0 1 13 *", wantErr: true},
+		{name: "weekday out of range", expr: "0 0 * * 8", wantErr: true},
+		{name: "reversed range", expr: "5-1 * * * *", wantErr: true},
+		{name: "zero step", expr: "*/0 * * * *", wantErr: true},
+		{name: "not a number", expr: "a * * * *", wantErr: true},
+		{name: "unknown descriptor", expr: "@reboot", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sched, err := appshared.ParseCron(tt.expr)
+			if tt.wantErr {
+				if !errors.Is(err, appshared.ErrInvalidInput) {
+					t.Fatalf("expected invalid input, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parse %q: %v", tt.expr, err)
+			}
+			if sched.String() == "" {
+				t.Fatalf("expected the expression to be kept")
+			}
+		})
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	// 2026-03-10 is a Tuesday.
+	base := time.Date(2026, 3, 10, 10, 7, 30, 0, time.UTC)
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{name: "minute step", expr: "*/15 * * * *", from: base, want: at(3, 10, 10, 15)},
+		{name: "hour range with step", expr: "0 9-17/4 * * 1-5", from: base, want: at(3, 10, 13, 0)},
+		{name: "strictly after", expr: "0 12 * * *", from: at(3, 10, 12, 0), want: at(3, 11, 12, 0)},
+		{name: "month rollover", expr: "30 8 1 * *", from: base, want: at(4, 1, 8, 30)},
+		{name: "sunday as seven", expr: "0 0 * * 7", from: base, want: at(3, 15, 0, 0)},
+		{name: "sunday as zero", expr: "0 0 * * 0", from: base, want: at(3, 15, 0, 0)},
+		{name: "day of month before weekday", expr: "0 0 13 * 1", from: base, want: at(3, 13, 0, 0)},
+		{name: "weekday before day of month", expr: "0 0 20 * 1", from: base, want: at(3, 16, 0, 0)},
+		{name: "stepped day of month is restricted", expr: "0 0 */10 * 1", from: base, want: at(3, 11, 0, 0)},
+		{name: "hourly", expr: "@hourly", from: base, want: at(3, 10, 11, 0)},
+		{name: "weekly", expr: "@weekly", from: base, want: at(3, 15, 0, 0)},
+		{name: "yearly", expr: "@yearly", from: base, want: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "never matches", expr: "0 0 31 2 *", from: base, want: time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sched, err := appshared.ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("parse %q: %v", tt.expr, err)
+			}
+			if got := sched.Next(tt.from); !got.Equal(tt.want) {
+				t.Fatalf("next after %s: got %s, want %s", tt.from, got, tt.want)
+			}
+		})
+	}
+}
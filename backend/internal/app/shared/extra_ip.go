@@ -0,0 +1,59 @@
+package shared
+
+import (
+	"context"
+
+	"xiaoheiplay/internal/domain"
+)
+
+// ExtraIPMonthly is the monthly price of the extra IP add-ons in the spec.
+// IP add-ons are not subject to user tier discounts.
+func (s CartSpec) ExtraIPMonthly(plan domain.PlanGroup) int64 {
+	return int64(s.AddIPv4)*plan.UnitIPv4 + int64(s.AddIPv6)*plan.UnitIPv6
+}
+
+// ValidateExtraIPs checks extra IP counts against the plan group limits.
+func ValidateExtraIPs(ipv4, ipv6 int, plan domain.PlanGroup) error {
+	if ipv4 < 0 || ipv6 < 0 || ipv4 > plan.AddIPv4Max || ipv6 > plan.AddIPv6Max {
+		return ErrInvalidInput
+	}
+	return nil
+}
+
+// ExtraIPQuote prices additional addresses for an existing instance. The
+// monthly delta joins the renewal price; the charge covers the rest of the
+// current billing period.
+type ExtraIPQuote struct {
+	VPSID        int64 `json:"vps_id"`
+	AddIPv4      int   `json:"add_ipv4"`
+	AddIPv6      int   `json:"add_ipv6"`
+	TargetIPv4   int   `json:"target_ipv4"`
+	TargetIPv6   int   `json:"target_ipv6"`
+	MonthlyDelta int64 `json:"monthly_delta"`
+	ChargeAmount int64 `json:"charge_amount"`
+}
+
+// ExtraIPStore is implemented by VPS repositories that record add-on IPs.
+type ExtraIPStore interface {
+	ListVPSExtraIPs(ctx context.Context, vpsID int64) ([]domain.VPSExtraIP, error)
+	DeleteVPSExtraIP(ctx context.Context, id int64) error
+}
+
+// ReleaseExtraIPs hands the add-on IPs of an instance back to the provider
+// before its host is deleted. It is best effort: providers also reclaim
+// attached addresses with the host, and deleting the instance drops any
+// records left behind.
+func ReleaseExtraIPs(ctx context.Context, ips ExtraIPStore, cli AutomationClient, hostID, vpsID int64) {
+	if ips == nil || cli == nil || hostID == 0 {
+		return
+	}
+	items, err := ips.ListVPSExtraIPs(ctx, vpsID)
+	if err != nil {
+		return
+	}
+	for _, item := range items {
+		if err := cli.ReleaseIP(ctx, hostID, item.Address); err == nil {
+			_ = ips.DeleteVPSExtraIP(ctx, item.ID)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package shared
+
+// NotifyMessage is sent through a notify plugin. Target is channel specific
+// (chat ID, robot webhook URL); empty uses the instance default.
+type NotifyMessage struct {
+	Title    string
+	Body     string
+	Markdown bool
+	Target   string
+	Event    string
+}
+
+type NotifyDelivery struct {
+	MessageID string
+}
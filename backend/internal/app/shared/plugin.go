@@ -21,6 +21,19 @@ type PluginKYCCapability struct {
 	QueryResult bool `json:"query_result"`
 }
 
+type PluginNotifyCapability struct {
+	Send     bool `json:"send"`
+	Markdown bool `json:"markdown,omitempty"`
+}
+
+type PluginStorageCapability struct {
+	Presign bool `json:"presign,omitempty"`
+}
+
+type PluginCaptchaCapability struct {
+	Widget string `json:"widget"`
+}
+
 type PluginAutomationCapability struct {
 	Features            []string          `json:"features"`
 	NotSupportedReasons map[string]string `json:"not_supported_reasons,omitempty"`
@@ -32,15 +45,21 @@ type PluginCapabilities struct {
 	Payment    *PluginPaymentCapability    `json:"payment,omitempty"`
 	KYC        *PluginKYCCapability        `json:"kyc,omitempty"`
 	Automation *PluginAutomationCapability `json:"automation,omitempty"`
+	Notify     *PluginNotifyCapability     `json:"notify,omitempty"`
+	Storage    *PluginStorageCapability    `json:"storage,omitempty"`
+	Captcha    *PluginCaptchaCapability    `json:"captcha,omitempty"`
 }
 
 type PluginManifest struct {
-	PluginID     string             `json:"plugin_id"`
-	Name         string             `json:"name"`
-	Version      string             `json:"version"`
-	Description  string             `json:"description,omitempty"`
-	Binaries     map[string]string  `json:"binaries,omitempty"`
-	Capabilities PluginCapabilities `json:"capabilities"`
+	PluginID     string                `json:"plugin_id"`
+	Name         string                `json:"name"`
+	Version      string                `json:"version"`
+	Description  string                `json:"description,omitempty"`
+	Binaries     map[string]string     `json:"binaries,omitempty"`
+	Jobs         []PluginJobDefinition `json:"jobs,omitempty"`
+	Permissions  []string              `json:"permissions,omitempty"`
+	Hooks        []string              `json:"hooks,omitempty"`
+	Capabilities PluginCapabilities    `json:"capabilities"`
 }
 
 type PluginEntryInfo struct {
@@ -64,6 +83,7 @@ type PluginListItem struct {
 	LastHealthAt    *time.Time                   `json:"last_health_at"`
 	HealthStatus    string                       `json:"health_status"`
 	HealthMessage   string                       `json:"health_message"`
+	Runtime         *PluginRuntimeStatus         `json:"runtime,omitempty"`
 	Capabilities    PluginManifest               `json:"manifest"`
 	Entry           PluginEntryInfo              `json:"entry"`
 }
@@ -77,6 +97,22 @@ type PluginDiscoverItem struct {
 	Entry           PluginEntryInfo              `json:"entry"`
 }
 
+// PluginRepositoryItem is the newest version of a plugin offered by the
+// configured repository indexes.
+type PluginRepositoryItem struct {
+	Category         string `json:"category"`
+	PluginID         string `json:"plugin_id"`
+	Name             string `json:"name"`
+	Description      string `json:"description,omitempty"`
+	Version          string `json:"version"`
+	Source           string `json:"source"`
+	Installed        bool   `json:"installed"`
+	InstalledVersion string `json:"installed_version,omitempty"`
+	UpdateAvailable  bool   `json:"update_available"`
+	Verified         bool   `json:"verified"`
+	VerifyError      string `json:"verify_error,omitempty"`
+}
+
 type PluginPaymentMethodState struct {
 	Method  string `json:"method"`
 	Enabled bool   `json:"enabled"`
@@ -0,0 +1,41 @@
+package shared
+
+import "strings"
+
+// Events a plugin subscribes to in manifest.json "hooks" and receives through
+// HookService.OnEvent.
+const (
+	PluginHookOrderCreated   = "order.created"
+	PluginHookOrderPaid      = "order.paid"
+	PluginHookOrderActive    = "order.active"
+	PluginHookVPSExpired     = "vps.expired"
+	PluginHookUserRegistered = "user.registered"
+	PluginHookTicketCreated  = "ticket.created"
+)
+
+func IsPluginHookEvent(event string) bool {
+	switch strings.TrimSpace(event) {
+	case PluginHookOrderCreated, PluginHookOrderPaid, PluginHookOrderActive,
+		PluginHookVPSExpired, PluginHookUserRegistered, PluginHookTicketCreated:
+		return true
+	default:
+		return false
+	}
+}
+
+// PluginHookSubscriber is an enabled plugin instance subscribed to an event.
+type PluginHookSubscriber struct {
+	Category   string
+	PluginID   string
+	InstanceID string
+}
+
+type PluginHookDeliveryFilter struct {
+	Category   string
+	PluginID   string
+	InstanceID string
+	Status     string
+	Event      string
+	Limit      int
+	Offset     int
+}
@@ -0,0 +1,35 @@
+package shared
+
+import "strings"
+
+// Scopes a plugin declares in manifest.json "permissions" to reach host data
+// through HostService. Logging and the instance KV store need no scope.
+const (
+	PluginScopeOrderRead  = "order:read"
+	PluginScopeUserRead   = "user:read"
+	PluginScopeNotifyUser = "notify:user"
+)
+
+const (
+	PluginKVMaxKeyLen     = 191
+	PluginKVMaxValueBytes = 64 << 10
+	PluginKVMaxKeys       = 1000
+)
+
+func IsPluginScope(scope string) bool {
+	switch strings.TrimSpace(scope) {
+	case PluginScopeOrderRead, PluginScopeUserRead, PluginScopeNotifyUser:
+		return true
+	default:
+		return false
+	}
+}
+
+type PluginLogFilter struct {
+	Category   string
+	PluginID   string
+	InstanceID string
+	Level      string
+	Limit      int
+	Offset     int
+}
@@ -0,0 +1,81 @@
+package shared
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	PluginJobsSettingKey       = "plugin_jobs_json"
+	PluginJobDefaultTimeoutSec = 60
+	PluginJobMaxTimeoutSec     = 3600
+	pluginJobMinIntervalSec    = 30
+)
+
+var pluginJobNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,63}$`)
+
+// PluginJobDefinition is a job a plugin declares in its manifest. Exactly one
+// of IntervalSec or Cron is set.
+type PluginJobDefinition struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	IntervalSec int    `json:"interval_sec,omitempty"`
+	Cron        string `json:"cron,omitempty"`
+	TimeoutSec  int    `json:"timeout_sec,omitempty"`
+}
+
+func (d PluginJobDefinition) Validate() error {
+	if !pluginJobNamePattern.MatchString(d.Name) {
+		return ErrInvalidInput
+	}
+	hasCron := strings.TrimSpace(d.Cron) != ""
+	if hasCron == (d.IntervalSec > 0) {
+		return ErrInvalidInput
+	}
+	if d.IntervalSec > 0 && d.IntervalSec < pluginJobMinIntervalSec {
+		return ErrInvalidInput
+	}
+	if hasCron {
+		if _, err := ParseCron(d.Cron); err != nil {
+			return err
+		}
+	}
+	if d.TimeoutSec < 0 || d.TimeoutSec > PluginJobMaxTimeoutSec {
+		return ErrInvalidInput
+	}
+	return nil
+}
+
+func (d PluginJobDefinition) Timeout() time.Duration {
+	if d.TimeoutSec <= 0 {
+		return PluginJobDefaultTimeoutSec * time.Second
+	}
+	return time.Duration(d.TimeoutSec) * time.Second
+}
+
+// PluginJob is a declared job bound to one plugin instance.
+type PluginJob struct {
+	Category   string              `json:"category"`
+	PluginID   string              `json:"plugin_id"`
+	InstanceID string              `json:"instance_id"`
+	Definition PluginJobDefinition `json:"definition"`
+}
+
+// Key identifies the job in settings and in the scheduled task run history.
+func (j PluginJob) Key() string {
+	return "plugin:" + j.Category + ":" + j.PluginID + ":" + j.InstanceID + ":" + j.Definition.Name
+}
+
+// PluginJobStatus is a plugin job as shown to admins.
+type PluginJobStatus struct {
+	Key string `json:"key"`
+	PluginJob
+	Enabled     bool       `json:"enabled"`
+	Running     bool       `json:"running"`
+	LastRunAt   *time.Time `json:"last_run_at,omitempty"`
+	NextRunAt   *time.Time `json:"next_run_at,omitempty"`
+	LastStatus  string     `json:"last_status,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastElapsed int        `json:"last_elapsed_sec,omitempty"`
+}
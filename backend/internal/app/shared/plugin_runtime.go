@@ -0,0 +1,60 @@
+package shared
+
+import "time"
+
+// Supervision states of a plugin instance process.
+const (
+	PluginRuntimeRunning    = "running"
+	PluginRuntimeRestarting = "restarting"
+	PluginRuntimeDegraded   = "degraded"
+)
+
+// Runtime events raised by the plugin supervisor.
+const (
+	PluginRuntimeEventCrashed   = "plugin.crashed"
+	PluginRuntimeEventDegraded  = "plugin.degraded"
+	PluginRuntimeEventRecovered = "plugin.recovered"
+)
+
+// PluginRuntimeStatus is the supervision state of a started instance.
+// Restarts counts successful restarts; Failures counts crashes and failed
+// restarts since the instance last ran stably.
+type PluginRuntimeStatus struct {
+	State          string                 `json:"state"`
+	PID            int                    `json:"pid,omitempty"`
+	StartedAt      *time.Time             `json:"started_at,omitempty"`
+	Restarts       int                    `json:"restarts"`
+	Failures       int                    `json:"failures"`
+	LastError      string                 `json:"last_error,omitempty"`
+	LastExitAt     *time.Time             `json:"last_exit_at,omitempty"`
+	NextRestartAt  *time.Time             `json:"next_restart_at,omitempty"`
+	MemoryRSSBytes uint64                 `json:"memory_rss_bytes"`
+	CPUPercent     float64                `json:"cpu_percent"`
+	SampledAt      *time.Time             `json:"sampled_at,omitempty"`
+	RPC            []PluginRPCMethodStats `json:"rpc,omitempty"`
+}
+
+// PluginRPCMethodStats counts host-to-plugin calls of one gRPC method.
+type PluginRPCMethodStats struct {
+	Method       string     `json:"method"`
+	Calls        int64      `json:"calls"`
+	Errors       int64      `json:"errors"`
+	AvgLatencyMs float64    `json:"avg_latency_ms"`
+	MaxLatencyMs float64    `json:"max_latency_ms"`
+	LastError    string     `json:"last_error,omitempty"`
+	LastCallAt   *time.Time `json:"last_call_at,omitempty"`
+}
+
+// PluginRuntimeEvent reports a crash, a tripped circuit breaker or a recovery
+// of a supervised instance.
+type PluginRuntimeEvent struct {
+	Type          string     `json:"event"`
+	Category      string     `json:"category"`
+	PluginID      string     `json:"plugin_id"`
+	InstanceID    string     `json:"instance_id"`
+	Error         string     `json:"error,omitempty"`
+	Failures      int        `json:"failures"`
+	Restarts      int        `json:"restarts"`
+	NextRestartAt *time.Time `json:"next_restart_at,omitempty"`
+	OccurredAt    time.Time  `json:"occurred_at"`
+}
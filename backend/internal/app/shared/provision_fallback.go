@@ -0,0 +1,62 @@
+package shared
+
+import "strings"
+
+const PlanGroupProvisionFallbackSettingKey = "plan_group_provision_fallback_json"
+
+// ProvisionFallbackPolicy says what to do when creating a host for a package
+// of the plan group fails. ErrorPatterns are matched case-insensitively
+// against the upstream error; an empty list matches every create failure.
+type ProvisionFallbackPolicy struct {
+	ErrorPatterns      []string `json:"error_patterns,omitempty"`
+	SiblingLines       bool     `json:"sibling_lines"`
+	FallbackPackageIDs []int64  `json:"fallback_package_ids,omitempty"`
+	AutoRefund         bool     `json:"auto_refund"`
+}
+
+func (p ProvisionFallbackPolicy) Empty() bool {
+	return !p.SiblingLines && len(p.FallbackPackageIDs) == 0 && !p.AutoRefund
+}
+
+func (p ProvisionFallbackPolicy) Validate() error {
+	if len(p.ErrorPatterns) > 20 || len(p.FallbackPackageIDs) > 10 {
+		return ErrInvalidInput
+	}
+	for _, pattern := range p.ErrorPatterns {
+		if strings.TrimSpace(pattern) == "" || len(pattern) > 128 {
+			return ErrInvalidInput
+		}
+	}
+	for _, id := range p.FallbackPackageIDs {
+		if id <= 0 {
+			return ErrInvalidInput
+		}
+	}
+	return nil
+}
+
+// Matches reports whether err should trigger the fallback.
+func (p ProvisionFallbackPolicy) Matches(err error) bool {
+	if err == nil {
+		return false
+	}
+	if len(p.ErrorPatterns) == 0 {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, pattern := range p.ErrorPatterns {
+		if strings.Contains(msg, strings.ToLower(strings.TrimSpace(pattern))) {
+			return true
+		}
+	}
+	return false
+}
+
+// ProvisionAttemptFilter selects provision attempts for the admin watchdog
+// view. Failed limits the result to failed attempts.
+type ProvisionAttemptFilter struct {
+	OrderID int64
+	Failed  bool
+	Limit   int
+	Offset  int
+}
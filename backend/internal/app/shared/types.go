@@ -12,9 +12,18 @@ type CartSpec struct {
 	AddMemGB       int   `json:"add_mem_gb"`
 	AddDiskGB      int   `json:"add_disk_gb"`
 	AddBWMbps      int   `json:"add_bw_mbps"`
+	AddIPv4        int   `json:"add_ipv4,omitempty"`
+	AddIPv6        int   `json:"add_ipv6,omitempty"`
 	BillingCycleID int64 `json:"billing_cycle_id"`
 	CycleQty       int   `json:"cycle_qty"`
 	DurationMonths int   `json:"duration_months"`
+	// Guest initialization chosen at checkout. Keys reference the user's saved
+	// SSH keys and are resolved when the host is created. User data and the
+	// startup script are removed from the order item once the host exists.
+	SSHKeyIDs     []int64 `json:"ssh_key_ids,omitempty"`
+	UserData      string  `json:"user_data,omitempty"`
+	StartupScript string  `json:"startup_script,omitempty"`
+	KeyOnly       bool    `json:"key_only,omitempty"`
 }
 
 type RegisterInput struct {
@@ -78,6 +87,17 @@ type TicketFilter struct {
 	Offset  int
 }
 
+// AbuseCaseFilter selects abuse cases. Active limits the result to the desk
+// queue (open, investigating and awaiting_user) ordered by resolve deadline.
+type AbuseCaseFilter struct {
+	Status     string
+	Active     bool
+	TargetType string
+	TargetID   int64
+	Limit      int
+	Offset     int
+}
+
 type CMSPostFilter struct {
 	CategoryID    *int64
 	CategoryKey   string
@@ -220,6 +240,34 @@ type AutomationCreateHostRequest struct {
 	PortNum    int
 	Snapshot   int
 	Backups    int
+	Init       AutomationGuestInit
+}
+
+// AutomationGuestInit is first-boot customization passed to create and
+// reinstall. Backends must declare the cloud_init feature to receive it.
+type AutomationGuestInit struct {
+	SSHKeys         []string
+	UserData        string
+	StartupScript   string
+	DisablePassword bool
+}
+
+// MaxGuestUserDataBytes matches the smallest user data limit among common
+// cloud-init datasources.
+const MaxGuestUserDataBytes = 16 * 1024
+
+func (g AutomationGuestInit) Empty() bool {
+	return len(g.SSHKeys) == 0 && g.UserData == "" && g.StartupScript == "" && !g.DisablePassword
+}
+
+func (g AutomationGuestInit) Validate() error {
+	if len(g.UserData) > MaxGuestUserDataBytes || len(g.StartupScript) > MaxGuestUserDataBytes {
+		return domain.ErrUserDataTooLarge
+	}
+	if g.DisablePassword && len(g.SSHKeys) == 0 {
+		return domain.ErrKeyOnlyRequiresSSHKey
+	}
+	return nil
 }
 
 type AutomationCreateHostResult struct {
@@ -240,6 +288,14 @@ type AutomationHostInfo struct {
 	OSPassword    string
 	RemoteIP      string
 	ExpireAt      *time.Time
+	// Task is the progress of a running rebuild or restore, nil when the
+	// plugin does not report one.
+	Task *AutomationTaskProgress
+}
+
+type AutomationTaskProgress struct {
+	Percent int
+	Message string
 }
 
 type AutomationHostSimple struct {
@@ -311,6 +367,18 @@ type AutomationFirewallRuleCreate struct {
 	Priority  int
 }
 
+// AutomationReverseDNSRecord is the PTR of one instance IP; PTR is empty when unset.
+type AutomationReverseDNSRecord struct {
+	IP  string `json:"ip"`
+	PTR string `json:"ptr"`
+}
+
+// AutomationRescueCredentials are the one-time login of the rescue system.
+type AutomationRescueCredentials struct {
+	Username string
+	Password string
+}
+
 type AutomationPortMappingCreate struct {
 	HostID int64
 	Name   string
@@ -330,7 +398,7 @@ type AutomationClient interface {
 	StartHost(ctx context.Context, hostID int64) error
 	ShutdownHost(ctx context.Context, hostID int64) error
 	RebootHost(ctx context.Context, hostID int64) error
-	ResetOS(ctx context.Context, hostID int64, templateID int64, password string) error
+	ResetOS(ctx context.Context, hostID int64, templateID int64, password string, guest AutomationGuestInit) error
 	ResetOSPassword(ctx context.Context, hostID int64, password string) error
 	ListSnapshots(ctx context.Context, hostID int64) ([]AutomationSnapshot, error)
 	CreateSnapshot(ctx context.Context, hostID int64) error
@@ -343,6 +411,14 @@ type AutomationClient interface {
 	ListFirewallRules(ctx context.Context, hostID int64) ([]AutomationFirewallRule, error)
 	AddFirewallRule(ctx context.Context, req AutomationFirewallRuleCreate) error
 	DeleteFirewallRule(ctx context.Context, hostID int64, ruleID int64) error
+	ListReverseDNS(ctx context.Context, hostID int64) ([]AutomationReverseDNSRecord, error)
+	SetReverseDNS(ctx context.Context, hostID int64, ip, ptr string) error
+	AssignIP(ctx context.Context, hostID int64, family string) (string, error)
+	ReleaseIP(ctx context.Context, hostID int64, ip string) error
+	EnterRescue(ctx context.Context, hostID int64, password string) (AutomationRescueCredentials, error)
+	ExitRescue(ctx context.Context, hostID int64) error
+	MountISO(ctx context.Context, hostID int64, iso string) error
+	UnmountISO(ctx context.Context, hostID int64) error
 	ListPortMappings(ctx context.Context, hostID int64) ([]AutomationPortMapping, error)
 	AddPortMapping(ctx context.Context, req AutomationPortMappingCreate) error
 	DeletePortMapping(ctx context.Context, hostID int64, mappingID int64) error
@@ -560,12 +636,17 @@ type SystemInfoProvider interface {
 	Status(ctx context.Context) (ServerStatus, error)
 }
 
+// RobotWebhookConfig is one robot notification target. With PluginID set the
+// event is sent through that notify plugin and URL is passed as its target
+// (chat ID or robot webhook); otherwise the event is POSTed to URL as JSON.
 type RobotWebhookConfig struct {
-	Name    string   `json:"name"`
-	URL     string   `json:"url"`
-	Secret  string   `json:"secret"`
-	Enabled bool     `json:"enabled"`
-	Events  []string `json:"events"`
+	Name       string   `json:"name"`
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret"`
+	Enabled    bool     `json:"enabled"`
+	Events     []string `json:"events"`
+	PluginID   string   `json:"plugin_id,omitempty"`
+	InstanceID string   `json:"instance_id,omitempty"`
 }
 
 const (
@@ -573,3 +654,13 @@ const (
 	CodeComplexityLetters = "letters"
 	CodeComplexityAlnum   = "alnum"
 )
+
+// ConsoleSessionFilter selects console sessions for the admin log. Open
+// limits the result to pending and active sessions.
+type ConsoleSessionFilter struct {
+	VPSID  int64
+	UserID int64
+	Open   bool
+	Limit  int
+	Offset int
+}
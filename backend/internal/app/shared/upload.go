@@ -0,0 +1,18 @@
+package shared
+
+// UploadObject tells how to serve /uploads/<key>. Local objects are read
+// from the local disk by the caller; otherwise RedirectURL is a presigned
+// link or Content holds the object.
+type UploadObject struct {
+	Local       bool
+	RedirectURL string
+	Content     []byte
+	ContentType string
+}
+
+// UploadMigrationResult counts the uploads moved between storage backends.
+// Missing uploads had no object in the source backend and were left as is.
+type UploadMigrationResult struct {
+	Moved   int
+	Missing int
+}
@@ -0,0 +1,116 @@
+package shared
+
+import (
+	"context"
+	"time"
+	"unicode/utf8"
+
+	"xiaoheiplay/internal/domain"
+)
+
+const (
+	VPSActivityStart           = "start"
+	VPSActivityShutdown        = "shutdown"
+	VPSActivityReboot          = "reboot"
+	VPSActivityResetOS         = "reset_os"
+	VPSActivityResetPassword   = "reset_password"
+	VPSActivityEmergencyRenew  = "emergency_renew"
+	VPSActivitySnapshotCreate  = "snapshot_create"
+	VPSActivitySnapshotDelete  = "snapshot_delete"
+	VPSActivitySnapshotRestore = "snapshot_restore"
+	VPSActivityBackupCreate    = "backup_create"
+	VPSActivityBackupDelete    = "backup_delete"
+	VPSActivityBackupRestore   = "backup_restore"
+	VPSActivityFirewallAdd     = "firewall_add"
+	VPSActivityFirewallDelete  = "firewall_delete"
+	VPSActivityPortAdd         = "port_mapping_add"
+	VPSActivityPortDelete      = "port_mapping_delete"
+	VPSActivityAutoLock        = "auto_lock"
+	VPSActivityAdminStatus     = "admin_status"
+	VPSActivityResize          = "resize"
+	VPSActivityExpireChange    = "expire_change"
+
+	// VPSActivityExportLimit caps the rows of one activity export.
+	VPSActivityExportLimit = 5000
+)
+
+// VPSActivityFilter selects entries of an instance activity feed. From is
+// inclusive and To exclusive.
+type VPSActivityFilter struct {
+	VPSID     int64
+	Action    string
+	ActorType domain.VPSActivityActorType
+	Result    domain.VPSActivityResult
+	From      *time.Time
+	To        *time.Time
+	Limit     int
+	Offset    int
+}
+
+// VPSActivityActor identifies who triggered an instance action. Requests set
+// it on the context; actions without one are attributed to the scheduler.
+type VPSActivityActor struct {
+	Type domain.VPSActivityActorType
+	ID   int64
+	IP   string
+}
+
+type vpsActivityActorKey struct{}
+
+func WithVPSActivityActor(ctx context.Context, actor VPSActivityActor) context.Context {
+	return context.WithValue(ctx, vpsActivityActorKey{}, actor)
+}
+
+func GetVPSActivityActor(ctx context.Context) (VPSActivityActor, bool) {
+	value := ctx.Value(vpsActivityActorKey{})
+	if value == nil {
+		return VPSActivityActor{}, false
+	}
+	actor, ok := value.(VPSActivityActor)
+	return actor, ok
+}
+
+// VPSActivityWriter is implemented by repositories that store the activity
+// feed.
+type VPSActivityWriter interface {
+	CreateVPSActivity(ctx context.Context, activity *domain.VPSActivity) error
+}
+
+// RecordVPSActivity appends an entry to the activity feed of inst. It is best
+// effort: a failed write never fails the action itself.
+func RecordVPSActivity(ctx context.Context, w VPSActivityWriter, inst domain.VPSInstance, action, detail string, actionErr error) {
+	if w == nil || inst.ID <= 0 {
+		return
+	}
+	actor, ok := GetVPSActivityActor(ctx)
+	if !ok {
+		actor = VPSActivityActor{Type: domain.VPSActivityActorScheduler}
+	}
+	entry := domain.VPSActivity{
+		VPSID:     inst.ID,
+		UserID:    inst.UserID,
+		ActorType: actor.Type,
+		ActorID:   actor.ID,
+		Action:    action,
+		Detail:    clipActivityText(detail),
+		IP:        actor.IP,
+		Result:    domain.VPSActivityResultSuccess,
+	}
+	if actionErr != nil {
+		entry.Result = domain.VPSActivityResultFailed
+		entry.ErrorMessage = clipActivityText(actionErr.Error())
+	}
+	_ = w.CreateVPSActivity(ctx, &entry)
+}
+
+func clipActivityText(s string) string {
+	const max = 255
+	if len(s) <= max {
+		return s
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut]
+}
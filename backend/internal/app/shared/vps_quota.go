@@ -0,0 +1,81 @@
+package shared
+
+import "time"
+
+const (
+	PackageQuotaSettingKey   = "package_action_quotas_json"
+	GoodsTypeQuotaSettingKey = "goods_type_action_quotas_json"
+
+	// MaxQuotaCooldownMinutes keeps cooldowns within the usage retention.
+	MaxQuotaCooldownMinutes = 7 * 24 * 60
+)
+
+// Quota-limited VPS actions.
+const (
+	QuotaResetOS       = "reset_os"
+	QuotaResetPassword = "reset_password"
+	QuotaSnapshots     = "snapshots"
+	QuotaBackups       = "backups"
+	QuotaPortMappings  = "port_mappings"
+	QuotaFirewallRules = "firewall_rules"
+)
+
+// VPSQuotaPolicy holds the action limits of a package or goods type. A nil
+// field inherits from the goods type; zero means unlimited.
+type VPSQuotaPolicy struct {
+	ResetOSPerDay          *int `json:"reset_os_per_day,omitempty"`
+	ResetOSCooldownMinutes *int `json:"reset_os_cooldown_minutes,omitempty"`
+	PasswordResetsPerHour  *int `json:"password_resets_per_hour,omitempty"`
+	SnapshotsMax           *int `json:"snapshots_max,omitempty"`
+	BackupsMax             *int `json:"backups_max,omitempty"`
+	PortMappingsMax        *int `json:"port_mappings_max,omitempty"`
+	FirewallRulesMax       *int `json:"firewall_rules_max,omitempty"`
+}
+
+func (p VPSQuotaPolicy) Empty() bool {
+	return p.ResetOSPerDay == nil && p.ResetOSCooldownMinutes == nil && p.PasswordResetsPerHour == nil &&
+		p.SnapshotsMax == nil && p.BackupsMax == nil && p.PortMappingsMax == nil && p.FirewallRulesMax == nil
+}
+
+func (p VPSQuotaPolicy) Validate() error {
+	for _, v := range []*int{p.ResetOSPerDay, p.PasswordResetsPerHour, p.SnapshotsMax, p.BackupsMax, p.PortMappingsMax, p.FirewallRulesMax} {
+		if v != nil && (*v < 0 || *v > 10000) {
+			return ErrInvalidInput
+		}
+	}
+	if v := p.ResetOSCooldownMinutes; v != nil && (*v < 0 || *v > MaxQuotaCooldownMinutes) {
+		return ErrInvalidInput
+	}
+	return nil
+}
+
+// Merge overlays the fields set on override.
+func (p VPSQuotaPolicy) Merge(override VPSQuotaPolicy) VPSQuotaPolicy {
+	pick := func(base, over *int) *int {
+		if over != nil {
+			return over
+		}
+		return base
+	}
+	return VPSQuotaPolicy{
+		ResetOSPerDay:          pick(p.ResetOSPerDay, override.ResetOSPerDay),
+		ResetOSCooldownMinutes: pick(p.ResetOSCooldownMinutes, override.ResetOSCooldownMinutes),
+		PasswordResetsPerHour:  pick(p.PasswordResetsPerHour, override.PasswordResetsPerHour),
+		SnapshotsMax:           pick(p.SnapshotsMax, override.SnapshotsMax),
+		BackupsMax:             pick(p.BackupsMax, override.BackupsMax),
+		PortMappingsMax:        pick(p.PortMappingsMax, override.PortMappingsMax),
+		FirewallRulesMax:       pick(p.FirewallRulesMax, override.FirewallRulesMax),
+	}
+}
+
+// VPSQuotaStatus is one limit as it applies to an instance. Used and
+// Remaining are only filled for the per-day and per-hour limits; counted
+// resources are checked against the provider when they are created.
+type VPSQuotaStatus struct {
+	Action        string
+	Window        string
+	Limit         int
+	Used          *int
+	Remaining     *int
+	CooldownUntil *time.Time
+}
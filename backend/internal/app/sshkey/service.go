@@ -0,0 +1,126 @@
+package sshkey
+
+import (
+	"context"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+
+	appports "xiaoheiplay/internal/app/ports"
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+const (
+	maxKeysPerUser = 50
+	maxNameLen     = 64
+)
+
+type Service struct {
+	repo appports.SSHKeyRepository
+}
+
+func NewService(repo appports.SSHKeyRepository) *Service {
+	return &Service{repo: repo}
+}
+
+func (s *Service) List(ctx context.Context, userID int64) ([]domain.SSHKey, error) {
+	if userID <= 0 {
+		return nil, appshared.ErrInvalidInput
+	}
+	return s.repo.ListSSHKeys(ctx, userID)
+}
+
+// Create validates an authorized_keys line and stores it in canonical form.
+// The comment is kept as the name when none is given.
+func (s *Service) Create(ctx context.Context, userID int64, name, publicKey string) (domain.SSHKey, error) {
+	if userID <= 0 {
+		return domain.SSHKey{}, appshared.ErrInvalidInput
+	}
+	pub, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(strings.TrimSpace(publicKey)))
+	if err != nil {
+		return domain.SSHKey{}, domain.ErrSSHKeyInvalid
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = strings.TrimSpace(comment)
+	}
+	if name == "" || len([]rune(name)) > maxNameLen {
+		return domain.SSHKey{}, appshared.ErrInvalidInput
+	}
+	existing, err := s.repo.ListSSHKeys(ctx, userID)
+	if err != nil {
+		return domain.SSHKey{}, err
+	}
+	if len(existing) >= maxKeysPerUser {
+		return domain.SSHKey{}, domain.ErrSSHKeyLimitReached
+	}
+	fingerprint := ssh.FingerprintSHA256(pub)
+	for _, item := range existing {
+		if item.Fingerprint == fingerprint {
+			return domain.SSHKey{}, domain.ErrSSHKeyExists
+		}
+	}
+	key := domain.SSHKey{
+		UserID:      userID,
+		Name:        name,
+		PublicKey:   strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pub))),
+		Fingerprint: fingerprint,
+	}
+	if err := s.repo.CreateSSHKey(ctx, &key); err != nil {
+		return domain.SSHKey{}, err
+	}
+	return key, nil
+}
+
+func (s *Service) Delete(ctx context.Context, userID, id int64) error {
+	if userID <= 0 || id <= 0 {
+		return appshared.ErrInvalidInput
+	}
+	key, err := s.repo.GetSSHKey(ctx, id)
+	if err != nil {
+		return err
+	}
+	if key.UserID != userID {
+		return appshared.ErrNotFound
+	}
+	return s.repo.DeleteSSHKey(ctx, userID, id)
+}
+
+// PublicKeys resolves key ids owned by userID. Unknown or foreign ids are
+// reported as not found so callers cannot probe other users' keys.
+func (s *Service) PublicKeys(ctx context.Context, userID int64, ids []int64) ([]string, error) {
+	return ResolvePublicKeys(ctx, s.repo, userID, ids, true)
+}
+
+// ResolvePublicKeys maps key ids to authorized_keys lines. With strict unset,
+// missing keys are skipped; provisioning uses that so a key deleted after
+// checkout does not fail the order.
+func ResolvePublicKeys(ctx context.Context, repo appports.SSHKeyRepository, userID int64, ids []int64, strict bool) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	if repo == nil {
+		return nil, appshared.ErrNotSupported
+	}
+	out := make([]string, 0, len(ids))
+	seen := make(map[int64]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		key, err := repo.GetSSHKey(ctx, id)
+		if err == nil && key.UserID != userID {
+			err = appshared.ErrNotFound
+		}
+		if err != nil {
+			if strict {
+				return nil, err
+			}
+			continue
+		}
+		out = append(out, key.PublicKey)
+	}
+	return out, nil
+}
@@ -0,0 +1,74 @@
+package sshkey_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+
+	appsshkey "xiaoheiplay/internal/app/sshkey"
+	"xiaoheiplay/internal/domain"
+	"xiaoheiplay/internal/testutil"
+)
+
+func newAuthorizedKey(t *testing.T, comment string) string {
+	t.Helper()
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatalf("ssh public key: %v", err)
+	}
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub))) + " " + comment
+}
+
+func TestService_CreateListDeleteAndResolve(t *testing.T) {
+	_, repo := testutil.NewTestDB(t, false)
+	ctx := context.Background()
+	owner := testutil.CreateUser(t, repo, "keyowner", "keyowner@example.com", "pass")
+	other := testutil.CreateUser(t, repo, "keyother", "keyother@example.com", "pass")
+	svc := appsshkey.NewService(repo)
+
+	raw := newAuthorizedKey(t, "laptop@home")
+	key, err := svc.Create(ctx, owner.ID, "", raw)
+	if err != nil {
+		t.Fatalf("create key: %v", err)
+	}
+	if key.Name != "laptop@home" || !strings.HasPrefix(key.Fingerprint, "SHA256:") || strings.Contains(key.PublicKey, "laptop") {
+		t.Fatalf("unexpected stored key: %+v", key)
+	}
+	if _, err := svc.Create(ctx, owner.ID, "again", raw); !errors.Is(err, domain.ErrSSHKeyExists) {
+		t.Fatalf("expected duplicate rejected, got %v", err)
+	}
+	if _, err := svc.Create(ctx, owner.ID, "bad", "ssh-rsa not-a-key"); !errors.Is(err, domain.ErrSSHKeyInvalid) {
+		t.Fatalf("expected invalid key rejected, got %v", err)
+	}
+
+	keys, err := svc.PublicKeys(ctx, owner.ID, []int64{key.ID, key.ID})
+	if err != nil || len(keys) != 1 || keys[0] != key.PublicKey {
+		t.Fatalf("resolve own keys: %v %v", keys, err)
+	}
+	if _, err := svc.PublicKeys(ctx, other.ID, []int64{key.ID}); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected foreign key hidden, got %v", err)
+	}
+	if err := svc.Delete(ctx, other.ID, key.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected foreign delete rejected, got %v", err)
+	}
+	if err := svc.Delete(ctx, owner.ID, key.ID); err != nil {
+		t.Fatalf("delete key: %v", err)
+	}
+	items, err := svc.List(ctx, owner.ID)
+	if err != nil || len(items) != 0 {
+		t.Fatalf("expected no keys after delete: %v %v", items, err)
+	}
+	lenient, err := appsshkey.ResolvePublicKeys(ctx, repo, owner.ID, []int64{key.ID}, false)
+	if err != nil || len(lenient) != 0 {
+		t.Fatalf("expected deleted key skipped: %v %v", lenient, err)
+	}
+}
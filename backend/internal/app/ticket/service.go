@@ -20,12 +20,22 @@ type Service struct {
 	users    appports.UserRepository
 	settings appports.SettingsRepository
 	messages messageCenter
+	hooks    pluginHookEmitter
+}
+
+type pluginHookEmitter interface {
+	Emit(ctx context.Context, event string, payload any)
 }
 
 func NewService(repo appports.TicketRepository, users appports.UserRepository, settings appports.SettingsRepository, messages messageCenter) *Service {
 	return &Service{repo: repo, users: users, settings: settings, messages: messages}
 }
 
+// SetPluginHooks emits ticket.created after a user opens a ticket.
+func (s *Service) SetPluginHooks(hooks pluginHookEmitter) {
+	s.hooks = hooks
+}
+
 func (s *Service) Create(ctx context.Context, userID int64, subject, content string, resources []domain.TicketResource) (domain.Ticket, []domain.TicketMessage, []domain.TicketResource, error) {
 	var err error
 	subject, err = trimAndValidateRequired(subject, maxLenTicketSubject)
@@ -70,6 +80,13 @@ func (s *Service) Create(ctx context.Context, userID int64, subject, content str
 	if err := s.repo.CreateTicketWithDetails(ctx, &ticket, &msg, resources); err != nil {
 		return domain.Ticket{}, nil, nil, err
 	}
+	if s.hooks != nil {
+		s.hooks.Emit(ctx, appshared.PluginHookTicketCreated, map[string]any{
+			"ticket_id": ticket.ID,
+			"user_id":   ticket.UserID,
+			"subject":   ticket.Subject,
+		})
+	}
 	return ticket, []domain.TicketMessage{msg}, resources, nil
 }
 
@@ -2,19 +2,47 @@ package upload
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
 
 	appports "xiaoheiplay/internal/app/ports"
+	appshared "xiaoheiplay/internal/app/shared"
 	"xiaoheiplay/internal/domain"
 )
 
+const (
+	settingStoragePluginID   = "upload_storage_plugin_id"
+	settingStorageInstanceID = "upload_storage_instance_id"
+
+	localDir         = "uploads"
+	presignTTL       = 15 * time.Minute
+	migrateBatchSize = 100
+)
+
 type Service struct {
-	repo appports.UploadRepository
+	repo     appports.UploadRepository
+	storage  appports.ObjectStorageResolver
+	settings appports.SettingsRepository
 }
 
 func NewService(repo appports.UploadRepository) *Service {
 	return &Service{repo: repo}
 }
 
+// SetStorage enables Store, Open and Migrate.
+func (s *Service) SetStorage(storage appports.ObjectStorageResolver) {
+	s.storage = storage
+}
+
+// SetSettings lets new uploads go to the storage plugin selected in settings
+// instead of the local disk.
+func (s *Service) SetSettings(settings appports.SettingsRepository) {
+	s.settings = settings
+}
+
 func (s *Service) Create(ctx context.Context, item *domain.Upload) error {
 	return s.repo.CreateUpload(ctx, item)
 }
@@ -22,3 +50,182 @@ func (s *Service) Create(ctx context.Context, item *domain.Upload) error {
 func (s *Service) List(ctx context.Context, limit, offset int) ([]domain.Upload, int, error) {
 	return s.repo.ListUploads(ctx, limit, offset)
 }
+
+// Backend returns the backend that receives new uploads.
+func (s *Service) Backend(ctx context.Context) string {
+	pluginID := strings.TrimSpace(s.stringSetting(ctx, settingStoragePluginID))
+	if pluginID == "" {
+		return domain.UploadBackendLocal
+	}
+	return NormalizeBackend(pluginID + "/" + strings.TrimSpace(s.stringSetting(ctx, settingStorageInstanceID)))
+}
+
+// Store writes body to the current backend under item.ObjectKey and records
+// the upload.
+func (s *Service) Store(ctx context.Context, item *domain.Upload, body []byte) error {
+	if s.storage == nil {
+		return appshared.ErrNotSupported
+	}
+	key := strings.TrimSpace(item.ObjectKey)
+	if !validObjectKey(key) {
+		return appshared.ErrInvalidInput
+	}
+	backend := s.Backend(ctx)
+	st, err := s.storage.Storage(ctx, backend)
+	if err != nil {
+		return err
+	}
+	if err := st.Put(ctx, key, body, item.Mime); err != nil {
+		return err
+	}
+	item.Backend = backend
+	item.ObjectKey = key
+	item.Path = storagePath(backend, key)
+	item.URL = "/uploads/" + key
+	if err := s.repo.CreateUpload(ctx, item); err != nil {
+		_ = st.Delete(ctx, key)
+		return err
+	}
+	return nil
+}
+
+// Open locates the content served at /uploads/<key>. Uploads on the local
+// backend, and files that predate upload records, are reported as Local.
+func (s *Service) Open(ctx context.Context, key string) (appshared.UploadObject, error) {
+	key = strings.TrimPrefix(strings.TrimSpace(key), "/")
+	if !validObjectKey(key) {
+		return appshared.UploadObject{}, appshared.ErrNotFound
+	}
+	item, err := s.repo.GetUploadByObjectKey(ctx, key)
+	if errors.Is(err, appshared.ErrNotFound) || (err == nil && item.Backend == domain.UploadBackendLocal) {
+		return appshared.UploadObject{Local: true}, nil
+	}
+	if err != nil {
+		return appshared.UploadObject{}, err
+	}
+	if s.storage == nil {
+		return appshared.UploadObject{}, appshared.ErrNotSupported
+	}
+	st, err := s.storage.Storage(ctx, item.Backend)
+	if err != nil {
+		return appshared.UploadObject{}, err
+	}
+	if url, err := st.PresignURL(ctx, key, presignTTL); err == nil && url != "" {
+		return appshared.UploadObject{RedirectURL: url}, nil
+	}
+	body, contentType, err := st.Get(ctx, key)
+	if err != nil {
+		return appshared.UploadObject{}, err
+	}
+	if contentType == "" {
+		contentType = item.Mime
+	}
+	return appshared.UploadObject{Content: body, ContentType: contentType}, nil
+}
+
+// Migrate copies every upload held by from into to, repoints its record and
+// deletes the source object. URLs do not change, so content referencing the
+// uploads keeps working.
+func (s *Service) Migrate(ctx context.Context, from, to string) (appshared.UploadMigrationResult, error) {
+	var res appshared.UploadMigrationResult
+	if s.storage == nil {
+		return res, appshared.ErrNotSupported
+	}
+	from, to = NormalizeBackend(from), NormalizeBackend(to)
+	if from == to {
+		return res, appshared.ErrInvalidInput
+	}
+	src, err := s.storage.Storage(ctx, from)
+	if err != nil {
+		return res, err
+	}
+	dst, err := s.storage.Storage(ctx, to)
+	if err != nil {
+		return res, err
+	}
+	var afterID int64
+	for {
+		items, err := s.repo.ListUploadsByBackend(ctx, from, afterID, migrateBatchSize)
+		if err != nil {
+			return res, err
+		}
+		if len(items) == 0 {
+			return res, nil
+		}
+		for _, item := range items {
+			afterID = item.ID
+			key := item.ObjectKey
+			if key == "" {
+				key = legacyObjectKey(item.Path)
+			}
+			body, _, err := src.Get(ctx, key)
+			if errors.Is(err, appshared.ErrNotFound) {
+				res.Missing++
+				continue
+			}
+			if err != nil {
+				return res, fmt.Errorf("upload %d: %w", item.ID, err)
+			}
+			if err := dst.Put(ctx, key, body, item.Mime); err != nil {
+				return res, fmt.Errorf("upload %d: %w", item.ID, err)
+			}
+			if err := s.repo.UpdateUploadStorage(ctx, item.ID, to, key, storagePath(to, key)); err != nil {
+				return res, err
+			}
+			if err := src.Delete(ctx, key); err != nil {
+				return res, fmt.Errorf("upload %d: %w", item.ID, err)
+			}
+			res.Moved++
+		}
+	}
+}
+
+// NormalizeBackend turns a backend name into its recorded form: "local" or
+// "<plugin_id>/<instance_id>", with the default instance filled in.
+func NormalizeBackend(backend string) string {
+	backend = strings.Trim(strings.TrimSpace(backend), "/")
+	if backend == "" || backend == domain.UploadBackendLocal {
+		return domain.UploadBackendLocal
+	}
+	pluginID, instanceID, _ := strings.Cut(backend, "/")
+	if strings.TrimSpace(instanceID) == "" {
+		instanceID = "default"
+	}
+	return strings.TrimSpace(pluginID) + "/" + strings.TrimSpace(instanceID)
+}
+
+func (s *Service) stringSetting(ctx context.Context, key string) string {
+	if s.settings == nil {
+		return ""
+	}
+	setting, err := s.settings.GetSetting(ctx, key)
+	if err != nil {
+		return ""
+	}
+	return setting.ValueJSON
+}
+
+func storagePath(backend, key string) string {
+	if backend == domain.UploadBackendLocal {
+		return filepath.Join(localDir, filepath.FromSlash(key))
+	}
+	return key
+}
+
+// legacyObjectKey derives the key of uploads recorded before object keys,
+// whose path is uploads/<date>/<name> on the local disk.
+func legacyObjectKey(path string) string {
+	return strings.TrimPrefix(filepath.ToSlash(path), localDir+"/")
+}
+
+func validObjectKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
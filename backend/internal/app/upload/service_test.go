@@ -0,0 +1,171 @@
+package upload_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"xiaoheiplay/internal/adapter/storage"
+	appports "xiaoheiplay/internal/app/ports"
+	appupload "xiaoheiplay/internal/app/upload"
+	"xiaoheiplay/internal/domain"
+	"xiaoheiplay/internal/testutil"
+)
+
+// memStorage stands in for a storage plugin instance.
+type memStorage struct {
+	mu      sync.Mutex
+	presign bool
+	objects map[string][]byte
+}
+
+func (m *memStorage) Put(_ context.Context, key string, body []byte, _ string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[key] = append([]byte(nil), body...)
+	return nil
+}
+
+func (m *memStorage) Get(_ context.Context, key string) ([]byte, string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	body, ok := m.objects[key]
+	if !ok {
+		return nil, "", domain.ErrNotFound
+	}
+	return body, "", nil
+}
+
+func (m *memStorage) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.objects, key)
+	return nil
+}
+
+func (m *memStorage) PresignURL(_ context.Context, key string, _ time.Duration) (string, error) {
+	if !m.presign {
+		return "", nil
+	}
+	return "https://bucket.example.com/" + key + "?sig=1", nil
+}
+
+type uploadEnv struct {
+	svc    *appupload.Service
+	repo   interface{ appports.SettingsRepository }
+	dir    string
+	remote *memStorage
+}
+
+func newUploadEnv(t *testing.T) *uploadEnv {
+	t.Helper()
+	_, repo := testutil.NewTestDB(t, false)
+	env := &uploadEnv{repo: repo, dir: t.TempDir(), remote: &memStorage{objects: map[string][]byte{}}}
+	env.svc = appupload.NewService(repo)
+	env.svc.SetSettings(repo)
+	env.svc.SetStorage(storage.NewResolver(storage.NewLocal(env.dir), func(pluginID, instanceID string) appports.ObjectStorage {
+		if pluginID != "s3_compat" || instanceID != "default" {
+			t.Fatalf("unexpected storage plugin %s/%s", pluginID, instanceID)
+		}
+		return env.remote
+	}))
+	return env
+}
+
+func (e *uploadEnv) useBackend(t *testing.T, pluginID string) {
+	t.Helper()
+	if err := e.repo.UpsertSetting(context.Background(), domain.Setting{Key: "upload_storage_plugin_id", ValueJSON: pluginID}); err != nil {
+		t.Fatalf("upsert setting: %v", err)
+	}
+}
+
+func TestStoreAndOpenFollowConfiguredBackend(t *testing.T) {
+	ctx := context.Background()
+	env := newUploadEnv(t)
+
+	local := domain.Upload{Name: "a.png", ObjectKey: "20260101/a.png", Mime: "image/png", Size: 1}
+	if err := env.svc.Store(ctx, &local, []byte("a")); err != nil {
+		t.Fatalf("store local: %v", err)
+	}
+	if local.Backend != domain.UploadBackendLocal || local.URL != "/uploads/20260101/a.png" {
+		t.Fatalf("unexpected local upload: %+v", local)
+	}
+	if _, err := os.Stat(filepath.Join(env.dir, "20260101", "a.png")); err != nil {
+		t.Fatalf("expected file on disk: %v", err)
+	}
+	if obj, err := env.svc.Open(ctx, "/20260101/a.png"); err != nil || !obj.Local {
+		t.Fatalf("expected local object, got %+v %v", obj, err)
+	}
+
+	env.useBackend(t, "s3_compat")
+	remote := domain.Upload{Name: "b.png", ObjectKey: "20260101/b.png", Mime: "image/png", Size: 1}
+	if err := env.svc.Store(ctx, &remote, []byte("b")); err != nil {
+		t.Fatalf("store remote: %v", err)
+	}
+	if remote.Backend != "s3_compat/default" || remote.URL != "/uploads/20260101/b.png" {
+		t.Fatalf("unexpected remote upload: %+v", remote)
+	}
+	obj, err := env.svc.Open(ctx, "20260101/b.png")
+	if err != nil || obj.Local || string(obj.Content) != "b" || obj.ContentType != "image/png" {
+		t.Fatalf("expected content from the plugin, got %+v %v", obj, err)
+	}
+	env.remote.presign = true
+	if obj, err := env.svc.Open(ctx, "20260101/b.png"); err != nil || obj.RedirectURL == "" {
+		t.Fatalf("expected presigned redirect, got %+v %v", obj, err)
+	}
+
+	bad := domain.Upload{Name: "c.png", ObjectKey: "../c.png"}
+	if err := env.svc.Store(ctx, &bad, []byte("c")); err == nil {
+		t.Fatalf("expected key outside the upload root to be rejected")
+	}
+}
+
+func TestMigrateMovesLegacyUploads(t *testing.T) {
+	ctx := context.Background()
+	env := newUploadEnv(t)
+
+	// Uploads recorded before object keys only have a local path.
+	if err := os.MkdirAll(filepath.Join(env.dir, "20250101"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(env.dir, "20250101", "old.png"), []byte("old"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	legacy := domain.Upload{Name: "old.png", Path: filepath.Join("uploads", "20250101", "old.png"), URL: "/uploads/20250101/old.png", Mime: "image/png"}
+	if err := env.svc.Create(ctx, &legacy); err != nil {
+		t.Fatalf("create legacy upload: %v", err)
+	}
+	gone := domain.Upload{Name: "gone.png", Path: filepath.Join("uploads", "20250101", "gone.png"), URL: "/uploads/20250101/gone.png", Mime: "image/png"}
+	if err := env.svc.Create(ctx, &gone); err != nil {
+		t.Fatalf("create missing upload: %v", err)
+	}
+
+	res, err := env.svc.Migrate(ctx, "local", "s3_compat")
+	if err != nil || res.Moved != 1 || res.Missing != 1 {
+		t.Fatalf("migrate: %+v %v", res, err)
+	}
+	if string(env.remote.objects["20250101/old.png"]) != "old" {
+		t.Fatalf("expected object in the plugin backend, have %v", env.remote.objects)
+	}
+	if _, err := os.Stat(filepath.Join(env.dir, "20250101", "old.png")); !os.IsNotExist(err) {
+		t.Fatalf("expected local copy to be removed, stat err %v", err)
+	}
+	obj, err := env.svc.Open(ctx, "20250101/old.png")
+	if err != nil || obj.Local || string(obj.Content) != "old" {
+		t.Fatalf("migrated upload must be served from the plugin, got %+v %v", obj, err)
+	}
+
+	back, err := env.svc.Migrate(ctx, "s3_compat/default", "local")
+	if err != nil || back.Moved != 1 {
+		t.Fatalf("migrate back: %+v %v", back, err)
+	}
+	if obj, err := env.svc.Open(ctx, "20250101/old.png"); err != nil || !obj.Local {
+		t.Fatalf("expected upload back on local disk, got %+v %v", obj, err)
+	}
+	if _, err := env.svc.Migrate(ctx, "local", "local"); err == nil {
+		t.Fatalf("expected migrating a backend onto itself to fail")
+	}
+}
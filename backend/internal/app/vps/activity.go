@@ -0,0 +1,48 @@
+package vps
+
+import (
+	"context"
+	"fmt"
+
+	appports "xiaoheiplay/internal/app/ports"
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+// SetActivityLog records user-facing actions in the instance activity feed.
+func (s *Service) SetActivityLog(activity appports.VPSActivityRepository) {
+	s.activity = activity
+}
+
+// Activities lists the activity feed of one instance, newest first.
+func (s *Service) Activities(ctx context.Context, filter appshared.VPSActivityFilter) ([]domain.VPSActivity, int, error) {
+	if s.activity == nil {
+		return nil, 0, appshared.ErrNotSupported
+	}
+	if filter.VPSID <= 0 {
+		return nil, 0, appshared.ErrInvalidInput
+	}
+	if filter.Limit > appshared.VPSActivityExportLimit {
+		filter.Limit = appshared.VPSActivityExportLimit
+	}
+	return s.activity.ListVPSActivities(ctx, filter)
+}
+
+func (s *Service) recordActivity(ctx context.Context, inst domain.VPSInstance, action, detail string, err error) {
+	if s.activity == nil {
+		return
+	}
+	appshared.RecordVPSActivity(ctx, s.activity, inst, action, detail, err)
+}
+
+func idDetail(kind string, id int64) string {
+	return fmt.Sprintf("%s #%d", kind, id)
+}
+
+func firewallRuleDetail(req AutomationFirewallRuleCreate) string {
+	return fmt.Sprintf("%s %s %s port %s ip %s", req.Direction, req.Method, req.Protocol, req.Port, req.IP)
+}
+
+func portMappingDetail(req AutomationPortMappingCreate) string {
+	return fmt.Sprintf("%s %s -> %d", req.Name, req.Sport, req.Dport)
+}
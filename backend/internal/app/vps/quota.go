@@ -0,0 +1,216 @@
+package vps
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	appports "xiaoheiplay/internal/app/ports"
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+const (
+	quotaWindowDay      = "day"
+	quotaWindowHour     = "hour"
+	quotaWindowInstance = "instance"
+)
+
+// SetActionUsage enables the per-day and per-hour limits, which count past
+// actions. Without it only the per-instance resource limits apply.
+func (s *Service) SetActionUsage(usage appports.VPSActionUsageRepository) {
+	s.usage = usage
+}
+
+// QuotaPolicy resolves the limits of an instance: the goods type policy with
+// the package policy on top.
+func (s *Service) QuotaPolicy(ctx context.Context, inst domain.VPSInstance) appshared.VPSQuotaPolicy {
+	var policy appshared.VPSQuotaPolicy
+	if inst.GoodsTypeID > 0 {
+		policy = policy.Merge(loadQuotaPolicies(ctx, s.settings, appshared.GoodsTypeQuotaSettingKey)[strconv.FormatInt(inst.GoodsTypeID, 10)])
+	}
+	if inst.PackageID > 0 {
+		policy = policy.Merge(loadQuotaPolicies(ctx, s.settings, appshared.PackageQuotaSettingKey)[strconv.FormatInt(inst.PackageID, 10)])
+	}
+	return policy
+}
+
+// Quotas reports the limits configured for the instance.
+func (s *Service) Quotas(ctx context.Context, inst domain.VPSInstance) []appshared.VPSQuotaStatus {
+	policy := s.QuotaPolicy(ctx, inst)
+	now := time.Now()
+	var out []appshared.VPSQuotaStatus
+	if limit, cooldown := quotaLimit(policy.ResetOSPerDay), quotaLimit(policy.ResetOSCooldownMinutes); limit > 0 || cooldown > 0 {
+		status := s.rateStatus(ctx, inst.ID, appshared.QuotaResetOS, quotaWindowDay, limit, now.Add(-24*time.Hour))
+		if cooldown > 0 && s.usage != nil {
+			if last, err := s.usage.LastVPSActionUsage(ctx, inst.ID, appshared.QuotaResetOS); err == nil {
+				if until := last.Add(time.Duration(cooldown) * time.Minute); until.After(now) {
+					status.CooldownUntil = &until
+				}
+			}
+		}
+		out = append(out, status)
+	}
+	if limit := quotaLimit(policy.PasswordResetsPerHour); limit > 0 {
+		out = append(out, s.rateStatus(ctx, inst.ID, appshared.QuotaResetPassword, quotaWindowHour, limit, now.Add(-time.Hour)))
+	}
+	for _, item := range []struct {
+		action string
+		limit  *int
+		count  func(AutomationClient, int64) (int, error)
+	}{
+		{appshared.QuotaSnapshots, policy.SnapshotsMax, func(cli AutomationClient, hostID int64) (int, error) {
+			items, err := cli.ListSnapshots(ctx, hostID)
+			return len(items), err
+		}},
+		{appshared.QuotaBackups, policy.BackupsMax, func(cli AutomationClient, hostID int64) (int, error) {
+			items, err := cli.ListBackups(ctx, hostID)
+			return len(items), err
+		}},
+		{appshared.QuotaPortMappings, policy.PortMappingsMax, func(cli AutomationClient, hostID int64) (int, error) {
+			items, err := cli.ListPortMappings(ctx, hostID)
+			return len(items), err
+		}},
+		{appshared.QuotaFirewallRules, policy.FirewallRulesMax, func(cli AutomationClient, hostID int64) (int, error) {
+			items, err := cli.ListFirewallRules(ctx, hostID)
+			return len(items), err
+		}},
+	} {
+		if limit := quotaLimit(item.limit); limit > 0 {
+			out = append(out, s.countStatus(ctx, inst, item.action, limit, item.count))
+		}
+	}
+	return out
+}
+
+// countStatus fills Used and Remaining from the same provider list the create
+// path checks. They stay nil when the provider cannot be asked.
+func (s *Service) countStatus(ctx context.Context, inst domain.VPSInstance, action string, limit int, count func(AutomationClient, int64) (int, error)) appshared.VPSQuotaStatus {
+	status := appshared.VPSQuotaStatus{Action: action, Window: quotaWindowInstance, Limit: limit}
+	hostID := parseHostID(inst.AutomationInstanceID)
+	if hostID == 0 {
+		return status
+	}
+	cli, err := s.client(ctx, inst)
+	if err != nil {
+		return status
+	}
+	used, err := count(cli, hostID)
+	if err != nil {
+		return status
+	}
+	remaining := limit - used
+	if remaining < 0 {
+		remaining = 0
+	}
+	status.Used = &used
+	status.Remaining = &remaining
+	return status
+}
+
+func (s *Service) rateStatus(ctx context.Context, vpsID int64, action, window string, limit int, since time.Time) appshared.VPSQuotaStatus {
+	status := appshared.VPSQuotaStatus{Action: action, Window: window, Limit: limit}
+	if s.usage == nil || limit <= 0 {
+		return status
+	}
+	used, err := s.usage.CountVPSActionUsage(ctx, vpsID, action, since)
+	if err != nil {
+		return status
+	}
+	remaining := limit - used
+	if remaining < 0 {
+		remaining = 0
+	}
+	status.Used = &used
+	status.Remaining = &remaining
+	return status
+}
+
+// CheckResetOSQuota reports whether a reinstall is allowed right now. Handlers
+// call it before queueing a reinstall so the user is told up front.
+func (s *Service) CheckResetOSQuota(ctx context.Context, inst domain.VPSInstance) error {
+	if s.usage == nil {
+		return nil
+	}
+	policy := s.QuotaPolicy(ctx, inst)
+	if cooldown := quotaLimit(policy.ResetOSCooldownMinutes); cooldown > 0 {
+		last, err := s.usage.LastVPSActionUsage(ctx, inst.ID, appshared.QuotaResetOS)
+		if err != nil && !errors.Is(err, appshared.ErrNotFound) {
+			return err
+		}
+		if err == nil {
+			if wait := time.Until(last.Add(time.Duration(cooldown) * time.Minute)); wait > 0 {
+				return fmt.Errorf("%w: reinstall available again in %d minutes", domain.ErrVPSActionCooldown, int(wait.Minutes())+1)
+			}
+		}
+	}
+	return s.checkRate(ctx, inst.ID, appshared.QuotaResetOS, quotaLimit(policy.ResetOSPerDay), 24*time.Hour, "reinstalls per day")
+}
+
+func (s *Service) checkResetPasswordQuota(ctx context.Context, inst domain.VPSInstance) error {
+	if s.usage == nil {
+		return nil
+	}
+	policy := s.QuotaPolicy(ctx, inst)
+	return s.checkRate(ctx, inst.ID, appshared.QuotaResetPassword, quotaLimit(policy.PasswordResetsPerHour), time.Hour, "password resets per hour")
+}
+
+func (s *Service) checkRate(ctx context.Context, vpsID int64, action string, limit int, window time.Duration, label string) error {
+	if limit <= 0 {
+		return nil
+	}
+	used, err := s.usage.CountVPSActionUsage(ctx, vpsID, action, time.Now().Add(-window))
+	if err != nil {
+		return err
+	}
+	if used >= limit {
+		return fmt.Errorf("%w: %d %s", domain.ErrVPSActionQuotaExceeded, limit, label)
+	}
+	return nil
+}
+
+// checkCount rejects creating another resource once the instance holds limit
+// of them.
+func checkCount(current, limit int, label string) error {
+	if limit > 0 && current >= limit {
+		return fmt.Errorf("%w: at most %d %s", domain.ErrVPSActionQuotaExceeded, limit, label)
+	}
+	return nil
+}
+
+func (s *Service) recordUsage(ctx context.Context, vpsID int64, action string) {
+	if s.usage == nil {
+		return
+	}
+	_ = s.usage.RecordVPSActionUsage(ctx, vpsID, action, time.Now())
+}
+
+func loadQuotaPolicies(ctx context.Context, settings appports.SettingsRepository, key string) map[string]appshared.VPSQuotaPolicy {
+	if settings == nil {
+		return map[string]appshared.VPSQuotaPolicy{}
+	}
+	setting, err := settings.GetSetting(ctx, key)
+	if err != nil {
+		return map[string]appshared.VPSQuotaPolicy{}
+	}
+	raw := strings.TrimSpace(setting.ValueJSON)
+	if raw == "" || raw == "{}" {
+		return map[string]appshared.VPSQuotaPolicy{}
+	}
+	var all map[string]appshared.VPSQuotaPolicy
+	if err := json.Unmarshal([]byte(raw), &all); err != nil || all == nil {
+		return map[string]appshared.VPSQuotaPolicy{}
+	}
+	return all
+}
+
+func quotaLimit(v *int) int {
+	if v == nil || *v < 0 {
+		return 0
+	}
+	return *v
+}
@@ -28,17 +28,29 @@ type Service struct {
 	vps        appports.VPSRepository
 	automation appports.AutomationClientResolver
 	settings   appports.SettingsRepository
+	usage      appports.VPSActionUsageRepository
+	activity   appports.VPSActivityRepository
+	hooks      pluginHookEmitter
+}
+
+type pluginHookEmitter interface {
+	Emit(ctx context.Context, event string, payload any)
 }
 
 func NewService(vps appports.VPSRepository, automation appports.AutomationClientResolver, settings appports.SettingsRepository) *Service {
 	return &Service{vps: vps, automation: automation, settings: settings}
 }
 
-func (s *Service) client(ctx context.Context, goodsTypeID int64) (AutomationClient, error) {
+// SetPluginHooks emits vps.expired when an instance is locked on expiry.
+func (s *Service) SetPluginHooks(hooks pluginHookEmitter) {
+	s.hooks = hooks
+}
+
+func (s *Service) client(ctx context.Context, inst domain.VPSInstance) (AutomationClient, error) {
 	if s.automation == nil {
 		return nil, appshared.ErrInvalidInput
 	}
-	return s.automation.ClientForGoodsType(ctx, goodsTypeID)
+	return s.automation.ClientForInstance(ctx, inst)
 }
 
 func (s *Service) ListByUser(ctx context.Context, userID int64) ([]domain.VPSInstance, error) {
@@ -93,21 +105,33 @@ func (s *Service) UpdateLocalSystemID(ctx context.Context, inst domain.VPSInstan
 }
 
 func (s *Service) RefreshStatus(ctx context.Context, inst domain.VPSInstance) (domain.VPSInstance, error) {
+	updated, _, err := s.RefreshTaskStatus(ctx, inst)
+	return updated, err
+}
+
+// RefreshTaskStatus is RefreshStatus that also returns the progress the plugin
+// reports for a running rebuild or restore, or nil when it reports none.
+func (s *Service) RefreshTaskStatus(ctx context.Context, inst domain.VPSInstance) (domain.VPSInstance, *appshared.AutomationTaskProgress, error) {
 	hostID := parseHostID(inst.AutomationInstanceID)
 	if hostID == 0 {
-		return domain.VPSInstance{}, appshared.ErrInvalidInput
+		return domain.VPSInstance{}, nil, appshared.ErrInvalidInput
 	}
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.client(ctx, inst)
 	if err != nil {
-		return domain.VPSInstance{}, err
+		return domain.VPSInstance{}, nil, err
 	}
 	info, err := cli.GetHostInfo(ctx, hostID)
 	if err != nil {
-		return domain.VPSInstance{}, err
+		return domain.VPSInstance{}, nil, err
 	}
 	status := MapAutomationState(info.State)
+	if inst.Status == domain.VPSStatusRescue && (status == domain.VPSStatusRunning || status == domain.VPSStatusStopped) {
+		// Providers report the rescue system as a normal power state; keep
+		// rescue until the session is exited.
+		status = domain.VPSStatusRescue
+	}
 	if err := s.vps.UpdateInstanceStatus(ctx, inst.ID, status, info.State); err != nil {
-		return domain.VPSInstance{}, err
+		return domain.VPSInstance{}, nil, err
 	}
 	if info.RemoteIP != "" || info.PanelPassword != "" || info.VNCPassword != "" {
 		_ = s.vps.UpdateInstanceAccessInfo(ctx, inst.ID, mergeAccessInfo(inst.AccessInfoJSON, info))
@@ -118,7 +142,11 @@ func (s *Service) RefreshStatus(ctx context.Context, inst domain.VPSInstance) (d
 			_ = s.vps.UpdateInstanceSpec(ctx, inst.ID, merged)
 		}
 	}
-	return s.vps.GetInstance(ctx, inst.ID)
+	updated, err := s.vps.GetInstance(ctx, inst.ID)
+	if err != nil {
+		return domain.VPSInstance{}, nil, err
+	}
+	return updated, info.Task, nil
 }
 
 func (s *Service) SetStatus(ctx context.Context, inst domain.VPSInstance, status domain.VPSStatus, automationState int) error {
@@ -130,7 +158,7 @@ func (s *Service) GetPanelURL(ctx context.Context, inst domain.VPSInstance) (str
 	if hostID == 0 {
 		return "", appshared.ErrInvalidInput
 	}
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.client(ctx, inst)
 	if err != nil {
 		return "", err
 	}
@@ -151,7 +179,7 @@ func (s *Service) RenewNow(ctx context.Context, inst domain.VPSInstance, days in
 	if hostID == 0 {
 		return appshared.ErrInvalidInput
 	}
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.client(ctx, inst)
 	if err != nil {
 		return err
 	}
@@ -176,43 +204,51 @@ func (s *Service) RenewNow(ctx context.Context, inst domain.VPSInstance, days in
 	return s.vps.UpdateInstanceExpireAt(ctx, inst.ID, next)
 }
 
-func (s *Service) Start(ctx context.Context, inst domain.VPSInstance) error {
+func (s *Service) Start(ctx context.Context, inst domain.VPSInstance) (err error) {
+	defer func() { s.recordActivity(ctx, inst, appshared.VPSActivityStart, "", err) }()
 	hostID := parseHostID(inst.AutomationInstanceID)
 	if hostID == 0 {
 		return appshared.ErrInvalidInput
 	}
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.client(ctx, inst)
 	if err != nil {
 		return err
 	}
 	return cli.StartHost(ctx, hostID)
 }
 
-func (s *Service) Shutdown(ctx context.Context, inst domain.VPSInstance) error {
+func (s *Service) Shutdown(ctx context.Context, inst domain.VPSInstance) (err error) {
+	defer func() { s.recordActivity(ctx, inst, appshared.VPSActivityShutdown, "", err) }()
 	hostID := parseHostID(inst.AutomationInstanceID)
 	if hostID == 0 {
 		return appshared.ErrInvalidInput
 	}
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.client(ctx, inst)
 	if err != nil {
 		return err
 	}
 	return cli.ShutdownHost(ctx, hostID)
 }
 
-func (s *Service) Reboot(ctx context.Context, inst domain.VPSInstance) error {
+func (s *Service) Reboot(ctx context.Context, inst domain.VPSInstance) (err error) {
+	defer func() { s.recordActivity(ctx, inst, appshared.VPSActivityReboot, "", err) }()
 	hostID := parseHostID(inst.AutomationInstanceID)
 	if hostID == 0 {
 		return appshared.ErrInvalidInput
 	}
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.client(ctx, inst)
 	if err != nil {
 		return err
 	}
 	return cli.RebootHost(ctx, hostID)
 }
 
-func (s *Service) ResetOS(ctx context.Context, inst domain.VPSInstance, templateID int64, password string) error {
+// ResetOS reinstalls the host. With a key-only guest init the password may be
+// empty; the previous OS password is then dropped from the access info.
+func (s *Service) ResetOS(ctx context.Context, inst domain.VPSInstance, templateID int64, password string, guest appshared.AutomationGuestInit) (err error) {
+	defer func() {
+		s.recordActivity(ctx, inst, appshared.VPSActivityResetOS, idDetail("template", templateID), err)
+	}()
 	if s.automation == nil {
 		return appshared.ErrInvalidInput
 	}
@@ -223,7 +259,14 @@ func (s *Service) ResetOS(ctx context.Context, inst domain.VPSInstance, template
 	if templateID <= 0 {
 		return appshared.ErrInvalidInput
 	}
-	if password == "" && inst.AccessInfoJSON != "" {
+	if err := guest.Validate(); err != nil {
+		return err
+	}
+	keyOnly := guest.DisablePassword
+	if keyOnly {
+		password = ""
+	}
+	if !keyOnly && password == "" && inst.AccessInfoJSON != "" {
 		var existing map[string]any
 		if err := json.Unmarshal([]byte(inst.AccessInfoJSON), &existing); err == nil {
 			if v, ok := existing["os_password"]; ok {
@@ -231,32 +274,43 @@ func (s *Service) ResetOS(ctx context.Context, inst domain.VPSInstance, template
 			}
 		}
 	}
-	if password == "" {
-		return appshared.ErrInvalidInput
+	if !keyOnly {
+		if password == "" {
+			return appshared.ErrInvalidInput
+		}
+		validatedPassword, validateErr := trimAndValidateRequired(password, maxLenPassword)
+		if validateErr != nil {
+			return appshared.ErrInvalidInput
+		}
+		password = validatedPassword
 	}
-	validatedPassword, validateErr := trimAndValidateRequired(password, maxLenPassword)
-	if validateErr != nil {
-		return appshared.ErrInvalidInput
+	if err := s.CheckResetOSQuota(ctx, inst); err != nil {
+		return err
 	}
-	password = validatedPassword
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.client(ctx, inst)
 	if err != nil {
 		return err
 	}
-	if err := cli.ResetOS(ctx, hostID, templateID, password); err != nil {
+	if err := cli.ResetOS(ctx, hostID, templateID, password, guest); err != nil {
 		return err
 	}
+	s.recordUsage(ctx, inst.ID, appshared.QuotaResetOS)
 	_ = s.vps.UpdateInstanceStatus(ctx, inst.ID, domain.VPSStatusReinstalling, 4)
 	access := map[string]any{}
 	if inst.AccessInfoJSON != "" {
 		_ = json.Unmarshal([]byte(inst.AccessInfoJSON), &access)
 	}
-	access["os_password"] = password
+	if keyOnly {
+		delete(access, "os_password")
+	} else {
+		access["os_password"] = password
+	}
 	_ = s.vps.UpdateInstanceAccessInfo(ctx, inst.ID, mustJSON(access))
 	return nil
 }
 
-func (s *Service) ResetOSPassword(ctx context.Context, inst domain.VPSInstance, password string) error {
+func (s *Service) ResetOSPassword(ctx context.Context, inst domain.VPSInstance, password string) (err error) {
+	defer func() { s.recordActivity(ctx, inst, appshared.VPSActivityResetPassword, "", err) }()
 	if s.automation == nil {
 		return appshared.ErrInvalidInput
 	}
@@ -273,13 +327,17 @@ func (s *Service) ResetOSPassword(ctx context.Context, inst domain.VPSInstance,
 		return appshared.ErrInvalidInput
 	}
 	password = validatedPassword
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	if err := s.checkResetPasswordQuota(ctx, inst); err != nil {
+		return err
+	}
+	cli, err := s.client(ctx, inst)
 	if err != nil {
 		return err
 	}
 	if err := cli.ResetOSPassword(ctx, hostID, password); err != nil {
 		return err
 	}
+	s.recordUsage(ctx, inst.ID, appshared.QuotaResetPassword)
 	access := map[string]any{}
 	if inst.AccessInfoJSON != "" {
 		_ = json.Unmarshal([]byte(inst.AccessInfoJSON), &access)
@@ -294,43 +352,59 @@ func (s *Service) ListSnapshots(ctx context.Context, inst domain.VPSInstance) ([
 	if hostID == 0 {
 		return nil, appshared.ErrInvalidInput
 	}
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.client(ctx, inst)
 	if err != nil {
 		return nil, err
 	}
 	return cli.ListSnapshots(ctx, hostID)
 }
 
-func (s *Service) CreateSnapshot(ctx context.Context, inst domain.VPSInstance) error {
+func (s *Service) CreateSnapshot(ctx context.Context, inst domain.VPSInstance) (err error) {
+	defer func() { s.recordActivity(ctx, inst, appshared.VPSActivitySnapshotCreate, "", err) }()
 	hostID := parseHostID(inst.AutomationInstanceID)
 	if hostID == 0 {
 		return appshared.ErrInvalidInput
 	}
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.client(ctx, inst)
 	if err != nil {
 		return err
 	}
+	if limit := quotaLimit(s.QuotaPolicy(ctx, inst).SnapshotsMax); limit > 0 {
+		items, err := cli.ListSnapshots(ctx, hostID)
+		if err != nil {
+			return err
+		}
+		if err := checkCount(len(items), limit, "snapshots"); err != nil {
+			return err
+		}
+	}
 	return cli.CreateSnapshot(ctx, hostID)
 }
 
-func (s *Service) DeleteSnapshot(ctx context.Context, inst domain.VPSInstance, snapshotID int64) error {
+func (s *Service) DeleteSnapshot(ctx context.Context, inst domain.VPSInstance, snapshotID int64) (err error) {
+	defer func() {
+		s.recordActivity(ctx, inst, appshared.VPSActivitySnapshotDelete, idDetail("snapshot", snapshotID), err)
+	}()
 	hostID := parseHostID(inst.AutomationInstanceID)
 	if hostID == 0 || snapshotID <= 0 {
 		return appshared.ErrInvalidInput
 	}
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.client(ctx, inst)
 	if err != nil {
 		return err
 	}
 	return cli.DeleteSnapshot(ctx, hostID, snapshotID)
 }
 
-func (s *Service) RestoreSnapshot(ctx context.Context, inst domain.VPSInstance, snapshotID int64) error {
+func (s *Service) RestoreSnapshot(ctx context.Context, inst domain.VPSInstance, snapshotID int64) (err error) {
+	defer func() {
+		s.recordActivity(ctx, inst, appshared.VPSActivitySnapshotRestore, idDetail("snapshot", snapshotID), err)
+	}()
 	hostID := parseHostID(inst.AutomationInstanceID)
 	if hostID == 0 || snapshotID <= 0 {
 		return appshared.ErrInvalidInput
 	}
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.client(ctx, inst)
 	if err != nil {
 		return err
 	}
@@ -342,43 +416,59 @@ func (s *Service) ListBackups(ctx context.Context, inst domain.VPSInstance) ([]A
 	if hostID == 0 {
 		return nil, appshared.ErrInvalidInput
 	}
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.client(ctx, inst)
 	if err != nil {
 		return nil, err
 	}
 	return cli.ListBackups(ctx, hostID)
 }
 
-func (s *Service) CreateBackup(ctx context.Context, inst domain.VPSInstance) error {
+func (s *Service) CreateBackup(ctx context.Context, inst domain.VPSInstance) (err error) {
+	defer func() { s.recordActivity(ctx, inst, appshared.VPSActivityBackupCreate, "", err) }()
 	hostID := parseHostID(inst.AutomationInstanceID)
 	if hostID == 0 {
 		return appshared.ErrInvalidInput
 	}
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.client(ctx, inst)
 	if err != nil {
 		return err
 	}
+	if limit := quotaLimit(s.QuotaPolicy(ctx, inst).BackupsMax); limit > 0 {
+		items, err := cli.ListBackups(ctx, hostID)
+		if err != nil {
+			return err
+		}
+		if err := checkCount(len(items), limit, "backups"); err != nil {
+			return err
+		}
+	}
 	return cli.CreateBackup(ctx, hostID)
 }
 
-func (s *Service) DeleteBackup(ctx context.Context, inst domain.VPSInstance, backupID int64) error {
+func (s *Service) DeleteBackup(ctx context.Context, inst domain.VPSInstance, backupID int64) (err error) {
+	defer func() {
+		s.recordActivity(ctx, inst, appshared.VPSActivityBackupDelete, idDetail("backup", backupID), err)
+	}()
 	hostID := parseHostID(inst.AutomationInstanceID)
 	if hostID == 0 || backupID <= 0 {
 		return appshared.ErrInvalidInput
 	}
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.client(ctx, inst)
 	if err != nil {
 		return err
 	}
 	return cli.DeleteBackup(ctx, hostID, backupID)
 }
 
-func (s *Service) RestoreBackup(ctx context.Context, inst domain.VPSInstance, backupID int64) error {
+func (s *Service) RestoreBackup(ctx context.Context, inst domain.VPSInstance, backupID int64) (err error) {
+	defer func() {
+		s.recordActivity(ctx, inst, appshared.VPSActivityBackupRestore, idDetail("backup", backupID), err)
+	}()
 	hostID := parseHostID(inst.AutomationInstanceID)
 	if hostID == 0 || backupID <= 0 {
 		return appshared.ErrInvalidInput
 	}
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.client(ctx, inst)
 	if err != nil {
 		return err
 	}
@@ -390,32 +480,45 @@ func (s *Service) ListFirewallRules(ctx context.Context, inst domain.VPSInstance
 	if hostID == 0 {
 		return nil, appshared.ErrInvalidInput
 	}
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.client(ctx, inst)
 	if err != nil {
 		return nil, err
 	}
 	return cli.ListFirewallRules(ctx, hostID)
 }
 
-func (s *Service) AddFirewallRule(ctx context.Context, inst domain.VPSInstance, req AutomationFirewallRuleCreate) error {
+func (s *Service) AddFirewallRule(ctx context.Context, inst domain.VPSInstance, req AutomationFirewallRuleCreate) (err error) {
+	defer func() { s.recordActivity(ctx, inst, appshared.VPSActivityFirewallAdd, firewallRuleDetail(req), err) }()
 	hostID := parseHostID(inst.AutomationInstanceID)
 	if hostID == 0 {
 		return appshared.ErrInvalidInput
 	}
 	req.HostID = hostID
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.client(ctx, inst)
 	if err != nil {
 		return err
 	}
+	if limit := quotaLimit(s.QuotaPolicy(ctx, inst).FirewallRulesMax); limit > 0 {
+		items, err := cli.ListFirewallRules(ctx, hostID)
+		if err != nil {
+			return err
+		}
+		if err := checkCount(len(items), limit, "firewall rules"); err != nil {
+			return err
+		}
+	}
 	return cli.AddFirewallRule(ctx, req)
 }
 
-func (s *Service) DeleteFirewallRule(ctx context.Context, inst domain.VPSInstance, ruleID int64) error {
+func (s *Service) DeleteFirewallRule(ctx context.Context, inst domain.VPSInstance, ruleID int64) (err error) {
+	defer func() {
+		s.recordActivity(ctx, inst, appshared.VPSActivityFirewallDelete, idDetail("rule", ruleID), err)
+	}()
 	hostID := parseHostID(inst.AutomationInstanceID)
 	if hostID == 0 || ruleID <= 0 {
 		return appshared.ErrInvalidInput
 	}
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.client(ctx, inst)
 	if err != nil {
 		return err
 	}
@@ -427,14 +530,15 @@ func (s *Service) ListPortMappings(ctx context.Context, inst domain.VPSInstance)
 	if hostID == 0 {
 		return nil, appshared.ErrInvalidInput
 	}
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.client(ctx, inst)
 	if err != nil {
 		return nil, err
 	}
 	return cli.ListPortMappings(ctx, hostID)
 }
 
-func (s *Service) AddPortMapping(ctx context.Context, inst domain.VPSInstance, req AutomationPortMappingCreate) error {
+func (s *Service) AddPortMapping(ctx context.Context, inst domain.VPSInstance, req AutomationPortMappingCreate) (err error) {
+	defer func() { s.recordActivity(ctx, inst, appshared.VPSActivityPortAdd, portMappingDetail(req), err) }()
 	hostID := parseHostID(inst.AutomationInstanceID)
 	if hostID == 0 {
 		return appshared.ErrInvalidInput
@@ -447,19 +551,31 @@ func (s *Service) AddPortMapping(ctx context.Context, inst domain.VPSInstance, r
 		req.Name = name
 	}
 	req.HostID = hostID
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.client(ctx, inst)
 	if err != nil {
 		return err
 	}
+	if limit := quotaLimit(s.QuotaPolicy(ctx, inst).PortMappingsMax); limit > 0 {
+		items, err := cli.ListPortMappings(ctx, hostID)
+		if err != nil {
+			return err
+		}
+		if err := checkCount(len(items), limit, "port mappings"); err != nil {
+			return err
+		}
+	}
 	return cli.AddPortMapping(ctx, req)
 }
 
-func (s *Service) DeletePortMapping(ctx context.Context, inst domain.VPSInstance, mappingID int64) error {
+func (s *Service) DeletePortMapping(ctx context.Context, inst domain.VPSInstance, mappingID int64) (err error) {
+	defer func() {
+		s.recordActivity(ctx, inst, appshared.VPSActivityPortDelete, idDetail("mapping", mappingID), err)
+	}()
 	hostID := parseHostID(inst.AutomationInstanceID)
 	if hostID == 0 || mappingID <= 0 {
 		return appshared.ErrInvalidInput
 	}
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.client(ctx, inst)
 	if err != nil {
 		return err
 	}
@@ -471,7 +587,7 @@ func (s *Service) FindPortCandidates(ctx context.Context, inst domain.VPSInstanc
 	if hostID == 0 {
 		return nil, appshared.ErrInvalidInput
 	}
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.client(ctx, inst)
 	if err != nil {
 		return nil, err
 	}
@@ -483,7 +599,7 @@ func (s *Service) Monitor(ctx context.Context, inst domain.VPSInstance) (Automat
 	if hostID == 0 {
 		return AutomationMonitor{}, appshared.ErrInvalidInput
 	}
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.client(ctx, inst)
 	if err != nil {
 		return AutomationMonitor{}, err
 	}
@@ -520,7 +636,7 @@ func (s *Service) VNCURL(ctx context.Context, inst domain.VPSInstance) (string,
 	if hostID == 0 {
 		return "", appshared.ErrInvalidInput
 	}
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.client(ctx, inst)
 	if err != nil {
 		return "", err
 	}
@@ -547,7 +663,7 @@ func (s *Service) EmergencyRenew(ctx context.Context, inst domain.VPSInstance) (
 	if hostID == 0 {
 		return domain.VPSInstance{}, appshared.ErrInvalidInput
 	}
-	cli, err := s.client(ctx, inst.GoodsTypeID)
+	cli, err := s.client(ctx, inst)
 	if err != nil {
 		return domain.VPSInstance{}, err
 	}
@@ -595,10 +711,13 @@ func (s *Service) AutoDeleteExpired(ctx context.Context) error {
 		if hostID == 0 {
 			continue
 		}
-		cli, err := s.client(ctx, inst.GoodsTypeID)
+		cli, err := s.client(ctx, inst)
 		if err != nil {
 			continue
 		}
+		if ips, ok := s.vps.(appshared.ExtraIPStore); ok {
+			appshared.ReleaseExtraIPs(ctx, ips, cli, hostID, inst.ID)
+		}
 		if err := cli.DeleteHost(ctx, hostID); err != nil {
 			continue
 		}
@@ -616,6 +735,7 @@ func (s *Service) AutoLockExpired(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	schedCtx := appshared.WithVPSActivityActor(ctx, appshared.VPSActivityActor{Type: domain.VPSActivityActorScheduler})
 	for _, inst := range items {
 		if inst.ExpireAt == nil || inst.ExpireAt.After(now) {
 			continue
@@ -630,15 +750,25 @@ func (s *Service) AutoLockExpired(ctx context.Context) error {
 		if hostID == 0 {
 			continue
 		}
-		cli, err := s.client(ctx, inst.GoodsTypeID)
+		cli, err := s.client(ctx, inst)
 		if err != nil {
 			continue
 		}
 		if err := cli.LockHost(ctx, hostID); err != nil {
+			s.recordActivity(schedCtx, inst, appshared.VPSActivityAutoLock, "expired", err)
 			continue
 		}
+		s.recordActivity(schedCtx, inst, appshared.VPSActivityAutoLock, "expired", nil)
 		_ = s.vps.UpdateInstanceStatus(ctx, inst.ID, domain.VPSStatusExpiredLocked, 10)
 		_ = s.vps.UpdateInstanceAdminStatus(ctx, inst.ID, domain.VPSAdminStatusLocked)
+		if s.hooks != nil {
+			s.hooks.Emit(ctx, appshared.PluginHookVPSExpired, map[string]any{
+				"vps_id":    inst.ID,
+				"user_id":   inst.UserID,
+				"name":      inst.Name,
+				"expire_at": inst.ExpireAt.Unix(),
+			})
+		}
 	}
 	return nil
 }
@@ -2,6 +2,7 @@ package vps_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -149,6 +150,105 @@ func TestVPSService_RenewAndEmergency(t *testing.T) {
 	}
 }
 
+func TestVPSService_ActionQuotas(t *testing.T) {
+	ctx := context.Background()
+	_, repo := testutil.NewTestDB(t, false)
+	user := testutil.CreateUser(t, repo, "v6", "v6@example.com", "pass")
+	inst := createVPSInstance(t, repo, user.ID, "400")
+	inst.GoodsTypeID = 7
+	inst.PackageID = 9
+	// The package overrides the goods type reinstall limit and inherits the
+	// snapshot limit.
+	if err := repo.UpsertSetting(ctx, domain.Setting{Key: appshared.GoodsTypeQuotaSettingKey, ValueJSON: `{"7":{"reset_os_per_day":5,"snapshots_max":1}}`}); err != nil {
+		t.Fatalf("save goods type quotas: %v", err)
+	}
+	if err := repo.UpsertSetting(ctx, domain.Setting{Key: appshared.PackageQuotaSettingKey, ValueJSON: `{"9":{"reset_os_per_day":1,"password_resets_per_hour":2}}`}); err != nil {
+		t.Fatalf("save package quotas: %v", err)
+	}
+	fakeAuto := &testutil.FakeAutomationClient{SnapshotList: []appshared.AutomationSnapshot{{"id": 1}}}
+	svc := appvps.NewService(repo, &testutil.FakeAutomationResolver{Client: fakeAuto}, repo)
+	svc.SetActionUsage(repo)
+
+	if err := svc.ResetOS(ctx, inst, 1, "Passw0rd!", appshared.AutomationGuestInit{}); err != nil {
+		t.Fatalf("first reinstall: %v", err)
+	}
+	if err := svc.ResetOS(ctx, inst, 1, "Passw0rd!", appshared.AutomationGuestInit{}); !errors.Is(err, domain.ErrVPSActionQuotaExceeded) {
+		t.Fatalf("expected reinstall quota error, got %v", err)
+	}
+	if len(fakeAuto.ResetOSCalls) != 1 {
+		t.Fatalf("expected one reinstall to reach the provider, got %d", len(fakeAuto.ResetOSCalls))
+	}
+	if err := svc.CreateSnapshot(ctx, inst); !errors.Is(err, domain.ErrVPSActionQuotaExceeded) {
+		t.Fatalf("expected snapshot quota error, got %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := svc.ResetOSPassword(ctx, inst, "Passw0rd!"); err != nil {
+			t.Fatalf("password reset %d: %v", i, err)
+		}
+	}
+	if err := svc.ResetOSPassword(ctx, inst, "Passw0rd!"); !errors.Is(err, domain.ErrVPSActionQuotaExceeded) {
+		t.Fatalf("expected password reset quota error, got %v", err)
+	}
+
+	quotas := map[string]appshared.VPSQuotaStatus{}
+	for _, item := range svc.Quotas(ctx, inst) {
+		quotas[item.Action] = item
+	}
+	if q := quotas[appshared.QuotaResetOS]; q.Limit != 1 || q.Remaining == nil || *q.Remaining != 0 {
+		t.Fatalf("unexpected reinstall quota: %+v", q)
+	}
+	if q := quotas[appshared.QuotaSnapshots]; q.Limit != 1 || q.Used == nil || *q.Used != 1 || *q.Remaining != 0 {
+		t.Fatalf("unexpected snapshot quota: %+v", q)
+	}
+
+	fakeAuto.ListSnapshotsErr = errors.New("provider down")
+	for _, item := range svc.Quotas(ctx, inst) {
+		if item.Action == appshared.QuotaSnapshots && (item.Limit != 1 || item.Used != nil || item.Remaining != nil) {
+			t.Fatalf("expected limit only when the provider fails, got %+v", item)
+		}
+	}
+}
+
+func TestVPSService_ActivityLog(t *testing.T) {
+	ctx := context.Background()
+	_, repo := testutil.NewTestDB(t, false)
+	user := testutil.CreateUser(t, repo, "v7", "v7@example.com", "pass")
+	inst := createVPSInstance(t, repo, user.ID, "500")
+	svc := appvps.NewService(repo, &testutil.FakeAutomationResolver{Client: &testutil.FakeAutomationClient{}}, repo)
+	svc.SetActivityLog(repo)
+
+	userCtx := appshared.WithVPSActivityActor(ctx, appshared.VPSActivityActor{Type: domain.VPSActivityActorUser, ID: user.ID, IP: "203.0.113.7"})
+	keyCtx := appshared.WithVPSActivityActor(ctx, appshared.VPSActivityActor{Type: domain.VPSActivityActorAPIKey, ID: 42, IP: "198.51.100.2"})
+	if err := svc.Start(userCtx, inst); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := svc.Reboot(keyCtx, inst); err != nil {
+		t.Fatalf("reboot: %v", err)
+	}
+	if err := svc.ResetOSPassword(userCtx, inst, ""); err == nil {
+		t.Fatalf("expected invalid password error")
+	}
+
+	items, total, err := svc.Activities(ctx, appshared.VPSActivityFilter{VPSID: inst.ID})
+	if err != nil || total != 3 {
+		t.Fatalf("list activities: %v total=%d", err, total)
+	}
+	if items[0].Action != appshared.VPSActivityResetPassword || items[0].Result != domain.VPSActivityResultFailed || items[0].ErrorMessage == "" {
+		t.Fatalf("unexpected newest entry: %+v", items[0])
+	}
+	items, _, err = svc.Activities(ctx, appshared.VPSActivityFilter{VPSID: inst.ID, ActorType: domain.VPSActivityActorAPIKey})
+	if err != nil || len(items) != 1 {
+		t.Fatalf("filter by actor: %v %+v", err, items)
+	}
+	if got := items[0]; got.Action != appshared.VPSActivityReboot || got.ActorID != 42 || got.IP != "198.51.100.2" || got.UserID != user.ID {
+		t.Fatalf("unexpected api key entry: %+v", got)
+	}
+	_, total, err = svc.Activities(ctx, appshared.VPSActivityFilter{VPSID: inst.ID, Result: domain.VPSActivityResultSuccess})
+	if err != nil || total != 2 {
+		t.Fatalf("filter by result: %v total=%d", err, total)
+	}
+}
+
 func createVPSInstance(t *testing.T, repo *repo.GormRepo, userID int64, automationID string) domain.VPSInstance {
 	t.Helper()
 	order := domain.Order{UserID: userID, OrderNo: "ORD-VPS-X", Status: domain.OrderStatusApproved, TotalAmount: 1000, Currency: "CNY"}
@@ -0,0 +1,100 @@
+package vpsoperation
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+	"xiaoheiplay/internal/testutil"
+)
+
+type scriptedPoll struct {
+	status domain.VPSStatus
+	task   *appshared.AutomationTaskProgress
+}
+
+type scriptedExecutor struct {
+	polls []scriptedPoll
+	calls int
+}
+
+func (e *scriptedExecutor) ResetOS(ctx context.Context, inst domain.VPSInstance, templateID int64, password string, guest appshared.AutomationGuestInit) error {
+	return nil
+}
+
+func (e *scriptedExecutor) UpdateLocalSystemID(ctx context.Context, inst domain.VPSInstance, systemID int64) error {
+	return nil
+}
+
+func (e *scriptedExecutor) RefreshTaskStatus(ctx context.Context, inst domain.VPSInstance) (domain.VPSInstance, *appshared.AutomationTaskProgress, error) {
+	poll := e.polls[min(e.calls, len(e.polls)-1)]
+	e.calls++
+	inst.Status = poll.status
+	return inst, poll.task, nil
+}
+
+func (e *scriptedExecutor) RestoreSnapshot(ctx context.Context, inst domain.VPSInstance, snapshotID int64) error {
+	return nil
+}
+
+func (e *scriptedExecutor) RestoreBackup(ctx context.Context, inst domain.VPSInstance, backupID int64) error {
+	return nil
+}
+
+type progressRecorder struct {
+	ops []domain.VPSOperation
+}
+
+func (r *progressRecorder) PublishVPSOperation(ctx context.Context, op domain.VPSOperation) {
+	r.ops = append(r.ops, op)
+}
+
+func runScriptedReset(t *testing.T, polls []scriptedPoll) []domain.VPSOperation {
+	t.Helper()
+	_, repo := testutil.NewTestDB(t, false)
+	rec := &progressRecorder{}
+	svc := NewService(repo, repo, repo, &scriptedExecutor{polls: polls}, rec)
+	svc.pollInterval = 0
+	params, _ := json.Marshal(resetOSParams{TemplateID: 1})
+	op := domain.VPSOperation{VPSID: 1, UserID: 1, Action: domain.VPSOperationResetOS, Status: domain.VPSOperationStatusPending, ParamsJSON: string(params)}
+	if err := repo.CreateVPSOperation(context.Background(), &op); err != nil {
+		t.Fatalf("create operation: %v", err)
+	}
+	if err := svc.runResetOS(context.Background(), &op, domain.VPSInstance{ID: 1}, operationSecret{}); err != nil {
+		t.Fatalf("reset os: %v", err)
+	}
+	return rec.ops
+}
+
+func TestRunResetOS_ForwardsPluginProgress(t *testing.T) {
+	ops := runScriptedReset(t, []scriptedPoll{
+		{status: domain.VPSStatusReinstalling, task: &appshared.AutomationTaskProgress{Percent: 30, Message: "copying image"}},
+		{status: domain.VPSStatusReinstalling, task: &appshared.AutomationTaskProgress{Percent: 30, Message: "copying image"}},
+		{status: domain.VPSStatusReinstalling, task: &appshared.AutomationTaskProgress{Percent: 80}},
+		{status: domain.VPSStatusRunning},
+	})
+	if len(ops) != 3 {
+		t.Fatalf("expected the estimate and two reported steps, got %+v", ops)
+	}
+	if ops[0].Progress != 50 || !ops[0].ProgressEstimated {
+		t.Fatalf("expected an estimated first step, got %+v", ops[0])
+	}
+	if ops[1].Progress != 30 || ops[1].ProgressEstimated || ops[1].Message != "copying image" {
+		t.Fatalf("expected the plugin progress, got %+v", ops[1])
+	}
+	if ops[2].Progress != 80 || ops[2].ProgressEstimated || ops[2].Message != "reinstalling" {
+		t.Fatalf("expected the plugin progress with the default message, got %+v", ops[2])
+	}
+}
+
+func TestRunResetOS_MarksProgressEstimatedWithoutPluginProgress(t *testing.T) {
+	ops := runScriptedReset(t, []scriptedPoll{
+		{status: domain.VPSStatusReinstalling},
+		{status: domain.VPSStatusRunning},
+	})
+	if len(ops) != 1 || ops[0].Progress != 50 || !ops[0].ProgressEstimated {
+		t.Fatalf("expected a single estimated step, got %+v", ops)
+	}
+}
@@ -0,0 +1,527 @@
+package vpsoperation
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	appports "xiaoheiplay/internal/app/ports"
+	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
+)
+
+const (
+	defaultWorkers      = 4
+	defaultPollInterval = 10 * time.Second
+	defaultWaitTimeout  = 30 * time.Minute
+	maxErrorMessageLen  = 2000
+)
+
+type vpsExecutor interface {
+	ResetOS(ctx context.Context, inst domain.VPSInstance, templateID int64, password string, guest appshared.AutomationGuestInit) error
+	UpdateLocalSystemID(ctx context.Context, inst domain.VPSInstance, systemID int64) error
+	RefreshTaskStatus(ctx context.Context, inst domain.VPSInstance) (domain.VPSInstance, *appshared.AutomationTaskProgress, error)
+	RestoreSnapshot(ctx context.Context, inst domain.VPSInstance, snapshotID int64) error
+	RestoreBackup(ctx context.Context, inst domain.VPSInstance, backupID int64) error
+}
+
+type ResetOSInput struct {
+	TemplateID int64
+	SystemID   int64
+	Password   string
+	Init       appshared.AutomationGuestInit
+}
+
+// resetOSParams is persisted with the operation. Public keys are kept so a
+// pending reinstall survives a restart; user data may hold credentials and,
+// like the password, only lives in memory.
+type resetOSParams struct {
+	TemplateID  int64    `json:"template_id"`
+	SystemID    int64    `json:"system_id,omitempty"`
+	PasswordSet bool     `json:"password_set"`
+	SSHKeys     []string `json:"ssh_keys,omitempty"`
+	UserDataSet bool     `json:"user_data_set,omitempty"`
+	KeyOnly     bool     `json:"key_only,omitempty"`
+}
+
+type operationSecret struct {
+	Password      string
+	UserData      string
+	StartupScript string
+}
+
+func (s operationSecret) empty() bool {
+	return s.Password == "" && s.UserData == "" && s.StartupScript == ""
+}
+
+type restoreParams struct {
+	SnapshotID int64 `json:"snapshot_id,omitempty"`
+	BackupID   int64 `json:"backup_id,omitempty"`
+}
+
+type firewallReapplier interface {
+	Reapply(ctx context.Context, inst domain.VPSInstance) error
+}
+
+// Service runs long VPS actions in the background and records their progress.
+// Only one pending or running operation is allowed per instance.
+type Service struct {
+	ops         appports.VPSOperationRepository
+	vps         appports.VPSRepository
+	resizeTasks appports.ResizeTaskRepository
+	exec        vpsExecutor
+	publisher   appports.VPSOperationPublisher
+	firewall    firewallReapplier
+
+	mu       sync.Mutex
+	secrets  map[int64]operationSecret
+	actors   map[int64]appshared.VPSActivityActor
+	inflight map[int64]struct{}
+	wake     chan struct{}
+	slots    chan struct{}
+
+	bootAt       time.Time
+	pollInterval time.Duration
+	waitTimeout  time.Duration
+}
+
+func NewService(ops appports.VPSOperationRepository, vps appports.VPSRepository, resizeTasks appports.ResizeTaskRepository, exec vpsExecutor, publisher appports.VPSOperationPublisher) *Service {
+	return &Service{
+		ops:          ops,
+		vps:          vps,
+		resizeTasks:  resizeTasks,
+		exec:         exec,
+		publisher:    publisher,
+		secrets:      make(map[int64]operationSecret),
+		actors:       make(map[int64]appshared.VPSActivityActor),
+		inflight:     make(map[int64]struct{}),
+		wake:         make(chan struct{}, 1),
+		slots:        make(chan struct{}, defaultWorkers),
+		bootAt:       time.Now(),
+		pollInterval: defaultPollInterval,
+		waitTimeout:  defaultWaitTimeout,
+	}
+}
+
+// SetFirewallTemplates re-applies attached firewall templates after a reinstall.
+func (s *Service) SetFirewallTemplates(firewall firewallReapplier) {
+	s.firewall = firewall
+}
+
+func (s *Service) Get(ctx context.Context, vpsID, operationID int64) (domain.VPSOperation, error) {
+	op, err := s.ops.GetVPSOperation(ctx, operationID)
+	if err != nil {
+		return domain.VPSOperation{}, err
+	}
+	if op.VPSID != vpsID {
+		return domain.VPSOperation{}, appshared.ErrNotFound
+	}
+	return op, nil
+}
+
+func (s *Service) List(ctx context.Context, vpsID int64, limit, offset int) ([]domain.VPSOperation, int, error) {
+	if vpsID <= 0 {
+		return nil, 0, appshared.ErrInvalidInput
+	}
+	return s.ops.ListVPSOperations(ctx, vpsID, limit, offset)
+}
+
+// ListActive returns the pending and running operations of an instance, newest first.
+func (s *Service) ListActive(ctx context.Context, vpsID int64) ([]domain.VPSOperation, error) {
+	items, _, err := s.ops.ListVPSOperations(ctx, vpsID, 20, 0)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.VPSOperation, 0, len(items))
+	for _, item := range items {
+		if isActiveStatus(item.Status) {
+			out = append(out, item)
+		}
+	}
+	return out, nil
+}
+
+func (s *Service) HasActive(ctx context.Context, vpsID int64) (bool, error) {
+	return s.ops.HasActiveVPSOperation(ctx, vpsID)
+}
+
+func (s *Service) SubmitResetOS(ctx context.Context, inst domain.VPSInstance, input ResetOSInput) (domain.VPSOperation, error) {
+	if input.TemplateID <= 0 {
+		return domain.VPSOperation{}, appshared.ErrInvalidInput
+	}
+	if err := input.Init.Validate(); err != nil {
+		return domain.VPSOperation{}, err
+	}
+	secret := operationSecret{
+		Password:      strings.TrimSpace(input.Password),
+		UserData:      input.Init.UserData,
+		StartupScript: input.Init.StartupScript,
+	}
+	params := resetOSParams{
+		TemplateID:  input.TemplateID,
+		SystemID:    input.SystemID,
+		PasswordSet: secret.Password != "",
+		SSHKeys:     input.Init.SSHKeys,
+		UserDataSet: secret.UserData != "" || secret.StartupScript != "",
+		KeyOnly:     input.Init.DisablePassword,
+	}
+	return s.submit(ctx, inst, domain.VPSOperationResetOS, params, secret)
+}
+
+func (s *Service) SubmitRestoreSnapshot(ctx context.Context, inst domain.VPSInstance, snapshotID int64) (domain.VPSOperation, error) {
+	if snapshotID <= 0 {
+		return domain.VPSOperation{}, appshared.ErrInvalidInput
+	}
+	return s.submit(ctx, inst, domain.VPSOperationRestoreSnapshot, restoreParams{SnapshotID: snapshotID}, operationSecret{})
+}
+
+func (s *Service) SubmitRestoreBackup(ctx context.Context, inst domain.VPSInstance, backupID int64) (domain.VPSOperation, error) {
+	if backupID <= 0 {
+		return domain.VPSOperation{}, appshared.ErrInvalidInput
+	}
+	return s.submit(ctx, inst, domain.VPSOperationRestoreBackup, restoreParams{BackupID: backupID}, operationSecret{})
+}
+
+// Begin records an operation that the caller executes synchronously, such as
+// the elastic update applied by the resize flow. Pair it with Finish.
+func (s *Service) Begin(ctx context.Context, inst domain.VPSInstance, action domain.VPSOperationAction, params any) (domain.VPSOperation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkConflictLocked(ctx, inst.ID, false); err != nil {
+		return domain.VPSOperation{}, err
+	}
+	now := time.Now()
+	op := domain.VPSOperation{
+		VPSID:             inst.ID,
+		UserID:            inst.UserID,
+		Action:            action,
+		Status:            domain.VPSOperationStatusRunning,
+		Progress:          10,
+		Message:           "started",
+		ParamsJSON:        mustJSON(params),
+		StartedAt:         &now,
+		ProgressEstimated: true,
+	}
+	if err := s.ops.CreateVPSOperation(ctx, &op); err != nil {
+		return domain.VPSOperation{}, err
+	}
+	s.publish(ctx, op)
+	return op, nil
+}
+
+func (s *Service) Finish(ctx context.Context, op domain.VPSOperation, runErr error) {
+	if op.ID <= 0 {
+		return
+	}
+	s.complete(ctx, &op, runErr)
+}
+
+// Start drives the background worker until ctx is canceled.
+func (s *Service) Start(ctx context.Context) {
+	s.recoverInterrupted(ctx)
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+	for {
+		s.dispatch(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) submit(ctx context.Context, inst domain.VPSInstance, action domain.VPSOperationAction, params any, secret operationSecret) (domain.VPSOperation, error) {
+	if inst.ID <= 0 {
+		return domain.VPSOperation{}, appshared.ErrInvalidInput
+	}
+	s.mu.Lock()
+	if err := s.checkConflictLocked(ctx, inst.ID, true); err != nil {
+		s.mu.Unlock()
+		return domain.VPSOperation{}, err
+	}
+	op := domain.VPSOperation{
+		VPSID:      inst.ID,
+		UserID:     inst.UserID,
+		Action:     action,
+		Status:     domain.VPSOperationStatusPending,
+		Message:    "queued",
+		ParamsJSON: mustJSON(params),
+	}
+	if err := s.ops.CreateVPSOperation(ctx, &op); err != nil {
+		s.mu.Unlock()
+		return domain.VPSOperation{}, err
+	}
+	if !secret.empty() {
+		s.secrets[op.ID] = secret
+	}
+	if actor, ok := appshared.GetVPSActivityActor(ctx); ok {
+		s.actors[op.ID] = actor
+	}
+	s.mu.Unlock()
+	s.publish(ctx, op)
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return op, nil
+}
+
+func (s *Service) checkConflictLocked(ctx context.Context, vpsID int64, includeResize bool) error {
+	active, err := s.ops.HasActiveVPSOperation(ctx, vpsID)
+	if err != nil {
+		return err
+	}
+	if active {
+		return appshared.ErrVPSOperationInProgress
+	}
+	if includeResize && s.resizeTasks != nil {
+		pending, err := s.resizeTasks.HasPendingResizeTask(ctx, vpsID)
+		if err != nil {
+			return err
+		}
+		if pending {
+			return appshared.ErrVPSOperationInProgress
+		}
+	}
+	return nil
+}
+
+// recoverInterrupted settles operations left behind by a previous process.
+// Running ones are failed because their upstream state is unknown; pending ones
+// are resumed unless they depended on in-memory state such as a root password.
+func (s *Service) recoverInterrupted(ctx context.Context) {
+	items, err := s.ops.ListVPSOperationsByStatus(ctx, []domain.VPSOperationStatus{domain.VPSOperationStatusPending, domain.VPSOperationStatusRunning}, 500)
+	if err != nil {
+		return
+	}
+	for i := range items {
+		op := &items[i]
+		if !op.CreatedAt.Before(s.bootAt) {
+			continue
+		}
+		if op.Status == domain.VPSOperationStatusPending && !needsSecret(*op) {
+			continue
+		}
+		s.complete(ctx, op, domain.ErrVPSOperationInterrupted)
+	}
+}
+
+func needsSecret(op domain.VPSOperation) bool {
+	if op.Action != domain.VPSOperationResetOS {
+		return false
+	}
+	var params resetOSParams
+	_ = json.Unmarshal([]byte(op.ParamsJSON), &params)
+	return params.PasswordSet || params.UserDataSet
+}
+
+func (s *Service) dispatch(ctx context.Context) {
+	items, err := s.ops.ListVPSOperationsByStatus(ctx, []domain.VPSOperationStatus{domain.VPSOperationStatusPending}, 50)
+	if err != nil {
+		return
+	}
+	for _, op := range items {
+		s.mu.Lock()
+		if _, ok := s.inflight[op.ID]; ok {
+			s.mu.Unlock()
+			continue
+		}
+		s.inflight[op.ID] = struct{}{}
+		secret := s.secrets[op.ID]
+		delete(s.secrets, op.ID)
+		actor, ok := s.actors[op.ID]
+		delete(s.actors, op.ID)
+		s.mu.Unlock()
+		// Operations resumed after a restart lost their requester; they were
+		// submitted by the owner or on the owner's behalf.
+		if !ok {
+			actor = appshared.VPSActivityActor{Type: domain.VPSActivityActorUser, ID: op.UserID}
+		}
+
+		select {
+		case s.slots <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+		go func(op domain.VPSOperation, secret operationSecret, actor appshared.VPSActivityActor) {
+			defer func() {
+				<-s.slots
+				s.mu.Lock()
+				delete(s.inflight, op.ID)
+				s.mu.Unlock()
+			}()
+			s.run(appshared.WithVPSActivityActor(ctx, actor), op, secret)
+		}(op, secret, actor)
+	}
+}
+
+func (s *Service) run(ctx context.Context, op domain.VPSOperation, secret operationSecret) {
+	now := time.Now()
+	op.Status = domain.VPSOperationStatusRunning
+	op.Progress = 10
+	op.ProgressEstimated = true
+	op.Message = "started"
+	op.StartedAt = &now
+	if err := s.save(ctx, &op); err != nil {
+		return
+	}
+	s.publish(ctx, op)
+
+	inst, err := s.vps.GetInstance(ctx, op.VPSID)
+	if err != nil {
+		s.complete(ctx, &op, err)
+		return
+	}
+	if s.exec == nil {
+		s.complete(ctx, &op, appshared.ErrInvalidInput)
+		return
+	}
+	switch op.Action {
+	case domain.VPSOperationResetOS:
+		err = s.runResetOS(ctx, &op, inst, secret)
+	case domain.VPSOperationRestoreSnapshot:
+		var params restoreParams
+		_ = json.Unmarshal([]byte(op.ParamsJSON), &params)
+		err = s.exec.RestoreSnapshot(ctx, inst, params.SnapshotID)
+	case domain.VPSOperationRestoreBackup:
+		var params restoreParams
+		_ = json.Unmarshal([]byte(op.ParamsJSON), &params)
+		err = s.exec.RestoreBackup(ctx, inst, params.BackupID)
+	default:
+		err = appshared.ErrNotSupported
+	}
+	s.complete(ctx, &op, err)
+}
+
+func (s *Service) runResetOS(ctx context.Context, op *domain.VPSOperation, inst domain.VPSInstance, secret operationSecret) error {
+	var params resetOSParams
+	if err := json.Unmarshal([]byte(op.ParamsJSON), &params); err != nil {
+		return appshared.ErrInvalidInput
+	}
+	guest := appshared.AutomationGuestInit{
+		SSHKeys:         params.SSHKeys,
+		UserData:        secret.UserData,
+		StartupScript:   secret.StartupScript,
+		DisablePassword: params.KeyOnly,
+	}
+	if err := s.exec.ResetOS(ctx, inst, params.TemplateID, secret.Password, guest); err != nil {
+		return err
+	}
+	if params.SystemID > 0 {
+		_ = s.exec.UpdateLocalSystemID(ctx, inst, params.SystemID)
+	}
+	s.progress(ctx, op, 50, true, "reinstalling")
+	if err := s.waitReinstalled(ctx, op, inst); err != nil {
+		return err
+	}
+	if s.firewall != nil {
+		// A failed reapply stays pending for the firewall reconcile task and
+		// does not fail the reinstall.
+		_ = s.firewall.Reapply(ctx, inst)
+	}
+	return nil
+}
+
+// waitReinstalled polls the upstream host state until the reinstall leaves the
+// provisioning/reinstalling states, forwarding the task progress the plugin
+// reports while it waits.
+func (s *Service) waitReinstalled(ctx context.Context, op *domain.VPSOperation, inst domain.VPSInstance) error {
+	deadline := time.Now().Add(s.waitTimeout)
+	for {
+		if current, task, err := s.exec.RefreshTaskStatus(ctx, inst); err == nil {
+			if task != nil {
+				message := task.Message
+				if message == "" {
+					message = "reinstalling"
+				}
+				if op.ProgressEstimated || task.Percent != op.Progress || message != op.Message {
+					s.progress(ctx, op, task.Percent, false, message)
+				}
+			}
+			switch current.Status {
+			case domain.VPSStatusReinstallFailed:
+				return domain.ErrReinstallFailed
+			case domain.VPSStatusReinstalling, domain.VPSStatusProvisioning:
+			default:
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return domain.ErrOperationTimeout
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.pollInterval):
+		}
+	}
+}
+
+// progress records a step of a running operation. Values the worker picks
+// itself, rather than reads from the plugin, are marked as estimates.
+func (s *Service) progress(ctx context.Context, op *domain.VPSOperation, progress int, estimated bool, message string) {
+	op.Progress = progress
+	op.ProgressEstimated = estimated
+	op.Message = message
+	_ = s.save(ctx, op)
+	s.publish(ctx, *op)
+}
+
+func (s *Service) complete(ctx context.Context, op *domain.VPSOperation, runErr error) {
+	now := time.Now()
+	op.FinishedAt = &now
+	if op.StartedAt == nil {
+		op.StartedAt = &now
+	}
+	if runErr != nil {
+		op.Status = domain.VPSOperationStatusFailed
+		op.Message = "failed"
+		op.ErrorMessage = truncate(runErr.Error(), maxErrorMessageLen)
+	} else {
+		op.Status = domain.VPSOperationStatusSucceeded
+		op.Progress = 100
+		op.ProgressEstimated = false
+		op.Message = "completed"
+		op.ErrorMessage = ""
+	}
+	_ = s.save(ctx, op)
+	s.publish(ctx, *op)
+}
+
+func (s *Service) save(ctx context.Context, op *domain.VPSOperation) error {
+	op.UpdatedAt = time.Now()
+	return s.ops.UpdateVPSOperation(ctx, *op)
+}
+
+func (s *Service) publish(ctx context.Context, op domain.VPSOperation) {
+	if s.publisher == nil {
+		return
+	}
+	s.publisher.PublishVPSOperation(ctx, op)
+}
+
+func isActiveStatus(status domain.VPSOperationStatus) bool {
+	return status == domain.VPSOperationStatusPending || status == domain.VPSOperationStatusRunning
+}
+
+func mustJSON(v any) string {
+	if v == nil {
+		return "{}"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "{}"
+	}
+	return string(b)
+}
+
+func truncate(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	return value[:max]
+}
@@ -0,0 +1,182 @@
+package vpsoperation_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"xiaoheiplay/internal/adapter/repo/core"
+	appshared "xiaoheiplay/internal/app/shared"
+	appvps "xiaoheiplay/internal/app/vps"
+	appvpsoperation "xiaoheiplay/internal/app/vpsoperation"
+	"xiaoheiplay/internal/domain"
+	"xiaoheiplay/internal/testutil"
+)
+
+type recordingPublisher struct {
+	mu  sync.Mutex
+	ops []domain.VPSOperation
+}
+
+func (p *recordingPublisher) PublishVPSOperation(ctx context.Context, op domain.VPSOperation) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ops = append(p.ops, op)
+}
+
+func (p *recordingPublisher) statuses() []domain.VPSOperationStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make([]domain.VPSOperationStatus, 0, len(p.ops))
+	for _, op := range p.ops {
+		out = append(out, op.Status)
+	}
+	return out
+}
+
+func createVPSInstance(t *testing.T, repo *repo.GormRepo, userID int64, automationID string) domain.VPSInstance {
+	t.Helper()
+	order := domain.Order{UserID: userID, OrderNo: "ORD-OP-" + automationID, Status: domain.OrderStatusApproved, TotalAmount: 1000, Currency: "CNY"}
+	if err := repo.CreateOrder(context.Background(), &order); err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	item := domain.OrderItem{OrderID: order.ID, Amount: 1000, Status: domain.OrderItemStatusActive, Action: "create", SpecJSON: "{}"}
+	if err := repo.CreateOrderItems(context.Background(), []domain.OrderItem{item}); err != nil {
+		t.Fatalf("create item: %v", err)
+	}
+	items, _ := repo.ListOrderItems(context.Background(), order.ID)
+	inst := domain.VPSInstance{
+		UserID:               userID,
+		OrderItemID:          items[0].ID,
+		AutomationInstanceID: automationID,
+		Name:                 "vm-" + automationID,
+		Status:               domain.VPSStatusRunning,
+		SpecJSON:             "{}",
+	}
+	if err := repo.CreateInstance(context.Background(), &inst); err != nil {
+		t.Fatalf("create vps: %v", err)
+	}
+	return inst
+}
+
+func waitOperation(t *testing.T, repo *repo.GormRepo, id int64) domain.VPSOperation {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		op, err := repo.GetVPSOperation(context.Background(), id)
+		if err != nil {
+			t.Fatalf("get operation: %v", err)
+		}
+		if op.Status == domain.VPSOperationStatusSucceeded || op.Status == domain.VPSOperationStatusFailed {
+			return op
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("operation %d still %s", id, op.Status)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestVPSOperationService_ResetOSRunsInBackground(t *testing.T) {
+	_, repo := testutil.NewTestDB(t, false)
+	user := testutil.CreateUser(t, repo, "op1", "op1@example.com", "pass")
+	inst := createVPSInstance(t, repo, user.ID, "101")
+
+	client := &testutil.FakeAutomationClient{}
+	vpsSvc := appvps.NewService(repo, &testutil.FakeAutomationResolver{Client: client}, repo)
+	pub := &recordingPublisher{}
+	svc := appvpsoperation.NewService(repo, repo, repo, vpsSvc, pub)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go svc.Start(ctx)
+
+	op, err := svc.SubmitResetOS(context.Background(), inst, appvpsoperation.ResetOSInput{TemplateID: 7, SystemID: 3, Password: "Pass123!"})
+	if err != nil {
+		t.Fatalf("submit reset os: %v", err)
+	}
+	if op.Status != domain.VPSOperationStatusPending {
+		t.Fatalf("expected pending, got %s", op.Status)
+	}
+	done := waitOperation(t, repo, op.ID)
+	if done.Status != domain.VPSOperationStatusSucceeded || done.Progress != 100 {
+		t.Fatalf("unexpected operation result: %+v", done)
+	}
+	if len(client.ResetOSCalls) != 1 || client.ResetOSCalls[0].Password != "Pass123!" || client.ResetOSCalls[0].TemplateID != 7 {
+		t.Fatalf("unexpected reset calls: %+v", client.ResetOSCalls)
+	}
+	updated, _ := repo.GetInstance(context.Background(), inst.ID)
+	if updated.SystemID != 3 {
+		t.Fatalf("expected system id updated, got %d", updated.SystemID)
+	}
+	statuses := pub.statuses()
+	if len(statuses) < 3 || statuses[0] != domain.VPSOperationStatusPending || statuses[len(statuses)-1] != domain.VPSOperationStatusSucceeded {
+		t.Fatalf("unexpected published statuses: %v", statuses)
+	}
+}
+
+func TestVPSOperationService_BlocksConcurrentOperations(t *testing.T) {
+	_, repo := testutil.NewTestDB(t, false)
+	user := testutil.CreateUser(t, repo, "op2", "op2@example.com", "pass")
+	inst := createVPSInstance(t, repo, user.ID, "102")
+
+	vpsSvc := appvps.NewService(repo, &testutil.FakeAutomationResolver{Client: &testutil.FakeAutomationClient{}}, repo)
+	svc := appvpsoperation.NewService(repo, repo, repo, vpsSvc, nil)
+
+	if _, err := svc.SubmitRestoreSnapshot(context.Background(), inst, 5); err != nil {
+		t.Fatalf("submit restore snapshot: %v", err)
+	}
+	if _, err := svc.SubmitRestoreBackup(context.Background(), inst, 6); err != appshared.ErrVPSOperationInProgress {
+		t.Fatalf("expected in progress, got %v", err)
+	}
+	if _, err := svc.Begin(context.Background(), inst, domain.VPSOperationElasticUpdate, nil); err != appshared.ErrVPSOperationInProgress {
+		t.Fatalf("expected in progress for elastic update, got %v", err)
+	}
+	other := createVPSInstance(t, repo, user.ID, "103")
+	if _, err := svc.SubmitRestoreBackup(context.Background(), other, 6); err != nil {
+		t.Fatalf("other instance should not be blocked: %v", err)
+	}
+}
+
+func TestVPSOperationService_FailedOperationRecordsError(t *testing.T) {
+	_, repo := testutil.NewTestDB(t, false)
+	user := testutil.CreateUser(t, repo, "op3", "op3@example.com", "pass")
+	inst := createVPSInstance(t, repo, user.ID, "104")
+
+	vpsSvc := appvps.NewService(repo, &testutil.FakeAutomationResolver{Err: appshared.ErrNotSupported}, repo)
+	svc := appvpsoperation.NewService(repo, repo, repo, vpsSvc, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go svc.Start(ctx)
+
+	op, err := svc.SubmitRestoreBackup(context.Background(), inst, 9)
+	if err != nil {
+		t.Fatalf("submit restore backup: %v", err)
+	}
+	done := waitOperation(t, repo, op.ID)
+	if done.Status != domain.VPSOperationStatusFailed || done.ErrorMessage != appshared.ErrNotSupported.Error() {
+		t.Fatalf("unexpected failed operation: %+v", done)
+	}
+	if _, err := svc.SubmitRestoreBackup(context.Background(), inst, 9); err != nil {
+		t.Fatalf("finished operation should not block new ones: %v", err)
+	}
+}
+
+func TestVPSOperationService_StartFailsInterruptedOperations(t *testing.T) {
+	_, repo := testutil.NewTestDB(t, false)
+	user := testutil.CreateUser(t, repo, "op4", "op4@example.com", "pass")
+	inst := createVPSInstance(t, repo, user.ID, "105")
+
+	stale := domain.VPSOperation{VPSID: inst.ID, UserID: user.ID, Action: domain.VPSOperationResetOS, Status: domain.VPSOperationStatusRunning, ParamsJSON: "{}"}
+	if err := repo.CreateVPSOperation(context.Background(), &stale); err != nil {
+		t.Fatalf("create stale operation: %v", err)
+	}
+	svc := appvpsoperation.NewService(repo, repo, repo, nil, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	go svc.Start(ctx)
+	done := waitOperation(t, repo, stale.ID)
+	cancel()
+	if done.Status != domain.VPSOperationStatusFailed || done.ErrorMessage != domain.ErrVPSOperationInterrupted.Error() {
+		t.Fatalf("expected interrupted failure, got %+v", done)
+	}
+}
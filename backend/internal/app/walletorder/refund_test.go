@@ -222,6 +222,14 @@ func (f *fakeOrderItemRepo) UpdateOrderItemStatus(ctx context.Context, id int64,
 func (f *fakeOrderItemRepo) UpdateOrderItemAutomation(ctx context.Context, id int64, automationID string) error {
 	return nil
 }
+
+func (f *fakeOrderItemRepo) UpdateOrderItemSpec(ctx context.Context, id int64, specJSON string) error {
+	return nil
+}
+
+func (f *fakeOrderItemRepo) SwitchOrderItemPackage(ctx context.Context, id, packageID, amount int64) error {
+	return nil
+}
 func (f *fakeOrderItemRepo) HasPendingRenewOrder(ctx context.Context, userID, vpsID int64) (bool, error) {
 	return false, nil
 }
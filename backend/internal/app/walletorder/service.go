@@ -388,10 +388,13 @@ func (s *Service) deleteVPS(ctx context.Context, metaJSON string) error {
 	if hostID == 0 {
 		return appshared.ErrInvalidInput
 	}
-	cli, err := s.automation.ClientForGoodsType(ctx, inst.GoodsTypeID)
+	cli, err := s.automation.ClientForInstance(ctx, inst)
 	if err != nil {
 		return err
 	}
+	if ips, ok := s.vps.(appshared.ExtraIPStore); ok {
+		appshared.ReleaseExtraIPs(ctx, ips, cli, hostID, inst.ID)
+	}
 	if err := cli.DeleteHost(ctx, hostID); err != nil {
 		return err
 	}
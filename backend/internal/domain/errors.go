@@ -14,6 +14,10 @@ var (
 	ErrResizeSamePlan                                     = errors.New("resize target matches current plan")
 	ErrResizeDisabled                                     = errors.New("resize disabled")
 	ErrResizeInProgress                                   = errors.New("resize already in progress")
+	ErrVPSOperationInProgress                             = errors.New("another operation is in progress for this instance")
+	ErrVPSOperationInterrupted                            = errors.New("operation interrupted by service restart")
+	ErrReinstallFailed                                    = errors.New("reinstall failed")
+	ErrOperationTimeout                                   = errors.New("operation timed out")
 	ErrCaptchaFailed                                      = errors.New("captcha failed")
 	ErrInsufficientBalance                                = errors.New("insufficient balance")
 	ErrRealNameRequired                                   = errors.New("real name required")
@@ -33,6 +37,34 @@ var (
 	ErrPluginMethodNotSupported                           = errors.New("method not supported")
 	ErrAutomationBindingInvalid                           = errors.New("invalid automation binding")
 	ErrAutomationPluginInstanceNotFound                   = errors.New("automation plugin instance not found")
+	ErrAutomationBackendExists                            = errors.New("automation backend already in pool")
+	ErrNoAvailableAutomationBackend                       = errors.New("no healthy automation backend with free capacity")
+	ErrAutomationBackendCatalogMismatch                   = errors.New("automation backend does not carry the ordered line or image")
+	ErrSSHKeyInvalid                                      = errors.New("invalid ssh public key")
+	ErrSSHKeyExists                                       = errors.New("ssh key already added")
+	ErrSSHKeyLimitReached                                 = errors.New("ssh key limit reached")
+	ErrUserDataTooLarge                                   = errors.New("user data too large")
+	ErrKeyOnlyRequiresSSHKey                              = errors.New("key-only install requires at least one ssh key")
+	ErrGuestInitNotSupported                              = errors.New("ssh key and user data injection not supported by this product")
+	ErrReverseDNSInvalidHostname                          = errors.New("invalid ptr hostname")
+	ErrReverseDNSNotConfirmed                             = errors.New("ptr hostname does not resolve to this ip")
+	ErrReverseDNSUnknownIP                                = errors.New("ip is not assigned to this instance")
+	ErrExtraIPNotSupported                                = errors.New("extra ip addresses not supported by this product")
+	ErrExtraIPNotAssigned                                 = errors.New("automation returned no ip address")
+	ErrRescueActive                                       = errors.New("instance is already in rescue mode")
+	ErrRescueNotActive                                    = errors.New("instance is not in rescue mode")
+	ErrISONotFound                                        = errors.New("iso image not found")
+	ErrAbuseCaseClosed                                    = errors.New("abuse case is closed")
+	ErrAbuseTargetNotLinked                               = errors.New("instance is not linked to this abuse case")
+	ErrAbuseWebhookDisabled                               = errors.New("abuse report webhook disabled")
+	ErrConsoleTicketInvalid                               = errors.New("console ticket invalid or expired")
+	ErrConsoleSessionLimit                                = errors.New("too many open console sessions")
+	ErrFirewallTemplateLimitReached                       = errors.New("firewall template limit reached")
+	ErrFirewallTemplateTooManyRules                       = errors.New("too many rules in firewall template")
+	ErrFirewallRuleInvalid                                = errors.New("invalid firewall rule")
+	ErrVPSActionQuotaExceeded                             = errors.New("action quota exceeded")
+	ErrVPSActionCooldown                                  = errors.New("action is cooling down")
+	ErrProvisionRefunded                                  = errors.New("provision failed and the order item was refunded")
 	ErrNoWritableAutomationPluginInstance                 = errors.New("no writable automation plugin instance found; configure automation plugin instance first")
 	ErrSecurityTicketRequired                             = errors.New("security ticket required")
 	ErrSecurityTicketInvalid                              = errors.New("invalid security ticket")
@@ -230,6 +262,7 @@ var (
 	ErrUpdateAdminPasswordFailed                          = errors.New("update admin password failed: ")
 	ErrUploadDirError                                     = errors.New("upload dir error")
 	ErrUploadFailed                                       = errors.New("upload failed")
+	ErrUploadUnavailable                                  = errors.New("upload unavailable")
 	ErrUserDisabled                                       = errors.New("user disabled")
 	ErrUsernameRequired                                   = errors.New("username required")
 	ErrUserNotFound                                       = errors.New("user not found")
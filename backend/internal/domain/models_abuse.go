@@ -0,0 +1,76 @@
+package domain
+
+import "time"
+
+type AbuseCaseStatus string
+
+const (
+	AbuseCaseStatusOpen          AbuseCaseStatus = "open"
+	AbuseCaseStatusInvestigating AbuseCaseStatus = "investigating"
+	AbuseCaseStatusAwaitingUser  AbuseCaseStatus = "awaiting_user"
+	AbuseCaseStatusResolved      AbuseCaseStatus = "resolved"
+	AbuseCaseStatusDismissed     AbuseCaseStatus = "dismissed"
+)
+
+// Closed reports whether the case has left the abuse desk queue.
+func (s AbuseCaseStatus) Closed() bool {
+	return s == AbuseCaseStatusResolved || s == AbuseCaseStatusDismissed
+}
+
+// AbuseCase is one complaint worked by the abuse desk. Source is admin for
+// cases opened from the admin form and webhook for inbound reports.
+// AckDueAt and ResolveDueAt are the SLA deadlines fixed at intake;
+// ResponseDueAt is the end of the user response window after a warning.
+type AbuseCase struct {
+	ID             int64
+	Source         string
+	Category       string
+	Reporter       string
+	Subject        string
+	Description    string
+	Status         AbuseCaseStatus
+	AssigneeID     int64
+	Resolution     string
+	AckDueAt       time.Time
+	ResolveDueAt   time.Time
+	ResponseDueAt  *time.Time
+	AcknowledgedAt *time.Time
+	WarnedAt       *time.Time
+	ClosedAt       *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// AbuseCaseLink ties a case to an instance or user. User links carry the
+// ticket opened for the user when they were warned.
+type AbuseCaseLink struct {
+	ID         int64
+	CaseID     int64
+	TargetType string
+	TargetID   int64
+	TicketID   int64
+	CreatedAt  time.Time
+}
+
+// AbuseEvidence is one attachment on a case. Kind is text, url or upload;
+// upload evidence references an admin upload by UploadID and its URL.
+type AbuseEvidence struct {
+	ID        int64
+	CaseID    int64
+	Kind      string
+	Content   string
+	UploadID  int64
+	AddedBy   int64
+	CreatedAt time.Time
+}
+
+// AbuseCaseEvent is one entry on the case timeline. AdminID is zero for
+// events raised by the webhook, the user or the SLA checker.
+type AbuseCaseEvent struct {
+	ID         int64
+	CaseID     int64
+	AdminID    int64
+	Type       string
+	DetailJSON string
+	CreatedAt  time.Time
+}
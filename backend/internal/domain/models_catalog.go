@@ -23,6 +23,25 @@ type GoodsType struct {
 	UpdatedAt            time.Time
 }
 
+// AutomationBackend is one plugin instance in a goods type's placement pool.
+// Capacity caps the number of hosts placed on it; zero means unlimited.
+type AutomationBackend struct {
+	ID                  int64
+	GoodsTypeID         int64
+	PluginID            string
+	InstanceID          string
+	Weight              int
+	Capacity            int
+	Enabled             bool
+	Health              AutomationBackendHealth
+	ConsecutiveFailures int
+	LastError           string
+	LastCheckedAt       *time.Time
+	ActiveHosts         int
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
 type PlanGroup struct {
 	ID                int64
 	GoodsTypeID       int64
@@ -49,6 +68,12 @@ type PlanGroup struct {
 	Visible           bool
 	CapacityRemaining int
 	SortOrder         int
+	// Extra IP add-ons, priced per address per month. A max of 0 means the
+	// family is not offered for this plan group.
+	UnitIPv4   int64
+	UnitIPv6   int64
+	AddIPv4Max int
+	AddIPv6Max int
 }
 
 type Package struct {
@@ -45,6 +45,12 @@ type CMSBlock struct {
 	UpdatedAt   time.Time
 }
 
+// UploadBackendLocal keeps uploads on the local disk under ./uploads. Other
+// upload backends are named "<plugin_id>/<instance_id>" of a storage plugin.
+const UploadBackendLocal = "local"
+
+// Upload is a stored file. It is always served at URL (/uploads/<ObjectKey>),
+// whichever Backend holds the content.
 type Upload struct {
 	ID         int64
 	Name       string
@@ -52,6 +58,8 @@ type Upload struct {
 	URL        string
 	Mime       string
 	Size       int64
+	Backend    string
+	ObjectKey  string
 	UploaderID int64
 	CreatedAt  time.Time
 }
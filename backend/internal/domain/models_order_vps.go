@@ -57,6 +57,8 @@ type VPSInstance struct {
 	OrderItemID          int64
 	AutomationInstanceID string
 	GoodsTypeID          int64
+	BackendPluginID      string
+	BackendInstanceID    string
 	Name                 string
 	Region               string
 	RegionID             int64
@@ -123,6 +125,21 @@ type ProvisionJob struct {
 	UpdatedAt   time.Time
 }
 
+// ProvisionAttempt records one try to create the host of an order item, or
+// the wallet refund issued after every try failed.
+type ProvisionAttempt struct {
+	ID           int64
+	OrderID      int64
+	OrderItemID  int64
+	Attempt      int
+	Strategy     ProvisionAttemptStrategy
+	LineID       int64
+	PackageID    int64
+	Success      bool
+	ErrorMessage string
+	CreatedAt    time.Time
+}
+
 type ResizeTask struct {
 	ID          int64
 	VPSID       int64
@@ -135,3 +152,170 @@ type ResizeTask struct {
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
+
+type VPSOperation struct {
+	ID       int64
+	VPSID    int64
+	UserID   int64
+	Action   VPSOperationAction
+	Status   VPSOperationStatus
+	Progress int
+	// ProgressEstimated marks Progress as a host-side estimate rather than a
+	// value reported by the plugin.
+	ProgressEstimated bool
+	Message           string
+	ParamsJSON        string
+	ErrorMessage      string
+	StartedAt         *time.Time
+	FinishedAt        *time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// VPSActivity is one entry of the instance activity feed shown to the owner.
+// ActorID is the user, API key or admin id depending on ActorType, and is zero
+// for the scheduler.
+type VPSActivity struct {
+	ID           int64
+	VPSID        int64
+	UserID       int64
+	ActorType    VPSActivityActorType
+	ActorID      int64
+	Action       string
+	Detail       string
+	IP           string
+	Result       VPSActivityResult
+	ErrorMessage string
+	CreatedAt    time.Time
+}
+
+// ReverseDNSChange records one PTR update. AdminID is set for admin overrides,
+// which skip forward confirmation.
+type ReverseDNSChange struct {
+	ID        int64
+	VPSID     int64
+	UserID    int64
+	AdminID   int64
+	IP        string
+	OldPTR    string
+	NewPTR    string
+	CreatedAt time.Time
+}
+
+// VPSExtraIP is an additional address bought as an add-on. OrderItemID is the
+// create or add_ip item that paid for it.
+type VPSExtraIP struct {
+	ID          int64
+	VPSID       int64
+	OrderItemID int64
+	Family      string
+	Address     string
+	CreatedAt   time.Time
+}
+
+// VPSRescueSession is one boot into the provider rescue system. EndedAt stays
+// nil while the instance is in rescue; EndReason is user, admin or timeout.
+type VPSRescueSession struct {
+	ID        int64
+	VPSID     int64
+	UserID    int64
+	ExpiresAt time.Time
+	EndedAt   *time.Time
+	EndReason string
+	CreatedAt time.Time
+}
+
+// VPSISOMount is the ISO currently attached to an instance.
+type VPSISOMount struct {
+	VPSID     int64
+	ISOID     string
+	ISOName   string
+	MountedAt time.Time
+}
+
+// ConsoleSession is one browser console opened through the backend proxy.
+// It is created pending when the ticket is issued, becomes active when the
+// ticket is redeemed and ended when the relay stops. ClientBytes and
+// UpstreamBytes count payload sent by the browser and by the console host.
+type ConsoleSession struct {
+	ID            int64
+	VPSID         int64
+	UserID        int64
+	Status        string
+	ClientIP      string
+	UpstreamHost  string
+	ExpiresAt     time.Time
+	StartedAt     *time.Time
+	EndedAt       *time.Time
+	EndReason     string
+	ClientBytes   int64
+	UpstreamBytes int64
+	CreatedAt     time.Time
+}
+
+type MetricResolution string
+
+const (
+	MetricResolutionRaw  MetricResolution = "raw"
+	MetricResolution5Min MetricResolution = "5m"
+	MetricResolutionHour MetricResolution = "1h"
+)
+
+// VPSMetricPoint is one monitoring bucket. Values are averages of the Samples
+// readings collected in [BucketAt, BucketAt+resolution).
+type VPSMetricPoint struct {
+	VPSID          int64
+	Resolution     MetricResolution
+	BucketAt       time.Time
+	CPUPercent     float64
+	MemoryPercent  float64
+	StoragePercent float64
+	BytesIn        int64
+	BytesOut       int64
+	Samples        int
+}
+
+// FirewallTemplate is a named set of firewall rules that can be attached to
+// many instances. UserID 0 marks an admin template; GoodsTypeID, only set on
+// admin templates, makes it a default attached to new instances of that goods
+// type.
+type FirewallTemplate struct {
+	ID          int64
+	UserID      int64
+	GoodsTypeID int64
+	Name        string
+	Description string
+	Rules       []FirewallTemplateRule
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// FirewallTemplateRule uses the automation firewall values: direction In/Out,
+// protocol tcp/udp/all, method allowed/denied.
+type FirewallTemplateRule struct {
+	Direction string
+	Protocol  string
+	Method    string
+	Port      string
+	IP        string
+	Priority  int
+}
+
+const (
+	FirewallSyncPending = "pending"
+	FirewallSyncSynced  = "synced"
+	FirewallSyncFailed  = "failed"
+)
+
+// VPSFirewallSync is the reconcile state of one instance. ManagedRules are
+// the provider rules that came from templates; rules added by hand are never
+// in it and are left alone.
+type VPSFirewallSync struct {
+	VPSID        int64
+	ManagedRules []FirewallTemplateRule
+	Status       string
+	LastError    string
+	Attempts     int
+	SyncedAt     *time.Time
+	UpdatedAt    time.Time
+}
@@ -119,6 +119,57 @@ const (
 	ResizeTaskStatusFailed  ResizeTaskStatus = "failed"
 )
 
+type VPSOperationAction string
+
+const (
+	VPSOperationResetOS         VPSOperationAction = "reset_os"
+	VPSOperationRestoreSnapshot VPSOperationAction = "restore_snapshot"
+	VPSOperationRestoreBackup   VPSOperationAction = "restore_backup"
+	VPSOperationElasticUpdate   VPSOperationAction = "elastic_update"
+)
+
+type VPSOperationStatus string
+
+const (
+	VPSOperationStatusPending   VPSOperationStatus = "pending"
+	VPSOperationStatusRunning   VPSOperationStatus = "running"
+	VPSOperationStatusSucceeded VPSOperationStatus = "succeeded"
+	VPSOperationStatusFailed    VPSOperationStatus = "failed"
+)
+
+type VPSActivityActorType string
+
+const (
+	VPSActivityActorUser      VPSActivityActorType = "user"
+	VPSActivityActorAPIKey    VPSActivityActorType = "api_key"
+	VPSActivityActorAdmin     VPSActivityActorType = "admin"
+	VPSActivityActorScheduler VPSActivityActorType = "scheduler"
+)
+
+type VPSActivityResult string
+
+const (
+	VPSActivityResultSuccess VPSActivityResult = "success"
+	VPSActivityResultFailed  VPSActivityResult = "failed"
+)
+
+type ProvisionAttemptStrategy string
+
+const (
+	ProvisionAttemptPrimary         ProvisionAttemptStrategy = "primary"
+	ProvisionAttemptSiblingLine     ProvisionAttemptStrategy = "sibling_line"
+	ProvisionAttemptFallbackPackage ProvisionAttemptStrategy = "fallback_package"
+	ProvisionAttemptRefund          ProvisionAttemptStrategy = "refund"
+)
+
+type AutomationBackendHealth string
+
+const (
+	AutomationBackendHealthy  AutomationBackendHealth = "healthy"
+	AutomationBackendDegraded AutomationBackendHealth = "degraded"
+	AutomationBackendDown     AutomationBackendHealth = "down"
+)
+
 type User struct {
 	ID                   int64
 	Username             string
@@ -188,6 +239,17 @@ type UserAPIKey struct {
 	LastUsedAt *time.Time
 }
 
+// SSHKey is a public key a user keeps on their account for injection into
+// new and reinstalled hosts.
+type SSHKey struct {
+	ID          int64
+	UserID      int64
+	Name        string
+	PublicKey   string
+	Fingerprint string
+	CreatedAt   time.Time
+}
+
 type RequestActor struct {
 	Mode          RequestActorMode
 	UserID        int64
@@ -32,3 +32,78 @@ type PluginPaymentMethod struct {
 	CreatedAt  time.Time
 	UpdatedAt  time.Time
 }
+
+// PluginKV is one entry of the key-value store a plugin instance reaches
+// through HostService.
+type PluginKV struct {
+	Category   string
+	PluginID   string
+	InstanceID string
+	Key        string
+	Value      []byte
+	ExpiresAt  *time.Time
+	UpdatedAt  time.Time
+}
+
+type PluginLog struct {
+	ID         int64
+	Category   string
+	PluginID   string
+	InstanceID string
+	Level      string
+	Message    string
+	FieldsJSON string
+	CreatedAt  time.Time
+}
+
+type PluginHookDeliveryStatus string
+
+const (
+	PluginHookDeliveryPending   PluginHookDeliveryStatus = "pending"
+	PluginHookDeliveryDelivered PluginHookDeliveryStatus = "delivered"
+	PluginHookDeliveryFailed    PluginHookDeliveryStatus = "failed"
+)
+
+// PluginHookDelivery is one event queued for one subscribed plugin instance.
+// EventID is shared by every delivery of the same event.
+type PluginHookDelivery struct {
+	ID            int64
+	EventID       string
+	Category      string
+	PluginID      string
+	InstanceID    string
+	Event         string
+	PayloadJSON   string
+	Status        PluginHookDeliveryStatus
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	OccurredAt    time.Time
+	DeliveredAt   *time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type PluginVersionStatus string
+
+const (
+	PluginVersionActive   PluginVersionStatus = "active"
+	PluginVersionInactive PluginVersionStatus = "inactive"
+	PluginVersionFailed   PluginVersionStatus = "failed"
+)
+
+// PluginVersion is one version of a plugin kept side by side on disk.
+// LastError holds why the most recent activation of the version was rolled
+// back.
+type PluginVersion struct {
+	ID              int64
+	Category        string
+	PluginID        string
+	Version         string
+	SignatureStatus PluginSignatureStatus
+	Status          PluginVersionStatus
+	LastError       string
+	ActivatedAt     *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
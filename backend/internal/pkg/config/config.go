@@ -26,6 +26,9 @@ type Config struct {
 	PluginMasterKey    string
 	PluginOfficialKeys []string
 	PluginsDir         string
+	// PluginRepositories are plugin index sources: local directories holding
+	// index.json or http(s) URLs of an index file.
+	PluginRepositories []string
 	APIBase            string
 	SiteName           string
 	SiteURL            string
@@ -44,6 +47,7 @@ type fileConfig struct {
 	PluginMasterKey    string   `json:"plugin_master_key" yaml:"plugin_master_key"`
 	PluginOfficialKeys []string `json:"plugin_official_ed25519_pubkeys" yaml:"plugin_official_ed25519_pubkeys"`
 	PluginsDir         string   `json:"plugins_dir" yaml:"plugins_dir"`
+	PluginRepositories []string `json:"plugin_repositories" yaml:"plugin_repositories"`
 
 	DB struct {
 		Type string `json:"type" yaml:"type"`
@@ -91,6 +95,11 @@ func Load() Config {
 		if strings.TrimSpace(cfg.PluginsDir) != "" && !filepath.IsAbs(cfg.PluginsDir) {
 			cfg.PluginsDir = filepath.Join(cfg.ConfigDir, cfg.PluginsDir)
 		}
+		for i, repo := range cfg.PluginRepositories {
+			if !strings.Contains(repo, "://") && !filepath.IsAbs(repo) {
+				cfg.PluginRepositories[i] = filepath.Join(cfg.ConfigDir, repo)
+			}
+		}
 	}
 
 	if strings.TrimSpace(cfg.JWTSecret) == "" {
@@ -304,6 +313,9 @@ func applyFileConfig(cfg *Config, fc *fileConfig) {
 	if strings.TrimSpace(fc.PluginsDir) != "" {
 		cfg.PluginsDir = strings.TrimSpace(fc.PluginsDir)
 	}
+	if repos := trimNonEmpty(fc.PluginRepositories); len(repos) > 0 {
+		cfg.PluginRepositories = repos
+	}
 	if strings.TrimSpace(fc.DB.Type) != "" {
 		cfg.DBType = strings.TrimSpace(fc.DB.Type)
 	}
@@ -343,6 +355,19 @@ func applyEnvConfig(cfg *Config) {
 	if v, ok := getEnvTrimmed("APP_PLUGINS_DIR"); ok {
 		cfg.PluginsDir = v
 	}
+	if v, ok := getEnvTrimmed("APP_PLUGIN_REPOSITORIES"); ok {
+		cfg.PluginRepositories = trimNonEmpty(strings.Split(v, ","))
+	}
+}
+
+func trimNonEmpty(items []string) []string {
+	var out []string
+	for _, it := range items {
+		if it = strings.TrimSpace(it); it != "" {
+			out = append(out, it)
+		}
+	}
+	return out
 }
 
 func getEnvTrimmed(key string) (string, bool) {
@@ -8,6 +8,12 @@ import (
 )
 
 func TestLoadConfigDefaults(t *testing.T) {
+	// Load persists a generated jwt_secret to the working directory.
+	td := t.TempDir()
+	oldWD, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(oldWD) })
+	_ = os.Chdir(td)
+
 	cfg := Load()
 	if cfg.Addr == "" {
 		t.Fatalf("expected defaults")
@@ -87,3 +93,31 @@ func TestLoadEnvOverridesDBConfig(t *testing.T) {
 		t.Fatalf("expected env db dsn override, got %q", cfg.DBDSN)
 	}
 }
+
+func TestLoadPluginRepositoriesResolvesLocalPaths(t *testing.T) {
+	td := t.TempDir()
+	oldWD, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(oldWD) })
+	_ = os.Chdir(td)
+
+	b := []byte("plugin_repositories:\n  - ./repo\n  - https://plugins.example.com/index.json\n")
+	if err := os.WriteFile(filepath.Join(td, localConfigYAML), b, 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg := Load()
+	if len(cfg.PluginRepositories) != 2 {
+		t.Fatalf("expected two repositories, got %v", cfg.PluginRepositories)
+	}
+	if cfg.PluginRepositories[0] != filepath.Join(cfg.ConfigDir, "repo") {
+		t.Fatalf("expected local repository resolved from config dir, got %q", cfg.PluginRepositories[0])
+	}
+	if cfg.PluginRepositories[1] != "https://plugins.example.com/index.json" {
+		t.Fatalf("expected url repository unchanged, got %q", cfg.PluginRepositories[1])
+	}
+
+	t.Setenv("APP_PLUGIN_REPOSITORIES", " /srv/plugins , ")
+	if cfg := Load(); len(cfg.PluginRepositories) != 1 || cfg.PluginRepositories[0] != "/srv/plugins" {
+		t.Fatalf("expected env repositories override, got %v", cfg.PluginRepositories)
+	}
+}
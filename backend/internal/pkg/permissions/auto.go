@@ -15,38 +15,41 @@ type moduleMeta struct {
 }
 
 var moduleMapping = map[string]moduleMeta{
-	"user":             {Display: "用户管理", SortOrder: 1},
-	"order":            {Display: "订单管理", SortOrder: 2},
-	"vps":              {Display: "VPS管理", SortOrder: 3},
-	"region":           {Display: "地区管理", SortOrder: 4},
-	"plan_group":       {Display: "线路管理", SortOrder: 5},
-	"line":             {Display: "线路管理", SortOrder: 5},
-	"package":          {Display: "套餐管理", SortOrder: 6},
-	"system_image":     {Display: "系统镜像", SortOrder: 7},
-	"billing_cycle":    {Display: "计费周期", SortOrder: 8},
-	"settings":         {Display: "系统设置", SortOrder: 9},
-	"debug":            {Display: "Debug", SortOrder: 9},
-	"automation":       {Display: "自动化平台", SortOrder: 10},
-	"robot":            {Display: "机器人配置", SortOrder: 11},
-	"smtp":             {Display: "SMTP配置", SortOrder: 12},
-	"sms":              {Display: "短信配置", SortOrder: 12},
-	"api_key":          {Display: "API密钥", SortOrder: 13},
-	"email_template":   {Display: "邮件模板", SortOrder: 14},
-	"sms_template":     {Display: "短信模板", SortOrder: 14},
-	"admin":            {Display: "管理员管理", SortOrder: 15},
-	"permission_group": {Display: "权限组", SortOrder: 16},
-	"permission":       {Display: "权限配置", SortOrder: 17},
-	"audit_log":        {Display: "审计日志", SortOrder: 18},
-	"dashboard":        {Display: "数据面板", SortOrder: 19},
-	"profile":          {Display: "个人中心", SortOrder: 20},
-	"cms_category":     {Display: "内容分类", SortOrder: 21},
-	"cms_post":         {Display: "内容管理", SortOrder: 22},
-	"cms_block":        {Display: "页面模块", SortOrder: 23},
-	"upload":           {Display: "资源上传", SortOrder: 24},
-	"tickets":          {Display: "工单管理", SortOrder: 25},
-	"goods_type":       {Display: "Goods Type", SortOrder: 6},
-	"plugin":           {Display: "Plugin", SortOrder: 26},
-	"probe":            {Display: "探针监控", SortOrder: 27},
+	"user":              {Display: "用户管理", SortOrder: 1},
+	"order":             {Display: "订单管理", SortOrder: 2},
+	"vps":               {Display: "VPS管理", SortOrder: 3},
+	"region":            {Display: "地区管理", SortOrder: 4},
+	"plan_group":        {Display: "线路管理", SortOrder: 5},
+	"line":              {Display: "线路管理", SortOrder: 5},
+	"package":           {Display: "套餐管理", SortOrder: 6},
+	"system_image":      {Display: "系统镜像", SortOrder: 7},
+	"billing_cycle":     {Display: "计费周期", SortOrder: 8},
+	"settings":          {Display: "系统设置", SortOrder: 9},
+	"debug":             {Display: "Debug", SortOrder: 9},
+	"automation":        {Display: "自动化平台", SortOrder: 10},
+	"robot":             {Display: "机器人配置", SortOrder: 11},
+	"smtp":              {Display: "SMTP配置", SortOrder: 12},
+	"sms":               {Display: "短信配置", SortOrder: 12},
+	"api_key":           {Display: "API密钥", SortOrder: 13},
+	"email_template":    {Display: "邮件模板", SortOrder: 14},
+	"sms_template":      {Display: "短信模板", SortOrder: 14},
+	"admin":             {Display: "管理员管理", SortOrder: 15},
+	"permission_group":  {Display: "权限组", SortOrder: 16},
+	"permission":        {Display: "权限配置", SortOrder: 17},
+	"audit_log":         {Display: "审计日志", SortOrder: 18},
+	"dashboard":         {Display: "数据面板", SortOrder: 19},
+	"profile":           {Display: "个人中心", SortOrder: 20},
+	"cms_category":      {Display: "内容分类", SortOrder: 21},
+	"cms_post":          {Display: "内容管理", SortOrder: 22},
+	"cms_block":         {Display: "页面模块", SortOrder: 23},
+	"upload":            {Display: "资源上传", SortOrder: 24},
+	"tickets":           {Display: "工单管理", SortOrder: 25},
+	"goods_type":        {Display: "Goods Type", SortOrder: 6},
+	"plugin":            {Display: "Plugin", SortOrder: 26},
+	"probe":             {Display: "探针监控", SortOrder: 27},
+	"abuse_case":        {Display: "滥用处理", SortOrder: 28},
+	"console_session":   {Display: "控制台会话", SortOrder: 29},
+	"firewall_template": {Display: "防火墙模板", SortOrder: 30},
 }
 
 var actionFriendlyName = map[string]string{
@@ -81,6 +84,16 @@ var actionFriendlyName = map[string]string{
 	"revenue_analytics_details":  "收入明细分析",
 	"vps_status":                 "VPS状态分布",
 	"tree":                       "权限树",
+	"ack":                        "受理",
+	"evidence":                   "添加证据",
+	"links":                      "关联资源",
+	"notes":                      "添加备注",
+	"warn":                       "警告用户",
+	"terminate":                  "终止",
+	"resolve":                    "结案",
+	"dismiss":                    "驳回",
+	"upgrade":                    "升级",
+	"rollback":                   "回滚",
 }
 
 var actionSortOrder = map[string]int{
@@ -115,6 +128,16 @@ var actionSortOrder = map[string]int{
 	"revenue_analytics_details":  29,
 	"vps_status":                 30,
 	"tree":                       31,
+	"ack":                        32,
+	"evidence":                   33,
+	"links":                      34,
+	"notes":                      35,
+	"warn":                       36,
+	"terminate":                  37,
+	"resolve":                    38,
+	"dismiss":                    39,
+	"upgrade":                    40,
+	"rollback":                   41,
 }
 
 func BuildFromRoutes(routes []gin.RouteInfo) []domain.PermissionDefinition {
@@ -280,6 +303,14 @@ func moduleFromSegments(segments []string) string {
 		return "integration"
 	case "probes":
 		return "probe"
+	case "abuse-cases":
+		return "abuse_case"
+	case "console-sessions":
+		return "console_session"
+	case "firewall-templates":
+		return "firewall_template"
+	case "plugin-jobs":
+		return "plugin_job"
 	default:
 		return strings.ReplaceAll(segments[0], "-", "_")
 	}
@@ -366,6 +397,13 @@ func actionFromSegments(method string, segments []string) (string, bool) {
 		if len(segments) == 2 && segments[1] == "install" && method == "POST" {
 			return "create", true
 		}
+		// /plugins/repository[/install]
+		if len(segments) == 2 && segments[1] == "repository" && method == "GET" {
+			return "list", true
+		}
+		if len(segments) == 3 && segments[1] == "repository" && segments[2] == "install" && method == "POST" {
+			return "create", true
+		}
 		if len(segments) >= 3 && strings.HasPrefix(segments[1], ":") && strings.HasPrefix(segments[2], ":") {
 			// /plugins/:category/:plugin_id/import
 			if len(segments) == 4 && segments[3] == "import" && method == "POST" {
@@ -379,6 +417,17 @@ func actionFromSegments(method string, segments []string) (string, bool) {
 			if len(segments) == 4 && segments[3] == "files" && method == "DELETE" {
 				return "delete", true
 			}
+			// /plugins/:category/:plugin_id/versions[/:version/rollback]
+			if len(segments) == 4 && segments[3] == "versions" && method == "GET" {
+				return "view", true
+			}
+			if len(segments) == 6 && segments[3] == "versions" && segments[5] == "rollback" && method == "POST" {
+				return "rollback", true
+			}
+			// /plugins/:category/:plugin_id/upgrade
+			if len(segments) == 4 && segments[3] == "upgrade" && method == "POST" {
+				return "upgrade", true
+			}
 			// legacy default-instance endpoints
 			if len(segments) == 3 && method == "DELETE" {
 				return "delete", true
@@ -403,6 +452,17 @@ func actionFromSegments(method string, segments []string) (string, bool) {
 				if len(segments) == 5 && (segments[4] == "enable" || segments[4] == "disable") && method == "POST" {
 					return "update", true
 				}
+				if len(segments) == 5 && segments[4] == "logs" && method == "GET" {
+					return "view", true
+				}
+				if len(segments) >= 5 && segments[4] == "hook-deliveries" {
+					if len(segments) == 5 && method == "GET" {
+						return "view", true
+					}
+					if len(segments) == 7 && segments[6] == "retry" && method == "POST" {
+						return "retry", true
+					}
+				}
 				if len(segments) >= 5 && segments[4] == "config" {
 					switch method {
 					case "GET":
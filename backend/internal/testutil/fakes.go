@@ -7,6 +7,7 @@ import (
 
 	"fmt"
 	appshared "xiaoheiplay/internal/app/shared"
+	"xiaoheiplay/internal/domain"
 )
 
 type FakeAutomationClient struct {
@@ -15,6 +16,11 @@ type FakeAutomationClient struct {
 	CreateHostRequests []appshared.AutomationCreateHostRequest
 	CreateHostResult   appshared.AutomationCreateHostResult
 	CreateHostErr      error
+	// CreateHostErrByLine fails create requests for the given line IDs.
+	CreateHostErrByLine map[int64]error
+	// Lines and Images are the catalog the fake panel lists.
+	Lines  []appshared.AutomationLine
+	Images []appshared.AutomationImage
 
 	HostInfo    map[int64]appshared.AutomationHostInfo
 	HostInfoErr error
@@ -41,20 +47,49 @@ type FakeAutomationClient struct {
 		HostID     int64
 		TemplateID int64
 		Password   string
+		Init       appshared.AutomationGuestInit
 	}
 	ResetOSPasswordCalls []struct {
 		HostID   int64
 		Password string
 	}
-	SnapshotList []appshared.AutomationSnapshot
-	BackupList   []appshared.AutomationBackup
-	FirewallList []appshared.AutomationFirewallRule
-	PortList     []appshared.AutomationPortMapping
+	SnapshotList       []appshared.AutomationSnapshot
+	ListSnapshotsErr   error
+	BackupList         []appshared.AutomationBackup
+	FirewallList       []appshared.AutomationFirewallRule
+	FirewallAddErr     error
+	ReverseDNS         []appshared.AutomationReverseDNSRecord
+	SetReverseDNSCalls []struct {
+		HostID int64
+		IP     string
+		PTR    string
+	}
+	PortList    []appshared.AutomationPortMapping
+	AssignIPErr error
+	AssignedIPs []string
+	ReleasedIPs []string
+	nextExtraIP int
+	RescueErr   error
+	RescueCalls []struct {
+		HostID   int64
+		Password string
+	}
+	ExitRescueCalls []int64
+	MountISOCalls   []struct {
+		HostID int64
+		ISO    string
+	}
+	UnmountISOCalls []int64
+	MonitorCalls    []int64
+	// VNCURL overrides the console URL returned by GetVNCURL.
+	VNCURL string
 }
 
 type FakeAutomationResolver struct {
 	Client appshared.AutomationClient
 	Err    error
+	// Backends overrides Client per "plugin/instance" key.
+	Backends map[string]appshared.AutomationClient
 }
 
 func (r *FakeAutomationResolver) ClientForGoodsType(ctx context.Context, goodsTypeID int64) (appshared.AutomationClient, error) {
@@ -66,6 +101,24 @@ func (r *FakeAutomationResolver) ClientForGoodsType(ctx context.Context, goodsTy
 	return r.Client, nil
 }
 
+func (r *FakeAutomationResolver) ClientForBackend(ctx context.Context, pluginID, instanceID string) (appshared.AutomationClient, error) {
+	_ = ctx
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	if client, ok := r.Backends[pluginID+"/"+instanceID]; ok {
+		return client, nil
+	}
+	return r.Client, nil
+}
+
+func (r *FakeAutomationResolver) ClientForInstance(ctx context.Context, inst domain.VPSInstance) (appshared.AutomationClient, error) {
+	if inst.BackendPluginID != "" && inst.BackendInstanceID != "" {
+		return r.ClientForBackend(ctx, inst.BackendPluginID, inst.BackendInstanceID)
+	}
+	return r.ClientForGoodsType(ctx, inst.GoodsTypeID)
+}
+
 func (f *FakeAutomationClient) CreateHost(ctx context.Context, req appshared.AutomationCreateHostRequest) (appshared.AutomationCreateHostResult, error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
@@ -73,6 +126,9 @@ func (f *FakeAutomationClient) CreateHost(ctx context.Context, req appshared.Aut
 	if f.CreateHostErr != nil {
 		return appshared.AutomationCreateHostResult{}, f.CreateHostErr
 	}
+	if err := f.CreateHostErrByLine[req.LineID]; err != nil {
+		return appshared.AutomationCreateHostResult{}, err
+	}
 	if f.CreateHostResult.HostID == 0 {
 		f.CreateHostResult.HostID = 1001
 	}
@@ -160,14 +216,15 @@ func (f *FakeAutomationClient) RebootHost(ctx context.Context, hostID int64) err
 	return nil
 }
 
-func (f *FakeAutomationClient) ResetOS(ctx context.Context, hostID int64, templateID int64, password string) error {
+func (f *FakeAutomationClient) ResetOS(ctx context.Context, hostID int64, templateID int64, password string, guest appshared.AutomationGuestInit) error {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 	f.ResetOSCalls = append(f.ResetOSCalls, struct {
 		HostID     int64
 		TemplateID int64
 		Password   string
-	}{HostID: hostID, TemplateID: templateID, Password: password})
+		Init       appshared.AutomationGuestInit
+	}{HostID: hostID, TemplateID: templateID, Password: password, Init: guest})
 	return nil
 }
 
@@ -182,6 +239,9 @@ func (f *FakeAutomationClient) ResetOSPassword(ctx context.Context, hostID int64
 }
 
 func (f *FakeAutomationClient) ListSnapshots(ctx context.Context, hostID int64) ([]appshared.AutomationSnapshot, error) {
+	if f.ListSnapshotsErr != nil {
+		return nil, f.ListSnapshotsErr
+	}
 	return f.SnapshotList, nil
 }
 
@@ -214,14 +274,123 @@ func (f *FakeAutomationClient) RestoreBackup(ctx context.Context, hostID int64,
 }
 
 func (f *FakeAutomationClient) ListFirewallRules(ctx context.Context, hostID int64) ([]appshared.AutomationFirewallRule, error) {
-	return f.FirewallList, nil
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]appshared.AutomationFirewallRule(nil), f.FirewallList...), nil
 }
 
 func (f *FakeAutomationClient) AddFirewallRule(ctx context.Context, req appshared.AutomationFirewallRuleCreate) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.FirewallAddErr != nil {
+		return f.FirewallAddErr
+	}
+	var nextID int64 = 1
+	for _, rule := range f.FirewallList {
+		if id, ok := rule["id"].(int64); ok && id >= nextID {
+			nextID = id + 1
+		}
+	}
+	f.FirewallList = append(f.FirewallList, appshared.AutomationFirewallRule{
+		"id":        nextID,
+		"direction": req.Direction,
+		"protocol":  req.Protocol,
+		"method":    req.Method,
+		"port":      req.Port,
+		"ip":        req.IP,
+		"priority":  req.Priority,
+	})
 	return nil
 }
 
 func (f *FakeAutomationClient) DeleteFirewallRule(ctx context.Context, hostID int64, ruleID int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, rule := range f.FirewallList {
+		if id, ok := rule["id"].(int64); ok && id == ruleID {
+			f.FirewallList = append(f.FirewallList[:i], f.FirewallList[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (f *FakeAutomationClient) ListReverseDNS(ctx context.Context, hostID int64) ([]appshared.AutomationReverseDNSRecord, error) {
+	return f.ReverseDNS, nil
+}
+
+func (f *FakeAutomationClient) SetReverseDNS(ctx context.Context, hostID int64, ip, ptr string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.SetReverseDNSCalls = append(f.SetReverseDNSCalls, struct {
+		HostID int64
+		IP     string
+		PTR    string
+	}{HostID: hostID, IP: ip, PTR: ptr})
+	for i := range f.ReverseDNS {
+		if f.ReverseDNS[i].IP == ip {
+			f.ReverseDNS[i].PTR = ptr
+		}
+	}
+	return nil
+}
+
+func (f *FakeAutomationClient) AssignIP(ctx context.Context, hostID int64, family string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.AssignIPErr != nil {
+		return "", f.AssignIPErr
+	}
+	f.nextExtraIP++
+	ip := fmt.Sprintf("198.51.100.%d", f.nextExtraIP)
+	if family == "ipv6" {
+		ip = fmt.Sprintf("2001:db8::%x", f.nextExtraIP)
+	}
+	f.AssignedIPs = append(f.AssignedIPs, ip)
+	return ip, nil
+}
+
+func (f *FakeAutomationClient) ReleaseIP(ctx context.Context, hostID int64, ip string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ReleasedIPs = append(f.ReleasedIPs, ip)
+	return nil
+}
+
+func (f *FakeAutomationClient) EnterRescue(ctx context.Context, hostID int64, password string) (appshared.AutomationRescueCredentials, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.RescueErr != nil {
+		return appshared.AutomationRescueCredentials{}, f.RescueErr
+	}
+	f.RescueCalls = append(f.RescueCalls, struct {
+		HostID   int64
+		Password string
+	}{HostID: hostID, Password: password})
+	return appshared.AutomationRescueCredentials{Username: "root", Password: password}, nil
+}
+
+func (f *FakeAutomationClient) ExitRescue(ctx context.Context, hostID int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ExitRescueCalls = append(f.ExitRescueCalls, hostID)
+	return nil
+}
+
+func (f *FakeAutomationClient) MountISO(ctx context.Context, hostID int64, iso string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.MountISOCalls = append(f.MountISOCalls, struct {
+		HostID int64
+		ISO    string
+	}{HostID: hostID, ISO: iso})
+	return nil
+}
+
+func (f *FakeAutomationClient) UnmountISO(ctx context.Context, hostID int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.UnmountISOCalls = append(f.UnmountISOCalls, hostID)
 	return nil
 }
 
@@ -250,11 +419,11 @@ func (f *FakeAutomationClient) ListAreas(ctx context.Context) ([]appshared.Autom
 }
 
 func (f *FakeAutomationClient) ListImages(ctx context.Context, lineID int64) ([]appshared.AutomationImage, error) {
-	return []appshared.AutomationImage{}, nil
+	return append([]appshared.AutomationImage{}, f.Images...), nil
 }
 
 func (f *FakeAutomationClient) ListLines(ctx context.Context) ([]appshared.AutomationLine, error) {
-	return []appshared.AutomationLine{}, nil
+	return append([]appshared.AutomationLine{}, f.Lines...), nil
 }
 
 func (f *FakeAutomationClient) ListProducts(ctx context.Context, lineID int64) ([]appshared.AutomationProduct, error) {
@@ -262,10 +431,16 @@ func (f *FakeAutomationClient) ListProducts(ctx context.Context, lineID int64) (
 }
 
 func (f *FakeAutomationClient) GetMonitor(ctx context.Context, hostID int64) (appshared.AutomationMonitor, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.MonitorCalls = append(f.MonitorCalls, hostID)
 	return appshared.AutomationMonitor{CPUPercent: 10, MemoryPercent: 20}, nil
 }
 
 func (f *FakeAutomationClient) GetVNCURL(ctx context.Context, hostID int64) (string, error) {
+	if f.VNCURL != "" {
+		return f.VNCURL, nil
+	}
 	return "https://vnc.local/" + "host", nil
 }
 
@@ -402,6 +577,32 @@ func (f *FakeRobotNotifier) NotifyOrderPending(ctx context.Context, payload apps
 	return f.Err
 }
 
+// FakeCaptchaProvider passes answers whose token equals Token, the same rule
+// the mock_captcha demo plugin applies.
+type FakeCaptchaProvider struct {
+	mu      sync.Mutex
+	Token   string
+	Scenes  []string
+	Answers []appshared.CaptchaAnswer
+}
+
+func (f *FakeCaptchaProvider) Init(ctx context.Context, pluginID, instanceID, scene, remoteIP string) (appshared.CaptchaChallenge, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Scenes = append(f.Scenes, scene)
+	return appshared.CaptchaChallenge{Widget: "mock", Params: map[string]string{"plugin_id": pluginID, "instance_id": instanceID}}, nil
+}
+
+func (f *FakeCaptchaProvider) Verify(ctx context.Context, pluginID, instanceID string, answer appshared.CaptchaAnswer) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Answers = append(f.Answers, answer)
+	if f.Token == "" || answer.Token != f.Token {
+		return domain.ErrCaptchaFailed
+	}
+	return nil
+}
+
 type FakeRealNameProvider struct {
 	KeyVal  string
 	NameVal string
@@ -10,6 +10,8 @@ import (
 	"xiaoheiplay/internal/adapter/http"
 	"xiaoheiplay/internal/adapter/repo/core"
 	"xiaoheiplay/internal/adapter/sse"
+	"xiaoheiplay/internal/adapter/storage"
+	appabuse "xiaoheiplay/internal/app/abuse"
 	appadmin "xiaoheiplay/internal/app/admin"
 	appadminvps "xiaoheiplay/internal/app/adminvps"
 	appauth "xiaoheiplay/internal/app/auth"
@@ -17,21 +19,28 @@ import (
 	appcart "xiaoheiplay/internal/app/cart"
 	appcatalog "xiaoheiplay/internal/app/catalog"
 	appcms "xiaoheiplay/internal/app/cms"
+	appconsole "xiaoheiplay/internal/app/console"
+	appfirewall "xiaoheiplay/internal/app/firewall"
 	appgoodstype "xiaoheiplay/internal/app/goodstype"
 	appintegration "xiaoheiplay/internal/app/integration"
 	appmessage "xiaoheiplay/internal/app/message"
+	appmetrics "xiaoheiplay/internal/app/metrics"
 	appnotification "xiaoheiplay/internal/app/notification"
 	apporder "xiaoheiplay/internal/app/order"
 	apporderevent "xiaoheiplay/internal/app/orderevent"
 	apppayment "xiaoheiplay/internal/app/payment"
 	apppermission "xiaoheiplay/internal/app/permission"
+	apprdns "xiaoheiplay/internal/app/rdns"
 	apprealname "xiaoheiplay/internal/app/realname"
 	appreport "xiaoheiplay/internal/app/report"
+	apprescue "xiaoheiplay/internal/app/rescue"
 	appsecurityticket "xiaoheiplay/internal/app/securityticket"
 	appsettings "xiaoheiplay/internal/app/settings"
+	appsshkey "xiaoheiplay/internal/app/sshkey"
 	appticket "xiaoheiplay/internal/app/ticket"
 	appupload "xiaoheiplay/internal/app/upload"
 	appvps "xiaoheiplay/internal/app/vps"
+	appvpsoperation "xiaoheiplay/internal/app/vpsoperation"
 	appwallet "xiaoheiplay/internal/app/wallet"
 	appwalletorder "xiaoheiplay/internal/app/walletorder"
 	"xiaoheiplay/internal/domain"
@@ -45,6 +54,7 @@ type Env struct {
 	PaymentReg    *testutil.FakePaymentRegistry
 	Email         *testutil.FakeEmailSender
 	Robot         *testutil.FakeRobotNotifier
+	Captcha       *testutil.FakeCaptchaProvider
 	RealnameReg   *testutil.FakeRealNameRegistry
 	Broker        *sse.Broker
 	AuthSvc       *appauth.Service
@@ -52,6 +62,7 @@ type Env struct {
 	CartSvc       *appcart.Service
 	OrderSvc      *apporder.Service
 	VpsSvc        *appvps.Service
+	VPSOperations *appvpsoperation.Service
 	AdminSvc      *appadmin.Service
 	AdminVPSSvc   *appadminvps.Service
 	PermissionSvc *apppermission.Service
@@ -77,6 +88,7 @@ func NewTestEnv(t *testing.T, withCMS bool) *Env {
 	paymentReg := testutil.NewFakePaymentRegistry()
 	email := &testutil.FakeEmailSender{}
 	robot := &testutil.FakeRobotNotifier{}
+	captcha := &testutil.FakeCaptchaProvider{Token: "mock-pass"}
 	realnameReg := testutil.NewFakeRealNameRegistry()
 	broker := sse.NewBroker(repoSQLite)
 
@@ -87,14 +99,41 @@ func NewTestEnv(t *testing.T, withCMS bool) *Env {
 	realnameSvc := apprealname.NewService(repoSQLite, realnameReg, repoSQLite)
 	orderSvc := apporder.NewService(repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite, broker, automationResolver, robot, repoSQLite, repoSQLite, email, repoSQLite, repoSQLite, repoSQLite, repoSQLite, messageSvc, realnameSvc)
 	vpsSvc := appvps.NewService(repoSQLite, automationResolver, repoSQLite)
+	vpsSvc.SetActionUsage(repoSQLite)
+	vpsSvc.SetActivityLog(repoSQLite)
+	vpsOperationFeed := sse.NewVPSOperationBroker()
+	vpsOperationSvc := appvpsoperation.NewService(repoSQLite, repoSQLite, repoSQLite, vpsSvc, vpsOperationFeed)
+	orderSvc.SetVPSOperationTracker(vpsOperationSvc)
+	goodsTypeSvc.SetBackendPool(repoSQLite, automationResolver)
+	orderSvc.SetBackendPlacer(goodsTypeSvc)
+	sshKeySvc := appsshkey.NewService(repoSQLite)
+	reverseDNSSvc := apprdns.NewService(repoSQLite, automationResolver, repoSQLite, repoSQLite)
+	rescueSvc := apprescue.NewService(repoSQLite, automationResolver, repoSQLite, repoSQLite, repoSQLite)
+	metricsSvc := appmetrics.NewService(repoSQLite, automationResolver, repoSQLite, repoSQLite)
+	orderSvc.SetGuestInit(repoSQLite, nil)
+	orderSvc.SetExtraIPs(repoSQLite)
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	t.Cleanup(stopWorker)
+	go vpsOperationSvc.Start(workerCtx)
 	adminSvc := appadmin.NewService(repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite)
 	adminVPSSvc := appadminvps.NewService(repoSQLite, automationResolver, repoSQLite, repoSQLite, repoSQLite, messageSvc)
+	adminVPSSvc.SetActivityLog(repoSQLite)
+	abuseSvc := appabuse.NewService(repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite)
+	abuseSvc.SetEnforcer(adminVPSSvc)
+	abuseSvc.SetMessageCenter(messageSvc)
+	firewallSvc := appfirewall.NewService(repoSQLite, repoSQLite, automationResolver, repoSQLite)
+	firewallSvc.SetQuotaPolicy(vpsSvc)
+	orderSvc.SetFirewallTemplates(firewallSvc)
+	orderSvc.SetProvisionAttempts(repoSQLite)
+	vpsOperationSvc.SetFirewallTemplates(firewallSvc)
 	authSvc := appauth.NewService(repoSQLite, repoSQLite, repoSQLite)
 	permissionSvc := apppermission.NewService(repoSQLite, repoSQLite, repoSQLite)
 	paymentSvc := apppayment.NewService(repoSQLite, repoSQLite, repoSQLite, paymentReg, repoSQLite, orderSvc, broker)
 	walletSvc := appwallet.NewService(repoSQLite, repoSQLite)
 	walletOrderSvc := appwalletorder.NewService(repoSQLite, repoSQLite, repoSQLite, repoSQLite, repoSQLite, automationResolver, repoSQLite)
 	uploadSvc := appupload.NewService(repoSQLite)
+	uploadSvc.SetSettings(repoSQLite)
+	uploadSvc.SetStorage(storage.NewResolver(storage.NewLocal("uploads"), nil))
 	autoLogSvc := appautomationlog.NewService(repoSQLite)
 	orderEventSvc := apporderevent.NewService(repoSQLite)
 	securityTicketSvc := appsecurityticket.NewService(repoSQLite)
@@ -107,6 +146,7 @@ func NewTestEnv(t *testing.T, withCMS bool) *Env {
 	seedDefaultGoodsType(t, repoSQLite)
 
 	jwtSecret := "test-secret"
+	consoleSvc := appconsole.NewService(repoSQLite, automationResolver, repoSQLite, repoSQLite, repoSQLite, jwtSecret)
 	handler := http.NewHandler(http.HandlerDeps{
 		AuthSvc:           authSvc,
 		CatalogSvc:        catalogSvc,
@@ -130,10 +170,20 @@ func NewTestEnv(t *testing.T, withCMS bool) *Env {
 		SettingsSvc:       settingsSvc,
 		UploadSvc:         uploadSvc,
 		Broker:            broker,
+		VPSOperationSvc:   vpsOperationSvc,
+		VPSOperationFeed:  vpsOperationFeed,
 		JWTSecret:         jwtSecret,
 		SecurityTicketSvc: securityTicketSvc,
 		PermissionSvc:     permissionSvc,
+		SSHKeySvc:         sshKeySvc,
+		ReverseDNSSvc:     reverseDNSSvc,
+		RescueSvc:         rescueSvc,
+		MetricsSvc:        metricsSvc,
+		AbuseSvc:          abuseSvc,
+		ConsoleSvc:        consoleSvc,
+		FirewallSvc:       firewallSvc,
 		EmailSender:       adapteremail.NewSender(repoSQLite),
+		CaptchaProvider:   captcha,
 	})
 	middleware := http.NewMiddleware(jwtSecret, nil, nil, permissionSvc, authSvc, settingsSvc)
 	server := http.NewServer(handler, middleware)
@@ -145,6 +195,7 @@ func NewTestEnv(t *testing.T, withCMS bool) *Env {
 		PaymentReg:    paymentReg,
 		Email:         email,
 		Robot:         robot,
+		Captcha:       captcha,
 		RealnameReg:   realnameReg,
 		Broker:        broker,
 		AuthSvc:       authSvc,
@@ -152,6 +203,7 @@ func NewTestEnv(t *testing.T, withCMS bool) *Env {
 		CartSvc:       cartSvc,
 		OrderSvc:      orderSvc,
 		VpsSvc:        vpsSvc,
+		VPSOperations: vpsOperationSvc,
 		AdminSvc:      adminSvc,
 		AdminVPSSvc:   adminVPSSvc,
 		PermissionSvc: permissionSvc,
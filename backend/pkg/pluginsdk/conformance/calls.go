@@ -0,0 +1,120 @@
+package conformance
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/proto"
+
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+type invokeFunc func(ctx context.Context, cli pluginv1.AutomationServiceClient, fx Fixture, instanceID int64) (proto.Message, error)
+
+// call is one AutomationService RPC the suite exercises.
+//
+// Read-only calls are checked for success, error mapping and argument
+// validation. Idempotent calls set a state and must succeed when repeated.
+// Calls that are neither are only used to probe undeclared features.
+type call struct {
+	name       string
+	feature    pluginv1.AutomationFeature
+	readOnly   bool
+	idempotent bool
+	instance   bool
+	invoke     invokeFunc
+}
+
+var calls = []call{
+	{name: "ListAreas", feature: pluginv1.AutomationFeature_AUTOMATION_FEATURE_CATALOG_SYNC, readOnly: true,
+		invoke: func(ctx context.Context, cli pluginv1.AutomationServiceClient, _ Fixture, _ int64) (proto.Message, error) {
+			return cli.ListAreas(ctx, &pluginv1.Empty{})
+		}},
+	{name: "ListLines", feature: pluginv1.AutomationFeature_AUTOMATION_FEATURE_CATALOG_SYNC, readOnly: true,
+		invoke: func(ctx context.Context, cli pluginv1.AutomationServiceClient, _ Fixture, _ int64) (proto.Message, error) {
+			return cli.ListLines(ctx, &pluginv1.Empty{})
+		}},
+	{name: "ListPackages", feature: pluginv1.AutomationFeature_AUTOMATION_FEATURE_CATALOG_SYNC, readOnly: true,
+		invoke: func(ctx context.Context, cli pluginv1.AutomationServiceClient, fx Fixture, _ int64) (proto.Message, error) {
+			return cli.ListPackages(ctx, &pluginv1.ListPackagesRequest{LineId: fx.LineID})
+		}},
+	{name: "ListImages", feature: pluginv1.AutomationFeature_AUTOMATION_FEATURE_CATALOG_SYNC, readOnly: true,
+		invoke: func(ctx context.Context, cli pluginv1.AutomationServiceClient, fx Fixture, _ int64) (proto.Message, error) {
+			return cli.ListImages(ctx, &pluginv1.ListImagesRequest{LineId: fx.LineID})
+		}},
+	{name: "GetInstance", feature: pluginv1.AutomationFeature_AUTOMATION_FEATURE_LIFECYCLE, readOnly: true, instance: true,
+		invoke: func(ctx context.Context, cli pluginv1.AutomationServiceClient, _ Fixture, id int64) (proto.Message, error) {
+			return cli.GetInstance(ctx, &pluginv1.GetInstanceRequest{InstanceId: id})
+		}},
+	{name: "ListInstancesSimple", feature: pluginv1.AutomationFeature_AUTOMATION_FEATURE_LIFECYCLE, readOnly: true,
+		invoke: func(ctx context.Context, cli pluginv1.AutomationServiceClient, fx Fixture, _ int64) (proto.Message, error) {
+			return cli.ListInstancesSimple(ctx, &pluginv1.ListInstancesSimpleRequest{SearchTag: fx.InstanceName})
+		}},
+	{name: "GetPanelURL", feature: pluginv1.AutomationFeature_AUTOMATION_FEATURE_LIFECYCLE, readOnly: true,
+		invoke: func(ctx context.Context, cli pluginv1.AutomationServiceClient, fx Fixture, _ int64) (proto.Message, error) {
+			return cli.GetPanelURL(ctx, &pluginv1.GetPanelURLRequest{InstanceName: fx.InstanceName, PanelPassword: fx.PanelPassword})
+		}},
+	{name: "GetVNCURL", feature: pluginv1.AutomationFeature_AUTOMATION_FEATURE_LIFECYCLE, readOnly: true, instance: true,
+		invoke: func(ctx context.Context, cli pluginv1.AutomationServiceClient, _ Fixture, id int64) (proto.Message, error) {
+			return cli.GetVNCURL(ctx, &pluginv1.GetVNCURLRequest{InstanceId: id})
+		}},
+	{name: "GetMonitor", feature: pluginv1.AutomationFeature_AUTOMATION_FEATURE_LIFECYCLE, readOnly: true, instance: true,
+		invoke: func(ctx context.Context, cli pluginv1.AutomationServiceClient, _ Fixture, id int64) (proto.Message, error) {
+			return cli.GetMonitor(ctx, &pluginv1.GetMonitorRequest{InstanceId: id})
+		}},
+	{name: "Start", feature: pluginv1.AutomationFeature_AUTOMATION_FEATURE_LIFECYCLE, idempotent: true, instance: true,
+		invoke: func(ctx context.Context, cli pluginv1.AutomationServiceClient, _ Fixture, id int64) (proto.Message, error) {
+			return cli.Start(ctx, &pluginv1.StartRequest{InstanceId: id})
+		}},
+	{name: "Shutdown", feature: pluginv1.AutomationFeature_AUTOMATION_FEATURE_LIFECYCLE, idempotent: true, instance: true,
+		invoke: func(ctx context.Context, cli pluginv1.AutomationServiceClient, _ Fixture, id int64) (proto.Message, error) {
+			return cli.Shutdown(ctx, &pluginv1.ShutdownRequest{InstanceId: id})
+		}},
+	{name: "Lock", feature: pluginv1.AutomationFeature_AUTOMATION_FEATURE_LIFECYCLE, idempotent: true, instance: true,
+		invoke: func(ctx context.Context, cli pluginv1.AutomationServiceClient, _ Fixture, id int64) (proto.Message, error) {
+			return cli.Lock(ctx, &pluginv1.LockRequest{InstanceId: id})
+		}},
+	{name: "Unlock", feature: pluginv1.AutomationFeature_AUTOMATION_FEATURE_LIFECYCLE, idempotent: true, instance: true,
+		invoke: func(ctx context.Context, cli pluginv1.AutomationServiceClient, _ Fixture, id int64) (proto.Message, error) {
+			return cli.Unlock(ctx, &pluginv1.UnlockRequest{InstanceId: id})
+		}},
+	{name: "ListPortMappings", feature: pluginv1.AutomationFeature_AUTOMATION_FEATURE_PORT_MAPPING, readOnly: true, instance: true,
+		invoke: func(ctx context.Context, cli pluginv1.AutomationServiceClient, _ Fixture, id int64) (proto.Message, error) {
+			return cli.ListPortMappings(ctx, &pluginv1.ListPortMappingsRequest{InstanceId: id})
+		}},
+	{name: "ListBackups", feature: pluginv1.AutomationFeature_AUTOMATION_FEATURE_BACKUP, readOnly: true, instance: true,
+		invoke: func(ctx context.Context, cli pluginv1.AutomationServiceClient, _ Fixture, id int64) (proto.Message, error) {
+			return cli.ListBackups(ctx, &pluginv1.ListBackupsRequest{InstanceId: id})
+		}},
+	{name: "ListSnapshots", feature: pluginv1.AutomationFeature_AUTOMATION_FEATURE_SNAPSHOT, readOnly: true, instance: true,
+		invoke: func(ctx context.Context, cli pluginv1.AutomationServiceClient, _ Fixture, id int64) (proto.Message, error) {
+			return cli.ListSnapshots(ctx, &pluginv1.ListSnapshotsRequest{InstanceId: id})
+		}},
+	{name: "ListFirewallRules", feature: pluginv1.AutomationFeature_AUTOMATION_FEATURE_FIREWALL, readOnly: true, instance: true,
+		invoke: func(ctx context.Context, cli pluginv1.AutomationServiceClient, _ Fixture, id int64) (proto.Message, error) {
+			return cli.ListFirewallRules(ctx, &pluginv1.ListFirewallRulesRequest{InstanceId: id})
+		}},
+	{name: "ListReverseDNS", feature: pluginv1.AutomationFeature_AUTOMATION_FEATURE_REVERSE_DNS, readOnly: true, instance: true,
+		invoke: func(ctx context.Context, cli pluginv1.AutomationServiceClient, _ Fixture, id int64) (proto.Message, error) {
+			return cli.ListReverseDNS(ctx, &pluginv1.ListReverseDNSRequest{InstanceId: id})
+		}},
+	{name: "AssignIP", feature: pluginv1.AutomationFeature_AUTOMATION_FEATURE_EXTRA_IP, instance: true,
+		invoke: func(ctx context.Context, cli pluginv1.AutomationServiceClient, _ Fixture, id int64) (proto.Message, error) {
+			return cli.AssignIP(ctx, &pluginv1.AssignIPRequest{InstanceId: id, Family: "ipv4"})
+		}},
+	{name: "ExitRescue", feature: pluginv1.AutomationFeature_AUTOMATION_FEATURE_RESCUE, instance: true,
+		invoke: func(ctx context.Context, cli pluginv1.AutomationServiceClient, _ Fixture, id int64) (proto.Message, error) {
+			return cli.ExitRescue(ctx, &pluginv1.ExitRescueRequest{InstanceId: id})
+		}},
+}
+
+// probeFor returns the call used to check that an undeclared feature is
+// reported as unimplemented. Features without an RPC of their own, such as
+// cloud_init, have none.
+func probeFor(feature pluginv1.AutomationFeature) (call, bool) {
+	for _, c := range calls {
+		if c.feature == feature {
+			return c, true
+		}
+	}
+	return call{}, false
+}
@@ -0,0 +1,57 @@
+package conformance_test
+
+import (
+	"net/http"
+	"testing"
+
+	"xiaoheiplay/pkg/pluginsdk/conformance"
+)
+
+func TestRun_DemoPlugin(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and launches a plugin binary")
+	}
+	conformance.Run(t, conformance.Suite{
+		PluginDir: conformance.Build(t, "./testdata/demo_automation", "testdata/demo_automation/manifest.json"),
+		Config: func(url string) string {
+			return `{"base_url":"` + url + `"}`
+		},
+		Routes: []conformance.Route{
+			{Method: http.MethodGet, Path: "/areas", Body: `[{"id":1,"name":"Asia","state":1}]`},
+			{Method: http.MethodGet, Path: "/lines", Body: `[{"id":10,"name":"HK","area_id":1,"state":1}]`},
+			{Method: http.MethodGet, Path: "/packages", Body: `[{"id":100,"name":"S1","cpu":1,"memory_gb":1}]`},
+			{Method: http.MethodGet, Path: "/images", Body: `[{"id":5,"name":"Debian 12","type":"linux"}]`},
+			{Method: http.MethodGet, Path: "/instances", Body: `[{"id":7,"name":"vm-7","ip":"203.0.113.7"}]`},
+			{Method: http.MethodGet, Path: "/panel", Body: `{"url":"https://panel.example/login"}`},
+			{Method: http.MethodGet, Path: "/instances/7", Body: `{"id":7,"name":"vm-7","state":2}`},
+			{Method: http.MethodGet, Path: "/instances/7/vnc", Body: `{"url":"wss://vnc.example/7"}`},
+			{Method: http.MethodGet, Path: "/instances/7/monitor", Body: `{"cpu":3}`},
+			{Method: http.MethodGet, Path: "/instances/7/snapshots", Body: `[]`},
+			{Method: http.MethodPost, Path: "/instances/7/*", Body: `{}`},
+		},
+		Fixture: conformance.Fixture{InstanceID: 7, InstanceName: "vm-7", PanelPassword: "secret", LineID: 10},
+	})
+}
+
+func TestUpstream_ScriptsAndRecords(t *testing.T) {
+	up := conformance.NewUpstream(conformance.Route{Method: http.MethodGet, Path: "/v1/*", Status: http.StatusAccepted, Body: `{}`})
+	defer up.Close()
+	resp, err := http.Get(up.URL() + "/v1/things?x=1")
+	if err != nil || resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("expected scripted prefix route, got %v %v", resp, err)
+	}
+	resp.Body.Close()
+	if resp, _ = http.Post(up.URL()+"/v1/things", "application/json", nil); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected method mismatch to 404, got %d", resp.StatusCode)
+	}
+	resp.Body.Close()
+	up.FailWith(http.StatusBadGateway)
+	if resp, _ = http.Get(up.URL() + "/v1/things"); resp.StatusCode != http.StatusBadGateway {
+		t.Fatalf("expected forced failure, got %d", resp.StatusCode)
+	}
+	resp.Body.Close()
+	reqs := up.Requests()
+	if len(reqs) != 3 || reqs[0].Query != "x=1" || reqs[1].Method != http.MethodPost {
+		t.Fatalf("unexpected recorded requests %+v", reqs)
+	}
+}
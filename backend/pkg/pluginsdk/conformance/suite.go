@@ -0,0 +1,351 @@
+// Package conformance checks an automation plugin against the contract the
+// host relies on. A suite starts the plugin binary through the same runtime
+// the server uses, points it at a scripted local upstream and exercises each
+// AutomationService RPC against the features the plugin declares:
+//
+//   - declared features: read-only RPCs succeed, state-setting RPCs can be
+//     repeated, missing instance IDs are rejected with InvalidArgument, and
+//     upstream failures surface as errors instead of empty results;
+//   - undeclared features: RPCs return Unimplemented without calling the
+//     upstream, which the host reports as "not supported";
+//   - not_supported_reasons only name known, undeclared features.
+//
+// Plugin authors call Run from an ordinary go test:
+//
+//	func TestConformance(t *testing.T) {
+//		conformance.Run(t, conformance.Suite{
+//			PluginDir: conformance.Build(t, ".", "manifest.json"),
+//			Config: func(url string) string {
+//				return `{"base_url":"` + url + `","api_key":"test"}`
+//			},
+//			Routes:  []conformance.Route{{Method: "GET", Path: "/api/areas", Body: `[]`}},
+//			Fixture: conformance.Fixture{InstanceID: 1, LineID: 1},
+//		})
+//	}
+//
+// Every check is a subtest named <kind>/<name>, e.g. "idempotent/Start";
+// list names in Suite.Skip to waive them.
+package conformance
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	plugins "xiaoheiplay/internal/adapter/plugins/core"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+const instanceID = "conformance"
+
+// Fixture holds the upstream IDs the scripted routes answer for.
+type Fixture struct {
+	InstanceID    int64
+	InstanceName  string
+	PanelPassword string
+	LineID        int64
+}
+
+type Suite struct {
+	// PluginDir holds manifest.json and the plugin entry, as installed under
+	// plugins/automation/<plugin_id>. See Build.
+	PluginDir string
+	// Config renders the instance config for the upstream base URL.
+	Config func(upstreamURL string) string
+	// Routes script the upstream stand-in.
+	Routes  []Route
+	Fixture Fixture
+	// RequireReasons fails undeclared features that have no
+	// not_supported_reasons entry.
+	RequireReasons bool
+	// Skip lists check names to waive.
+	Skip []string
+	// Timeout bounds each RPC. It defaults to 10 seconds.
+	Timeout time.Duration
+}
+
+// Build compiles pkg, a go build target relative to the test's working
+// directory, into a temporary plugin directory next to a copy of
+// manifestPath. The binary goes where the runtime will look for it: the
+// manifest binaries entry for the current platform, or ./plugin.
+func Build(t *testing.T, pkg, manifestPath string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := copyFile(manifestPath, filepath.Join(dir, "manifest.json"), 0o644); err != nil {
+		t.Fatalf("copy manifest: %v", err)
+	}
+	manifest, err := plugins.ReadManifest(dir)
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	rel := "plugin"
+	if runtime.GOOS == "windows" {
+		rel = "plugin.exe"
+	}
+	if len(manifest.Binaries) > 0 {
+		rel = manifest.Binaries[plugins.CurrentPlatformKey()]
+		if rel == "" {
+			t.Fatalf("manifest has no binary for %s", plugins.CurrentPlatformKey())
+		}
+	}
+	out := filepath.Join(dir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+		t.Fatalf("create binary dir: %v", err)
+	}
+	cmd := exec.Command("go", "build", "-o", out, pkg)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("build plugin %s: %v\n%s", pkg, err, output)
+	}
+	return dir
+}
+
+// Run starts the plugin and runs every check as a subtest.
+func Run(t *testing.T, s Suite) {
+	t.Helper()
+	manifestJSON, err := plugins.ReadManifest(s.PluginDir)
+	if err != nil {
+		t.Fatalf("read manifest: %v", err)
+	}
+	pluginID := manifestJSON.PluginID
+
+	upstream := NewUpstream(s.Routes...)
+	t.Cleanup(upstream.Close)
+	base := t.TempDir()
+	if err := copyDir(s.PluginDir, filepath.Join(base, "automation", pluginID)); err != nil {
+		t.Fatalf("stage plugin: %v", err)
+	}
+	config := "{}"
+	if s.Config != nil {
+		config = s.Config(upstream.URL())
+	}
+
+	rt := plugins.NewRuntime(base)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	// Start also checks manifest.json against the gRPC manifest.
+	manifest, err := rt.Start(ctx, "automation", pluginID, instanceID, config)
+	if err != nil {
+		t.Fatalf("start plugin through runtime: %v", err)
+	}
+	t.Cleanup(func() { rt.Stop("automation", pluginID, instanceID) })
+	cli, ok := rt.AutomationClient(pluginID, instanceID)
+	if !ok || manifest.GetAutomation() == nil {
+		t.Fatalf("plugin %s does not serve the automation service", pluginID)
+	}
+
+	r := &runner{suite: s, cli: cli, upstream: upstream}
+	capability := manifest.GetAutomation()
+	r.check(t, "manifest/features", func(t *testing.T) { checkManifest(t, capability, s.RequireReasons) })
+
+	declared := map[pluginv1.AutomationFeature]bool{}
+	for _, f := range capability.GetFeatures() {
+		declared[f] = true
+	}
+	for _, c := range calls {
+		if !declared[c.feature] {
+			continue
+		}
+		if c.readOnly {
+			r.check(t, "supported/"+c.name, r.expectSuccess(c, 1))
+		}
+		if c.idempotent {
+			r.check(t, "idempotent/"+c.name, r.expectSuccess(c, 2))
+		}
+		if c.readOnly && c.instance {
+			r.check(t, "invalid_argument/"+c.name, r.expectInvalidArgument(c))
+		}
+	}
+	for _, f := range knownFeatures() {
+		if declared[f] {
+			continue
+		}
+		if c, ok := probeFor(f); ok {
+			r.check(t, "unsupported/"+featureName(f), r.expectUnimplemented(c))
+		}
+	}
+
+	upstream.FailWith(500)
+	for _, c := range calls {
+		if declared[c.feature] && c.readOnly {
+			r.check(t, "upstream_error/"+c.name, r.expectUpstreamError(c))
+		}
+	}
+	upstream.FailWith(0)
+}
+
+type runner struct {
+	suite    Suite
+	cli      pluginv1.AutomationServiceClient
+	upstream *Upstream
+}
+
+func (r *runner) check(t *testing.T, name string, fn func(t *testing.T)) {
+	t.Helper()
+	t.Run(name, func(t *testing.T) {
+		if slices.Contains(r.suite.Skip, name) {
+			t.Skip("waived by suite")
+		}
+		fn(t)
+	})
+}
+
+func (r *runner) invoke(c call, id int64) (proto.Message, int, error) {
+	timeout := r.suite.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	before := len(r.upstream.Requests())
+	resp, err := c.invoke(ctx, r.cli, r.suite.Fixture, id)
+	return resp, len(r.upstream.Requests()) - before, err
+}
+
+func (r *runner) expectSuccess(c call, times int) func(t *testing.T) {
+	return func(t *testing.T) {
+		for i := 1; i <= times; i++ {
+			resp, _, err := r.invoke(c, r.suite.Fixture.InstanceID)
+			if err != nil {
+				t.Fatalf("call %d: %s returned %v", i, c.name, err)
+			}
+			if op, ok := resp.(*pluginv1.OperationResult); ok && !op.GetOk() {
+				t.Fatalf("call %d: %s not ok: %s %s", i, c.name, op.GetErrorCode(), op.GetErrorMessage())
+			}
+		}
+	}
+}
+
+func (r *runner) expectInvalidArgument(c call) func(t *testing.T) {
+	return func(t *testing.T) {
+		_, upstreamCalls, err := r.invoke(c, 0)
+		if code := status.Code(err); code != codes.InvalidArgument {
+			t.Fatalf("%s without instance_id: want InvalidArgument, got %s (%v)", c.name, code, err)
+		}
+		if upstreamCalls > 0 {
+			t.Fatalf("%s without instance_id called the upstream %d times", c.name, upstreamCalls)
+		}
+	}
+}
+
+func (r *runner) expectUnimplemented(c call) func(t *testing.T) {
+	return func(t *testing.T) {
+		_, upstreamCalls, err := r.invoke(c, r.suite.Fixture.InstanceID)
+		if code := status.Code(err); code != codes.Unimplemented {
+			t.Fatalf("%s for undeclared %s: want Unimplemented, got %s (%v)", c.name, featureName(c.feature), code, err)
+		}
+		if upstreamCalls > 0 {
+			t.Fatalf("%s for undeclared %s called the upstream %d times", c.name, featureName(c.feature), upstreamCalls)
+		}
+	}
+}
+
+func (r *runner) expectUpstreamError(c call) func(t *testing.T) {
+	return func(t *testing.T) {
+		resp, _, err := r.invoke(c, r.suite.Fixture.InstanceID)
+		if err == nil {
+			if op, ok := resp.(*pluginv1.OperationResult); ok && !op.GetOk() && op.GetErrorMessage() != "" {
+				return
+			}
+			t.Fatalf("%s succeeded although the upstream answered 500", c.name)
+		}
+		switch code := status.Code(err); code {
+		case codes.Unimplemented, codes.InvalidArgument:
+			t.Fatalf("%s misreports an upstream failure as %s (%v)", c.name, code, err)
+		}
+	}
+}
+
+func checkManifest(t *testing.T, capability *pluginv1.AutomationCapability, requireReasons bool) {
+	declared := map[pluginv1.AutomationFeature]bool{}
+	for _, f := range capability.GetFeatures() {
+		if _, known := pluginv1.AutomationFeature_name[int32(f)]; !known || f == pluginv1.AutomationFeature_AUTOMATION_FEATURE_UNSPECIFIED {
+			t.Errorf("unknown feature %d declared", f)
+		}
+		if declared[f] {
+			t.Errorf("feature %s declared twice", featureName(f))
+		}
+		declared[f] = true
+	}
+	reasons := capability.GetNotSupportedReasons()
+	for key, reason := range reasons {
+		f := pluginv1.AutomationFeature(key)
+		if _, known := pluginv1.AutomationFeature_name[key]; !known || f == pluginv1.AutomationFeature_AUTOMATION_FEATURE_UNSPECIFIED {
+			t.Errorf("not_supported_reasons names unknown feature %d", key)
+			continue
+		}
+		if declared[f] {
+			t.Errorf("not_supported_reasons names declared feature %s", featureName(f))
+		}
+		if strings.TrimSpace(reason) == "" {
+			t.Errorf("not_supported_reasons entry for %s is empty", featureName(f))
+		}
+	}
+	if !requireReasons {
+		return
+	}
+	for _, f := range knownFeatures() {
+		if !declared[f] && strings.TrimSpace(reasons[int32(f)]) == "" {
+			t.Errorf("undeclared feature %s has no not_supported_reasons entry", featureName(f))
+		}
+	}
+}
+
+func knownFeatures() []pluginv1.AutomationFeature {
+	out := make([]pluginv1.AutomationFeature, 0, len(pluginv1.AutomationFeature_name))
+	for v := range pluginv1.AutomationFeature_name {
+		if v != int32(pluginv1.AutomationFeature_AUTOMATION_FEATURE_UNSPECIFIED) {
+			out = append(out, pluginv1.AutomationFeature(v))
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+func featureName(f pluginv1.AutomationFeature) string {
+	return strings.ToLower(strings.TrimPrefix(f.String(), "AUTOMATION_FEATURE_"))
+}
+
+func copyDir(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		return copyFile(path, target, info.Mode())
+	})
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
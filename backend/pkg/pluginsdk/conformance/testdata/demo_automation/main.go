@@ -0,0 +1,224 @@
+// Command demo_automation is a minimal automation plugin the conformance
+// kit runs against in its own tests. It proxies a tiny JSON API:
+//
+//	GET  /areas /lines /packages /images /instances
+//	GET  /instances/{id} /instances/{id}/vnc /instances/{id}/monitor /instances/{id}/snapshots
+//	POST /instances/{id}/{start|shutdown|lock|unlock}
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"xiaoheiplay/pkg/pluginsdk"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+type coreServer struct {
+	pluginv1.UnimplementedCoreServiceServer
+
+	baseURL string
+}
+
+func (s *coreServer) GetManifest(context.Context, *pluginv1.Empty) (*pluginv1.Manifest, error) {
+	return &pluginv1.Manifest{
+		PluginId:    "conformance_demo",
+		Name:        "Conformance Demo",
+		Version:     "0.1.0",
+		Description: "Minimal automation plugin used to test the conformance kit.",
+		Automation: &pluginv1.AutomationCapability{
+			Features: []pluginv1.AutomationFeature{
+				pluginv1.AutomationFeature_AUTOMATION_FEATURE_CATALOG_SYNC,
+				pluginv1.AutomationFeature_AUTOMATION_FEATURE_LIFECYCLE,
+				pluginv1.AutomationFeature_AUTOMATION_FEATURE_SNAPSHOT,
+			},
+			NotSupportedReasons: map[int32]string{
+				int32(pluginv1.AutomationFeature_AUTOMATION_FEATURE_BACKUP): "upstream has no backup API",
+			},
+		},
+	}, nil
+}
+
+func (s *coreServer) Init(_ context.Context, req *pluginv1.InitRequest) (*pluginv1.InitResponse, error) {
+	var cfg struct {
+		BaseURL string `json:"base_url"`
+	}
+	if err := json.Unmarshal([]byte(req.GetConfigJson()), &cfg); err != nil || cfg.BaseURL == "" {
+		return &pluginv1.InitResponse{Ok: false, Error: "base_url required"}, nil
+	}
+	s.baseURL = strings.TrimRight(cfg.BaseURL, "/")
+	return &pluginv1.InitResponse{Ok: true}, nil
+}
+
+func (s *coreServer) Health(context.Context, *pluginv1.HealthCheckRequest) (*pluginv1.HealthCheckResponse, error) {
+	return &pluginv1.HealthCheckResponse{Status: pluginv1.HealthStatus_HEALTH_STATUS_OK, UnixMillis: time.Now().UnixMilli()}, nil
+}
+
+type automationServer struct {
+	pluginv1.UnimplementedAutomationServiceServer
+	core *coreServer
+}
+
+func (a *automationServer) do(ctx context.Context, method, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, method, a.core.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("upstream %s %s: http %d", method, path, resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func requireInstance(id int64) error {
+	if id <= 0 {
+		return status.Error(codes.InvalidArgument, "instance_id required")
+	}
+	return nil
+}
+
+func (a *automationServer) ListAreas(ctx context.Context, _ *pluginv1.Empty) (*pluginv1.ListAreasResponse, error) {
+	var items []*pluginv1.AutomationArea
+	if err := a.do(ctx, http.MethodGet, "/areas", &items); err != nil {
+		return nil, err
+	}
+	return &pluginv1.ListAreasResponse{Items: items}, nil
+}
+
+func (a *automationServer) ListLines(ctx context.Context, _ *pluginv1.Empty) (*pluginv1.ListLinesResponse, error) {
+	var items []*pluginv1.AutomationLine
+	if err := a.do(ctx, http.MethodGet, "/lines", &items); err != nil {
+		return nil, err
+	}
+	return &pluginv1.ListLinesResponse{Items: items}, nil
+}
+
+func (a *automationServer) ListPackages(ctx context.Context, req *pluginv1.ListPackagesRequest) (*pluginv1.ListPackagesResponse, error) {
+	var items []*pluginv1.AutomationPackage
+	if err := a.do(ctx, http.MethodGet, fmt.Sprintf("/packages?line_id=%d", req.GetLineId()), &items); err != nil {
+		return nil, err
+	}
+	return &pluginv1.ListPackagesResponse{Items: items}, nil
+}
+
+func (a *automationServer) ListImages(ctx context.Context, req *pluginv1.ListImagesRequest) (*pluginv1.ListImagesResponse, error) {
+	var items []*pluginv1.AutomationImage
+	if err := a.do(ctx, http.MethodGet, fmt.Sprintf("/images?line_id=%d", req.GetLineId()), &items); err != nil {
+		return nil, err
+	}
+	return &pluginv1.ListImagesResponse{Items: items}, nil
+}
+
+func (a *automationServer) GetInstance(ctx context.Context, req *pluginv1.GetInstanceRequest) (*pluginv1.GetInstanceResponse, error) {
+	if err := requireInstance(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
+	var inst pluginv1.AutomationInstance
+	if err := a.do(ctx, http.MethodGet, fmt.Sprintf("/instances/%d", req.GetInstanceId()), &inst); err != nil {
+		return nil, err
+	}
+	return &pluginv1.GetInstanceResponse{Instance: &inst}, nil
+}
+
+func (a *automationServer) ListInstancesSimple(ctx context.Context, req *pluginv1.ListInstancesSimpleRequest) (*pluginv1.ListInstancesSimpleResponse, error) {
+	var items []*pluginv1.AutomationInstanceSimple
+	if err := a.do(ctx, http.MethodGet, "/instances?tag="+url.QueryEscape(req.GetSearchTag()), &items); err != nil {
+		return nil, err
+	}
+	return &pluginv1.ListInstancesSimpleResponse{Items: items}, nil
+}
+
+func (a *automationServer) GetPanelURL(ctx context.Context, req *pluginv1.GetPanelURLRequest) (*pluginv1.GetPanelURLResponse, error) {
+	var out struct {
+		URL string `json:"url"`
+	}
+	if err := a.do(ctx, http.MethodGet, "/panel?name="+url.QueryEscape(req.GetInstanceName()), &out); err != nil {
+		return nil, err
+	}
+	return &pluginv1.GetPanelURLResponse{Url: out.URL}, nil
+}
+
+func (a *automationServer) GetVNCURL(ctx context.Context, req *pluginv1.GetVNCURLRequest) (*pluginv1.GetVNCURLResponse, error) {
+	if err := requireInstance(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
+	var out struct {
+		URL string `json:"url"`
+	}
+	if err := a.do(ctx, http.MethodGet, fmt.Sprintf("/instances/%d/vnc", req.GetInstanceId()), &out); err != nil {
+		return nil, err
+	}
+	return &pluginv1.GetVNCURLResponse{Url: out.URL}, nil
+}
+
+func (a *automationServer) GetMonitor(ctx context.Context, req *pluginv1.GetMonitorRequest) (*pluginv1.GetMonitorResponse, error) {
+	if err := requireInstance(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
+	var raw json.RawMessage
+	if err := a.do(ctx, http.MethodGet, fmt.Sprintf("/instances/%d/monitor", req.GetInstanceId()), &raw); err != nil {
+		return nil, err
+	}
+	return &pluginv1.GetMonitorResponse{RawJson: string(raw)}, nil
+}
+
+func (a *automationServer) action(ctx context.Context, id int64, name string) (*pluginv1.OperationResult, error) {
+	if err := requireInstance(id); err != nil {
+		return nil, err
+	}
+	if err := a.do(ctx, http.MethodPost, fmt.Sprintf("/instances/%d/%s", id, name), nil); err != nil {
+		return &pluginv1.OperationResult{Ok: false, ErrorCode: "upstream", ErrorMessage: err.Error()}, nil
+	}
+	return &pluginv1.OperationResult{Ok: true}, nil
+}
+
+func (a *automationServer) Start(ctx context.Context, req *pluginv1.StartRequest) (*pluginv1.OperationResult, error) {
+	return a.action(ctx, req.GetInstanceId(), "start")
+}
+
+func (a *automationServer) Shutdown(ctx context.Context, req *pluginv1.ShutdownRequest) (*pluginv1.OperationResult, error) {
+	return a.action(ctx, req.GetInstanceId(), "shutdown")
+}
+
+func (a *automationServer) Lock(ctx context.Context, req *pluginv1.LockRequest) (*pluginv1.OperationResult, error) {
+	return a.action(ctx, req.GetInstanceId(), "lock")
+}
+
+func (a *automationServer) Unlock(ctx context.Context, req *pluginv1.UnlockRequest) (*pluginv1.OperationResult, error) {
+	return a.action(ctx, req.GetInstanceId(), "unlock")
+}
+
+func (a *automationServer) ListSnapshots(ctx context.Context, req *pluginv1.ListSnapshotsRequest) (*pluginv1.ListSnapshotsResponse, error) {
+	if err := requireInstance(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
+	var items []*pluginv1.AutomationSnapshot
+	if err := a.do(ctx, http.MethodGet, fmt.Sprintf("/instances/%d/snapshots", req.GetInstanceId()), &items); err != nil {
+		return nil, err
+	}
+	return &pluginv1.ListSnapshotsResponse{Items: items}, nil
+}
+
+func main() {
+	core := &coreServer{}
+	pluginsdk.Serve(map[string]pluginsdk.Plugin{
+		pluginsdk.PluginKeyCore:       &pluginsdk.CoreGRPCPlugin{Impl: core},
+		pluginsdk.PluginKeyAutomation: &pluginsdk.AutomationGRPCPlugin{Impl: &automationServer{core: core}},
+	})
+}
@@ -0,0 +1,112 @@
+package conformance
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// Route is one scripted upstream response. Path matches the request path
+// exactly, or as a prefix when it ends in "*". An empty Method matches any
+// method. Status defaults to 200.
+type Route struct {
+	Method string
+	Path   string
+	Status int
+	Body   string
+	Header map[string]string
+}
+
+// Request is one call the plugin made to the upstream.
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+	Body   string
+}
+
+// Upstream is a local stand-in for the provider API a plugin talks to.
+// Unmatched requests get 404 and are still recorded.
+type Upstream struct {
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	routes   []Route
+	requests []Request
+	failWith int
+}
+
+func NewUpstream(routes ...Route) *Upstream {
+	u := &Upstream{routes: routes}
+	u.srv = httptest.NewServer(http.HandlerFunc(u.serve))
+	return u
+}
+
+func (u *Upstream) URL() string { return u.srv.URL }
+
+func (u *Upstream) Close() { u.srv.Close() }
+
+// Requests returns the calls recorded so far.
+func (u *Upstream) Requests() []Request {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return append([]Request(nil), u.requests...)
+}
+
+// FailWith makes every request answer status with an empty JSON body until
+// it is called again with 0.
+func (u *Upstream) FailWith(status int) {
+	u.mu.Lock()
+	u.failWith = status
+	u.mu.Unlock()
+}
+
+func (u *Upstream) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	u.mu.Lock()
+	u.requests = append(u.requests, Request{Method: r.Method, Path: r.URL.Path, Query: r.URL.RawQuery, Body: string(body)})
+	failWith := u.failWith
+	route, ok := u.match(r.Method, r.URL.Path)
+	u.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if failWith > 0 {
+		w.WriteHeader(failWith)
+		_, _ = io.WriteString(w, `{}`)
+		return
+	}
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `{"error":"no scripted route"}`)
+		return
+	}
+	for k, v := range route.Header {
+		w.Header().Set(k, v)
+	}
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	_, _ = io.WriteString(w, route.Body)
+}
+
+func (u *Upstream) match(method, path string) (Route, bool) {
+	for _, route := range u.routes {
+		if route.Method != "" && !strings.EqualFold(route.Method, method) {
+			continue
+		}
+		if prefix, ok := strings.CutSuffix(route.Path, "*"); ok {
+			if strings.HasPrefix(path, prefix) {
+				return route, true
+			}
+			continue
+		}
+		if route.Path == path {
+			return route, true
+		}
+	}
+	return Route{}, false
+}
@@ -12,15 +12,36 @@ import (
 type CoreGRPCPlugin struct {
 	plugin.NetRPCUnsupportedPlugin
 	Impl pluginv1.CoreServiceServer
+	// Host is set by the host process; when non-nil it is served to the
+	// plugin over the broker and the dispensed client is a *CoreClient.
+	Host pluginv1.HostServiceServer
 }
 
-func (p *CoreGRPCPlugin) GRPCServer(_ *plugin.GRPCBroker, s *grpc.Server) error {
+// CoreClient is the core client dispensed by a host that serves HostService.
+// HostBrokerID is passed to the plugin in InitRequest.host_broker_id.
+type CoreClient struct {
+	pluginv1.CoreServiceClient
+	HostBrokerID uint32
+}
+
+func (p *CoreGRPCPlugin) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
+	setHostBroker(broker)
 	pluginv1.RegisterCoreServiceServer(s, p.Impl)
 	return nil
 }
 
-func (p *CoreGRPCPlugin) GRPCClient(_ context.Context, _ *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
-	return pluginv1.NewCoreServiceClient(c), nil
+func (p *CoreGRPCPlugin) GRPCClient(_ context.Context, broker *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	core := pluginv1.NewCoreServiceClient(c)
+	if p.Host == nil || broker == nil {
+		return core, nil
+	}
+	id := broker.NextId()
+	go broker.AcceptAndServe(id, func(opts []grpc.ServerOption) *grpc.Server {
+		s := grpc.NewServer(opts...)
+		pluginv1.RegisterHostServiceServer(s, p.Host)
+		return s
+	})
+	return &CoreClient{CoreServiceClient: core, HostBrokerID: id}, nil
 }
 
 type SmsGRPCPlugin struct {
@@ -78,3 +99,59 @@ func (p *AutomationGRPCPlugin) GRPCServer(_ *plugin.GRPCBroker, s *grpc.Server)
 func (p *AutomationGRPCPlugin) GRPCClient(_ context.Context, _ *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
 	return pluginv1.NewAutomationServiceClient(c), nil
 }
+
+type HookGRPCPlugin struct {
+	plugin.NetRPCUnsupportedPlugin
+	Impl pluginv1.HookServiceServer
+}
+
+func (p *HookGRPCPlugin) GRPCServer(_ *plugin.GRPCBroker, s *grpc.Server) error {
+	pluginv1.RegisterHookServiceServer(s, p.Impl)
+	return nil
+}
+
+func (p *HookGRPCPlugin) GRPCClient(_ context.Context, _ *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return pluginv1.NewHookServiceClient(c), nil
+}
+
+type NotifyGRPCPlugin struct {
+	plugin.NetRPCUnsupportedPlugin
+	Impl pluginv1.NotifyServiceServer
+}
+
+func (p *NotifyGRPCPlugin) GRPCServer(_ *plugin.GRPCBroker, s *grpc.Server) error {
+	pluginv1.RegisterNotifyServiceServer(s, p.Impl)
+	return nil
+}
+
+func (p *NotifyGRPCPlugin) GRPCClient(_ context.Context, _ *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return pluginv1.NewNotifyServiceClient(c), nil
+}
+
+type StorageGRPCPlugin struct {
+	plugin.NetRPCUnsupportedPlugin
+	Impl pluginv1.StorageServiceServer
+}
+
+func (p *StorageGRPCPlugin) GRPCServer(_ *plugin.GRPCBroker, s *grpc.Server) error {
+	pluginv1.RegisterStorageServiceServer(s, p.Impl)
+	return nil
+}
+
+func (p *StorageGRPCPlugin) GRPCClient(_ context.Context, _ *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return pluginv1.NewStorageServiceClient(c), nil
+}
+
+type CaptchaGRPCPlugin struct {
+	plugin.NetRPCUnsupportedPlugin
+	Impl pluginv1.CaptchaServiceServer
+}
+
+func (p *CaptchaGRPCPlugin) GRPCServer(_ *plugin.GRPCBroker, s *grpc.Server) error {
+	pluginv1.RegisterCaptchaServiceServer(s, p.Impl)
+	return nil
+}
+
+func (p *CaptchaGRPCPlugin) GRPCClient(_ context.Context, _ *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return pluginv1.NewCaptchaServiceClient(c), nil
+}
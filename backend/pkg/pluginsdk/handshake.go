@@ -14,6 +14,10 @@ const (
 	PluginKeyPayment    = "payment"
 	PluginKeyKYC        = "kyc"
 	PluginKeyAutomation = "automation"
+	PluginKeyHook       = "hook"
+	PluginKeyNotify     = "notify"
+	PluginKeyStorage    = "storage"
+	PluginKeyCaptcha    = "captcha"
 )
 
 var Handshake = plugin.HandshakeConfig{
@@ -0,0 +1,43 @@
+package pluginsdk
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/hashicorp/go-plugin"
+
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+var (
+	hostMu     sync.Mutex
+	hostBroker *plugin.GRPCBroker
+)
+
+func setHostBroker(broker *plugin.GRPCBroker) {
+	hostMu.Lock()
+	defer hostMu.Unlock()
+	if broker != nil {
+		hostBroker = broker
+	}
+}
+
+// DialHost connects to the HostService the host serves for this plugin
+// instance. Call it from Init with req.GetHostBrokerId() and keep the client:
+// ReloadConfig does not carry the broker id again.
+func DialHost(brokerID uint32) (pluginv1.HostServiceClient, error) {
+	if brokerID == 0 {
+		return nil, errors.New("host service not offered")
+	}
+	hostMu.Lock()
+	broker := hostBroker
+	hostMu.Unlock()
+	if broker == nil {
+		return nil, errors.New("plugin broker not ready")
+	}
+	conn, err := broker.Dial(brokerID)
+	if err != nil {
+		return nil, err
+	}
+	return pluginv1.NewHostServiceClient(conn), nil
+}
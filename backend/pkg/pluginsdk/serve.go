@@ -1,13 +1,24 @@
 package pluginsdk
 
-import "github.com/hashicorp/go-plugin"
+import (
+	"github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+)
 
 type Plugin = plugin.Plugin
 
+// MaxMessageSize bounds one gRPC message between host and plugin. It is
+// above the 4 MiB gRPC default so storage plugins can move an upload in a
+// single call.
+const MaxMessageSize = 64 << 20
+
 func Serve(plugins map[string]Plugin) {
 	plugin.Serve(&plugin.ServeConfig{
 		HandshakeConfig: Handshake,
 		Plugins:         plugins,
-		GRPCServer:      plugin.DefaultGRPCServer,
+		GRPCServer: func(opts []grpc.ServerOption) *grpc.Server {
+			opts = append(opts, grpc.MaxRecvMsgSize(MaxMessageSize), grpc.MaxSendMsgSize(MaxMessageSize))
+			return plugin.DefaultGRPCServer(opts)
+		},
 	})
 }
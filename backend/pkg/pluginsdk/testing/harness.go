@@ -0,0 +1,306 @@
+// Package plugintest runs a plugin's gRPC services in process, so plugin
+// authors can test them against the host contract without building a
+// binary. New registers the same plugin map main passes to pluginsdk.Serve
+// on a gRPC server behind an in-memory listener; the harness then drives it
+// the way the host does:
+//
+//   - CheckManifest compares manifest.json with GetManifest using the check
+//     the runtime runs before it dispenses a plugin, and makes sure every
+//     declared capability is served;
+//   - ValidateConfig mirrors a config save: required schema fields first,
+//     then the plugin's ValidateConfig;
+//   - Init and ReloadConfig send what the runtime and manager send and turn
+//     ok=false into an error.
+//
+// A typical test:
+//
+//	func TestPlugin(t *testing.T) {
+//		h := plugintest.New(t, newPlugins())
+//		h.Start("../../../plugins/notify/mock_notify", `{"default_target":"ops"}`)
+//		resp, err := h.Notify().Send(context.Background(), &pluginv1.SendNotifyRequest{Title: "hi"})
+//		...
+//	}
+//
+// The harness does not offer a HostService: InitRequest.host_broker_id is 0,
+// so pluginsdk.DialHost fails and plugins must run without the host.
+package plugintest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	plugins "xiaoheiplay/internal/adapter/plugins/core"
+	"xiaoheiplay/pkg/pluginsdk"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+// DefaultInstanceID is sent in InitRequest unless Harness.InstanceID is set.
+const DefaultInstanceID = "test"
+
+const bufSize = 1 << 20
+
+type Harness struct {
+	// Core talks to the plugin's CoreService.
+	Core pluginv1.CoreServiceClient
+	// InstanceID is sent in InitRequest.
+	InstanceID string
+	// Timeout bounds each host-side RPC. It defaults to 10 seconds.
+	Timeout time.Duration
+
+	t       testing.TB
+	conn    *grpc.ClientConn
+	served  map[string]bool
+	started bool
+}
+
+// New serves plugins, keyed like pluginsdk.Serve's argument, over an
+// in-memory connection that is closed when the test ends.
+func New(t testing.TB, plugins map[string]pluginsdk.Plugin) *Harness {
+	t.Helper()
+	if _, ok := plugins[pluginsdk.PluginKeyCore]; !ok {
+		t.Fatalf("plugin map has no %q entry", pluginsdk.PluginKeyCore)
+	}
+	srv := grpc.NewServer(grpc.MaxRecvMsgSize(pluginsdk.MaxMessageSize), grpc.MaxSendMsgSize(pluginsdk.MaxMessageSize))
+	served := map[string]bool{}
+	for key, p := range plugins {
+		gp, ok := p.(plugin.GRPCPlugin)
+		if !ok {
+			t.Fatalf("plugin %q does not implement plugin.GRPCPlugin", key)
+		}
+		if err := gp.GRPCServer(nil, srv); err != nil {
+			t.Fatalf("register plugin %q: %v", key, err)
+		}
+		served[key] = true
+	}
+	lis := bufconn.Listen(bufSize)
+	go func() { _ = srv.Serve(lis) }()
+	conn, err := grpc.NewClient("passthrough:///plugin",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(pluginsdk.MaxMessageSize), grpc.MaxCallSendMsgSize(pluginsdk.MaxMessageSize)),
+	)
+	if err != nil {
+		srv.Stop()
+		t.Fatalf("dial in-memory plugin: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+		srv.Stop()
+	})
+	return &Harness{
+		Core:       pluginv1.NewCoreServiceClient(conn),
+		InstanceID: DefaultInstanceID,
+		t:          t,
+		conn:       conn,
+		served:     served,
+	}
+}
+
+// Start checks the manifest in manifestDir and initialises the plugin with
+// configJSON, failing the test on either error. It returns the gRPC manifest.
+func (h *Harness) Start(manifestDir, configJSON string) *pluginv1.Manifest {
+	h.t.Helper()
+	if err := h.CheckManifest(manifestDir); err != nil {
+		h.t.Fatalf("check manifest: %v", err)
+	}
+	if err := h.Init(configJSON); err != nil {
+		h.t.Fatalf("init: %v", err)
+	}
+	manifest, err := h.manifest()
+	if err != nil {
+		h.t.Fatalf("get manifest: %v", err)
+	}
+	return manifest
+}
+
+// CheckManifest reads manifestDir/manifest.json and compares it with the
+// plugin's GetManifest. It also fails when a declared capability, or a hook
+// subscription, has no service in the plugin map.
+func (h *Harness) CheckManifest(manifestDir string) error {
+	jsonM, err := plugins.ReadManifest(manifestDir)
+	if err != nil {
+		return err
+	}
+	grpcM, err := h.manifest()
+	if err != nil {
+		return err
+	}
+	if grpcM.GetPluginId() == "" {
+		return fmt.Errorf("invalid manifest")
+	}
+	if err := plugins.ValidateManifestConsistency(jsonM, grpcM); err != nil {
+		return err
+	}
+	declared := map[string]bool{
+		pluginsdk.PluginKeySMS:        grpcM.Sms != nil,
+		pluginsdk.PluginKeyPayment:    grpcM.Payment != nil,
+		pluginsdk.PluginKeyKYC:        grpcM.Kyc != nil,
+		pluginsdk.PluginKeyAutomation: grpcM.Automation != nil,
+		pluginsdk.PluginKeyNotify:     grpcM.Notify != nil,
+		pluginsdk.PluginKeyStorage:    grpcM.Storage != nil,
+		pluginsdk.PluginKeyCaptcha:    grpcM.Captcha != nil,
+		pluginsdk.PluginKeyHook:       len(jsonM.Hooks) > 0,
+	}
+	for key, want := range declared {
+		if want && !h.served[key] {
+			return fmt.Errorf("manifest declares %s but the plugin map does not serve it", key)
+		}
+	}
+	return nil
+}
+
+// ValidateConfig runs the checks a config save runs before it is stored:
+// required fields from GetConfigSchema, then the plugin's ValidateConfig.
+func (h *Harness) ValidateConfig(configJSON string) error {
+	h.t.Helper()
+	ctx, cancel := h.context()
+	defer cancel()
+	if schema, err := h.Core.GetConfigSchema(ctx, &pluginv1.Empty{}); err == nil {
+		if missing := plugins.MissingRequiredConfigFields(schema.GetJsonSchema(), configJSON); len(missing) > 0 {
+			return fmt.Errorf("missing required config: %s", strings.Join(missing, ", "))
+		}
+	}
+	resp, err := h.Core.ValidateConfig(ctx, &pluginv1.ValidateConfigRequest{ConfigJson: configJSON})
+	if err != nil {
+		return err
+	}
+	if !resp.GetOk() {
+		return failure(resp.GetError(), "invalid config")
+	}
+	return nil
+}
+
+// Init sends InitRequest as the runtime does when it starts an instance.
+func (h *Harness) Init(configJSON string) error {
+	h.t.Helper()
+	ctx, cancel := h.context()
+	defer cancel()
+	resp, err := h.Core.Init(ctx, &pluginv1.InitRequest{InstanceId: h.InstanceID, ConfigJson: configJSON})
+	if err != nil {
+		return err
+	}
+	if !resp.GetOk() {
+		return failure(resp.GetError(), "plugin init failed")
+	}
+	h.started = true
+	return nil
+}
+
+// ReloadConfig sends ReloadConfigRequest as the manager does after saving a
+// running instance's config. The host only reloads started plugins, so it
+// fails the test when Init has not succeeded.
+func (h *Harness) ReloadConfig(configJSON string) error {
+	h.t.Helper()
+	if !h.started {
+		h.t.Fatalf("ReloadConfig before a successful Init")
+	}
+	ctx, cancel := h.context()
+	defer cancel()
+	resp, err := h.Core.ReloadConfig(ctx, &pluginv1.ReloadConfigRequest{ConfigJson: configJSON})
+	if err != nil {
+		return err
+	}
+	if !resp.GetOk() {
+		return failure(resp.GetError(), "plugin reload failed")
+	}
+	return nil
+}
+
+// Health calls Health and fails the test unless the plugin reports OK.
+func (h *Harness) Health() {
+	h.t.Helper()
+	ctx, cancel := h.context()
+	defer cancel()
+	resp, err := h.Core.Health(ctx, &pluginv1.HealthCheckRequest{InstanceId: h.InstanceID})
+	if err != nil {
+		h.t.Fatalf("health: %v", err)
+	}
+	if resp.GetStatus() != pluginv1.HealthStatus_HEALTH_STATUS_OK {
+		h.t.Fatalf("health: %s %s", resp.GetStatus(), resp.GetMessage())
+	}
+}
+
+func (h *Harness) SMS() pluginv1.SmsServiceClient {
+	h.t.Helper()
+	h.require(pluginsdk.PluginKeySMS)
+	return pluginv1.NewSmsServiceClient(h.conn)
+}
+
+func (h *Harness) Payment() pluginv1.PaymentServiceClient {
+	h.t.Helper()
+	h.require(pluginsdk.PluginKeyPayment)
+	return pluginv1.NewPaymentServiceClient(h.conn)
+}
+
+func (h *Harness) KYC() pluginv1.KycServiceClient {
+	h.t.Helper()
+	h.require(pluginsdk.PluginKeyKYC)
+	return pluginv1.NewKycServiceClient(h.conn)
+}
+
+func (h *Harness) Automation() pluginv1.AutomationServiceClient {
+	h.t.Helper()
+	h.require(pluginsdk.PluginKeyAutomation)
+	return pluginv1.NewAutomationServiceClient(h.conn)
+}
+
+func (h *Harness) Hook() pluginv1.HookServiceClient {
+	h.t.Helper()
+	h.require(pluginsdk.PluginKeyHook)
+	return pluginv1.NewHookServiceClient(h.conn)
+}
+
+func (h *Harness) Notify() pluginv1.NotifyServiceClient {
+	h.t.Helper()
+	h.require(pluginsdk.PluginKeyNotify)
+	return pluginv1.NewNotifyServiceClient(h.conn)
+}
+
+func (h *Harness) Storage() pluginv1.StorageServiceClient {
+	h.t.Helper()
+	h.require(pluginsdk.PluginKeyStorage)
+	return pluginv1.NewStorageServiceClient(h.conn)
+}
+
+func (h *Harness) Captcha() pluginv1.CaptchaServiceClient {
+	h.t.Helper()
+	h.require(pluginsdk.PluginKeyCaptcha)
+	return pluginv1.NewCaptchaServiceClient(h.conn)
+}
+
+func (h *Harness) require(key string) {
+	h.t.Helper()
+	if !h.served[key] {
+		h.t.Fatalf("plugin map does not serve %q", key)
+	}
+}
+
+func (h *Harness) manifest() (*pluginv1.Manifest, error) {
+	ctx, cancel := h.context()
+	defer cancel()
+	return h.Core.GetManifest(ctx, &pluginv1.Empty{})
+}
+
+func (h *Harness) context() (context.Context, context.CancelFunc) {
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+func failure(msg, fallback string) error {
+	if msg = strings.TrimSpace(msg); msg != "" {
+		return fmt.Errorf("%s", msg)
+	}
+	return fmt.Errorf("%s", fallback)
+}
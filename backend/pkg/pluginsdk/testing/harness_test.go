@@ -0,0 +1,132 @@
+package plugintest_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"xiaoheiplay/pkg/pluginsdk"
+	plugintest "xiaoheiplay/pkg/pluginsdk/testing"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+type echoCore struct {
+	pluginv1.UnimplementedCoreServiceServer
+	instance string
+	prefix   string
+}
+
+func (s *echoCore) GetManifest(context.Context, *pluginv1.Empty) (*pluginv1.Manifest, error) {
+	return &pluginv1.Manifest{PluginId: "echo", Name: "Echo", Version: "1.0.0", Notify: &pluginv1.NotifyCapability{Send: true}}, nil
+}
+
+func (s *echoCore) GetConfigSchema(context.Context, *pluginv1.Empty) (*pluginv1.ConfigSchema, error) {
+	return &pluginv1.ConfigSchema{JsonSchema: `{"type":"object","required":["prefix"],"properties":{"prefix":{"type":"string"}}}`}, nil
+}
+
+func (s *echoCore) ValidateConfig(_ context.Context, req *pluginv1.ValidateConfigRequest) (*pluginv1.ValidateConfigResponse, error) {
+	if strings.Contains(req.GetConfigJson(), "bad") {
+		return &pluginv1.ValidateConfigResponse{Ok: false, Error: "prefix rejected"}, nil
+	}
+	return &pluginv1.ValidateConfigResponse{Ok: true}, nil
+}
+
+func (s *echoCore) Init(_ context.Context, req *pluginv1.InitRequest) (*pluginv1.InitResponse, error) {
+	s.instance = req.GetInstanceId()
+	return &pluginv1.InitResponse{Ok: s.apply(req.GetConfigJson())}, nil
+}
+
+func (s *echoCore) ReloadConfig(_ context.Context, req *pluginv1.ReloadConfigRequest) (*pluginv1.ReloadConfigResponse, error) {
+	if !s.apply(req.GetConfigJson()) {
+		return &pluginv1.ReloadConfigResponse{Ok: false}, nil
+	}
+	return &pluginv1.ReloadConfigResponse{Ok: true}, nil
+}
+
+func (s *echoCore) apply(raw string) bool {
+	var cfg struct {
+		Prefix string `json:"prefix"`
+	}
+	if err := json.Unmarshal([]byte(raw), &cfg); err != nil {
+		return false
+	}
+	s.prefix = cfg.Prefix
+	return true
+}
+
+type echoNotify struct {
+	pluginv1.UnimplementedNotifyServiceServer
+	core *echoCore
+}
+
+func (s *echoNotify) Send(_ context.Context, req *pluginv1.SendNotifyRequest) (*pluginv1.SendNotifyResponse, error) {
+	return &pluginv1.SendNotifyResponse{Ok: true, MessageId: s.core.instance + ":" + s.core.prefix + req.GetTitle()}, nil
+}
+
+func echoPlugins(withNotify bool) map[string]pluginsdk.Plugin {
+	core := &echoCore{}
+	out := map[string]pluginsdk.Plugin{pluginsdk.PluginKeyCore: &pluginsdk.CoreGRPCPlugin{Impl: core}}
+	if withNotify {
+		out[pluginsdk.PluginKeyNotify] = &pluginsdk.NotifyGRPCPlugin{Impl: &echoNotify{core: core}}
+	}
+	return out
+}
+
+func writeManifest(t *testing.T, manifest string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json"), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+const echoManifest = `{"plugin_id":"echo","name":"Echo","version":"1.0.0","capabilities":{"notify":{"send":true}}}`
+
+func TestHarness_ConfigFlow(t *testing.T) {
+	h := plugintest.New(t, echoPlugins(true))
+	if err := h.ValidateConfig(`{}`); err == nil || !strings.Contains(err.Error(), "missing required config: prefix") {
+		t.Fatalf("expected missing required field, got %v", err)
+	}
+	if err := h.ValidateConfig(`{"prefix":"bad"}`); err == nil || err.Error() != "prefix rejected" {
+		t.Fatalf("expected plugin validation error, got %v", err)
+	}
+	if err := h.ValidateConfig(`{"prefix":"a:"}`); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+
+	h.Start(writeManifest(t, echoManifest), `{"prefix":"a:"}`)
+	resp, err := h.Notify().Send(context.Background(), &pluginv1.SendNotifyRequest{Title: "hi"})
+	if err != nil || resp.GetMessageId() != plugintest.DefaultInstanceID+":a:hi" {
+		t.Fatalf("unexpected send result %v %v", resp, err)
+	}
+	if err := h.ReloadConfig(`{"prefix":"b:"}`); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	resp, _ = h.Notify().Send(context.Background(), &pluginv1.SendNotifyRequest{Title: "hi"})
+	if resp.GetMessageId() != plugintest.DefaultInstanceID+":b:hi" {
+		t.Fatalf("reload not applied: %v", resp)
+	}
+	if err := h.ReloadConfig(`not json`); err == nil || err.Error() != "plugin reload failed" {
+		t.Fatalf("expected reload failure, got %v", err)
+	}
+}
+
+func TestHarness_CheckManifest(t *testing.T) {
+	h := plugintest.New(t, echoPlugins(true))
+	if err := h.CheckManifest(writeManifest(t, echoManifest)); err != nil {
+		t.Fatalf("check manifest: %v", err)
+	}
+	mismatch := `{"plugin_id":"echo","name":"Echo","version":"1.0.1","capabilities":{"notify":{"send":true}}}`
+	if err := h.CheckManifest(writeManifest(t, mismatch)); err == nil || !strings.Contains(err.Error(), "version") {
+		t.Fatalf("expected version mismatch, got %v", err)
+	}
+
+	h = plugintest.New(t, echoPlugins(false))
+	if err := h.CheckManifest(writeManifest(t, echoManifest)); err == nil || !strings.Contains(err.Error(), "does not serve") {
+		t.Fatalf("expected unserved notify capability, got %v", err)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"xiaoheiplay/pkg/pluginsdk/conformance"
+)
+
+func TestConformance(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and launches the plugin binary")
+	}
+	ok := func(data string) string { return `{"code":1,"msg":"succ","data":` + data + `}` }
+	conformance.Run(t, conformance.Suite{
+		PluginDir: conformance.Build(t, ".", filepath.Join("..", "..", "..", "plugins", "automation", "lightboat", "manifest.json")),
+		Config: func(url string) string {
+			return `{"base_url":"` + url + `/index.php/api/cloud","api_key":"test","timeout_sec":5}`
+		},
+		Routes: []conformance.Route{
+			{Method: http.MethodGet, Path: "/index.php/api/cloud/line", Body: ok(`[{"id":10,"line_name":"HK","area_id":1,"state":1,"image_ids":[5]}]`)},
+			{Method: http.MethodGet, Path: "/index.php/api/cloud/product", Body: ok(`{"100":{"id":100,"product_name":"S1","host_cpu":1,"host_ram":1,"price":"9.90"}}`)},
+			{Method: http.MethodGet, Path: "/index.php/api/cloud/mirror_image", Body: ok(`[{"id":5,"name":"Debian 12","type":"linux"}]`)},
+			{Method: http.MethodGet, Path: "/index.php/api/cloud/hostlist", Body: ok(`[{"id":7,"host_name":"vm-7","ip":"203.0.113.7"}]`)},
+			{Method: http.MethodPost, Path: "/index.php/api/cloud/hostinfo", Body: ok(`{"id":7,"host_name":"vm-7","state":2,"cpu":1,"memory":1}`)},
+			{Method: http.MethodPost, Path: "/index.php/api/cloud/monitor", Body: ok(`{"CpuStats":3,"MemoryStats":40,"StorageStats":10}`)},
+			{Method: http.MethodPost, Path: "/index.php/api/cloud/panel", Status: http.StatusFound, Header: map[string]string{"Location": "/panel/login?token=t"}},
+			{Method: http.MethodPost, Path: "/index.php/api/cloud/vnc_view", Status: http.StatusFound, Header: map[string]string{"Location": "/vnc/7"}},
+			{Method: http.MethodPost, Path: "/index.php/api/cloud/snapshot_list", Body: ok(`[]`)},
+			{Method: http.MethodPost, Path: "/index.php/api/cloud/backups_list", Body: ok(`[]`)},
+			{Method: http.MethodGet, Path: "/index.php/api/cloud/security_acl_list", Body: ok(`[]`)},
+			{Method: http.MethodGet, Path: "/index.php/api/cloud/nat_acl_list", Body: ok(`[]`)},
+			{Method: http.MethodPost, Path: "/index.php/api/cloud/*", Body: ok(`{}`)},
+		},
+		Fixture: conformance.Fixture{InstanceID: 7, InstanceName: "vm-7", PanelPassword: "secret", LineID: 10},
+	})
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	plugintest "xiaoheiplay/pkg/pluginsdk/testing"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+func TestPlugin_ListImagesAndReload(t *testing.T) {
+	var gotKey string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get("apikey")
+		if r.URL.Path != "/api/cloud/mirror_image" {
+			t.Errorf("unexpected request path: %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"code":1,"msg":"succ","data":[{"id":101,"name":"ubuntu","type":"ubuntu"}]}`))
+	}))
+	defer srv.Close()
+
+	h := plugintest.New(t, newPlugins())
+	h.Start("../../../plugins/automation/lightboat", `{"base_url":"`+srv.URL+`/api/cloud","api_key":"k1","timeout_sec":2}`)
+	h.Health()
+	auto := h.Automation()
+	ctx := context.Background()
+
+	resp, err := auto.ListImages(ctx, &pluginv1.ListImagesRequest{LineId: 9})
+	if err != nil || len(resp.GetItems()) != 1 || resp.GetItems()[0].GetId() != 101 {
+		t.Fatalf("list images: %v %v", resp, err)
+	}
+	if gotKey != "k1" {
+		t.Fatalf("apikey header = %q, want k1", gotKey)
+	}
+
+	if err := h.ReloadConfig(`{"base_url":"` + srv.URL + `/api/cloud","api_key":"k2","timeout_sec":2}`); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if _, err := auto.ListImages(ctx, &pluginv1.ListImagesRequest{LineId: 9}); err != nil || gotKey != "k2" {
+		t.Fatalf("reload not applied: key=%q err=%v", gotKey, err)
+	}
+
+	if err := h.ReloadConfig(`{}`); err != nil {
+		t.Fatalf("reload empty config: %v", err)
+	}
+	if _, err := auto.ListImages(ctx, &pluginv1.ListImagesRequest{LineId: 9}); err == nil || !strings.Contains(err.Error(), "missing config") {
+		t.Fatalf("expected missing config, got %v", err)
+	}
+}
+
+func TestPlugin_ValidateConfig(t *testing.T) {
+	h := plugintest.New(t, newPlugins())
+	cases := map[string]string{
+		`{"base_url":"https://panel.example"}`:                                "missing required config: api_key",
+		`{"base_url":"https://panel.example","api_key":"k","timeout_sec":90}`: "timeout_sec out of range",
+		`{"base_url":"https://panel.example","api_key":"k","retry":9}`:        "retry out of range",
+	}
+	for cfg, want := range cases {
+		if err := h.ValidateConfig(cfg); err == nil || err.Error() != want {
+			t.Fatalf("validate %s: want %q, got %v", cfg, want, err)
+		}
+	}
+	if err := h.ValidateConfig(`{"base_url":"https://panel.example","api_key":"k","timeout_sec":12,"retry":1}`); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+}
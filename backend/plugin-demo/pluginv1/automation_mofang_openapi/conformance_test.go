@@ -0,0 +1,39 @@
+package main
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"xiaoheiplay/pkg/pluginsdk/conformance"
+)
+
+func TestConformance(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and launches the plugin binary")
+	}
+	conformance.Run(t, conformance.Suite{
+		PluginDir: conformance.Build(t, ".", filepath.Join("..", "..", "..", "plugins", "automation", "mofang_openapi", "manifest.json")),
+		Config: func(url string) string {
+			return `{"base_url":"` + url + `","account":"ops","api_password":"secret","timeout_sec":5}`
+		},
+		Routes: []conformance.Route{
+			{Method: http.MethodPost, Path: "/v1/login_api", Body: `{"status":200,"jwt":"tok"}`},
+			{Method: http.MethodGet, Path: "/v1/products", Body: `{"status":200,"data":{"first_group":[{"id":1,"name":"HK","group":[
+				{"id":10,"name":"HK-CN2","products":[{"id":100,"name":"S1","cpu":"1","memory":"1","sale_price":"9.90"}]}]}]}}`},
+			{Method: http.MethodGet, Path: "/v1/hosts", Body: `{"status":200,"data":{"host":[{"id":7,"domain":"vm-7","dedicatedip":"203.0.113.7"}]}}`},
+			{Method: http.MethodGet, Path: "/v1/hosts/7", Body: `{"status":200,"data":{"host":{"id":7,"domain":"vm-7","domainstatus":"Active"}}}`},
+			{Method: http.MethodGet, Path: "/v1/hosts/7/module/status", Body: `{"status":200,"data":{"status":"on"}}`},
+			{Method: http.MethodPut, Path: "/v1/hosts/7/module/vnc", Body: `{"status":200,"data":{"url":"https://vnc.example/7"}}`},
+			{Method: http.MethodPut, Path: "/v1/hosts/7/module/*", Body: `{"status":200}`},
+		},
+		Fixture: conformance.Fixture{InstanceID: 7, InstanceName: "vm-7", LineID: 10},
+		Skip: []string{
+			// The open API has no monitor or lock endpoints, so these lifecycle
+			// calls answer Unimplemented.
+			"supported/GetMonitor", "upstream_error/GetMonitor", "idempotent/Lock", "idempotent/Unlock",
+			// ListImages answers an empty list without asking the upstream.
+			"upstream_error/ListImages",
+		},
+	})
+}
@@ -3,11 +3,15 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	appshared "xiaoheiplay/internal/app/shared"
 	"xiaoheiplay/pkg/pluginsdk"
 	pluginv1 "xiaoheiplay/plugin/v1"
@@ -200,6 +204,10 @@ func wrapHTTPTraceErr(err error, last *HTTPLogEntry) error {
 	if err == nil {
 		return nil
 	}
+	// The host reports Unimplemented as "not supported" instead of a failure.
+	if errors.Is(err, errNotSupported) {
+		return status.Error(codes.Unimplemented, err.Error())
+	}
 	if last == nil || strings.TrimSpace(last.Action) == "" {
 		return err
 	}
@@ -260,6 +268,15 @@ type automationServer struct {
 	core *coreServer
 }
 
+// requireInstanceID rejects calls without a host before they reach the
+// upstream, which would otherwise be asked for /v1/hosts/0.
+func requireInstanceID(id int64) error {
+	if id <= 0 {
+		return status.Error(codes.InvalidArgument, "instance_id required")
+	}
+	return nil
+}
+
 func (a *automationServer) ListAreas(ctx context.Context, _ *pluginv1.Empty) (*pluginv1.ListAreasResponse, error) {
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
@@ -390,6 +407,9 @@ func (a *automationServer) CreateInstance(ctx context.Context, req *pluginv1.Cre
 }
 
 func (a *automationServer) GetInstance(ctx context.Context, req *pluginv1.GetInstanceRequest) (*pluginv1.GetInstanceResponse, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -447,6 +467,9 @@ func (a *automationServer) ListInstancesSimple(ctx context.Context, req *pluginv
 }
 
 func (a *automationServer) Start(ctx context.Context, req *pluginv1.StartRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -462,6 +485,9 @@ func (a *automationServer) Start(ctx context.Context, req *pluginv1.StartRequest
 }
 
 func (a *automationServer) Shutdown(ctx context.Context, req *pluginv1.ShutdownRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -477,6 +503,9 @@ func (a *automationServer) Shutdown(ctx context.Context, req *pluginv1.ShutdownR
 }
 
 func (a *automationServer) Reboot(ctx context.Context, req *pluginv1.RebootRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -492,6 +521,9 @@ func (a *automationServer) Reboot(ctx context.Context, req *pluginv1.RebootReque
 }
 
 func (a *automationServer) Rebuild(ctx context.Context, req *pluginv1.RebuildRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -507,6 +539,9 @@ func (a *automationServer) Rebuild(ctx context.Context, req *pluginv1.RebuildReq
 }
 
 func (a *automationServer) ResetPassword(ctx context.Context, req *pluginv1.ResetPasswordRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -522,6 +557,9 @@ func (a *automationServer) ResetPassword(ctx context.Context, req *pluginv1.Rese
 }
 
 func (a *automationServer) ElasticUpdate(ctx context.Context, req *pluginv1.ElasticUpdateRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -558,6 +596,9 @@ func (a *automationServer) ElasticUpdate(ctx context.Context, req *pluginv1.Elas
 }
 
 func (a *automationServer) Lock(ctx context.Context, req *pluginv1.LockRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -573,6 +614,9 @@ func (a *automationServer) Lock(ctx context.Context, req *pluginv1.LockRequest)
 }
 
 func (a *automationServer) Unlock(ctx context.Context, req *pluginv1.UnlockRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -588,6 +632,9 @@ func (a *automationServer) Unlock(ctx context.Context, req *pluginv1.UnlockReque
 }
 
 func (a *automationServer) Renew(ctx context.Context, req *pluginv1.RenewRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -604,6 +651,9 @@ func (a *automationServer) Renew(ctx context.Context, req *pluginv1.RenewRequest
 }
 
 func (a *automationServer) Destroy(ctx context.Context, req *pluginv1.DestroyRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -636,6 +686,9 @@ func (a *automationServer) GetPanelURL(ctx context.Context, req *pluginv1.GetPan
 }
 
 func (a *automationServer) GetVNCURL(ctx context.Context, req *pluginv1.GetVNCURLRequest) (*pluginv1.GetVNCURLResponse, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -653,6 +706,9 @@ func (a *automationServer) GetVNCURL(ctx context.Context, req *pluginv1.GetVNCUR
 }
 
 func (a *automationServer) GetMonitor(ctx context.Context, req *pluginv1.GetMonitorRequest) (*pluginv1.GetMonitorResponse, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -680,6 +736,9 @@ func (a *automationServer) GetMonitor(ctx context.Context, req *pluginv1.GetMoni
 }
 
 func (a *automationServer) ListPortMappings(ctx context.Context, req *pluginv1.ListPortMappingsRequest) (*pluginv1.ListPortMappingsResponse, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -710,6 +769,9 @@ func (a *automationServer) ListPortMappings(ctx context.Context, req *pluginv1.L
 }
 
 func (a *automationServer) AddPortMapping(ctx context.Context, req *pluginv1.AddPortMappingRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -730,6 +792,9 @@ func (a *automationServer) AddPortMapping(ctx context.Context, req *pluginv1.Add
 }
 
 func (a *automationServer) DeletePortMapping(ctx context.Context, req *pluginv1.DeletePortMappingRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -745,6 +810,9 @@ func (a *automationServer) DeletePortMapping(ctx context.Context, req *pluginv1.
 }
 
 func (a *automationServer) FindPortCandidates(ctx context.Context, req *pluginv1.FindPortCandidatesRequest) (*pluginv1.FindPortCandidatesResponse, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -762,6 +830,9 @@ func (a *automationServer) FindPortCandidates(ctx context.Context, req *pluginv1
 }
 
 func (a *automationServer) ListBackups(ctx context.Context, req *pluginv1.ListBackupsRequest) (*pluginv1.ListBackupsResponse, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -788,6 +859,9 @@ func (a *automationServer) ListBackups(ctx context.Context, req *pluginv1.ListBa
 }
 
 func (a *automationServer) CreateBackup(ctx context.Context, req *pluginv1.CreateBackupRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -803,6 +877,9 @@ func (a *automationServer) CreateBackup(ctx context.Context, req *pluginv1.Creat
 }
 
 func (a *automationServer) DeleteBackup(ctx context.Context, req *pluginv1.DeleteBackupRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -818,6 +895,9 @@ func (a *automationServer) DeleteBackup(ctx context.Context, req *pluginv1.Delet
 }
 
 func (a *automationServer) RestoreBackup(ctx context.Context, req *pluginv1.RestoreBackupRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -833,6 +913,9 @@ func (a *automationServer) RestoreBackup(ctx context.Context, req *pluginv1.Rest
 }
 
 func (a *automationServer) ListSnapshots(ctx context.Context, req *pluginv1.ListSnapshotsRequest) (*pluginv1.ListSnapshotsResponse, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -859,6 +942,9 @@ func (a *automationServer) ListSnapshots(ctx context.Context, req *pluginv1.List
 }
 
 func (a *automationServer) CreateSnapshot(ctx context.Context, req *pluginv1.CreateSnapshotRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -874,6 +960,9 @@ func (a *automationServer) CreateSnapshot(ctx context.Context, req *pluginv1.Cre
 }
 
 func (a *automationServer) DeleteSnapshot(ctx context.Context, req *pluginv1.DeleteSnapshotRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -889,6 +978,9 @@ func (a *automationServer) DeleteSnapshot(ctx context.Context, req *pluginv1.Del
 }
 
 func (a *automationServer) RestoreSnapshot(ctx context.Context, req *pluginv1.RestoreSnapshotRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -904,6 +996,9 @@ func (a *automationServer) RestoreSnapshot(ctx context.Context, req *pluginv1.Re
 }
 
 func (a *automationServer) ListFirewallRules(ctx context.Context, req *pluginv1.ListFirewallRulesRequest) (*pluginv1.ListFirewallRulesResponse, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -933,6 +1028,9 @@ func (a *automationServer) ListFirewallRules(ctx context.Context, req *pluginv1.
 }
 
 func (a *automationServer) AddFirewallRule(ctx context.Context, req *pluginv1.AddFirewallRuleRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -956,6 +1054,9 @@ func (a *automationServer) AddFirewallRule(ctx context.Context, req *pluginv1.Ad
 }
 
 func (a *automationServer) DeleteFirewallRule(ctx context.Context, req *pluginv1.DeleteFirewallRuleRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -1001,10 +1102,14 @@ func toTimeUnix(v1 any, v2 any) int64 {
 }
 
 func main() {
+	pluginsdk.Serve(newPlugins())
+}
+
+func newPlugins() map[string]pluginsdk.Plugin {
 	core := &coreServer{}
 	auto := &automationServer{core: core}
-	pluginsdk.Serve(map[string]pluginsdk.Plugin{
+	return map[string]pluginsdk.Plugin{
 		pluginsdk.PluginKeyCore:       &pluginsdk.CoreGRPCPlugin{Impl: core},
 		pluginsdk.PluginKeyAutomation: &pluginsdk.AutomationGRPCPlugin{Impl: auto},
-	})
+	}
 }
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	plugintest "xiaoheiplay/pkg/pluginsdk/testing"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+func newMofangServer(t *testing.T, calls *int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		switch r.URL.Path {
+		case "/v1/login_api":
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body["account"] != "ops" || body["password"] != "secret" {
+				_, _ = w.Write([]byte(`{"status":400,"msg":"bad credentials"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"status":200,"jwt":"tok"}`))
+		case "/v1/products":
+			if r.Header.Get("Authorization") != "JWT tok" {
+				t.Errorf("unexpected authorization %q", r.Header.Get("Authorization"))
+			}
+			_, _ = w.Write([]byte(`{"status":200,"data":{"first_group":[{"id":3,"name":"HK"},{"id":4,"name":"JP"}]}}`))
+		default:
+			t.Errorf("unexpected request path: %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestPlugin_ListAreasAndCatalogToggle(t *testing.T) {
+	var calls int32
+	srv := newMofangServer(t, &calls)
+	h := plugintest.New(t, newPlugins())
+	h.Start("../../../plugins/automation/mofang_openapi", `{"base_url":"`+srv.URL+`","account":"ops","api_password":"secret","timeout_sec":2}`)
+	h.Health()
+	auto := h.Automation()
+	ctx := context.Background()
+
+	resp, err := auto.ListAreas(ctx, &pluginv1.Empty{})
+	if err != nil || len(resp.GetItems()) != 2 || resp.GetItems()[0].GetName() != "HK" {
+		t.Fatalf("list areas: %v %v", resp, err)
+	}
+
+	// enable_catalog_sync defaults to true only when the key is absent.
+	if err := h.ReloadConfig(`{"base_url":"` + srv.URL + `","account":"ops","api_password":"secret","enable_catalog_sync":false}`); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	before := atomic.LoadInt32(&calls)
+	resp, err = auto.ListAreas(ctx, &pluginv1.Empty{})
+	if err != nil || len(resp.GetItems()) != 0 {
+		t.Fatalf("list areas with catalog sync off: %v %v", resp, err)
+	}
+	if atomic.LoadInt32(&calls) != before {
+		t.Fatalf("catalog sync off should not call upstream")
+	}
+
+	if err := h.ReloadConfig(`{"base_url":"` + srv.URL + `","account":"ops","api_password":"wrong"}`); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if _, err := auto.ListAreas(ctx, &pluginv1.Empty{}); err == nil {
+		t.Fatalf("expected login failure with a wrong password")
+	}
+	if err := h.ReloadConfig(`{`); err == nil || err.Error() != "invalid config" {
+		t.Fatalf("expected reload to reject malformed json, got %v", err)
+	}
+}
+
+func TestPlugin_ValidateConfig(t *testing.T) {
+	h := plugintest.New(t, newPlugins())
+	cases := map[string]string{
+		`{"base_url":"https://mf.example","account":"ops"}`:                                     "missing required config: api_password",
+		`{"base_url":"https://mf.example","account":"ops","api_password":"p","timeout_sec":61}`: "timeout_sec out of range",
+		`{"base_url":"https://mf.example","account":"ops","api_password":"p","retry":-1}`:       "retry out of range",
+	}
+	for cfg, want := range cases {
+		if err := h.ValidateConfig(cfg); err == nil || err.Error() != want {
+			t.Fatalf("validate %s: want %q, got %v", cfg, want, err)
+		}
+	}
+	if err := h.ValidateConfig(`{"base_url":"https://mf.example","account":"ops","api_password":"p"}`); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+}
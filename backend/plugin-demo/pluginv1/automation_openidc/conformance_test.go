@@ -0,0 +1,44 @@
+package main
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"xiaoheiplay/pkg/pluginsdk/conformance"
+)
+
+func TestConformance(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and launches the plugin binary")
+	}
+	ok := func(data string) string { return `{"code":200,"msg":"ok","data":` + data + `}` }
+	vm := "/hs1/vm-7"
+	conformance.Run(t, conformance.Suite{
+		PluginDir: conformance.Build(t, ".", filepath.Join("..", "..", "..", "plugins", "automation", "openidc_default", "manifest.json")),
+		Config: func(url string) string {
+			return `{"base_url":"` + url + `","api_key":"test","timeout_sec":5}`
+		},
+		Routes: []conformance.Route{
+			{Method: http.MethodGet, Path: "/api/server/detail", Body: ok(`{"hs1":{"server_name":"hs1","status":"online","server_area":"HK"}}`)},
+			{Method: http.MethodGet, Path: "/api/client/detail/hs1", Body: ok(`[{"vm_uuid":"vm-7","vm_name":"vm-7","status":"running","ip_address":"203.0.113.7"}]`)},
+			{Method: http.MethodGet, Path: "/api/client/detail" + vm, Body: ok(`{"vm_uuid":"vm-7","vm_name":"vm-7","status":"running","cpu_num":1,"mem_num":1024}`)},
+			{Method: http.MethodGet, Path: "/api/client/status" + vm, Body: ok(`{"vm_uuid":"vm-7","power_state":"on","cpu_usage":3}`)},
+			{Method: http.MethodGet, Path: "/api/client/remote" + vm, Body: ok(`{"console_url":"https://idc.example/vnc/vm-7","terminal_url":"https://idc.example/tty/vm-7"}`)},
+			{Method: http.MethodGet, Path: "/api/client/os-images/hs1", Body: ok(`{"system_maps":{"Debian 12":["debian12.vmdk","12"]}}`)},
+			{Method: http.MethodGet, Path: "/api/client/natget" + vm, Body: ok(`[]`)},
+			{Method: http.MethodGet, Path: "/api/client/backup/list" + vm, Body: ok(`[]`)},
+			{Method: http.MethodPost, Path: "/api/client/powers" + vm, Body: ok(`{}`)},
+		},
+		Fixture: conformance.Fixture{
+			InstanceID:   fnv64("hs1/vm-7"),
+			InstanceName: "hs1/vm-7",
+			LineID:       fnv64("hs1"),
+		},
+		Skip: []string{
+			// Plans are managed by the billing side; ListPackages answers an
+			// empty list once the line is known, without asking the upstream.
+			"upstream_error/ListPackages",
+		},
+	})
+}
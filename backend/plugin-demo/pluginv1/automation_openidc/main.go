@@ -11,6 +11,9 @@ import (
 	"sync"
 	"time"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	"xiaoheiplay/pkg/pluginsdk"
 	pluginv1 "xiaoheiplay/plugin/v1"
 )
@@ -377,6 +380,14 @@ type automationServer struct {
 	core *coreServer
 }
 
+// requireInstanceID 拒绝缺少 instance_id 的调用，避免为反查 ID 遍历 OpenIDCS 主机
+func requireInstanceID(id int64) error {
+	if id <= 0 {
+		return status.Error(codes.InvalidArgument, "instance_id required")
+	}
+	return nil
+}
+
 // ---- 目录同步 ----
 
 // ListAreas 地区列表（从 OpenIDCS server_area 字段获取）
@@ -591,6 +602,9 @@ func (a *automationServer) CreateInstance(ctx context.Context, req *pluginv1.Cre
 
 // GetInstance 查询虚拟机详情
 func (a *automationServer) GetInstance(ctx context.Context, req *pluginv1.GetInstanceRequest) (*pluginv1.GetInstanceResponse, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -679,6 +693,9 @@ func (a *automationServer) ListInstancesSimple(ctx context.Context, req *pluginv
 
 // Start 开机
 func (a *automationServer) Start(ctx context.Context, req *pluginv1.StartRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	if a.core.cfg.DryRun {
 		return &pluginv1.OperationResult{Ok: true}, nil
 	}
@@ -698,6 +715,9 @@ func (a *automationServer) Start(ctx context.Context, req *pluginv1.StartRequest
 
 // Shutdown 关机
 func (a *automationServer) Shutdown(ctx context.Context, req *pluginv1.ShutdownRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	if a.core.cfg.DryRun {
 		return &pluginv1.OperationResult{Ok: true}, nil
 	}
@@ -717,6 +737,9 @@ func (a *automationServer) Shutdown(ctx context.Context, req *pluginv1.ShutdownR
 
 // Reboot 重启
 func (a *automationServer) Reboot(ctx context.Context, req *pluginv1.RebootRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	if a.core.cfg.DryRun {
 		return &pluginv1.OperationResult{Ok: true}, nil
 	}
@@ -737,6 +760,9 @@ func (a *automationServer) Reboot(ctx context.Context, req *pluginv1.RebootReque
 // Rebuild 重装系统（挂载 ISO + 重启）
 // image_id = fnv64(hs_name + "/img/" + img.File)
 func (a *automationServer) Rebuild(ctx context.Context, req *pluginv1.RebuildRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	if a.core.cfg.DryRun {
 		return &pluginv1.OperationResult{Ok: true}, nil
 	}
@@ -776,6 +802,9 @@ func (a *automationServer) Rebuild(ctx context.Context, req *pluginv1.RebuildReq
 
 // ResetPassword 重置系统密码
 func (a *automationServer) ResetPassword(ctx context.Context, req *pluginv1.ResetPasswordRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	if a.core.cfg.DryRun {
 		return &pluginv1.OperationResult{Ok: true}, nil
 	}
@@ -797,6 +826,9 @@ func (a *automationServer) ResetPassword(ctx context.Context, req *pluginv1.Rese
 
 // ElasticUpdate 弹性变更配置
 func (a *automationServer) ElasticUpdate(ctx context.Context, req *pluginv1.ElasticUpdateRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	if a.core.cfg.DryRun {
 		return &pluginv1.OperationResult{Ok: true}, nil
 	}
@@ -829,6 +861,9 @@ func (a *automationServer) ElasticUpdate(ctx context.Context, req *pluginv1.Elas
 
 // Lock 锁定（强制关机，财务系统到期/欠费时调用）
 func (a *automationServer) Lock(ctx context.Context, req *pluginv1.LockRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	if a.core.cfg.DryRun {
 		return &pluginv1.OperationResult{Ok: true}, nil
 	}
@@ -863,6 +898,9 @@ func (a *automationServer) Renew(_ context.Context, _ *pluginv1.RenewRequest) (*
 
 // Destroy 销毁虚拟机
 func (a *automationServer) Destroy(ctx context.Context, req *pluginv1.DestroyRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	if a.core.cfg.DryRun {
 		return &pluginv1.OperationResult{Ok: true}, nil
 	}
@@ -882,6 +920,9 @@ func (a *automationServer) Destroy(ctx context.Context, req *pluginv1.DestroyReq
 
 // GetVNCURL 获取 VNC 控制台地址
 func (a *automationServer) GetVNCURL(ctx context.Context, req *pluginv1.GetVNCURLRequest) (*pluginv1.GetVNCURLResponse, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -944,6 +985,9 @@ func (a *automationServer) GetPanelURL(ctx context.Context, req *pluginv1.GetPan
 
 // GetMonitor 获取监控数据
 func (a *automationServer) GetMonitor(ctx context.Context, req *pluginv1.GetMonitorRequest) (*pluginv1.GetMonitorResponse, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -986,6 +1030,9 @@ func (a *automationServer) GetMonitor(ctx context.Context, req *pluginv1.GetMoni
 
 // ListPortMappings 获取 NAT 规则列表
 func (a *automationServer) ListPortMappings(ctx context.Context, req *pluginv1.ListPortMappingsRequest) (*pluginv1.ListPortMappingsResponse, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -1018,6 +1065,9 @@ func (a *automationServer) ListPortMappings(ctx context.Context, req *pluginv1.L
 // AddPortMapping 添加 NAT 规则
 // sport 格式："{host_port}/{protocol}" 或 "{host_port}"
 func (a *automationServer) AddPortMapping(ctx context.Context, req *pluginv1.AddPortMappingRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	if a.core.cfg.DryRun {
 		return &pluginv1.OperationResult{Ok: true}, nil
 	}
@@ -1048,6 +1098,9 @@ func (a *automationServer) AddPortMapping(ctx context.Context, req *pluginv1.Add
 // DeletePortMapping 删除 NAT 规则
 // mapping_id = rule_index
 func (a *automationServer) DeletePortMapping(ctx context.Context, req *pluginv1.DeletePortMappingRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	if a.core.cfg.DryRun {
 		return &pluginv1.OperationResult{Ok: true}, nil
 	}
@@ -1067,6 +1120,9 @@ func (a *automationServer) DeletePortMapping(ctx context.Context, req *pluginv1.
 
 // FindPortCandidates 查找可用端口候选（从 OpenIDCS 获取主机可分配端口）
 func (a *automationServer) FindPortCandidates(ctx context.Context, req *pluginv1.FindPortCandidatesRequest) (*pluginv1.FindPortCandidatesResponse, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	hsName, vmUUID, err := a.core.resolveInstanceWithFallback(ctx, req.GetInstanceId())
 	if err != nil {
 		// 如果 instance_id 无法解析，返回空列表
@@ -1093,6 +1149,9 @@ func (a *automationServer) FindPortCandidates(ctx context.Context, req *pluginv1
 
 // ListBackups 获取备份列表
 func (a *automationServer) ListBackups(ctx context.Context, req *pluginv1.ListBackupsRequest) (*pluginv1.ListBackupsResponse, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	c, last, err := a.core.newClientWithTrace()
 	if err != nil {
 		return nil, err
@@ -1125,6 +1184,9 @@ func (a *automationServer) ListBackups(ctx context.Context, req *pluginv1.ListBa
 
 // CreateBackup 创建备份
 func (a *automationServer) CreateBackup(ctx context.Context, req *pluginv1.CreateBackupRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	if a.core.cfg.DryRun {
 		return &pluginv1.OperationResult{Ok: true}, nil
 	}
@@ -1145,6 +1207,9 @@ func (a *automationServer) CreateBackup(ctx context.Context, req *pluginv1.Creat
 // DeleteBackup 删除备份
 // backup_id = 备份索引（对应 ListBackups 返回的 id）
 func (a *automationServer) DeleteBackup(ctx context.Context, req *pluginv1.DeleteBackupRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	if a.core.cfg.DryRun {
 		return &pluginv1.OperationResult{Ok: true}, nil
 	}
@@ -1173,6 +1238,9 @@ func (a *automationServer) DeleteBackup(ctx context.Context, req *pluginv1.Delet
 
 // RestoreBackup 恢复备份
 func (a *automationServer) RestoreBackup(ctx context.Context, req *pluginv1.RestoreBackupRequest) (*pluginv1.OperationResult, error) {
+	if err := requireInstanceID(req.GetInstanceId()); err != nil {
+		return nil, err
+	}
 	if a.core.cfg.DryRun {
 		return &pluginv1.OperationResult{Ok: true}, nil
 	}
@@ -1226,11 +1294,15 @@ func parseTimeToUnix(s string) int64 {
 func main() {
 	initLogger()
 	pluginLog.Printf("plugin starting, pid=%d", os.Getpid())
+	pluginsdk.Serve(newPlugins())
+}
+
+func newPlugins() map[string]pluginsdk.Plugin {
 	ids := newIDStore()
 	core := &coreServer{ids: ids}
 	auto := &automationServer{core: core}
-	pluginsdk.Serve(map[string]pluginsdk.Plugin{
+	return map[string]pluginsdk.Plugin{
 		pluginsdk.PluginKeyCore:       &pluginsdk.CoreGRPCPlugin{Impl: core},
 		pluginsdk.PluginKeyAutomation: &pluginsdk.AutomationGRPCPlugin{Impl: auto},
-	})
+	}
 }
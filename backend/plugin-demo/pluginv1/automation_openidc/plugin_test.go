@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	plugintest "xiaoheiplay/pkg/pluginsdk/testing"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+func TestMain(m *testing.M) {
+	// initLogger only runs from main and would create plugin.log.
+	pluginLog = log.New(io.Discard, "", 0)
+	os.Exit(m.Run())
+}
+
+func TestPlugin_ListAreasAndReload(t *testing.T) {
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		if r.URL.Path != "/api/server/detail" {
+			t.Errorf("unexpected request path: %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"code":200,"msg":"ok","data":{
+			"hs1":{"server_name":"hs1","status":"online","server_area":"HK"},
+			"hs2":{"server_name":"hs2","status":"offline","server_area":"HK"},
+			"hs3":{"server_name":"hs3","status":"online","server_area":"SG"}}}`))
+	}))
+	defer srv.Close()
+
+	h := plugintest.New(t, newPlugins())
+	h.Start("../../../plugins/automation/openidc_default", `{"base_url":"`+srv.URL+`","api_key":"k1","timeout_sec":2}`)
+	h.Health()
+	auto := h.Automation()
+	ctx := context.Background()
+
+	resp, err := auto.ListAreas(ctx, &pluginv1.Empty{})
+	if err != nil {
+		t.Fatalf("list areas: %v", err)
+	}
+	var names []string
+	for _, it := range resp.GetItems() {
+		names = append(names, it.GetName())
+	}
+	sort.Strings(names)
+	if strings.Join(names, ",") != "HK,SG" {
+		t.Fatalf("areas = %v, want HK,SG", names)
+	}
+	if gotAuth != "Bearer k1" {
+		t.Fatalf("authorization = %q", gotAuth)
+	}
+
+	if err := h.ReloadConfig(`{"base_url":"` + srv.URL + `","api_key":"k2"}`); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if _, err := auto.ListAreas(ctx, &pluginv1.Empty{}); err != nil || gotAuth != "Bearer k2" {
+		t.Fatalf("reload not applied: auth=%q err=%v", gotAuth, err)
+	}
+
+	if err := h.ReloadConfig(`{"base_url":"` + srv.URL + `"}`); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if _, err := auto.ListAreas(ctx, &pluginv1.Empty{}); err == nil || !strings.Contains(err.Error(), "missing config") {
+		t.Fatalf("expected missing config, got %v", err)
+	}
+}
+
+func TestPlugin_ValidateConfig(t *testing.T) {
+	h := plugintest.New(t, newPlugins())
+	cases := map[string]string{
+		`{"api_key":"k"}`: "missing required config: base_url",
+		`{"base_url":"https://idc.example","api_key":"k","timeout_sec":61}`: "timeout_sec out of range [0,60]",
+		`{"base_url":"https://idc.example","api_key":"k","retry":6}`:        "retry out of range [0,5]",
+	}
+	for cfg, want := range cases {
+		if err := h.ValidateConfig(cfg); err == nil || err.Error() != want {
+			t.Fatalf("validate %s: want %q, got %v", cfg, want, err)
+		}
+	}
+	if err := h.ValidateConfig(`{"base_url":"https://idc.example","api_key":"k","hs_name":"hs1"}`); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if err := h.Init(`{`); err == nil || err.Error() != "invalid config" {
+		t.Fatalf("expected init to reject malformed json, got %v", err)
+	}
+}
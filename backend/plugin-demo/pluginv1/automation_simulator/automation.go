@@ -0,0 +1,1044 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+const publicHost = "simulator.invalid"
+
+var errSimulated = status.Error(codes.Unavailable, "simulated upstream failure")
+
+type automationServer struct {
+	pluginv1.UnimplementedAutomationServiceServer
+	core *coreServer
+}
+
+// enter applies the configured latency and failure injection to rpc.
+func (a *automationServer) enter(ctx context.Context, rpc string) (*store, error) {
+	st := a.core.current()
+	if st == nil {
+		return nil, status.Error(codes.FailedPrecondition, "plugin not initialized")
+	}
+	cfg := a.core.config()
+	if delay := latency(cfg); delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+	}
+	if shouldFail(cfg, rpc) {
+		return nil, errSimulated
+	}
+	return st, nil
+}
+
+// op runs a state-changing RPC. Injected failures are reported as an
+// OperationResult when failure_mode is "result".
+func (a *automationServer) op(ctx context.Context, rpc string, fn func(st *store) error) (*pluginv1.OperationResult, error) {
+	st, err := a.enter(ctx, rpc)
+	if errors.Is(err, errSimulated) && a.core.config().FailureMode == failureModeResult {
+		return &pluginv1.OperationResult{Ok: false, ErrorCode: "simulated_failure", ErrorMessage: "simulated upstream failure"}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := st.write(func() error { return fn(st) }); err != nil {
+		return nil, err
+	}
+	return &pluginv1.OperationResult{Ok: true}, nil
+}
+
+func latency(cfg config) time.Duration {
+	ms := cfg.LatencyMS
+	if cfg.JitterMS > 0 {
+		ms += rand.IntN(cfg.JitterMS + 1)
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+func shouldFail(cfg config, rpc string) bool {
+	for _, name := range strings.Split(cfg.FailRPCs, ",") {
+		name = strings.TrimSpace(name)
+		if name == "*" || strings.EqualFold(name, rpc) {
+			return true
+		}
+	}
+	return cfg.FailureRate > 0 && rand.Float64() < cfg.FailureRate
+}
+
+func (a *automationServer) ListAreas(ctx context.Context, _ *pluginv1.Empty) (*pluginv1.ListAreasResponse, error) {
+	st, err := a.enter(ctx, "ListAreas")
+	if err != nil {
+		return nil, err
+	}
+	resp := &pluginv1.ListAreasResponse{}
+	err = st.read(func() error {
+		for _, it := range st.m.Areas {
+			resp.Items = append(resp.Items, &pluginv1.AutomationArea{Id: it.ID, Name: it.Name, State: it.State})
+		}
+		return nil
+	})
+	return resp, err
+}
+
+func (a *automationServer) ListLines(ctx context.Context, _ *pluginv1.Empty) (*pluginv1.ListLinesResponse, error) {
+	st, err := a.enter(ctx, "ListLines")
+	if err != nil {
+		return nil, err
+	}
+	resp := &pluginv1.ListLinesResponse{}
+	err = st.read(func() error {
+		for _, it := range st.m.Lines {
+			resp.Items = append(resp.Items, &pluginv1.AutomationLine{Id: it.ID, Name: it.Name, AreaId: it.AreaID, State: it.State})
+		}
+		return nil
+	})
+	return resp, err
+}
+
+func (a *automationServer) ListPackages(ctx context.Context, req *pluginv1.ListPackagesRequest) (*pluginv1.ListPackagesResponse, error) {
+	st, err := a.enter(ctx, "ListPackages")
+	if err != nil {
+		return nil, err
+	}
+	resp := &pluginv1.ListPackagesResponse{}
+	err = st.read(func() error {
+		for _, it := range st.m.Packages {
+			if req.GetLineId() > 0 && it.LineID != req.GetLineId() {
+				continue
+			}
+			item := &pluginv1.AutomationPackage{
+				Id:            it.ID,
+				Name:          it.Name,
+				Cpu:           it.CPU,
+				MemoryGb:      it.MemoryGB,
+				DiskGb:        it.DiskGB,
+				BandwidthMbps: it.BandwidthMbps,
+				PortNum:       it.PortNum,
+				MonthlyPrice:  it.MonthlyPrice,
+			}
+			if remaining := st.remainingLocked(it.ID); remaining >= 0 {
+				v := int32(remaining)
+				item.CapacityRemaining = &v
+			}
+			resp.Items = append(resp.Items, item)
+		}
+		return nil
+	})
+	return resp, err
+}
+
+func (a *automationServer) ListImages(ctx context.Context, _ *pluginv1.ListImagesRequest) (*pluginv1.ListImagesResponse, error) {
+	st, err := a.enter(ctx, "ListImages")
+	if err != nil {
+		return nil, err
+	}
+	resp := &pluginv1.ListImagesResponse{}
+	err = st.read(func() error {
+		for _, it := range st.m.Images {
+			resp.Items = append(resp.Items, &pluginv1.AutomationImage{Id: it.ID, Name: it.Name, Type: it.Type})
+		}
+		return nil
+	})
+	return resp, err
+}
+
+func (a *automationServer) CreateInstance(ctx context.Context, req *pluginv1.CreateInstanceRequest) (*pluginv1.CreateInstanceResponse, error) {
+	st, err := a.enter(ctx, "CreateInstance")
+	if err != nil {
+		return nil, err
+	}
+	var id int64
+	err = st.write(func() error {
+		pkg, err := st.resolvePackageLocked(req.GetPackageId(), req.GetLineId())
+		if err != nil {
+			return err
+		}
+		if st.remainingLocked(pkg.ID) == 0 {
+			return status.Errorf(codes.ResourceExhausted, "package %d is sold out", pkg.ID)
+		}
+		img, ok := st.imageLocked(req.GetImageId(), strings.TrimSpace(req.GetOs()))
+		if !ok {
+			if req.GetImageId() > 0 || strings.TrimSpace(req.GetOs()) != "" {
+				return status.Error(codes.InvalidArgument, "unknown image")
+			}
+			img = st.m.Images[0]
+		}
+		h := st.newHost(pkg, img.ID, strings.TrimSpace(req.GetName()))
+		overrideSpec(&h.CPU, req.GetCpu())
+		overrideSpec(&h.MemoryGB, req.GetMemoryGb())
+		overrideSpec(&h.DiskGB, req.GetDiskGb())
+		overrideSpec(&h.BandwidthMbps, req.GetBandwidthMbps())
+		overrideSpec(&h.PortNum, req.GetPortNum())
+		h.OSPassword = req.GetPassword()
+		if v := req.GetVncPassword(); v != "" {
+			h.VNCPassword = v
+		}
+		h.ExpireAt = req.GetExpireAtUnix()
+		h.SSHKeys = req.GetSshKeys()
+		h.UserData = req.GetUserData()
+		h.Startup = req.GetStartupScript()
+		h.NoPassword = req.GetDisablePasswordLogin()
+		h.State = stateCreating
+		h.BusySince = st.now()
+		h.BusyUntil = h.BusySince.Add(time.Duration(st.cfg.ProvisionSeconds) * time.Second)
+		h.BusyFail = st.cfg.ProvisionFailRate > 0 && rand.Float64() < st.cfg.ProvisionFailRate
+		st.m.Hosts = append(st.m.Hosts, h)
+		id = h.ID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pluginv1.CreateInstanceResponse{InstanceId: id}, nil
+}
+
+func (s *store) resolvePackageLocked(packageID, lineID int64) (product, error) {
+	if packageID > 0 {
+		p, ok := s.packageLocked(packageID)
+		if !ok {
+			return product{}, status.Errorf(codes.NotFound, "package %d not found", packageID)
+		}
+		lineID = p.LineID
+	}
+	if lineID <= 0 {
+		return product{}, status.Error(codes.InvalidArgument, "line_id or package_id required")
+	}
+	ln, ok := s.lineLocked(lineID)
+	if !ok {
+		return product{}, status.Errorf(codes.NotFound, "line %d not found", lineID)
+	}
+	if ln.State != 1 {
+		return product{}, status.Errorf(codes.FailedPrecondition, "line %d is not accepting orders", lineID)
+	}
+	if packageID > 0 {
+		p, _ := s.packageLocked(packageID)
+		return p, nil
+	}
+	for _, p := range s.m.Packages {
+		if p.LineID == lineID {
+			return p, nil
+		}
+	}
+	return product{}, status.Errorf(codes.FailedPrecondition, "line %d has no packages", lineID)
+}
+
+func overrideSpec(dst *int32, v int32) {
+	if v > 0 {
+		*dst = v
+	}
+}
+
+func (a *automationServer) GetInstance(ctx context.Context, req *pluginv1.GetInstanceRequest) (*pluginv1.GetInstanceResponse, error) {
+	st, err := a.enter(ctx, "GetInstance")
+	if err != nil {
+		return nil, err
+	}
+	resp := &pluginv1.GetInstanceResponse{}
+	err = st.read(func() error {
+		h, err := st.hostLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		resp.Instance = &pluginv1.AutomationInstance{
+			Id:            h.ID,
+			Name:          h.Name,
+			State:         h.State,
+			Cpu:           h.CPU,
+			MemoryGb:      h.MemoryGB,
+			DiskGb:        h.DiskGB,
+			BandwidthMbps: h.BandwidthMbps,
+			RemoteIp:      h.IPs[0],
+			PanelPassword: h.PanelPassword,
+			VncPassword:   h.VNCPassword,
+			OsPassword:    h.OSPassword,
+			ExpireAtUnix:  h.ExpireAt,
+		}
+		if h.State == stateCreating || h.State == stateRebuilding {
+			progress := st.taskProgressLocked(h)
+			resp.Instance.TaskProgress = &progress
+			resp.Instance.TaskMessage = "rebuilding"
+			if h.State == stateCreating {
+				resp.Instance.TaskMessage = "creating"
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (a *automationServer) ListInstancesSimple(ctx context.Context, req *pluginv1.ListInstancesSimpleRequest) (*pluginv1.ListInstancesSimpleResponse, error) {
+	st, err := a.enter(ctx, "ListInstancesSimple")
+	if err != nil {
+		return nil, err
+	}
+	tag := strings.ToLower(strings.TrimSpace(req.GetSearchTag()))
+	resp := &pluginv1.ListInstancesSimpleResponse{}
+	err = st.read(func() error {
+		for _, h := range st.m.Hosts {
+			if tag != "" && !strings.Contains(strings.ToLower(h.Name), tag) && !slices.Contains(h.IPs, tag) {
+				continue
+			}
+			resp.Items = append(resp.Items, &pluginv1.AutomationInstanceSimple{Id: h.ID, Name: h.Name, Ip: h.IPs[0]})
+		}
+		return nil
+	})
+	return resp, err
+}
+
+func (a *automationServer) Start(ctx context.Context, req *pluginv1.StartRequest) (*pluginv1.OperationResult, error) {
+	return a.op(ctx, "Start", func(st *store) error {
+		h, err := st.operableLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		h.State = stateRunning
+		return nil
+	})
+}
+
+func (a *automationServer) Shutdown(ctx context.Context, req *pluginv1.ShutdownRequest) (*pluginv1.OperationResult, error) {
+	return a.op(ctx, "Shutdown", func(st *store) error {
+		h, err := st.operableLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		h.State = stateStopped
+		return nil
+	})
+}
+
+func (a *automationServer) Reboot(ctx context.Context, req *pluginv1.RebootRequest) (*pluginv1.OperationResult, error) {
+	return a.op(ctx, "Reboot", func(st *store) error {
+		h, err := st.operableLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		h.State = stateRunning
+		return nil
+	})
+}
+
+func (a *automationServer) Rebuild(ctx context.Context, req *pluginv1.RebuildRequest) (*pluginv1.OperationResult, error) {
+	return a.op(ctx, "Rebuild", func(st *store) error {
+		h, err := st.rebuildableLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		if req.GetImageId() <= 0 {
+			return status.Error(codes.InvalidArgument, "image_id required")
+		}
+		img, ok := st.imageLocked(req.GetImageId(), "")
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "image %d not found", req.GetImageId())
+		}
+		h.ImageID = img.ID
+		h.OSPassword = req.GetPassword()
+		h.SSHKeys = req.GetSshKeys()
+		h.UserData = req.GetUserData()
+		h.Startup = req.GetStartupScript()
+		h.NoPassword = req.GetDisablePasswordLogin()
+		st.beginRebuildLocked(h)
+		return nil
+	})
+}
+
+// rebuildableLocked also accepts hosts whose last rebuild failed.
+func (s *store) rebuildableLocked(id int64) (*host, error) {
+	h, err := s.hostLocked(id)
+	if err != nil {
+		return nil, err
+	}
+	if h.State == stateRebuildFailed {
+		return h, nil
+	}
+	return s.operableLocked(id)
+}
+
+func (s *store) beginRebuildLocked(h *host) {
+	h.Rescue, h.ISO = false, ""
+	h.State = stateRebuilding
+	h.BusySince = s.now()
+	h.BusyUntil = h.BusySince.Add(time.Duration(s.cfg.RebuildSeconds) * time.Second)
+	h.BusyFail = false
+}
+
+func (a *automationServer) ResetPassword(ctx context.Context, req *pluginv1.ResetPasswordRequest) (*pluginv1.OperationResult, error) {
+	return a.op(ctx, "ResetPassword", func(st *store) error {
+		h, err := st.operableLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		if req.GetPassword() == "" {
+			return status.Error(codes.InvalidArgument, "password required")
+		}
+		h.OSPassword = req.GetPassword()
+		return nil
+	})
+}
+
+func (a *automationServer) ElasticUpdate(ctx context.Context, req *pluginv1.ElasticUpdateRequest) (*pluginv1.OperationResult, error) {
+	return a.op(ctx, "ElasticUpdate", func(st *store) error {
+		h, err := st.operableLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		if req.DiskGb != nil && req.GetDiskGb() < h.DiskGB {
+			return status.Errorf(codes.InvalidArgument, "disk cannot shrink from %d to %d GB", h.DiskGB, req.GetDiskGb())
+		}
+		for _, v := range []*int32{req.Cpu, req.MemoryGb, req.DiskGb, req.BandwidthMbps} {
+			if v != nil && *v <= 0 {
+				return status.Error(codes.InvalidArgument, "resource values must be positive")
+			}
+		}
+		if req.PortNum != nil && (req.GetPortNum() < 0 || int(req.GetPortNum()) < len(h.PortMappings)) {
+			return status.Errorf(codes.InvalidArgument, "port_num below the %d mappings in use", len(h.PortMappings))
+		}
+		setOptional(&h.CPU, req.Cpu)
+		setOptional(&h.MemoryGB, req.MemoryGb)
+		setOptional(&h.DiskGB, req.DiskGb)
+		setOptional(&h.BandwidthMbps, req.BandwidthMbps)
+		setOptional(&h.PortNum, req.PortNum)
+		return nil
+	})
+}
+
+func setOptional(dst *int32, v *int32) {
+	if v != nil {
+		*dst = *v
+	}
+}
+
+func (a *automationServer) Lock(ctx context.Context, req *pluginv1.LockRequest) (*pluginv1.OperationResult, error) {
+	return a.op(ctx, "Lock", func(st *store) error {
+		h, err := st.hostLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		if h.State != stateLocked {
+			h.LockedFrom, h.State = h.State, stateLocked
+		}
+		return nil
+	})
+}
+
+func (a *automationServer) Unlock(ctx context.Context, req *pluginv1.UnlockRequest) (*pluginv1.OperationResult, error) {
+	return a.op(ctx, "Unlock", func(st *store) error {
+		h, err := st.hostLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		if h.State == stateLocked {
+			h.State = unlockedState(h)
+		}
+		return nil
+	})
+}
+
+func unlockedState(h *host) int32 {
+	if h.LockedFrom == stateLocked || h.LockedFrom == stateCreating {
+		return stateRunning
+	}
+	return h.LockedFrom
+}
+
+// Renew moves the due date and undoes the expire action, like a panel
+// unsuspending a paid host.
+func (a *automationServer) Renew(ctx context.Context, req *pluginv1.RenewRequest) (*pluginv1.OperationResult, error) {
+	return a.op(ctx, "Renew", func(st *store) error {
+		h, err := st.hostLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		if req.GetNextDueAtUnix() <= 0 {
+			return status.Error(codes.InvalidArgument, "next_due_at_unix required")
+		}
+		h.ExpireAt = req.GetNextDueAtUnix()
+		if h.Expired {
+			h.Expired = false
+			switch {
+			case st.cfg.ExpireAction == expireStop && h.State == stateStopped:
+				h.State = stateRunning
+			case st.cfg.ExpireAction == expireLock && h.State == stateLocked:
+				h.State = unlockedState(h)
+			}
+		}
+		return nil
+	})
+}
+
+// Destroy succeeds for hosts that are already gone so refunds can retry.
+func (a *automationServer) Destroy(ctx context.Context, req *pluginv1.DestroyRequest) (*pluginv1.OperationResult, error) {
+	return a.op(ctx, "Destroy", func(st *store) error {
+		if req.GetInstanceId() <= 0 {
+			return status.Error(codes.InvalidArgument, "instance_id required")
+		}
+		st.removeHostLocked(req.GetInstanceId())
+		return nil
+	})
+}
+
+func (a *automationServer) GetPanelURL(ctx context.Context, req *pluginv1.GetPanelURLRequest) (*pluginv1.GetPanelURLResponse, error) {
+	st, err := a.enter(ctx, "GetPanelURL")
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(req.GetInstanceName())
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "instance_name required")
+	}
+	resp := &pluginv1.GetPanelURLResponse{}
+	err = st.read(func() error {
+		for _, h := range st.m.Hosts {
+			if h.Name != name {
+				continue
+			}
+			if req.GetPanelPassword() != "" && req.GetPanelPassword() != h.PanelPassword {
+				return status.Error(codes.PermissionDenied, "panel password mismatch")
+			}
+			resp.Url = fmt.Sprintf("https://%s/panel/%d", publicHost, h.ID)
+			return nil
+		}
+		return status.Errorf(codes.NotFound, "instance %q not found", name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (a *automationServer) GetVNCURL(ctx context.Context, req *pluginv1.GetVNCURLRequest) (*pluginv1.GetVNCURLResponse, error) {
+	st, err := a.enter(ctx, "GetVNCURL")
+	if err != nil {
+		return nil, err
+	}
+	resp := &pluginv1.GetVNCURLResponse{}
+	err = st.read(func() error {
+		h, err := st.hostLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		resp.Url = fmt.Sprintf("wss://%s/vnc/%d?password=%s", publicHost, h.ID, url.QueryEscape(h.VNCPassword))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetMonitor reports load that drifts minute by minute, in the raw format
+// of the panels the host already parses.
+func (a *automationServer) GetMonitor(ctx context.Context, req *pluginv1.GetMonitorRequest) (*pluginv1.GetMonitorResponse, error) {
+	st, err := a.enter(ctx, "GetMonitor")
+	if err != nil {
+		return nil, err
+	}
+	resp := &pluginv1.GetMonitorResponse{}
+	err = st.read(func() error {
+		h, err := st.hostLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		var cpu, mem, rx, tx int64
+		if h.State == stateRunning {
+			tick := h.ID*31 + st.now().Unix()/60
+			cpu = 3 + tick%47
+			mem = 20 + (tick*7)%55
+			rx = 2048 + (tick*7919)%(int64(h.BandwidthMbps)*131072+1)
+			tx = 1024 + (tick*104729)%(int64(h.BandwidthMbps)*65536+1)
+		}
+		disk := 8 + (h.ID*13)%60
+		resp.RawJson = fmt.Sprintf(`{"CpuStats":%d,"MemoryStats":%d,"StorageStats":%d,"NetworkStats":{"BytesReceivedPersec":%d,"BytesSentPersec":%d}}`, cpu, mem, disk, rx, tx)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (a *automationServer) ListPortMappings(ctx context.Context, req *pluginv1.ListPortMappingsRequest) (*pluginv1.ListPortMappingsResponse, error) {
+	st, err := a.enter(ctx, "ListPortMappings")
+	if err != nil {
+		return nil, err
+	}
+	resp := &pluginv1.ListPortMappingsResponse{}
+	err = st.read(func() error {
+		h, err := st.hostLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		for _, it := range h.PortMappings {
+			resp.Items = append(resp.Items, &pluginv1.AutomationPortMapping{Id: it.ID, Name: it.Name, Sport: it.Sport, Dport: it.Dport})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (a *automationServer) AddPortMapping(ctx context.Context, req *pluginv1.AddPortMappingRequest) (*pluginv1.OperationResult, error) {
+	return a.op(ctx, "AddPortMapping", func(st *store) error {
+		h, err := st.operableLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		if req.GetDport() < 1 || req.GetDport() > 65535 {
+			return status.Error(codes.InvalidArgument, "dport out of range")
+		}
+		if h.PortNum > 0 && len(h.PortMappings) >= int(h.PortNum) {
+			return status.Errorf(codes.ResourceExhausted, "port mapping limit %d reached", h.PortNum)
+		}
+		sport := strings.TrimSpace(req.GetSport())
+		if sport == "" {
+			ports := st.freePortsLocked(0, 1)
+			if len(ports) == 0 {
+				return status.Error(codes.ResourceExhausted, "no public port available")
+			}
+			sport = strconv.FormatInt(ports[0], 10)
+		} else if n, err := strconv.ParseInt(sport, 10, 64); err != nil || n < portRangeStart || n > portRangeEnd {
+			return status.Errorf(codes.InvalidArgument, "sport must be a port between %d and %d", portRangeStart, portRangeEnd)
+		} else if st.portUsedLocked(n) {
+			return status.Errorf(codes.AlreadyExists, "public port %s is in use", sport)
+		}
+		name := strings.TrimSpace(req.GetName())
+		if name == "" {
+			name = fmt.Sprintf("port-%d", req.GetDport())
+		}
+		h.PortMappings = append(h.PortMappings, portMapping{ID: st.nextIDLocked(), Name: name, Sport: sport, Dport: req.GetDport()})
+		return nil
+	})
+}
+
+func (a *automationServer) DeletePortMapping(ctx context.Context, req *pluginv1.DeletePortMappingRequest) (*pluginv1.OperationResult, error) {
+	return a.op(ctx, "DeletePortMapping", func(st *store) error {
+		h, err := st.operableLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		i := slices.IndexFunc(h.PortMappings, func(it portMapping) bool { return it.ID == req.GetMappingId() })
+		if i < 0 {
+			return status.Errorf(codes.NotFound, "port mapping %d not found", req.GetMappingId())
+		}
+		h.PortMappings = slices.Delete(h.PortMappings, i, i+1)
+		return nil
+	})
+}
+
+func (a *automationServer) FindPortCandidates(ctx context.Context, req *pluginv1.FindPortCandidatesRequest) (*pluginv1.FindPortCandidatesResponse, error) {
+	st, err := a.enter(ctx, "FindPortCandidates")
+	if err != nil {
+		return nil, err
+	}
+	resp := &pluginv1.FindPortCandidatesResponse{}
+	err = st.read(func() error {
+		if _, err := st.hostLocked(req.GetInstanceId()); err != nil {
+			return err
+		}
+		from, _ := strconv.ParseInt(strings.TrimSpace(req.GetKeywords()), 10, 64)
+		resp.Ports = st.freePortsLocked(from, 10)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+const (
+	portRangeStart = 20000
+	portRangeEnd   = 60000
+)
+
+// Public ports are shared by every host on the simulated node.
+func (s *store) portUsedLocked(port int64) bool {
+	p := strconv.FormatInt(port, 10)
+	for _, h := range s.m.Hosts {
+		for _, it := range h.PortMappings {
+			if it.Sport == p {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *store) freePortsLocked(from int64, limit int) []int64 {
+	from = max(from, portRangeStart)
+	var out []int64
+	for port := from; port <= portRangeEnd && len(out) < limit; port++ {
+		if !s.portUsedLocked(port) {
+			out = append(out, port)
+		}
+	}
+	return out
+}
+
+func (a *automationServer) ListBackups(ctx context.Context, req *pluginv1.ListBackupsRequest) (*pluginv1.ListBackupsResponse, error) {
+	st, err := a.enter(ctx, "ListBackups")
+	if err != nil {
+		return nil, err
+	}
+	resp := &pluginv1.ListBackupsResponse{}
+	err = st.read(func() error {
+		h, err := st.hostLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		for _, it := range h.Backups {
+			resp.Items = append(resp.Items, &pluginv1.AutomationBackup{Id: it.ID, Name: it.Name, CreatedAtUnix: it.CreatedAt, State: it.State})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (a *automationServer) CreateBackup(ctx context.Context, req *pluginv1.CreateBackupRequest) (*pluginv1.OperationResult, error) {
+	return a.op(ctx, "CreateBackup", func(st *store) error {
+		h, err := st.operableLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		h.Backups = append(h.Backups, st.newArtifactLocked("backup"))
+		return nil
+	})
+}
+
+func (a *automationServer) DeleteBackup(ctx context.Context, req *pluginv1.DeleteBackupRequest) (*pluginv1.OperationResult, error) {
+	return a.op(ctx, "DeleteBackup", func(st *store) error {
+		h, err := st.operableLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		return deleteArtifact(&h.Backups, req.GetBackupId(), "backup")
+	})
+}
+
+func (a *automationServer) RestoreBackup(ctx context.Context, req *pluginv1.RestoreBackupRequest) (*pluginv1.OperationResult, error) {
+	return a.op(ctx, "RestoreBackup", func(st *store) error {
+		return st.restoreLocked(req.GetInstanceId(), func(h *host) []artifact { return h.Backups }, req.GetBackupId(), "backup")
+	})
+}
+
+func (a *automationServer) ListSnapshots(ctx context.Context, req *pluginv1.ListSnapshotsRequest) (*pluginv1.ListSnapshotsResponse, error) {
+	st, err := a.enter(ctx, "ListSnapshots")
+	if err != nil {
+		return nil, err
+	}
+	resp := &pluginv1.ListSnapshotsResponse{}
+	err = st.read(func() error {
+		h, err := st.hostLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		for _, it := range h.Snapshots {
+			resp.Items = append(resp.Items, &pluginv1.AutomationSnapshot{Id: it.ID, Name: it.Name, CreatedAtUnix: it.CreatedAt, State: it.State})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (a *automationServer) CreateSnapshot(ctx context.Context, req *pluginv1.CreateSnapshotRequest) (*pluginv1.OperationResult, error) {
+	return a.op(ctx, "CreateSnapshot", func(st *store) error {
+		h, err := st.operableLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		h.Snapshots = append(h.Snapshots, st.newArtifactLocked("snapshot"))
+		return nil
+	})
+}
+
+func (a *automationServer) DeleteSnapshot(ctx context.Context, req *pluginv1.DeleteSnapshotRequest) (*pluginv1.OperationResult, error) {
+	return a.op(ctx, "DeleteSnapshot", func(st *store) error {
+		h, err := st.operableLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		return deleteArtifact(&h.Snapshots, req.GetSnapshotId(), "snapshot")
+	})
+}
+
+func (a *automationServer) RestoreSnapshot(ctx context.Context, req *pluginv1.RestoreSnapshotRequest) (*pluginv1.OperationResult, error) {
+	return a.op(ctx, "RestoreSnapshot", func(st *store) error {
+		return st.restoreLocked(req.GetInstanceId(), func(h *host) []artifact { return h.Snapshots }, req.GetSnapshotId(), "snapshot")
+	})
+}
+
+func (s *store) newArtifactLocked(kind string) artifact {
+	now := s.now()
+	return artifact{ID: s.nextIDLocked(), Name: kind + "-" + now.Format("20060102-150405"), CreatedAt: now.Unix(), State: 1}
+}
+
+func deleteArtifact(items *[]artifact, id int64, kind string) error {
+	i := slices.IndexFunc(*items, func(it artifact) bool { return it.ID == id })
+	if i < 0 {
+		return status.Errorf(codes.NotFound, "%s %d not found", kind, id)
+	}
+	*items = slices.Delete(*items, i, i+1)
+	return nil
+}
+
+// restoreLocked puts the host through a rebuild-length restore.
+func (s *store) restoreLocked(hostID int64, items func(*host) []artifact, id int64, kind string) error {
+	h, err := s.rebuildableLocked(hostID)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(items(h), func(it artifact) bool { return it.ID == id }) {
+		return status.Errorf(codes.NotFound, "%s %d not found", kind, id)
+	}
+	s.beginRebuildLocked(h)
+	return nil
+}
+
+func (a *automationServer) ListFirewallRules(ctx context.Context, req *pluginv1.ListFirewallRulesRequest) (*pluginv1.ListFirewallRulesResponse, error) {
+	st, err := a.enter(ctx, "ListFirewallRules")
+	if err != nil {
+		return nil, err
+	}
+	resp := &pluginv1.ListFirewallRulesResponse{}
+	err = st.read(func() error {
+		h, err := st.hostLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		for _, it := range h.Firewall {
+			resp.Items = append(resp.Items, &pluginv1.AutomationFirewallRule{
+				Id: it.ID, Direction: it.Direction, Protocol: it.Protocol, Method: it.Method, Port: it.Port, Ip: it.IP, Priority: it.Priority,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (a *automationServer) AddFirewallRule(ctx context.Context, req *pluginv1.AddFirewallRuleRequest) (*pluginv1.OperationResult, error) {
+	return a.op(ctx, "AddFirewallRule", func(st *store) error {
+		h, err := st.operableLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		rule := firewallRule{
+			Direction: normalizeChoice(req.GetDirection(), "In", "Out"),
+			Protocol:  normalizeChoice(req.GetProtocol(), "tcp", "udp", "icmp", "all"),
+			Method:    normalizeChoice(req.GetMethod(), "allowed", "denied"),
+			Port:      strings.TrimSpace(req.GetPort()),
+			IP:        strings.TrimSpace(req.GetIp()),
+			Priority:  req.GetPriority(),
+		}
+		if rule.Direction == "" || rule.Protocol == "" || rule.Method == "" {
+			return status.Error(codes.InvalidArgument, "direction must be In/Out, protocol tcp/udp/icmp/all, method allowed/denied")
+		}
+		rule.ID = st.nextIDLocked()
+		h.Firewall = append(h.Firewall, rule)
+		return nil
+	})
+}
+
+func normalizeChoice(v string, choices ...string) string {
+	for _, c := range choices {
+		if strings.EqualFold(strings.TrimSpace(v), c) {
+			return c
+		}
+	}
+	return ""
+}
+
+func (a *automationServer) DeleteFirewallRule(ctx context.Context, req *pluginv1.DeleteFirewallRuleRequest) (*pluginv1.OperationResult, error) {
+	return a.op(ctx, "DeleteFirewallRule", func(st *store) error {
+		h, err := st.operableLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		i := slices.IndexFunc(h.Firewall, func(it firewallRule) bool { return it.ID == req.GetRuleId() })
+		if i < 0 {
+			return status.Errorf(codes.NotFound, "firewall rule %d not found", req.GetRuleId())
+		}
+		h.Firewall = slices.Delete(h.Firewall, i, i+1)
+		return nil
+	})
+}
+
+func (a *automationServer) ListReverseDNS(ctx context.Context, req *pluginv1.ListReverseDNSRequest) (*pluginv1.ListReverseDNSResponse, error) {
+	st, err := a.enter(ctx, "ListReverseDNS")
+	if err != nil {
+		return nil, err
+	}
+	resp := &pluginv1.ListReverseDNSResponse{}
+	err = st.read(func() error {
+		h, err := st.hostLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		for _, ip := range h.IPs {
+			resp.Items = append(resp.Items, &pluginv1.AutomationReverseDNSRecord{Ip: ip, Ptr: h.PTR[ip]})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (a *automationServer) SetReverseDNS(ctx context.Context, req *pluginv1.SetReverseDNSRequest) (*pluginv1.OperationResult, error) {
+	return a.op(ctx, "SetReverseDNS", func(st *store) error {
+		h, err := st.hostLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		ip := strings.TrimSpace(req.GetIp())
+		if !slices.Contains(h.IPs, ip) {
+			return status.Errorf(codes.InvalidArgument, "ip %s is not assigned to instance %d", ip, h.ID)
+		}
+		if h.PTR == nil {
+			h.PTR = map[string]string{}
+		}
+		if ptr := strings.TrimSpace(req.GetPtr()); ptr != "" {
+			h.PTR[ip] = ptr
+		} else {
+			delete(h.PTR, ip)
+		}
+		return nil
+	})
+}
+
+func (a *automationServer) AssignIP(ctx context.Context, req *pluginv1.AssignIPRequest) (*pluginv1.AssignIPResponse, error) {
+	st, err := a.enter(ctx, "AssignIP")
+	if err != nil {
+		return nil, err
+	}
+	family := strings.ToLower(strings.TrimSpace(req.GetFamily()))
+	if family != "ipv4" && family != "ipv6" {
+		return nil, status.Error(codes.InvalidArgument, "family must be ipv4 or ipv6")
+	}
+	resp := &pluginv1.AssignIPResponse{}
+	err = st.write(func() error {
+		h, err := st.operableLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		resp.Ip = st.allocIPLocked(family)
+		h.IPs = append(h.IPs, resp.Ip)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ReleaseIP succeeds for addresses already released; the primary address
+// cannot be released.
+func (a *automationServer) ReleaseIP(ctx context.Context, req *pluginv1.ReleaseIPRequest) (*pluginv1.OperationResult, error) {
+	return a.op(ctx, "ReleaseIP", func(st *store) error {
+		h, err := st.hostLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		ip := strings.TrimSpace(req.GetIp())
+		i := slices.Index(h.IPs, ip)
+		if i == 0 {
+			return status.Error(codes.FailedPrecondition, "the primary address cannot be released")
+		}
+		if i > 0 {
+			h.IPs = slices.Delete(h.IPs, i, i+1)
+			delete(h.PTR, ip)
+		}
+		return nil
+	})
+}
+
+func (a *automationServer) EnterRescue(ctx context.Context, req *pluginv1.EnterRescueRequest) (*pluginv1.EnterRescueResponse, error) {
+	st, err := a.enter(ctx, "EnterRescue")
+	if err != nil {
+		return nil, err
+	}
+	resp := &pluginv1.EnterRescueResponse{Username: "root", Password: req.GetPassword()}
+	if resp.Password == "" {
+		resp.Password = randomSecret()
+	}
+	err = st.write(func() error {
+		h, err := st.operableLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		h.Rescue, h.State = true, stateRunning
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (a *automationServer) ExitRescue(ctx context.Context, req *pluginv1.ExitRescueRequest) (*pluginv1.OperationResult, error) {
+	return a.op(ctx, "ExitRescue", func(st *store) error {
+		h, err := st.operableLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		if h.Rescue {
+			h.Rescue, h.State = false, stateRunning
+		}
+		return nil
+	})
+}
+
+func (a *automationServer) MountISO(ctx context.Context, req *pluginv1.MountISORequest) (*pluginv1.OperationResult, error) {
+	return a.op(ctx, "MountISO", func(st *store) error {
+		h, err := st.operableLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(req.GetIso()) == "" {
+			return status.Error(codes.InvalidArgument, "iso required")
+		}
+		h.ISO = strings.TrimSpace(req.GetIso())
+		return nil
+	})
+}
+
+func (a *automationServer) UnmountISO(ctx context.Context, req *pluginv1.UnmountISORequest) (*pluginv1.OperationResult, error) {
+	return a.op(ctx, "UnmountISO", func(st *store) error {
+		h, err := st.operableLocked(req.GetInstanceId())
+		if err != nil {
+			return err
+		}
+		h.ISO = ""
+		return nil
+	})
+}
@@ -0,0 +1,230 @@
+// Command automation_simulator is a stateful stand-in for a VPS panel. It
+// keeps its own catalog and hosts, implements every AutomationService RPC,
+// and can add latency and inject failures, so provisioning, resize, expiry
+// and add-on flows can be exercised locally without a real provider.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"xiaoheiplay/pkg/pluginsdk"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+const pluginID = "simulator"
+
+const (
+	expireNone = "none"
+	expireStop = "stop"
+	expireLock = "lock"
+
+	failureModeError  = "error"
+	failureModeResult = "result"
+)
+
+type config struct {
+	// ProvisionSeconds and RebuildSeconds are how long creates and
+	// rebuilds/restores stay in progress.
+	ProvisionSeconds int `json:"provision_seconds"`
+	RebuildSeconds   int `json:"rebuild_seconds"`
+	// ProvisionFailRate is the share of creates that end in the failed state.
+	ProvisionFailRate float64 `json:"provision_fail_rate"`
+	// PackageCapacity caps hosts per package; 0 is unlimited.
+	PackageCapacity int `json:"package_capacity"`
+	// ExpireAction is applied to hosts past expire_at: none, stop or lock.
+	ExpireAction string `json:"expire_action"`
+	LatencyMS    int    `json:"latency_ms"`
+	JitterMS     int    `json:"jitter_ms"`
+	// FailureRate is the share of RPCs that fail; FailRPCs always fail
+	// (comma separated RPC names, "*" for all).
+	FailureRate float64 `json:"failure_rate"`
+	FailRPCs    string  `json:"fail_rpcs"`
+	// FailureMode is error (gRPC Unavailable) or result (OperationResult
+	// with ok=false where the RPC returns one).
+	FailureMode string `json:"failure_mode"`
+	// StateFile keeps the model across restarts; empty keeps it in memory.
+	StateFile string `json:"state_file"`
+	// DemoHosts seeds running hosts named demo-N with password "demo".
+	DemoHosts int `json:"demo_hosts"`
+}
+
+func parseConfig(raw string) (config, error) {
+	cfg := config{ProvisionSeconds: 15, RebuildSeconds: 10, ExpireAction: expireStop, FailureMode: failureModeError}
+	if strings.TrimSpace(raw) != "" {
+		if err := json.Unmarshal([]byte(raw), &cfg); err != nil {
+			return config{}, err
+		}
+	}
+	cfg.ExpireAction = strings.ToLower(strings.TrimSpace(cfg.ExpireAction))
+	cfg.FailureMode = strings.ToLower(strings.TrimSpace(cfg.FailureMode))
+	return cfg, nil
+}
+
+func (c config) validate() string {
+	switch {
+	case c.ProvisionSeconds < 0 || c.RebuildSeconds < 0:
+		return "provision_seconds/rebuild_seconds must not be negative"
+	case c.ProvisionFailRate < 0 || c.ProvisionFailRate > 1 || c.FailureRate < 0 || c.FailureRate > 1:
+		return "rates must be between 0 and 1"
+	case c.LatencyMS < 0 || c.JitterMS < 0:
+		return "latency_ms/jitter_ms must not be negative"
+	case c.PackageCapacity < 0 || c.DemoHosts < 0 || c.DemoHosts > 100:
+		return "package_capacity/demo_hosts out of range"
+	}
+	switch c.ExpireAction {
+	case "", expireNone, expireStop, expireLock:
+	default:
+		return "expire_action must be none, stop or lock"
+	}
+	switch c.FailureMode {
+	case "", failureModeError, failureModeResult:
+	default:
+		return "failure_mode must be error or result"
+	}
+	return ""
+}
+
+type coreServer struct {
+	pluginv1.UnimplementedCoreServiceServer
+
+	mu       sync.RWMutex
+	cfg      config
+	store    *store
+	instance string
+}
+
+func (s *coreServer) GetManifest(context.Context, *pluginv1.Empty) (*pluginv1.Manifest, error) {
+	return &pluginv1.Manifest{
+		PluginId:    pluginID,
+		Name:        "Automation Simulator",
+		Version:     "0.1.0",
+		Description: "Stateful simulated VPS panel for local development. Not for production.",
+		Automation: &pluginv1.AutomationCapability{
+			Features: []pluginv1.AutomationFeature{
+				pluginv1.AutomationFeature_AUTOMATION_FEATURE_CATALOG_SYNC,
+				pluginv1.AutomationFeature_AUTOMATION_FEATURE_LIFECYCLE,
+				pluginv1.AutomationFeature_AUTOMATION_FEATURE_PORT_MAPPING,
+				pluginv1.AutomationFeature_AUTOMATION_FEATURE_BACKUP,
+				pluginv1.AutomationFeature_AUTOMATION_FEATURE_SNAPSHOT,
+				pluginv1.AutomationFeature_AUTOMATION_FEATURE_FIREWALL,
+				pluginv1.AutomationFeature_AUTOMATION_FEATURE_CLOUD_INIT,
+				pluginv1.AutomationFeature_AUTOMATION_FEATURE_REVERSE_DNS,
+				pluginv1.AutomationFeature_AUTOMATION_FEATURE_EXTRA_IP,
+				pluginv1.AutomationFeature_AUTOMATION_FEATURE_RESCUE,
+			},
+			NotSupportedReasons: map[int32]string{},
+		},
+	}, nil
+}
+
+func (s *coreServer) GetConfigSchema(context.Context, *pluginv1.Empty) (*pluginv1.ConfigSchema, error) {
+	return &pluginv1.ConfigSchema{
+		JsonSchema: `{
+  "title": "Automation Simulator (Test Only)",
+  "type": "object",
+  "properties": {
+    "provision_seconds": { "type": "integer", "title": "开通耗时（秒）", "default": 15, "minimum": 0 },
+    "rebuild_seconds": { "type": "integer", "title": "重装/恢复耗时（秒）", "default": 10, "minimum": 0 },
+    "provision_fail_rate": { "type": "number", "title": "开通失败率", "default": 0, "minimum": 0, "maximum": 1 },
+    "package_capacity": { "type": "integer", "title": "每个套餐库存", "description": "0 表示不限", "default": 0, "minimum": 0 },
+    "expire_action": { "type": "string", "title": "到期动作", "enum": ["none", "stop", "lock"], "default": "stop" },
+    "latency_ms": { "type": "integer", "title": "固定延迟（毫秒）", "default": 0, "minimum": 0 },
+    "jitter_ms": { "type": "integer", "title": "随机抖动（毫秒）", "default": 0, "minimum": 0 },
+    "failure_rate": { "type": "number", "title": "随机失败率", "default": 0, "minimum": 0, "maximum": 1 },
+    "fail_rpcs": { "type": "string", "title": "必定失败的 RPC", "description": "逗号分隔，例如 CreateInstance,Start；* 表示全部" },
+    "failure_mode": { "type": "string", "title": "失败方式", "enum": ["error", "result"], "default": "error" },
+    "state_file": { "type": "string", "title": "状态文件", "description": "留空则仅保存在内存中，重启后重置" },
+    "demo_hosts": { "type": "integer", "title": "预置实例数", "default": 0, "minimum": 0, "maximum": 100 }
+  }
+}`,
+		UiSchema: `{}`,
+	}, nil
+}
+
+func (s *coreServer) ValidateConfig(_ context.Context, req *pluginv1.ValidateConfigRequest) (*pluginv1.ValidateConfigResponse, error) {
+	cfg, err := parseConfig(req.GetConfigJson())
+	if err != nil {
+		return &pluginv1.ValidateConfigResponse{Ok: false, Error: "invalid json"}, nil
+	}
+	if msg := cfg.validate(); msg != "" {
+		return &pluginv1.ValidateConfigResponse{Ok: false, Error: msg}, nil
+	}
+	return &pluginv1.ValidateConfigResponse{Ok: true}, nil
+}
+
+func (s *coreServer) Init(_ context.Context, req *pluginv1.InitRequest) (*pluginv1.InitResponse, error) {
+	cfg, err := parseConfig(req.GetConfigJson())
+	if err != nil {
+		return &pluginv1.InitResponse{Ok: false, Error: "invalid config"}, nil
+	}
+	if msg := cfg.validate(); msg != "" {
+		return &pluginv1.InitResponse{Ok: false, Error: msg}, nil
+	}
+	st, err := newStore(cfg)
+	if err != nil {
+		return &pluginv1.InitResponse{Ok: false, Error: err.Error()}, nil
+	}
+	s.mu.Lock()
+	s.cfg, s.store, s.instance = cfg, st, req.GetInstanceId()
+	s.mu.Unlock()
+	return &pluginv1.InitResponse{Ok: true}, nil
+}
+
+// ReloadConfig applies timing and failure settings to the running model.
+// A changed state_file only takes effect on the next Init.
+func (s *coreServer) ReloadConfig(_ context.Context, req *pluginv1.ReloadConfigRequest) (*pluginv1.ReloadConfigResponse, error) {
+	cfg, err := parseConfig(req.GetConfigJson())
+	if err != nil {
+		return &pluginv1.ReloadConfigResponse{Ok: false, Error: "invalid config"}, nil
+	}
+	if msg := cfg.validate(); msg != "" {
+		return &pluginv1.ReloadConfigResponse{Ok: false, Error: msg}, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cfg = cfg
+	if s.store != nil {
+		s.store.setConfig(cfg)
+	}
+	return &pluginv1.ReloadConfigResponse{Ok: true}, nil
+}
+
+func (s *coreServer) Health(context.Context, *pluginv1.HealthCheckRequest) (*pluginv1.HealthCheckResponse, error) {
+	msg := "ok"
+	if s.current() == nil {
+		msg = "not initialized"
+	}
+	return &pluginv1.HealthCheckResponse{
+		Status:     pluginv1.HealthStatus_HEALTH_STATUS_OK,
+		Message:    msg,
+		UnixMillis: time.Now().UnixMilli(),
+	}, nil
+}
+
+func (s *coreServer) current() *store {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.store
+}
+
+func (s *coreServer) config() config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cfg
+}
+
+func main() {
+	pluginsdk.Serve(newPlugins())
+}
+
+func newPlugins() map[string]pluginsdk.Plugin {
+	core := &coreServer{}
+	return map[string]pluginsdk.Plugin{
+		pluginsdk.PluginKeyCore:       &pluginsdk.CoreGRPCPlugin{Impl: core},
+		pluginsdk.PluginKeyAutomation: &pluginsdk.AutomationGRPCPlugin{Impl: &automationServer{core: core}},
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	plugintest "xiaoheiplay/pkg/pluginsdk/testing"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+func TestPlugin_ConfigFlow(t *testing.T) {
+	h := plugintest.New(t, newPlugins())
+	h.Start("../../../plugins/automation/simulator", `{"demo_hosts":2}`)
+	h.Health()
+	auto := h.Automation()
+	ctx := context.Background()
+
+	hosts, err := auto.ListInstancesSimple(ctx, &pluginv1.ListInstancesSimpleRequest{})
+	if err != nil || len(hosts.GetItems()) != 2 {
+		t.Fatalf("list demo hosts: %v %v", hosts, err)
+	}
+
+	if err := h.ReloadConfig(`{"demo_hosts":2,"fail_rpcs":"ListAreas"}`); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if _, err := auto.ListAreas(ctx, &pluginv1.Empty{}); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected injected Unavailable after reload, got %v", err)
+	}
+	// Reload keeps the running model.
+	if hosts, err := auto.ListInstancesSimple(ctx, &pluginv1.ListInstancesSimpleRequest{}); err != nil || len(hosts.GetItems()) != 2 {
+		t.Fatalf("hosts after reload: %v %v", hosts, err)
+	}
+	if err := h.ReloadConfig(`{"expire_action":"delete"}`); err == nil || err.Error() != "expire_action must be none, stop or lock" {
+		t.Fatalf("expected reload to reject expire_action, got %v", err)
+	}
+}
+
+func TestPlugin_ValidateConfig(t *testing.T) {
+	h := plugintest.New(t, newPlugins())
+	cases := map[string]string{
+		`{"failure_rate":1.5}`:      "rates must be between 0 and 1",
+		`{"demo_hosts":101}`:        "package_capacity/demo_hosts out of range",
+		`{"failure_mode":"panic"}`:  "failure_mode must be error or result",
+		`{"provision_seconds":-1}`:  "provision_seconds/rebuild_seconds must not be negative",
+		`{"provision_seconds":"x"}`: "invalid json",
+	}
+	for cfg, want := range cases {
+		if err := h.ValidateConfig(cfg); err == nil || err.Error() != want {
+			t.Fatalf("validate %s: want %q, got %v", cfg, want, err)
+		}
+	}
+	if err := h.ValidateConfig(`{}`); err != nil {
+		t.Fatalf("validate defaults: %v", err)
+	}
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"xiaoheiplay/pkg/pluginsdk/conformance"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+func TestConformance(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and launches the plugin binary")
+	}
+	// The simulator has no upstream to fail; failure injection is covered
+	// by TestFailureInjection.
+	var skip []string
+	for _, rpc := range []string{
+		"ListAreas", "ListLines", "ListPackages", "ListImages", "GetInstance", "ListInstancesSimple",
+		"GetPanelURL", "GetVNCURL", "GetMonitor", "ListPortMappings", "ListBackups", "ListSnapshots",
+		"ListFirewallRules", "ListReverseDNS",
+	} {
+		skip = append(skip, "upstream_error/"+rpc)
+	}
+	conformance.Run(t, conformance.Suite{
+		PluginDir: conformance.Build(t, ".", filepath.Join("..", "..", "..", "plugins", "automation", "simulator", "manifest.json")),
+		Config:    func(string) string { return `{"demo_hosts":1}` },
+		Fixture:   conformance.Fixture{InstanceID: firstHostID, InstanceName: "demo-1", PanelPassword: "demo", LineID: 11},
+		Skip:      skip,
+	})
+}
+
+type simClock struct{ now time.Time }
+
+func (c *simClock) advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestServer(t *testing.T, cfg string) (*coreServer, *automationServer, *simClock) {
+	t.Helper()
+	core := &coreServer{}
+	resp, err := core.Init(context.Background(), &pluginv1.InitRequest{InstanceId: "test", ConfigJson: cfg})
+	if err != nil || !resp.GetOk() {
+		t.Fatalf("init: %v %s", err, resp.GetError())
+	}
+	clock := &simClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	core.current().now = func() time.Time { return clock.now }
+	return core, &automationServer{core: core}, clock
+}
+
+func mustState(t *testing.T, a *automationServer, id int64, want int32) {
+	t.Helper()
+	resp, err := a.GetInstance(context.Background(), &pluginv1.GetInstanceRequest{InstanceId: id})
+	if err != nil {
+		t.Fatalf("get instance: %v", err)
+	}
+	if got := resp.GetInstance().GetState(); got != want {
+		t.Fatalf("instance %d state = %d, want %d", id, got, want)
+	}
+}
+
+func TestProvisionResizeAndExpiry(t *testing.T) {
+	ctx := context.Background()
+	_, a, clock := newTestServer(t, `{"provision_seconds":30,"expire_action":"stop","package_capacity":1}`)
+
+	created, err := a.CreateInstance(ctx, &pluginv1.CreateInstanceRequest{
+		LineId: 11, Os: "Debian 12", Name: "vm1", Password: "pw", ExpireAtUnix: clock.now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	id := created.GetInstanceId()
+	mustState(t, a, id, stateCreating)
+	if _, err := a.Start(ctx, &pluginv1.StartRequest{InstanceId: id}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("start while provisioning: want FailedPrecondition, got %v", err)
+	}
+	if _, err := a.CreateInstance(ctx, &pluginv1.CreateInstanceRequest{LineId: 11}); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("create beyond capacity: want ResourceExhausted, got %v", err)
+	}
+	pkgs, _ := a.ListPackages(ctx, &pluginv1.ListPackagesRequest{LineId: 11})
+	if pkgs.GetItems()[0].CapacityRemaining == nil || pkgs.GetItems()[0].GetCapacityRemaining() != 0 {
+		t.Fatalf("expected sold out package, got %+v", pkgs.GetItems()[0])
+	}
+
+	clock.advance(31 * time.Second)
+	mustState(t, a, id, stateRunning)
+
+	disk := int32(10)
+	if _, err := a.ElasticUpdate(ctx, &pluginv1.ElasticUpdateRequest{InstanceId: id, DiskGb: &disk}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("shrink disk: want InvalidArgument, got %v", err)
+	}
+	cpu, disk := int32(4), int32(60)
+	if res, err := a.ElasticUpdate(ctx, &pluginv1.ElasticUpdateRequest{InstanceId: id, Cpu: &cpu, DiskGb: &disk}); err != nil || !res.GetOk() {
+		t.Fatalf("resize: %v %v", res, err)
+	}
+	inst, _ := a.GetInstance(ctx, &pluginv1.GetInstanceRequest{InstanceId: id})
+	if inst.GetInstance().GetCpu() != 4 || inst.GetInstance().GetDiskGb() != 60 || inst.GetInstance().GetMemoryGb() != 1 {
+		t.Fatalf("unexpected specs after resize: %+v", inst.GetInstance())
+	}
+
+	clock.advance(2 * time.Hour)
+	mustState(t, a, id, stateStopped)
+	if res, err := a.Renew(ctx, &pluginv1.RenewRequest{InstanceId: id, NextDueAtUnix: clock.now.Add(720 * time.Hour).Unix()}); err != nil || !res.GetOk() {
+		t.Fatalf("renew: %v %v", res, err)
+	}
+	mustState(t, a, id, stateRunning)
+
+	for i := 0; i < 2; i++ {
+		if res, err := a.Destroy(ctx, &pluginv1.DestroyRequest{InstanceId: id}); err != nil || !res.GetOk() {
+			t.Fatalf("destroy %d: %v %v", i, res, err)
+		}
+	}
+	if _, err := a.GetInstance(ctx, &pluginv1.GetInstanceRequest{InstanceId: id}); status.Code(err) != codes.NotFound {
+		t.Fatalf("destroyed instance: want NotFound, got %v", err)
+	}
+}
+
+func TestAddOnsAndRestore(t *testing.T) {
+	ctx := context.Background()
+	_, a, clock := newTestServer(t, `{"demo_hosts":1,"rebuild_seconds":5}`)
+	id := int64(firstHostID)
+
+	ip, err := a.AssignIP(ctx, &pluginv1.AssignIPRequest{InstanceId: id, Family: "ipv6"})
+	if err != nil || ip.GetIp() == "" {
+		t.Fatalf("assign ip: %v %v", ip, err)
+	}
+	if res, err := a.SetReverseDNS(ctx, &pluginv1.SetReverseDNSRequest{InstanceId: id, Ip: ip.GetIp(), Ptr: "vm.example.com"}); err != nil || !res.GetOk() {
+		t.Fatalf("set ptr: %v %v", res, err)
+	}
+	records, _ := a.ListReverseDNS(ctx, &pluginv1.ListReverseDNSRequest{InstanceId: id})
+	if len(records.GetItems()) != 2 || records.GetItems()[1].GetPtr() != "vm.example.com" {
+		t.Fatalf("unexpected ptr records %+v", records.GetItems())
+	}
+	inst, _ := a.GetInstance(ctx, &pluginv1.GetInstanceRequest{InstanceId: id})
+	if _, err := a.ReleaseIP(ctx, &pluginv1.ReleaseIPRequest{InstanceId: id, Ip: inst.GetInstance().GetRemoteIp()}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("release primary ip: want FailedPrecondition, got %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if res, err := a.AddPortMapping(ctx, &pluginv1.AddPortMappingRequest{InstanceId: id, Dport: 22}); err != nil || !res.GetOk() {
+			t.Fatalf("add port mapping: %v %v", res, err)
+		}
+	}
+	mappings, _ := a.ListPortMappings(ctx, &pluginv1.ListPortMappingsRequest{InstanceId: id})
+	if len(mappings.GetItems()) != 2 || mappings.GetItems()[0].GetSport() == mappings.GetItems()[1].GetSport() {
+		t.Fatalf("expected two distinct public ports, got %+v", mappings.GetItems())
+	}
+	if _, err := a.AddPortMapping(ctx, &pluginv1.AddPortMappingRequest{InstanceId: id, Sport: mappings.GetItems()[0].GetSport(), Dport: 80}); status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("duplicate public port: want AlreadyExists, got %v", err)
+	}
+
+	if _, err := a.CreateSnapshot(ctx, &pluginv1.CreateSnapshotRequest{InstanceId: id}); err != nil {
+		t.Fatalf("create snapshot: %v", err)
+	}
+	snaps, _ := a.ListSnapshots(ctx, &pluginv1.ListSnapshotsRequest{InstanceId: id})
+	if len(snaps.GetItems()) != 1 {
+		t.Fatalf("expected one snapshot, got %d", len(snaps.GetItems()))
+	}
+	if _, err := a.RestoreSnapshot(ctx, &pluginv1.RestoreSnapshotRequest{InstanceId: id, SnapshotId: snaps.GetItems()[0].GetId()}); err != nil {
+		t.Fatalf("restore snapshot: %v", err)
+	}
+	mustState(t, a, id, stateRebuilding)
+	clock.advance(2 * time.Second)
+	if inst, _ := a.GetInstance(ctx, &pluginv1.GetInstanceRequest{InstanceId: id}); inst.GetInstance().TaskProgress == nil || inst.GetInstance().GetTaskProgress() != 40 {
+		t.Fatalf("expected rebuild progress 40, got %+v", inst.GetInstance())
+	}
+	clock.advance(3 * time.Second)
+	mustState(t, a, id, stateRunning)
+	if inst, _ := a.GetInstance(ctx, &pluginv1.GetInstanceRequest{InstanceId: id}); inst.GetInstance().TaskProgress != nil {
+		t.Fatalf("expected no task progress once running, got %d", inst.GetInstance().GetTaskProgress())
+	}
+
+	if _, err := a.AddFirewallRule(ctx, &pluginv1.AddFirewallRuleRequest{InstanceId: id, Direction: "sideways", Protocol: "tcp", Method: "allowed"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("bad firewall direction: want InvalidArgument, got %v", err)
+	}
+	rescue, err := a.EnterRescue(ctx, &pluginv1.EnterRescueRequest{InstanceId: id, Password: "rescue-pw"})
+	if err != nil || rescue.GetPassword() != "rescue-pw" {
+		t.Fatalf("enter rescue: %v %v", rescue, err)
+	}
+}
+
+func TestFailureInjection(t *testing.T) {
+	ctx := context.Background()
+	core, a, _ := newTestServer(t, `{"demo_hosts":1,"fail_rpcs":"Start","failure_mode":"result"}`)
+	id := int64(firstHostID)
+
+	res, err := a.Start(ctx, &pluginv1.StartRequest{InstanceId: id})
+	if err != nil || res.GetOk() || res.GetErrorCode() != "simulated_failure" {
+		t.Fatalf("result mode: want ok=false, got %v %v", res, err)
+	}
+	if res, err := a.Shutdown(ctx, &pluginv1.ShutdownRequest{InstanceId: id}); err != nil || !res.GetOk() {
+		t.Fatalf("unlisted rpc should pass: %v %v", res, err)
+	}
+
+	if r, _ := core.ReloadConfig(ctx, &pluginv1.ReloadConfigRequest{ConfigJson: `{"fail_rpcs":"*"}`}); !r.GetOk() {
+		t.Fatalf("reload: %s", r.GetError())
+	}
+	if _, err := a.Start(ctx, &pluginv1.StartRequest{InstanceId: id}); status.Code(err) != codes.Unavailable {
+		t.Fatalf("error mode: want Unavailable, got %v", err)
+	}
+	if _, err := a.ListAreas(ctx, &pluginv1.Empty{}); status.Code(err) != codes.Unavailable {
+		t.Fatalf("wildcard: want Unavailable, got %v", err)
+	}
+}
+
+func TestStateFileSurvivesRestart(t *testing.T) {
+	ctx := context.Background()
+	cfg := `{"provision_seconds":0,"state_file":"` + filepath.ToSlash(filepath.Join(t.TempDir(), "sim.json")) + `"}`
+	_, a, _ := newTestServer(t, cfg)
+	created, err := a.CreateInstance(ctx, &pluginv1.CreateInstanceRequest{PackageId: 2102, ImageId: 4, Name: "keep-me"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	_, restarted, _ := newTestServer(t, cfg)
+	list, err := restarted.ListInstancesSimple(ctx, &pluginv1.ListInstancesSimpleRequest{SearchTag: "keep"})
+	if err != nil || len(list.GetItems()) != 1 || list.GetItems()[0].GetId() != created.GetInstanceId() {
+		t.Fatalf("expected persisted instance, got %v %v", list.GetItems(), err)
+	}
+	mustState(t, restarted, created.GetInstanceId(), stateRunning)
+}
@@ -0,0 +1,434 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Instance states as reported to the host (see vps.MapAutomationState).
+const (
+	stateCreating      int32 = 0
+	stateRunning       int32 = 2
+	stateStopped       int32 = 3
+	stateRebuilding    int32 = 4
+	stateRebuildFailed int32 = 5
+	stateLocked        int32 = 10
+	stateCreateFailed  int32 = 11
+)
+
+const firstHostID = 1001
+
+type area struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	State int32  `json:"state"`
+}
+
+type line struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	AreaID int64  `json:"area_id"`
+	State  int32  `json:"state"`
+}
+
+type product struct {
+	ID            int64  `json:"id"`
+	LineID        int64  `json:"line_id"`
+	Name          string `json:"name"`
+	CPU           int32  `json:"cpu"`
+	MemoryGB      int32  `json:"memory_gb"`
+	DiskGB        int32  `json:"disk_gb"`
+	BandwidthMbps int32  `json:"bandwidth_mbps"`
+	PortNum       int32  `json:"port_num"`
+	MonthlyPrice  int64  `json:"monthly_price"`
+}
+
+type image struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type portMapping struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Sport string `json:"sport"`
+	Dport int64  `json:"dport"`
+}
+
+// artifact is a backup or snapshot.
+type artifact struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	CreatedAt int64  `json:"created_at"`
+	State     int32  `json:"state"`
+}
+
+type firewallRule struct {
+	ID        int64  `json:"id"`
+	Direction string `json:"direction"`
+	Protocol  string `json:"protocol"`
+	Method    string `json:"method"`
+	Port      string `json:"port"`
+	IP        string `json:"ip"`
+	Priority  int32  `json:"priority"`
+}
+
+type host struct {
+	ID            int64  `json:"id"`
+	Name          string `json:"name"`
+	LineID        int64  `json:"line_id"`
+	PackageID     int64  `json:"package_id"`
+	ImageID       int64  `json:"image_id"`
+	State         int32  `json:"state"`
+	LockedFrom    int32  `json:"locked_from"`
+	CPU           int32  `json:"cpu"`
+	MemoryGB      int32  `json:"memory_gb"`
+	DiskGB        int32  `json:"disk_gb"`
+	BandwidthMbps int32  `json:"bandwidth_mbps"`
+	PortNum       int32  `json:"port_num"`
+	OSPassword    string `json:"os_password"`
+	PanelPassword string `json:"panel_password"`
+	VNCPassword   string `json:"vnc_password"`
+	ExpireAt      int64  `json:"expire_at"`
+	// Expired is set once the expire action ran, so renewing can undo it.
+	Expired bool `json:"expired"`
+	// BusySince and BusyUntil bound a running create, rebuild or restore;
+	// BusyFail makes it finish in a failed state.
+	BusySince time.Time `json:"busy_since,omitzero"`
+	BusyUntil time.Time `json:"busy_until,omitzero"`
+	BusyFail  bool      `json:"busy_fail"`
+
+	IPs          []string          `json:"ips"`
+	PTR          map[string]string `json:"ptr"`
+	SSHKeys      []string          `json:"ssh_keys"`
+	UserData     string            `json:"user_data"`
+	Startup      string            `json:"startup_script"`
+	NoPassword   bool              `json:"disable_password_login"`
+	Rescue       bool              `json:"rescue"`
+	ISO          string            `json:"iso"`
+	PortMappings []portMapping     `json:"port_mappings"`
+	Backups      []artifact        `json:"backups"`
+	Snapshots    []artifact        `json:"snapshots"`
+	Firewall     []firewallRule    `json:"firewall"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+// model is everything the simulator persists.
+type model struct {
+	Areas    []area    `json:"areas"`
+	Lines    []line    `json:"lines"`
+	Packages []product `json:"packages"`
+	Images   []image   `json:"images"`
+	Hosts    []*host   `json:"hosts"`
+	NextID   int64     `json:"next_id"`
+	NextIPv4 int       `json:"next_ipv4"`
+	NextIPv6 int       `json:"next_ipv6"`
+	Updated  time.Time `json:"updated_at"`
+}
+
+// store is the simulated provider. Every method takes the lock, advances
+// timed state transitions, and persists the model when a state file is set.
+type store struct {
+	mu   sync.Mutex
+	cfg  config
+	now  func() time.Time
+	m    model
+	path string
+}
+
+func newStore(cfg config) (*store, error) {
+	s := &store{cfg: cfg, now: time.Now, path: strings.TrimSpace(cfg.StateFile)}
+	if s.path != "" {
+		raw, err := os.ReadFile(s.path)
+		switch {
+		case err == nil:
+			if err := json.Unmarshal(raw, &s.m); err != nil {
+				return nil, fmt.Errorf("state_file: %w", err)
+			}
+			return s, nil
+		case !errors.Is(err, os.ErrNotExist):
+			return nil, fmt.Errorf("state_file: %w", err)
+		}
+	}
+	s.m = seedModel()
+	for i := 0; i < cfg.DemoHosts; i++ {
+		h := s.newHost(s.m.Packages[0], s.m.Images[0].ID, fmt.Sprintf("demo-%d", i+1))
+		h.State = stateRunning
+		h.PanelPassword = "demo"
+		h.OSPassword = "demo"
+		h.VNCPassword = "demo"
+		s.m.Hosts = append(s.m.Hosts, h)
+	}
+	return s, s.saveLocked()
+}
+
+func seedModel() model {
+	m := model{
+		Areas: []area{
+			{ID: 1, Name: "Hong Kong", State: 1},
+			{ID: 2, Name: "Los Angeles", State: 1},
+		},
+		Lines: []line{
+			{ID: 11, Name: "HK BGP", AreaID: 1, State: 1},
+			{ID: 12, Name: "HK CN2", AreaID: 1, State: 1},
+			{ID: 21, Name: "LA Standard", AreaID: 2, State: 1},
+			{ID: 22, Name: "LA Legacy", AreaID: 2, State: 0},
+		},
+		Images: []image{
+			{ID: 1, Name: "Debian 12", Type: "linux"},
+			{ID: 2, Name: "Ubuntu 24.04", Type: "linux"},
+			{ID: 3, Name: "Rocky Linux 9", Type: "linux"},
+			{ID: 4, Name: "Windows Server 2022", Type: "windows"},
+		},
+		NextID: firstHostID,
+	}
+	tiers := []product{
+		{Name: "S1", CPU: 1, MemoryGB: 1, DiskGB: 20, BandwidthMbps: 10, PortNum: 5, MonthlyPrice: 1500},
+		{Name: "S2", CPU: 2, MemoryGB: 2, DiskGB: 40, BandwidthMbps: 20, PortNum: 10, MonthlyPrice: 3000},
+		{Name: "S4", CPU: 4, MemoryGB: 8, DiskGB: 80, BandwidthMbps: 50, PortNum: 20, MonthlyPrice: 8800},
+	}
+	for _, ln := range m.Lines {
+		for i, tier := range tiers {
+			tier.ID = ln.ID*100 + int64(i+1)
+			tier.LineID = ln.ID
+			m.Packages = append(m.Packages, tier)
+		}
+	}
+	return m
+}
+
+func (s *store) saveLocked() error {
+	if s.path == "" {
+		return nil
+	}
+	s.m.Updated = s.now()
+	raw, err := json.MarshalIndent(&s.m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// read runs fn against the model. Timed transitions that fired are saved.
+func (s *store) read(fn func() error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.advanceLocked() {
+		if err := s.saveLocked(); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+	}
+	return fn()
+}
+
+// write runs fn and saves the model when fn succeeds.
+func (s *store) write(fn func() error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.advanceLocked()
+	if err := fn(); err != nil {
+		return err
+	}
+	if err := s.saveLocked(); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
+// advanceLocked finishes pending creates and rebuilds and applies the
+// expire action to hosts past their due date.
+func (s *store) advanceLocked() bool {
+	now := s.now()
+	changed := false
+	for _, h := range s.m.Hosts {
+		if !h.BusyUntil.IsZero() && !now.Before(h.BusyUntil) {
+			switch {
+			case h.State == stateCreating && h.BusyFail:
+				h.State = stateCreateFailed
+			case h.State == stateRebuilding && h.BusyFail:
+				h.State = stateRebuildFailed
+			default:
+				h.State = stateRunning
+			}
+			h.BusySince, h.BusyUntil, h.BusyFail = time.Time{}, time.Time{}, false
+			changed = true
+		}
+		if h.ExpireAt > 0 && !h.Expired && now.Unix() >= h.ExpireAt && (h.State == stateRunning || h.State == stateStopped) {
+			switch s.cfg.ExpireAction {
+			case expireStop:
+				h.State = stateStopped
+			case expireLock:
+				h.LockedFrom, h.State = h.State, stateLocked
+			default:
+				continue
+			}
+			h.Expired = true
+			changed = true
+		}
+	}
+	return changed
+}
+
+// taskProgressLocked is how far the running create or rebuild of h is, kept
+// below 100 until advanceLocked finishes it.
+func (s *store) taskProgressLocked(h *host) int32 {
+	total := h.BusyUntil.Sub(h.BusySince)
+	if total <= 0 {
+		return 0
+	}
+	p := int32(s.now().Sub(h.BusySince) * 100 / total)
+	return max(0, min(p, 99))
+}
+
+func (s *store) hostLocked(id int64) (*host, error) {
+	if id <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "instance_id required")
+	}
+	for _, h := range s.m.Hosts {
+		if h.ID == id {
+			return h, nil
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "instance %d not found", id)
+}
+
+// operableLocked returns the host when it can take a power or data action.
+func (s *store) operableLocked(id int64) (*host, error) {
+	h, err := s.hostLocked(id)
+	if err != nil {
+		return nil, err
+	}
+	switch h.State {
+	case stateCreating, stateRebuilding:
+		return nil, status.Errorf(codes.FailedPrecondition, "instance %d is busy", id)
+	case stateCreateFailed:
+		return nil, status.Errorf(codes.FailedPrecondition, "instance %d failed to provision", id)
+	case stateLocked:
+		return nil, status.Errorf(codes.FailedPrecondition, "instance %d is locked", id)
+	}
+	return h, nil
+}
+
+func (s *store) lineLocked(id int64) (line, bool) {
+	for _, ln := range s.m.Lines {
+		if ln.ID == id {
+			return ln, true
+		}
+	}
+	return line{}, false
+}
+
+func (s *store) packageLocked(id int64) (product, bool) {
+	for _, p := range s.m.Packages {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return product{}, false
+}
+
+func (s *store) imageLocked(id int64, name string) (image, bool) {
+	for _, img := range s.m.Images {
+		if (id > 0 && img.ID == id) || (id <= 0 && name != "" && strings.EqualFold(img.Name, name)) {
+			return img, true
+		}
+	}
+	return image{}, false
+}
+
+// remainingLocked is the capacity left on a package, or -1 when unlimited.
+func (s *store) remainingLocked(packageID int64) int {
+	if s.cfg.PackageCapacity <= 0 {
+		return -1
+	}
+	used := 0
+	for _, h := range s.m.Hosts {
+		if h.PackageID == packageID {
+			used++
+		}
+	}
+	return max(s.cfg.PackageCapacity-used, 0)
+}
+
+func (s *store) nextIDLocked() int64 {
+	if s.m.NextID < firstHostID {
+		s.m.NextID = firstHostID
+	}
+	id := s.m.NextID
+	s.m.NextID++
+	return id
+}
+
+// Addresses come from the documentation ranges so they never route.
+func (s *store) allocIPLocked(family string) string {
+	if family == "ipv6" {
+		s.m.NextIPv6++
+		return fmt.Sprintf("2001:db8::%x", s.m.NextIPv6)
+	}
+	n := s.m.NextIPv4
+	s.m.NextIPv4++
+	ranges := []string{"192.0.2", "198.51.100", "203.0.113"}
+	return fmt.Sprintf("%s.%d", ranges[(n/253)%len(ranges)], n%253+1)
+}
+
+func (s *store) newHost(p product, imageID int64, name string) *host {
+	h := &host{
+		ID:            s.nextIDLocked(),
+		Name:          name,
+		LineID:        p.LineID,
+		PackageID:     p.ID,
+		ImageID:       imageID,
+		CPU:           p.CPU,
+		MemoryGB:      p.MemoryGB,
+		DiskGB:        p.DiskGB,
+		BandwidthMbps: p.BandwidthMbps,
+		PortNum:       p.PortNum,
+		PanelPassword: randomSecret(),
+		VNCPassword:   randomSecret()[:8],
+		PTR:           map[string]string{},
+		CreatedAt:     s.now(),
+	}
+	h.IPs = []string{s.allocIPLocked("ipv4")}
+	if h.Name == "" {
+		h.Name = fmt.Sprintf("sim-%d", h.ID)
+	}
+	return h
+}
+
+func (s *store) removeHostLocked(id int64) bool {
+	before := len(s.m.Hosts)
+	s.m.Hosts = slices.DeleteFunc(s.m.Hosts, func(h *host) bool { return h.ID == id })
+	return len(s.m.Hosts) != before
+}
+
+func randomSecret() string {
+	var b [12]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+func (s *store) setConfig(cfg config) {
+	s.mu.Lock()
+	s.cfg = cfg
+	s.mu.Unlock()
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"xiaoheiplay/pkg/pluginsdk/conformance"
+)
+
+func TestConformance(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and launches the plugin binary")
+	}
+	vps := "/api/v1/open/vps/7"
+	conformance.Run(t, conformance.Suite{
+		PluginDir: conformance.Build(t, ".", filepath.Join("..", "..", "..", "plugins", "automation", "xiaohei_proxy", "manifest.json")),
+		Config: func(url string) string {
+			return `{"base_url":"` + url + `","open_akid":"ak","open_key":"sk","admin_api_key":"adm","goods_type_id":1,"timeout_sec":5}`
+		},
+		Routes: []conformance.Route{
+			{Method: http.MethodGet, Path: "/admin/api/v1/regions", Body: `{"items":[{"id":1,"name":"HK","active":true}]}`},
+			{Method: http.MethodGet, Path: "/admin/api/v1/plan-groups", Body: `{"items":[{"id":10,"region_id":1,"name":"HK-CN2","line_id":10,"active":true,"visible":true}]}`},
+			{Method: http.MethodGet, Path: "/admin/api/v1/packages", Body: `{"items":[{"id":100,"plan_group_id":10,"name":"S1","cores":1,"memory_gb":1,"monthly_price":"9.90","active":true,"visible":true}]}`},
+			{Method: http.MethodGet, Path: "/admin/api/v1/system-images", Body: `{"items":[{"id":5,"name":"Debian 12","type":"linux","enabled":true}]}`},
+			{Method: http.MethodPost, Path: "/admin/api/v1/vps/7/*", Body: `{}`},
+			{Method: http.MethodGet, Path: "/api/v1/open/vps", Body: `{"items":[{"id":7,"name":"vm-7","status":"running","access_info":{"remote_ip":"203.0.113.7"}}]}`},
+			{Method: http.MethodGet, Path: vps, Body: `{"id":7,"name":"vm-7","status":"running","cpu":1,"memory_gb":1}`},
+			{Method: http.MethodGet, Path: vps + "/panel", Status: http.StatusFound, Header: map[string]string{"Location": "https://panel.example/7"}},
+			{Method: http.MethodGet, Path: vps + "/vnc", Status: http.StatusFound, Header: map[string]string{"Location": "https://vnc.example/7"}},
+			{Method: http.MethodGet, Path: vps + "/monitor", Body: `{"cpu":3,"memory":40}`},
+			{Method: http.MethodGet, Path: vps + "/ports", Body: `{"data":[]}`},
+			{Method: http.MethodGet, Path: vps + "/backups", Body: `{"data":[]}`},
+			{Method: http.MethodGet, Path: vps + "/snapshots", Body: `{"data":[]}`},
+			{Method: http.MethodGet, Path: vps + "/firewall", Body: `{"data":[]}`},
+			{Method: http.MethodPost, Path: vps + "/*", Body: `{}`},
+		},
+		Fixture: conformance.Fixture{InstanceID: 7, InstanceName: "vm-7", LineID: 10},
+	})
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	plugintest "xiaoheiplay/pkg/pluginsdk/testing"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+func TestPlugin_ListAreasAndReload(t *testing.T) {
+	var gotKey, gotGoodsType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get("X-API-Key")
+		gotGoodsType = r.URL.Query().Get("goods_type_id")
+		if r.URL.Path != "/admin/api/v1/regions" {
+			t.Errorf("unexpected request path: %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"items":[{"id":1,"name":"HK","active":true},{"id":2,"name":"US","active":false}]}`))
+	}))
+	defer srv.Close()
+
+	base := `"base_url":"` + srv.URL + `","open_akid":"ak","open_key":"sk","goods_type_id":7,"timeout_sec":2`
+	h := plugintest.New(t, newPlugins())
+	h.Start("../../../plugins/automation/xiaohei_proxy", `{`+base+`,"admin_api_key":"adm1"}`)
+	h.Health()
+	auto := h.Automation()
+	ctx := context.Background()
+
+	resp, err := auto.ListAreas(ctx, &pluginv1.Empty{})
+	if err != nil || len(resp.GetItems()) != 2 {
+		t.Fatalf("list areas: %v %v", resp, err)
+	}
+	if resp.GetItems()[0].GetState() != 1 || resp.GetItems()[1].GetState() != 0 {
+		t.Fatalf("unexpected area states: %v", resp.GetItems())
+	}
+	if gotKey != "adm1" || gotGoodsType != "7" {
+		t.Fatalf("request carried key=%q goods_type_id=%q", gotKey, gotGoodsType)
+	}
+
+	if err := h.ReloadConfig(`{` + base + `}`); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if _, err := auto.ListAreas(ctx, &pluginv1.Empty{}); err == nil || !strings.Contains(err.Error(), "admin_api_key required") {
+		t.Fatalf("expected admin_api_key required after reload, got %v", err)
+	}
+	if err := h.ReloadConfig(`{"base_url":"` + srv.URL + `"}`); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if _, err := auto.ListAreas(ctx, &pluginv1.Empty{}); err == nil || !strings.Contains(err.Error(), "missing config") {
+		t.Fatalf("expected missing config, got %v", err)
+	}
+}
+
+func TestPlugin_ValidateConfig(t *testing.T) {
+	h := plugintest.New(t, newPlugins())
+	cases := map[string]string{
+		`{"base_url":"https://xh.example","open_akid":"ak","open_key":"sk"}`:                                   "missing required config: goods_type_id",
+		`{"base_url":"https://xh.example","open_akid":"ak","goods_type_id":7}`:                                 "base_url/open_akid/open_key required",
+		`{"base_url":"https://xh.example","open_akid":"ak","open_key":"sk","goods_type_id":7,"price_rate":-1}`: "price_rate out of range",
+	}
+	for cfg, want := range cases {
+		if err := h.ValidateConfig(cfg); err == nil || err.Error() != want {
+			t.Fatalf("validate %s: want %q, got %v", cfg, want, err)
+		}
+	}
+	// open_secret is still accepted in place of open_key.
+	if err := h.ValidateConfig(`{"base_url":"https://xh.example","open_akid":"ak","open_secret":"sk","goods_type_id":7}`); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+}
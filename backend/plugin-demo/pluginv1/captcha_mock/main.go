@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"xiaoheiplay/pkg/pluginsdk"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+const defaultAcceptToken = "mock-pass"
+
+type config struct {
+	AcceptToken string `json:"accept_token"`
+	FailScenes  string `json:"fail_scenes"`
+}
+
+func (c config) acceptToken() string {
+	if v := strings.TrimSpace(c.AcceptToken); v != "" {
+		return v
+	}
+	return defaultAcceptToken
+}
+
+type coreServer struct {
+	pluginv1.UnimplementedCoreServiceServer
+	cfg      config
+	instance string
+}
+
+func (s *coreServer) GetManifest(ctx context.Context, _ *pluginv1.Empty) (*pluginv1.Manifest, error) {
+	_ = ctx
+	return &pluginv1.Manifest{
+		PluginId:    "mock_captcha",
+		Name:        "Mock Captcha",
+		Version:     "1.0.0",
+		Description: "Offline captcha provider for local and e2e testing. Any request carrying the configured token passes.",
+		Captcha:     &pluginv1.CaptchaCapability{Widget: "mock"},
+	}, nil
+}
+
+func (s *coreServer) GetConfigSchema(ctx context.Context, _ *pluginv1.Empty) (*pluginv1.ConfigSchema, error) {
+	_ = ctx
+	return &pluginv1.ConfigSchema{
+		JsonSchema: `{
+  "title": "Mock Captcha",
+  "type": "object",
+  "properties": {
+    "accept_token": { "type": "string", "title": "Accept Token", "description": "Token that passes verification", "default": "mock-pass" },
+    "fail_scenes": { "type": "string", "title": "Fail Scenes", "description": "Comma separated scenes that always fail, for testing rejections" }
+  }
+}`,
+		UiSchema: `{}`,
+	}, nil
+}
+
+func (s *coreServer) ValidateConfig(ctx context.Context, req *pluginv1.ValidateConfigRequest) (*pluginv1.ValidateConfigResponse, error) {
+	_ = ctx
+	var cfg config
+	if strings.TrimSpace(req.GetConfigJson()) == "" {
+		return &pluginv1.ValidateConfigResponse{Ok: true}, nil
+	}
+	if err := json.Unmarshal([]byte(req.GetConfigJson()), &cfg); err != nil {
+		return &pluginv1.ValidateConfigResponse{Ok: false, Error: "invalid json"}, nil
+	}
+	return &pluginv1.ValidateConfigResponse{Ok: true}, nil
+}
+
+func (s *coreServer) Init(ctx context.Context, req *pluginv1.InitRequest) (*pluginv1.InitResponse, error) {
+	_ = ctx
+	var cfg config
+	if strings.TrimSpace(req.GetConfigJson()) != "" {
+		if err := json.Unmarshal([]byte(req.GetConfigJson()), &cfg); err != nil {
+			return &pluginv1.InitResponse{Ok: false, Error: "invalid config"}, nil
+		}
+	}
+	s.cfg = cfg
+	s.instance = req.GetInstanceId()
+	return &pluginv1.InitResponse{Ok: true}, nil
+}
+
+func (s *coreServer) ReloadConfig(ctx context.Context, req *pluginv1.ReloadConfigRequest) (*pluginv1.ReloadConfigResponse, error) {
+	_ = ctx
+	var cfg config
+	if strings.TrimSpace(req.GetConfigJson()) != "" {
+		if err := json.Unmarshal([]byte(req.GetConfigJson()), &cfg); err != nil {
+			return &pluginv1.ReloadConfigResponse{Ok: false, Error: "invalid config"}, nil
+		}
+	}
+	s.cfg = cfg
+	return &pluginv1.ReloadConfigResponse{Ok: true}, nil
+}
+
+func (s *coreServer) Health(ctx context.Context, _ *pluginv1.HealthCheckRequest) (*pluginv1.HealthCheckResponse, error) {
+	_ = ctx
+	return &pluginv1.HealthCheckResponse{
+		Status:     pluginv1.HealthStatus_HEALTH_STATUS_OK,
+		Message:    "ok",
+		UnixMillis: time.Now().UnixMilli(),
+	}, nil
+}
+
+type captchaServer struct {
+	pluginv1.UnimplementedCaptchaServiceServer
+	core *coreServer
+	seq  atomic.Int64
+}
+
+func (s *captchaServer) Init(ctx context.Context, req *pluginv1.CaptchaInitRequest) (*pluginv1.CaptchaInitResponse, error) {
+	_ = ctx
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "missing request")
+	}
+	return &pluginv1.CaptchaInitResponse{
+		Ok:     true,
+		Widget: "mock",
+		Params: map[string]string{
+			"challenge": fmt.Sprintf("mock-%d", s.seq.Add(1)),
+			"hint":      "输入 " + s.core.cfg.acceptToken() + " 通过验证",
+		},
+	}, nil
+}
+
+func (s *captchaServer) Verify(ctx context.Context, req *pluginv1.CaptchaVerifyRequest) (*pluginv1.CaptchaVerifyResponse, error) {
+	_ = ctx
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "missing request")
+	}
+	scene := strings.TrimSpace(req.GetScene())
+	for _, sc := range strings.Split(s.core.cfg.FailScenes, ",") {
+		if sc = strings.TrimSpace(sc); sc != "" && sc == scene {
+			return &pluginv1.CaptchaVerifyResponse{Ok: false, Error: "scene rejected by mock", ErrorCode: "rejected"}, nil
+		}
+	}
+	if strings.TrimSpace(req.GetToken()) != s.core.cfg.acceptToken() {
+		return &pluginv1.CaptchaVerifyResponse{Ok: false, Error: "invalid token", ErrorCode: "invalid_token"}, nil
+	}
+	return &pluginv1.CaptchaVerifyResponse{Ok: true}, nil
+}
+
+func main() {
+	pluginsdk.Serve(newPlugins())
+}
+
+func newPlugins() map[string]pluginsdk.Plugin {
+	core := &coreServer{}
+	captcha := &captchaServer{core: core}
+	return map[string]pluginsdk.Plugin{
+		pluginsdk.PluginKeyCore:    &pluginsdk.CoreGRPCPlugin{Impl: core},
+		pluginsdk.PluginKeyCaptcha: &pluginsdk.CaptchaGRPCPlugin{Impl: captcha},
+	}
+}
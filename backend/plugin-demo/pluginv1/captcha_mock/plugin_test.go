@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	plugintest "xiaoheiplay/pkg/pluginsdk/testing"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+func TestPlugin_InitAndVerify(t *testing.T) {
+	h := plugintest.New(t, newPlugins())
+	h.Start("../../../plugins/captcha/mock_captcha", `{}`)
+	captcha := h.Captcha()
+	ctx := context.Background()
+
+	challenge, err := captcha.Init(ctx, &pluginv1.CaptchaInitRequest{Scene: "login"})
+	if err != nil || !challenge.GetOk() || challenge.GetWidget() != "mock" || challenge.GetParams()["challenge"] != "mock-1" {
+		t.Fatalf("unexpected challenge %v %v", challenge, err)
+	}
+	if resp, err := captcha.Verify(ctx, &pluginv1.CaptchaVerifyRequest{Scene: "login", Token: defaultAcceptToken}); err != nil || !resp.GetOk() {
+		t.Fatalf("default token should pass: %v %v", resp, err)
+	}
+	if resp, err := captcha.Verify(ctx, &pluginv1.CaptchaVerifyRequest{Scene: "login", Token: "wrong"}); err != nil || resp.GetErrorCode() != "invalid_token" {
+		t.Fatalf("expected invalid_token, got %v %v", resp, err)
+	}
+
+	if err := h.ReloadConfig(`{"accept_token":"letmein","fail_scenes":"register"}`); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if resp, err := captcha.Verify(ctx, &pluginv1.CaptchaVerifyRequest{Scene: "login", Token: "letmein"}); err != nil || !resp.GetOk() {
+		t.Fatalf("reloaded token should pass: %v %v", resp, err)
+	}
+	if resp, err := captcha.Verify(ctx, &pluginv1.CaptchaVerifyRequest{Scene: "register", Token: "letmein"}); err != nil || resp.GetErrorCode() != "rejected" {
+		t.Fatalf("expected failing scene to be rejected, got %v %v", resp, err)
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	plugintest "xiaoheiplay/pkg/pluginsdk/testing"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+const testConfig = `{"access_key_id":"ak","access_key_secret":"sk","scene_id":1000}`
+
+func TestPlugin_ConfigFlow(t *testing.T) {
+	h := plugintest.New(t, newPlugins())
+	if err := h.ValidateConfig(`{"access_key_id":"ak","access_key_secret":"sk"}`); err == nil {
+		t.Fatalf("expected missing scene_id to be rejected")
+	}
+	if err := h.ValidateConfig(`{"access_key_id":"ak","access_key_secret":"sk","scene_id":0}`); err == nil {
+		t.Fatalf("expected zero scene_id to be rejected")
+	}
+	if err := h.ValidateConfig(testConfig); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if _, err := h.KYC().QueryResult(context.Background(), &pluginv1.KycQueryRequest{Token: "x"}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition before init, got %v", err)
+	}
+	h.Start("../../../plugins/kyc/aliyun_kyc", testConfig)
+	if err := h.ReloadConfig(`{"scene_id":1000}`); err == nil {
+		t.Fatalf("expected reload without keys to fail")
+	}
+}
+
+func TestPlugin_RequestValidation(t *testing.T) {
+	h := plugintest.New(t, newPlugins())
+	h.Start("../../../plugins/kyc/aliyun_kyc", testConfig)
+	kyc := h.KYC()
+	ctx := context.Background()
+	if _, err := kyc.Start(ctx, &pluginv1.KycStartRequest{UserId: "1", Params: map[string]string{"name": "张三"}}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument without id_number, got %v", err)
+	}
+	if _, err := kyc.QueryResult(ctx, &pluginv1.KycQueryRequest{Token: " "}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument without token, got %v", err)
+	}
+}
@@ -109,13 +109,16 @@ func (s *coreServer) Init(ctx context.Context, req *pluginv1.InitRequest) (*plug
 }
 
 func (s *coreServer) ReloadConfig(ctx context.Context, req *pluginv1.ReloadConfigRequest) (*pluginv1.ReloadConfigResponse, error) {
-	_, err := s.Init(ctx, &pluginv1.InitRequest{
+	ir, err := s.Init(ctx, &pluginv1.InitRequest{
 		InstanceId: s.instance,
 		ConfigJson: req.GetConfigJson(),
 	})
 	if err != nil {
 		return &pluginv1.ReloadConfigResponse{Ok: false, Error: err.Error()}, nil
 	}
+	if ir != nil && !ir.Ok {
+		return &pluginv1.ReloadConfigResponse{Ok: false, Error: ir.Error}, nil
+	}
 	return &pluginv1.ReloadConfigResponse{Ok: true}, nil
 }
 
@@ -513,10 +516,14 @@ func newToken() string {
 }
 
 func main() {
+	pluginsdk.Serve(newPlugins())
+}
+
+func newPlugins() map[string]pluginsdk.Plugin {
 	core := &coreServer{}
 	kyc := &kycServer{core: core}
-	pluginsdk.Serve(map[string]pluginsdk.Plugin{
+	return map[string]pluginsdk.Plugin{
 		pluginsdk.PluginKeyCore: &pluginsdk.CoreGRPCPlugin{Impl: core},
 		pluginsdk.PluginKeyKYC:  &pluginsdk.KycGRPCPlugin{Impl: kyc},
-	})
+	}
 }
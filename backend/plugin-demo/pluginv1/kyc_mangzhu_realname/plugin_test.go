@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	plugintest "xiaoheiplay/pkg/pluginsdk/testing"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+func newUpstream(t *testing.T, replies map[string]string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("key") != "k" {
+			http.Error(w, "bad key", http.StatusForbidden)
+			return
+		}
+		reply, ok := replies[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(reply))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestPlugin_ThreeFactor(t *testing.T) {
+	srv := newUpstream(t, map[string]string{"/index/sm3_api": `{"code":200,"msg":"一致"}`})
+	h := plugintest.New(t, newPlugins())
+	h.Start("../../../plugins/kyc/mangzhu_realname", `{"base_url":"`+srv.URL+`","api_key":"k"}`)
+	kyc := h.KYC()
+	ctx := context.Background()
+
+	params := map[string]string{"name": "张三", "id_number": "110101199001011234"}
+	if _, err := kyc.Start(ctx, &pluginv1.KycStartRequest{Params: params}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument without mobile, got %v", err)
+	}
+	params["mobile"] = "13800000000"
+	start, err := kyc.Start(ctx, &pluginv1.KycStartRequest{Params: params})
+	if err != nil || start.GetNextStep() != "query_result" {
+		t.Fatalf("start: %v %v", start, err)
+	}
+	res, err := kyc.QueryResult(ctx, &pluginv1.KycQueryRequest{Token: start.GetToken()})
+	if err != nil || res.GetStatus() != "VERIFIED" {
+		t.Fatalf("query: %v %v", res, err)
+	}
+}
+
+func TestPlugin_FaceFlow(t *testing.T) {
+	srv := newUpstream(t, map[string]string{
+		"/index/wx_sm": `{"code":200,"token":"face-1","url":"https://face.example/1"}`,
+		"/index/wx_cx": `{"code":200,"sm":3,"msg":"人脸不匹配"}`,
+	})
+	h := plugintest.New(t, newPlugins())
+	h.Start("../../../plugins/kyc/mangzhu_realname", `{"base_url":"`+srv.URL+`","api_key":"k","auth_mode":"face","face_provider":"wechat"}`)
+	kyc := h.KYC()
+	ctx := context.Background()
+
+	params := map[string]string{"name": "张三", "id_number": "110101199001011234"}
+	if _, err := kyc.Start(ctx, &pluginv1.KycStartRequest{Params: params}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument without callback_url, got %v", err)
+	}
+	params["callback_url"] = "https://shop.example/kyc/done"
+	start, err := kyc.Start(ctx, &pluginv1.KycStartRequest{Params: params})
+	if err != nil || start.GetToken() != "face-1" || start.GetUrl() != "https://face.example/1" || start.GetNextStep() != "redirect" {
+		t.Fatalf("start: %v %v", start, err)
+	}
+	res, err := kyc.QueryResult(ctx, &pluginv1.KycQueryRequest{Token: "face-1"})
+	if err != nil || res.GetStatus() != "FAILED" || res.GetReason() != "人脸不匹配" {
+		t.Fatalf("query: %v %v", res, err)
+	}
+}
+
+func TestPlugin_ConfigFlow(t *testing.T) {
+	h := plugintest.New(t, newPlugins())
+	if err := h.ValidateConfig(`{"auth_mode":"face"}`); err == nil {
+		t.Fatalf("expected missing api_key to be rejected")
+	}
+	if err := h.Init(`{}`); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	if _, err := h.KYC().Start(context.Background(), &pluginv1.KycStartRequest{Params: map[string]string{"name": "a", "id_number": "b"}}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition without api_key, got %v", err)
+	}
+	if err := h.ReloadConfig(`{`); err == nil {
+		t.Fatalf("expected reload with malformed json to fail")
+	}
+}
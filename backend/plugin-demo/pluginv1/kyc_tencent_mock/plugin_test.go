@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	plugintest "xiaoheiplay/pkg/pluginsdk/testing"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+const testConfig = `{"secret_id":"id","secret_key":"key","rule_id":"1"}`
+
+func TestPlugin_ConfigFlow(t *testing.T) {
+	h := plugintest.New(t, newPlugins())
+	if err := h.ValidateConfig(`{"secret_id":"id","secret_key":"key"}`); err == nil {
+		t.Fatalf("expected missing rule_id to be rejected")
+	}
+	if err := h.ValidateConfig(testConfig); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	h.Start("../../../plugins/kyc/tencent_kyc", testConfig)
+	if err := h.ReloadConfig(`{`); err == nil {
+		t.Fatalf("expected reload with malformed json to fail")
+	}
+}
+
+func TestPlugin_RequestValidation(t *testing.T) {
+	h := plugintest.New(t, newPlugins())
+	h.Start("../../../plugins/kyc/tencent_kyc", testConfig)
+	kyc := h.KYC()
+	ctx := context.Background()
+	if _, err := kyc.Start(ctx, &pluginv1.KycStartRequest{Params: map[string]string{"id_number": "110101199001011234"}}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument without name, got %v", err)
+	}
+	if _, err := kyc.QueryResult(ctx, &pluginv1.KycQueryRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument without token, got %v", err)
+	}
+}
+
+func TestParseTencentDetectInfo(t *testing.T) {
+	cases := []struct {
+		raw, status, reason string
+	}{
+		{``, "PENDING", ""},
+		{`{"Text":{}}`, "PENDING", ""},
+		{`{"Text":{"ErrCode":0}}`, "VERIFIED", ""},
+		{`{"Text":{"ErrCode":-1101,"ErrMsg":"比对失败"}}`, "FAILED", "比对失败"},
+		{`{"Text":{"ErrCode":17}}`, "FAILED", "ErrCode=17"},
+	}
+	for _, c := range cases {
+		if st, reason := parseTencentDetectInfo(c.raw); st != c.status || reason != c.reason {
+			t.Fatalf("parseTencentDetectInfo(%q) = %q %q, want %q %q", c.raw, st, reason, c.status, c.reason)
+		}
+	}
+}
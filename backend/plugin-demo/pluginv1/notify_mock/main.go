@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"xiaoheiplay/pkg/pluginsdk"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+type config struct {
+	DefaultTarget string `json:"default_target"`
+	FailTargets   string `json:"fail_targets"`
+}
+
+type coreServer struct {
+	pluginv1.UnimplementedCoreServiceServer
+	cfg      config
+	instance string
+	// host receives a log line per delivered message; nil when the host
+	// broker is unavailable.
+	host pluginv1.HostServiceClient
+}
+
+func (s *coreServer) GetManifest(ctx context.Context, _ *pluginv1.Empty) (*pluginv1.Manifest, error) {
+	_ = ctx
+	return &pluginv1.Manifest{
+		PluginId:    "mock_notify",
+		Name:        "Mock Notify",
+		Version:     "1.0.0",
+		Description: "Mock notification channel for local testing. Messages are written to the plugin log instead of being delivered.",
+		Notify:      &pluginv1.NotifyCapability{Send: true, Markdown: true},
+	}, nil
+}
+
+func (s *coreServer) GetConfigSchema(ctx context.Context, _ *pluginv1.Empty) (*pluginv1.ConfigSchema, error) {
+	_ = ctx
+	return &pluginv1.ConfigSchema{
+		JsonSchema: `{
+  "title": "Mock Notify",
+  "type": "object",
+  "properties": {
+    "default_target": { "type": "string", "title": "Default Target", "description": "Used when the host sends without a target" },
+    "fail_targets": { "type": "string", "title": "Fail Targets", "description": "Comma separated targets that are rejected, for testing failures" }
+  }
+}`,
+		UiSchema: `{}`,
+	}, nil
+}
+
+func (s *coreServer) ValidateConfig(ctx context.Context, req *pluginv1.ValidateConfigRequest) (*pluginv1.ValidateConfigResponse, error) {
+	_ = ctx
+	var cfg config
+	if strings.TrimSpace(req.GetConfigJson()) == "" {
+		return &pluginv1.ValidateConfigResponse{Ok: true}, nil
+	}
+	if err := json.Unmarshal([]byte(req.GetConfigJson()), &cfg); err != nil {
+		return &pluginv1.ValidateConfigResponse{Ok: false, Error: "invalid json"}, nil
+	}
+	return &pluginv1.ValidateConfigResponse{Ok: true}, nil
+}
+
+func (s *coreServer) Init(ctx context.Context, req *pluginv1.InitRequest) (*pluginv1.InitResponse, error) {
+	_ = ctx
+	var cfg config
+	if strings.TrimSpace(req.GetConfigJson()) != "" {
+		if err := json.Unmarshal([]byte(req.GetConfigJson()), &cfg); err != nil {
+			return &pluginv1.InitResponse{Ok: false, Error: "invalid config"}, nil
+		}
+	}
+	s.cfg = cfg
+	s.instance = req.GetInstanceId()
+	if id := req.GetHostBrokerId(); id != 0 && s.host == nil {
+		if host, err := pluginsdk.DialHost(id); err == nil {
+			s.host = host
+		}
+	}
+	return &pluginv1.InitResponse{Ok: true}, nil
+}
+
+func (s *coreServer) ReloadConfig(ctx context.Context, req *pluginv1.ReloadConfigRequest) (*pluginv1.ReloadConfigResponse, error) {
+	_ = ctx
+	var cfg config
+	if strings.TrimSpace(req.GetConfigJson()) != "" {
+		if err := json.Unmarshal([]byte(req.GetConfigJson()), &cfg); err != nil {
+			return &pluginv1.ReloadConfigResponse{Ok: false, Error: "invalid config"}, nil
+		}
+	}
+	s.cfg = cfg
+	return &pluginv1.ReloadConfigResponse{Ok: true}, nil
+}
+
+func (s *coreServer) Health(ctx context.Context, _ *pluginv1.HealthCheckRequest) (*pluginv1.HealthCheckResponse, error) {
+	_ = ctx
+	return &pluginv1.HealthCheckResponse{
+		Status:     pluginv1.HealthStatus_HEALTH_STATUS_OK,
+		Message:    "ok",
+		UnixMillis: time.Now().UnixMilli(),
+	}, nil
+}
+
+type notifyServer struct {
+	pluginv1.UnimplementedNotifyServiceServer
+	core *coreServer
+	seq  atomic.Int64
+}
+
+func (s *notifyServer) Send(ctx context.Context, req *pluginv1.SendNotifyRequest) (*pluginv1.SendNotifyResponse, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "missing request")
+	}
+	if strings.TrimSpace(req.GetTitle()) == "" && strings.TrimSpace(req.GetBody()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "title or body required")
+	}
+	target := strings.TrimSpace(req.GetTarget())
+	if target == "" {
+		target = strings.TrimSpace(s.core.cfg.DefaultTarget)
+	}
+	if target == "" {
+		return &pluginv1.SendNotifyResponse{Ok: false, Error: "target required", ErrorCode: "missing_target"}, nil
+	}
+	for _, t := range strings.Split(s.core.cfg.FailTargets, ",") {
+		if t = strings.TrimSpace(t); t != "" && t == target {
+			return &pluginv1.SendNotifyResponse{Ok: false, Error: "target rejected by mock", ErrorCode: "rejected"}, nil
+		}
+	}
+	id := fmt.Sprintf("mock-%d", s.seq.Add(1))
+	if s.core.host != nil {
+		_, _ = s.core.host.Log(ctx, &pluginv1.LogRequest{
+			Level:   pluginv1.LogLevel_LOG_LEVEL_INFO,
+			Message: "mock notify delivered",
+			Fields: map[string]string{
+				"message_id": id,
+				"target":     target,
+				"event":      req.GetEvent(),
+				"title":      req.GetTitle(),
+			},
+		})
+	}
+	return &pluginv1.SendNotifyResponse{Ok: true, MessageId: id}, nil
+}
+
+func main() {
+	pluginsdk.Serve(newPlugins())
+}
+
+func newPlugins() map[string]pluginsdk.Plugin {
+	core := &coreServer{}
+	notify := &notifyServer{core: core}
+	return map[string]pluginsdk.Plugin{
+		pluginsdk.PluginKeyCore:   &pluginsdk.CoreGRPCPlugin{Impl: core},
+		pluginsdk.PluginKeyNotify: &pluginsdk.NotifyGRPCPlugin{Impl: notify},
+	}
+}
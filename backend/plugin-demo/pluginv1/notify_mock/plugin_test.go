@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	plugintest "xiaoheiplay/pkg/pluginsdk/testing"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+func TestPlugin_SendAndReload(t *testing.T) {
+	h := plugintest.New(t, newPlugins())
+	h.Start("../../../plugins/notify/mock_notify", `{"default_target":"ops","fail_targets":"blocked"}`)
+	h.Health()
+	notify := h.Notify()
+	ctx := context.Background()
+
+	resp, err := notify.Send(ctx, &pluginv1.SendNotifyRequest{Title: "order paid"})
+	if err != nil || !resp.GetOk() || resp.GetMessageId() != "mock-1" {
+		t.Fatalf("send to default target: %v %v", resp, err)
+	}
+	resp, err = notify.Send(ctx, &pluginv1.SendNotifyRequest{Target: "blocked", Body: "x"})
+	if err != nil || resp.GetOk() || resp.GetErrorCode() != "rejected" {
+		t.Fatalf("expected rejected target, got %v %v", resp, err)
+	}
+	if _, err := notify.Send(ctx, &pluginv1.SendNotifyRequest{Target: "ops"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for empty message, got %v", err)
+	}
+
+	if err := h.ReloadConfig(`{}`); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	resp, err = notify.Send(ctx, &pluginv1.SendNotifyRequest{Title: "order paid"})
+	if err != nil || resp.GetOk() || resp.GetErrorCode() != "missing_target" {
+		t.Fatalf("expected missing target after reload, got %v %v", resp, err)
+	}
+}
+
+func TestPlugin_InvalidConfig(t *testing.T) {
+	h := plugintest.New(t, newPlugins())
+	if err := h.ValidateConfig(`{"default_target":1}`); err == nil {
+		t.Fatalf("expected a non-string target to be rejected")
+	}
+	if err := h.Init(`{`); err == nil {
+		t.Fatalf("expected init to reject malformed json")
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	plugintest "xiaoheiplay/pkg/pluginsdk/testing"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+func testConfig(t *testing.T) string {
+	t.Helper()
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey: %v", err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey: %v", err)
+	}
+	b, _ := json.Marshal(map[string]string{
+		"app_id":            "2026000000000000",
+		"app_private_key":   base64.StdEncoding.EncodeToString(x509.MarshalPKCS1PrivateKey(priv)),
+		"alipay_public_key": base64.StdEncoding.EncodeToString(pubDER),
+	})
+	return string(b)
+}
+
+func TestPlugin_CreatePagePayment(t *testing.T) {
+	h := plugintest.New(t, newPlugins())
+	cfg := testConfig(t)
+	if err := h.ValidateConfig(cfg); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	h.Start("../../../plugins/payment/alipay_open", cfg)
+	pay := h.Payment()
+	ctx := context.Background()
+
+	resp, err := pay.CreatePayment(ctx, &pluginv1.CreatePaymentRpcRequest{
+		Method:  "alipay_page",
+		Request: &pluginv1.PaymentCreateRequest{OrderNo: "ORDER-1", Amount: 1234, Subject: "VPS", NotifyUrl: "https://shop.example/notify"},
+	})
+	if err != nil || !resp.GetOk() || resp.GetExtra()["amount_yuan"] != "12.34" {
+		t.Fatalf("create: %v %v", resp, err)
+	}
+	u, err := url.Parse(resp.GetPayUrl())
+	if err != nil || u.Query().Get("sign") == "" || !strings.Contains(u.Query().Get("biz_content"), `"out_trade_no":"ORDER-1"`) {
+		t.Fatalf("unexpected pay url %q", resp.GetPayUrl())
+	}
+	if _, err := pay.CreatePayment(ctx, &pluginv1.CreatePaymentRpcRequest{Method: "alipay_app", Request: &pluginv1.PaymentCreateRequest{OrderNo: "ORDER-1"}}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for unknown method, got %v", err)
+	}
+}
+
+func TestPlugin_ConfigFlow(t *testing.T) {
+	h := plugintest.New(t, newPlugins())
+	if err := h.ValidateConfig(`{"app_id":"1","app_private_key":"x"}`); err == nil {
+		t.Fatalf("expected missing alipay_public_key to be rejected")
+	}
+	if err := h.ValidateConfig(`{"app_id":"1","app_private_key":"x","alipay_public_key":"not-a-key"}`); err == nil || !strings.Contains(err.Error(), "invalid alipay_public_key") {
+		t.Fatalf("expected invalid public key, got %v", err)
+	}
+	if err := h.Init(``); err == nil || err.Error() != "missing config" {
+		t.Fatalf("expected init without config to fail, got %v", err)
+	}
+	if err := h.Init(testConfig(t)); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	if err := h.ReloadConfig(`{"app_id":"1"}`); err == nil {
+		t.Fatalf("expected reload with incomplete keys to fail")
+	}
+}
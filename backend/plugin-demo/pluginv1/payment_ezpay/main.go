@@ -18,6 +18,7 @@ import (
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	"xiaoheiplay/pkg/pluginsdk"
 	pluginv1 "xiaoheiplay/plugin/v1"
@@ -53,6 +54,9 @@ type coreServer struct {
 	cfg       config
 	instance  string
 	updatedAt time.Time
+	// host keeps payment links across plugin restarts; nil when the host
+	// does not serve HostService.
+	host pluginv1.HostServiceClient
 }
 
 func (s *coreServer) GetManifest(ctx context.Context, _ *pluginv1.Empty) (*pluginv1.Manifest, error) {
@@ -118,6 +122,11 @@ func (s *coreServer) Init(ctx context.Context, req *pluginv1.InitRequest) (*plug
 	}
 	s.instance = req.GetInstanceId()
 	s.updatedAt = time.Now()
+	if id := req.GetHostBrokerId(); id != 0 && s.host == nil {
+		if host, err := pluginsdk.DialHost(id); err == nil {
+			s.host = host
+		}
+	}
 	return &pluginv1.InitResponse{Ok: true}, nil
 }
 
@@ -350,7 +359,7 @@ func (p *payServer) createEZPay(method string, in *pluginv1.PaymentCreateRequest
 		if err != nil {
 			return nil, err
 		}
-		p.setCachedPayment(outTradeNo, resp)
+		p.setCachedPayment(outTradeNo, resp, cfg)
 		return resp, nil
 	}
 	formHTML := buildAutoSubmitFormHTML(submitURL, params)
@@ -364,7 +373,7 @@ func (p *payServer) createEZPay(method string, in *pluginv1.PaymentCreateRequest
 			"out_trade_no": outTradeNo,
 		},
 	}
-	p.setCachedPayment(outTradeNo, resp)
+	p.setCachedPayment(outTradeNo, resp, cfg)
 	return resp, nil
 }
 
@@ -556,37 +565,34 @@ func cloneCreateResponse(in *pluginv1.PaymentCreateResponse) *pluginv1.PaymentCr
 	return out
 }
 
+func paymentLinkTTL(cfg config) time.Duration {
+	expire := time.Duration(cfg.OrderExpireMinutes) * time.Minute
+	if expire <= 0 {
+		expire = 5 * time.Minute
+	}
+	return expire
+}
+
 func (p *payServer) getCachedPayment(cacheKey string, cfg config) *pluginv1.PaymentCreateResponse {
 	cacheKey = strings.TrimSpace(cacheKey)
 	if cacheKey == "" {
 		return nil
 	}
 	p.mu.RLock()
-	defer p.mu.RUnlock()
-	if p.linkCache == nil {
-		return nil
-	}
 	item, ok := p.linkCache[cacheKey]
-	if !ok || item.resp == nil {
-		return nil
-	}
-	expire := time.Duration(cfg.OrderExpireMinutes) * time.Minute
-	if expire <= 0 {
-		expire = 5 * time.Minute
-	}
-	if time.Since(item.createdAt) > expire {
-		return nil
+	p.mu.RUnlock()
+	if ok && item.resp != nil && time.Since(item.createdAt) <= paymentLinkTTL(cfg) {
+		return cloneCreateResponse(item.resp)
 	}
-	return cloneCreateResponse(item.resp)
+	return p.loadStoredPayment(cacheKey)
 }
 
-func (p *payServer) setCachedPayment(cacheKey string, resp *pluginv1.PaymentCreateResponse) {
+func (p *payServer) setCachedPayment(cacheKey string, resp *pluginv1.PaymentCreateResponse, cfg config) {
 	cacheKey = strings.TrimSpace(cacheKey)
 	if cacheKey == "" || resp == nil {
 		return
 	}
 	p.mu.Lock()
-	defer p.mu.Unlock()
 	if p.linkCache == nil {
 		p.linkCache = map[string]cachedPayment{}
 	}
@@ -594,6 +600,40 @@ func (p *payServer) setCachedPayment(cacheKey string, resp *pluginv1.PaymentCrea
 		resp:      cloneCreateResponse(resp),
 		createdAt: time.Now(),
 	}
+	p.mu.Unlock()
+	p.storePayment(cacheKey, resp, paymentLinkTTL(cfg))
+}
+
+// loadStoredPayment reads a payment link kept in the host KV store, so a
+// restarted plugin hands out the same link instead of creating a new trade.
+func (p *payServer) loadStoredPayment(cacheKey string) *pluginv1.PaymentCreateResponse {
+	if p.core == nil || p.core.host == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	got, err := p.core.host.KVGet(ctx, &pluginv1.KVGetRequest{Key: "payment_link:" + cacheKey})
+	if err != nil || !got.GetFound() {
+		return nil
+	}
+	var resp pluginv1.PaymentCreateResponse
+	if err := proto.Unmarshal(got.GetValue(), &resp); err != nil {
+		return nil
+	}
+	return &resp
+}
+
+func (p *payServer) storePayment(cacheKey string, resp *pluginv1.PaymentCreateResponse, ttl time.Duration) {
+	if p.core == nil || p.core.host == nil {
+		return
+	}
+	raw, err := proto.Marshal(resp)
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, _ = p.core.host.KVSet(ctx, &pluginv1.KVSetRequest{Key: "payment_link:" + cacheKey, Value: raw, TtlSec: int64(ttl / time.Second)})
 }
 
 func rawToParams(req *pluginv1.RawHttpRequest) map[string]string {
@@ -990,11 +1030,14 @@ func firstNonEmpty(v string, fallback string) string {
 }
 
 func main() {
+	pluginsdk.Serve(newPlugins())
+}
+
+func newPlugins() map[string]pluginsdk.Plugin {
 	core := &coreServer{}
 	pay := &payServer{core: core}
-
-	pluginsdk.Serve(map[string]pluginsdk.Plugin{
+	return map[string]pluginsdk.Plugin{
 		pluginsdk.PluginKeyCore:    &pluginsdk.CoreGRPCPlugin{Impl: core},
 		pluginsdk.PluginKeyPayment: &pluginsdk.PaymentGRPCPlugin{Impl: pay},
-	})
+	}
 }
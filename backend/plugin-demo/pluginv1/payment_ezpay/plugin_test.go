@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	plugintest "xiaoheiplay/pkg/pluginsdk/testing"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+func signedNotify(key string) *pluginv1.RawHttpRequest {
+	params := map[string]string{
+		"pid":          "10001",
+		"out_trade_no": "ORDER-9-wx",
+		"trade_no":     "PLAT-1",
+		"type":         "wxpay",
+		"money":        "5.00",
+		"trade_status": "TRADE_SUCCESS",
+		"sign_type":    "MD5",
+	}
+	params["sign"] = signEZPay(params, key, "plain")
+	q := url.Values{}
+	for k, v := range params {
+		q.Set(k, v)
+	}
+	return &pluginv1.RawHttpRequest{Method: "GET", RawQuery: q.Encode()}
+}
+
+func TestPlugin_MethodsAndNotifyAcrossReload(t *testing.T) {
+	h := plugintest.New(t, newPlugins())
+	if err := h.ValidateConfig(`{"pid":"10001"}`); err == nil {
+		t.Fatalf("expected missing merchant_key to be rejected")
+	}
+	manifest := h.Start("../../../plugins/payment/ezpay", `{"pid":"10001","merchant_key":"k1"}`)
+	pay := h.Payment()
+	ctx := context.Background()
+
+	methods, err := pay.ListMethods(ctx, &pluginv1.Empty{})
+	if err != nil || !slices.Equal(methods.GetMethods(), manifest.GetPayment().GetMethods()) {
+		t.Fatalf("ListMethods %v does not match manifest %v (%v)", methods.GetMethods(), manifest.GetPayment().GetMethods(), err)
+	}
+
+	res, err := pay.VerifyNotify(ctx, &pluginv1.VerifyNotifyRequest{Method: "wxpay", Raw: signedNotify("k1")})
+	if err != nil || res.GetOrderNo() != "ORDER-9" || res.GetAmount() != 500 || res.GetStatus() != pluginv1.PaymentStatus_PAYMENT_STATUS_PAID {
+		t.Fatalf("verify: %v %v", res, err)
+	}
+
+	if err := h.ReloadConfig(`{"pid":"10001","merchant_key":"k2"}`); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if _, err := pay.VerifyNotify(ctx, &pluginv1.VerifyNotifyRequest{Method: "wxpay", Raw: signedNotify("k1")}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected the old key's signature to be rejected after reload, got %v", err)
+	}
+	if _, err := pay.VerifyNotify(ctx, &pluginv1.VerifyNotifyRequest{Method: "wxpay", Raw: signedNotify("k2")}); err != nil {
+		t.Fatalf("verify with reloaded key: %v", err)
+	}
+}
@@ -579,8 +579,12 @@ func main() {
 	core := &coreServer{}
 	pay := &payServer{core: core, pending: map[string]pendingPayment{}}
 	pay.prewarm()
-	pluginsdk.Serve(map[string]pluginsdk.Plugin{
+	pluginsdk.Serve(newPlugins(core, pay))
+}
+
+func newPlugins(core *coreServer, pay *payServer) map[string]pluginsdk.Plugin {
+	return map[string]pluginsdk.Plugin{
 		pluginsdk.PluginKeyCore:    &pluginsdk.CoreGRPCPlugin{Impl: core},
 		pluginsdk.PluginKeyPayment: &pluginsdk.PaymentGRPCPlugin{Impl: pay},
-	})
+	}
 }
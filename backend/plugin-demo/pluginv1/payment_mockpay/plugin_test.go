@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	plugintest "xiaoheiplay/pkg/pluginsdk/testing"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+func startMockpay(t *testing.T) *plugintest.Harness {
+	t.Helper()
+	// Skip the cloudflared tunnel; the checkout page stays on localhost.
+	t.Setenv("MOCKPAY_PUBLIC_BASE_URL", "https://pay.example")
+	core := &coreServer{}
+	h := plugintest.New(t, newPlugins(core, &payServer{core: core, pending: map[string]pendingPayment{}}))
+	h.Start("../../../plugins/payment/mockpay", `{"confirm_text":"`+confirmTextNeed+`"}`)
+	return h
+}
+
+func TestPlugin_CreateAndVerifyNotify(t *testing.T) {
+	h := startMockpay(t)
+	h.Health()
+	pay := h.Payment()
+	ctx := context.Background()
+
+	created, err := pay.CreatePayment(ctx, &pluginv1.CreatePaymentRpcRequest{
+		Method:  pluginMethod,
+		Request: &pluginv1.PaymentCreateRequest{OrderNo: "O1", Amount: 1999, Currency: "CNY", ReturnUrl: "https://shop.example/orders/O1"},
+	})
+	if err != nil || !created.GetOk() {
+		t.Fatalf("create: %v %v", created, err)
+	}
+	if !strings.HasPrefix(created.GetPayUrl(), "https://pay.example/mockpay/checkout?token=") {
+		t.Fatalf("unexpected pay url %q", created.GetPayUrl())
+	}
+	if got := created.GetExtra()["debug_notify_url"]; got != "https://shop.example/api/v1/payments/notify/mockpay.mock" {
+		t.Fatalf("unexpected notify url %q", got)
+	}
+
+	form := url.Values{
+		"token":    {created.GetExtra()["debug_token"]},
+		"order_no": {"O1"},
+		"trade_no": {created.GetTradeNo()},
+		"amount":   {strconv.Itoa(1999)},
+		"paid":     {"1"},
+	}
+	res, err := pay.VerifyNotify(ctx, &pluginv1.VerifyNotifyRequest{Method: pluginMethod, Raw: &pluginv1.RawHttpRequest{Body: []byte(form.Encode())}})
+	if err != nil || res.GetStatus() != pluginv1.PaymentStatus_PAYMENT_STATUS_PAID || res.GetAmount() != 1999 {
+		t.Fatalf("verify: %v %v", res, err)
+	}
+
+	form.Set("amount", "1")
+	if _, err := pay.VerifyNotify(ctx, &pluginv1.VerifyNotifyRequest{Method: pluginMethod, Raw: &pluginv1.RawHttpRequest{Body: []byte(form.Encode())}}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected tampered amount to be rejected, got %v", err)
+	}
+}
+
+func TestPlugin_ConfirmTextGuard(t *testing.T) {
+	h := startMockpay(t)
+	if err := h.ValidateConfig(`{"confirm_text":"yes"}`); err == nil || err.Error() != "confirm_text mismatch" {
+		t.Fatalf("expected confirm_text mismatch, got %v", err)
+	}
+	if err := h.ReloadConfig(`{"confirm_text":"yes"}`); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	_, err := h.Payment().CreatePayment(context.Background(), &pluginv1.CreatePaymentRpcRequest{
+		Method:  pluginMethod,
+		Request: &pluginv1.PaymentCreateRequest{OrderNo: "O1", Amount: 1},
+	})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition without confirmation, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"slices"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	plugintest "xiaoheiplay/pkg/pluginsdk/testing"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+// Init downloads platform certificates from WeChat Pay, so these tests stop
+// at the checks that run before it.
+
+func TestPlugin_ManifestAndMethods(t *testing.T) {
+	h := plugintest.New(t, newPlugins())
+	if err := h.CheckManifest("../../../plugins/payment/wechatpay_v3"); err != nil {
+		t.Fatalf("check manifest: %v", err)
+	}
+	manifest, err := h.Core.GetManifest(context.Background(), &pluginv1.Empty{})
+	if err != nil {
+		t.Fatalf("get manifest: %v", err)
+	}
+	methods, err := h.Payment().ListMethods(context.Background(), &pluginv1.Empty{})
+	if err != nil || !slices.Equal(methods.GetMethods(), manifest.GetPayment().GetMethods()) {
+		t.Fatalf("ListMethods %v does not match manifest %v (%v)", methods.GetMethods(), manifest.GetPayment().GetMethods(), err)
+	}
+	if _, err := h.Payment().CreatePayment(context.Background(), &pluginv1.CreatePaymentRpcRequest{Method: "wechat_native"}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition before init, got %v", err)
+	}
+}
+
+func TestPlugin_ValidateConfig(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey: %v", err)
+	}
+	cfg := map[string]string{
+		"mch_id":                   "1900000001",
+		"merchant_serial_no":       "SERIAL",
+		"merchant_private_key_pem": string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"api_v3_key":               "01234567890123456789012345678901",
+		"app_id":                   "wx0000000000000000",
+	}
+	render := func() string {
+		b, _ := json.Marshal(cfg)
+		return string(b)
+	}
+
+	h := plugintest.New(t, newPlugins())
+	if err := h.ValidateConfig(render()); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	cfg["api_v3_key"] = "short"
+	if err := h.ValidateConfig(render()); err == nil || err.Error() != "api_v3_key must be 32 chars" {
+		t.Fatalf("expected api_v3_key length error, got %v", err)
+	}
+	cfg["api_v3_key"] = "01234567890123456789012345678901"
+	cfg["merchant_private_key_pem"] = "garbage"
+	if err := h.ValidateConfig(render()); err == nil || !strings.Contains(err.Error(), "merchant_private_key_pem") {
+		t.Fatalf("expected invalid key error, got %v", err)
+	}
+	delete(cfg, "app_id")
+	if err := h.ValidateConfig(render()); err == nil || !strings.Contains(err.Error(), "missing required config: app_id") {
+		t.Fatalf("expected missing app_id, got %v", err)
+	}
+}
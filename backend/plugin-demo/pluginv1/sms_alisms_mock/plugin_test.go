@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	plugintest "xiaoheiplay/pkg/pluginsdk/testing"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+const testConfig = `{"access_key_id":"ak","access_key_secret":"sk","sign_name":"Demo"}`
+
+func TestPlugin_ConfigFlow(t *testing.T) {
+	h := plugintest.New(t, newPlugins())
+	if err := h.ValidateConfig(`{"access_key_id":"ak"}`); err == nil {
+		t.Fatalf("expected missing secret and sign name to be rejected")
+	}
+	if err := h.ValidateConfig(testConfig); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if _, err := h.SMS().Send(context.Background(), &pluginv1.SendSmsRequest{TemplateId: "SMS_1", Phones: []string{"1"}}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition before init, got %v", err)
+	}
+	h.Start("../../../plugins/sms/alisms", testConfig)
+	if err := h.ReloadConfig(`{"sign_name":"Demo"}`); err == nil {
+		t.Fatalf("expected reload without keys to fail")
+	}
+}
+
+func TestPlugin_SendRequiresTemplateAndPhones(t *testing.T) {
+	h := plugintest.New(t, newPlugins())
+	h.Start("../../../plugins/sms/alisms", testConfig)
+	sms := h.SMS()
+	ctx := context.Background()
+	for name, req := range map[string]*pluginv1.SendSmsRequest{
+		"content only": {Content: "hello", Phones: []string{"13800000000"}},
+		"no phones":    {TemplateId: "SMS_1"},
+	} {
+		if _, err := sms.Send(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("%s: expected InvalidArgument, got %v", name, err)
+		}
+	}
+}
@@ -108,7 +108,13 @@ func (s *coreServer) Init(ctx context.Context, req *pluginv1.InitRequest) (*plug
 }
 
 func (s *coreServer) ReloadConfig(ctx context.Context, req *pluginv1.ReloadConfigRequest) (*pluginv1.ReloadConfigResponse, error) {
-	_, _ = s.Init(ctx, &pluginv1.InitRequest{InstanceId: s.instance, ConfigJson: req.GetConfigJson()})
+	ir, err := s.Init(ctx, &pluginv1.InitRequest{InstanceId: s.instance, ConfigJson: req.GetConfigJson()})
+	if err != nil {
+		return &pluginv1.ReloadConfigResponse{Ok: false, Error: err.Error()}, nil
+	}
+	if ir != nil && !ir.Ok {
+		return &pluginv1.ReloadConfigResponse{Ok: false, Error: ir.Error}, nil
+	}
 	return &pluginv1.ReloadConfigResponse{Ok: true}, nil
 }
 
@@ -188,12 +194,16 @@ func (s *smsServer) Send(ctx context.Context, req *pluginv1.SendSmsRequest) (*pl
 }
 
 func main() {
+	pluginsdk.Serve(newPlugins())
+}
+
+func newPlugins() map[string]pluginsdk.Plugin {
 	core := &coreServer{}
 	sms := &smsServer{core: core}
-	pluginsdk.Serve(map[string]pluginsdk.Plugin{
+	return map[string]pluginsdk.Plugin{
 		pluginsdk.PluginKeyCore: &pluginsdk.CoreGRPCPlugin{Impl: core},
 		pluginsdk.PluginKeySMS:  &pluginsdk.SmsGRPCPlugin{Impl: sms},
-	})
+	}
 }
 
 func md5Hex(raw string) string {
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	plugintest "xiaoheiplay/pkg/pluginsdk/testing"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+func TestPlugin_SendThroughEndpoint(t *testing.T) {
+	var got url.Values
+	reply := "0"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query()
+		_, _ = w.Write([]byte(reply))
+	}))
+	defer srv.Close()
+
+	h := plugintest.New(t, newPlugins())
+	cfg := `{"username":"demo","passwd":"secret","goods_id":"g1","endpoint":"` + srv.URL + `"}`
+	if err := h.ValidateConfig(cfg); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	h.Start("../../../plugins/sms/duanxinbao", cfg)
+	sms := h.SMS()
+	ctx := context.Background()
+
+	resp, err := sms.Send(ctx, &pluginv1.SendSmsRequest{Phones: []string{"13800000000", " "}, Content: "code 1234"})
+	if err != nil || !resp.GetOk() {
+		t.Fatalf("send: %v %v", resp, err)
+	}
+	if got.Get("u") != "demo" || got.Get("p") != md5Hex("secret") || got.Get("m") != "13800000000" || got.Get("g") != "g1" {
+		t.Fatalf("unexpected query %v", got)
+	}
+
+	reply = "30"
+	if _, err := sms.Send(ctx, &pluginv1.SendSmsRequest{Phones: []string{"13800000000"}, Content: "x"}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected rejected send, got %v", err)
+	}
+	if _, err := sms.Send(ctx, &pluginv1.SendSmsRequest{Phones: []string{"13800000000"}}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument without content, got %v", err)
+	}
+}
+
+func TestPlugin_ConfigFlow(t *testing.T) {
+	h := plugintest.New(t, newPlugins())
+	if err := h.ValidateConfig(`{"username":"demo"}`); err == nil {
+		t.Fatalf("expected missing passwd to be rejected")
+	}
+	if _, err := h.SMS().Send(context.Background(), &pluginv1.SendSmsRequest{Phones: []string{"1"}, Content: "x"}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition before init, got %v", err)
+	}
+	if err := h.Init(`{"username":"demo","passwd":"secret"}`); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	if err := h.ReloadConfig(`{"username":"demo"}`); err == nil {
+		t.Fatalf("expected reload without passwd to fail")
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	plugintest "xiaoheiplay/pkg/pluginsdk/testing"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+const testConfig = `{"secret_id":"id","secret_key":"key","sdk_app_id":"1400000000","sign_name":"Demo"}`
+
+func TestPlugin_ConfigFlow(t *testing.T) {
+	h := plugintest.New(t, newPlugins())
+	if err := h.ValidateConfig(`{"secret_id":"id","secret_key":"key"}`); err == nil {
+		t.Fatalf("expected missing sdk_app_id and sign_name to be rejected")
+	}
+	if err := h.ValidateConfig(testConfig); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	h.Start("../../../plugins/sms/tencent_sms", testConfig)
+	if err := h.ReloadConfig(`{`); err == nil {
+		t.Fatalf("expected reload with malformed json to fail")
+	}
+}
+
+func TestPlugin_SendValidatesRequest(t *testing.T) {
+	h := plugintest.New(t, newPlugins())
+	h.Start("../../../plugins/sms/tencent_sms", testConfig)
+	sms := h.SMS()
+	ctx := context.Background()
+	for name, req := range map[string]*pluginv1.SendSmsRequest{
+		"content only":     {Content: "hello", Phones: []string{"13800000000"}},
+		"blank phones":     {TemplateId: "1", Phones: []string{" "}},
+		"named vars":       {TemplateId: "1", Phones: []string{"13800000000"}, Vars: map[string]string{"code": "1234"}},
+		"zero-indexed var": {TemplateId: "1", Phones: []string{"13800000000"}, Vars: map[string]string{"0": "1234"}},
+	} {
+		if _, err := sms.Send(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("%s: expected InvalidArgument, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"xiaoheiplay/pkg/pluginsdk"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+type config struct {
+	Endpoint       string `json:"endpoint"`
+	Region         string `json:"region"`
+	Bucket         string `json:"bucket"`
+	AccessKeyID    string `json:"access_key_id"`
+	SecretKey      string `json:"secret_access_key"`
+	ForcePathStyle bool   `json:"force_path_style"`
+	Prefix         string `json:"prefix"`
+	TimeoutSec     int    `json:"timeout_sec"`
+}
+
+type coreServer struct {
+	pluginv1.UnimplementedCoreServiceServer
+	cfg      config
+	instance string
+	client   *s3Client
+}
+
+func (s *coreServer) GetManifest(ctx context.Context, _ *pluginv1.Empty) (*pluginv1.Manifest, error) {
+	_ = ctx
+	return &pluginv1.Manifest{
+		PluginId:    "s3_compat",
+		Name:        "S3 Compatible Storage",
+		Version:     "1.0.0",
+		Description: "Keeps uploads in an S3 compatible bucket (AWS S3, MinIO, Cloudflare R2, Aliyun OSS, Tencent COS).",
+		Storage:     &pluginv1.StorageCapability{Presign: true},
+	}, nil
+}
+
+func (s *coreServer) GetConfigSchema(ctx context.Context, _ *pluginv1.Empty) (*pluginv1.ConfigSchema, error) {
+	_ = ctx
+	return &pluginv1.ConfigSchema{
+		JsonSchema: `{
+  "title": "S3 Compatible Storage",
+  "type": "object",
+  "properties": {
+    "endpoint": { "type": "string", "title": "Endpoint", "description": "e.g. https://s3.us-east-1.amazonaws.com or http://127.0.0.1:9000" },
+    "region": { "type": "string", "title": "Region", "default": "us-east-1" },
+    "bucket": { "type": "string", "title": "Bucket" },
+    "access_key_id": { "type": "string", "title": "Access Key ID" },
+    "secret_access_key": { "type": "string", "title": "Secret Access Key", "format": "password" },
+    "force_path_style": { "type": "boolean", "title": "Path Style", "description": "Address the bucket as /bucket/key (MinIO and most self-hosted stores)", "default": false },
+    "prefix": { "type": "string", "title": "Key Prefix", "description": "Optional prefix for object keys, e.g. uploads/" },
+    "timeout_sec": { "type": "integer", "title": "Request Timeout (sec)", "default": 30, "minimum": 1, "maximum": 300 }
+  },
+  "required": ["endpoint","bucket","access_key_id","secret_access_key"]
+}`,
+		UiSchema: `{
+  "secret_access_key": { "ui:widget": "password", "ui:help": "留空表示不修改（由宿主处理）" }
+}`,
+	}, nil
+}
+
+func (s *coreServer) ValidateConfig(ctx context.Context, req *pluginv1.ValidateConfigRequest) (*pluginv1.ValidateConfigResponse, error) {
+	_ = ctx
+	var cfg config
+	if err := json.Unmarshal([]byte(req.GetConfigJson()), &cfg); err != nil {
+		return &pluginv1.ValidateConfigResponse{Ok: false, Error: "invalid json"}, nil
+	}
+	if _, err := newS3Client(cfg); err != "" {
+		return &pluginv1.ValidateConfigResponse{Ok: false, Error: err}, nil
+	}
+	return &pluginv1.ValidateConfigResponse{Ok: true}, nil
+}
+
+func (s *coreServer) Init(ctx context.Context, req *pluginv1.InitRequest) (*pluginv1.InitResponse, error) {
+	_ = ctx
+	var cfg config
+	if err := json.Unmarshal([]byte(req.GetConfigJson()), &cfg); err != nil {
+		return &pluginv1.InitResponse{Ok: false, Error: "invalid config"}, nil
+	}
+	client, errMsg := newS3Client(cfg)
+	if errMsg != "" {
+		return &pluginv1.InitResponse{Ok: false, Error: errMsg}, nil
+	}
+	s.cfg = cfg
+	s.client = client
+	s.instance = req.GetInstanceId()
+	return &pluginv1.InitResponse{Ok: true}, nil
+}
+
+func (s *coreServer) ReloadConfig(ctx context.Context, req *pluginv1.ReloadConfigRequest) (*pluginv1.ReloadConfigResponse, error) {
+	resp, err := s.Init(ctx, &pluginv1.InitRequest{InstanceId: s.instance, ConfigJson: req.GetConfigJson()})
+	if err != nil {
+		return &pluginv1.ReloadConfigResponse{Ok: false, Error: err.Error()}, nil
+	}
+	return &pluginv1.ReloadConfigResponse{Ok: resp.GetOk(), Error: resp.GetError()}, nil
+}
+
+func (s *coreServer) Health(ctx context.Context, _ *pluginv1.HealthCheckRequest) (*pluginv1.HealthCheckResponse, error) {
+	_ = ctx
+	msg := "ok"
+	if s.client == nil {
+		msg = "not initialized"
+	}
+	return &pluginv1.HealthCheckResponse{
+		Status:     pluginv1.HealthStatus_HEALTH_STATUS_OK,
+		Message:    msg,
+		UnixMillis: time.Now().UnixMilli(),
+	}, nil
+}
+
+func newS3Client(cfg config) (*s3Client, string) {
+	if strings.TrimSpace(cfg.Endpoint) == "" || strings.TrimSpace(cfg.Bucket) == "" ||
+		strings.TrimSpace(cfg.AccessKeyID) == "" || strings.TrimSpace(cfg.SecretKey) == "" {
+		return nil, "endpoint/bucket/access_key_id/secret_access_key required"
+	}
+	endpoint, err := url.Parse(strings.TrimSpace(cfg.Endpoint))
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, "endpoint must be an http(s) URL"
+	}
+	region := strings.TrimSpace(cfg.Region)
+	if region == "" {
+		region = "us-east-1"
+	}
+	timeout := cfg.TimeoutSec
+	if timeout <= 0 {
+		timeout = 30
+	}
+	return &s3Client{
+		endpoint:  endpoint,
+		region:    region,
+		bucket:    strings.TrimSpace(cfg.Bucket),
+		accessKey: strings.TrimSpace(cfg.AccessKeyID),
+		secretKey: strings.TrimSpace(cfg.SecretKey),
+		pathStyle: cfg.ForcePathStyle,
+		http:      &http.Client{Timeout: time.Duration(timeout) * time.Second},
+		now:       time.Now,
+	}, ""
+}
+
+type storageServer struct {
+	pluginv1.UnimplementedStorageServiceServer
+	core *coreServer
+}
+
+func (s *storageServer) Put(ctx context.Context, req *pluginv1.PutObjectRequest) (*pluginv1.PutObjectResponse, error) {
+	key, err := s.objectKey(req.GetKey())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.core.client.put(ctx, key, req.GetContent(), req.GetContentType()); err != nil {
+		return nil, rpcError(err)
+	}
+	return &pluginv1.PutObjectResponse{Ok: true}, nil
+}
+
+func (s *storageServer) Get(ctx context.Context, req *pluginv1.GetObjectRequest) (*pluginv1.GetObjectResponse, error) {
+	key, err := s.objectKey(req.GetKey())
+	if err != nil {
+		return nil, err
+	}
+	body, contentType, err := s.core.client.get(ctx, key)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	return &pluginv1.GetObjectResponse{Ok: true, Content: body, ContentType: contentType}, nil
+}
+
+func (s *storageServer) Delete(ctx context.Context, req *pluginv1.DeleteObjectRequest) (*pluginv1.DeleteObjectResponse, error) {
+	key, err := s.objectKey(req.GetKey())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.core.client.del(ctx, key); err != nil {
+		return nil, rpcError(err)
+	}
+	return &pluginv1.DeleteObjectResponse{Ok: true}, nil
+}
+
+func (s *storageServer) PresignURL(ctx context.Context, req *pluginv1.PresignURLRequest) (*pluginv1.PresignURLResponse, error) {
+	_ = ctx
+	key, err := s.objectKey(req.GetKey())
+	if err != nil {
+		return nil, err
+	}
+	u, expiresAt := s.core.client.presign(key, time.Duration(req.GetExpiresInSec())*time.Second)
+	return &pluginv1.PresignURLResponse{Ok: true, Url: u, ExpiresAtUnix: expiresAt.Unix()}, nil
+}
+
+func (s *storageServer) objectKey(key string) (string, error) {
+	if s.core == nil || s.core.client == nil {
+		return "", status.Error(codes.FailedPrecondition, "plugin not initialized")
+	}
+	key = strings.TrimSpace(key)
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "..") {
+		return "", status.Error(codes.InvalidArgument, "invalid key")
+	}
+	return strings.TrimSpace(s.core.cfg.Prefix) + key, nil
+}
+
+func rpcError(err error) error {
+	if e, ok := err.(*s3Error); ok {
+		switch {
+		case e.Status == http.StatusNotFound:
+			return status.Error(codes.NotFound, e.Error())
+		case e.Status == http.StatusForbidden || e.Status == http.StatusUnauthorized:
+			return status.Error(codes.PermissionDenied, e.Error())
+		case e.Status >= 500:
+			return status.Error(codes.Unavailable, e.Error())
+		default:
+			return status.Error(codes.FailedPrecondition, e.Error())
+		}
+	}
+	return status.Error(codes.Unavailable, err.Error())
+}
+
+func main() {
+	pluginsdk.Serve(newPlugins())
+}
+
+func newPlugins() map[string]pluginsdk.Plugin {
+	core := &coreServer{}
+	storage := &storageServer{core: core}
+	return map[string]pluginsdk.Plugin{
+		pluginsdk.PluginKeyCore:    &pluginsdk.CoreGRPCPlugin{Impl: core},
+		pluginsdk.PluginKeyStorage: &pluginsdk.StorageGRPCPlugin{Impl: storage},
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	plugintest "xiaoheiplay/pkg/pluginsdk/testing"
+	pluginv1 "xiaoheiplay/plugin/v1"
+)
+
+func TestPlugin_LargeObjectAndReload(t *testing.T) {
+	fake := newFakeS3("uploads", "us-west-2", "test-secret")
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	render := func(prefix string) string {
+		b, _ := json.Marshal(config{
+			Endpoint: srv.URL, Region: "us-west-2", Bucket: "uploads",
+			AccessKeyID: "test-key", SecretKey: "test-secret", ForcePathStyle: true, Prefix: prefix,
+		})
+		return string(b)
+	}
+
+	h := plugintest.New(t, newPlugins())
+	if err := h.ValidateConfig(`{"endpoint":"ftp://example","bucket":"b","access_key_id":"k","secret_access_key":"s"}`); err == nil {
+		t.Fatalf("expected a non-http endpoint to be rejected")
+	}
+	h.Start("../../../plugins/storage/s3_compat", render("a/"))
+	storage := h.Storage()
+	ctx := context.Background()
+
+	// Above the 4 MiB gRPC default, as a large upload would be.
+	content := bytes.Repeat([]byte("x"), 6<<20)
+	if _, err := storage.Put(ctx, &pluginv1.PutObjectRequest{Key: "big.bin", Content: content}); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	got, err := storage.Get(ctx, &pluginv1.GetObjectRequest{Key: "big.bin"})
+	if err != nil || !bytes.Equal(got.GetContent(), content) {
+		t.Fatalf("get: %v (%d bytes)", err, len(got.GetContent()))
+	}
+
+	if err := h.ReloadConfig(render("b/")); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if _, err := storage.Put(ctx, &pluginv1.PutObjectRequest{Key: "small.txt", Content: []byte("hi")}); err != nil {
+		t.Fatalf("put after reload: %v", err)
+	}
+	fake.mu.Lock()
+	_, underOld := fake.objects["a/big.bin"]
+	_, underNew := fake.objects["b/small.txt"]
+	fake.mu.Unlock()
+	if !underOld || !underNew {
+		t.Fatalf("expected objects under each configured prefix, got %v %v", underOld, underNew)
+	}
+	if err := h.ReloadConfig(`{"bucket":"uploads"}`); err == nil {
+		t.Fatalf("expected reload without endpoint to fail")
+	}
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	sigAlgorithm     = "AWS4-HMAC-SHA256"
+	amzDateFormat    = "20060102T150405Z"
+	unsignedPayload  = "UNSIGNED-PAYLOAD"
+	maxPresignExpiry = 7 * 24 * time.Hour
+)
+
+// s3Client talks to an S3 compatible object store (AWS S3, MinIO, R2, OSS,
+// COS ...) using Signature Version 4.
+type s3Client struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	pathStyle bool
+	http      *http.Client
+	now       func() time.Time
+}
+
+type s3Error struct {
+	Status  int
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func (e *s3Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("s3 http %d", e.Status)
+	}
+	if e.Message == "" {
+		return fmt.Sprintf("s3 %s (http %d)", e.Code, e.Status)
+	}
+	return fmt.Sprintf("s3 %s: %s (http %d)", e.Code, e.Message, e.Status)
+}
+
+func (c *s3Client) put(ctx context.Context, key string, body []byte, contentType string) error {
+	req, err := c.newRequest(ctx, http.MethodPut, key, body)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := c.do(req, body)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (c *s3Client) get(ctx context.Context, key string) ([]byte, string, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := c.do(req, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	return body, resp.Header.Get("Content-Type"), nil
+}
+
+// del succeeds for missing objects, as S3 DeleteObject does.
+func (c *s3Client) del(ctx context.Context, key string) error {
+	req, err := c.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(req, nil)
+	if err != nil {
+		if e, ok := err.(*s3Error); ok && e.Status == http.StatusNotFound {
+			return nil
+		}
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// presign returns a GET URL valid for expires, signed in the query string.
+func (c *s3Client) presign(key string, expires time.Duration) (string, time.Time) {
+	if expires <= 0 {
+		expires = 15 * time.Minute
+	}
+	if expires > maxPresignExpiry {
+		expires = maxPresignExpiry
+	}
+	t := c.now().UTC()
+	u := c.objectURL(key)
+	q := url.Values{}
+	q.Set("X-Amz-Algorithm", sigAlgorithm)
+	q.Set("X-Amz-Credential", c.accessKey+"/"+c.scope(t))
+	q.Set("X-Amz-Date", t.Format(amzDateFormat))
+	q.Set("X-Amz-Expires", strconv.Itoa(int(expires/time.Second)))
+	q.Set("X-Amz-SignedHeaders", "host")
+	u.RawQuery = canonicalQuery(q)
+	canonical := strings.Join([]string{
+		http.MethodGet,
+		u.EscapedPath(),
+		u.RawQuery,
+		"host:" + u.Host + "\n",
+		"host",
+		unsignedPayload,
+	}, "\n")
+	u.RawQuery += "&X-Amz-Signature=" + c.signature(t, canonical)
+	return u.String(), t.Add(expires)
+}
+
+func (c *s3Client) newRequest(ctx context.Context, method, key string, body []byte) (*http.Request, error) {
+	var rd io.Reader
+	if body != nil {
+		rd = bytes.NewReader(body)
+	}
+	return http.NewRequestWithContext(ctx, method, c.objectURL(key).String(), rd)
+}
+
+func (c *s3Client) do(req *http.Request, body []byte) (*http.Response, error) {
+	sum := sha256.Sum256(body)
+	c.sign(req, hex.EncodeToString(sum[:]), c.now())
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	e := &s3Error{Status: resp.StatusCode}
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	_ = xml.Unmarshal(raw, e)
+	return nil, e
+}
+
+func (c *s3Client) objectURL(key string) *url.URL {
+	u := *c.endpoint
+	base := strings.TrimSuffix(u.Path, "/")
+	if c.pathStyle {
+		u.Path = base + "/" + c.bucket + "/" + key
+	} else {
+		u.Host = c.bucket + "." + u.Host
+		u.Path = base + "/" + key
+	}
+	u.RawPath = encodePath(u.Path)
+	u.RawQuery = ""
+	return &u
+}
+
+// sign adds the SigV4 Authorization header. Every header already set on the
+// request is signed along with host.
+func (c *s3Client) sign(req *http.Request, payloadHash string, t time.Time) {
+	t = t.UTC()
+	req.Header.Set("X-Amz-Date", t.Format(amzDateFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigAlgorithm, c.accessKey, c.scope(t), signedHeaders, c.signature(t, canonical)))
+}
+
+func (c *s3Client) scope(t time.Time) string {
+	return t.Format("20060102") + "/" + c.region + "/s3/aws4_request"
+}
+
+func (c *s3Client) signature(t time.Time, canonicalRequest string) string {
+	sum := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := sigAlgorithm + "\n" + t.Format(amzDateFormat) + "\n" + c.scope(t) + "\n" + hex.EncodeToString(sum[:])
+	key := hmacSHA256([]byte("AWS4"+c.secretKey), t.Format("20060102"))
+	key = hmacSHA256(key, c.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func canonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		values := append([]string(nil), q[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			parts = append(parts, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+func encodePath(p string) string {
+	return uriEncode(p, false)
+}
+
+// uriEncode percent-encodes everything but the RFC 3986 unreserved
+// characters; "/" is kept unless encodeSlash is set.
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case ch >= 'A' && ch <= 'Z', ch >= 'a' && ch <= 'z', ch >= '0' && ch <= '9',
+			ch == '-', ch == '_', ch == '.', ch == '~':
+			b.WriteByte(ch)
+		case ch == '/' && !encodeSlash:
+			b.WriteByte(ch)
+		default:
+			fmt.Fprintf(&b, "%%%02X", ch)
+		}
+	}
+	return b.String()
+}
@@ -391,6 +391,10 @@ type AutomationInstance struct {
 	VncPassword   string                 `protobuf:"bytes,10,opt,name=vnc_password,json=vncPassword,proto3" json:"vnc_password,omitempty"`
 	OsPassword    string                 `protobuf:"bytes,11,opt,name=os_password,json=osPassword,proto3" json:"os_password,omitempty"`
 	ExpireAtUnix  int64                  `protobuf:"varint,12,opt,name=expire_at_unix,json=expireAtUnix,proto3" json:"expire_at_unix,omitempty"`
+	// Progress (0-100) of the rebuild or restore running on the instance, if the
+	// provider reports one. Leave unset when the provider has no task progress.
+	TaskProgress  *int32 `protobuf:"varint,13,opt,name=task_progress,json=taskProgress,proto3,oneof" json:"task_progress,omitempty"`
+	TaskMessage   string `protobuf:"bytes,14,opt,name=task_message,json=taskMessage,proto3" json:"task_message,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -509,6 +513,20 @@ func (x *AutomationInstance) GetExpireAtUnix() int64 {
 	return 0
 }
 
+func (x *AutomationInstance) GetTaskProgress() int32 {
+	if x != nil && x.TaskProgress != nil {
+		return *x.TaskProgress
+	}
+	return 0
+}
+
+func (x *AutomationInstance) GetTaskMessage() string {
+	if x != nil {
+		return x.TaskMessage
+	}
+	return ""
+}
+
 type ListAreasResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Items         []*AutomationArea      `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
@@ -792,8 +810,17 @@ type CreateInstanceRequest struct {
 	MemoryGb      int32 `protobuf:"varint,11,opt,name=memory_gb,json=memoryGb,proto3" json:"memory_gb,omitempty"`
 	DiskGb        int32 `protobuf:"varint,12,opt,name=disk_gb,json=diskGb,proto3" json:"disk_gb,omitempty"`
 	BandwidthMbps int32 `protobuf:"varint,13,opt,name=bandwidth_mbps,json=bandwidthMbps,proto3" json:"bandwidth_mbps,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	// Guest initialization (requires AUTOMATION_FEATURE_CLOUD_INIT).
+	// ssh_keys are OpenSSH authorized_keys lines installed for the default user.
+	SshKeys []string `protobuf:"bytes,14,rep,name=ssh_keys,json=sshKeys,proto3" json:"ssh_keys,omitempty"`
+	// user_data is passed to cloud-init as-is; startup_script runs once on first boot
+	// for providers without cloud-init.
+	UserData      string `protobuf:"bytes,15,opt,name=user_data,json=userData,proto3" json:"user_data,omitempty"`
+	StartupScript string `protobuf:"bytes,16,opt,name=startup_script,json=startupScript,proto3" json:"startup_script,omitempty"`
+	// disable_password_login asks the provider to install without password authentication.
+	DisablePasswordLogin bool `protobuf:"varint,17,opt,name=disable_password_login,json=disablePasswordLogin,proto3" json:"disable_password_login,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
 }
 
 func (x *CreateInstanceRequest) Reset() {
@@ -917,6 +944,34 @@ func (x *CreateInstanceRequest) GetBandwidthMbps() int32 {
 	return 0
 }
 
+func (x *CreateInstanceRequest) GetSshKeys() []string {
+	if x != nil {
+		return x.SshKeys
+	}
+	return nil
+}
+
+func (x *CreateInstanceRequest) GetUserData() string {
+	if x != nil {
+		return x.UserData
+	}
+	return ""
+}
+
+func (x *CreateInstanceRequest) GetStartupScript() string {
+	if x != nil {
+		return x.StartupScript
+	}
+	return ""
+}
+
+func (x *CreateInstanceRequest) GetDisablePasswordLogin() bool {
+	if x != nil {
+		return x.DisablePasswordLogin
+	}
+	return false
+}
+
 type CreateInstanceResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	InstanceId    int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
@@ -1330,12 +1385,17 @@ func (x *RebootRequest) GetInstanceId() int64 {
 }
 
 type RebuildRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	InstanceId    int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
-	ImageId       int64                  `protobuf:"varint,2,opt,name=image_id,json=imageId,proto3" json:"image_id,omitempty"`
-	Password      string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	ImageId    int64                  `protobuf:"varint,2,opt,name=image_id,json=imageId,proto3" json:"image_id,omitempty"`
+	Password   string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	// Same semantics as CreateInstanceRequest (requires AUTOMATION_FEATURE_CLOUD_INIT).
+	SshKeys              []string `protobuf:"bytes,4,rep,name=ssh_keys,json=sshKeys,proto3" json:"ssh_keys,omitempty"`
+	UserData             string   `protobuf:"bytes,5,opt,name=user_data,json=userData,proto3" json:"user_data,omitempty"`
+	StartupScript        string   `protobuf:"bytes,6,opt,name=startup_script,json=startupScript,proto3" json:"startup_script,omitempty"`
+	DisablePasswordLogin bool     `protobuf:"varint,7,opt,name=disable_password_login,json=disablePasswordLogin,proto3" json:"disable_password_login,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
 }
 
 func (x *RebuildRequest) Reset() {
@@ -1389,6 +1449,34 @@ func (x *RebuildRequest) GetPassword() string {
 	return ""
 }
 
+func (x *RebuildRequest) GetSshKeys() []string {
+	if x != nil {
+		return x.SshKeys
+	}
+	return nil
+}
+
+func (x *RebuildRequest) GetUserData() string {
+	if x != nil {
+		return x.UserData
+	}
+	return ""
+}
+
+func (x *RebuildRequest) GetStartupScript() string {
+	if x != nil {
+		return x.StartupScript
+	}
+	return ""
+}
+
+func (x *RebuildRequest) GetDisablePasswordLogin() bool {
+	if x != nil {
+		return x.DisablePasswordLogin
+	}
+	return false
+}
+
 type ResetPasswordRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	InstanceId    int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
@@ -3286,6 +3374,607 @@ func (x *DeleteFirewallRuleRequest) GetRuleId() int64 {
 	return 0
 }
 
+type AutomationReverseDNSRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	Ptr           string                 `protobuf:"bytes,2,opt,name=ptr,proto3" json:"ptr,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AutomationReverseDNSRecord) Reset() {
+	*x = AutomationReverseDNSRecord{}
+	mi := &file_plugin_v1_automation_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AutomationReverseDNSRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AutomationReverseDNSRecord) ProtoMessage() {}
+
+func (x *AutomationReverseDNSRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_automation_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AutomationReverseDNSRecord.ProtoReflect.Descriptor instead.
+func (*AutomationReverseDNSRecord) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_automation_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *AutomationReverseDNSRecord) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *AutomationReverseDNSRecord) GetPtr() string {
+	if x != nil {
+		return x.Ptr
+	}
+	return ""
+}
+
+// Lists one record per IP assigned to the instance, including IPs without a PTR.
+type ListReverseDNSRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId    int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReverseDNSRequest) Reset() {
+	*x = ListReverseDNSRequest{}
+	mi := &file_plugin_v1_automation_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReverseDNSRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReverseDNSRequest) ProtoMessage() {}
+
+func (x *ListReverseDNSRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_automation_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReverseDNSRequest.ProtoReflect.Descriptor instead.
+func (*ListReverseDNSRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_automation_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *ListReverseDNSRequest) GetInstanceId() int64 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+type ListReverseDNSResponse struct {
+	state         protoimpl.MessageState        `protogen:"open.v1"`
+	Items         []*AutomationReverseDNSRecord `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReverseDNSResponse) Reset() {
+	*x = ListReverseDNSResponse{}
+	mi := &file_plugin_v1_automation_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReverseDNSResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReverseDNSResponse) ProtoMessage() {}
+
+func (x *ListReverseDNSResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_automation_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReverseDNSResponse.ProtoReflect.Descriptor instead.
+func (*ListReverseDNSResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_automation_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *ListReverseDNSResponse) GetItems() []*AutomationReverseDNSRecord {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// An empty ptr resets the record to the provider default.
+type SetReverseDNSRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId    int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	Ip            string                 `protobuf:"bytes,2,opt,name=ip,proto3" json:"ip,omitempty"`
+	Ptr           string                 `protobuf:"bytes,3,opt,name=ptr,proto3" json:"ptr,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetReverseDNSRequest) Reset() {
+	*x = SetReverseDNSRequest{}
+	mi := &file_plugin_v1_automation_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetReverseDNSRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetReverseDNSRequest) ProtoMessage() {}
+
+func (x *SetReverseDNSRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_automation_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetReverseDNSRequest.ProtoReflect.Descriptor instead.
+func (*SetReverseDNSRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_automation_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *SetReverseDNSRequest) GetInstanceId() int64 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+func (x *SetReverseDNSRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *SetReverseDNSRequest) GetPtr() string {
+	if x != nil {
+		return x.Ptr
+	}
+	return ""
+}
+
+// family: "ipv4" | "ipv6". The plugin allocates one additional address and
+// attaches it to the instance.
+type AssignIPRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId    int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	Family        string                 `protobuf:"bytes,2,opt,name=family,proto3" json:"family,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignIPRequest) Reset() {
+	*x = AssignIPRequest{}
+	mi := &file_plugin_v1_automation_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignIPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignIPRequest) ProtoMessage() {}
+
+func (x *AssignIPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_automation_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignIPRequest.ProtoReflect.Descriptor instead.
+func (*AssignIPRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_automation_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *AssignIPRequest) GetInstanceId() int64 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+func (x *AssignIPRequest) GetFamily() string {
+	if x != nil {
+		return x.Family
+	}
+	return ""
+}
+
+type AssignIPResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignIPResponse) Reset() {
+	*x = AssignIPResponse{}
+	mi := &file_plugin_v1_automation_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignIPResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignIPResponse) ProtoMessage() {}
+
+func (x *AssignIPResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_automation_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignIPResponse.ProtoReflect.Descriptor instead.
+func (*AssignIPResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_automation_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *AssignIPResponse) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+type ReleaseIPRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId    int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	Ip            string                 `protobuf:"bytes,2,opt,name=ip,proto3" json:"ip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseIPRequest) Reset() {
+	*x = ReleaseIPRequest{}
+	mi := &file_plugin_v1_automation_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseIPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseIPRequest) ProtoMessage() {}
+
+func (x *ReleaseIPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_automation_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseIPRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseIPRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_automation_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *ReleaseIPRequest) GetInstanceId() int64 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+func (x *ReleaseIPRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+// Boots the instance into the provider rescue system. password is generated by
+// the host; plugins whose provider issues its own credentials return those
+// instead. The host shows the credentials once and never stores them.
+type EnterRescueRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId    int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnterRescueRequest) Reset() {
+	*x = EnterRescueRequest{}
+	mi := &file_plugin_v1_automation_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnterRescueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnterRescueRequest) ProtoMessage() {}
+
+func (x *EnterRescueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_automation_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnterRescueRequest.ProtoReflect.Descriptor instead.
+func (*EnterRescueRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_automation_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *EnterRescueRequest) GetInstanceId() int64 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+func (x *EnterRescueRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type EnterRescueResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Username      string                 `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnterRescueResponse) Reset() {
+	*x = EnterRescueResponse{}
+	mi := &file_plugin_v1_automation_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnterRescueResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnterRescueResponse) ProtoMessage() {}
+
+func (x *EnterRescueResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_automation_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnterRescueResponse.ProtoReflect.Descriptor instead.
+func (*EnterRescueResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_automation_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *EnterRescueResponse) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *EnterRescueResponse) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+// Reboots the instance from its own disk.
+type ExitRescueRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId    int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExitRescueRequest) Reset() {
+	*x = ExitRescueRequest{}
+	mi := &file_plugin_v1_automation_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExitRescueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExitRescueRequest) ProtoMessage() {}
+
+func (x *ExitRescueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_automation_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExitRescueRequest.ProtoReflect.Descriptor instead.
+func (*ExitRescueRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_automation_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *ExitRescueRequest) GetInstanceId() int64 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+// iso is the provider reference (image id or URL) configured by the admin.
+type MountISORequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId    int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	Iso           string                 `protobuf:"bytes,2,opt,name=iso,proto3" json:"iso,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MountISORequest) Reset() {
+	*x = MountISORequest{}
+	mi := &file_plugin_v1_automation_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MountISORequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MountISORequest) ProtoMessage() {}
+
+func (x *MountISORequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_automation_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MountISORequest.ProtoReflect.Descriptor instead.
+func (*MountISORequest) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_automation_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *MountISORequest) GetInstanceId() int64 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
+func (x *MountISORequest) GetIso() string {
+	if x != nil {
+		return x.Iso
+	}
+	return ""
+}
+
+type UnmountISORequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId    int64                  `protobuf:"varint,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnmountISORequest) Reset() {
+	*x = UnmountISORequest{}
+	mi := &file_plugin_v1_automation_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnmountISORequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnmountISORequest) ProtoMessage() {}
+
+func (x *UnmountISORequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_automation_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnmountISORequest.ProtoReflect.Descriptor instead.
+func (*UnmountISORequest) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_automation_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *UnmountISORequest) GetInstanceId() int64 {
+	if x != nil {
+		return x.InstanceId
+	}
+	return 0
+}
+
 var File_plugin_v1_automation_proto protoreflect.FileDescriptor
 
 const file_plugin_v1_automation_proto_rawDesc = "" +
@@ -3319,7 +4008,7 @@ const file_plugin_v1_automation_proto_rawDesc = "" +
 	"\x0fAutomationImage\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
-	"\x04type\x18\x03 \x01(\tR\x04type\"\xeb\x02\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\"\xca\x03\n" +
 	"\x12AutomationInstance\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
@@ -3334,7 +4023,10 @@ const file_plugin_v1_automation_proto_rawDesc = "" +
 	" \x01(\tR\vvncPassword\x12\x1f\n" +
 	"\vos_password\x18\v \x01(\tR\n" +
 	"osPassword\x12$\n" +
-	"\x0eexpire_at_unix\x18\f \x01(\x03R\fexpireAtUnix\"D\n" +
+	"\x0eexpire_at_unix\x18\f \x01(\x03R\fexpireAtUnix\x12(\n" +
+	"\rtask_progress\x18\r \x01(\x05H\x00R\ftaskProgress\x88\x01\x01\x12!\n" +
+	"\ftask_message\x18\x0e \x01(\tR\vtaskMessageB\x10\n" +
+	"\x0e_task_progress\"D\n" +
 	"\x11ListAreasResponse\x12/\n" +
 	"\x05items\x18\x01 \x03(\v2\x19.plugin.v1.AutomationAreaR\x05items\"D\n" +
 	"\x11ListLinesResponse\x12/\n" +
@@ -3346,7 +4038,7 @@ const file_plugin_v1_automation_proto_rawDesc = "" +
 	"\x11ListImagesRequest\x12\x17\n" +
 	"\aline_id\x18\x01 \x01(\x03R\x06lineId\"F\n" +
 	"\x12ListImagesResponse\x120\n" +
-	"\x05items\x18\x01 \x03(\v2\x1a.plugin.v1.AutomationImageR\x05items\"\xfd\x02\n" +
+	"\x05items\x18\x01 \x03(\v2\x1a.plugin.v1.AutomationImageR\x05items\"\x92\x04\n" +
 	"\x15CreateInstanceRequest\x12\x17\n" +
 	"\aline_id\x18\x01 \x01(\x03R\x06lineId\x12\x1d\n" +
 	"\n" +
@@ -3362,7 +4054,11 @@ const file_plugin_v1_automation_proto_rawDesc = "" +
 	" \x01(\x05R\x03cpu\x12\x1b\n" +
 	"\tmemory_gb\x18\v \x01(\x05R\bmemoryGb\x12\x17\n" +
 	"\adisk_gb\x18\f \x01(\x05R\x06diskGb\x12%\n" +
-	"\x0ebandwidth_mbps\x18\r \x01(\x05R\rbandwidthMbps\"9\n" +
+	"\x0ebandwidth_mbps\x18\r \x01(\x05R\rbandwidthMbps\x12\x19\n" +
+	"\bssh_keys\x18\x0e \x03(\tR\asshKeys\x12\x1b\n" +
+	"\tuser_data\x18\x0f \x01(\tR\buserData\x12%\n" +
+	"\x0estartup_script\x18\x10 \x01(\tR\rstartupScript\x124\n" +
+	"\x16disable_password_login\x18\x11 \x01(\bR\x14disablePasswordLogin\"9\n" +
 	"\x16CreateInstanceResponse\x12\x1f\n" +
 	"\vinstance_id\x18\x01 \x01(\x03R\n" +
 	"instanceId\"5\n" +
@@ -3388,12 +4084,16 @@ const file_plugin_v1_automation_proto_rawDesc = "" +
 	"instanceId\"0\n" +
 	"\rRebootRequest\x12\x1f\n" +
 	"\vinstance_id\x18\x01 \x01(\x03R\n" +
-	"instanceId\"h\n" +
+	"instanceId\"\xfd\x01\n" +
 	"\x0eRebuildRequest\x12\x1f\n" +
 	"\vinstance_id\x18\x01 \x01(\x03R\n" +
 	"instanceId\x12\x19\n" +
 	"\bimage_id\x18\x02 \x01(\x03R\aimageId\x12\x1a\n" +
-	"\bpassword\x18\x03 \x01(\tR\bpassword\"S\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\x12\x19\n" +
+	"\bssh_keys\x18\x04 \x03(\tR\asshKeys\x12\x1b\n" +
+	"\tuser_data\x18\x05 \x01(\tR\buserData\x12%\n" +
+	"\x0estartup_script\x18\x06 \x01(\tR\rstartupScript\x124\n" +
+	"\x16disable_password_login\x18\a \x01(\bR\x14disablePasswordLogin\"S\n" +
 	"\x14ResetPasswordRequest\x12\x1f\n" +
 	"\vinstance_id\x18\x01 \x01(\x03R\n" +
 	"instanceId\x12\x1a\n" +
@@ -3537,7 +4237,47 @@ const file_plugin_v1_automation_proto_rawDesc = "" +
 	"\x19DeleteFirewallRuleRequest\x12\x1f\n" +
 	"\vinstance_id\x18\x01 \x01(\x03R\n" +
 	"instanceId\x12\x17\n" +
-	"\arule_id\x18\x02 \x01(\x03R\x06ruleId2\x9b\x15\n" +
+	"\arule_id\x18\x02 \x01(\x03R\x06ruleId\">\n" +
+	"\x1aAutomationReverseDNSRecord\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\x12\x10\n" +
+	"\x03ptr\x18\x02 \x01(\tR\x03ptr\"8\n" +
+	"\x15ListReverseDNSRequest\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\x03R\n" +
+	"instanceId\"U\n" +
+	"\x16ListReverseDNSResponse\x12;\n" +
+	"\x05items\x18\x01 \x03(\v2%.plugin.v1.AutomationReverseDNSRecordR\x05items\"Y\n" +
+	"\x14SetReverseDNSRequest\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\x03R\n" +
+	"instanceId\x12\x0e\n" +
+	"\x02ip\x18\x02 \x01(\tR\x02ip\x12\x10\n" +
+	"\x03ptr\x18\x03 \x01(\tR\x03ptr\"J\n" +
+	"\x0fAssignIPRequest\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\x03R\n" +
+	"instanceId\x12\x16\n" +
+	"\x06family\x18\x02 \x01(\tR\x06family\"\"\n" +
+	"\x10AssignIPResponse\x12\x0e\n" +
+	"\x02ip\x18\x01 \x01(\tR\x02ip\"C\n" +
+	"\x10ReleaseIPRequest\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\x03R\n" +
+	"instanceId\x12\x0e\n" +
+	"\x02ip\x18\x02 \x01(\tR\x02ip\"Q\n" +
+	"\x12EnterRescueRequest\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\x03R\n" +
+	"instanceId\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"M\n" +
+	"\x13EnterRescueResponse\x12\x1a\n" +
+	"\busername\x18\x01 \x01(\tR\busername\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"4\n" +
+	"\x11ExitRescueRequest\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\x03R\n" +
+	"instanceId\"D\n" +
+	"\x0fMountISORequest\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\x03R\n" +
+	"instanceId\x12\x10\n" +
+	"\x03iso\x18\x02 \x01(\tR\x03iso\"4\n" +
+	"\x11UnmountISORequest\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\x03R\n" +
+	"instanceId2\xed\x19\n" +
 	"\x11AutomationService\x12;\n" +
 	"\tListAreas\x12\x10.plugin.v1.Empty\x1a\x1c.plugin.v1.ListAreasResponse\x12;\n" +
 	"\tListLines\x12\x10.plugin.v1.Empty\x1a\x1c.plugin.v1.ListLinesResponse\x12O\n" +
@@ -3575,7 +4315,17 @@ const file_plugin_v1_automation_proto_rawDesc = "" +
 	"\x0fRestoreSnapshot\x12!.plugin.v1.RestoreSnapshotRequest\x1a\x1a.plugin.v1.OperationResult\x12^\n" +
 	"\x11ListFirewallRules\x12#.plugin.v1.ListFirewallRulesRequest\x1a$.plugin.v1.ListFirewallRulesResponse\x12P\n" +
 	"\x0fAddFirewallRule\x12!.plugin.v1.AddFirewallRuleRequest\x1a\x1a.plugin.v1.OperationResult\x12V\n" +
-	"\x12DeleteFirewallRule\x12$.plugin.v1.DeleteFirewallRuleRequest\x1a\x1a.plugin.v1.OperationResultB Z\x1exiaoheiplay/plugin/v1;pluginv1b\x06proto3"
+	"\x12DeleteFirewallRule\x12$.plugin.v1.DeleteFirewallRuleRequest\x1a\x1a.plugin.v1.OperationResult\x12U\n" +
+	"\x0eListReverseDNS\x12 .plugin.v1.ListReverseDNSRequest\x1a!.plugin.v1.ListReverseDNSResponse\x12L\n" +
+	"\rSetReverseDNS\x12\x1f.plugin.v1.SetReverseDNSRequest\x1a\x1a.plugin.v1.OperationResult\x12C\n" +
+	"\bAssignIP\x12\x1a.plugin.v1.AssignIPRequest\x1a\x1b.plugin.v1.AssignIPResponse\x12D\n" +
+	"\tReleaseIP\x12\x1b.plugin.v1.ReleaseIPRequest\x1a\x1a.plugin.v1.OperationResult\x12L\n" +
+	"\vEnterRescue\x12\x1d.plugin.v1.EnterRescueRequest\x1a\x1e.plugin.v1.EnterRescueResponse\x12F\n" +
+	"\n" +
+	"ExitRescue\x12\x1c.plugin.v1.ExitRescueRequest\x1a\x1a.plugin.v1.OperationResult\x12B\n" +
+	"\bMountISO\x12\x1a.plugin.v1.MountISORequest\x1a\x1a.plugin.v1.OperationResult\x12F\n" +
+	"\n" +
+	"UnmountISO\x12\x1c.plugin.v1.UnmountISORequest\x1a\x1a.plugin.v1.OperationResultB Z\x1exiaoheiplay/plugin/v1;pluginv1b\x06proto3"
 
 var (
 	file_plugin_v1_automation_proto_rawDescOnce sync.Once
@@ -3589,7 +4339,7 @@ func file_plugin_v1_automation_proto_rawDescGZIP() []byte {
 	return file_plugin_v1_automation_proto_rawDescData
 }
 
-var file_plugin_v1_automation_proto_msgTypes = make([]protoimpl.MessageInfo, 59)
+var file_plugin_v1_automation_proto_msgTypes = make([]protoimpl.MessageInfo, 71)
 var file_plugin_v1_automation_proto_goTypes = []any{
 	(*OperationResult)(nil),             // 0: plugin.v1.OperationResult
 	(*AutomationArea)(nil),              // 1: plugin.v1.AutomationArea
@@ -3650,7 +4400,19 @@ var file_plugin_v1_automation_proto_goTypes = []any{
 	(*ListFirewallRulesResponse)(nil),   // 56: plugin.v1.ListFirewallRulesResponse
 	(*AddFirewallRuleRequest)(nil),      // 57: plugin.v1.AddFirewallRuleRequest
 	(*DeleteFirewallRuleRequest)(nil),   // 58: plugin.v1.DeleteFirewallRuleRequest
-	(*Empty)(nil),                       // 59: plugin.v1.Empty
+	(*AutomationReverseDNSRecord)(nil),  // 59: plugin.v1.AutomationReverseDNSRecord
+	(*ListReverseDNSRequest)(nil),       // 60: plugin.v1.ListReverseDNSRequest
+	(*ListReverseDNSResponse)(nil),      // 61: plugin.v1.ListReverseDNSResponse
+	(*SetReverseDNSRequest)(nil),        // 62: plugin.v1.SetReverseDNSRequest
+	(*AssignIPRequest)(nil),             // 63: plugin.v1.AssignIPRequest
+	(*AssignIPResponse)(nil),            // 64: plugin.v1.AssignIPResponse
+	(*ReleaseIPRequest)(nil),            // 65: plugin.v1.ReleaseIPRequest
+	(*EnterRescueRequest)(nil),          // 66: plugin.v1.EnterRescueRequest
+	(*EnterRescueResponse)(nil),         // 67: plugin.v1.EnterRescueResponse
+	(*ExitRescueRequest)(nil),           // 68: plugin.v1.ExitRescueRequest
+	(*MountISORequest)(nil),             // 69: plugin.v1.MountISORequest
+	(*UnmountISORequest)(nil),           // 70: plugin.v1.UnmountISORequest
+	(*Empty)(nil),                       // 71: plugin.v1.Empty
 }
 var file_plugin_v1_automation_proto_depIdxs = []int32{
 	1,  // 0: plugin.v1.ListAreasResponse.items:type_name -> plugin.v1.AutomationArea
@@ -3663,81 +4425,98 @@ var file_plugin_v1_automation_proto_depIdxs = []int32{
 	42, // 7: plugin.v1.ListBackupsResponse.items:type_name -> plugin.v1.AutomationBackup
 	48, // 8: plugin.v1.ListSnapshotsResponse.items:type_name -> plugin.v1.AutomationSnapshot
 	54, // 9: plugin.v1.ListFirewallRulesResponse.items:type_name -> plugin.v1.AutomationFirewallRule
-	59, // 10: plugin.v1.AutomationService.ListAreas:input_type -> plugin.v1.Empty
-	59, // 11: plugin.v1.AutomationService.ListLines:input_type -> plugin.v1.Empty
-	8,  // 12: plugin.v1.AutomationService.ListPackages:input_type -> plugin.v1.ListPackagesRequest
-	10, // 13: plugin.v1.AutomationService.ListImages:input_type -> plugin.v1.ListImagesRequest
-	12, // 14: plugin.v1.AutomationService.CreateInstance:input_type -> plugin.v1.CreateInstanceRequest
-	14, // 15: plugin.v1.AutomationService.GetInstance:input_type -> plugin.v1.GetInstanceRequest
-	17, // 16: plugin.v1.AutomationService.ListInstancesSimple:input_type -> plugin.v1.ListInstancesSimpleRequest
-	19, // 17: plugin.v1.AutomationService.Start:input_type -> plugin.v1.StartRequest
-	20, // 18: plugin.v1.AutomationService.Shutdown:input_type -> plugin.v1.ShutdownRequest
-	21, // 19: plugin.v1.AutomationService.Reboot:input_type -> plugin.v1.RebootRequest
-	22, // 20: plugin.v1.AutomationService.Rebuild:input_type -> plugin.v1.RebuildRequest
-	23, // 21: plugin.v1.AutomationService.ResetPassword:input_type -> plugin.v1.ResetPasswordRequest
-	24, // 22: plugin.v1.AutomationService.ElasticUpdate:input_type -> plugin.v1.ElasticUpdateRequest
-	25, // 23: plugin.v1.AutomationService.Lock:input_type -> plugin.v1.LockRequest
-	26, // 24: plugin.v1.AutomationService.Unlock:input_type -> plugin.v1.UnlockRequest
-	27, // 25: plugin.v1.AutomationService.Renew:input_type -> plugin.v1.RenewRequest
-	28, // 26: plugin.v1.AutomationService.Destroy:input_type -> plugin.v1.DestroyRequest
-	29, // 27: plugin.v1.AutomationService.GetPanelURL:input_type -> plugin.v1.GetPanelURLRequest
-	31, // 28: plugin.v1.AutomationService.GetVNCURL:input_type -> plugin.v1.GetVNCURLRequest
-	33, // 29: plugin.v1.AutomationService.GetMonitor:input_type -> plugin.v1.GetMonitorRequest
-	36, // 30: plugin.v1.AutomationService.ListPortMappings:input_type -> plugin.v1.ListPortMappingsRequest
-	38, // 31: plugin.v1.AutomationService.AddPortMapping:input_type -> plugin.v1.AddPortMappingRequest
-	39, // 32: plugin.v1.AutomationService.DeletePortMapping:input_type -> plugin.v1.DeletePortMappingRequest
-	40, // 33: plugin.v1.AutomationService.FindPortCandidates:input_type -> plugin.v1.FindPortCandidatesRequest
-	43, // 34: plugin.v1.AutomationService.ListBackups:input_type -> plugin.v1.ListBackupsRequest
-	45, // 35: plugin.v1.AutomationService.CreateBackup:input_type -> plugin.v1.CreateBackupRequest
-	46, // 36: plugin.v1.AutomationService.DeleteBackup:input_type -> plugin.v1.DeleteBackupRequest
-	47, // 37: plugin.v1.AutomationService.RestoreBackup:input_type -> plugin.v1.RestoreBackupRequest
-	49, // 38: plugin.v1.AutomationService.ListSnapshots:input_type -> plugin.v1.ListSnapshotsRequest
-	51, // 39: plugin.v1.AutomationService.CreateSnapshot:input_type -> plugin.v1.CreateSnapshotRequest
-	52, // 40: plugin.v1.AutomationService.DeleteSnapshot:input_type -> plugin.v1.DeleteSnapshotRequest
-	53, // 41: plugin.v1.AutomationService.RestoreSnapshot:input_type -> plugin.v1.RestoreSnapshotRequest
-	55, // 42: plugin.v1.AutomationService.ListFirewallRules:input_type -> plugin.v1.ListFirewallRulesRequest
-	57, // 43: plugin.v1.AutomationService.AddFirewallRule:input_type -> plugin.v1.AddFirewallRuleRequest
-	58, // 44: plugin.v1.AutomationService.DeleteFirewallRule:input_type -> plugin.v1.DeleteFirewallRuleRequest
-	6,  // 45: plugin.v1.AutomationService.ListAreas:output_type -> plugin.v1.ListAreasResponse
-	7,  // 46: plugin.v1.AutomationService.ListLines:output_type -> plugin.v1.ListLinesResponse
-	9,  // 47: plugin.v1.AutomationService.ListPackages:output_type -> plugin.v1.ListPackagesResponse
-	11, // 48: plugin.v1.AutomationService.ListImages:output_type -> plugin.v1.ListImagesResponse
-	13, // 49: plugin.v1.AutomationService.CreateInstance:output_type -> plugin.v1.CreateInstanceResponse
-	15, // 50: plugin.v1.AutomationService.GetInstance:output_type -> plugin.v1.GetInstanceResponse
-	18, // 51: plugin.v1.AutomationService.ListInstancesSimple:output_type -> plugin.v1.ListInstancesSimpleResponse
-	0,  // 52: plugin.v1.AutomationService.Start:output_type -> plugin.v1.OperationResult
-	0,  // 53: plugin.v1.AutomationService.Shutdown:output_type -> plugin.v1.OperationResult
-	0,  // 54: plugin.v1.AutomationService.Reboot:output_type -> plugin.v1.OperationResult
-	0,  // 55: plugin.v1.AutomationService.Rebuild:output_type -> plugin.v1.OperationResult
-	0,  // 56: plugin.v1.AutomationService.ResetPassword:output_type -> plugin.v1.OperationResult
-	0,  // 57: plugin.v1.AutomationService.ElasticUpdate:output_type -> plugin.v1.OperationResult
-	0,  // 58: plugin.v1.AutomationService.Lock:output_type -> plugin.v1.OperationResult
-	0,  // 59: plugin.v1.AutomationService.Unlock:output_type -> plugin.v1.OperationResult
-	0,  // 60: plugin.v1.AutomationService.Renew:output_type -> plugin.v1.OperationResult
-	0,  // 61: plugin.v1.AutomationService.Destroy:output_type -> plugin.v1.OperationResult
-	30, // 62: plugin.v1.AutomationService.GetPanelURL:output_type -> plugin.v1.GetPanelURLResponse
-	32, // 63: plugin.v1.AutomationService.GetVNCURL:output_type -> plugin.v1.GetVNCURLResponse
-	34, // 64: plugin.v1.AutomationService.GetMonitor:output_type -> plugin.v1.GetMonitorResponse
-	37, // 65: plugin.v1.AutomationService.ListPortMappings:output_type -> plugin.v1.ListPortMappingsResponse
-	0,  // 66: plugin.v1.AutomationService.AddPortMapping:output_type -> plugin.v1.OperationResult
-	0,  // 67: plugin.v1.AutomationService.DeletePortMapping:output_type -> plugin.v1.OperationResult
-	41, // 68: plugin.v1.AutomationService.FindPortCandidates:output_type -> plugin.v1.FindPortCandidatesResponse
-	44, // 69: plugin.v1.AutomationService.ListBackups:output_type -> plugin.v1.ListBackupsResponse
-	0,  // 70: plugin.v1.AutomationService.CreateBackup:output_type -> plugin.v1.OperationResult
-	0,  // 71: plugin.v1.AutomationService.DeleteBackup:output_type -> plugin.v1.OperationResult
-	0,  // 72: plugin.v1.AutomationService.RestoreBackup:output_type -> plugin.v1.OperationResult
-	50, // 73: plugin.v1.AutomationService.ListSnapshots:output_type -> plugin.v1.ListSnapshotsResponse
-	0,  // 74: plugin.v1.AutomationService.CreateSnapshot:output_type -> plugin.v1.OperationResult
-	0,  // 75: plugin.v1.AutomationService.DeleteSnapshot:output_type -> plugin.v1.OperationResult
-	0,  // 76: plugin.v1.AutomationService.RestoreSnapshot:output_type -> plugin.v1.OperationResult
-	56, // 77: plugin.v1.AutomationService.ListFirewallRules:output_type -> plugin.v1.ListFirewallRulesResponse
-	0,  // 78: plugin.v1.AutomationService.AddFirewallRule:output_type -> plugin.v1.OperationResult
-	0,  // 79: plugin.v1.AutomationService.DeleteFirewallRule:output_type -> plugin.v1.OperationResult
-	45, // [45:80] is the sub-list for method output_type
-	10, // [10:45] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	59, // 10: plugin.v1.ListReverseDNSResponse.items:type_name -> plugin.v1.AutomationReverseDNSRecord
+	71, // 11: plugin.v1.AutomationService.ListAreas:input_type -> plugin.v1.Empty
+	71, // 12: plugin.v1.AutomationService.ListLines:input_type -> plugin.v1.Empty
+	8,  // 13: plugin.v1.AutomationService.ListPackages:input_type -> plugin.v1.ListPackagesRequest
+	10, // 14: plugin.v1.AutomationService.ListImages:input_type -> plugin.v1.ListImagesRequest
+	12, // 15: plugin.v1.AutomationService.CreateInstance:input_type -> plugin.v1.CreateInstanceRequest
+	14, // 16: plugin.v1.AutomationService.GetInstance:input_type -> plugin.v1.GetInstanceRequest
+	17, // 17: plugin.v1.AutomationService.ListInstancesSimple:input_type -> plugin.v1.ListInstancesSimpleRequest
+	19, // 18: plugin.v1.AutomationService.Start:input_type -> plugin.v1.StartRequest
+	20, // 19: plugin.v1.AutomationService.Shutdown:input_type -> plugin.v1.ShutdownRequest
+	21, // 20: plugin.v1.AutomationService.Reboot:input_type -> plugin.v1.RebootRequest
+	22, // 21: plugin.v1.AutomationService.Rebuild:input_type -> plugin.v1.RebuildRequest
+	23, // 22: plugin.v1.AutomationService.ResetPassword:input_type -> plugin.v1.ResetPasswordRequest
+	24, // 23: plugin.v1.AutomationService.ElasticUpdate:input_type -> plugin.v1.ElasticUpdateRequest
+	25, // 24: plugin.v1.AutomationService.Lock:input_type -> plugin.v1.LockRequest
+	26, // 25: plugin.v1.AutomationService.Unlock:input_type -> plugin.v1.UnlockRequest
+	27, // 26: plugin.v1.AutomationService.Renew:input_type -> plugin.v1.RenewRequest
+	28, // 27: plugin.v1.AutomationService.Destroy:input_type -> plugin.v1.DestroyRequest
+	29, // 28: plugin.v1.AutomationService.GetPanelURL:input_type -> plugin.v1.GetPanelURLRequest
+	31, // 29: plugin.v1.AutomationService.GetVNCURL:input_type -> plugin.v1.GetVNCURLRequest
+	33, // 30: plugin.v1.AutomationService.GetMonitor:input_type -> plugin.v1.GetMonitorRequest
+	36, // 31: plugin.v1.AutomationService.ListPortMappings:input_type -> plugin.v1.ListPortMappingsRequest
+	38, // 32: plugin.v1.AutomationService.AddPortMapping:input_type -> plugin.v1.AddPortMappingRequest
+	39, // 33: plugin.v1.AutomationService.DeletePortMapping:input_type -> plugin.v1.DeletePortMappingRequest
+	40, // 34: plugin.v1.AutomationService.FindPortCandidates:input_type -> plugin.v1.FindPortCandidatesRequest
+	43, // 35: plugin.v1.AutomationService.ListBackups:input_type -> plugin.v1.ListBackupsRequest
+	45, // 36: plugin.v1.AutomationService.CreateBackup:input_type -> plugin.v1.CreateBackupRequest
+	46, // 37: plugin.v1.AutomationService.DeleteBackup:input_type -> plugin.v1.DeleteBackupRequest
+	47, // 38: plugin.v1.AutomationService.RestoreBackup:input_type -> plugin.v1.RestoreBackupRequest
+	49, // 39: plugin.v1.AutomationService.ListSnapshots:input_type -> plugin.v1.ListSnapshotsRequest
+	51, // 40: plugin.v1.AutomationService.CreateSnapshot:input_type -> plugin.v1.CreateSnapshotRequest
+	52, // 41: plugin.v1.AutomationService.DeleteSnapshot:input_type -> plugin.v1.DeleteSnapshotRequest
+	53, // 42: plugin.v1.AutomationService.RestoreSnapshot:input_type -> plugin.v1.RestoreSnapshotRequest
+	55, // 43: plugin.v1.AutomationService.ListFirewallRules:input_type -> plugin.v1.ListFirewallRulesRequest
+	57, // 44: plugin.v1.AutomationService.AddFirewallRule:input_type -> plugin.v1.AddFirewallRuleRequest
+	58, // 45: plugin.v1.AutomationService.DeleteFirewallRule:input_type -> plugin.v1.DeleteFirewallRuleRequest
+	60, // 46: plugin.v1.AutomationService.ListReverseDNS:input_type -> plugin.v1.ListReverseDNSRequest
+	62, // 47: plugin.v1.AutomationService.SetReverseDNS:input_type -> plugin.v1.SetReverseDNSRequest
+	63, // 48: plugin.v1.AutomationService.AssignIP:input_type -> plugin.v1.AssignIPRequest
+	65, // 49: plugin.v1.AutomationService.ReleaseIP:input_type -> plugin.v1.ReleaseIPRequest
+	66, // 50: plugin.v1.AutomationService.EnterRescue:input_type -> plugin.v1.EnterRescueRequest
+	68, // 51: plugin.v1.AutomationService.ExitRescue:input_type -> plugin.v1.ExitRescueRequest
+	69, // 52: plugin.v1.AutomationService.MountISO:input_type -> plugin.v1.MountISORequest
+	70, // 53: plugin.v1.AutomationService.UnmountISO:input_type -> plugin.v1.UnmountISORequest
+	6,  // 54: plugin.v1.AutomationService.ListAreas:output_type -> plugin.v1.ListAreasResponse
+	7,  // 55: plugin.v1.AutomationService.ListLines:output_type -> plugin.v1.ListLinesResponse
+	9,  // 56: plugin.v1.AutomationService.ListPackages:output_type -> plugin.v1.ListPackagesResponse
+	11, // 57: plugin.v1.AutomationService.ListImages:output_type -> plugin.v1.ListImagesResponse
+	13, // 58: plugin.v1.AutomationService.CreateInstance:output_type -> plugin.v1.CreateInstanceResponse
+	15, // 59: plugin.v1.AutomationService.GetInstance:output_type -> plugin.v1.GetInstanceResponse
+	18, // 60: plugin.v1.AutomationService.ListInstancesSimple:output_type -> plugin.v1.ListInstancesSimpleResponse
+	0,  // 61: plugin.v1.AutomationService.Start:output_type -> plugin.v1.OperationResult
+	0,  // 62: plugin.v1.AutomationService.Shutdown:output_type -> plugin.v1.OperationResult
+	0,  // 63: plugin.v1.AutomationService.Reboot:output_type -> plugin.v1.OperationResult
+	0,  // 64: plugin.v1.AutomationService.Rebuild:output_type -> plugin.v1.OperationResult
+	0,  // 65: plugin.v1.AutomationService.ResetPassword:output_type -> plugin.v1.OperationResult
+	0,  // 66: plugin.v1.AutomationService.ElasticUpdate:output_type -> plugin.v1.OperationResult
+	0,  // 67: plugin.v1.AutomationService.Lock:output_type -> plugin.v1.OperationResult
+	0,  // 68: plugin.v1.AutomationService.Unlock:output_type -> plugin.v1.OperationResult
+	0,  // 69: plugin.v1.AutomationService.Renew:output_type -> plugin.v1.OperationResult
+	0,  // 70: plugin.v1.AutomationService.Destroy:output_type -> plugin.v1.OperationResult
+	30, // 71: plugin.v1.AutomationService.GetPanelURL:output_type -> plugin.v1.GetPanelURLResponse
+	32, // 72: plugin.v1.AutomationService.GetVNCURL:output_type -> plugin.v1.GetVNCURLResponse
+	34, // 73: plugin.v1.AutomationService.GetMonitor:output_type -> plugin.v1.GetMonitorResponse
+	37, // 74: plugin.v1.AutomationService.ListPortMappings:output_type -> plugin.v1.ListPortMappingsResponse
+	0,  // 75: plugin.v1.AutomationService.AddPortMapping:output_type -> plugin.v1.OperationResult
+	0,  // 76: plugin.v1.AutomationService.DeletePortMapping:output_type -> plugin.v1.OperationResult
+	41, // 77: plugin.v1.AutomationService.FindPortCandidates:output_type -> plugin.v1.FindPortCandidatesResponse
+	44, // 78: plugin.v1.AutomationService.ListBackups:output_type -> plugin.v1.ListBackupsResponse
+	0,  // 79: plugin.v1.AutomationService.CreateBackup:output_type -> plugin.v1.OperationResult
+	0,  // 80: plugin.v1.AutomationService.DeleteBackup:output_type -> plugin.v1.OperationResult
+	0,  // 81: plugin.v1.AutomationService.RestoreBackup:output_type -> plugin.v1.OperationResult
+	50, // 82: plugin.v1.AutomationService.ListSnapshots:output_type -> plugin.v1.ListSnapshotsResponse
+	0,  // 83: plugin.v1.AutomationService.CreateSnapshot:output_type -> plugin.v1.OperationResult
+	0,  // 84: plugin.v1.AutomationService.DeleteSnapshot:output_type -> plugin.v1.OperationResult
+	0,  // 85: plugin.v1.AutomationService.RestoreSnapshot:output_type -> plugin.v1.OperationResult
+	56, // 86: plugin.v1.AutomationService.ListFirewallRules:output_type -> plugin.v1.ListFirewallRulesResponse
+	0,  // 87: plugin.v1.AutomationService.AddFirewallRule:output_type -> plugin.v1.OperationResult
+	0,  // 88: plugin.v1.AutomationService.DeleteFirewallRule:output_type -> plugin.v1.OperationResult
+	61, // 89: plugin.v1.AutomationService.ListReverseDNS:output_type -> plugin.v1.ListReverseDNSResponse
+	0,  // 90: plugin.v1.AutomationService.SetReverseDNS:output_type -> plugin.v1.OperationResult
+	64, // 91: plugin.v1.AutomationService.AssignIP:output_type -> plugin.v1.AssignIPResponse
+	0,  // 92: plugin.v1.AutomationService.ReleaseIP:output_type -> plugin.v1.OperationResult
+	67, // 93: plugin.v1.AutomationService.EnterRescue:output_type -> plugin.v1.EnterRescueResponse
+	0,  // 94: plugin.v1.AutomationService.ExitRescue:output_type -> plugin.v1.OperationResult
+	0,  // 95: plugin.v1.AutomationService.MountISO:output_type -> plugin.v1.OperationResult
+	0,  // 96: plugin.v1.AutomationService.UnmountISO:output_type -> plugin.v1.OperationResult
+	54, // [54:97] is the sub-list for method output_type
+	11, // [11:54] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_plugin_v1_automation_proto_init() }
@@ -3747,6 +4526,7 @@ func file_plugin_v1_automation_proto_init() {
 	}
 	file_plugin_v1_types_proto_init()
 	file_plugin_v1_automation_proto_msgTypes[3].OneofWrappers = []any{}
+	file_plugin_v1_automation_proto_msgTypes[5].OneofWrappers = []any{}
 	file_plugin_v1_automation_proto_msgTypes[24].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -3754,7 +4534,7 @@ func file_plugin_v1_automation_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_plugin_v1_automation_proto_rawDesc), len(file_plugin_v1_automation_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   59,
+			NumMessages:   71,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
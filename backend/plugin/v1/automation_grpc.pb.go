@@ -54,6 +54,14 @@ const (
 	AutomationService_ListFirewallRules_FullMethodName   = "/plugin.v1.AutomationService/ListFirewallRules"
 	AutomationService_AddFirewallRule_FullMethodName     = "/plugin.v1.AutomationService/AddFirewallRule"
 	AutomationService_DeleteFirewallRule_FullMethodName  = "/plugin.v1.AutomationService/DeleteFirewallRule"
+	AutomationService_ListReverseDNS_FullMethodName      = "/plugin.v1.AutomationService/ListReverseDNS"
+	AutomationService_SetReverseDNS_FullMethodName       = "/plugin.v1.AutomationService/SetReverseDNS"
+	AutomationService_AssignIP_FullMethodName            = "/plugin.v1.AutomationService/AssignIP"
+	AutomationService_ReleaseIP_FullMethodName           = "/plugin.v1.AutomationService/ReleaseIP"
+	AutomationService_EnterRescue_FullMethodName         = "/plugin.v1.AutomationService/EnterRescue"
+	AutomationService_ExitRescue_FullMethodName          = "/plugin.v1.AutomationService/ExitRescue"
+	AutomationService_MountISO_FullMethodName            = "/plugin.v1.AutomationService/MountISO"
+	AutomationService_UnmountISO_FullMethodName          = "/plugin.v1.AutomationService/UnmountISO"
 )
 
 // AutomationServiceClient is the client API for AutomationService service.
@@ -101,6 +109,17 @@ type AutomationServiceClient interface {
 	ListFirewallRules(ctx context.Context, in *ListFirewallRulesRequest, opts ...grpc.CallOption) (*ListFirewallRulesResponse, error)
 	AddFirewallRule(ctx context.Context, in *AddFirewallRuleRequest, opts ...grpc.CallOption) (*OperationResult, error)
 	DeleteFirewallRule(ctx context.Context, in *DeleteFirewallRuleRequest, opts ...grpc.CallOption) (*OperationResult, error)
+	// Reverse DNS (optional)
+	ListReverseDNS(ctx context.Context, in *ListReverseDNSRequest, opts ...grpc.CallOption) (*ListReverseDNSResponse, error)
+	SetReverseDNS(ctx context.Context, in *SetReverseDNSRequest, opts ...grpc.CallOption) (*OperationResult, error)
+	// Extra IP addresses (optional)
+	AssignIP(ctx context.Context, in *AssignIPRequest, opts ...grpc.CallOption) (*AssignIPResponse, error)
+	ReleaseIP(ctx context.Context, in *ReleaseIPRequest, opts ...grpc.CallOption) (*OperationResult, error)
+	// Rescue boot and ISO media (optional)
+	EnterRescue(ctx context.Context, in *EnterRescueRequest, opts ...grpc.CallOption) (*EnterRescueResponse, error)
+	ExitRescue(ctx context.Context, in *ExitRescueRequest, opts ...grpc.CallOption) (*OperationResult, error)
+	MountISO(ctx context.Context, in *MountISORequest, opts ...grpc.CallOption) (*OperationResult, error)
+	UnmountISO(ctx context.Context, in *UnmountISORequest, opts ...grpc.CallOption) (*OperationResult, error)
 }
 
 type automationServiceClient struct {
@@ -461,6 +480,86 @@ func (c *automationServiceClient) DeleteFirewallRule(ctx context.Context, in *De
 	return out, nil
 }
 
+func (c *automationServiceClient) ListReverseDNS(ctx context.Context, in *ListReverseDNSRequest, opts ...grpc.CallOption) (*ListReverseDNSResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListReverseDNSResponse)
+	err := c.cc.Invoke(ctx, AutomationService_ListReverseDNS_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *automationServiceClient) SetReverseDNS(ctx context.Context, in *SetReverseDNSRequest, opts ...grpc.CallOption) (*OperationResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OperationResult)
+	err := c.cc.Invoke(ctx, AutomationService_SetReverseDNS_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *automationServiceClient) AssignIP(ctx context.Context, in *AssignIPRequest, opts ...grpc.CallOption) (*AssignIPResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AssignIPResponse)
+	err := c.cc.Invoke(ctx, AutomationService_AssignIP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *automationServiceClient) ReleaseIP(ctx context.Context, in *ReleaseIPRequest, opts ...grpc.CallOption) (*OperationResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OperationResult)
+	err := c.cc.Invoke(ctx, AutomationService_ReleaseIP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *automationServiceClient) EnterRescue(ctx context.Context, in *EnterRescueRequest, opts ...grpc.CallOption) (*EnterRescueResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EnterRescueResponse)
+	err := c.cc.Invoke(ctx, AutomationService_EnterRescue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *automationServiceClient) ExitRescue(ctx context.Context, in *ExitRescueRequest, opts ...grpc.CallOption) (*OperationResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OperationResult)
+	err := c.cc.Invoke(ctx, AutomationService_ExitRescue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *automationServiceClient) MountISO(ctx context.Context, in *MountISORequest, opts ...grpc.CallOption) (*OperationResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OperationResult)
+	err := c.cc.Invoke(ctx, AutomationService_MountISO_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *automationServiceClient) UnmountISO(ctx context.Context, in *UnmountISORequest, opts ...grpc.CallOption) (*OperationResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OperationResult)
+	err := c.cc.Invoke(ctx, AutomationService_UnmountISO_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AutomationServiceServer is the server API for AutomationService service.
 // All implementations must embed UnimplementedAutomationServiceServer
 // for forward compatibility.
@@ -506,6 +605,17 @@ type AutomationServiceServer interface {
 	ListFirewallRules(context.Context, *ListFirewallRulesRequest) (*ListFirewallRulesResponse, error)
 	AddFirewallRule(context.Context, *AddFirewallRuleRequest) (*OperationResult, error)
 	DeleteFirewallRule(context.Context, *DeleteFirewallRuleRequest) (*OperationResult, error)
+	// Reverse DNS (optional)
+	ListReverseDNS(context.Context, *ListReverseDNSRequest) (*ListReverseDNSResponse, error)
+	SetReverseDNS(context.Context, *SetReverseDNSRequest) (*OperationResult, error)
+	// Extra IP addresses (optional)
+	AssignIP(context.Context, *AssignIPRequest) (*AssignIPResponse, error)
+	ReleaseIP(context.Context, *ReleaseIPRequest) (*OperationResult, error)
+	// Rescue boot and ISO media (optional)
+	EnterRescue(context.Context, *EnterRescueRequest) (*EnterRescueResponse, error)
+	ExitRescue(context.Context, *ExitRescueRequest) (*OperationResult, error)
+	MountISO(context.Context, *MountISORequest) (*OperationResult, error)
+	UnmountISO(context.Context, *UnmountISORequest) (*OperationResult, error)
 	mustEmbedUnimplementedAutomationServiceServer()
 }
 
@@ -621,6 +731,30 @@ func (UnimplementedAutomationServiceServer) AddFirewallRule(context.Context, *Ad
 func (UnimplementedAutomationServiceServer) DeleteFirewallRule(context.Context, *DeleteFirewallRuleRequest) (*OperationResult, error) {
 	return nil, status.Error(codes.Unimplemented, "method DeleteFirewallRule not implemented")
 }
+func (UnimplementedAutomationServiceServer) ListReverseDNS(context.Context, *ListReverseDNSRequest) (*ListReverseDNSResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListReverseDNS not implemented")
+}
+func (UnimplementedAutomationServiceServer) SetReverseDNS(context.Context, *SetReverseDNSRequest) (*OperationResult, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetReverseDNS not implemented")
+}
+func (UnimplementedAutomationServiceServer) AssignIP(context.Context, *AssignIPRequest) (*AssignIPResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AssignIP not implemented")
+}
+func (UnimplementedAutomationServiceServer) ReleaseIP(context.Context, *ReleaseIPRequest) (*OperationResult, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReleaseIP not implemented")
+}
+func (UnimplementedAutomationServiceServer) EnterRescue(context.Context, *EnterRescueRequest) (*EnterRescueResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method EnterRescue not implemented")
+}
+func (UnimplementedAutomationServiceServer) ExitRescue(context.Context, *ExitRescueRequest) (*OperationResult, error) {
+	return nil, status.Error(codes.Unimplemented, "method ExitRescue not implemented")
+}
+func (UnimplementedAutomationServiceServer) MountISO(context.Context, *MountISORequest) (*OperationResult, error) {
+	return nil, status.Error(codes.Unimplemented, "method MountISO not implemented")
+}
+func (UnimplementedAutomationServiceServer) UnmountISO(context.Context, *UnmountISORequest) (*OperationResult, error) {
+	return nil, status.Error(codes.Unimplemented, "method UnmountISO not implemented")
+}
 func (UnimplementedAutomationServiceServer) mustEmbedUnimplementedAutomationServiceServer() {}
 func (UnimplementedAutomationServiceServer) testEmbeddedByValue()                           {}
 
@@ -1272,6 +1406,150 @@ func _AutomationService_DeleteFirewallRule_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AutomationService_ListReverseDNS_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReverseDNSRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AutomationServiceServer).ListReverseDNS(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AutomationService_ListReverseDNS_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AutomationServiceServer).ListReverseDNS(ctx, req.(*ListReverseDNSRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AutomationService_SetReverseDNS_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetReverseDNSRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AutomationServiceServer).SetReverseDNS(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AutomationService_SetReverseDNS_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AutomationServiceServer).SetReverseDNS(ctx, req.(*SetReverseDNSRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AutomationService_AssignIP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignIPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AutomationServiceServer).AssignIP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AutomationService_AssignIP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AutomationServiceServer).AssignIP(ctx, req.(*AssignIPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AutomationService_ReleaseIP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseIPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AutomationServiceServer).ReleaseIP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AutomationService_ReleaseIP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AutomationServiceServer).ReleaseIP(ctx, req.(*ReleaseIPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AutomationService_EnterRescue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnterRescueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AutomationServiceServer).EnterRescue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AutomationService_EnterRescue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AutomationServiceServer).EnterRescue(ctx, req.(*EnterRescueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AutomationService_ExitRescue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExitRescueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AutomationServiceServer).ExitRescue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AutomationService_ExitRescue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AutomationServiceServer).ExitRescue(ctx, req.(*ExitRescueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AutomationService_MountISO_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MountISORequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AutomationServiceServer).MountISO(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AutomationService_MountISO_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AutomationServiceServer).MountISO(ctx, req.(*MountISORequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AutomationService_UnmountISO_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnmountISORequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AutomationServiceServer).UnmountISO(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AutomationService_UnmountISO_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AutomationServiceServer).UnmountISO(ctx, req.(*UnmountISORequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AutomationService_ServiceDesc is the grpc.ServiceDesc for AutomationService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1419,6 +1697,38 @@ var AutomationService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteFirewallRule",
 			Handler:    _AutomationService_DeleteFirewallRule_Handler,
 		},
+		{
+			MethodName: "ListReverseDNS",
+			Handler:    _AutomationService_ListReverseDNS_Handler,
+		},
+		{
+			MethodName: "SetReverseDNS",
+			Handler:    _AutomationService_SetReverseDNS_Handler,
+		},
+		{
+			MethodName: "AssignIP",
+			Handler:    _AutomationService_AssignIP_Handler,
+		},
+		{
+			MethodName: "ReleaseIP",
+			Handler:    _AutomationService_ReleaseIP_Handler,
+		},
+		{
+			MethodName: "EnterRescue",
+			Handler:    _AutomationService_EnterRescue_Handler,
+		},
+		{
+			MethodName: "ExitRescue",
+			Handler:    _AutomationService_ExitRescue_Handler,
+		},
+		{
+			MethodName: "MountISO",
+			Handler:    _AutomationService_MountISO_Handler,
+		},
+		{
+			MethodName: "UnmountISO",
+			Handler:    _AutomationService_UnmountISO_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "plugin/v1/automation.proto",
@@ -0,0 +1,369 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.0
+// source: plugin/v1/captcha.proto
+
+package pluginv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CaptchaInitRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// register, login, password_reset or order_submit.
+	Scene         string `protobuf:"bytes,1,opt,name=scene,proto3" json:"scene,omitempty"`
+	RemoteIp      string `protobuf:"bytes,2,opt,name=remote_ip,json=remoteIp,proto3" json:"remote_ip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CaptchaInitRequest) Reset() {
+	*x = CaptchaInitRequest{}
+	mi := &file_plugin_v1_captcha_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CaptchaInitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CaptchaInitRequest) ProtoMessage() {}
+
+func (x *CaptchaInitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_captcha_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CaptchaInitRequest.ProtoReflect.Descriptor instead.
+func (*CaptchaInitRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_captcha_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CaptchaInitRequest) GetScene() string {
+	if x != nil {
+		return x.Scene
+	}
+	return ""
+}
+
+func (x *CaptchaInitRequest) GetRemoteIp() string {
+	if x != nil {
+		return x.RemoteIp
+	}
+	return ""
+}
+
+type CaptchaInitResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Ok    bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	Error string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	// Front-end widget to load, e.g. geetest_v4 or hcaptcha. Empty falls back
+	// to the widget declared in the manifest.
+	Widget string `protobuf:"bytes,3,opt,name=widget,proto3" json:"widget,omitempty"`
+	// Public widget parameters such as the site key or a challenge ID. Never
+	// put secrets here; the map is sent to browsers as is.
+	Params        map[string]string `protobuf:"bytes,4,rep,name=params,proto3" json:"params,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CaptchaInitResponse) Reset() {
+	*x = CaptchaInitResponse{}
+	mi := &file_plugin_v1_captcha_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CaptchaInitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CaptchaInitResponse) ProtoMessage() {}
+
+func (x *CaptchaInitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_captcha_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CaptchaInitResponse.ProtoReflect.Descriptor instead.
+func (*CaptchaInitResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_captcha_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CaptchaInitResponse) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *CaptchaInitResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *CaptchaInitResponse) GetWidget() string {
+	if x != nil {
+		return x.Widget
+	}
+	return ""
+}
+
+func (x *CaptchaInitResponse) GetParams() map[string]string {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+type CaptchaVerifyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Scene string                 `protobuf:"bytes,1,opt,name=scene,proto3" json:"scene,omitempty"`
+	// Primary token produced by the widget.
+	Token string `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	// Any extra widget output, e.g. lot_number and gen_time for Geetest v4.
+	Params        map[string]string `protobuf:"bytes,3,rep,name=params,proto3" json:"params,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	RemoteIp      string            `protobuf:"bytes,4,opt,name=remote_ip,json=remoteIp,proto3" json:"remote_ip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CaptchaVerifyRequest) Reset() {
+	*x = CaptchaVerifyRequest{}
+	mi := &file_plugin_v1_captcha_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CaptchaVerifyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CaptchaVerifyRequest) ProtoMessage() {}
+
+func (x *CaptchaVerifyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_captcha_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CaptchaVerifyRequest.ProtoReflect.Descriptor instead.
+func (*CaptchaVerifyRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_captcha_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CaptchaVerifyRequest) GetScene() string {
+	if x != nil {
+		return x.Scene
+	}
+	return ""
+}
+
+func (x *CaptchaVerifyRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *CaptchaVerifyRequest) GetParams() map[string]string {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *CaptchaVerifyRequest) GetRemoteIp() string {
+	if x != nil {
+		return x.RemoteIp
+	}
+	return ""
+}
+
+type CaptchaVerifyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ok            bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	ErrorCode     string                 `protobuf:"bytes,3,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CaptchaVerifyResponse) Reset() {
+	*x = CaptchaVerifyResponse{}
+	mi := &file_plugin_v1_captcha_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CaptchaVerifyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CaptchaVerifyResponse) ProtoMessage() {}
+
+func (x *CaptchaVerifyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_captcha_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CaptchaVerifyResponse.ProtoReflect.Descriptor instead.
+func (*CaptchaVerifyResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_captcha_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CaptchaVerifyResponse) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *CaptchaVerifyResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *CaptchaVerifyResponse) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+var File_plugin_v1_captcha_proto protoreflect.FileDescriptor
+
+const file_plugin_v1_captcha_proto_rawDesc = "" +
+	"\n" +
+	"\x17plugin/v1/captcha.proto\x12\tplugin.v1\"G\n" +
+	"\x12CaptchaInitRequest\x12\x14\n" +
+	"\x05scene\x18\x01 \x01(\tR\x05scene\x12\x1b\n" +
+	"\tremote_ip\x18\x02 \x01(\tR\bremoteIp\"\xd2\x01\n" +
+	"\x13CaptchaInitResponse\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x12\x16\n" +
+	"\x06widget\x18\x03 \x01(\tR\x06widget\x12B\n" +
+	"\x06params\x18\x04 \x03(\v2*.plugin.v1.CaptchaInitResponse.ParamsEntryR\x06params\x1a9\n" +
+	"\vParamsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xdf\x01\n" +
+	"\x14CaptchaVerifyRequest\x12\x14\n" +
+	"\x05scene\x18\x01 \x01(\tR\x05scene\x12\x14\n" +
+	"\x05token\x18\x02 \x01(\tR\x05token\x12C\n" +
+	"\x06params\x18\x03 \x03(\v2+.plugin.v1.CaptchaVerifyRequest.ParamsEntryR\x06params\x12\x1b\n" +
+	"\tremote_ip\x18\x04 \x01(\tR\bremoteIp\x1a9\n" +
+	"\vParamsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\\\n" +
+	"\x15CaptchaVerifyResponse\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\x03 \x01(\tR\terrorCode2\xa4\x01\n" +
+	"\x0eCaptchaService\x12E\n" +
+	"\x04Init\x12\x1d.plugin.v1.CaptchaInitRequest\x1a\x1e.plugin.v1.CaptchaInitResponse\x12K\n" +
+	"\x06Verify\x12\x1f.plugin.v1.CaptchaVerifyRequest\x1a .plugin.v1.CaptchaVerifyResponseB Z\x1exiaoheiplay/plugin/v1;pluginv1b\x06proto3"
+
+var (
+	file_plugin_v1_captcha_proto_rawDescOnce sync.Once
+	file_plugin_v1_captcha_proto_rawDescData []byte
+)
+
+func file_plugin_v1_captcha_proto_rawDescGZIP() []byte {
+	file_plugin_v1_captcha_proto_rawDescOnce.Do(func() {
+		file_plugin_v1_captcha_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_plugin_v1_captcha_proto_rawDesc), len(file_plugin_v1_captcha_proto_rawDesc)))
+	})
+	return file_plugin_v1_captcha_proto_rawDescData
+}
+
+var file_plugin_v1_captcha_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_plugin_v1_captcha_proto_goTypes = []any{
+	(*CaptchaInitRequest)(nil),    // 0: plugin.v1.CaptchaInitRequest
+	(*CaptchaInitResponse)(nil),   // 1: plugin.v1.CaptchaInitResponse
+	(*CaptchaVerifyRequest)(nil),  // 2: plugin.v1.CaptchaVerifyRequest
+	(*CaptchaVerifyResponse)(nil), // 3: plugin.v1.CaptchaVerifyResponse
+	nil,                           // 4: plugin.v1.CaptchaInitResponse.ParamsEntry
+	nil,                           // 5: plugin.v1.CaptchaVerifyRequest.ParamsEntry
+}
+var file_plugin_v1_captcha_proto_depIdxs = []int32{
+	4, // 0: plugin.v1.CaptchaInitResponse.params:type_name -> plugin.v1.CaptchaInitResponse.ParamsEntry
+	5, // 1: plugin.v1.CaptchaVerifyRequest.params:type_name -> plugin.v1.CaptchaVerifyRequest.ParamsEntry
+	0, // 2: plugin.v1.CaptchaService.Init:input_type -> plugin.v1.CaptchaInitRequest
+	2, // 3: plugin.v1.CaptchaService.Verify:input_type -> plugin.v1.CaptchaVerifyRequest
+	1, // 4: plugin.v1.CaptchaService.Init:output_type -> plugin.v1.CaptchaInitResponse
+	3, // 5: plugin.v1.CaptchaService.Verify:output_type -> plugin.v1.CaptchaVerifyResponse
+	4, // [4:6] is the sub-list for method output_type
+	2, // [2:4] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_plugin_v1_captcha_proto_init() }
+func file_plugin_v1_captcha_proto_init() {
+	if File_plugin_v1_captcha_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_plugin_v1_captcha_proto_rawDesc), len(file_plugin_v1_captcha_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_plugin_v1_captcha_proto_goTypes,
+		DependencyIndexes: file_plugin_v1_captcha_proto_depIdxs,
+		MessageInfos:      file_plugin_v1_captcha_proto_msgTypes,
+	}.Build()
+	File_plugin_v1_captcha_proto = out.File
+	file_plugin_v1_captcha_proto_goTypes = nil
+	file_plugin_v1_captcha_proto_depIdxs = nil
+}
@@ -0,0 +1,167 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.0
+// - protoc             v6.33.0
+// source: plugin/v1/captcha.proto
+
+package pluginv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CaptchaService_Init_FullMethodName   = "/plugin.v1.CaptchaService/Init"
+	CaptchaService_Verify_FullMethodName = "/plugin.v1.CaptchaService/Verify"
+)
+
+// CaptchaServiceClient is the client API for CaptchaService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// CaptchaService serves third-party human verification (Geetest, Tencent
+// Captcha, hCaptcha and the like). The host calls Init to render the widget
+// on the front end and Verify to check the token the widget produced.
+type CaptchaServiceClient interface {
+	Init(ctx context.Context, in *CaptchaInitRequest, opts ...grpc.CallOption) (*CaptchaInitResponse, error)
+	Verify(ctx context.Context, in *CaptchaVerifyRequest, opts ...grpc.CallOption) (*CaptchaVerifyResponse, error)
+}
+
+type captchaServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCaptchaServiceClient(cc grpc.ClientConnInterface) CaptchaServiceClient {
+	return &captchaServiceClient{cc}
+}
+
+func (c *captchaServiceClient) Init(ctx context.Context, in *CaptchaInitRequest, opts ...grpc.CallOption) (*CaptchaInitResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CaptchaInitResponse)
+	err := c.cc.Invoke(ctx, CaptchaService_Init_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *captchaServiceClient) Verify(ctx context.Context, in *CaptchaVerifyRequest, opts ...grpc.CallOption) (*CaptchaVerifyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CaptchaVerifyResponse)
+	err := c.cc.Invoke(ctx, CaptchaService_Verify_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CaptchaServiceServer is the server API for CaptchaService service.
+// All implementations must embed UnimplementedCaptchaServiceServer
+// for forward compatibility.
+//
+// CaptchaService serves third-party human verification (Geetest, Tencent
+// Captcha, hCaptcha and the like). The host calls Init to render the widget
+// on the front end and Verify to check the token the widget produced.
+type CaptchaServiceServer interface {
+	Init(context.Context, *CaptchaInitRequest) (*CaptchaInitResponse, error)
+	Verify(context.Context, *CaptchaVerifyRequest) (*CaptchaVerifyResponse, error)
+	mustEmbedUnimplementedCaptchaServiceServer()
+}
+
+// UnimplementedCaptchaServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCaptchaServiceServer struct{}
+
+func (UnimplementedCaptchaServiceServer) Init(context.Context, *CaptchaInitRequest) (*CaptchaInitResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Init not implemented")
+}
+func (UnimplementedCaptchaServiceServer) Verify(context.Context, *CaptchaVerifyRequest) (*CaptchaVerifyResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Verify not implemented")
+}
+func (UnimplementedCaptchaServiceServer) mustEmbedUnimplementedCaptchaServiceServer() {}
+func (UnimplementedCaptchaServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeCaptchaServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CaptchaServiceServer will
+// result in compilation errors.
+type UnsafeCaptchaServiceServer interface {
+	mustEmbedUnimplementedCaptchaServiceServer()
+}
+
+func RegisterCaptchaServiceServer(s grpc.ServiceRegistrar, srv CaptchaServiceServer) {
+	// If the following call panics, it indicates UnimplementedCaptchaServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CaptchaService_ServiceDesc, srv)
+}
+
+func _CaptchaService_Init_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CaptchaInitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CaptchaServiceServer).Init(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CaptchaService_Init_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CaptchaServiceServer).Init(ctx, req.(*CaptchaInitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CaptchaService_Verify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CaptchaVerifyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CaptchaServiceServer).Verify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CaptchaService_Verify_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CaptchaServiceServer).Verify(ctx, req.(*CaptchaVerifyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CaptchaService_ServiceDesc is the grpc.ServiceDesc for CaptchaService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CaptchaService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "plugin.v1.CaptchaService",
+	HandlerType: (*CaptchaServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Init",
+			Handler:    _CaptchaService_Init_Handler,
+		},
+		{
+			MethodName: "Verify",
+			Handler:    _CaptchaService_Verify_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "plugin/v1/captcha.proto",
+}
@@ -118,9 +118,12 @@ func (x *ValidateConfigResponse) GetError() string {
 }
 
 type InitRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	InstanceId    string                 `protobuf:"bytes,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
-	ConfigJson    string                 `protobuf:"bytes,2,opt,name=config_json,json=configJson,proto3" json:"config_json,omitempty"`
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId string                 `protobuf:"bytes,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	ConfigJson string                 `protobuf:"bytes,2,opt,name=config_json,json=configJson,proto3" json:"config_json,omitempty"`
+	// Broker id the plugin dials to reach HostService; 0 when the host does not
+	// serve it.
+	HostBrokerId  uint32 `protobuf:"varint,3,opt,name=host_broker_id,json=hostBrokerId,proto3" json:"host_broker_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -169,6 +172,13 @@ func (x *InitRequest) GetConfigJson() string {
 	return ""
 }
 
+func (x *InitRequest) GetHostBrokerId() uint32 {
+	if x != nil {
+		return x.HostBrokerId
+	}
+	return 0
+}
+
 type InitResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Ok            bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
@@ -317,6 +327,136 @@ func (x *ReloadConfigResponse) GetError() string {
 	return ""
 }
 
+type RunJobRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId string                 `protobuf:"bytes,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	Name       string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Unix seconds of the scheduled slot; 0 for manual runs.
+	ScheduledAtUnix int64 `protobuf:"varint,3,opt,name=scheduled_at_unix,json=scheduledAtUnix,proto3" json:"scheduled_at_unix,omitempty"`
+	Manual          bool  `protobuf:"varint,4,opt,name=manual,proto3" json:"manual,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RunJobRequest) Reset() {
+	*x = RunJobRequest{}
+	mi := &file_plugin_v1_core_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunJobRequest) ProtoMessage() {}
+
+func (x *RunJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_core_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunJobRequest.ProtoReflect.Descriptor instead.
+func (*RunJobRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_core_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RunJobRequest) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+func (x *RunJobRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RunJobRequest) GetScheduledAtUnix() int64 {
+	if x != nil {
+		return x.ScheduledAtUnix
+	}
+	return 0
+}
+
+func (x *RunJobRequest) GetManual() bool {
+	if x != nil {
+		return x.Manual
+	}
+	return false
+}
+
+type RunJobResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Ok    bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	Error string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	// Short summary kept in the run history.
+	Message       string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunJobResponse) Reset() {
+	*x = RunJobResponse{}
+	mi := &file_plugin_v1_core_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunJobResponse) ProtoMessage() {}
+
+func (x *RunJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_core_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunJobResponse.ProtoReflect.Descriptor instead.
+func (*RunJobResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_core_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RunJobResponse) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *RunJobResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *RunJobResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
 var File_plugin_v1_core_proto protoreflect.FileDescriptor
 
 const file_plugin_v1_core_proto_rawDesc = "" +
@@ -327,12 +467,13 @@ const file_plugin_v1_core_proto_rawDesc = "" +
 	"configJson\">\n" +
 	"\x16ValidateConfigResponse\x12\x0e\n" +
 	"\x02ok\x18\x01 \x01(\bR\x02ok\x12\x14\n" +
-	"\x05error\x18\x02 \x01(\tR\x05error\"O\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"u\n" +
 	"\vInitRequest\x12\x1f\n" +
 	"\vinstance_id\x18\x01 \x01(\tR\n" +
 	"instanceId\x12\x1f\n" +
 	"\vconfig_json\x18\x02 \x01(\tR\n" +
-	"configJson\"4\n" +
+	"configJson\x12$\n" +
+	"\x0ehost_broker_id\x18\x03 \x01(\rR\fhostBrokerId\"4\n" +
 	"\fInitResponse\x12\x0e\n" +
 	"\x02ok\x18\x01 \x01(\bR\x02ok\x12\x14\n" +
 	"\x05error\x18\x02 \x01(\tR\x05error\"6\n" +
@@ -341,14 +482,25 @@ const file_plugin_v1_core_proto_rawDesc = "" +
 	"configJson\"<\n" +
 	"\x14ReloadConfigResponse\x12\x0e\n" +
 	"\x02ok\x18\x01 \x01(\bR\x02ok\x12\x14\n" +
-	"\x05error\x18\x02 \x01(\tR\x05error2\xab\x03\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"\x88\x01\n" +
+	"\rRunJobRequest\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\tR\n" +
+	"instanceId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12*\n" +
+	"\x11scheduled_at_unix\x18\x03 \x01(\x03R\x0fscheduledAtUnix\x12\x16\n" +
+	"\x06manual\x18\x04 \x01(\bR\x06manual\"P\n" +
+	"\x0eRunJobResponse\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage2\xea\x03\n" +
 	"\vCoreService\x124\n" +
 	"\vGetManifest\x12\x10.plugin.v1.Empty\x1a\x13.plugin.v1.Manifest\x12<\n" +
 	"\x0fGetConfigSchema\x12\x10.plugin.v1.Empty\x1a\x17.plugin.v1.ConfigSchema\x12U\n" +
 	"\x0eValidateConfig\x12 .plugin.v1.ValidateConfigRequest\x1a!.plugin.v1.ValidateConfigResponse\x127\n" +
 	"\x04Init\x12\x16.plugin.v1.InitRequest\x1a\x17.plugin.v1.InitResponse\x12O\n" +
 	"\fReloadConfig\x12\x1e.plugin.v1.ReloadConfigRequest\x1a\x1f.plugin.v1.ReloadConfigResponse\x12G\n" +
-	"\x06Health\x12\x1d.plugin.v1.HealthCheckRequest\x1a\x1e.plugin.v1.HealthCheckResponseB Z\x1exiaoheiplay/plugin/v1;pluginv1b\x06proto3"
+	"\x06Health\x12\x1d.plugin.v1.HealthCheckRequest\x1a\x1e.plugin.v1.HealthCheckResponse\x12=\n" +
+	"\x06RunJob\x12\x18.plugin.v1.RunJobRequest\x1a\x19.plugin.v1.RunJobResponseB Z\x1exiaoheiplay/plugin/v1;pluginv1b\x06proto3"
 
 var (
 	file_plugin_v1_core_proto_rawDescOnce sync.Once
@@ -362,7 +514,7 @@ func file_plugin_v1_core_proto_rawDescGZIP() []byte {
 	return file_plugin_v1_core_proto_rawDescData
 }
 
-var file_plugin_v1_core_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_plugin_v1_core_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_plugin_v1_core_proto_goTypes = []any{
 	(*ValidateConfigRequest)(nil),  // 0: plugin.v1.ValidateConfigRequest
 	(*ValidateConfigResponse)(nil), // 1: plugin.v1.ValidateConfigResponse
@@ -370,27 +522,31 @@ var file_plugin_v1_core_proto_goTypes = []any{
 	(*InitResponse)(nil),           // 3: plugin.v1.InitResponse
 	(*ReloadConfigRequest)(nil),    // 4: plugin.v1.ReloadConfigRequest
 	(*ReloadConfigResponse)(nil),   // 5: plugin.v1.ReloadConfigResponse
-	(*Empty)(nil),                  // 6: plugin.v1.Empty
-	(*HealthCheckRequest)(nil),     // 7: plugin.v1.HealthCheckRequest
-	(*Manifest)(nil),               // 8: plugin.v1.Manifest
-	(*ConfigSchema)(nil),           // 9: plugin.v1.ConfigSchema
-	(*HealthCheckResponse)(nil),    // 10: plugin.v1.HealthCheckResponse
+	(*RunJobRequest)(nil),          // 6: plugin.v1.RunJobRequest
+	(*RunJobResponse)(nil),         // 7: plugin.v1.RunJobResponse
+	(*Empty)(nil),                  // 8: plugin.v1.Empty
+	(*HealthCheckRequest)(nil),     // 9: plugin.v1.HealthCheckRequest
+	(*Manifest)(nil),               // 10: plugin.v1.Manifest
+	(*ConfigSchema)(nil),           // 11: plugin.v1.ConfigSchema
+	(*HealthCheckResponse)(nil),    // 12: plugin.v1.HealthCheckResponse
 }
 var file_plugin_v1_core_proto_depIdxs = []int32{
-	6,  // 0: plugin.v1.CoreService.GetManifest:input_type -> plugin.v1.Empty
-	6,  // 1: plugin.v1.CoreService.GetConfigSchema:input_type -> plugin.v1.Empty
+	8,  // 0: plugin.v1.CoreService.GetManifest:input_type -> plugin.v1.Empty
+	8,  // 1: plugin.v1.CoreService.GetConfigSchema:input_type -> plugin.v1.Empty
 	0,  // 2: plugin.v1.CoreService.ValidateConfig:input_type -> plugin.v1.ValidateConfigRequest
 	2,  // 3: plugin.v1.CoreService.Init:input_type -> plugin.v1.InitRequest
 	4,  // 4: plugin.v1.CoreService.ReloadConfig:input_type -> plugin.v1.ReloadConfigRequest
-	7,  // 5: plugin.v1.CoreService.Health:input_type -> plugin.v1.HealthCheckRequest
-	8,  // 6: plugin.v1.CoreService.GetManifest:output_type -> plugin.v1.Manifest
-	9,  // 7: plugin.v1.CoreService.GetConfigSchema:output_type -> plugin.v1.ConfigSchema
-	1,  // 8: plugin.v1.CoreService.ValidateConfig:output_type -> plugin.v1.ValidateConfigResponse
-	3,  // 9: plugin.v1.CoreService.Init:output_type -> plugin.v1.InitResponse
-	5,  // 10: plugin.v1.CoreService.ReloadConfig:output_type -> plugin.v1.ReloadConfigResponse
-	10, // 11: plugin.v1.CoreService.Health:output_type -> plugin.v1.HealthCheckResponse
-	6,  // [6:12] is the sub-list for method output_type
-	0,  // [0:6] is the sub-list for method input_type
+	9,  // 5: plugin.v1.CoreService.Health:input_type -> plugin.v1.HealthCheckRequest
+	6,  // 6: plugin.v1.CoreService.RunJob:input_type -> plugin.v1.RunJobRequest
+	10, // 7: plugin.v1.CoreService.GetManifest:output_type -> plugin.v1.Manifest
+	11, // 8: plugin.v1.CoreService.GetConfigSchema:output_type -> plugin.v1.ConfigSchema
+	1,  // 9: plugin.v1.CoreService.ValidateConfig:output_type -> plugin.v1.ValidateConfigResponse
+	3,  // 10: plugin.v1.CoreService.Init:output_type -> plugin.v1.InitResponse
+	5,  // 11: plugin.v1.CoreService.ReloadConfig:output_type -> plugin.v1.ReloadConfigResponse
+	12, // 12: plugin.v1.CoreService.Health:output_type -> plugin.v1.HealthCheckResponse
+	7,  // 13: plugin.v1.CoreService.RunJob:output_type -> plugin.v1.RunJobResponse
+	7,  // [7:14] is the sub-list for method output_type
+	0,  // [0:7] is the sub-list for method input_type
 	0,  // [0:0] is the sub-list for extension type_name
 	0,  // [0:0] is the sub-list for extension extendee
 	0,  // [0:0] is the sub-list for field type_name
@@ -409,7 +565,7 @@ func file_plugin_v1_core_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_plugin_v1_core_proto_rawDesc), len(file_plugin_v1_core_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -25,6 +25,7 @@ const (
 	CoreService_Init_FullMethodName            = "/plugin.v1.CoreService/Init"
 	CoreService_ReloadConfig_FullMethodName    = "/plugin.v1.CoreService/ReloadConfig"
 	CoreService_Health_FullMethodName          = "/plugin.v1.CoreService/Health"
+	CoreService_RunJob_FullMethodName          = "/plugin.v1.CoreService/RunJob"
 )
 
 // CoreServiceClient is the client API for CoreService service.
@@ -37,6 +38,10 @@ type CoreServiceClient interface {
 	Init(ctx context.Context, in *InitRequest, opts ...grpc.CallOption) (*InitResponse, error)
 	ReloadConfig(ctx context.Context, in *ReloadConfigRequest, opts ...grpc.CallOption) (*ReloadConfigResponse, error)
 	Health(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error)
+	// RunJob runs one of the jobs declared in Manifest.jobs. The host calls it
+	// on the job schedule for every enabled instance, or when an admin triggers
+	// the job manually.
+	RunJob(ctx context.Context, in *RunJobRequest, opts ...grpc.CallOption) (*RunJobResponse, error)
 }
 
 type coreServiceClient struct {
@@ -107,6 +112,16 @@ func (c *coreServiceClient) Health(ctx context.Context, in *HealthCheckRequest,
 	return out, nil
 }
 
+func (c *coreServiceClient) RunJob(ctx context.Context, in *RunJobRequest, opts ...grpc.CallOption) (*RunJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RunJobResponse)
+	err := c.cc.Invoke(ctx, CoreService_RunJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CoreServiceServer is the server API for CoreService service.
 // All implementations must embed UnimplementedCoreServiceServer
 // for forward compatibility.
@@ -117,6 +132,10 @@ type CoreServiceServer interface {
 	Init(context.Context, *InitRequest) (*InitResponse, error)
 	ReloadConfig(context.Context, *ReloadConfigRequest) (*ReloadConfigResponse, error)
 	Health(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error)
+	// RunJob runs one of the jobs declared in Manifest.jobs. The host calls it
+	// on the job schedule for every enabled instance, or when an admin triggers
+	// the job manually.
+	RunJob(context.Context, *RunJobRequest) (*RunJobResponse, error)
 	mustEmbedUnimplementedCoreServiceServer()
 }
 
@@ -145,6 +164,9 @@ func (UnimplementedCoreServiceServer) ReloadConfig(context.Context, *ReloadConfi
 func (UnimplementedCoreServiceServer) Health(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Health not implemented")
 }
+func (UnimplementedCoreServiceServer) RunJob(context.Context, *RunJobRequest) (*RunJobResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RunJob not implemented")
+}
 func (UnimplementedCoreServiceServer) mustEmbedUnimplementedCoreServiceServer() {}
 func (UnimplementedCoreServiceServer) testEmbeddedByValue()                     {}
 
@@ -274,6 +296,24 @@ func _CoreService_Health_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CoreService_RunJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RunJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CoreServiceServer).RunJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CoreService_RunJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CoreServiceServer).RunJob(ctx, req.(*RunJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CoreService_ServiceDesc is the grpc.ServiceDesc for CoreService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -305,6 +345,10 @@ var CoreService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Health",
 			Handler:    _CoreService_Health_Handler,
 		},
+		{
+			MethodName: "RunJob",
+			Handler:    _CoreService_RunJob_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "plugin/v1/core.proto",
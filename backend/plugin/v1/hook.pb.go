@@ -0,0 +1,233 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.0
+// source: plugin/v1/hook.proto
+
+package pluginv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type HookEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Stable across retries and shared by every subscriber of the same event.
+	Id         string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	InstanceId string `protobuf:"bytes,2,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	// One of order.created, order.paid, order.active, vps.expired,
+	// user.registered or ticket.created.
+	Type           string `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	OccurredAtUnix int64  `protobuf:"varint,4,opt,name=occurred_at_unix,json=occurredAtUnix,proto3" json:"occurred_at_unix,omitempty"`
+	PayloadJson    string `protobuf:"bytes,5,opt,name=payload_json,json=payloadJson,proto3" json:"payload_json,omitempty"`
+	// 1 for the first delivery.
+	Attempt       int32 `protobuf:"varint,6,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HookEvent) Reset() {
+	*x = HookEvent{}
+	mi := &file_plugin_v1_hook_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HookEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HookEvent) ProtoMessage() {}
+
+func (x *HookEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_hook_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HookEvent.ProtoReflect.Descriptor instead.
+func (*HookEvent) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_hook_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *HookEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *HookEvent) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+func (x *HookEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *HookEvent) GetOccurredAtUnix() int64 {
+	if x != nil {
+		return x.OccurredAtUnix
+	}
+	return 0
+}
+
+func (x *HookEvent) GetPayloadJson() string {
+	if x != nil {
+		return x.PayloadJson
+	}
+	return ""
+}
+
+func (x *HookEvent) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+type HookEventResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ok=false is retried like a transport error.
+	Ok            bool   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	Error         string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HookEventResponse) Reset() {
+	*x = HookEventResponse{}
+	mi := &file_plugin_v1_hook_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HookEventResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HookEventResponse) ProtoMessage() {}
+
+func (x *HookEventResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_hook_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HookEventResponse.ProtoReflect.Descriptor instead.
+func (*HookEventResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_hook_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *HookEventResponse) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *HookEventResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+var File_plugin_v1_hook_proto protoreflect.FileDescriptor
+
+const file_plugin_v1_hook_proto_rawDesc = "" +
+	"\n" +
+	"\x14plugin/v1/hook.proto\x12\tplugin.v1\"\xb7\x01\n" +
+	"\tHookEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
+	"\vinstance_id\x18\x02 \x01(\tR\n" +
+	"instanceId\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12(\n" +
+	"\x10occurred_at_unix\x18\x04 \x01(\x03R\x0eoccurredAtUnix\x12!\n" +
+	"\fpayload_json\x18\x05 \x01(\tR\vpayloadJson\x12\x18\n" +
+	"\aattempt\x18\x06 \x01(\x05R\aattempt\"9\n" +
+	"\x11HookEventResponse\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error2L\n" +
+	"\vHookService\x12=\n" +
+	"\aOnEvent\x12\x14.plugin.v1.HookEvent\x1a\x1c.plugin.v1.HookEventResponseB Z\x1exiaoheiplay/plugin/v1;pluginv1b\x06proto3"
+
+var (
+	file_plugin_v1_hook_proto_rawDescOnce sync.Once
+	file_plugin_v1_hook_proto_rawDescData []byte
+)
+
+func file_plugin_v1_hook_proto_rawDescGZIP() []byte {
+	file_plugin_v1_hook_proto_rawDescOnce.Do(func() {
+		file_plugin_v1_hook_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_plugin_v1_hook_proto_rawDesc), len(file_plugin_v1_hook_proto_rawDesc)))
+	})
+	return file_plugin_v1_hook_proto_rawDescData
+}
+
+var file_plugin_v1_hook_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_plugin_v1_hook_proto_goTypes = []any{
+	(*HookEvent)(nil),         // 0: plugin.v1.HookEvent
+	(*HookEventResponse)(nil), // 1: plugin.v1.HookEventResponse
+}
+var file_plugin_v1_hook_proto_depIdxs = []int32{
+	0, // 0: plugin.v1.HookService.OnEvent:input_type -> plugin.v1.HookEvent
+	1, // 1: plugin.v1.HookService.OnEvent:output_type -> plugin.v1.HookEventResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_plugin_v1_hook_proto_init() }
+func file_plugin_v1_hook_proto_init() {
+	if File_plugin_v1_hook_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_plugin_v1_hook_proto_rawDesc), len(file_plugin_v1_hook_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_plugin_v1_hook_proto_goTypes,
+		DependencyIndexes: file_plugin_v1_hook_proto_depIdxs,
+		MessageInfos:      file_plugin_v1_hook_proto_msgTypes,
+	}.Build()
+	File_plugin_v1_hook_proto = out.File
+	file_plugin_v1_hook_proto_goTypes = nil
+	file_plugin_v1_hook_proto_depIdxs = nil
+}
@@ -31,18 +31,29 @@ const (
 	AutomationFeature_AUTOMATION_FEATURE_BACKUP       AutomationFeature = 4
 	AutomationFeature_AUTOMATION_FEATURE_SNAPSHOT     AutomationFeature = 5
 	AutomationFeature_AUTOMATION_FEATURE_FIREWALL     AutomationFeature = 6
+	// SSH key injection, cloud-init user data and startup scripts at create/rebuild.
+	AutomationFeature_AUTOMATION_FEATURE_CLOUD_INIT AutomationFeature = 7
+	// PTR records for instance IPs.
+	AutomationFeature_AUTOMATION_FEATURE_REVERSE_DNS AutomationFeature = 8
+	AutomationFeature_AUTOMATION_FEATURE_EXTRA_IP    AutomationFeature = 9
+	// Rescue boot and ISO mounting.
+	AutomationFeature_AUTOMATION_FEATURE_RESCUE AutomationFeature = 10
 )
 
 // Enum value maps for AutomationFeature.
 var (
 	AutomationFeature_name = map[int32]string{
-		0: "AUTOMATION_FEATURE_UNSPECIFIED",
-		1: "AUTOMATION_FEATURE_CATALOG_SYNC",
-		2: "AUTOMATION_FEATURE_LIFECYCLE",
-		3: "AUTOMATION_FEATURE_PORT_MAPPING",
-		4: "AUTOMATION_FEATURE_BACKUP",
-		5: "AUTOMATION_FEATURE_SNAPSHOT",
-		6: "AUTOMATION_FEATURE_FIREWALL",
+		0:  "AUTOMATION_FEATURE_UNSPECIFIED",
+		1:  "AUTOMATION_FEATURE_CATALOG_SYNC",
+		2:  "AUTOMATION_FEATURE_LIFECYCLE",
+		3:  "AUTOMATION_FEATURE_PORT_MAPPING",
+		4:  "AUTOMATION_FEATURE_BACKUP",
+		5:  "AUTOMATION_FEATURE_SNAPSHOT",
+		6:  "AUTOMATION_FEATURE_FIREWALL",
+		7:  "AUTOMATION_FEATURE_CLOUD_INIT",
+		8:  "AUTOMATION_FEATURE_REVERSE_DNS",
+		9:  "AUTOMATION_FEATURE_EXTRA_IP",
+		10: "AUTOMATION_FEATURE_RESCUE",
 	}
 	AutomationFeature_value = map[string]int32{
 		"AUTOMATION_FEATURE_UNSPECIFIED":  0,
@@ -52,6 +63,10 @@ var (
 		"AUTOMATION_FEATURE_BACKUP":       4,
 		"AUTOMATION_FEATURE_SNAPSHOT":     5,
 		"AUTOMATION_FEATURE_FIREWALL":     6,
+		"AUTOMATION_FEATURE_CLOUD_INIT":   7,
+		"AUTOMATION_FEATURE_REVERSE_DNS":  8,
+		"AUTOMATION_FEATURE_EXTRA_IP":     9,
+		"AUTOMATION_FEATURE_RESCUE":       10,
 	}
 )
 
@@ -222,6 +237,150 @@ func (x *KycCapability) GetQueryResult() bool {
 	return false
 }
 
+type NotifyCapability struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Send  bool                   `protobuf:"varint,1,opt,name=send,proto3" json:"send,omitempty"`
+	// Send renders SendNotifyRequest.markdown bodies.
+	Markdown      bool `protobuf:"varint,2,opt,name=markdown,proto3" json:"markdown,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NotifyCapability) Reset() {
+	*x = NotifyCapability{}
+	mi := &file_plugin_v1_manifest_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotifyCapability) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotifyCapability) ProtoMessage() {}
+
+func (x *NotifyCapability) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_manifest_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotifyCapability.ProtoReflect.Descriptor instead.
+func (*NotifyCapability) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_manifest_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *NotifyCapability) GetSend() bool {
+	if x != nil {
+		return x.Send
+	}
+	return false
+}
+
+func (x *NotifyCapability) GetMarkdown() bool {
+	if x != nil {
+		return x.Markdown
+	}
+	return false
+}
+
+type StorageCapability struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// PresignURL returns direct download links; without it the host streams
+	// objects through Get.
+	Presign       bool `protobuf:"varint,1,opt,name=presign,proto3" json:"presign,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StorageCapability) Reset() {
+	*x = StorageCapability{}
+	mi := &file_plugin_v1_manifest_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StorageCapability) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StorageCapability) ProtoMessage() {}
+
+func (x *StorageCapability) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_manifest_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StorageCapability.ProtoReflect.Descriptor instead.
+func (*StorageCapability) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_manifest_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *StorageCapability) GetPresign() bool {
+	if x != nil {
+		return x.Presign
+	}
+	return false
+}
+
+type CaptchaCapability struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Front-end widget rendered for this provider, e.g. geetest_v4.
+	Widget        string `protobuf:"bytes,1,opt,name=widget,proto3" json:"widget,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CaptchaCapability) Reset() {
+	*x = CaptchaCapability{}
+	mi := &file_plugin_v1_manifest_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CaptchaCapability) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CaptchaCapability) ProtoMessage() {}
+
+func (x *CaptchaCapability) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_manifest_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CaptchaCapability.ProtoReflect.Descriptor instead.
+func (*CaptchaCapability) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_manifest_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CaptchaCapability) GetWidget() string {
+	if x != nil {
+		return x.Widget
+	}
+	return ""
+}
+
 type AutomationCapability struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Supported features. Missing features are treated as "not supported".
@@ -237,7 +396,7 @@ type AutomationCapability struct {
 
 func (x *AutomationCapability) Reset() {
 	*x = AutomationCapability{}
-	mi := &file_plugin_v1_manifest_proto_msgTypes[3]
+	mi := &file_plugin_v1_manifest_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -249,7 +408,7 @@ func (x *AutomationCapability) String() string {
 func (*AutomationCapability) ProtoMessage() {}
 
 func (x *AutomationCapability) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_v1_manifest_proto_msgTypes[3]
+	mi := &file_plugin_v1_manifest_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -262,7 +421,7 @@ func (x *AutomationCapability) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use AutomationCapability.ProtoReflect.Descriptor instead.
 func (*AutomationCapability) Descriptor() ([]byte, []int) {
-	return file_plugin_v1_manifest_proto_rawDescGZIP(), []int{3}
+	return file_plugin_v1_manifest_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *AutomationCapability) GetFeatures() []AutomationFeature {
@@ -286,6 +445,86 @@ func (x *AutomationCapability) GetCatalogReadonly() bool {
 	return false
 }
 
+// JobDefinition declares a job the host runs through CoreService.RunJob.
+// Exactly one of interval_sec or cron must be set.
+type JobDefinition struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Name        string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	IntervalSec int32                  `protobuf:"varint,3,opt,name=interval_sec,json=intervalSec,proto3" json:"interval_sec,omitempty"`
+	// Five field cron expression evaluated in host local time.
+	Cron string `protobuf:"bytes,4,opt,name=cron,proto3" json:"cron,omitempty"`
+	// Upper bound of one run; the host default applies when 0.
+	TimeoutSec    int32 `protobuf:"varint,5,opt,name=timeout_sec,json=timeoutSec,proto3" json:"timeout_sec,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobDefinition) Reset() {
+	*x = JobDefinition{}
+	mi := &file_plugin_v1_manifest_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobDefinition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobDefinition) ProtoMessage() {}
+
+func (x *JobDefinition) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_manifest_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobDefinition.ProtoReflect.Descriptor instead.
+func (*JobDefinition) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_manifest_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *JobDefinition) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *JobDefinition) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *JobDefinition) GetIntervalSec() int32 {
+	if x != nil {
+		return x.IntervalSec
+	}
+	return 0
+}
+
+func (x *JobDefinition) GetCron() string {
+	if x != nil {
+		return x.Cron
+	}
+	return ""
+}
+
+func (x *JobDefinition) GetTimeoutSec() int32 {
+	if x != nil {
+		return x.TimeoutSec
+	}
+	return 0
+}
+
 type Manifest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	PluginId      string                 `protobuf:"bytes,1,opt,name=plugin_id,json=pluginId,proto3" json:"plugin_id,omitempty"`
@@ -296,13 +535,17 @@ type Manifest struct {
 	Payment       *PaymentCapability     `protobuf:"bytes,11,opt,name=payment,proto3,oneof" json:"payment,omitempty"`
 	Kyc           *KycCapability         `protobuf:"bytes,12,opt,name=kyc,proto3,oneof" json:"kyc,omitempty"`
 	Automation    *AutomationCapability  `protobuf:"bytes,13,opt,name=automation,proto3,oneof" json:"automation,omitempty"`
+	Notify        *NotifyCapability      `protobuf:"bytes,14,opt,name=notify,proto3,oneof" json:"notify,omitempty"`
+	Storage       *StorageCapability     `protobuf:"bytes,15,opt,name=storage,proto3,oneof" json:"storage,omitempty"`
+	Captcha       *CaptchaCapability     `protobuf:"bytes,16,opt,name=captcha,proto3,oneof" json:"captcha,omitempty"`
+	Jobs          []*JobDefinition       `protobuf:"bytes,20,rep,name=jobs,proto3" json:"jobs,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Manifest) Reset() {
 	*x = Manifest{}
-	mi := &file_plugin_v1_manifest_proto_msgTypes[4]
+	mi := &file_plugin_v1_manifest_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -314,7 +557,7 @@ func (x *Manifest) String() string {
 func (*Manifest) ProtoMessage() {}
 
 func (x *Manifest) ProtoReflect() protoreflect.Message {
-	mi := &file_plugin_v1_manifest_proto_msgTypes[4]
+	mi := &file_plugin_v1_manifest_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -327,7 +570,7 @@ func (x *Manifest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Manifest.ProtoReflect.Descriptor instead.
 func (*Manifest) Descriptor() ([]byte, []int) {
-	return file_plugin_v1_manifest_proto_rawDescGZIP(), []int{4}
+	return file_plugin_v1_manifest_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *Manifest) GetPluginId() string {
@@ -386,6 +629,34 @@ func (x *Manifest) GetAutomation() *AutomationCapability {
 	return nil
 }
 
+func (x *Manifest) GetNotify() *NotifyCapability {
+	if x != nil {
+		return x.Notify
+	}
+	return nil
+}
+
+func (x *Manifest) GetStorage() *StorageCapability {
+	if x != nil {
+		return x.Storage
+	}
+	return nil
+}
+
+func (x *Manifest) GetCaptcha() *CaptchaCapability {
+	if x != nil {
+		return x.Captcha
+	}
+	return nil
+}
+
+func (x *Manifest) GetJobs() []*JobDefinition {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
 var File_plugin_v1_manifest_proto protoreflect.FileDescriptor
 
 const file_plugin_v1_manifest_proto_rawDesc = "" +
@@ -397,14 +668,28 @@ const file_plugin_v1_manifest_proto_rawDesc = "" +
 	"\amethods\x18\x01 \x03(\tR\amethods\"H\n" +
 	"\rKycCapability\x12\x14\n" +
 	"\x05start\x18\x01 \x01(\bR\x05start\x12!\n" +
-	"\fquery_result\x18\x02 \x01(\bR\vqueryResult\"\xb1\x02\n" +
+	"\fquery_result\x18\x02 \x01(\bR\vqueryResult\"B\n" +
+	"\x10NotifyCapability\x12\x12\n" +
+	"\x04send\x18\x01 \x01(\bR\x04send\x12\x1a\n" +
+	"\bmarkdown\x18\x02 \x01(\bR\bmarkdown\"-\n" +
+	"\x11StorageCapability\x12\x18\n" +
+	"\apresign\x18\x01 \x01(\bR\apresign\"+\n" +
+	"\x11CaptchaCapability\x12\x16\n" +
+	"\x06widget\x18\x01 \x01(\tR\x06widget\"\xb1\x02\n" +
 	"\x14AutomationCapability\x128\n" +
 	"\bfeatures\x18\x01 \x03(\x0e2\x1c.plugin.v1.AutomationFeatureR\bfeatures\x12l\n" +
 	"\x15not_supported_reasons\x18\x02 \x03(\v28.plugin.v1.AutomationCapability.NotSupportedReasonsEntryR\x13notSupportedReasons\x12)\n" +
 	"\x10catalog_readonly\x18\x03 \x01(\bR\x0fcatalogReadonly\x1aF\n" +
 	"\x18NotSupportedReasonsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\x05R\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x87\x03\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x9d\x01\n" +
+	"\rJobDefinition\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12!\n" +
+	"\finterval_sec\x18\x03 \x01(\x05R\vintervalSec\x12\x12\n" +
+	"\x04cron\x18\x04 \x01(\tR\x04cron\x12\x1f\n" +
+	"\vtimeout_sec\x18\x05 \x01(\x05R\n" +
+	"timeoutSec\"\x8c\x05\n" +
 	"\bManifest\x12\x1b\n" +
 	"\tplugin_id\x18\x01 \x01(\tR\bpluginId\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
@@ -416,12 +701,21 @@ const file_plugin_v1_manifest_proto_rawDesc = "" +
 	"\x03kyc\x18\f \x01(\v2\x18.plugin.v1.KycCapabilityH\x02R\x03kyc\x88\x01\x01\x12D\n" +
 	"\n" +
 	"automation\x18\r \x01(\v2\x1f.plugin.v1.AutomationCapabilityH\x03R\n" +
-	"automation\x88\x01\x01B\x06\n" +
+	"automation\x88\x01\x01\x128\n" +
+	"\x06notify\x18\x0e \x01(\v2\x1b.plugin.v1.NotifyCapabilityH\x04R\x06notify\x88\x01\x01\x12;\n" +
+	"\astorage\x18\x0f \x01(\v2\x1c.plugin.v1.StorageCapabilityH\x05R\astorage\x88\x01\x01\x12;\n" +
+	"\acaptcha\x18\x10 \x01(\v2\x1c.plugin.v1.CaptchaCapabilityH\x06R\acaptcha\x88\x01\x01\x12,\n" +
+	"\x04jobs\x18\x14 \x03(\v2\x18.plugin.v1.JobDefinitionR\x04jobsB\x06\n" +
 	"\x04_smsB\n" +
 	"\n" +
 	"\b_paymentB\x06\n" +
 	"\x04_kycB\r\n" +
-	"\v_automation*\x84\x02\n" +
+	"\v_automationB\t\n" +
+	"\a_notifyB\n" +
+	"\n" +
+	"\b_storageB\n" +
+	"\n" +
+	"\b_captcha*\x8b\x03\n" +
 	"\x11AutomationFeature\x12\"\n" +
 	"\x1eAUTOMATION_FEATURE_UNSPECIFIED\x10\x00\x12#\n" +
 	"\x1fAUTOMATION_FEATURE_CATALOG_SYNC\x10\x01\x12 \n" +
@@ -429,7 +723,12 @@ const file_plugin_v1_manifest_proto_rawDesc = "" +
 	"\x1fAUTOMATION_FEATURE_PORT_MAPPING\x10\x03\x12\x1d\n" +
 	"\x19AUTOMATION_FEATURE_BACKUP\x10\x04\x12\x1f\n" +
 	"\x1bAUTOMATION_FEATURE_SNAPSHOT\x10\x05\x12\x1f\n" +
-	"\x1bAUTOMATION_FEATURE_FIREWALL\x10\x06B Z\x1exiaoheiplay/plugin/v1;pluginv1b\x06proto3"
+	"\x1bAUTOMATION_FEATURE_FIREWALL\x10\x06\x12!\n" +
+	"\x1dAUTOMATION_FEATURE_CLOUD_INIT\x10\a\x12\"\n" +
+	"\x1eAUTOMATION_FEATURE_REVERSE_DNS\x10\b\x12\x1f\n" +
+	"\x1bAUTOMATION_FEATURE_EXTRA_IP\x10\t\x12\x1d\n" +
+	"\x19AUTOMATION_FEATURE_RESCUE\x10\n" +
+	"B Z\x1exiaoheiplay/plugin/v1;pluginv1b\x06proto3"
 
 var (
 	file_plugin_v1_manifest_proto_rawDescOnce sync.Once
@@ -444,28 +743,36 @@ func file_plugin_v1_manifest_proto_rawDescGZIP() []byte {
 }
 
 var file_plugin_v1_manifest_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_plugin_v1_manifest_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_plugin_v1_manifest_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
 var file_plugin_v1_manifest_proto_goTypes = []any{
 	(AutomationFeature)(0),       // 0: plugin.v1.AutomationFeature
 	(*SmsCapability)(nil),        // 1: plugin.v1.SmsCapability
 	(*PaymentCapability)(nil),    // 2: plugin.v1.PaymentCapability
 	(*KycCapability)(nil),        // 3: plugin.v1.KycCapability
-	(*AutomationCapability)(nil), // 4: plugin.v1.AutomationCapability
-	(*Manifest)(nil),             // 5: plugin.v1.Manifest
-	nil,                          // 6: plugin.v1.AutomationCapability.NotSupportedReasonsEntry
+	(*NotifyCapability)(nil),     // 4: plugin.v1.NotifyCapability
+	(*StorageCapability)(nil),    // 5: plugin.v1.StorageCapability
+	(*CaptchaCapability)(nil),    // 6: plugin.v1.CaptchaCapability
+	(*AutomationCapability)(nil), // 7: plugin.v1.AutomationCapability
+	(*JobDefinition)(nil),        // 8: plugin.v1.JobDefinition
+	(*Manifest)(nil),             // 9: plugin.v1.Manifest
+	nil,                          // 10: plugin.v1.AutomationCapability.NotSupportedReasonsEntry
 }
 var file_plugin_v1_manifest_proto_depIdxs = []int32{
-	0, // 0: plugin.v1.AutomationCapability.features:type_name -> plugin.v1.AutomationFeature
-	6, // 1: plugin.v1.AutomationCapability.not_supported_reasons:type_name -> plugin.v1.AutomationCapability.NotSupportedReasonsEntry
-	1, // 2: plugin.v1.Manifest.sms:type_name -> plugin.v1.SmsCapability
-	2, // 3: plugin.v1.Manifest.payment:type_name -> plugin.v1.PaymentCapability
-	3, // 4: plugin.v1.Manifest.kyc:type_name -> plugin.v1.KycCapability
-	4, // 5: plugin.v1.Manifest.automation:type_name -> plugin.v1.AutomationCapability
-	6, // [6:6] is the sub-list for method output_type
-	6, // [6:6] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	0,  // 0: plugin.v1.AutomationCapability.features:type_name -> plugin.v1.AutomationFeature
+	10, // 1: plugin.v1.AutomationCapability.not_supported_reasons:type_name -> plugin.v1.AutomationCapability.NotSupportedReasonsEntry
+	1,  // 2: plugin.v1.Manifest.sms:type_name -> plugin.v1.SmsCapability
+	2,  // 3: plugin.v1.Manifest.payment:type_name -> plugin.v1.PaymentCapability
+	3,  // 4: plugin.v1.Manifest.kyc:type_name -> plugin.v1.KycCapability
+	7,  // 5: plugin.v1.Manifest.automation:type_name -> plugin.v1.AutomationCapability
+	4,  // 6: plugin.v1.Manifest.notify:type_name -> plugin.v1.NotifyCapability
+	5,  // 7: plugin.v1.Manifest.storage:type_name -> plugin.v1.StorageCapability
+	6,  // 8: plugin.v1.Manifest.captcha:type_name -> plugin.v1.CaptchaCapability
+	8,  // 9: plugin.v1.Manifest.jobs:type_name -> plugin.v1.JobDefinition
+	10, // [10:10] is the sub-list for method output_type
+	10, // [10:10] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_plugin_v1_manifest_proto_init() }
@@ -473,14 +780,14 @@ func file_plugin_v1_manifest_proto_init() {
 	if File_plugin_v1_manifest_proto != nil {
 		return
 	}
-	file_plugin_v1_manifest_proto_msgTypes[4].OneofWrappers = []any{}
+	file_plugin_v1_manifest_proto_msgTypes[8].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_plugin_v1_manifest_proto_rawDesc), len(file_plugin_v1_manifest_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   6,
+			NumMessages:   10,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
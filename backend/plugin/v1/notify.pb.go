@@ -0,0 +1,242 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.0
+// source: plugin/v1/notify.proto
+
+package pluginv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SendNotifyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Title string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Body  string                 `protobuf:"bytes,2,opt,name=body,proto3" json:"body,omitempty"`
+	// body is Markdown; plugins without Markdown support send it as plain text.
+	Markdown bool `protobuf:"varint,3,opt,name=markdown,proto3" json:"markdown,omitempty"`
+	// Channel specific destination, e.g. a chat ID or a robot webhook URL.
+	// Empty means the default target of the instance config.
+	Target string `protobuf:"bytes,4,opt,name=target,proto3" json:"target,omitempty"`
+	// Host event that caused the notification, e.g. order.paid.
+	Event         string `protobuf:"bytes,5,opt,name=event,proto3" json:"event,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendNotifyRequest) Reset() {
+	*x = SendNotifyRequest{}
+	mi := &file_plugin_v1_notify_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendNotifyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendNotifyRequest) ProtoMessage() {}
+
+func (x *SendNotifyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_notify_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendNotifyRequest.ProtoReflect.Descriptor instead.
+func (*SendNotifyRequest) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_notify_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SendNotifyRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *SendNotifyRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *SendNotifyRequest) GetMarkdown() bool {
+	if x != nil {
+		return x.Markdown
+	}
+	return false
+}
+
+func (x *SendNotifyRequest) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *SendNotifyRequest) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+type SendNotifyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ok            bool                   `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	MessageId     string                 `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	ErrorCode     string                 `protobuf:"bytes,4,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendNotifyResponse) Reset() {
+	*x = SendNotifyResponse{}
+	mi := &file_plugin_v1_notify_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendNotifyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendNotifyResponse) ProtoMessage() {}
+
+func (x *SendNotifyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_plugin_v1_notify_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendNotifyResponse.ProtoReflect.Descriptor instead.
+func (*SendNotifyResponse) Descriptor() ([]byte, []int) {
+	return file_plugin_v1_notify_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SendNotifyResponse) GetOk() bool {
+	if x != nil {
+		return x.Ok
+	}
+	return false
+}
+
+func (x *SendNotifyResponse) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *SendNotifyResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *SendNotifyResponse) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+var File_plugin_v1_notify_proto protoreflect.FileDescriptor
+
+const file_plugin_v1_notify_proto_rawDesc = "" +
+	"\n" +
+	"\x16plugin/v1/notify.proto\x12\tplugin.v1\"\x87\x01\n" +
+	"\x11SendNotifyRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x12\n" +
+	"\x04body\x18\x02 \x01(\tR\x04body\x12\x1a\n" +
+	"\bmarkdown\x18\x03 \x01(\bR\bmarkdown\x12\x16\n" +
+	"\x06target\x18\x04 \x01(\tR\x06target\x12\x14\n" +
+	"\x05event\x18\x05 \x01(\tR\x05event\"x\n" +
+	"\x12SendNotifyResponse\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\bR\x02ok\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\tR\tmessageId\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\x04 \x01(\tR\terrorCode2T\n" +
+	"\rNotifyService\x12C\n" +
+	"\x04Send\x12\x1c.plugin.v1.SendNotifyRequest\x1a\x1d.plugin.v1.SendNotifyResponseB Z\x1exiaoheiplay/plugin/v1;pluginv1b\x06proto3"
+
+var (
+	file_plugin_v1_notify_proto_rawDescOnce sync.Once
+	file_plugin_v1_notify_proto_rawDescData []byte
+)
+
+func file_plugin_v1_notify_proto_rawDescGZIP() []byte {
+	file_plugin_v1_notify_proto_rawDescOnce.Do(func() {
+		file_plugin_v1_notify_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_plugin_v1_notify_proto_rawDesc), len(file_plugin_v1_notify_proto_rawDesc)))
+	})
+	return file_plugin_v1_notify_proto_rawDescData
+}
+
+var file_plugin_v1_notify_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_plugin_v1_notify_proto_goTypes = []any{
+	(*SendNotifyRequest)(nil),  // 0: plugin.v1.SendNotifyRequest
+	(*SendNotifyResponse)(nil), // 1: plugin.v1.SendNotifyResponse
+}
+var file_plugin_v1_notify_proto_depIdxs = []int32{
+	0, // 0: plugin.v1.NotifyService.Send:input_type -> plugin.v1.SendNotifyRequest
+	1, // 1: plugin.v1.NotifyService.Send:output_type -> plugin.v1.SendNotifyResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_plugin_v1_notify_proto_init() }
+func file_plugin_v1_notify_proto_init() {
+	if File_plugin_v1_notify_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_plugin_v1_notify_proto_rawDesc), len(file_plugin_v1_notify_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_plugin_v1_notify_proto_goTypes,
+		DependencyIndexes: file_plugin_v1_notify_proto_depIdxs,
+		MessageInfos:      file_plugin_v1_notify_proto_msgTypes,
+	}.Build()
+	File_plugin_v1_notify_proto = out.File
+	file_plugin_v1_notify_proto_goTypes = nil
+	file_plugin_v1_notify_proto_depIdxs = nil
+}
@@ -0,0 +1,129 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.0
+// - protoc             v6.33.0
+// source: plugin/v1/notify.proto
+
+package pluginv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	NotifyService_Send_FullMethodName = "/plugin.v1.NotifyService/Send"
+)
+
+// NotifyServiceClient is the client API for NotifyService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// NotifyService delivers host notifications (robot webhooks, admin alerts,
+// user notices) to a chat or push channel such as Telegram, DingTalk, Feishu
+// or WeCom.
+type NotifyServiceClient interface {
+	Send(ctx context.Context, in *SendNotifyRequest, opts ...grpc.CallOption) (*SendNotifyResponse, error)
+}
+
+type notifyServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewNotifyServiceClient(cc grpc.ClientConnInterface) NotifyServiceClient {
+	return &notifyServiceClient{cc}
+}
+
+func (c *notifyServiceClient) Send(ctx context.Context, in *SendNotifyRequest, opts ...grpc.CallOption) (*SendNotifyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SendNotifyResponse)
+	err := c.cc.Invoke(ctx, NotifyService_Send_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// NotifyServiceServer is the server API for NotifyService service.
+// All implementations must embed UnimplementedNotifyServiceServer
+// for forward compatibility.
+//
+// NotifyService delivers host notifications (robot webhooks, admin alerts,
+// user notices) to a chat or push channel such as Telegram, DingTalk, Feishu
+// or WeCom.
+type NotifyServiceServer interface {
+	Send(context.Context, *SendNotifyRequest) (*SendNotifyResponse, error)
+	mustEmbedUnimplementedNotifyServiceServer()
+}
+
+// UnimplementedNotifyServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedNotifyServiceServer struct{}
+
+func (UnimplementedNotifyServiceServer) Send(context.Context, *SendNotifyRequest) (*SendNotifyResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Send not implemented")
+}
+func (UnimplementedNotifyServiceServer) mustEmbedUnimplementedNotifyServiceServer() {}
+func (UnimplementedNotifyServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeNotifyServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to NotifyServiceServer will
+// result in compilation errors.
+type UnsafeNotifyServiceServer interface {
+	mustEmbedUnimplementedNotifyServiceServer()
+}
+
+func RegisterNotifyServiceServer(s grpc.ServiceRegistrar, srv NotifyServiceServer) {
+	// If the following call panics, it indicates UnimplementedNotifyServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&NotifyService_ServiceDesc, srv)
+}
+
+func _NotifyService_Send_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendNotifyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotifyServiceServer).Send(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotifyService_Send_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotifyServiceServer).Send(ctx, req.(*SendNotifyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// NotifyService_ServiceDesc is the grpc.ServiceDesc for NotifyService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var NotifyService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "plugin.v1.NotifyService",
+	HandlerType: (*NotifyServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Send",
+			Handler:    _NotifyService_Send_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "plugin/v1/notify.proto",
+}
@@ -19,6 +19,10 @@ New-Item -ItemType Directory -Force "plugins/automation/lightboat" | Out-Null
 New-Item -ItemType Directory -Force "plugins/automation/xiaohei_proxy" | Out-Null
 New-Item -ItemType Directory -Force "plugins/automation/mofang_openapi" | Out-Null
 New-Item -ItemType Directory -Force "plugins/automation/openidc_default" | Out-Null
+New-Item -ItemType Directory -Force "plugins/automation/simulator" | Out-Null
+New-Item -ItemType Directory -Force "plugins/notify/mock_notify" | Out-Null
+New-Item -ItemType Directory -Force "plugins/storage/s3_compat" | Out-Null
+New-Item -ItemType Directory -Force "plugins/captcha/mock_captcha" | Out-Null
 
 $targets = @(
   @{ goos = "windows"; goarch = "amd64"; ext = ".exe" },
@@ -40,7 +44,11 @@ $plugins = @(
   @{ id = "automation/lightboat"; pkg = "./plugin-demo/pluginv1/automation_lightboat" },
   @{ id = "automation/xiaohei_proxy"; pkg = "./plugin-demo/pluginv1/automation_xiaohei_proxy" },
   @{ id = "automation/mofang_openapi"; pkg = "./plugin-demo/pluginv1/automation_mofang_openapi" },
-  @{ id = "automation/openidc_default"; pkg = "./plugin-demo/pluginv1/automation_openidc" }
+  @{ id = "automation/openidc_default"; pkg = "./plugin-demo/pluginv1/automation_openidc" },
+  @{ id = "automation/simulator"; pkg = "./plugin-demo/pluginv1/automation_simulator" },
+  @{ id = "notify/mock_notify"; pkg = "./plugin-demo/pluginv1/notify_mock" },
+  @{ id = "storage/s3_compat"; pkg = "./plugin-demo/pluginv1/storage_s3" },
+  @{ id = "captcha/mock_captcha"; pkg = "./plugin-demo/pluginv1/captcha_mock" }
 )
 
 $origGOOS = $env:GOOS
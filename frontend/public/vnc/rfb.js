@@ -0,0 +1,510 @@
+// Minimal RFB (VNC) client for the console relay. It speaks RFB 3.3-3.8 with
+// no or VNC password authentication and the Raw, CopyRect and DesktopSize
+// encodings, which every VNC server supports. Loaded by console.html; no
+// build step or dependencies.
+(function (global) {
+  "use strict";
+
+  var ENC_RAW = 0;
+  var ENC_COPYRECT = 1;
+  var ENC_DESKTOPSIZE = -223;
+
+  var SPECIAL_KEYS = {
+    Backspace: 0xff08, Tab: 0xff09, Enter: 0xff0d, Escape: 0xff1b,
+    Insert: 0xff63, Delete: 0xffff, Home: 0xff50, End: 0xff57,
+    PageUp: 0xff55, PageDown: 0xff56, ArrowLeft: 0xff51, ArrowUp: 0xff52,
+    ArrowRight: 0xff53, ArrowDown: 0xff54, CapsLock: 0xffe5, NumLock: 0xff7f,
+    ScrollLock: 0xff14, Pause: 0xff13, PrintScreen: 0xff61, ContextMenu: 0xff67
+  };
+  var MODIFIER_CODES = {
+    ShiftLeft: 0xffe1, ShiftRight: 0xffe2, ControlLeft: 0xffe3, ControlRight: 0xffe4,
+    AltLeft: 0xffe9, AltRight: 0xffea, MetaLeft: 0xffeb, MetaRight: 0xffec
+  };
+
+  function keysymFor(event) {
+    if (MODIFIER_CODES[event.code]) return MODIFIER_CODES[event.code];
+    if (SPECIAL_KEYS[event.key]) return SPECIAL_KEYS[event.key];
+    var fn = /^F([1-9]|1[0-2])$/.exec(event.key);
+    if (fn) return 0xffbd + Number(fn[1]);
+    if (event.key && event.key.length === 1) {
+      var cp = event.key.codePointAt(0);
+      return cp < 0x100 ? cp : 0x01000000 + cp;
+    }
+    return 0;
+  }
+
+  // VNC authentication encrypts the challenge with DES using the password,
+  // bit-reversed per byte, as the key.
+  var DES = (function () {
+    var PC1 = [57, 49, 41, 33, 25, 17, 9, 1, 58, 50, 42, 34, 26, 18, 10, 2, 59, 51, 43, 35, 27, 19, 11, 3, 60, 52, 44, 36,
+      63, 55, 47, 39, 31, 23, 15, 7, 62, 54, 46, 38, 30, 22, 14, 6, 61, 53, 45, 37, 29, 21, 13, 5, 28, 20, 12, 4];
+    var PC2 = [14, 17, 11, 24, 1, 5, 3, 28, 15, 6, 21, 10, 23, 19, 12, 4, 26, 8, 16, 7, 27, 20, 13, 2,
+      41, 52, 31, 37, 47, 55, 30, 40, 51, 45, 33, 48, 44, 49, 39, 56, 34, 53, 46, 42, 50, 36, 29, 32];
+    var SHIFTS = [1, 1, 2, 2, 2, 2, 2, 2, 1, 2, 2, 2, 2, 2, 2, 1];
+    var IP = [58, 50, 42, 34, 26, 18, 10, 2, 60, 52, 44, 36, 28, 20, 12, 4, 62, 54, 46, 38, 30, 22, 14, 6, 64, 56, 48, 40, 32, 24, 16, 8,
+      57, 49, 41, 33, 25, 17, 9, 1, 59, 51, 43, 35, 27, 19, 11, 3, 61, 53, 45, 37, 29, 21, 13, 5, 63, 55, 47, 39, 31, 23, 15, 7];
+    var FP = [40, 8, 48, 16, 56, 24, 64, 32, 39, 7, 47, 15, 55, 23, 63, 31, 38, 6, 46, 14, 54, 22, 62, 30, 37, 5, 45, 13, 53, 21, 61, 29,
+      36, 4, 44, 12, 52, 20, 60, 28, 35, 3, 43, 11, 51, 19, 59, 27, 34, 2, 42, 10, 50, 18, 58, 26, 33, 1, 41, 9, 49, 17, 57, 25];
+    var E = [32, 1, 2, 3, 4, 5, 4, 5, 6, 7, 8, 9, 8, 9, 10, 11, 12, 13, 12, 13, 14, 15, 16, 17,
+      16, 17, 18, 19, 20, 21, 20, 21, 22, 23, 24, 25, 24, 25, 26, 27, 28, 29, 28, 29, 30, 31, 32, 1];
+    var P = [16, 7, 20, 21, 29, 12, 28, 17, 1, 15, 23, 26, 5, 18, 31, 10, 2, 8, 24, 14, 32, 27, 3, 9, 19, 13, 30, 6, 22, 11, 4, 25];
+    var S = [
+      [14, 4, 13, 1, 2, 15, 11, 8, 3, 10, 6, 12, 5, 9, 0, 7, 0, 15, 7, 4, 14, 2, 13, 1, 10, 6, 12, 11, 9, 5, 3, 8,
+        4, 1, 14, 8, 13, 6, 2, 11, 15, 12, 9, 7, 3, 10, 5, 0, 15, 12, 8, 2, 4, 9, 1, 7, 5, 11, 3, 14, 10, 0, 6, 13],
+      [15, 1, 8, 14, 6, 11, 3, 4, 9, 7, 2, 13, 12, 0, 5, 10, 3, 13, 4, 7, 15, 2, 8, 14, 12, 0, 1, 10, 6, 9, 11, 5,
+        0, 14, 7, 11, 10, 4, 13, 1, 5, 8, 12, 6, 9, 3, 2, 15, 13, 8, 10, 1, 3, 15, 4, 2, 11, 6, 7, 12, 0, 5, 14, 9],
+      [10, 0, 9, 14, 6, 3, 15, 5, 1, 13, 12, 7, 11, 4, 2, 8, 13, 7, 0, 9, 3, 4, 6, 10, 2, 8, 5, 14, 12, 11, 15, 1,
+        13, 6, 4, 9, 8, 15, 3, 0, 11, 1, 2, 12, 5, 10, 14, 7, 1, 10, 13, 0, 6, 9, 8, 7, 4, 15, 14, 3, 11, 5, 2, 12],
+      [7, 13, 14, 3, 0, 6, 9, 10, 1, 2, 8, 5, 11, 12, 4, 15, 13, 8, 11, 5, 6, 15, 0, 3, 4, 7, 2, 12, 1, 10, 14, 9,
+        10, 6, 9, 0, 12, 11, 7, 13, 15, 1, 3, 14, 5, 2, 8, 4, 3, 15, 0, 6, 10, 1, 13, 8, 9, 4, 5, 11, 12, 7, 2, 14],
+      [2, 12, 4, 1, 7, 10, 11, 6, 8, 5, 3, 15, 13, 0, 14, 9, 14, 11, 2, 12, 4, 7, 13, 1, 5, 0, 15, 10, 3, 9, 8, 6,
+        4, 2, 1, 11, 10, 13, 7, 8, 15, 9, 12, 5, 6, 3, 0, 14, 11, 8, 12, 7, 1, 14, 2, 13, 6, 15, 0, 9, 10, 4, 5, 3],
+      [12, 1, 10, 15, 9, 2, 6, 8, 0, 13, 3, 4, 14, 7, 5, 11, 10, 15, 4, 2, 7, 12, 9, 5, 6, 1, 13, 14, 0, 11, 3, 8,
+        9, 14, 15, 5, 2, 8, 12, 3, 7, 0, 4, 10, 1, 13, 11, 6, 4, 3, 2, 12, 9, 5, 15, 10, 11, 14, 1, 7, 6, 0, 8, 13],
+      [4, 11, 2, 14, 15, 0, 8, 13, 3, 12, 9, 7, 5, 10, 6, 1, 13, 0, 11, 7, 4, 9, 1, 10, 14, 3, 5, 12, 2, 15, 8, 6,
+        1, 4, 11, 13, 12, 3, 7, 14, 10, 15, 6, 8, 0, 5, 9, 2, 6, 11, 13, 8, 1, 4, 10, 7, 9, 5, 0, 15, 14, 2, 3, 12],
+      [13, 2, 8, 4, 6, 15, 11, 1, 10, 9, 3, 14, 5, 0, 12, 7, 1, 15, 13, 8, 10, 3, 7, 4, 12, 5, 6, 11, 0, 14, 9, 2,
+        7, 11, 4, 1, 9, 12, 14, 2, 0, 6, 10, 13, 15, 3, 5, 8, 2, 1, 14, 7, 4, 10, 8, 13, 15, 12, 9, 0, 3, 5, 6, 11]
+    ];
+
+    function bits(bytes) {
+      var out = [];
+      for (var i = 0; i < bytes.length * 8; i++) out.push((bytes[i >> 3] >> (7 - (i & 7))) & 1);
+      return out;
+    }
+    function permute(input, table) {
+      return table.map(function (pos) { return input[pos - 1]; });
+    }
+    function rotate(half, n) {
+      return half.slice(n).concat(half.slice(0, n));
+    }
+
+    function encryptBlock(key, block) {
+      var cd = permute(bits(key), PC1);
+      var c = cd.slice(0, 28);
+      var d = cd.slice(28);
+      var keys = [];
+      for (var r = 0; r < 16; r++) {
+        c = rotate(c, SHIFTS[r]);
+        d = rotate(d, SHIFTS[r]);
+        keys.push(permute(c.concat(d), PC2));
+      }
+      var lr = permute(bits(block), IP);
+      var l = lr.slice(0, 32);
+      var rr = lr.slice(32);
+      for (var round = 0; round < 16; round++) {
+        var x = permute(rr, E).map(function (b, i) { return b ^ keys[round][i]; });
+        var f = [];
+        for (var s = 0; s < 8; s++) {
+          var chunk = x.slice(s * 6, s * 6 + 6);
+          var row = (chunk[0] << 1) | chunk[5];
+          var col = (chunk[1] << 3) | (chunk[2] << 2) | (chunk[3] << 1) | chunk[4];
+          var v = S[s][row * 16 + col];
+          f.push((v >> 3) & 1, (v >> 2) & 1, (v >> 1) & 1, v & 1);
+        }
+        f = permute(f, P);
+        var next = l.map(function (b, i) { return b ^ f[i]; });
+        l = rr;
+        rr = next;
+      }
+      var out = permute(rr.concat(l), FP);
+      var bytes = new Uint8Array(8);
+      for (var i = 0; i < 64; i++) bytes[i >> 3] |= out[i] << (7 - (i & 7));
+      return bytes;
+    }
+
+    return { encryptBlock: encryptBlock };
+  })();
+
+  function vncAuthResponse(password, challenge) {
+    var key = new Uint8Array(8);
+    for (var i = 0; i < 8 && i < password.length; i++) {
+      var c = password.charCodeAt(i) & 0xff;
+      var rev = 0;
+      for (var b = 0; b < 8; b++) rev |= ((c >> b) & 1) << (7 - b);
+      key[i] = rev;
+    }
+    var out = new Uint8Array(16);
+    out.set(DES.encryptBlock(key, challenge.subarray(0, 8)), 0);
+    out.set(DES.encryptBlock(key, challenge.subarray(8, 16)), 8);
+    return out;
+  }
+
+  // RFB drives one connection. Callbacks: onstatus(text), onpassword() which
+  // returns a Promise of the password, onconnect(name), ondisconnect(reason).
+  function RFB(canvas, url, callbacks) {
+    this.canvas = canvas;
+    this.ctx = canvas.getContext("2d");
+    this.cb = callbacks || {};
+    this.buf = new Uint8Array(0);
+    this.state = "version";
+    this.version = 8;
+    this.buttons = 0;
+    this.pressed = {};
+    this.closed = false;
+    this.ws = new WebSocket(url, ["binary"]);
+    this.ws.binaryType = "arraybuffer";
+    var self = this;
+    this.ws.onopen = function () { self.status("正在握手…"); };
+    this.ws.onmessage = function (e) { self.receive(new Uint8Array(e.data)); };
+    this.ws.onclose = function (e) { self.finish(e.reason || "连接已关闭"); };
+    this.ws.onerror = function () { self.finish("连接失败"); };
+  }
+
+  RFB.prototype.status = function (text) {
+    if (this.cb.onstatus) this.cb.onstatus(text);
+  };
+
+  RFB.prototype.finish = function (reason) {
+    if (this.closed) return;
+    this.closed = true;
+    this.detachInput();
+    if (this.ws.readyState <= 1) this.ws.close();
+    if (this.cb.ondisconnect) this.cb.ondisconnect(reason);
+  };
+
+  RFB.prototype.fail = function (reason) {
+    this.state = "failed";
+    this.finish(reason);
+  };
+
+  RFB.prototype.send = function (bytes) {
+    if (this.ws.readyState === 1) this.ws.send(bytes);
+  };
+
+  RFB.prototype.receive = function (chunk) {
+    var merged = new Uint8Array(this.buf.length + chunk.length);
+    merged.set(this.buf, 0);
+    merged.set(chunk, this.buf.length);
+    this.buf = merged;
+    while (!this.closed && this.step()) { /* keep parsing */ }
+  };
+
+  RFB.prototype.has = function (n) {
+    return this.buf.length >= n;
+  };
+
+  RFB.prototype.take = function (n) {
+    var out = this.buf.subarray(0, n);
+    this.buf = this.buf.subarray(n);
+    return out;
+  };
+
+  function u16(b, o) { return (b[o] << 8) | b[o + 1]; }
+  function u32(b, o) { return ((b[o] << 24) >>> 0) + ((b[o + 1] << 16) | (b[o + 2] << 8) | b[o + 3]); }
+  function s32(b, o) { return (b[o] << 24) | (b[o + 1] << 16) | (b[o + 2] << 8) | b[o + 3]; }
+  function text(b) { return new TextDecoder().decode(b); }
+
+  // step handles one protocol unit and reports whether it consumed input.
+  RFB.prototype.step = function () {
+    switch (this.state) {
+      case "version": return this.readVersion();
+      case "security": return this.readSecurity();
+      case "challenge": return this.readChallenge();
+      case "result": return this.readSecurityResult();
+      case "reason": return this.readReason();
+      case "init": return this.readServerInit();
+      case "normal": return this.readMessage();
+      case "rect": return this.readRect();
+      default: return false;
+    }
+  };
+
+  RFB.prototype.readVersion = function () {
+    if (!this.has(12)) return false;
+    var banner = text(this.take(12));
+    var m = /^RFB (\d{3})\.(\d{3})\n$/.exec(banner);
+    if (!m) { this.fail("不支持的协议"); return false; }
+    var minor = Number(m[2]);
+    this.version = Number(m[1]) > 3 || minor >= 8 ? 8 : minor >= 7 ? 7 : 3;
+    this.send(new TextEncoder().encode("RFB 003.00" + this.version + "\n"));
+    this.state = "security";
+    return true;
+  };
+
+  RFB.prototype.readSecurity = function () {
+    var type;
+    if (this.version === 3) {
+      if (!this.has(4)) return false;
+      type = u32(this.take(4), 0);
+      if (type === 0) { this.state = "reason"; return true; }
+    } else {
+      if (!this.has(1)) return false;
+      var n = this.buf[0];
+      if (n === 0) { this.take(1); this.state = "reason"; return true; }
+      if (!this.has(1 + n)) return false;
+      var types = Array.prototype.slice.call(this.take(1 + n).subarray(1));
+      type = types.indexOf(1) >= 0 ? 1 : types.indexOf(2) >= 0 ? 2 : 0;
+      if (!type) { this.fail("不支持的认证方式"); return false; }
+      this.send(new Uint8Array([type]));
+    }
+    if (type === 2) {
+      this.state = "challenge";
+    } else if (type === 1) {
+      if (this.version === 8) {
+        this.state = "result";
+      } else {
+        this.clientInit();
+      }
+    } else {
+      this.fail("不支持的认证方式");
+      return false;
+    }
+    return true;
+  };
+
+  RFB.prototype.readChallenge = function () {
+    if (!this.has(16)) return false;
+    var challenge = this.take(16).slice();
+    var self = this;
+    this.state = "password";
+    this.status("需要 VNC 密码");
+    var ask = this.cb.onpassword ? this.cb.onpassword() : Promise.resolve("");
+    Promise.resolve(ask).then(function (password) {
+      if (self.closed) return;
+      self.send(vncAuthResponse(password || "", challenge));
+      self.state = "result";
+      while (!self.closed && self.step()) { /* resume */ }
+    });
+    return false;
+  };
+
+  RFB.prototype.readSecurityResult = function () {
+    if (!this.has(4)) return false;
+    if (u32(this.take(4), 0) === 0) {
+      this.clientInit();
+      return true;
+    }
+    if (this.version === 8) {
+      this.state = "reason";
+      return true;
+    }
+    this.fail("认证失败");
+    return false;
+  };
+
+  RFB.prototype.readReason = function () {
+    if (!this.has(4)) return false;
+    var len = u32(this.buf, 0);
+    if (!this.has(4 + len)) return false;
+    var reason = text(this.take(4 + len).subarray(4));
+    this.fail(reason || "认证失败");
+    return false;
+  };
+
+  RFB.prototype.clientInit = function () {
+    this.send(new Uint8Array([1]));
+    this.state = "init";
+  };
+
+  RFB.prototype.readServerInit = function () {
+    if (!this.has(24)) return false;
+    var len = u32(this.buf, 20);
+    if (!this.has(24 + len)) return false;
+    var head = this.take(24 + len);
+    this.name = text(head.subarray(24));
+    this.resize(u16(head, 0), u16(head, 2));
+    // 32 bpp, depth 24, little endian true colour with red in the low byte,
+    // so pixels copy straight into canvas image data.
+    this.send(new Uint8Array([0, 0, 0, 0, 32, 24, 0, 1, 0, 255, 0, 255, 0, 255, 0, 8, 16, 0, 0, 0]));
+    var encodings = [ENC_COPYRECT, ENC_RAW, ENC_DESKTOPSIZE];
+    var msg = new DataView(new ArrayBuffer(4 + 4 * encodings.length));
+    msg.setUint8(0, 2);
+    msg.setUint16(2, encodings.length);
+    encodings.forEach(function (enc, i) { msg.setInt32(4 + 4 * i, enc); });
+    this.send(new Uint8Array(msg.buffer));
+    this.requestUpdate(false);
+    this.state = "normal";
+    this.attachInput();
+    if (this.cb.onconnect) this.cb.onconnect(this.name);
+    return true;
+  };
+
+  RFB.prototype.resize = function (width, height) {
+    this.width = width;
+    this.height = height;
+    this.canvas.width = width;
+    this.canvas.height = height;
+  };
+
+  RFB.prototype.requestUpdate = function (incremental) {
+    var msg = new DataView(new ArrayBuffer(10));
+    msg.setUint8(0, 3);
+    msg.setUint8(1, incremental ? 1 : 0);
+    msg.setUint16(6, this.width);
+    msg.setUint16(8, this.height);
+    this.send(new Uint8Array(msg.buffer));
+  };
+
+  RFB.prototype.readMessage = function () {
+    if (!this.has(1)) return false;
+    switch (this.buf[0]) {
+      case 0:
+        if (!this.has(4)) return false;
+        this.rects = u16(this.take(4), 2);
+        this.state = "rect";
+        if (this.rects === 0) this.updateDone();
+        return true;
+      case 1:
+        if (!this.has(6)) return false;
+        var colours = u16(this.buf, 4);
+        if (!this.has(6 + colours * 6)) return false;
+        this.take(6 + colours * 6);
+        return true;
+      case 2:
+        this.take(1);
+        return true;
+      case 3:
+        if (!this.has(8)) return false;
+        var len = u32(this.buf, 4);
+        if (!this.has(8 + len)) return false;
+        this.take(8 + len);
+        return true;
+      default:
+        this.fail("未知的服务器消息 " + this.buf[0]);
+        return false;
+    }
+  };
+
+  RFB.prototype.readRect = function () {
+    if (!this.has(12)) return false;
+    var x = u16(this.buf, 0);
+    var y = u16(this.buf, 2);
+    var w = u16(this.buf, 4);
+    var h = u16(this.buf, 6);
+    var enc = s32(this.buf, 8);
+    if (enc === ENC_RAW) {
+      var size = w * h * 4;
+      if (!this.has(12 + size)) return false;
+      var pixels = this.take(12 + size).subarray(12);
+      if (w > 0 && h > 0) {
+        var img = this.ctx.createImageData(w, h);
+        img.data.set(pixels);
+        for (var i = 3; i < img.data.length; i += 4) img.data[i] = 255;
+        this.ctx.putImageData(img, x, y);
+      }
+    } else if (enc === ENC_COPYRECT) {
+      if (!this.has(16)) return false;
+      var src = this.take(16);
+      if (w > 0 && h > 0) this.ctx.drawImage(this.canvas, u16(src, 12), u16(src, 14), w, h, x, y, w, h);
+    } else if (enc === ENC_DESKTOPSIZE) {
+      this.take(12);
+      this.resize(w, h);
+    } else {
+      this.fail("不支持的编码 " + enc);
+      return false;
+    }
+    this.rects--;
+    if (this.rects === 0) this.updateDone();
+    return true;
+  };
+
+  RFB.prototype.updateDone = function () {
+    this.state = "normal";
+    this.requestUpdate(true);
+  };
+
+  RFB.prototype.sendKey = function (keysym, down) {
+    var msg = new DataView(new ArrayBuffer(8));
+    msg.setUint8(0, 4);
+    msg.setUint8(1, down ? 1 : 0);
+    msg.setUint32(4, keysym);
+    this.send(new Uint8Array(msg.buffer));
+  };
+
+  RFB.prototype.sendPointer = function (x, y, mask) {
+    var msg = new DataView(new ArrayBuffer(6));
+    msg.setUint8(0, 5);
+    msg.setUint8(1, mask);
+    msg.setUint16(2, x);
+    msg.setUint16(4, y);
+    this.send(new Uint8Array(msg.buffer));
+  };
+
+  RFB.prototype.sendCtrlAltDel = function () {
+    if (this.state !== "normal" && this.state !== "rect") return;
+    var keys = [0xffe3, 0xffe9, 0xffff];
+    var self = this;
+    keys.forEach(function (k) { self.sendKey(k, true); });
+    keys.slice().reverse().forEach(function (k) { self.sendKey(k, false); });
+  };
+
+  RFB.prototype.position = function (event) {
+    var rect = this.canvas.getBoundingClientRect();
+    var x = Math.round((event.clientX - rect.left) * this.width / rect.width);
+    var y = Math.round((event.clientY - rect.top) * this.height / rect.height);
+    return [Math.max(0, Math.min(this.width - 1, x)), Math.max(0, Math.min(this.height - 1, y))];
+  };
+
+  RFB.prototype.attachInput = function () {
+    var self = this;
+    var bit = function (button) { return button === 0 ? 1 : button === 1 ? 2 : button === 2 ? 4 : 0; };
+    this.handlers = {
+      mousedown: function (e) {
+        e.preventDefault();
+        self.canvas.focus();
+        self.buttons |= bit(e.button);
+        var p = self.position(e);
+        self.sendPointer(p[0], p[1], self.buttons);
+      },
+      mouseup: function (e) {
+        e.preventDefault();
+        self.buttons &= ~bit(e.button);
+        var p = self.position(e);
+        self.sendPointer(p[0], p[1], self.buttons);
+      },
+      mousemove: function (e) {
+        var p = self.position(e);
+        self.sendPointer(p[0], p[1], self.buttons);
+      },
+      wheel: function (e) {
+        e.preventDefault();
+        var p = self.position(e);
+        var b = e.deltaY < 0 ? 8 : 16;
+        self.sendPointer(p[0], p[1], self.buttons | b);
+        self.sendPointer(p[0], p[1], self.buttons);
+      },
+      contextmenu: function (e) { e.preventDefault(); },
+      keydown: function (e) {
+        var keysym = keysymFor(e);
+        if (!keysym) return;
+        e.preventDefault();
+        self.pressed[e.code || e.key] = keysym;
+        self.sendKey(keysym, true);
+      },
+      keyup: function (e) {
+        var keysym = self.pressed[e.code || e.key] || keysymFor(e);
+        if (!keysym) return;
+        e.preventDefault();
+        delete self.pressed[e.code || e.key];
+        self.sendKey(keysym, false);
+      },
+      blur: function () {
+        Object.keys(self.pressed).forEach(function (code) { self.sendKey(self.pressed[code], false); });
+        self.pressed = {};
+      }
+    };
+    ["mousedown", "mouseup", "mousemove", "wheel", "contextmenu", "keydown", "keyup", "blur"].forEach(function (name) {
+      self.canvas.addEventListener(name, self.handlers[name], { passive: false });
+    });
+    this.canvas.tabIndex = 0;
+    this.canvas.focus();
+  };
+
+  RFB.prototype.detachInput = function () {
+    if (!this.handlers) return;
+    var self = this;
+    Object.keys(this.handlers).forEach(function (name) {
+      self.canvas.removeEventListener(name, self.handlers[name]);
+    });
+    this.handlers = null;
+  };
+
+  RFB.prototype.disconnect = function () {
+    this.finish("已断开");
+  };
+
+  RFB.vncAuthResponse = vncAuthResponse;
+  RFB.DES = DES;
+  global.RFB = RFB;
+})(typeof window !== "undefined" ? window : globalThis);
@@ -65,11 +65,29 @@ export interface PluginDiscoverItem {
   };
 }
 
+export type CaptchaScene = "register" | "login" | "password_reset" | "order_submit";
+
 export interface CaptchaResponse {
-  captcha_provider?: "image" | "geetest";
+  captcha_provider?: "image" | "geetest" | "plugin";
   captcha_id?: string;
   api_server?: string;
   image_base64?: string;
+  scene?: CaptchaScene;
+  widget?: string;
+  params?: Record<string, string>;
+}
+
+// CaptchaAnswer carries the fields the backend verifies for a captcha scene;
+// which ones are set depends on the scene's provider.
+export interface CaptchaAnswer {
+  captcha_id?: string;
+  captcha_code?: string;
+  lot_number?: string;
+  captcha_output?: string;
+  pass_token?: string;
+  gen_time?: string;
+  captcha_token?: string;
+  captcha_params?: Record<string, string>;
 }
 
 export interface RegisterRequest {
@@ -84,6 +102,8 @@ export interface RegisterRequest {
   captcha_output?: string;
   pass_token?: string;
   gen_time?: string;
+  captcha_token?: string;
+  captcha_params?: Record<string, string>;
   verify_code?: string;
   verify_channel?: "email" | "sms";
 }
@@ -97,6 +117,8 @@ export interface LoginRequest {
   captcha_output?: string;
   pass_token?: string;
   gen_time?: string;
+  captcha_token?: string;
+  captcha_params?: Record<string, string>;
 }
 
 export interface AuthSettings {
@@ -113,7 +135,10 @@ export interface AuthSettings {
   register_verify_ttl_sec?: number;
   register_captcha_enabled?: boolean;
   login_captcha_enabled?: boolean;
-  captcha_provider?: "image" | "geetest";
+  captcha_provider?: "image" | "geetest" | "plugin";
+  captcha_scenes?: Partial<Record<CaptchaScene, "image" | "geetest" | "plugin">>;
+  password_reset_captcha_enabled?: boolean;
+  order_submit_captcha_enabled?: boolean;
   auth_geetest_captcha_id?: string;
   auth_geetest_api_server?: string;
   auth_login_notify_enabled?: boolean;
@@ -367,7 +392,7 @@ export interface OrderDetailResponse {
   payments?: OrderPayment[];
 }
 
-export interface OrderCreateRequest {
+export interface OrderCreateRequest extends CaptchaAnswer {
   coupon_code?: string;
   items?: Array<{
     package_id?: number;
@@ -802,6 +827,8 @@ export interface RobotConfig {
 export interface RobotWebhook {
   name?: string;
   url?: string;
+  plugin_id?: string;
+  instance_id?: string;
   secret?: string;
   enabled?: boolean;
   events?: string[];
@@ -1131,6 +1158,8 @@ export interface UploadItem {
   name?: string;
   path?: string;
   url?: string;
+  backend?: string;
+  object_key?: string;
   mime?: string;
   size?: number;
   uploader_id?: number;
@@ -3,7 +3,9 @@ import type {
   ApiList,
   AuthResponse,
   AuthSettings,
+  CaptchaAnswer,
   CaptchaResponse,
+  CaptchaScene,
   CartItem,
   CartItemRequest,
   Order,
@@ -39,7 +41,8 @@ import type {
   UserAPIKey
 } from "./types";
 
-export const getCaptcha = () => http.get<CaptchaResponse>("/api/v1/captcha");
+export const getCaptcha = (scene?: CaptchaScene) =>
+  http.get<CaptchaResponse>("/api/v1/captcha", { params: scene ? { scene } : undefined });
 export const getAuthSettings = () => http.get<AuthSettings>("/api/v1/auth/settings");
 export const requestRegisterCode = (payload: {
   channel?: "email" | "sms";
@@ -51,6 +54,8 @@ export const requestRegisterCode = (payload: {
   captcha_output?: string;
   pass_token?: string;
   gen_time?: string;
+  captcha_token?: string;
+  captcha_params?: Record<string, string>;
 }) =>
   http.post("/api/v1/auth/register/code", payload);
 export const getInstallStatus = () => http.get<{ installed: boolean }>("/api/v1/install/status");
@@ -85,7 +90,7 @@ export const createOrderFromCart = (idempotencyKey?: string) =>
   http.post<OrderCreateResponse>("/api/v1/orders", null, {
     headers: idempotencyKey ? { "Idempotency-Key": idempotencyKey } : {}
   });
-export const createOrderFromCartWithCoupon = (payload?: { coupon_code?: string }, idempotencyKey?: string) =>
+export const createOrderFromCartWithCoupon = (payload?: { coupon_code?: string } & CaptchaAnswer, idempotencyKey?: string) =>
   http.post<OrderCreateResponse>("/api/v1/orders", payload || null, {
     headers: idempotencyKey ? { "Idempotency-Key": idempotencyKey } : {}
   });
@@ -197,7 +202,9 @@ export const forgotPassword = (email: string) => http.post("/api/v1/auth/forgot-
 export const resetPassword = (token: string, new_password: string) =>
   http.post("/api/v1/auth/reset-password", { token, new_password });
 export const getPasswordResetOptions = (account: string) => http.post("/api/v1/auth/password-reset/options", { account });
-export const sendPasswordResetCode = (payload: { account: string; channel: "email" | "sms"; phone_full?: string }) =>
+export const sendPasswordResetCode = (
+  payload: { account: string; channel: "email" | "sms"; phone_full?: string } & CaptchaAnswer
+) =>
   http.post("/api/v1/auth/password-reset/send-code", payload);
 export const verifyPasswordResetCode = (payload: { account: string; channel: "email" | "sms"; code: string }) =>
   http.post<{ reset_ticket?: string; expires_in?: number }>("/api/v1/auth/password-reset/verify-code", payload);
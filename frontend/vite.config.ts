@@ -33,7 +33,8 @@ export default defineConfig({
     proxy: {
       "/api": {
         target: "http://localhost:8080",
-        changeOrigin: true
+        changeOrigin: true,
+        ws: true
       },
       "/admin/api": {
         target: "http://localhost:8080",